@@ -21,24 +21,41 @@ import (
 	"github.com/radamesvaz/bakery-app/internal/handlers/auth"
 	"github.com/radamesvaz/bakery-app/internal/logger"
 	"github.com/radamesvaz/bakery-app/internal/middleware"
+	analyticsRepository "github.com/radamesvaz/bakery-app/internal/repository/analytics"
 	authActionTokensRepo "github.com/radamesvaz/bakery-app/internal/repository/auth_action_tokens"
 	bootstrapRepository "github.com/radamesvaz/bakery-app/internal/repository/bootstrap"
+	capacityRepository "github.com/radamesvaz/bakery-app/internal/repository/capacity"
+	customersRepository "github.com/radamesvaz/bakery-app/internal/repository/customers"
+	notificationsRepository "github.com/radamesvaz/bakery-app/internal/repository/notifications"
+	orderProtectionRepository "github.com/radamesvaz/bakery-app/internal/repository/orderprotection"
 	ordersRepository "github.com/radamesvaz/bakery-app/internal/repository/orders"
+	paymentsRepository "github.com/radamesvaz/bakery-app/internal/repository/payments"
+	pickupRepository "github.com/radamesvaz/bakery-app/internal/repository/pickup"
+	pricingRepository "github.com/radamesvaz/bakery-app/internal/repository/pricing"
 	productsRepository "github.com/radamesvaz/bakery-app/internal/repository/products"
+	promotionsRepository "github.com/radamesvaz/bakery-app/internal/repository/promotions"
+	standingOrdersRepository "github.com/radamesvaz/bakery-app/internal/repository/standingorders"
 	tenantRepository "github.com/radamesvaz/bakery-app/internal/repository/tenant"
 	tenantSignupRepository "github.com/radamesvaz/bakery-app/internal/repository/tenantsignup"
 	"github.com/radamesvaz/bakery-app/internal/repository/user"
+	webhooksRepository "github.com/radamesvaz/bakery-app/internal/repository/webhooks"
+	accountClaimService "github.com/radamesvaz/bakery-app/internal/services/accountclaim"
+	analyticsService "github.com/radamesvaz/bakery-app/internal/services/analytics"
 	authService "github.com/radamesvaz/bakery-app/internal/services/auth"
 	authActionTokensService "github.com/radamesvaz/bakery-app/internal/services/auth_action_tokens"
 	bootstrapService "github.com/radamesvaz/bakery-app/internal/services/bootstrap"
 	emailService "github.com/radamesvaz/bakery-app/internal/services/email"
 	imagesService "github.com/radamesvaz/bakery-app/internal/services/images"
 	invitationService "github.com/radamesvaz/bakery-app/internal/services/invitations"
+	notificationsService "github.com/radamesvaz/bakery-app/internal/services/notifications"
 	orderService "github.com/radamesvaz/bakery-app/internal/services/orders"
 	passwordResetService "github.com/radamesvaz/bakery-app/internal/services/passwordreset"
+	paymentsService "github.com/radamesvaz/bakery-app/internal/services/payments"
+	standingOrdersService "github.com/radamesvaz/bakery-app/internal/services/standingorders"
 	subscriptionService "github.com/radamesvaz/bakery-app/internal/services/subscriptions"
 	tenantSignupService "github.com/radamesvaz/bakery-app/internal/services/tenantsignup"
 	tokensService "github.com/radamesvaz/bakery-app/internal/services/tokens"
+	webhooksService "github.com/radamesvaz/bakery-app/internal/services/webhooks"
 
 	"github.com/gorilla/mux"
 	_ "github.com/lib/pq"
@@ -171,6 +188,7 @@ func main() {
 	}
 
 	var db *sql.DB
+	var dbDSN string
 	var lastErr error
 	for idx, d := range candidateDSNs {
 		// Log a safe summary of the DSN (without password)
@@ -238,6 +256,7 @@ func main() {
 		}
 
 		if succeeded {
+			dbDSN = d
 			break
 		}
 
@@ -368,6 +387,15 @@ func main() {
 	passwordResetHandler := &auth.PasswordResetHandler{
 		Service: passwordResetSvc,
 	}
+	accountClaimHandler := &auth.AccountClaimHandler{
+		Service: &accountClaimService.AccountClaimService{
+			Users:        &userRepo,
+			AuthService:  authSvc,
+			TokenService: authActionTokensSvc,
+			EmailSender:  resolveEmailSender(),
+			AppBaseURL:   strings.TrimSpace(os.Getenv("APP_BASE_URL")),
+		},
+	}
 	invitationSvc := &invitationService.InvitationService{
 		Users:        &userRepo,
 		AuthService:  authSvc,
@@ -384,18 +412,103 @@ func main() {
 		ImageService: imageService,
 	}
 
+	// Webhook setup
+	// WEBHOOK_ALLOW_INSECURE_URLS lets local development deliver to http and internal hosts.
+	allowInsecureWebhookURLs := parseBoolWithDefault(os.Getenv("WEBHOOK_ALLOW_INSECURE_URLS"), false)
+	webhookRepo := &webhooksRepository.Repository{DB: db}
+	webhookHandler := &h.WebhookHandler{
+		Repo:              webhookRepo,
+		AllowInsecureURLs: allowInsecureWebhookURLs,
+	}
+
+	// Order email notifications setup
+	notificationRepo := &notificationsRepository.Repository{DB: db}
+	notificationSettingsHandler := &h.NotificationSettingsHandler{
+		Repo: notificationRepo,
+	}
+
+	// Delivery capacity setup
+	capacityRepo := &capacityRepository.Repository{DB: db}
+	deliveryCapacityHandler := &h.DeliveryCapacityHandler{
+		Repo: capacityRepo,
+	}
+
+	// Promotions setup
+	promotionRepo := &promotionsRepository.Repository{DB: db}
+	promotionHandler := &h.PromotionHandler{
+		Repo: promotionRepo,
+	}
+
+	// Pricing setup
+	pricingRepo := &pricingRepository.Repository{DB: db}
+	pricingHandler := &h.PricingHandler{
+		Repo: pricingRepo,
+	}
+
+	// Pickup locations setup
+	pickupRepo := &pickupRepository.Repository{DB: db}
+	pickupLocationHandler := &h.PickupLocationHandler{
+		Repo: pickupRepo,
+	}
+
+	// Order protection setup
+	orderProtectionRepo := &orderProtectionRepository.Repository{DB: db}
+	orderProtectionHandler := &h.OrderProtectionHandler{
+		Repo: orderProtectionRepo,
+	}
+
+	// Customer directory setup
+	customerHandler := &h.CustomerHandler{
+		Repo: &customersRepository.Repository{DB: db},
+	}
+
 	// Order setup
 	orderRepo := &ordersRepository.OrderRepository{DB: db}
 	orderHandler := &h.OrderHandler{
-		Repo:        orderRepo,
-		UserRepo:    &userRepo,
-		ProductRepo: productRepo,
-		TenantRepo:  tenantRepo,
+		Repo:            orderRepo,
+		UserRepo:        &userRepo,
+		ProductRepo:     productRepo,
+		TenantRepo:      tenantRepo,
+		Events:          webhookRepo,
+		Notifications:   notificationRepo,
+		Capacity:        capacityRepo,
+		TrackingTokens:  oneTimeTokenManager,
+		Promotions:      promotionRepo,
+		Pricing:         pricingRepo,
+		PickupLocations: pickupRepo,
+		Protection:      orderProtectionRepo,
 	}
+	orderStreamBroker := orderService.NewOrderStreamBroker()
+	orderHandler.Stream = orderService.NewOrderStreamer(orderRepo, orderStreamBroker)
+	orderHandler.Stream.PollInterval = time.Duration(parseIntWithDefault(os.Getenv("ORDER_STREAM_POLL_SECONDS"), 15)) * time.Second
+
+	// Standing orders setup
+	standingOrderRepo := &standingOrdersRepository.Repository{DB: db}
+	standingOrderHandler := &h.StandingOrderHandler{
+		Repo: standingOrderRepo,
+	}
+
+	reportHandler := &h.ReportHandler{
+		Repo: orderRepo,
+	}
+
+	analyticsHandler := &h.AnalyticsHandler{
+		Service: analyticsService.NewService(&analyticsRepository.Repository{DB: db}),
+	}
+
+	// Payment setup
+	paymentSvc := paymentsService.NewService(orderRepo, &paymentsRepository.Repository{DB: db}, resolvePaymentProvider(), oneTimeTokenManager)
+	paymentSvc.CheckoutTTL = time.Duration(parseIntWithDefault(os.Getenv("PAYMENT_CHECKOUT_TTL_MINUTES"), 30)) * time.Minute
+	paymentHandler := &h.PaymentHandler{
+		Service: paymentSvc,
+	}
+	orderHandler.OnlinePaymentsEnabled = paymentSvc.Provider != nil
 
 	// Ghost order worker: cancel expired pending orders on an interval
 	ghostOrderIntervalMin := parseIntWithDefault(os.Getenv("GHOST_ORDER_CRON_INTERVAL_MINUTES"), 5)
 	ghostCanceller := orderService.NewExpiredOrderCanceller(orderRepo, productRepo, tenantRepo)
+	ghostCanceller.Events = webhookRepo
+	ghostCanceller.Notifications = notificationRepo
 	subscriptionIntervalHours := parseIntWithDefault(os.Getenv("SUBSCRIPTION_CRON_INTERVAL_HOURS"), 24)
 	workerCtx, workerCancel := context.WithCancel(context.Background())
 	var workerWg sync.WaitGroup
@@ -409,6 +522,62 @@ func main() {
 		defer workerWg.Done()
 		subscriptionService.RunWorker(workerCtx, subscriptionSvc, subscriptionIntervalHours)
 	}()
+	// Standing order worker: create the orders of recurring templates a few days ahead, through
+	// the same Creator path as storefront orders
+	standingOrderCreator := orderService.NewCreator(orderRepo, &userRepo, productRepo, tenantRepo)
+	standingOrderCreator.Events = webhookRepo
+	standingOrderCreator.Notifications = notificationRepo
+	standingOrderCreator.Capacity = capacityRepo
+	standingOrderCreator.TrackingTokens = oneTimeTokenManager
+	standingOrderCreator.IdempotencyKeys = orderRepo
+	standingOrderCreator.Pricing = pricingRepo
+	standingOrderGenerator := &standingOrdersService.Generator{
+		Repo:        standingOrderRepo,
+		Tenants:     tenantRepo,
+		Orders:      standingOrderCreator,
+		DaysAhead:   parseIntWithDefault(os.Getenv("STANDING_ORDERS_DAYS_AHEAD"), standingOrdersService.DefaultDaysAhead),
+		MaxAttempts: parseIntWithDefault(os.Getenv("STANDING_ORDERS_MAX_ATTEMPTS"), standingOrdersService.DefaultMaxAttempts),
+	}
+	standingOrdersIntervalMin := parseIntWithDefault(os.Getenv("STANDING_ORDERS_INTERVAL_MINUTES"), 60)
+	workerWg.Add(1)
+	go func() {
+		defer workerWg.Done()
+		standingOrdersService.RunWorker(workerCtx, standingOrderGenerator, standingOrdersIntervalMin)
+	}()
+	// Webhook dispatcher: deliver outbox events to subscribers with retries
+	webhookDispatchIntervalSec := parseIntWithDefault(os.Getenv("WEBHOOK_DISPATCH_INTERVAL_SECONDS"), 30)
+	webhookDispatcher := webhooksService.NewDispatcher(webhookRepo, parseIntWithDefault(os.Getenv("WEBHOOK_MAX_ATTEMPTS"), webhooksService.DefaultMaxAttempts))
+	webhookDispatcher.Client = webhooksService.NewHTTPClient(allowInsecureWebhookURLs)
+	workerWg.Add(1)
+	go func() {
+		defer workerWg.Done()
+		webhooksService.RunDispatcherWorker(workerCtx, webhookDispatcher, webhookDispatchIntervalSec)
+	}()
+	// Order email worker: send queued order emails outside the order transactions
+	emailNotificationsIntervalSec := parseIntWithDefault(os.Getenv("EMAIL_NOTIFICATIONS_INTERVAL_SECONDS"), 30)
+	emailWorker := notificationsService.NewWorker(notificationRepo, resolveEmailSender(), parseIntWithDefault(os.Getenv("EMAIL_NOTIFICATIONS_MAX_ATTEMPTS"), notificationsService.DefaultMaxAttempts))
+	workerWg.Add(1)
+	go func() {
+		defer workerWg.Done()
+		notificationsService.RunWorker(workerCtx, emailWorker, emailNotificationsIntervalSec)
+	}()
+	// Order stream listener: wake the open order streams on the NOTIFYs of every API instance.
+	// Without it (e.g. behind a transaction pooler, which cannot LISTEN) streams fall back to
+	// polling every ORDER_STREAM_POLL_SECONDS. Closing the broker on shutdown ends the streams.
+	orderEventListener, err := ordersRepository.NewOrderEventListener(firstNonEmpty(os.Getenv("ORDER_EVENTS_DATABASE_URL"), dbDSN))
+	if err != nil {
+		logger.Warn().Err(err).Msg("Order events listener unavailable; order streams will poll")
+	}
+	workerWg.Add(1)
+	go func() {
+		defer workerWg.Done()
+		defer orderStreamBroker.Close()
+		if orderEventListener == nil {
+			<-workerCtx.Done()
+			return
+		}
+		orderEventListener.Run(workerCtx, orderStreamBroker.Publish, orderStreamBroker.PublishAll)
+	}()
 
 	r := mux.NewRouter()
 	rateLimiter := middleware.NewInMemoryRateLimiter()
@@ -425,6 +594,36 @@ func main() {
 		Window:      rateLimitWindow,
 		ScopeTenant: true,
 	})
+	accountClaimRateLimit := rateLimiter.Middleware(middleware.RateLimitOptions{
+		Name:        "account_claim",
+		MaxRequests: parseIntWithDefault(os.Getenv("RATE_LIMIT_ACCOUNT_CLAIM_MAX"), 5),
+		Window:      rateLimitWindow,
+		ScopeTenant: true,
+	})
+	accountClaimCompleteRateLimit := rateLimiter.Middleware(middleware.RateLimitOptions{
+		Name:        "account_claim_complete",
+		MaxRequests: parseIntWithDefault(os.Getenv("RATE_LIMIT_ACCOUNT_CLAIM_COMPLETE_MAX"), 10),
+		Window:      rateLimitWindow,
+		ScopeTenant: true,
+	})
+	orderCreateRateLimit := rateLimiter.Middleware(middleware.RateLimitOptions{
+		Name:        "order_create",
+		MaxRequests: parseIntWithDefault(os.Getenv("RATE_LIMIT_ORDER_CREATE_MAX"), 10),
+		Window:      rateLimitWindow,
+		ScopeTenant: true,
+	})
+	orderHandler.EmailRateLimit = rateLimiter.Keyed(middleware.RateLimitOptions{
+		Name:        "order_create_email",
+		MaxRequests: parseIntWithDefault(os.Getenv("RATE_LIMIT_ORDER_CREATE_EMAIL_MAX"), 5),
+		Window:      rateLimitWindow,
+		ScopeTenant: true,
+	})
+	orderHandler.PhoneRateLimit = rateLimiter.Keyed(middleware.RateLimitOptions{
+		Name:        "order_create_phone",
+		MaxRequests: parseIntWithDefault(os.Getenv("RATE_LIMIT_ORDER_CREATE_PHONE_MAX"), 5),
+		Window:      rateLimitWindow,
+		ScopeTenant: true,
+	})
 	inviteAcceptRateLimit := rateLimiter.Middleware(middleware.RateLimitOptions{
 		Name:        "invite_accept",
 		MaxRequests: parseIntWithDefault(os.Getenv("RATE_LIMIT_INVITE_ACCEPT_MAX"), 10),
@@ -486,12 +685,13 @@ func main() {
 	legacyPublic.Use(middleware.TenantFromPathOrHeader(tenantRepo))
 	legacyPublic.HandleFunc("/products", productHandler.GetAllProducts).Methods("GET")
 	legacyPublic.HandleFunc("/products/{id}", productHandler.GetProductByID).Methods("GET")
-	legacyPublic.HandleFunc("/orders", orderHandler.CreateOrder).Methods("POST")
+	legacyPublic.Handle("/orders", orderCreateRateLimit(http.HandlerFunc(orderHandler.CreateOrder))).Methods("POST")
 
 	r.HandleFunc("/setup/bootstrap/tenant", bootstrapHandler.BootstrapTenant).Methods("POST")
 	r.HandleFunc("/public/tenant-register", tenantSignupHandler.RegisterTenantWithCode).Methods("POST")
 	// Auth endpoints (legacy single-tenant)
 	r.HandleFunc("/login", authHandler.Login).Methods("POST")
+	r.HandleFunc("/payments/webhook", paymentHandler.PaymentWebhook).Methods("POST")
 	r.HandleFunc("/register", authHandler.Register).Methods("POST")
 
 	// Auth endpoints (multi-tenant, path-based)
@@ -502,6 +702,8 @@ func main() {
 	tAuth.Handle("/password/forgot", forgotRateLimit(http.HandlerFunc(passwordResetHandler.ForgotPassword))).Methods("POST")
 	tAuth.Handle("/password/reset", resetRateLimit(http.HandlerFunc(passwordResetHandler.ResetPassword))).Methods("POST")
 	tAuth.Handle("/invitations/accept", inviteAcceptRateLimit(http.HandlerFunc(invitationHandler.AcceptInvitation))).Methods("POST")
+	tAuth.Handle("/account/claim", accountClaimRateLimit(http.HandlerFunc(accountClaimHandler.RequestClaim))).Methods("POST")
+	tAuth.Handle("/account/claim/complete", accountClaimCompleteRateLimit(http.HandlerFunc(accountClaimHandler.CompleteClaim))).Methods("POST")
 
 	// Invitation admin under path tenant (slug from URL). Inherits TenantFromPathOrHeader from tAuth; JWT must match path tenant.
 	tAuthInv := tAuth.PathPrefix("/invitations").Subrouter()
@@ -512,8 +714,10 @@ func main() {
 	tAuthInv.Handle("/{id}/resend", inviteResendRateLimit(http.HandlerFunc(invitationHandler.ResendInvitation))).Methods("POST")
 
 	// Authenticated API: JWT + tenant context (path slug if present, else slug from DB via tenant_id claim).
+	// Customer sessions are rejected here; they only reach /t/{tenant_slug}/me.
 	auth := r.PathPrefix("/auth").Subrouter()
 	auth.Use(middleware.AuthMiddleware(authSvc))
+	auth.Use(middleware.RejectClientRole())
 	auth.Use(middleware.TenantMiddleware(tenantRepo))
 	auth.Use(middleware.RequireOperableSubscription(tenantRepo))
 	auth.HandleFunc("/ping", func(w http.ResponseWriter, r *http.Request) {
@@ -525,6 +729,7 @@ func main() {
 	authAdmin.Use(middleware.RequireAdminRole())
 	authAdmin.HandleFunc("/products", productHandler.GetAllProductsAdmin).Methods("GET")
 	authAdmin.HandleFunc("/products/{id}", productHandler.GetProductByIDAdmin).Methods("GET")
+	authAdmin.HandleFunc("/products/{id}/history", productHandler.GetProductHistory).Methods("GET")
 	authAdmin.HandleFunc("/products", productHandler.CreateProduct).Methods("POST")
 	authAdmin.HandleFunc("/products/{id}", productHandler.UpdateProduct).Methods("PUT")
 	authAdmin.HandleFunc("/products/{id}", productHandler.UpdateProductStatus).Methods("PATCH")
@@ -536,13 +741,94 @@ func main() {
 
 	// Order endpoints (authenticated: list, get, update)
 	auth.HandleFunc("/orders", orderHandler.GetAllOrders).Methods("GET")
+	auth.HandleFunc("/orders/stream", orderHandler.StreamOrders).Methods("GET")
 	auth.HandleFunc("/orders/{id}", orderHandler.GetOrderByID).Methods("GET")
 	auth.HandleFunc("/orders/{id}", orderHandler.UpdateOrder).Methods("PATCH")
+	auth.HandleFunc("/orders/{id}/transitions", orderHandler.GetOrderTransitions).Methods("GET")
+	authAdmin.HandleFunc("/orders", orderHandler.CreateStaffOrder).Methods("POST")
+	authAdmin.HandleFunc("/orders/export", orderHandler.ExportOrders).Methods("GET")
+	authAdmin.HandleFunc("/orders/{id}/items", orderHandler.UpdateOrderItems).Methods("PUT")
+	authAdmin.HandleFunc("/orders/{id}/history", orderHandler.GetOrderHistory).Methods("GET")
+	auth.HandleFunc("/orders/{id}/payments", orderHandler.GetOrderPayments).Methods("GET")
+	authAdmin.HandleFunc("/orders/{id}/payments", orderHandler.RecordOrderPayment).Methods("POST")
+	authAdmin.HandleFunc("/orders/{id}/refunds", orderHandler.RecordOrderRefund).Methods("POST")
+
+	// Webhook subscriptions (admin only)
+	authAdmin.HandleFunc("/webhooks", webhookHandler.ListSubscriptions).Methods("GET")
+	authAdmin.HandleFunc("/webhooks", webhookHandler.CreateSubscription).Methods("POST")
+	authAdmin.HandleFunc("/webhooks/{id}", webhookHandler.GetSubscription).Methods("GET")
+	authAdmin.HandleFunc("/webhooks/{id}", webhookHandler.UpdateSubscription).Methods("PATCH")
+	authAdmin.HandleFunc("/webhooks/{id}", webhookHandler.DeleteSubscription).Methods("DELETE")
+	authAdmin.HandleFunc("/webhooks/{id}/deliveries", webhookHandler.ListDeliveries).Methods("GET")
+
+	// Order email settings (admin only)
+	authAdmin.HandleFunc("/notifications/settings", notificationSettingsHandler.GetSettings).Methods("GET")
+	authAdmin.HandleFunc("/notifications/settings", notificationSettingsHandler.UpdateSettings).Methods("PUT")
+
+	// Reports (admin only)
+	authAdmin.HandleFunc("/reports/production", reportHandler.GetProductionReport).Methods("GET")
+	authAdmin.HandleFunc("/analytics/revenue", analyticsHandler.GetRevenue).Methods("GET")
+	authAdmin.HandleFunc("/analytics/top-products", analyticsHandler.GetTopProducts).Methods("GET")
+	authAdmin.HandleFunc("/analytics/cancellations", analyticsHandler.GetCancellations).Methods("GET")
+
+	// Delivery capacity: default daily limits, blackout dates and per-date limits (admin only)
+	authAdmin.HandleFunc("/delivery-capacity", deliveryCapacityHandler.GetSettings).Methods("GET")
+	authAdmin.HandleFunc("/delivery-capacity", deliveryCapacityHandler.UpdateSettings).Methods("PUT")
+	authAdmin.HandleFunc("/delivery-capacity/days", deliveryCapacityHandler.ListDayOverrides).Methods("GET")
+	authAdmin.HandleFunc("/delivery-capacity/days/{date}", deliveryCapacityHandler.UpsertDayOverride).Methods("PUT")
+	authAdmin.HandleFunc("/delivery-capacity/days/{date}", deliveryCapacityHandler.DeleteDayOverride).Methods("DELETE")
+
+	// Standing orders: recurring order templates and their generated occurrences (admin only)
+	authAdmin.HandleFunc("/standing-orders", standingOrderHandler.ListStandingOrders).Methods("GET")
+	authAdmin.HandleFunc("/standing-orders", standingOrderHandler.CreateStandingOrder).Methods("POST")
+	authAdmin.HandleFunc("/standing-orders/{id}", standingOrderHandler.GetStandingOrder).Methods("GET")
+	authAdmin.HandleFunc("/standing-orders/{id}", standingOrderHandler.UpdateStandingOrder).Methods("PUT")
+	authAdmin.HandleFunc("/standing-orders/{id}", standingOrderHandler.DeleteStandingOrder).Methods("DELETE")
+	authAdmin.HandleFunc("/standing-orders/{id}/occurrences", standingOrderHandler.ListOccurrences).Methods("GET")
+
+	// Promotions: discount codes redeemed with promotion_code on order creation (admin only)
+	authAdmin.HandleFunc("/promotions", promotionHandler.ListPromotions).Methods("GET")
+	authAdmin.HandleFunc("/promotions", promotionHandler.CreatePromotion).Methods("POST")
+	authAdmin.HandleFunc("/promotions/{id}", promotionHandler.GetPromotion).Methods("GET")
+	authAdmin.HandleFunc("/promotions/{id}", promotionHandler.UpdatePromotion).Methods("PUT")
+	authAdmin.HandleFunc("/promotions/{id}", promotionHandler.DeletePromotion).Methods("DELETE")
+
+	// Pricing: tax and delivery fees applied to order totals (admin only)
+	authAdmin.HandleFunc("/pricing", pricingHandler.GetSettings).Methods("GET")
+	authAdmin.HandleFunc("/pricing", pricingHandler.UpdateSettings).Methods("PUT")
+	authAdmin.HandleFunc("/delivery-zones", pricingHandler.ListDeliveryZones).Methods("GET")
+	authAdmin.HandleFunc("/delivery-zones", pricingHandler.CreateDeliveryZone).Methods("POST")
+	authAdmin.HandleFunc("/delivery-zones/{id}", pricingHandler.UpdateDeliveryZone).Methods("PUT")
+	authAdmin.HandleFunc("/delivery-zones/{id}", pricingHandler.DeleteDeliveryZone).Methods("DELETE")
+
+	// Pickup locations where customers collect pickup orders (admin only)
+	authAdmin.HandleFunc("/pickup-locations", pickupLocationHandler.ListPickupLocations).Methods("GET")
+	authAdmin.HandleFunc("/pickup-locations", pickupLocationHandler.CreatePickupLocation).Methods("POST")
+	authAdmin.HandleFunc("/pickup-locations/{id}", pickupLocationHandler.UpdatePickupLocation).Methods("PUT")
+	authAdmin.HandleFunc("/pickup-locations/{id}", pickupLocationHandler.DeletePickupLocation).Methods("DELETE")
+
+	// Order lifecycle: status transitions the tenant allows (admin only)
+	authAdmin.HandleFunc("/order-status-transitions", orderHandler.GetStatusTransitions).Methods("GET")
+	authAdmin.HandleFunc("/order-status-transitions", orderHandler.UpdateStatusTransitions).Methods("PUT")
+	authAdmin.HandleFunc("/order-status-transitions", orderHandler.ResetStatusTransitions).Methods("DELETE")
+
+	// Order protection: abuse limits and blocklist for storefront orders (admin only)
+	authAdmin.HandleFunc("/order-protection", orderProtectionHandler.GetSettings).Methods("GET")
+	authAdmin.HandleFunc("/order-protection", orderProtectionHandler.UpdateSettings).Methods("PUT")
+	authAdmin.HandleFunc("/order-blocklist", orderProtectionHandler.ListBlocklist).Methods("GET")
+	authAdmin.HandleFunc("/order-blocklist", orderProtectionHandler.CreateBlocklistEntry).Methods("POST")
+	authAdmin.HandleFunc("/order-blocklist/{id}", orderProtectionHandler.DeleteBlocklistEntry).Methods("DELETE")
+
+	// Customer directory: client accounts with order stats and duplicate merge (admin only)
+	authAdmin.HandleFunc("/customers", customerHandler.ListCustomers).Methods("GET")
+	authAdmin.HandleFunc("/customers/{id}", customerHandler.GetCustomer).Methods("GET")
+	authAdmin.HandleFunc("/customers/{id}/merge", customerHandler.MergeCustomers).Methods("POST")
 
 	// Tenant branding: reads are public (see tPublic); mutations require auth
 	auth.HandleFunc("/branding/logo", tenantHandler.UploadTenantLogo).Methods("PATCH")
 	auth.HandleFunc("/branding/colors", tenantHandler.UpdateBrandingColors).Methods("PATCH")
 	auth.HandleFunc("/branding/name", tenantHandler.UpdateTenantDisplayName).Methods("PATCH")
+	auth.HandleFunc("/branding/currency", tenantHandler.UpdateTenantCurrency).Methods("PATCH")
 	auth.HandleFunc("/subscription", subscriptionHandler.GetSubscription).Methods("GET")
 
 	authInv := auth.PathPrefix("/invitations").Subrouter()
@@ -564,7 +850,20 @@ func main() {
 	tPublic.HandleFunc("/products", productHandler.GetAllProducts).Methods("GET")
 	tPublic.HandleFunc("/products/{id}", productHandler.GetProductByID).Methods("GET")
 	tPublic.HandleFunc("/branding", tenantHandler.GetBranding).Methods("GET")
-	tPublic.HandleFunc("/orders", orderHandler.CreateOrder).Methods("POST")
+	tPublic.HandleFunc("/availability", deliveryCapacityHandler.GetAvailability).Methods("GET")
+	tPublic.HandleFunc("/delivery-zones", pricingHandler.ListActiveDeliveryZones).Methods("GET")
+	tPublic.HandleFunc("/pickup-locations", pickupLocationHandler.ListActivePickupLocations).Methods("GET")
+	tPublic.Handle("/orders", orderCreateRateLimit(http.HandlerFunc(orderHandler.CreateOrder))).Methods("POST")
+	tPublic.HandleFunc("/orders/track/{token}", orderHandler.TrackOrder).Methods("GET")
+	tPublic.HandleFunc("/orders/track/{token}/cancel", orderHandler.CancelTrackedOrder).Methods("POST")
+	tPublic.HandleFunc("/orders/track/{token}/checkout", paymentHandler.CreateCheckout).Methods("POST")
+
+	// Customer accounts: a client JWT of the tenant sees only its own orders
+	tMe := tPublic.PathPrefix("/me").Subrouter()
+	tMe.Use(middleware.AuthMiddleware(authSvc))
+	tMe.Use(middleware.RequireJWTTenantMatchesContext())
+	tMe.HandleFunc("/orders", orderHandler.ListMyOrders).Methods("GET")
+	tMe.HandleFunc("/orders/{id}", orderHandler.GetMyOrder).Methods("GET")
 
 	// Wrap router with CORS
 	corsWrapped := handlers.CORS(allowedOrigins, allowedMethods, allowedHeaders, allowCredentials)(r)
@@ -671,6 +970,45 @@ func resolveEmailSender() emailService.Sender {
 	return emailService.NewBrevoSender(apiKey, fromEmail, fromName)
 }
 
+// resolvePaymentProvider picks the provider from PAYMENT_PROVIDER ("fake" or "hosted").
+// Returns nil when online payments are disabled or misconfigured.
+func resolvePaymentProvider() paymentsService.Provider {
+	provider := strings.ToLower(strings.TrimSpace(os.Getenv("PAYMENT_PROVIDER")))
+	webhookSecret := stripEnvQuotes(strings.TrimSpace(os.Getenv("PAYMENT_WEBHOOK_SECRET")))
+
+	switch provider {
+	case "":
+		logger.Info().Msg("Payment provider not configured, online checkout disabled")
+		return nil
+	case "fake":
+		if webhookSecret == "" {
+			logger.Warn().Msg("PAYMENT_WEBHOOK_SECRET is required, online checkout disabled")
+			return nil
+		}
+		baseURL := firstNonEmpty(os.Getenv("PAYMENT_FAKE_BASE_URL"), "http://localhost:8080")
+		logger.Info().Str("base_url", baseURL).Msg("Fake payment provider enabled")
+		return paymentsService.NewFakeProvider(baseURL, webhookSecret)
+	case "hosted":
+		apiURL := strings.TrimSpace(os.Getenv("PAYMENT_HOSTED_API_URL"))
+		apiKey := stripEnvQuotes(strings.TrimSpace(os.Getenv("PAYMENT_HOSTED_API_KEY")))
+		if apiURL == "" || apiKey == "" || webhookSecret == "" {
+			logger.Warn().Msg("Hosted payment provider needs PAYMENT_HOSTED_API_URL, PAYMENT_HOSTED_API_KEY and PAYMENT_WEBHOOK_SECRET, online checkout disabled")
+			return nil
+		}
+		logger.Info().Str("api_url", apiURL).Msg("Hosted checkout payment provider enabled")
+		return paymentsService.NewHostedCheckoutProvider(
+			apiURL,
+			apiKey,
+			webhookSecret,
+			strings.TrimSpace(os.Getenv("PAYMENT_SUCCESS_URL")),
+			strings.TrimSpace(os.Getenv("PAYMENT_CANCEL_URL")),
+		)
+	default:
+		logger.Warn().Str("provider", provider).Msg("Unknown PAYMENT_PROVIDER, online checkout disabled")
+		return nil
+	}
+}
+
 func stripEnvQuotes(v string) string {
 	if len(v) >= 2 {
 		if (v[0] == '"' && v[len(v)-1] == '"') || (v[0] == '\'' && v[len(v)-1] == '\'') {
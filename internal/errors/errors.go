@@ -42,6 +42,54 @@ var (
 	ErrInvalidStatusTransition = NewBadRequest(errors.New("invalid status transition"))
 	ErrOrderAlreadyCancelled   = NewBadRequest(errors.New("order is already cancelled and cannot be modified"))
 	ErrOrderAlreadyDelivered   = NewBadRequest(errors.New("order is already delivered and cannot be modified"))
+	ErrOrderItemsNotEditable   = NewBadRequest(errors.New("order items can only be changed while the order is pending or preparing"))
+	ErrOrderNotCancellableByCustomer = NewConflict(errors.New("order can only be cancelled while it is pending"))
+	ErrOrderStatusChanged            = NewConflict(errors.New("order status was changed by another request"))
+	ErrIdempotencyKeyReused          = NewConflict(errors.New("idempotency key was already used with a different request"))
+	ErrIdempotencyKeyInProgress      = NewConflict(errors.New("a request with this idempotency key is still being processed"))
+	// Delivery capacity errors
+	ErrDeliveryDayOverrideNotFound = errors.New("delivery day override not found")
+	ErrDeliveryDateClosed          = NewConflict(errors.New("delivery date is not available"))
+	ErrDeliveryDateFullyBooked     = NewConflict(errors.New("delivery date is fully booked"))
+	ErrDeliveryLeadTimeNotMet      = NewBadRequest(errors.New("delivery date is too soon for the minimum lead time"))
+	// Standing order errors
+	ErrStandingOrderNotFound = errors.New("standing order not found")
+	// Promotion errors
+	ErrPromotionNotFound             = errors.New("promotion not found")
+	ErrPromotionCodeExists           = NewConflict(errors.New("a promotion with this code already exists"))
+	ErrPromotionCodeInvalid          = NewBadRequest(errors.New("promotion code is not valid"))
+	ErrPromotionMinOrderTotalNotMet  = NewBadRequest(errors.New("order total is below the promotion minimum"))
+	ErrPromotionNotApplicable        = NewBadRequest(errors.New("promotion does not apply to any item of the order"))
+	ErrPromotionUsageLimitReached    = NewConflict(errors.New("promotion code has reached its usage limit"))
+	ErrPromotionCustomerLimitReached = NewConflict(errors.New("promotion code has reached its usage limit for this customer"))
+	// Pricing errors
+	ErrDeliveryZoneNotFound   = errors.New("delivery zone not found")
+	ErrDeliveryZoneNameExists = NewConflict(errors.New("a delivery zone with this name already exists"))
+	ErrDeliveryZoneInvalid    = NewBadRequest(errors.New("delivery zone is not available"))
+	// Pickup location errors
+	ErrPickupLocationNotFound   = errors.New("pickup location not found")
+	ErrPickupLocationNameExists = NewConflict(errors.New("a pickup location with this name already exists"))
+	ErrPickupLocationInvalid    = NewBadRequest(errors.New("pickup location is not available"))
+	ErrPickupLocationClosed     = NewBadRequest(errors.New("pickup location is closed on the delivery date"))
+	// Customer errors
+	ErrCustomerNotFound     = errors.New("customer not found")
+	ErrCustomerMergeInvalid = NewBadRequest(errors.New("duplicate accounts must be other active customers of the tenant"))
+	// Order protection errors
+	ErrBlocklistEntryNotFound    = errors.New("blocklist entry not found")
+	ErrBlocklistEntryExists      = NewConflict(errors.New("this email or phone is already blocked"))
+	ErrOrderCustomerBlocked      = NewUnprocessableEntity(errors.New("orders from this email or phone are not accepted"))
+	ErrOrderLineQuantityExceeded = NewUnprocessableEntity(errors.New("quantity per product is above the tenant maximum"))
+	ErrTooManyPendingOrders      = NewTooManyRequests(errors.New("too many unpaid orders are pending for this customer"))
+	// Webhook errors
+	ErrWebhookSubscriptionNotFound = errors.New("webhook subscription not found")
+	// Payment errors
+	ErrPaymentNotFound           = errors.New("payment not found")
+	ErrOrderAlreadyPaid          = NewConflict(errors.New("order is already paid"))
+	ErrOrderNotPayable           = NewConflict(errors.New("order can no longer be paid"))
+	ErrInvalidPaymentSignature   = errors.New("invalid payment webhook signature")
+	ErrPaymentProviderNotEnabled = errors.New("payment provider not configured")
+	ErrPaymentExceedsBalance     = NewBadRequest(errors.New("payment amount exceeds the balance due"))
+	ErrRefundExceedsAmountPaid   = NewBadRequest(errors.New("refund amount exceeds the amount paid"))
 	// Auth action token errors
 	ErrInvalidToken         = errors.New("invalid token")
 	ErrExpiredToken         = errors.New("expired token")
@@ -43,3 +43,17 @@ func NewConflict(err error) *HTTPError {
 		StatusCode: http.StatusConflict,
 	}
 }
+
+func NewUnprocessableEntity(err error) *HTTPError {
+	return &HTTPError{
+		Err:        err,
+		StatusCode: http.StatusUnprocessableEntity,
+	}
+}
+
+func NewTooManyRequests(err error) *HTTPError {
+	return &HTTPError{
+		Err:        err,
+		StatusCode: http.StatusTooManyRequests,
+	}
+}
@@ -41,3 +41,12 @@ func TestHTTPError_NewConflict(t *testing.T) {
 	require.True(t, errors.As(err, &he))
 	assert.Equal(t, http.StatusConflict, he.StatusCode)
 }
+
+func TestHTTPError_NewUnprocessableEntityAndTooManyRequests(t *testing.T) {
+	var he *HTTPError
+	require.True(t, errors.As(ErrOrderCustomerBlocked, &he))
+	assert.Equal(t, http.StatusUnprocessableEntity, he.StatusCode)
+
+	require.True(t, errors.As(ErrTooManyPendingOrders, &he))
+	assert.Equal(t, http.StatusTooManyRequests, he.StatusCode)
+}
@@ -0,0 +1,137 @@
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/radamesvaz/bakery-app/internal/handlers/validators"
+	analyticsService "github.com/radamesvaz/bakery-app/internal/services/analytics"
+	aModel "github.com/radamesvaz/bakery-app/model/analytics"
+)
+
+// defaultAnalyticsDays is the trailing range used when from/to are omitted.
+const defaultAnalyticsDays = 30
+
+type AnalyticsHandler struct {
+	Service *analyticsService.Service
+}
+
+// parseAnalyticsRange reads from/to (YYYY-MM-DD, inclusive). to defaults to today (UTC) and from
+// to the 30 days ending at to.
+func parseAnalyticsRange(w http.ResponseWriter, r *http.Request) (time.Time, time.Time, bool) {
+	q := r.URL.Query()
+	to := todayUTC()
+	if raw := q.Get("to"); strings.TrimSpace(raw) != "" {
+		parsed, err := validators.ParseDeliveryDate("to", raw)
+		if err != nil {
+			writeRepoError(w, err, err.Error())
+			return time.Time{}, time.Time{}, false
+		}
+		to = parsed
+	}
+	fromStr := q.Get("from")
+	if strings.TrimSpace(fromStr) == "" {
+		fromStr = to.AddDate(0, 0, -(defaultAnalyticsDays - 1)).Format(time.DateOnly)
+	}
+	from, to, err := validators.ParseDateRange(fromStr, to.Format(time.DateOnly), to, defaultAnalyticsDays, analyticsService.MaxRangeDays)
+	if err != nil {
+		writeRepoError(w, err, err.Error())
+		return time.Time{}, time.Time{}, false
+	}
+	return from, to, true
+}
+
+// GetRevenue returns revenue, paid vs unpaid and average order value per day, week or month
+// (GET /auth/analytics/revenue?from=&to=&interval=day|week|month).
+func (h *AnalyticsHandler) GetRevenue(w http.ResponseWriter, r *http.Request) {
+	from, to, ok := parseAnalyticsRange(w, r)
+	if !ok {
+		return
+	}
+	interval := aModel.IntervalDay
+	if raw := strings.TrimSpace(r.URL.Query().Get("interval")); raw != "" {
+		interval = aModel.Interval(strings.ToLower(raw))
+	}
+	if !aModel.IsValidInterval(interval) {
+		http.Error(w, "interval must be one of day, week, month", http.StatusBadRequest)
+		return
+	}
+	tenantID, ok := requireTenantID(w, r)
+	if !ok {
+		return
+	}
+
+	report, err := h.Service.GetRevenue(r.Context(), tenantID, from, to, interval)
+	if err != nil {
+		writeRepoError(w, err, "Failed to get revenue")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(report)
+}
+
+// GetTopProducts returns the best selling products by units or revenue
+// (GET /auth/analytics/top-products?from=&to=&sort=units|revenue&limit=).
+func (h *AnalyticsHandler) GetTopProducts(w http.ResponseWriter, r *http.Request) {
+	from, to, ok := parseAnalyticsRange(w, r)
+	if !ok {
+		return
+	}
+	sortBy := aModel.SortByUnits
+	if raw := strings.TrimSpace(r.URL.Query().Get("sort")); raw != "" {
+		sortBy = aModel.TopProductsSort(strings.ToLower(raw))
+	}
+	if sortBy != aModel.SortByUnits && sortBy != aModel.SortByRevenue {
+		http.Error(w, "sort must be one of units, revenue", http.StatusBadRequest)
+		return
+	}
+	limit := analyticsService.DefaultTopProductsLimit
+	if raw := r.URL.Query().Get("limit"); raw != "" {
+		n, err := strconv.Atoi(raw)
+		if err != nil || n < 1 || n > analyticsService.MaxTopProductsLimit {
+			http.Error(w, fmt.Sprintf("limit must be between 1 and %d", analyticsService.MaxTopProductsLimit), http.StatusBadRequest)
+			return
+		}
+		limit = n
+	}
+	tenantID, ok := requireTenantID(w, r)
+	if !ok {
+		return
+	}
+
+	report, err := h.Service.GetTopProducts(r.Context(), tenantID, from, to, sortBy, limit)
+	if err != nil {
+		writeRepoError(w, err, "Failed to get top products")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(report)
+}
+
+// GetCancellations returns the share of orders cancelled by admins, cancelled by customers and
+// expired unpaid (GET /auth/analytics/cancellations?from=&to=).
+func (h *AnalyticsHandler) GetCancellations(w http.ResponseWriter, r *http.Request) {
+	from, to, ok := parseAnalyticsRange(w, r)
+	if !ok {
+		return
+	}
+	tenantID, ok := requireTenantID(w, r)
+	if !ok {
+		return
+	}
+
+	report, err := h.Service.GetCancellations(r.Context(), tenantID, from, to)
+	if err != nil {
+		writeRepoError(w, err, "Failed to get cancellations")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(report)
+}
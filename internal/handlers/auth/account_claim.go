@@ -0,0 +1,99 @@
+package auth
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+
+	v "github.com/radamesvaz/bakery-app/internal/handlers/validators"
+	"github.com/radamesvaz/bakery-app/internal/middleware"
+	accountclaimService "github.com/radamesvaz/bakery-app/internal/services/accountclaim"
+	authModel "github.com/radamesvaz/bakery-app/model/auth"
+)
+
+// AccountClaimHandler serves the flow that lets a customer created by an order set its password
+// (POST /t/{tenant_slug}/auth/account/claim and /account/claim/complete).
+type AccountClaimHandler struct {
+	Service accountclaimService.Service
+}
+
+func (h *AccountClaimHandler) RequestClaim(w http.ResponseWriter, r *http.Request) {
+	if h.Service == nil {
+		writeJSONAPIError(w, http.StatusInternalServerError, "service_unconfigured", "Account claim service not configured")
+		return
+	}
+
+	var req authModel.AccountClaimRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeJSONAPIError(w, http.StatusBadRequest, "bad_request", "Invalid request body")
+		return
+	}
+	if !v.IsValidEmail(strings.TrimSpace(req.Email)) {
+		writeJSONAPIError(w, http.StatusBadRequest, "invalid_email", "Invalid Email")
+		return
+	}
+
+	tenantID, err := middleware.GetTenantIDFromContext(r.Context())
+	if err != nil || tenantID == 0 {
+		writeJSONAPIError(w, http.StatusBadRequest, "tenant_context_missing", "tenant context missing")
+		return
+	}
+	tenantSlug, err := middleware.GetTenantSlugFromContext(r.Context())
+	if err != nil || strings.TrimSpace(tenantSlug) == "" {
+		writeJSONAPIError(w, http.StatusBadRequest, "tenant_context_missing", "tenant context missing")
+		return
+	}
+
+	if err := h.Service.RequestClaim(r.Context(), tenantID, tenantSlug, req.Email); err != nil {
+		respondForgotPasswordJSONError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(http.StatusOK)
+	_ = json.NewEncoder(w).Encode(authModel.AccountClaimResponse{
+		Message: "If the account can be claimed, instructions will be sent.",
+	})
+}
+
+func (h *AccountClaimHandler) CompleteClaim(w http.ResponseWriter, r *http.Request) {
+	if h.Service == nil {
+		writeJSONAPIError(w, http.StatusInternalServerError, "service_unconfigured", "Account claim service not configured")
+		return
+	}
+
+	var req authModel.CompleteAccountClaimRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeJSONAPIError(w, http.StatusBadRequest, "bad_request", "Invalid request body")
+		return
+	}
+	if strings.TrimSpace(req.Token) == "" {
+		writeJSONAPIError(w, http.StatusBadRequest, "bad_request", "token is required")
+		return
+	}
+	if err := v.ValidatePassword(req.NewPassword); err != nil {
+		writeValidatorJSONError(w, err, "Password does not meet security requirements")
+		return
+	}
+
+	tenantID, err := middleware.GetTenantIDFromContext(r.Context())
+	if err != nil || tenantID == 0 {
+		writeJSONAPIError(w, http.StatusBadRequest, "tenant_context_missing", "tenant context missing")
+		return
+	}
+
+	err = h.Service.CompleteClaim(r.Context(), tenantID, req.Token, req.NewPassword)
+	if err != nil {
+		if respondPasswordResetError(w, err) {
+			return
+		}
+		writeJSONAPIError(w, http.StatusInternalServerError, "internal_error", "Could not claim account")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(http.StatusOK)
+	_ = json.NewEncoder(w).Encode(authModel.CompleteAccountClaimResponse{
+		Message: "Account claimed successfully",
+	})
+}
@@ -0,0 +1,169 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/radamesvaz/bakery-app/internal/handlers/validators"
+	capacityRepository "github.com/radamesvaz/bakery-app/internal/repository/capacity"
+	orderService "github.com/radamesvaz/bakery-app/internal/services/orders"
+	capModel "github.com/radamesvaz/bakery-app/model/capacity"
+)
+
+// defaultAvailabilityDays is the range returned when the storefront omits "to".
+const defaultAvailabilityDays = 30
+
+type DeliveryCapacityHandler struct {
+	Repo *capacityRepository.Repository
+}
+
+func parseDeliveryDayPath(w http.ResponseWriter, r *http.Request) (time.Time, bool) {
+	date, err := validators.ParseDeliveryDate("date", mux.Vars(r)["date"])
+	if err != nil {
+		writeRepoError(w, err, err.Error())
+		return time.Time{}, false
+	}
+	return date, true
+}
+
+func todayUTC() time.Time {
+	return time.Now().UTC().Truncate(24 * time.Hour)
+}
+
+// GetSettings returns the tenant's default delivery limits (GET /auth/delivery-capacity).
+func (h *DeliveryCapacityHandler) GetSettings(w http.ResponseWriter, r *http.Request) {
+	tenantID, ok := requireTenantID(w, r)
+	if !ok {
+		return
+	}
+
+	settings, err := h.Repo.GetSettings(r.Context(), tenantID)
+	if err != nil {
+		writeRepoError(w, err, "Failed to get delivery capacity")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(settings)
+}
+
+// UpdateSettings replaces the tenant's default delivery limits (PUT /auth/delivery-capacity).
+func (h *DeliveryCapacityHandler) UpdateSettings(w http.ResponseWriter, r *http.Request) {
+	var req capModel.Settings
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+	if err := validators.ValidateDeliveryCapacitySettings(req); err != nil {
+		writeRepoError(w, err, err.Error())
+		return
+	}
+	tenantID, ok := requireTenantID(w, r)
+	if !ok {
+		return
+	}
+
+	if err := h.Repo.UpdateSettings(r.Context(), tenantID, req); err != nil {
+		writeRepoError(w, err, "Failed to update delivery capacity")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(req)
+}
+
+// ListDayOverrides returns blackout dates and per-date limits (GET /auth/delivery-capacity/days?from=&to=).
+func (h *DeliveryCapacityHandler) ListDayOverrides(w http.ResponseWriter, r *http.Request) {
+	from, to, err := validators.ParseDateRange(r.URL.Query().Get("from"), r.URL.Query().Get("to"), todayUTC(), 366, 366)
+	if err != nil {
+		writeRepoError(w, err, err.Error())
+		return
+	}
+	tenantID, ok := requireTenantID(w, r)
+	if !ok {
+		return
+	}
+
+	items, err := h.Repo.ListDayOverrides(r.Context(), tenantID, from, to)
+	if err != nil {
+		writeRepoError(w, err, "Failed to get delivery days")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(capModel.DayOverridesResponse{Items: items})
+}
+
+// UpsertDayOverride closes a date or sets its own limits (PUT /auth/delivery-capacity/days/{date}).
+func (h *DeliveryCapacityHandler) UpsertDayOverride(w http.ResponseWriter, r *http.Request) {
+	date, ok := parseDeliveryDayPath(w, r)
+	if !ok {
+		return
+	}
+	var req capModel.UpsertDayOverrideRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+	if err := validators.ValidateDeliveryDayOverride(req); err != nil {
+		writeRepoError(w, err, err.Error())
+		return
+	}
+	tenantID, ok := requireTenantID(w, r)
+	if !ok {
+		return
+	}
+
+	day, err := h.Repo.UpsertDayOverride(r.Context(), tenantID, date, req)
+	if err != nil {
+		writeRepoError(w, err, "Failed to update delivery day")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(day)
+}
+
+// DeleteDayOverride restores the default limits on a date (DELETE /auth/delivery-capacity/days/{date}).
+func (h *DeliveryCapacityHandler) DeleteDayOverride(w http.ResponseWriter, r *http.Request) {
+	date, ok := parseDeliveryDayPath(w, r)
+	if !ok {
+		return
+	}
+	tenantID, ok := requireTenantID(w, r)
+	if !ok {
+		return
+	}
+
+	if err := h.Repo.DeleteDayOverride(r.Context(), tenantID, date); err != nil {
+		writeRepoError(w, err, "Failed to delete delivery day")
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// GetAvailability tells the storefront which delivery dates can still be booked
+// (GET /t/{tenant_slug}/availability?from=&to=, public).
+func (h *DeliveryCapacityHandler) GetAvailability(w http.ResponseWriter, r *http.Request) {
+	from, to, err := validators.ParseDateRange(r.URL.Query().Get("from"), r.URL.Query().Get("to"), todayUTC(), defaultAvailabilityDays, orderService.MaxAvailabilityDays)
+	if err != nil {
+		writeRepoError(w, err, err.Error())
+		return
+	}
+	tenantID, ok := requireTenantID(w, r)
+	if !ok {
+		return
+	}
+
+	resp, err := orderService.NewAvailability(h.Repo).ListAvailability(r.Context(), tenantID, from, to)
+	if err != nil {
+		writeRepoError(w, err, "Failed to get availability")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(resp)
+}
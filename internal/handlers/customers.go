@@ -0,0 +1,129 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"github.com/gorilla/mux"
+	"github.com/radamesvaz/bakery-app/internal/handlers/validators"
+	"github.com/radamesvaz/bakery-app/internal/pagination"
+	customersRepository "github.com/radamesvaz/bakery-app/internal/repository/customers"
+	customerModel "github.com/radamesvaz/bakery-app/model/customers"
+)
+
+type CustomerHandler struct {
+	Repo *customersRepository.Repository
+}
+
+type customersListResponse struct {
+	Items      []customerModel.Customer `json:"items"`
+	NextCursor *string                  `json:"next_cursor"`
+}
+
+func parseCustomerID(w http.ResponseWriter, r *http.Request) (uint64, bool) {
+	id, err := strconv.ParseUint(mux.Vars(r)["id"], 10, 64)
+	if err != nil || id == 0 {
+		http.Error(w, "Invalid customer ID", http.StatusBadRequest)
+		return 0, false
+	}
+	return id, true
+}
+
+// ListCustomers returns the tenant's customers with their order stats, newest first (GET /auth/customers).
+// Query: limit, cursor, optional q (case-insensitive name, email or phone contains; min 2 chars).
+func (h *CustomerHandler) ListCustomers(w http.ResponseWriter, r *http.Request) {
+	tenantID, ok := requireTenantID(w, r)
+	if !ok {
+		return
+	}
+
+	limit, err := validators.ParseListLimit(r.URL.Query().Get("limit"))
+	if err != nil {
+		writeRepoError(w, err, err.Error())
+		return
+	}
+
+	searchRaw, err := validators.ParseCustomerSearchQuery(r.URL.Query().Get("q"))
+	if err != nil {
+		writeRepoError(w, err, err.Error())
+		return
+	}
+	var searchPattern *string
+	if searchRaw != nil {
+		pat := validators.ContainsLikePattern(*searchRaw)
+		searchPattern = &pat
+	}
+
+	var afterID *uint64
+	if c := r.URL.Query().Get("cursor"); c != "" {
+		id, err := pagination.DecodeIDCursor(c)
+		if err != nil {
+			http.Error(w, "Invalid cursor", http.StatusBadRequest)
+			return
+		}
+		afterID = &id
+	}
+
+	page, err := h.Repo.ListCustomersPage(r.Context(), tenantID, searchPattern, limit, afterID)
+	if err != nil {
+		http.Error(w, "Failed to get customers", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(customersListResponse{Items: page.Items, NextCursor: page.NextCursor})
+}
+
+// GetCustomer returns a customer with its order stats and the accounts sharing its phone
+// (GET /auth/customers/{id}).
+func (h *CustomerHandler) GetCustomer(w http.ResponseWriter, r *http.Request) {
+	id, ok := parseCustomerID(w, r)
+	if !ok {
+		return
+	}
+	tenantID, ok := requireTenantID(w, r)
+	if !ok {
+		return
+	}
+
+	customer, err := h.Repo.GetCustomer(r.Context(), tenantID, id)
+	if err != nil {
+		writeRepoError(w, err, "Failed to get customer")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(customer)
+}
+
+// MergeCustomers folds duplicate accounts into a customer (POST /auth/customers/{id}/merge): their
+// orders and history move to {id} and the duplicates are deleted.
+func (h *CustomerHandler) MergeCustomers(w http.ResponseWriter, r *http.Request) {
+	id, ok := parseCustomerID(w, r)
+	if !ok {
+		return
+	}
+	var req customerModel.MergeRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+	if err := validators.ValidateMergeRequest(id, req); err != nil {
+		writeRepoError(w, err, err.Error())
+		return
+	}
+	tenantID, ok := requireTenantID(w, r)
+	if !ok {
+		return
+	}
+
+	result, err := h.Repo.MergeCustomers(r.Context(), tenantID, id, req.DuplicateIDs)
+	if err != nil {
+		writeRepoError(w, err, "Failed to merge customers")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(result)
+}
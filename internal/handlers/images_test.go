@@ -71,7 +71,7 @@ func TestImageHandler_UploadProductThumbnail_Success(t *testing.T) {
 		productID,
 		"Cake",
 		"desc",
-		10.5,
+		"10.50",
 		true,
 		uint64(3),
 		"active",
@@ -149,7 +149,7 @@ func TestImageHandler_AddProductImages_ThumbnailUpdateFailureKeepsResponseEmpty(
 		productID,
 		"Cake",
 		"desc",
-		10.5,
+		"10.50",
 		true,
 		uint64(3),
 		"active",
@@ -0,0 +1,89 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"github.com/gorilla/mux"
+	appErrors "github.com/radamesvaz/bakery-app/internal/errors"
+	v "github.com/radamesvaz/bakery-app/internal/handlers/validators"
+	"github.com/radamesvaz/bakery-app/internal/middleware"
+	ordersRepository "github.com/radamesvaz/bakery-app/internal/repository/orders"
+	oModel "github.com/radamesvaz/bakery-app/model/orders"
+)
+
+// ListMyOrders lists the orders of the signed-in customer (GET /t/{tenant_slug}/me/orders), oldest first.
+// Query: limit, cursor, optional status. Deleted orders are never listed.
+func (h *OrderHandler) ListMyOrders(w http.ResponseWriter, r *http.Request) {
+	tenantID, ok := requireTenantID(w, r)
+	if !ok {
+		return
+	}
+	userID, err := middleware.GetUserIDFromContext(r.Context())
+	if err != nil {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	filter := ordersRepository.OrderListFilter{UserID: &userID}
+	status := r.URL.Query().Get("status")
+	if err := v.ValidateOrderListStatusFilter(status); err != nil {
+		writeRepoError(w, err, err.Error())
+		return
+	}
+	if status == string(oModel.StatusDeleted) {
+		http.Error(w, "Invalid status value", http.StatusBadRequest)
+		return
+	}
+	if status != "" {
+		filter.Status = &status
+	}
+
+	limit, err := v.ParseListLimit(r.URL.Query().Get("limit"))
+	if err != nil {
+		writeRepoError(w, err, err.Error())
+		return
+	}
+
+	page, err := h.Repo.ListOrdersWithFiltersPage(r.Context(), tenantID, filter, limit, r.URL.Query().Get("cursor"))
+	if err != nil {
+		writeRepoError(w, err, "Error getting orders")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(ordersListResponse{Items: page.Items, NextCursor: page.NextCursor})
+}
+
+// GetMyOrder returns one order of the signed-in customer (GET /t/{tenant_slug}/me/orders/{id}). Orders of
+// other customers and deleted orders answer 404.
+func (h *OrderHandler) GetMyOrder(w http.ResponseWriter, r *http.Request) {
+	idOrder, err := strconv.ParseUint(mux.Vars(r)["id"], 10, 64)
+	if err != nil || idOrder == 0 {
+		http.Error(w, "Invalid order ID", http.StatusBadRequest)
+		return
+	}
+	tenantID, ok := requireTenantID(w, r)
+	if !ok {
+		return
+	}
+	userID, err := middleware.GetUserIDFromContext(r.Context())
+	if err != nil {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	order, err := h.Repo.GetOrderByID(r.Context(), tenantID, idOrder)
+	if err != nil {
+		writeRepoError(w, err, "internal server error")
+		return
+	}
+	if order.IdUser != userID || order.Status == oModel.StatusDeleted {
+		writeRepoError(w, appErrors.NewNotFound(appErrors.ErrOrderNotFound), "")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(order)
+}
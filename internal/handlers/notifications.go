@@ -0,0 +1,64 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/radamesvaz/bakery-app/internal/handlers/validators"
+	notificationsRepository "github.com/radamesvaz/bakery-app/internal/repository/notifications"
+	nModel "github.com/radamesvaz/bakery-app/model/notifications"
+)
+
+type NotificationSettingsHandler struct {
+	Repo *notificationsRepository.Repository
+}
+
+// GetSettings returns whether each order email is enabled for the tenant (GET /auth/notifications/settings).
+func (h *NotificationSettingsHandler) GetSettings(w http.ResponseWriter, r *http.Request) {
+	tenantID, ok := requireTenantID(w, r)
+	if !ok {
+		return
+	}
+
+	settings, err := h.Repo.ListSettings(r.Context(), tenantID)
+	if err != nil {
+		writeRepoError(w, err, "Failed to get notification settings")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(nModel.SettingsResponse{Items: settings})
+}
+
+// UpdateSettings turns order emails on or off (PUT /auth/notifications/settings).
+// Kinds not present in the body keep their current value.
+func (h *NotificationSettingsHandler) UpdateSettings(w http.ResponseWriter, r *http.Request) {
+	var req nModel.UpdateSettingsRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+	if err := validators.ValidateNotificationSettings(req.Items); err != nil {
+		writeRepoError(w, err, err.Error())
+		return
+	}
+
+	ctx := r.Context()
+	tenantID, ok := requireTenantID(w, r)
+	if !ok {
+		return
+	}
+
+	if err := h.Repo.UpdateSettings(ctx, tenantID, req.Items); err != nil {
+		writeRepoError(w, err, "Failed to update notification settings")
+		return
+	}
+	settings, err := h.Repo.ListSettings(ctx, tenantID)
+	if err != nil {
+		writeRepoError(w, err, "Failed to get notification settings")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(nModel.SettingsResponse{Items: settings})
+}
@@ -0,0 +1,126 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"github.com/gorilla/mux"
+	"github.com/radamesvaz/bakery-app/internal/handlers/validators"
+	orderProtectionRepository "github.com/radamesvaz/bakery-app/internal/repository/orderprotection"
+	opModel "github.com/radamesvaz/bakery-app/model/orderprotection"
+)
+
+type OrderProtectionHandler struct {
+	Repo *orderProtectionRepository.Repository
+}
+
+// GetSettings returns the tenant's abuse limits for storefront orders (GET /auth/order-protection).
+func (h *OrderProtectionHandler) GetSettings(w http.ResponseWriter, r *http.Request) {
+	tenantID, ok := requireTenantID(w, r)
+	if !ok {
+		return
+	}
+
+	settings, err := h.Repo.GetSettings(r.Context(), tenantID)
+	if err != nil {
+		writeRepoError(w, err, "Failed to get order protection settings")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(settings)
+}
+
+// UpdateSettings replaces the tenant's abuse limits for storefront orders
+// (PUT /auth/order-protection). 0 disables a limit.
+func (h *OrderProtectionHandler) UpdateSettings(w http.ResponseWriter, r *http.Request) {
+	var settings opModel.Settings
+	if err := json.NewDecoder(r.Body).Decode(&settings); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+	if err := validators.ValidateOrderProtectionSettings(settings); err != nil {
+		writeRepoError(w, err, err.Error())
+		return
+	}
+	tenantID, ok := requireTenantID(w, r)
+	if !ok {
+		return
+	}
+
+	if err := h.Repo.UpdateSettings(r.Context(), tenantID, settings); err != nil {
+		writeRepoError(w, err, "Failed to update order protection settings")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(settings)
+}
+
+// ListBlocklist returns the emails and phones the storefront rejects orders from, newest first
+// (GET /auth/order-blocklist).
+func (h *OrderProtectionHandler) ListBlocklist(w http.ResponseWriter, r *http.Request) {
+	tenantID, ok := requireTenantID(w, r)
+	if !ok {
+		return
+	}
+
+	items, err := h.Repo.ListBlocklist(r.Context(), tenantID)
+	if err != nil {
+		writeRepoError(w, err, "Failed to get order blocklist")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(opModel.BlocklistResponse{Items: items})
+}
+
+// CreateBlocklistEntry blocks an email or phone (POST /auth/order-blocklist). Orders already
+// placed are not affected.
+func (h *OrderProtectionHandler) CreateBlocklistEntry(w http.ResponseWriter, r *http.Request) {
+	var req opModel.BlocklistEntryRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+	in, err := validators.ValidateBlocklistEntryRequest(req)
+	if err != nil {
+		writeRepoError(w, err, err.Error())
+		return
+	}
+	tenantID, ok := requireTenantID(w, r)
+	if !ok {
+		return
+	}
+
+	entry, err := h.Repo.CreateBlocklistEntry(r.Context(), tenantID, in)
+	if err != nil {
+		writeRepoError(w, err, "Failed to create blocklist entry")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(entry)
+}
+
+// DeleteBlocklistEntry unblocks an email or phone (DELETE /auth/order-blocklist/{id}).
+func (h *OrderProtectionHandler) DeleteBlocklistEntry(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.ParseUint(mux.Vars(r)["id"], 10, 64)
+	if err != nil || id == 0 {
+		http.Error(w, "Invalid blocklist entry ID", http.StatusBadRequest)
+		return
+	}
+	tenantID, ok := requireTenantID(w, r)
+	if !ok {
+		return
+	}
+
+	if err := h.Repo.DeleteBlocklistEntry(r.Context(), tenantID, id); err != nil {
+		writeRepoError(w, err, "Failed to delete blocklist entry")
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -0,0 +1,79 @@
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/radamesvaz/bakery-app/internal/logger"
+	oModel "github.com/radamesvaz/bakery-app/model/orders"
+)
+
+// orderStreamRetryMillis is the reconnect delay suggested to EventSource clients.
+const orderStreamRetryMillis = 3000
+
+// StreamOrders pushes the tenant's order changes as Server-Sent Events (GET /auth/orders/stream).
+// Each event's SSE id is the stream's resume id, so a reconnecting client sends it back as
+// Last-Event-ID (or ?last_event_id=) and misses nothing; the orders_history id is the "id" of the
+// data, which clients use to drop events re-sent after a reconnect.
+func (h *OrderHandler) StreamOrders(w http.ResponseWriter, r *http.Request) {
+	tenantID, ok := requireTenantID(w, r)
+	if !ok {
+		return
+	}
+	if h.Stream == nil {
+		http.Error(w, "Order stream is not available", http.StatusServiceUnavailable)
+		return
+	}
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		http.Error(w, "Streaming is not supported", http.StatusInternalServerError)
+		return
+	}
+
+	var lastEventID *uint64
+	raw := r.Header.Get("Last-Event-ID")
+	if raw == "" {
+		raw = r.URL.Query().Get("last_event_id")
+	}
+	if raw != "" {
+		id, err := strconv.ParseUint(raw, 10, 64)
+		if err != nil {
+			http.Error(w, "Invalid Last-Event-ID", http.StatusBadRequest)
+			return
+		}
+		lastEventID = &id
+	}
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.Header().Set("X-Accel-Buffering", "no")
+	w.WriteHeader(http.StatusOK)
+	fmt.Fprintf(w, "retry: %d\n\n", orderStreamRetryMillis)
+	flusher.Flush()
+
+	send := func(e oModel.OrderStreamEvent, resumeID uint64) error {
+		data, err := json.Marshal(e)
+		if err != nil {
+			return err
+		}
+		if _, err := fmt.Fprintf(w, "id: %d\nevent: %s\ndata: %s\n\n", resumeID, e.Type, data); err != nil {
+			return err
+		}
+		flusher.Flush()
+		return nil
+	}
+	keepAlive := func() error {
+		if _, err := fmt.Fprint(w, ": keep-alive\n\n"); err != nil {
+			return err
+		}
+		flusher.Flush()
+		return nil
+	}
+
+	if err := h.Stream.Stream(r.Context(), tenantID, lastEventID, send, keepAlive); err != nil && r.Context().Err() == nil {
+		logger.Err(err).Uint64("tenant_id", tenantID).Msg("Order stream ended with an error")
+	}
+}
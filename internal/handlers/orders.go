@@ -6,7 +6,9 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"math"
 	"net/http"
+	"net/url"
 	"strconv"
 	"strings"
 	"time"
@@ -16,12 +18,18 @@ import (
 	v "github.com/radamesvaz/bakery-app/internal/handlers/validators"
 	"github.com/radamesvaz/bakery-app/internal/logger"
 	"github.com/radamesvaz/bakery-app/internal/middleware"
-	"github.com/radamesvaz/bakery-app/internal/pagination"
+	orderProtectionRepository "github.com/radamesvaz/bakery-app/internal/repository/orderprotection"
 	ordersRepository "github.com/radamesvaz/bakery-app/internal/repository/orders"
+	pickupRepository "github.com/radamesvaz/bakery-app/internal/repository/pickup"
+	pricingRepository "github.com/radamesvaz/bakery-app/internal/repository/pricing"
 	productRepo "github.com/radamesvaz/bakery-app/internal/repository/products"
+	promotionsRepository "github.com/radamesvaz/bakery-app/internal/repository/promotions"
 	tenantRepository "github.com/radamesvaz/bakery-app/internal/repository/tenant"
 	userRepo "github.com/radamesvaz/bakery-app/internal/repository/user"
 	orderService "github.com/radamesvaz/bakery-app/internal/services/orders"
+	"github.com/radamesvaz/bakery-app/internal/services/tokens"
+	"github.com/radamesvaz/bakery-app/internal/xlsx"
+	opModel "github.com/radamesvaz/bakery-app/model/orderprotection"
 	oModel "github.com/radamesvaz/bakery-app/model/orders"
 )
 
@@ -30,15 +38,49 @@ type OrderHandler struct {
 	UserRepo    userRepo.Repository
 	ProductRepo *productRepo.ProductRepository
 	TenantRepo  *tenantRepository.Repository
+	// Events publishes webhook events from order changes; nil disables them.
+	Events orderService.OrderEventPublisher
+	// Notifications queues order emails from order changes; nil disables them.
+	Notifications orderService.OrderNotifier
+	// Capacity enforces delivery limits on order creation; nil accepts any delivery date.
+	Capacity orderService.DeliveryCapacityRepository
+	// TrackingTokens issues and hashes public order tracking tokens; nil disables tracking links.
+	TrackingTokens tokens.OneTimeTokenManager
+	// OnlinePaymentsEnabled adds the checkout URL to the order creation response.
+	OnlinePaymentsEnabled bool
+	// Promotions redeems discount codes on order creation and re-prices them on item edits;
+	// nil rejects any promotion_code.
+	Promotions *promotionsRepository.Repository
+	// Pricing supplies the tenant's tax and delivery fees for order totals; nil prices orders
+	// without them and rejects id_delivery_zone.
+	Pricing *pricingRepository.Repository
+	// PickupLocations checks the location of pickup orders; nil rejects fulfillment_type pickup.
+	PickupLocations *pickupRepository.Repository
+	// Stream serves GET /auth/orders/stream; nil answers 503.
+	Stream *orderService.OrderStreamer
+	// Protection enforces the tenant's blocklist, line quantity and pending unpaid order limits
+	// on storefront orders; nil disables them.
+	Protection *orderProtectionRepository.Repository
+	// EmailRateLimit and PhoneRateLimit limit storefront orders per customer email and phone;
+	// nil disables them.
+	EmailRateLimit *middleware.KeyedRateLimit
+	PhoneRateLimit *middleware.KeyedRateLimit
 }
 
+const (
+	headerIdempotencyKey     = "Idempotency-Key"
+	headerIdempotentReplayed = "Idempotent-Replayed"
+	maxIdempotencyKeyLength  = 255
+)
+
 type ordersListResponse struct {
 	Items      []oModel.OrderResponse `json:"items"`
 	NextCursor *string                `json:"next_cursor"`
 }
 
-// GetAllOrders lists orders with cursor pagination (query: limit, cursor, optional id_user) and filters ignore_status, status.
-// id_user: positive integer filters orders for that user within the tenant; omit for all users.
+// GetAllOrders lists orders with cursor pagination (query: limit, cursor, optional id_user) and the filters of
+// parseOrderListFilter. id_user: positive integer filters orders for that user within the tenant; omit for all users.
+// The cursor must come from a page with the same sort.
 func (h *OrderHandler) GetAllOrders(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
 
@@ -48,9 +90,12 @@ func (h *OrderHandler) GetAllOrders(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	ignoreStatus := r.URL.Query().Get("ignore_status") == "true"
-	statusFilter := r.URL.Query().Get("status")
-	searchQuery, err := v.NormalizeAndValidateOrderSearchQuery(r.URL.Query().Get("q"))
+	filter, ok := parseOrderListFilter(w, r)
+	if !ok {
+		return
+	}
+
+	limit, err := v.ParseListLimit(r.URL.Query().Get("limit"))
 	if err != nil {
 		var he *appErrors.HTTPError
 		if errors.As(err, &he) {
@@ -61,22 +106,26 @@ func (h *OrderHandler) GetAllOrders(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	if err := v.ValidateOrderListStatusFilter(statusFilter); err != nil {
-		var he *appErrors.HTTPError
-		if errors.As(err, &he) {
-			http.Error(w, he.Error(), he.StatusCode)
-		} else {
-			http.Error(w, err.Error(), http.StatusBadRequest)
-		}
+	page, err := h.Repo.ListOrdersWithFiltersPage(ctx, tenantID, filter, limit, r.URL.Query().Get("cursor"))
+	if err != nil {
+		writeRepoError(w, err, "Error getting orders")
 		return
 	}
 
-	var statusFilterPtr *string
-	if statusFilter != "" {
-		statusFilterPtr = &statusFilter
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(ordersListResponse{Items: page.Items, NextCursor: page.NextCursor})
+}
+
+// parseOrderListFilter reads the list filters shared by GetAllOrders and ExportOrders: ignore_status, status,
+// id_user, q, created_from/created_to and delivery_from/delivery_to (inclusive dates, UTC), paid,
+// min_total/max_total, fulfillment_type (delivery or pickup) and sort (created_on or delivery_date).
+func parseOrderListFilter(w http.ResponseWriter, r *http.Request) (ordersRepository.OrderListFilter, bool) {
+	filter := ordersRepository.OrderListFilter{
+		IgnoreStatus: r.URL.Query().Get("ignore_status") == "true",
 	}
 
-	limit, err := v.ParseListLimit(r.URL.Query().Get("limit"))
+	statusFilter := r.URL.Query().Get("status")
+	searchQuery, err := v.NormalizeAndValidateOrderSearchQuery(r.URL.Query().Get("q"))
 	if err != nil {
 		var he *appErrors.HTTPError
 		if errors.As(err, &he) {
@@ -84,42 +133,142 @@ func (h *OrderHandler) GetAllOrders(w http.ResponseWriter, r *http.Request) {
 		} else {
 			http.Error(w, err.Error(), http.StatusBadRequest)
 		}
-		return
+		return filter, false
 	}
 
-	var after *pagination.OrderKeyset
-	if c := r.URL.Query().Get("cursor"); c != "" {
-		k, err := pagination.DecodeOrderCursor(c)
-		if err != nil {
-			http.Error(w, "Invalid cursor", http.StatusBadRequest)
-			return
+	if err := v.ValidateOrderListStatusFilter(statusFilter); err != nil {
+		var he *appErrors.HTTPError
+		if errors.As(err, &he) {
+			http.Error(w, he.Error(), he.StatusCode)
+		} else {
+			http.Error(w, err.Error(), http.StatusBadRequest)
 		}
-		after = &k
+		return filter, false
+	}
+	if statusFilter != "" {
+		filter.Status = &statusFilter
 	}
 
-	var filterUserID *uint64
 	if s := r.URL.Query().Get("id_user"); s != "" {
 		uid, err := strconv.ParseUint(s, 10, 64)
 		if err != nil || uid == 0 {
 			http.Error(w, "Invalid id_user", http.StatusBadRequest)
-			return
+			return filter, false
 		}
-		filterUserID = &uid
+		filter.UserID = &uid
 	}
 
-	var searchQueryPtr *string
 	if searchQuery != "" {
-		searchQueryPtr = &searchQuery
+		filter.Search = &searchQuery
 	}
 
-	page, err := h.Repo.ListOrdersWithFiltersPage(ctx, tenantID, ignoreStatus, statusFilterPtr, limit, after, filterUserID, searchQueryPtr)
+	q := r.URL.Query()
+	createdFrom, createdTo, err := v.ParseOptionalDateRange("created_from", q.Get("created_from"), "created_to", q.Get("created_to"))
+	if err != nil {
+		writeRepoError(w, err, err.Error())
+		return filter, false
+	}
+	filter.CreatedFrom = createdFrom
+	if createdTo != nil {
+		end := createdTo.AddDate(0, 0, 1)
+		filter.CreatedTo = &end
+	}
+	filter.DeliveryFrom, filter.DeliveryTo, err = v.ParseOptionalDateRange("delivery_from", q.Get("delivery_from"), "delivery_to", q.Get("delivery_to"))
 	if err != nil {
-		http.Error(w, "Error getting orders", http.StatusInternalServerError)
+		writeRepoError(w, err, err.Error())
+		return filter, false
+	}
+	filter.Paid, err = v.ParseOptionalBool("paid", q.Get("paid"))
+	if err != nil {
+		writeRepoError(w, err, err.Error())
+		return filter, false
+	}
+	filter.MinTotal, filter.MaxTotal, err = v.ParseOptionalTotalRange(q.Get("min_total"), q.Get("max_total"))
+	if err != nil {
+		writeRepoError(w, err, err.Error())
+		return filter, false
+	}
+	filter.FulfillmentType, err = v.ParseOptionalFulfillmentType(q.Get("fulfillment_type"))
+	if err != nil {
+		writeRepoError(w, err, err.Error())
+		return filter, false
+	}
+	filter.Sort = ordersRepository.OrderListSort(q.Get("sort"))
+	if !ordersRepository.IsValidOrderListSort(filter.Sort) {
+		http.Error(w, "sort must be one of created_on, delivery_date", http.StatusBadRequest)
+		return filter, false
+	}
+	return filter, true
+}
+
+// ExportOrders downloads the orders matching the list filters as a spreadsheet
+// (GET /auth/orders/export?format=csv|xlsx&rows=orders|items). Rows are streamed page by page.
+func (h *OrderHandler) ExportOrders(w http.ResponseWriter, r *http.Request) {
+	format := orderService.ExportFormat(strings.ToLower(r.URL.Query().Get("format")))
+	if format == "" {
+		format = orderService.ExportFormatCSV
+	}
+	if format != orderService.ExportFormatCSV && format != orderService.ExportFormatXLSX {
+		http.Error(w, "format must be one of csv, xlsx", http.StatusBadRequest)
+		return
+	}
+	rows := orderService.ExportRows(strings.ToLower(r.URL.Query().Get("rows")))
+	if rows == "" {
+		rows = orderService.ExportRowsOrders
+	}
+	if rows != orderService.ExportRowsOrders && rows != orderService.ExportRowsItems {
+		http.Error(w, "rows must be one of orders, items", http.StatusBadRequest)
 		return
 	}
 
-	w.Header().Set("Content-Type", "application/json")
-	json.NewEncoder(w).Encode(ordersListResponse{Items: page.Items, NextCursor: page.NextCursor})
+	filter, ok := parseOrderListFilter(w, r)
+	if !ok {
+		return
+	}
+
+	tenantID, ok := requireTenantID(w, r)
+	if !ok {
+		return
+	}
+
+	filename := fmt.Sprintf("orders-%s.%s", todayUTC().Format(time.DateOnly), format)
+	contentType := "text/csv; charset=utf-8"
+	if format == orderService.ExportFormatXLSX {
+		contentType = xlsx.ContentType
+	}
+	w.Header().Set("Content-Type", contentType)
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", filename))
+
+	out := &exportResponseWriter{ResponseWriter: w}
+	err := orderService.NewOrderExporter(h.Repo).Export(r.Context(), out, tenantID, filter, format, rows)
+	if err == nil {
+		return
+	}
+	if !out.wrote {
+		w.Header().Del("Content-Disposition")
+		http.Error(w, "Error exporting orders", http.StatusInternalServerError)
+		return
+	}
+	// The status is already sent; the client gets a truncated file.
+	logger.Err(err).Uint64("tenant_id", tenantID).Msg("Order export aborted")
+}
+
+// exportResponseWriter records whether the body has started, so errors before the first byte can
+// still be answered with a status code.
+type exportResponseWriter struct {
+	http.ResponseWriter
+	wrote bool
+}
+
+func (e *exportResponseWriter) Write(p []byte) (int, error) {
+	e.wrote = true
+	return e.ResponseWriter.Write(p)
+}
+
+func (e *exportResponseWriter) Flush() {
+	if f, ok := e.ResponseWriter.(http.Flusher); ok {
+		f.Flush()
+	}
 }
 
 // GetOrderByID retrieves a product by its ID
@@ -151,6 +300,146 @@ func (h *OrderHandler) GetOrderByID(w http.ResponseWriter, r *http.Request) {
 	json.NewEncoder(w).Encode(order)
 }
 
+// GetOrderHistory returns the audit trail of an order (GET /auth/orders/{id}/history), oldest entry first,
+// with the acting user's name and the fields that changed versus the previous entry.
+func (h *OrderHandler) GetOrderHistory(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	idOrder, err := strconv.ParseUint(vars["id"], 10, 64)
+	if err != nil {
+		http.Error(w, "Invalid order ID", http.StatusBadRequest)
+		return
+	}
+
+	ctx := r.Context()
+	tenantID, err := middleware.GetTenantIDFromContext(ctx)
+	if err != nil {
+		http.Error(w, "tenant context required", http.StatusBadRequest)
+		return
+	}
+
+	historyReader := orderService.NewHistoryReader(h.Repo)
+	timeline, err := historyReader.GetOrderTimeline(ctx, tenantID, idOrder)
+	if err != nil {
+		var httpErr *appErrors.HTTPError
+		if errors.As(err, &httpErr) {
+			http.Error(w, httpErr.Error(), httpErr.StatusCode)
+			return
+		}
+		logger.Err(err).Uint64("tenant_id", tenantID).Uint64("order_id", idOrder).Msg("Error getting order history")
+		http.Error(w, "Error getting order history", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(timeline)
+}
+
+// GetOrderTransitions returns the statuses the order can move to next (GET /auth/orders/{id}/transitions),
+// so clients only offer actions that PATCH /auth/orders/{id} will accept.
+func (h *OrderHandler) GetOrderTransitions(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	idOrder, err := strconv.ParseUint(vars["id"], 10, 64)
+	if err != nil {
+		http.Error(w, "Invalid order ID", http.StatusBadRequest)
+		return
+	}
+
+	ctx := r.Context()
+	tenantID, err := middleware.GetTenantIDFromContext(ctx)
+	if err != nil {
+		http.Error(w, "tenant context required", http.StatusBadRequest)
+		return
+	}
+
+	order, err := h.Repo.GetOrderByID(ctx, tenantID, idOrder)
+	if err != nil {
+		var httpErr *appErrors.HTTPError
+		if errors.As(err, &httpErr) {
+			http.Error(w, httpErr.Error(), httpErr.StatusCode)
+			return
+		}
+		http.Error(w, "Error getting order", http.StatusInternalServerError)
+		return
+	}
+	machine, err := orderService.TenantStatusMachine(ctx, h.Repo, tenantID)
+	if err != nil {
+		writeRepoError(w, err, "Error getting order status transitions")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(oModel.OrderTransitions{
+		IDOrder:            order.ID,
+		Status:             order.Status,
+		AllowedTransitions: machine.AllowedTransitions(order.Status),
+	})
+}
+
+// GetStatusTransitions returns the tenant's order lifecycle (GET /auth/order-status-transitions):
+// its own transitions, or the default ones with customized false.
+func (h *OrderHandler) GetStatusTransitions(w http.ResponseWriter, r *http.Request) {
+	tenantID, ok := requireTenantID(w, r)
+	if !ok {
+		return
+	}
+
+	transitions, err := h.Repo.GetStatusTransitions(r.Context(), tenantID)
+	if err != nil {
+		writeRepoError(w, err, "Failed to get order status transitions")
+		return
+	}
+	response := oModel.StatusTransitionsResponse{Transitions: transitions, Customized: len(transitions) > 0}
+	if !response.Customized {
+		response.Transitions = orderService.DefaultStatusMachine.Transitions()
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(response)
+}
+
+// UpdateStatusTransitions replaces the tenant's order lifecycle (PUT /auth/order-status-transitions).
+// Status changes through PATCH /auth/orders/{id} and customer cancels are checked against it.
+func (h *OrderHandler) UpdateStatusTransitions(w http.ResponseWriter, r *http.Request) {
+	var req oModel.StatusTransitionsRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+	transitions, err := v.ValidateStatusTransitions(req.Transitions)
+	if err != nil {
+		writeRepoError(w, err, err.Error())
+		return
+	}
+	tenantID, ok := requireTenantID(w, r)
+	if !ok {
+		return
+	}
+
+	if err := h.Repo.ReplaceStatusTransitions(r.Context(), tenantID, transitions); err != nil {
+		writeRepoError(w, err, "Failed to update order status transitions")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(oModel.StatusTransitionsResponse{Transitions: transitions, Customized: true})
+}
+
+// ResetStatusTransitions puts the tenant back on the default order lifecycle
+// (DELETE /auth/order-status-transitions).
+func (h *OrderHandler) ResetStatusTransitions(w http.ResponseWriter, r *http.Request) {
+	tenantID, ok := requireTenantID(w, r)
+	if !ok {
+		return
+	}
+
+	if err := h.Repo.DeleteStatusTransitions(r.Context(), tenantID); err != nil {
+		writeRepoError(w, err, "Failed to reset order status transitions")
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
 // Create order creates a costumer order
 func (h *OrderHandler) CreateOrder(w http.ResponseWriter, r *http.Request) {
 	logger.Debug().Msg("Creating order")
@@ -163,6 +452,12 @@ func (h *OrderHandler) CreateOrder(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	idempotencyKey := strings.TrimSpace(r.Header.Get(headerIdempotencyKey))
+	if len(idempotencyKey) > maxIdempotencyKeyLength {
+		http.Error(w, fmt.Sprintf("%s must be at most %d characters", headerIdempotencyKey, maxIdempotencyKeyLength), http.StatusBadRequest)
+		return
+	}
+
 	// Validate payload fields
 	if err := v.ValidateCreateOrderPayload(payload); err != nil {
 		http.Error(w, err.Error(), http.StatusBadRequest)
@@ -186,32 +481,378 @@ func (h *OrderHandler) CreateOrder(w http.ResponseWriter, r *http.Request) {
 		http.Error(w, "tenant context required", http.StatusBadRequest)
 		return
 	}
+	if !h.allowCustomerOrder(w, r, payload) {
+		return
+	}
+	result, err := h.newCreator().CreateOrderWithIdempotencyKey(ctx, tenantID, idempotencyKey, payload, deliveryDate)
+	if err != nil {
+		writeCreateOrderError(w, err)
+		return
+	}
+
+	order := result.Order
+	payment := oModel.PaymentInstructions{AmountDue: order.BalanceDue}
+	if order.Status == oModel.StatusPending && !order.ExpiresAt.IsZero() {
+		payment.ExpiresAt = &order.ExpiresAt
+	}
+	// The checkout link is built on the tracking token, which a replayed request does not get back;
+	// the customer pays through the link of the first response.
+	if h.OnlinePaymentsEnabled && order.BalanceDue > 0 && result.TrackingToken != "" {
+		if slug := h.tenantSlug(r, tenantID); slug != "" {
+			payment.CheckoutURL = fmt.Sprintf("/t/%s/orders/track/%s/checkout", slug, url.PathEscape(result.TrackingToken))
+		}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if result.Replayed {
+		w.Header().Set(headerIdempotentReplayed, "true")
+	}
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(oModel.CreateOrderResponse{
+		Message:       "Order created successfully",
+		IDOrder:       order.ID,
+		TrackingToken: result.TrackingToken,
+		Order:         order,
+		Payment:       payment,
+	})
+}
+
+// CreateStaffOrder is the order entry of staff (POST /auth/orders): phone, WhatsApp and counter
+// orders that may have no customer email, start in a later status, be paid on the spot and never
+// expire. Stock, capacity and history go through the same Creator path as storefront orders.
+func (h *OrderHandler) CreateStaffOrder(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var payload oModel.StaffCreateOrderPayload
+	if err := json.NewDecoder(r.Body).Decode(&payload); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	idempotencyKey := strings.TrimSpace(r.Header.Get(headerIdempotencyKey))
+	if len(idempotencyKey) > maxIdempotencyKeyLength {
+		http.Error(w, fmt.Sprintf("%s must be at most %d characters", headerIdempotencyKey, maxIdempotencyKeyLength), http.StatusBadRequest)
+		return
+	}
+
+	if payload.PaymentReference != nil {
+		trimmed := strings.TrimSpace(*payload.PaymentReference)
+		payload.PaymentReference = &trimmed
+		if trimmed == "" {
+			payload.PaymentReference = nil
+		}
+	}
+	if err := v.ValidateStaffCreateOrderPayload(payload); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	// Counter sales are for today; unlike storefront orders, today is accepted.
+	now := time.Now().UTC()
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+	deliveryDate := today
+	if strings.TrimSpace(payload.DeliveryDate) != "" {
+		parsed, err := time.Parse("2006-01-02", payload.DeliveryDate)
+		if err != nil {
+			http.Error(w, "'delivery_date' must be in YYYY-MM-DD format", http.StatusBadRequest)
+			return
+		}
+		if parsed.Before(today) {
+			http.Error(w, "'delivery_date' can't be before present date", http.StatusBadRequest)
+			return
+		}
+		deliveryDate = parsed
+	}
+	payload.DeliveryDate = deliveryDate.Format("2006-01-02")
+
+	tenantID, ok := requireTenantID(w, r)
+	if !ok {
+		return
+	}
+	userID, err := middleware.GetUserIDFromContext(ctx)
+	if err != nil {
+		http.Error(w, "Unauthorized: invalid token", http.StatusUnauthorized)
+		return
+	}
+
+	staff := &orderService.StaffOrderOptions{
+		UserID:           userID,
+		Status:           payload.Status,
+		PaymentReference: payload.PaymentReference,
+	}
+	if payload.Paid {
+		staff.PaidWith = payload.PaymentMethod
+		if staff.PaidWith == "" {
+			staff.PaidWith = oModel.PaymentMethodCash
+		}
+	}
+	result, err := h.newCreator().CreateOrderWithOptions(ctx, tenantID, payload.CreateOrderPayload, deliveryDate, orderService.CreateOrderOptions{
+		IdempotencyKey: idempotencyKey,
+		SalesChannel:   payload.SalesChannel,
+		Staff:          staff,
+	})
+	if err != nil {
+		writeCreateOrderError(w, err)
+		return
+	}
+
+	order := result.Order
+	w.Header().Set("Content-Type", "application/json")
+	if result.Replayed {
+		w.Header().Set(headerIdempotentReplayed, "true")
+	}
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(oModel.CreateOrderResponse{
+		Message:       "Order created successfully",
+		IDOrder:       order.ID,
+		TrackingToken: result.TrackingToken,
+		Order:         order,
+		Payment:       oModel.PaymentInstructions{AmountDue: order.BalanceDue},
+	})
+}
+
+// newCreator wires the order Creator with the handler's optional repositories.
+func (h *OrderHandler) newCreator() *orderService.Creator {
 	var tenantCfgRepo orderService.TenantConfigRepository = nil
 	if h.TenantRepo != nil {
 		tenantCfgRepo = h.TenantRepo
 	}
 	orderCreator := orderService.NewCreator(h.Repo, h.UserRepo, h.ProductRepo, tenantCfgRepo)
-	err = orderCreator.CreateOrder(ctx, tenantID, payload, deliveryDate)
+	orderCreator.Events = h.Events
+	orderCreator.Notifications = h.Notifications
+	orderCreator.Capacity = h.Capacity
+	orderCreator.TrackingTokens = h.TrackingTokens
+	orderCreator.IdempotencyKeys = h.Repo
+	orderCreator.PaymentLedger = h.Repo
+	if h.Promotions != nil {
+		orderCreator.Promotions = h.Promotions
+	}
+	if h.Pricing != nil {
+		orderCreator.Pricing = h.Pricing
+	}
+	if h.PickupLocations != nil {
+		orderCreator.PickupLocations = h.PickupLocations
+	}
+	if h.Protection != nil {
+		orderCreator.Protection = h.Protection
+	}
+	return orderCreator
+}
+
+// orderErrorBody is the JSON error of the order abuse limits, so clients can tell them apart.
+type orderErrorBody struct {
+	Error   string `json:"error"`
+	Message string `json:"message"`
+}
+
+func writeOrderJSONError(w http.ResponseWriter, status int, code, message string) {
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(orderErrorBody{Error: code, Message: message})
+}
+
+// allowCustomerOrder applies the per-email and per-phone order rate limits and answers 429 when
+// one is exceeded. Emails compare case-insensitively and phones by their digits.
+func (h *OrderHandler) allowCustomerOrder(w http.ResponseWriter, r *http.Request, payload oModel.CreateOrderPayload) bool {
+	limits := []struct {
+		limit *middleware.KeyedRateLimit
+		key   string
+		msg   string
+	}{
+		{h.EmailRateLimit, opModel.NormalizeEmail(payload.Email), "Too many orders for this email, try again later"},
+		{h.PhoneRateLimit, opModel.NormalizePhone(payload.Phone), "Too many orders for this phone, try again later"},
+	}
+	for _, l := range limits {
+		if allowed, retryAfter := l.limit.Allow(r, l.key); !allowed {
+			if retryAfter > 0 {
+				w.Header().Set("Retry-After", strconv.Itoa(int(math.Ceil(retryAfter.Seconds()))))
+			}
+			writeOrderJSONError(w, http.StatusTooManyRequests, "too_many_requests", l.msg)
+			return false
+		}
+	}
+	return true
+}
+
+// orderProtectionErrorCodes are the error codes of the order abuse limits.
+var orderProtectionErrorCodes = []struct {
+	err  error
+	code string
+}{
+	{appErrors.ErrOrderCustomerBlocked, "customer_blocked"},
+	{appErrors.ErrOrderLineQuantityExceeded, "quantity_limit_exceeded"},
+	{appErrors.ErrTooManyPendingOrders, "too_many_pending_orders"},
+}
+
+// writeCreateOrderError maps order creation failures to HTTP statuses.
+func writeCreateOrderError(w http.ResponseWriter, err error) {
+	var httpErr *appErrors.HTTPError
+	for _, pe := range orderProtectionErrorCodes {
+		if errors.Is(err, pe.err) && errors.As(pe.err, &httpErr) {
+			writeOrderJSONError(w, httpErr.StatusCode, pe.code, err.Error())
+			return
+		}
+	}
+	switch {
+	case errors.Is(err, appErrors.ErrProductNotFound):
+		http.Error(w, err.Error(), http.StatusNotFound)
+	case errors.Is(err, appErrors.ErrProductNotPurchasable):
+		http.Error(w, err.Error(), http.StatusConflict)
+	case errors.Is(err, appErrors.ErrNotEnoughProductStock),
+		strings.Contains(err.Error(), "not enough product stock"):
+		http.Error(w, err.Error(), http.StatusConflict)
+	case errors.As(err, &httpErr):
+		http.Error(w, httpErr.Error(), httpErr.StatusCode)
+	default:
+		http.Error(w, fmt.Sprintf("Error creating the order: '%v'", err), http.StatusInternalServerError)
+	}
+}
+
+// tenantSlug returns the slug from the /t/{tenant_slug} path, falling back to the tenant repository.
+func (h *OrderHandler) tenantSlug(r *http.Request, tenantID uint64) string {
+	if slug := mux.Vars(r)["tenant_slug"]; slug != "" {
+		return slug
+	}
+	if h.TenantRepo == nil {
+		return ""
+	}
+	slug, err := h.TenantRepo.GetSlugByTenantID(r.Context(), tenantID)
+	if err != nil {
+		logger.Warn().Err(err).Uint64("tenant_id", tenantID).Msg("Failed to resolve tenant slug for checkout URL")
+		return ""
+	}
+	return slug
+}
+
+// TrackOrder returns the public view of an order from its tracking token (GET /t/{tenant_slug}/orders/track/{token}).
+func (h *OrderHandler) TrackOrder(w http.ResponseWriter, r *http.Request) {
+	tracker, tenantID, ok := h.tracker(w, r)
+	if !ok {
+		return
+	}
+
+	tracking, err := tracker.Track(r.Context(), tenantID, mux.Vars(r)["token"])
+	if err != nil {
+		writeRepoError(w, err, "Error getting order")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(tracking)
+}
+
+// CancelTrackedOrder lets the customer cancel a pending order from its tracking link
+// (POST /t/{tenant_slug}/orders/track/{token}/cancel, optional body {"reason": "..."}).
+func (h *OrderHandler) CancelTrackedOrder(w http.ResponseWriter, r *http.Request) {
+	var payload oModel.CancelTrackedOrderPayload
+	if r.ContentLength != 0 {
+		if err := json.NewDecoder(r.Body).Decode(&payload); err != nil {
+			http.Error(w, "Invalid request body", http.StatusBadRequest)
+			return
+		}
+	}
+
+	tracker, tenantID, ok := h.tracker(w, r)
+	if !ok {
+		return
+	}
+
+	tracking, err := tracker.Cancel(r.Context(), tenantID, mux.Vars(r)["token"], payload.Reason)
+	if err != nil {
+		logger.Warn().Err(err).Uint64("tenant_id", tenantID).Msg("Customer order cancel failed")
+		writeRepoError(w, err, "Error cancelling order")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(tracking)
+}
+
+func (h *OrderHandler) tracker(w http.ResponseWriter, r *http.Request) (*orderService.Tracker, uint64, bool) {
+	if h.TrackingTokens == nil {
+		http.Error(w, "Order tracking is not available", http.StatusNotFound)
+		return nil, 0, false
+	}
+	tenantID, ok := requireTenantID(w, r)
+	if !ok {
+		return nil, 0, false
+	}
+	machine, err := orderService.TenantStatusMachine(r.Context(), h.Repo, tenantID)
+	if err != nil {
+		writeRepoError(w, err, "Error getting order status transitions")
+		return nil, 0, false
+	}
+	statusUpdater := orderService.NewStatusUpdaterWithStock(h.Repo, h.ProductRepo)
+	statusUpdater.Machine = machine
+	statusUpdater.Events = h.Events
+	statusUpdater.Notifications = h.Notifications
+	return orderService.NewTracker(h.Repo, h.TrackingTokens, statusUpdater), tenantID, true
+}
+
+// UpdateOrderItems replaces the line items of an order (PUT /auth/orders/{id}/items).
+// Stock is reserved/reverted by the quantity difference and the total is recomputed
+// from current product prices. Only allowed while the order is pending or preparing.
+func (h *OrderHandler) UpdateOrderItems(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	idOrder, err := strconv.ParseUint(vars["id"], 10, 64)
+	if err != nil {
+		http.Error(w, "Invalid order ID", http.StatusBadRequest)
+		return
+	}
+
+	var payload oModel.UpdateOrderItemsPayload
+	if err := json.NewDecoder(r.Body).Decode(&payload); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	if err := v.ValidateOrderItemsInput(payload.Items); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	ctx := r.Context()
+	tenantID, err := middleware.GetTenantIDFromContext(ctx)
 	if err != nil {
+		http.Error(w, "tenant context required", http.StatusBadRequest)
+		return
+	}
+
+	userID, err := middleware.GetUserIDFromContext(ctx)
+	if err != nil {
+		http.Error(w, "Unauthorized: invalid token", http.StatusUnauthorized)
+		return
+	}
+
+	itemsUpdater := orderService.NewItemsUpdater(h.Repo, h.ProductRepo)
+	itemsUpdater.Events = h.Events
+	itemsUpdater.Capacity = h.Capacity
+	if h.Promotions != nil {
+		itemsUpdater.Promotions = h.Promotions
+	}
+	if h.Pricing != nil {
+		itemsUpdater.Pricing = h.Pricing
+	}
+	order, err := itemsUpdater.UpdateOrderItems(ctx, tenantID, idOrder, payload.Items, userID)
+	if err != nil {
+		var httpErr *appErrors.HTTPError
 		switch {
 		case errors.Is(err, appErrors.ErrProductNotFound):
 			http.Error(w, err.Error(), http.StatusNotFound)
-		case errors.Is(err, appErrors.ErrProductNotPurchasable):
-			http.Error(w, err.Error(), http.StatusConflict)
-		case errors.Is(err, appErrors.ErrNotEnoughProductStock),
-			strings.Contains(err.Error(), "not enough product stock"):
+		case errors.Is(err, appErrors.ErrProductNotPurchasable),
+			errors.Is(err, appErrors.ErrNotEnoughProductStock):
 			http.Error(w, err.Error(), http.StatusConflict)
+		case errors.As(err, &httpErr):
+			http.Error(w, httpErr.Error(), httpErr.StatusCode)
 		default:
-			http.Error(w, fmt.Sprintf("Error creating the order: '%v'", err), http.StatusInternalServerError)
+			logger.Err(err).Uint64("order_id", idOrder).Msg("Error updating order items")
+			http.Error(w, "Error updating order items", http.StatusInternalServerError)
 		}
 		return
 	}
 
 	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(http.StatusOK)
-	json.NewEncoder(w).Encode(map[string]string{
-		"message": "Order created successfully",
-	})
+	json.NewEncoder(w).Encode(order)
 }
 
 // UpdateOrderHistoryTable updates the order history table
@@ -238,9 +879,11 @@ func (h *OrderHandler) UpdateOrderHistoryTable(
 			Time:  order.DeliveryDate,
 			Valid: !order.DeliveryDate.IsZero(),
 		},
-		Paid:       order.Paid,
-		ModifiedBy: idUser,
-		Action:     action,
+		Paid:           order.Paid,
+		ModifiedBy:     idUser,
+		Action:         action,
+		DiscountAmount: order.DiscountAmount,
+		PromotionCode:  order.PromotionCode,
 	}
 
 	err := h.Repo.CreateOrderHistory(ctx, orderHistory)
@@ -310,24 +953,23 @@ func (h *OrderHandler) UpdateOrder(w http.ResponseWriter, r *http.Request) {
 	// leave paid updated when status validation/update fails.
 	statusUpdated := false
 	if payload.Status != nil {
-		validStatuses := []oModel.OrderStatus{
-			oModel.StatusPreparing,
-			oModel.StatusReady,
-			oModel.StatusDelivered,
-			oModel.StatusCancelled,
-			oModel.StatusDeleted,
+		machine, err := orderService.TenantStatusMachine(ctx, h.Repo, tenantID)
+		if err != nil {
+			http.Error(w, "Error getting order status transitions", http.StatusInternalServerError)
+			return
 		}
-
-		isValidStatus := false
-		for _, validStatus := range validStatuses {
-			if *payload.Status == validStatus {
-				isValidStatus = true
-				break
-			}
+		if !machine.IsTarget(*payload.Status) {
+			http.Error(w, "Invalid status value", http.StatusBadRequest)
+			return
 		}
 
-		if !isValidStatus {
-			http.Error(w, "Invalid status value", http.StatusBadRequest)
+		if err := machine.Validate(currentOrder.Status, *payload.Status); err != nil {
+			var httpErr *appErrors.HTTPError
+			if errors.As(err, &httpErr) {
+				http.Error(w, httpErr.Error(), httpErr.StatusCode)
+				return
+			}
+			http.Error(w, err.Error(), http.StatusBadRequest)
 			return
 		}
 
@@ -341,6 +983,9 @@ func (h *OrderHandler) UpdateOrder(w http.ResponseWriter, r *http.Request) {
 		isAdmin := middleware.IsAdminRole(userRole)
 
 		statusUpdater := orderService.NewStatusUpdaterWithStock(h.Repo, h.ProductRepo)
+		statusUpdater.Machine = machine
+		statusUpdater.Events = h.Events
+		statusUpdater.Notifications = h.Notifications
 
 		// Status updater applies paid atomically (same TX) when payload.Paid is set,
 		// and persists history with the final paid flag.
@@ -380,6 +1025,8 @@ func (h *OrderHandler) UpdateOrder(w http.ResponseWriter, r *http.Request) {
 			DeliveryDirection: currentOrder.DeliveryDirection,
 			DeliveryDate:      currentOrder.DeliveryDate,
 			Paid:              currentOrder.Paid,
+			DiscountAmount:    currentOrder.DiscountAmount,
+			PromotionCode:     currentOrder.PromotionCode,
 		}
 		if payload.Paid != nil {
 			orderModel.Paid = *payload.Paid
@@ -393,9 +1040,117 @@ func (h *OrderHandler) UpdateOrder(w http.ResponseWriter, r *http.Request) {
 		}
 	}
 
+	response := map[string]any{
+		"message": "Order updated successfully",
+	}
+	// Cancelling an order that already took money leaves a refund to settle via POST /orders/{id}/refunds.
+	if statusUpdated && oModel.IsClosedStatus(*payload.Status) {
+		updated, err := h.Repo.GetOrderByID(ctx, tenantID, idOrder)
+		if err != nil {
+			logger.Warn().Err(err).Uint64("order_id", idOrder).Msg("Failed to load order balance after status update")
+		} else if updated.RefundDue > 0 {
+			logger.Warn().
+				Uint64("tenant_id", tenantID).
+				Uint64("order_id", idOrder).
+				Stringer("refund_due", updated.RefundDue).
+				Msg("Closed order has payments to refund")
+			response["refund_due"] = updated.RefundDue
+		}
+	}
+
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(http.StatusOK)
-	json.NewEncoder(w).Encode(map[string]string{
-		"message": "Order updated successfully",
+	json.NewEncoder(w).Encode(response)
+}
+
+// GetOrderPayments returns the payments ledger of an order with its derived balance (GET /auth/orders/{id}/payments).
+func (h *OrderHandler) GetOrderPayments(w http.ResponseWriter, r *http.Request) {
+	idOrder, err := strconv.ParseUint(mux.Vars(r)["id"], 10, 64)
+	if err != nil {
+		http.Error(w, "Invalid order ID", http.StatusBadRequest)
+		return
+	}
+	tenantID, ok := requireTenantID(w, r)
+	if !ok {
+		return
+	}
+
+	ctx := r.Context()
+	order, err := h.Repo.GetOrderByID(ctx, tenantID, idOrder)
+	if err != nil {
+		writeRepoError(w, err, "Error getting order")
+		return
+	}
+	payments, err := h.Repo.ListOrderPayments(ctx, tenantID, idOrder)
+	if err != nil {
+		logger.Err(err).Uint64("order_id", idOrder).Msg("Error listing order payments")
+		http.Error(w, "Error listing order payments", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(oModel.OrderPaymentsResponse{
+		Items:      payments,
+		AmountPaid: order.AmountPaid,
+		BalanceDue: order.BalanceDue,
+		RefundDue:  order.RefundDue,
 	})
 }
+
+// RecordOrderPayment records a deposit or balance payment taken by staff (POST /auth/orders/{id}/payments).
+func (h *OrderHandler) RecordOrderPayment(w http.ResponseWriter, r *http.Request) {
+	h.recordOrderPayment(w, r, false)
+}
+
+// RecordOrderRefund records money returned to the customer (POST /auth/orders/{id}/refunds).
+func (h *OrderHandler) RecordOrderRefund(w http.ResponseWriter, r *http.Request) {
+	h.recordOrderPayment(w, r, true)
+}
+
+func (h *OrderHandler) recordOrderPayment(w http.ResponseWriter, r *http.Request, isRefund bool) {
+	idOrder, err := strconv.ParseUint(mux.Vars(r)["id"], 10, 64)
+	if err != nil {
+		http.Error(w, "Invalid order ID", http.StatusBadRequest)
+		return
+	}
+
+	var payload oModel.RecordOrderPaymentPayload
+	if err := json.NewDecoder(r.Body).Decode(&payload); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+	if payload.Reference != nil {
+		trimmed := strings.TrimSpace(*payload.Reference)
+		payload.Reference = &trimmed
+		if trimmed == "" {
+			payload.Reference = nil
+		}
+	}
+	if err := v.ValidateRecordOrderPaymentPayload(payload, isRefund); err != nil {
+		writeRepoError(w, err, "Invalid payment")
+		return
+	}
+
+	ctx := r.Context()
+	tenantID, ok := requireTenantID(w, r)
+	if !ok {
+		return
+	}
+	userID, err := middleware.GetUserIDFromContext(ctx)
+	if err != nil {
+		http.Error(w, "Unauthorized: invalid token", http.StatusUnauthorized)
+		return
+	}
+
+	recorder := orderService.NewPaymentRecorder(h.Repo)
+	order, err := recorder.RecordPayment(ctx, tenantID, idOrder, payload, isRefund, userID)
+	if err != nil {
+		logger.Err(err).Uint64("order_id", idOrder).Bool("is_refund", isRefund).Msg("Error recording order payment")
+		writeRepoError(w, err, "Error recording order payment")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(order)
+}
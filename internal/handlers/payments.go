@@ -0,0 +1,69 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+
+	"github.com/gorilla/mux"
+	appErrors "github.com/radamesvaz/bakery-app/internal/errors"
+	"github.com/radamesvaz/bakery-app/internal/logger"
+	paymentsService "github.com/radamesvaz/bakery-app/internal/services/payments"
+)
+
+const maxPaymentWebhookBodyBytes = 1 << 20
+
+type PaymentHandler struct {
+	Service *paymentsService.Service
+}
+
+// CreateCheckout starts (or resumes) a hosted checkout for a customer order identified by its
+// tracking token (POST /t/{tenant_slug}/orders/track/{token}/checkout).
+func (h *PaymentHandler) CreateCheckout(w http.ResponseWriter, r *http.Request) {
+	tenantID, ok := requireTenantID(w, r)
+	if !ok {
+		return
+	}
+
+	checkout, err := h.Service.StartCheckout(r.Context(), tenantID, mux.Vars(r)["token"])
+	if err != nil {
+		if errors.Is(err, appErrors.ErrPaymentProviderNotEnabled) {
+			http.Error(w, "Online payments are not available", http.StatusServiceUnavailable)
+			return
+		}
+		logger.Err(err).Uint64("tenant_id", tenantID).Msg("Error starting checkout")
+		writeRepoError(w, err, "Failed to start checkout")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(checkout)
+}
+
+// PaymentWebhook receives signed payment outcomes from the configured provider (POST /payments/webhook).
+func (h *PaymentHandler) PaymentWebhook(w http.ResponseWriter, r *http.Request) {
+	body, err := io.ReadAll(io.LimitReader(r.Body, maxPaymentWebhookBodyBytes))
+	if err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	if err := h.Service.HandleWebhook(r.Context(), r.Header, body); err != nil {
+		switch {
+		case errors.Is(err, appErrors.ErrInvalidPaymentSignature):
+			http.Error(w, "Invalid signature", http.StatusUnauthorized)
+		case errors.Is(err, appErrors.ErrPaymentProviderNotEnabled):
+			http.Error(w, "Online payments are not available", http.StatusServiceUnavailable)
+		case errors.Is(err, appErrors.ErrPaymentNotFound):
+			http.Error(w, err.Error(), http.StatusNotFound)
+		default:
+			logger.Err(err).Msg("Error handling payment webhook")
+			http.Error(w, "Failed to process payment webhook", http.StatusInternalServerError)
+		}
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+}
@@ -0,0 +1,133 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"github.com/gorilla/mux"
+	"github.com/radamesvaz/bakery-app/internal/handlers/validators"
+	pickupRepository "github.com/radamesvaz/bakery-app/internal/repository/pickup"
+	pickupModel "github.com/radamesvaz/bakery-app/model/pickup"
+)
+
+type PickupLocationHandler struct {
+	Repo *pickupRepository.Repository
+}
+
+func parsePickupLocationID(w http.ResponseWriter, r *http.Request) (uint64, bool) {
+	id, err := strconv.ParseUint(mux.Vars(r)["id"], 10, 64)
+	if err != nil || id == 0 {
+		http.Error(w, "Invalid pickup location ID", http.StatusBadRequest)
+		return 0, false
+	}
+	return id, true
+}
+
+func decodePickupLocationRequest(w http.ResponseWriter, r *http.Request) (pickupModel.Location, bool) {
+	var req pickupModel.LocationRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return pickupModel.Location{}, false
+	}
+	location, err := validators.ValidatePickupLocationRequest(req)
+	if err != nil {
+		writeRepoError(w, err, err.Error())
+		return pickupModel.Location{}, false
+	}
+	return location, true
+}
+
+// ListPickupLocations returns all the tenant's pickup locations (GET /auth/pickup-locations).
+func (h *PickupLocationHandler) ListPickupLocations(w http.ResponseWriter, r *http.Request) {
+	h.listPickupLocations(w, r, false)
+}
+
+// ListActivePickupLocations returns the locations a customer can pick at checkout
+// (GET /t/{tenant_slug}/pickup-locations).
+func (h *PickupLocationHandler) ListActivePickupLocations(w http.ResponseWriter, r *http.Request) {
+	h.listPickupLocations(w, r, true)
+}
+
+func (h *PickupLocationHandler) listPickupLocations(w http.ResponseWriter, r *http.Request, activeOnly bool) {
+	tenantID, ok := requireTenantID(w, r)
+	if !ok {
+		return
+	}
+
+	items, err := h.Repo.ListLocations(r.Context(), tenantID, activeOnly)
+	if err != nil {
+		writeRepoError(w, err, "Failed to get pickup locations")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(pickupModel.LocationsResponse{Items: items})
+}
+
+// CreatePickupLocation stores a pickup location (POST /auth/pickup-locations).
+func (h *PickupLocationHandler) CreatePickupLocation(w http.ResponseWriter, r *http.Request) {
+	in, ok := decodePickupLocationRequest(w, r)
+	if !ok {
+		return
+	}
+	tenantID, ok := requireTenantID(w, r)
+	if !ok {
+		return
+	}
+
+	location, err := h.Repo.CreateLocation(r.Context(), tenantID, in)
+	if err != nil {
+		writeRepoError(w, err, "Failed to create pickup location")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(location)
+}
+
+// UpdatePickupLocation replaces a pickup location (PUT /auth/pickup-locations/{id}).
+func (h *PickupLocationHandler) UpdatePickupLocation(w http.ResponseWriter, r *http.Request) {
+	id, ok := parsePickupLocationID(w, r)
+	if !ok {
+		return
+	}
+	in, ok := decodePickupLocationRequest(w, r)
+	if !ok {
+		return
+	}
+	tenantID, ok := requireTenantID(w, r)
+	if !ok {
+		return
+	}
+
+	location, err := h.Repo.UpdateLocation(r.Context(), tenantID, id, in)
+	if err != nil {
+		writeRepoError(w, err, "Failed to update pickup location")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(location)
+}
+
+// DeletePickupLocation removes a pickup location (DELETE /auth/pickup-locations/{id}). Orders
+// placed for it stay pickup orders without a location.
+func (h *PickupLocationHandler) DeletePickupLocation(w http.ResponseWriter, r *http.Request) {
+	id, ok := parsePickupLocationID(w, r)
+	if !ok {
+		return
+	}
+	tenantID, ok := requireTenantID(w, r)
+	if !ok {
+		return
+	}
+
+	if err := h.Repo.DeleteLocation(r.Context(), tenantID, id); err != nil {
+		writeRepoError(w, err, "Failed to delete pickup location")
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
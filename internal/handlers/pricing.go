@@ -0,0 +1,176 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"github.com/gorilla/mux"
+	"github.com/radamesvaz/bakery-app/internal/handlers/validators"
+	pricingRepository "github.com/radamesvaz/bakery-app/internal/repository/pricing"
+	pricingModel "github.com/radamesvaz/bakery-app/model/pricing"
+)
+
+type PricingHandler struct {
+	Repo *pricingRepository.Repository
+}
+
+func parseDeliveryZoneID(w http.ResponseWriter, r *http.Request) (uint64, bool) {
+	id, err := strconv.ParseUint(mux.Vars(r)["id"], 10, 64)
+	if err != nil || id == 0 {
+		http.Error(w, "Invalid delivery zone ID", http.StatusBadRequest)
+		return 0, false
+	}
+	return id, true
+}
+
+func decodeDeliveryZoneRequest(w http.ResponseWriter, r *http.Request) (pricingModel.DeliveryZone, bool) {
+	var req pricingModel.DeliveryZoneRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return pricingModel.DeliveryZone{}, false
+	}
+	zone, err := validators.ValidateDeliveryZoneRequest(req)
+	if err != nil {
+		writeRepoError(w, err, err.Error())
+		return pricingModel.DeliveryZone{}, false
+	}
+	return zone, true
+}
+
+// GetSettings returns the tenant's tax and default delivery fee (GET /auth/pricing).
+func (h *PricingHandler) GetSettings(w http.ResponseWriter, r *http.Request) {
+	tenantID, ok := requireTenantID(w, r)
+	if !ok {
+		return
+	}
+
+	settings, err := h.Repo.GetSettings(r.Context(), tenantID)
+	if err != nil {
+		writeRepoError(w, err, "Failed to get pricing settings")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(settings)
+}
+
+// UpdateSettings replaces the tenant's tax and default delivery fee (PUT /auth/pricing). Orders
+// already placed keep their totals.
+func (h *PricingHandler) UpdateSettings(w http.ResponseWriter, r *http.Request) {
+	var settings pricingModel.Settings
+	if err := json.NewDecoder(r.Body).Decode(&settings); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+	if err := validators.ValidatePricingSettings(settings); err != nil {
+		writeRepoError(w, err, err.Error())
+		return
+	}
+	tenantID, ok := requireTenantID(w, r)
+	if !ok {
+		return
+	}
+
+	if err := h.Repo.UpdateSettings(r.Context(), tenantID, settings); err != nil {
+		writeRepoError(w, err, "Failed to update pricing settings")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(settings)
+}
+
+// ListDeliveryZones returns all the tenant's delivery zones (GET /auth/delivery-zones).
+func (h *PricingHandler) ListDeliveryZones(w http.ResponseWriter, r *http.Request) {
+	h.listDeliveryZones(w, r, false)
+}
+
+// ListActiveDeliveryZones returns the zones a customer can pick at checkout
+// (GET /t/{tenant_slug}/delivery-zones).
+func (h *PricingHandler) ListActiveDeliveryZones(w http.ResponseWriter, r *http.Request) {
+	h.listDeliveryZones(w, r, true)
+}
+
+func (h *PricingHandler) listDeliveryZones(w http.ResponseWriter, r *http.Request, activeOnly bool) {
+	tenantID, ok := requireTenantID(w, r)
+	if !ok {
+		return
+	}
+
+	items, err := h.Repo.ListDeliveryZones(r.Context(), tenantID, activeOnly)
+	if err != nil {
+		writeRepoError(w, err, "Failed to get delivery zones")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(pricingModel.DeliveryZonesResponse{Items: items})
+}
+
+// CreateDeliveryZone stores a delivery zone (POST /auth/delivery-zones).
+func (h *PricingHandler) CreateDeliveryZone(w http.ResponseWriter, r *http.Request) {
+	in, ok := decodeDeliveryZoneRequest(w, r)
+	if !ok {
+		return
+	}
+	tenantID, ok := requireTenantID(w, r)
+	if !ok {
+		return
+	}
+
+	zone, err := h.Repo.CreateDeliveryZone(r.Context(), tenantID, in)
+	if err != nil {
+		writeRepoError(w, err, "Failed to create delivery zone")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(zone)
+}
+
+// UpdateDeliveryZone replaces a delivery zone (PUT /auth/delivery-zones/{id}).
+func (h *PricingHandler) UpdateDeliveryZone(w http.ResponseWriter, r *http.Request) {
+	id, ok := parseDeliveryZoneID(w, r)
+	if !ok {
+		return
+	}
+	in, ok := decodeDeliveryZoneRequest(w, r)
+	if !ok {
+		return
+	}
+	tenantID, ok := requireTenantID(w, r)
+	if !ok {
+		return
+	}
+
+	zone, err := h.Repo.UpdateDeliveryZone(r.Context(), tenantID, id, in)
+	if err != nil {
+		writeRepoError(w, err, "Failed to update delivery zone")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(zone)
+}
+
+// DeleteDeliveryZone removes a delivery zone (DELETE /auth/delivery-zones/{id}). Orders placed in
+// it keep their delivery fee.
+func (h *PricingHandler) DeleteDeliveryZone(w http.ResponseWriter, r *http.Request) {
+	id, ok := parseDeliveryZoneID(w, r)
+	if !ok {
+		return
+	}
+	tenantID, ok := requireTenantID(w, r)
+	if !ok {
+		return
+	}
+
+	if err := h.Repo.DeleteDeliveryZone(r.Context(), tenantID, id); err != nil {
+		writeRepoError(w, err, "Failed to delete delivery zone")
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
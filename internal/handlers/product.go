@@ -29,6 +29,11 @@ type productsListResponse struct {
 	NextCursor *string          `json:"next_cursor"`
 }
 
+type productHistoryListResponse struct {
+	Items      []pModel.ProductHistoryEntry `json:"items"`
+	NextCursor *string                      `json:"next_cursor"`
+}
+
 func writeRepoError(w http.ResponseWriter, err error, fallbackMsg string) {
 	var he *appErrors.HTTPError
 	if errors.As(err, &he) {
@@ -140,6 +145,58 @@ func (h *ProductHandler) getProductByID(w http.ResponseWriter, r *http.Request,
 	json.NewEncoder(w).Encode(product)
 }
 
+// GetProductHistory lists the history snapshots of a product, newest first, each with the
+// price/stock/status/image changes versus the previous snapshot (GET /auth/products/{id}/history).
+// Query: limit, cursor.
+func (h *ProductHandler) GetProductHistory(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	id, err := strconv.ParseUint(vars["id"], 10, 64)
+	if err != nil {
+		http.Error(w, "Invalid product ID", http.StatusBadRequest)
+		return
+	}
+
+	ctx := r.Context()
+	tenantID, ok := requireTenantID(w, r)
+	if !ok {
+		return
+	}
+
+	limit, err := validators.ParseListLimit(r.URL.Query().Get("limit"))
+	if err != nil {
+		writeRepoError(w, err, err.Error())
+		return
+	}
+
+	var afterID *uint64
+	if c := r.URL.Query().Get("cursor"); c != "" {
+		cursorID, err := pagination.DecodeIDCursor(c)
+		if err != nil {
+			http.Error(w, "Invalid cursor", http.StatusBadRequest)
+			return
+		}
+		afterID = &cursorID
+	}
+
+	if _, err := h.Repo.GetProductByID(ctx, tenantID, id, false); err != nil {
+		if errors.Is(err, appErrors.ErrProductNotFound) {
+			http.Error(w, "Product not found", http.StatusNotFound)
+			return
+		}
+		writeRepoError(w, err, "Failed to get product")
+		return
+	}
+
+	page, err := h.Repo.ListProductHistoryPage(ctx, tenantID, id, limit, afterID)
+	if err != nil {
+		http.Error(w, "Failed to get product history", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(productHistoryListResponse{Items: page.Items, NextCursor: page.NextCursor})
+}
+
 // CreateProduct - Create a product (JSON only)
 func (h *ProductHandler) CreateProduct(w http.ResponseWriter, r *http.Request) {
 	var req pModel.CreateProductRequest
@@ -102,7 +102,7 @@ func TestProductHandler_UpdateProduct_SoftDeleteCleansUpImages(t *testing.T) {
 
 	mock.ExpectExec(
 		regexp.QuoteMeta("UPDATE products SET name = $1, description = $2, price = $3, stock = $4, status = $5, track_inventory = $6, thumbnail_url = $7 WHERE tenant_id = $8 AND id_product = $9"),
-	).WithArgs("Cake", "desc", 10.5, uint64(3), "deleted", true, "/uploads/products/10/main.jpg", tenantID, productID).
+	).WithArgs("Cake", "desc", "10.50", uint64(3), "deleted", true, "/uploads/products/10/main.jpg", tenantID, productID).
 		WillReturnResult(sqlmock.NewResult(0, 1))
 
 	mock.ExpectExec(regexp.QuoteMeta("INSERT INTO products_history")).WithArgs(
@@ -110,7 +110,7 @@ func TestProductHandler_UpdateProduct_SoftDeleteCleansUpImages(t *testing.T) {
 		productID,
 		"Cake",
 		"desc",
-		10.5,
+		"10.50",
 		true,
 		uint64(3),
 		"deleted",
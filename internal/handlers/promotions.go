@@ -0,0 +1,149 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"github.com/gorilla/mux"
+	"github.com/radamesvaz/bakery-app/internal/handlers/validators"
+	promotionsRepository "github.com/radamesvaz/bakery-app/internal/repository/promotions"
+	promoModel "github.com/radamesvaz/bakery-app/model/promotions"
+)
+
+type PromotionHandler struct {
+	Repo *promotionsRepository.Repository
+}
+
+type promotionsListResponse struct {
+	Items []promoModel.Promotion `json:"items"`
+}
+
+func parsePromotionID(w http.ResponseWriter, r *http.Request) (uint64, bool) {
+	id, err := strconv.ParseUint(mux.Vars(r)["id"], 10, 64)
+	if err != nil || id == 0 {
+		http.Error(w, "Invalid promotion ID", http.StatusBadRequest)
+		return 0, false
+	}
+	return id, true
+}
+
+func decodePromotionRequest(w http.ResponseWriter, r *http.Request) (promoModel.Promotion, bool) {
+	var req promoModel.PromotionRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return promoModel.Promotion{}, false
+	}
+	p, err := validators.ValidatePromotionRequest(req)
+	if err != nil {
+		writeRepoError(w, err, err.Error())
+		return promoModel.Promotion{}, false
+	}
+	return p, true
+}
+
+// ListPromotions returns the tenant's discount codes with their current uses (GET /auth/promotions).
+func (h *PromotionHandler) ListPromotions(w http.ResponseWriter, r *http.Request) {
+	tenantID, ok := requireTenantID(w, r)
+	if !ok {
+		return
+	}
+
+	items, err := h.Repo.ListPromotions(r.Context(), tenantID)
+	if err != nil {
+		writeRepoError(w, err, "Failed to get promotions")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(promotionsListResponse{Items: items})
+}
+
+// GetPromotion returns one discount code (GET /auth/promotions/{id}).
+func (h *PromotionHandler) GetPromotion(w http.ResponseWriter, r *http.Request) {
+	id, ok := parsePromotionID(w, r)
+	if !ok {
+		return
+	}
+	tenantID, ok := requireTenantID(w, r)
+	if !ok {
+		return
+	}
+
+	p, err := h.Repo.GetPromotion(r.Context(), tenantID, id)
+	if err != nil {
+		writeRepoError(w, err, "Failed to get promotion")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(p)
+}
+
+// CreatePromotion stores a discount code (POST /auth/promotions).
+func (h *PromotionHandler) CreatePromotion(w http.ResponseWriter, r *http.Request) {
+	in, ok := decodePromotionRequest(w, r)
+	if !ok {
+		return
+	}
+	tenantID, ok := requireTenantID(w, r)
+	if !ok {
+		return
+	}
+
+	p, err := h.Repo.CreatePromotion(r.Context(), tenantID, in)
+	if err != nil {
+		writeRepoError(w, err, "Failed to create promotion")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(p)
+}
+
+// UpdatePromotion replaces a discount code (PUT /auth/promotions/{id}). Orders that already used
+// it keep their discount.
+func (h *PromotionHandler) UpdatePromotion(w http.ResponseWriter, r *http.Request) {
+	id, ok := parsePromotionID(w, r)
+	if !ok {
+		return
+	}
+	in, ok := decodePromotionRequest(w, r)
+	if !ok {
+		return
+	}
+	tenantID, ok := requireTenantID(w, r)
+	if !ok {
+		return
+	}
+
+	p, err := h.Repo.UpdatePromotion(r.Context(), tenantID, id, in)
+	if err != nil {
+		writeRepoError(w, err, "Failed to update promotion")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(p)
+}
+
+// DeletePromotion removes a discount code (DELETE /auth/promotions/{id}). To stop new redemptions
+// but keep the usage history, set active to false instead.
+func (h *PromotionHandler) DeletePromotion(w http.ResponseWriter, r *http.Request) {
+	id, ok := parsePromotionID(w, r)
+	if !ok {
+		return
+	}
+	tenantID, ok := requireTenantID(w, r)
+	if !ok {
+		return
+	}
+
+	if err := h.Repo.DeletePromotion(r.Context(), tenantID, id); err != nil {
+		writeRepoError(w, err, "Failed to delete promotion")
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -0,0 +1,64 @@
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/radamesvaz/bakery-app/internal/handlers/validators"
+	"github.com/radamesvaz/bakery-app/internal/logger"
+	ordersRepository "github.com/radamesvaz/bakery-app/internal/repository/orders"
+	orderService "github.com/radamesvaz/bakery-app/internal/services/orders"
+)
+
+type ReportHandler struct {
+	Repo *ordersRepository.OrderRepository
+}
+
+// GetProductionReport returns what to bake for a delivery date
+// (GET /auth/reports/production?date=YYYY-MM-DD&format=json|csv|text). date defaults to today (UTC).
+func (h *ReportHandler) GetProductionReport(w http.ResponseWriter, r *http.Request) {
+	date := todayUTC()
+	if raw := r.URL.Query().Get("date"); strings.TrimSpace(raw) != "" {
+		parsed, err := validators.ParseDeliveryDate("date", raw)
+		if err != nil {
+			writeRepoError(w, err, err.Error())
+			return
+		}
+		date = parsed
+	}
+	format := strings.ToLower(strings.TrimSpace(r.URL.Query().Get("format")))
+	if format != "" && format != "json" && format != "csv" && format != "text" {
+		http.Error(w, "format must be one of json, csv, text", http.StatusBadRequest)
+		return
+	}
+	tenantID, ok := requireTenantID(w, r)
+	if !ok {
+		return
+	}
+
+	report, err := orderService.NewProductionReporter(h.Repo).GetProductionReport(r.Context(), tenantID, date)
+	if err != nil {
+		writeRepoError(w, err, "Failed to get production report")
+		return
+	}
+
+	filename := fmt.Sprintf("production-%s", date.Format(time.DateOnly))
+	switch format {
+	case "csv":
+		w.Header().Set("Content-Type", "text/csv; charset=utf-8")
+		w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", filename+".csv"))
+		err = orderService.WriteProductionReportCSV(w, report)
+	case "text":
+		w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+		err = orderService.WriteProductionReportText(w, report)
+	default:
+		w.Header().Set("Content-Type", "application/json")
+		err = json.NewEncoder(w).Encode(report)
+	}
+	if err != nil {
+		logger.Err(err).Uint64("tenant_id", tenantID).Msg("Failed to write production report")
+	}
+}
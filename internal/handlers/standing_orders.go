@@ -0,0 +1,176 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"github.com/gorilla/mux"
+	"github.com/radamesvaz/bakery-app/internal/handlers/validators"
+	standingOrdersRepository "github.com/radamesvaz/bakery-app/internal/repository/standingorders"
+	soModel "github.com/radamesvaz/bakery-app/model/standingorders"
+)
+
+type StandingOrderHandler struct {
+	Repo *standingOrdersRepository.Repository
+}
+
+type standingOrdersListResponse struct {
+	Items []soModel.StandingOrder `json:"items"`
+}
+
+type standingOrderOccurrencesListResponse struct {
+	Items []soModel.Occurrence `json:"items"`
+}
+
+func parseStandingOrderID(w http.ResponseWriter, r *http.Request) (uint64, bool) {
+	id, err := strconv.ParseUint(mux.Vars(r)["id"], 10, 64)
+	if err != nil || id == 0 {
+		http.Error(w, "Invalid standing order ID", http.StatusBadRequest)
+		return 0, false
+	}
+	return id, true
+}
+
+func decodeStandingOrderRequest(w http.ResponseWriter, r *http.Request) (soModel.StandingOrderInput, bool) {
+	var req soModel.StandingOrderRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return soModel.StandingOrderInput{}, false
+	}
+	in, err := validators.ValidateStandingOrderRequest(req)
+	if err != nil {
+		writeRepoError(w, err, err.Error())
+		return soModel.StandingOrderInput{}, false
+	}
+	return in, true
+}
+
+// ListStandingOrders returns the tenant's standing orders (GET /auth/standing-orders).
+func (h *StandingOrderHandler) ListStandingOrders(w http.ResponseWriter, r *http.Request) {
+	tenantID, ok := requireTenantID(w, r)
+	if !ok {
+		return
+	}
+
+	items, err := h.Repo.ListStandingOrders(r.Context(), tenantID)
+	if err != nil {
+		writeRepoError(w, err, "Failed to get standing orders")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(standingOrdersListResponse{Items: items})
+}
+
+// GetStandingOrder returns one standing order (GET /auth/standing-orders/{id}).
+func (h *StandingOrderHandler) GetStandingOrder(w http.ResponseWriter, r *http.Request) {
+	id, ok := parseStandingOrderID(w, r)
+	if !ok {
+		return
+	}
+	tenantID, ok := requireTenantID(w, r)
+	if !ok {
+		return
+	}
+
+	so, err := h.Repo.GetStandingOrder(r.Context(), tenantID, id)
+	if err != nil {
+		writeRepoError(w, err, "Failed to get standing order")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(so)
+}
+
+// CreateStandingOrder stores a recurring order (POST /auth/standing-orders). The worker creates
+// its orders ahead of each delivery date.
+func (h *StandingOrderHandler) CreateStandingOrder(w http.ResponseWriter, r *http.Request) {
+	in, ok := decodeStandingOrderRequest(w, r)
+	if !ok {
+		return
+	}
+	tenantID, ok := requireTenantID(w, r)
+	if !ok {
+		return
+	}
+
+	so, err := h.Repo.CreateStandingOrder(r.Context(), tenantID, in)
+	if err != nil {
+		writeRepoError(w, err, "Failed to create standing order")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(so)
+}
+
+// UpdateStandingOrder replaces a standing order (PUT /auth/standing-orders/{id}). Orders already
+// created for it are not changed.
+func (h *StandingOrderHandler) UpdateStandingOrder(w http.ResponseWriter, r *http.Request) {
+	id, ok := parseStandingOrderID(w, r)
+	if !ok {
+		return
+	}
+	in, ok := decodeStandingOrderRequest(w, r)
+	if !ok {
+		return
+	}
+	tenantID, ok := requireTenantID(w, r)
+	if !ok {
+		return
+	}
+
+	so, err := h.Repo.UpdateStandingOrder(r.Context(), tenantID, id, in)
+	if err != nil {
+		writeRepoError(w, err, "Failed to update standing order")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(so)
+}
+
+// DeleteStandingOrder removes a standing order (DELETE /auth/standing-orders/{id}). Orders already
+// created for it are kept.
+func (h *StandingOrderHandler) DeleteStandingOrder(w http.ResponseWriter, r *http.Request) {
+	id, ok := parseStandingOrderID(w, r)
+	if !ok {
+		return
+	}
+	tenantID, ok := requireTenantID(w, r)
+	if !ok {
+		return
+	}
+
+	if err := h.Repo.DeleteStandingOrder(r.Context(), tenantID, id); err != nil {
+		writeRepoError(w, err, "Failed to delete standing order")
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// ListOccurrences returns what the worker did for each recent delivery date of a standing order:
+// the order it created, or why the date was skipped or failed (GET /auth/standing-orders/{id}/occurrences).
+func (h *StandingOrderHandler) ListOccurrences(w http.ResponseWriter, r *http.Request) {
+	id, ok := parseStandingOrderID(w, r)
+	if !ok {
+		return
+	}
+	tenantID, ok := requireTenantID(w, r)
+	if !ok {
+		return
+	}
+
+	items, err := h.Repo.ListOccurrences(r.Context(), tenantID, id)
+	if err != nil {
+		writeRepoError(w, err, "Failed to get standing order occurrences")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(standingOrderOccurrencesListResponse{Items: items})
+}
@@ -13,6 +13,7 @@ import (
 	"github.com/radamesvaz/bakery-app/internal/middleware"
 	tenantRepository "github.com/radamesvaz/bakery-app/internal/repository/tenant"
 	imagesService "github.com/radamesvaz/bakery-app/internal/services/images"
+	"github.com/radamesvaz/bakery-app/model/money"
 	uModel "github.com/radamesvaz/bakery-app/model/users"
 )
 
@@ -33,6 +34,10 @@ type updateTenantDisplayNameRequest struct {
 	TenantName string `json:"tenant_name"`
 }
 
+type updateTenantCurrencyRequest struct {
+	Currency string `json:"currency"`
+}
+
 // GetBranding returns logo + colors for the tenant resolved by TenantFromPathOrHeader (public, no auth).
 // Use GET /t/{tenant_slug}/branding (or X-Tenant-Slug). Response includes tenant_slug for clients.
 func (h *TenantHandler) GetBranding(w http.ResponseWriter, r *http.Request) {
@@ -115,6 +120,49 @@ func (h *TenantHandler) UpdateTenantDisplayName(w http.ResponseWriter, r *http.R
 	})
 }
 
+// UpdateTenantCurrency sets the currency new orders are placed in (admin only).
+// PATCH /auth/branding/currency — body: {"currency":"EUR"}. Orders already placed keep their currency.
+func (h *TenantHandler) UpdateTenantCurrency(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	tenantID, err := middleware.GetTenantIDFromContext(ctx)
+	if err != nil {
+		http.Error(w, "Failed to get tenant from context", http.StatusBadRequest)
+		return
+	}
+
+	roleID, err := middleware.GetUserRoleFromContext(ctx)
+	if err != nil || roleID != uint64(uModel.UserRoleAdmin) {
+		http.Error(w, "Forbidden", http.StatusForbidden)
+		return
+	}
+
+	var req updateTenantCurrencyRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	currency, ok := money.ParseCurrency(req.Currency)
+	if !ok {
+		http.Error(w, "Unsupported currency", http.StatusBadRequest)
+		return
+	}
+
+	if err := h.Repo.UpdateTenantCurrency(ctx, tenantID, currency); err != nil {
+		http.Error(w, "Failed to update tenant currency", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	_ = json.NewEncoder(w).Encode(map[string]interface{}{
+		"message":   "Tenant currency updated successfully",
+		"tenant_id": tenantID,
+		"currency":  currency,
+	})
+}
+
 // UploadTenantLogo accepts one image file (field `logo`) and sets or replaces the tenant logo (PATCH).
 // Requires auth; old local/Cloudinary file is best-effort deleted when replaced.
 func (h *TenantHandler) UploadTenantLogo(w http.ResponseWriter, r *http.Request) {
@@ -31,10 +31,10 @@ func TestTenantHandler_GetBranding_Success(t *testing.T) {
 
 	tenantID := uint64(1)
 	mock.ExpectQuery(
-		regexp.QuoteMeta("SELECT name, logo_url, primary_color, secondary_color, accent_color FROM tenants WHERE id = $1"),
+		regexp.QuoteMeta("SELECT name, logo_url, primary_color, secondary_color, accent_color, currency FROM tenants WHERE id = $1"),
 	).WithArgs(tenantID).WillReturnRows(
-		sqlmock.NewRows([]string{"name", "logo_url", "primary_color", "secondary_color", "accent_color"}).
-			AddRow("Café Demo", "https://example.com/logo.png", "#111827", "#374151", "#F59E0B"),
+		sqlmock.NewRows([]string{"name", "logo_url", "primary_color", "secondary_color", "accent_color", "currency"}).
+			AddRow("Café Demo", "https://example.com/logo.png", "#111827", "#374151", "#F59E0B", "EUR"),
 	)
 
 	req := httptest.NewRequest(http.MethodGet, "/t/default/branding", nil)
@@ -54,6 +54,7 @@ func TestTenantHandler_GetBranding_Success(t *testing.T) {
 	require.True(t, ok)
 	assert.Equal(t, "Café Demo", branding["tenant_name"])
 	assert.Equal(t, "https://example.com/logo.png", branding["logo_url"])
+	assert.Equal(t, "EUR", branding["currency"])
 	assert.NoError(t, mock.ExpectationsWereMet())
 }
 
@@ -99,10 +100,10 @@ func TestTenantHandler_UpdateBrandingColors_Success(t *testing.T) {
 		WillReturnResult(sqlmock.NewResult(0, 1))
 
 	mock.ExpectQuery(
-		regexp.QuoteMeta("SELECT name, logo_url, primary_color, secondary_color, accent_color FROM tenants WHERE id = $1"),
+		regexp.QuoteMeta("SELECT name, logo_url, primary_color, secondary_color, accent_color, currency FROM tenants WHERE id = $1"),
 	).WithArgs(tenantID).WillReturnRows(
-		sqlmock.NewRows([]string{"name", "logo_url", "primary_color", "secondary_color", "accent_color"}).
-			AddRow("Tenant Two", nil, "#111827", "#374151", "#F59E0B"),
+		sqlmock.NewRows([]string{"name", "logo_url", "primary_color", "secondary_color", "accent_color", "currency"}).
+			AddRow("Tenant Two", nil, "#111827", "#374151", "#F59E0B", "USD"),
 	)
 
 	req := httptest.NewRequest(http.MethodPatch, "/auth/branding/colors", strings.NewReader(payload))
@@ -223,6 +224,51 @@ func TestTenantHandler_UpdateTenantDisplayName_ClientForbidden(t *testing.T) {
 	require.Equal(t, http.StatusForbidden, rr.Code)
 }
 
+func TestTenantHandler_UpdateTenantCurrency_AdminSuccess(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	handler := &TenantHandler{
+		Repo: &tenantRepository.Repository{DB: db},
+	}
+
+	tenantID := uint64(2)
+	mock.ExpectExec(regexp.QuoteMeta(
+		`UPDATE tenants SET currency = $1, updated_on = NOW() WHERE id = $2`,
+	)).WithArgs("EUR", tenantID).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	req := httptest.NewRequest(http.MethodPatch, "/auth/branding/currency", strings.NewReader(`{"currency":" eur "}`))
+	req = req.WithContext(authTenantContext(t, tenantID, "slug-two", 1))
+	rr := httptest.NewRecorder()
+
+	handler.UpdateTenantCurrency(rr, req)
+
+	require.Equal(t, http.StatusOK, rr.Code)
+	assert.Contains(t, rr.Body.String(), `"currency":"EUR"`)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestTenantHandler_UpdateTenantCurrency_Unsupported(t *testing.T) {
+	db, _, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	handler := &TenantHandler{
+		Repo: &tenantRepository.Repository{DB: db},
+	}
+
+	req := httptest.NewRequest(http.MethodPatch, "/auth/branding/currency", strings.NewReader(`{"currency":"JPY"}`))
+	req = req.WithContext(authTenantContext(t, 2, "slug-two", 1))
+	rr := httptest.NewRecorder()
+
+	handler.UpdateTenantCurrency(rr, req)
+
+	require.Equal(t, http.StatusBadRequest, rr.Code)
+	assert.Contains(t, rr.Body.String(), "Unsupported currency")
+}
+
 func TestTenantHandler_UpdateBrandingColors_InvalidColor(t *testing.T) {
 	db, _, err := sqlmock.New()
 	require.NoError(t, err)
@@ -0,0 +1,81 @@
+package validators
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/radamesvaz/bakery-app/internal/errors"
+	capModel "github.com/radamesvaz/bakery-app/model/capacity"
+)
+
+// maxLeadTimeHours keeps the lead time within the availability window a storefront can show.
+const maxLeadTimeHours = 24 * 90
+
+// ValidateDeliveryCapacitySettings checks PUT /auth/delivery-capacity.
+func ValidateDeliveryCapacitySettings(settings capModel.Settings) error {
+	if settings.MaxOrdersPerDay != nil && *settings.MaxOrdersPerDay < 0 {
+		return errors.NewBadRequest(fmt.Errorf("'max_orders_per_day' must be 0 or greater"))
+	}
+	if settings.MaxUnitsPerDay != nil && *settings.MaxUnitsPerDay < 0 {
+		return errors.NewBadRequest(fmt.Errorf("'max_units_per_day' must be 0 or greater"))
+	}
+	if settings.MinLeadTimeHours < 0 || settings.MinLeadTimeHours > maxLeadTimeHours {
+		return errors.NewBadRequest(fmt.Errorf("'min_lead_time_hours' must be between 0 and %d", maxLeadTimeHours))
+	}
+	return nil
+}
+
+// ValidateDeliveryDayOverride checks PUT /auth/delivery-capacity/days/{date}.
+func ValidateDeliveryDayOverride(req capModel.UpsertDayOverrideRequest) error {
+	if req.MaxOrders != nil && *req.MaxOrders < 0 {
+		return errors.NewBadRequest(fmt.Errorf("'max_orders' must be 0 or greater"))
+	}
+	if req.MaxUnits != nil && *req.MaxUnits < 0 {
+		return errors.NewBadRequest(fmt.Errorf("'max_units' must be 0 or greater"))
+	}
+	if !req.Closed && req.MaxOrders == nil && req.MaxUnits == nil {
+		return errors.NewBadRequest(fmt.Errorf("set 'closed' or at least one of 'max_orders', 'max_units'"))
+	}
+	if req.Note != nil && len(*req.Note) > 255 {
+		return errors.NewBadRequest(fmt.Errorf("'note' must be at most 255 characters"))
+	}
+	return nil
+}
+
+// ParseDeliveryDate parses a YYYY-MM-DD date named field.
+func ParseDeliveryDate(field, s string) (time.Time, error) {
+	d, err := time.Parse(capModel.DateLayout, strings.TrimSpace(s))
+	if err != nil {
+		return time.Time{}, errors.NewBadRequest(fmt.Errorf("'%s' must be in YYYY-MM-DD format", field))
+	}
+	return d, nil
+}
+
+// ParseDateRange parses the from/to query params. Empty from is today (UTC), empty to is
+// from + defaultDays - 1; the range may span at most maxDays days.
+func ParseDateRange(fromStr, toStr string, today time.Time, defaultDays, maxDays int) (time.Time, time.Time, error) {
+	from := today
+	if strings.TrimSpace(fromStr) != "" {
+		d, err := ParseDeliveryDate("from", fromStr)
+		if err != nil {
+			return time.Time{}, time.Time{}, err
+		}
+		from = d
+	}
+	to := from.AddDate(0, 0, defaultDays-1)
+	if strings.TrimSpace(toStr) != "" {
+		d, err := ParseDeliveryDate("to", toStr)
+		if err != nil {
+			return time.Time{}, time.Time{}, err
+		}
+		to = d
+	}
+	if to.Before(from) {
+		return time.Time{}, time.Time{}, errors.NewBadRequest(fmt.Errorf("'to' must not be before 'from'"))
+	}
+	if to.Sub(from) >= time.Duration(maxDays)*24*time.Hour {
+		return time.Time{}, time.Time{}, errors.NewBadRequest(fmt.Errorf("the date range can span at most %d days", maxDays))
+	}
+	return from, to, nil
+}
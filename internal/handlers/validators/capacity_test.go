@@ -0,0 +1,48 @@
+package validators
+
+import (
+	"testing"
+	"time"
+
+	capModel "github.com/radamesvaz/bakery-app/model/capacity"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func intPtr(n int) *int { return &n }
+
+func TestValidateDeliveryCapacitySettings(t *testing.T) {
+	assert.NoError(t, ValidateDeliveryCapacitySettings(capModel.Settings{}))
+	assert.NoError(t, ValidateDeliveryCapacitySettings(capModel.Settings{MaxOrdersPerDay: intPtr(20), MaxUnitsPerDay: intPtr(0), MinLeadTimeHours: 24}))
+	assert.Error(t, ValidateDeliveryCapacitySettings(capModel.Settings{MaxOrdersPerDay: intPtr(-1)}))
+	assert.Error(t, ValidateDeliveryCapacitySettings(capModel.Settings{MinLeadTimeHours: -2}))
+	assert.Error(t, ValidateDeliveryCapacitySettings(capModel.Settings{MinLeadTimeHours: maxLeadTimeHours + 1}))
+}
+
+func TestValidateDeliveryDayOverride(t *testing.T) {
+	assert.NoError(t, ValidateDeliveryDayOverride(capModel.UpsertDayOverrideRequest{Closed: true}))
+	assert.NoError(t, ValidateDeliveryDayOverride(capModel.UpsertDayOverrideRequest{MaxUnits: intPtr(300)}))
+	assert.Error(t, ValidateDeliveryDayOverride(capModel.UpsertDayOverrideRequest{}))
+	assert.Error(t, ValidateDeliveryDayOverride(capModel.UpsertDayOverrideRequest{MaxOrders: intPtr(-5)}))
+}
+
+func TestParseDateRange(t *testing.T) {
+	today := time.Date(2026, 12, 20, 0, 0, 0, 0, time.UTC)
+
+	from, to, err := ParseDateRange("", "", today, 30, 92)
+	require.NoError(t, err)
+	assert.Equal(t, today, from)
+	assert.Equal(t, time.Date(2027, 1, 18, 0, 0, 0, 0, time.UTC), to)
+
+	from, to, err = ParseDateRange("2026-12-24", "2026-12-26", today, 30, 92)
+	require.NoError(t, err)
+	assert.Equal(t, time.Date(2026, 12, 24, 0, 0, 0, 0, time.UTC), from)
+	assert.Equal(t, time.Date(2026, 12, 26, 0, 0, 0, 0, time.UTC), to)
+
+	_, _, err = ParseDateRange("2026-12-26", "2026-12-24", today, 30, 92)
+	assert.Error(t, err)
+	_, _, err = ParseDateRange("2026-01-01", "2026-12-31", today, 30, 92)
+	assert.Error(t, err)
+	_, _, err = ParseDateRange("24/12/2026", "", today, 30, 92)
+	assert.Error(t, err)
+}
@@ -0,0 +1,48 @@
+package validators
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/radamesvaz/bakery-app/internal/errors"
+	customerModel "github.com/radamesvaz/bakery-app/model/customers"
+)
+
+const (
+	// MinCustomerSearchQLen is the minimum rune length for the `q` query param of the customer directory.
+	MinCustomerSearchQLen = 2
+	// MaxMergeDuplicates caps the accounts folded into a customer by one merge.
+	MaxMergeDuplicates = 20
+)
+
+// ParseCustomerSearchQuery parses the optional `q` param for the customer directory.
+// Empty or whitespace-only returns (nil, nil): no filter.
+func ParseCustomerSearchQuery(q string) (*string, error) {
+	trimmed := strings.TrimSpace(q)
+	if trimmed == "" {
+		return nil, nil
+	}
+	if len([]rune(trimmed)) < MinCustomerSearchQLen {
+		return nil, errors.NewBadRequest(fmt.Errorf("q must be at least %d characters", MinCustomerSearchQLen))
+	}
+	return &trimmed, nil
+}
+
+// ValidateMergeRequest requires between 1 and MaxMergeDuplicates distinct duplicate ids, none of
+// them the customer they are merged into.
+func ValidateMergeRequest(customerID uint64, req customerModel.MergeRequest) error {
+	if len(req.DuplicateIDs) == 0 || len(req.DuplicateIDs) > MaxMergeDuplicates {
+		return errors.NewBadRequest(fmt.Errorf("'duplicate_ids' must contain between 1 and %d ids", MaxMergeDuplicates))
+	}
+	seen := make(map[uint64]bool, len(req.DuplicateIDs))
+	for _, id := range req.DuplicateIDs {
+		if id == 0 || id == customerID {
+			return errors.NewBadRequest(fmt.Errorf("'duplicate_ids' must not contain %d", id))
+		}
+		if seen[id] {
+			return errors.NewBadRequest(fmt.Errorf("'duplicate_ids' contains %d more than once", id))
+		}
+		seen[id] = true
+	}
+	return nil
+}
@@ -0,0 +1,60 @@
+package validators
+
+import (
+	"net/http"
+	"testing"
+
+	appErrors "github.com/radamesvaz/bakery-app/internal/errors"
+	customerModel "github.com/radamesvaz/bakery-app/model/customers"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseCustomerSearchQuery(t *testing.T) {
+	q, err := ParseCustomerSearchQuery("  ")
+	require.NoError(t, err)
+	assert.Nil(t, q)
+
+	q, err = ParseCustomerSearchQuery(" 0414 ")
+	require.NoError(t, err)
+	require.NotNil(t, q)
+	assert.Equal(t, "0414", *q)
+
+	_, err = ParseCustomerSearchQuery("a")
+	var he *appErrors.HTTPError
+	require.ErrorAs(t, err, &he)
+	assert.Equal(t, http.StatusBadRequest, he.StatusCode)
+}
+
+func TestValidateMergeRequest(t *testing.T) {
+	tooMany := make([]uint64, MaxMergeDuplicates+1)
+	for i := range tooMany {
+		tooMany[i] = uint64(i + 10)
+	}
+
+	tests := []struct {
+		name    string
+		ids     []uint64
+		wantErr bool
+	}{
+		{name: "valid", ids: []uint64{4, 9}},
+		{name: "empty", ids: nil, wantErr: true},
+		{name: "too many", ids: tooMany, wantErr: true},
+		{name: "merges into itself", ids: []uint64{4, 3}, wantErr: true},
+		{name: "zero id", ids: []uint64{0}, wantErr: true},
+		{name: "repeated id", ids: []uint64{4, 4}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateMergeRequest(3, customerModel.MergeRequest{DuplicateIDs: tt.ids})
+			if tt.wantErr {
+				var he *appErrors.HTTPError
+				require.ErrorAs(t, err, &he)
+				assert.Equal(t, http.StatusBadRequest, he.StatusCode)
+				return
+			}
+			assert.NoError(t, err)
+		})
+	}
+}
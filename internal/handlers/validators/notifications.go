@@ -0,0 +1,26 @@
+package validators
+
+import (
+	"fmt"
+
+	"github.com/radamesvaz/bakery-app/internal/errors"
+	nModel "github.com/radamesvaz/bakery-app/model/notifications"
+)
+
+// ValidateNotificationSettings requires at least one setting, only known kinds and no duplicates.
+func ValidateNotificationSettings(settings []nModel.Setting) error {
+	if len(settings) == 0 {
+		return errors.NewBadRequest(fmt.Errorf("'items' must contain at least one notification"))
+	}
+	seen := make(map[nModel.Kind]bool, len(settings))
+	for _, s := range settings {
+		if !nModel.IsValidKind(s.Kind) {
+			return errors.NewBadRequest(fmt.Errorf("unknown notification kind '%s'", s.Kind))
+		}
+		if seen[s.Kind] {
+			return errors.NewBadRequest(fmt.Errorf("notification kind '%s' is repeated", s.Kind))
+		}
+		seen[s.Kind] = true
+	}
+	return nil
+}
@@ -0,0 +1,15 @@
+package validators
+
+import (
+	"testing"
+
+	nModel "github.com/radamesvaz/bakery-app/model/notifications"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidateNotificationSettings(t *testing.T) {
+	assert.NoError(t, ValidateNotificationSettings([]nModel.Setting{{Kind: nModel.KindOrderReady, Enabled: false}}))
+	assert.Error(t, ValidateNotificationSettings(nil))
+	assert.Error(t, ValidateNotificationSettings([]nModel.Setting{{Kind: "order_shipped"}}))
+	assert.Error(t, ValidateNotificationSettings([]nModel.Setting{{Kind: nModel.KindOrderReady}, {Kind: nModel.KindOrderReady, Enabled: true}}))
+}
@@ -0,0 +1,90 @@
+package validators
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/radamesvaz/bakery-app/internal/errors"
+	"github.com/radamesvaz/bakery-app/model/money"
+	oModel "github.com/radamesvaz/bakery-app/model/orders"
+)
+
+// ParseOptionalDateRange parses two optional YYYY-MM-DD query params that bound a range, both
+// inclusive. Empty values are nil.
+func ParseOptionalDateRange(fromField, fromStr, toField, toStr string) (*time.Time, *time.Time, error) {
+	var from, to *time.Time
+	if strings.TrimSpace(fromStr) != "" {
+		d, err := ParseDeliveryDate(fromField, fromStr)
+		if err != nil {
+			return nil, nil, err
+		}
+		from = &d
+	}
+	if strings.TrimSpace(toStr) != "" {
+		d, err := ParseDeliveryDate(toField, toStr)
+		if err != nil {
+			return nil, nil, err
+		}
+		to = &d
+	}
+	if from != nil && to != nil && to.Before(*from) {
+		return nil, nil, errors.NewBadRequest(fmt.Errorf("'%s' must not be before '%s'", toField, fromField))
+	}
+	return from, to, nil
+}
+
+// ParseOptionalTotalRange parses the min_total/max_total query params. Empty values are nil.
+func ParseOptionalTotalRange(minStr, maxStr string) (*money.Amount, *money.Amount, error) {
+	parse := func(field, s string) (*money.Amount, error) {
+		if strings.TrimSpace(s) == "" {
+			return nil, nil
+		}
+		v, err := money.Parse(s)
+		if err != nil || v < 0 {
+			return nil, errors.NewBadRequest(fmt.Errorf("'%s' must be a non-negative amount with at most 2 decimals", field))
+		}
+		return &v, nil
+	}
+	minTotal, err := parse("min_total", minStr)
+	if err != nil {
+		return nil, nil, err
+	}
+	maxTotal, err := parse("max_total", maxStr)
+	if err != nil {
+		return nil, nil, err
+	}
+	if minTotal != nil && maxTotal != nil && *maxTotal < *minTotal {
+		return nil, nil, errors.NewBadRequest(fmt.Errorf("'max_total' must not be less than 'min_total'"))
+	}
+	return minTotal, maxTotal, nil
+}
+
+// ParseOptionalBool parses a true/false query param. Empty is nil.
+func ParseOptionalBool(field, s string) (*bool, error) {
+	switch strings.ToLower(strings.TrimSpace(s)) {
+	case "":
+		return nil, nil
+	case "true":
+		v := true
+		return &v, nil
+	case "false":
+		v := false
+		return &v, nil
+	default:
+		return nil, errors.NewBadRequest(fmt.Errorf("'%s' must be true or false", field))
+	}
+}
+
+// ParseOptionalFulfillmentType parses the fulfillment_type query param (delivery or pickup). Empty is nil.
+func ParseOptionalFulfillmentType(s string) (*oModel.FulfillmentType, error) {
+	t := oModel.FulfillmentType(strings.ToLower(strings.TrimSpace(s)))
+	switch t {
+	case "":
+		return nil, nil
+	case oModel.FulfillmentDelivery, oModel.FulfillmentPickup:
+		return &t, nil
+	default:
+		return nil, errors.NewBadRequest(fmt.Errorf("'fulfillment_type' must be delivery or pickup"))
+	}
+}
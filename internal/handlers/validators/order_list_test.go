@@ -0,0 +1,83 @@
+package validators
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/radamesvaz/bakery-app/internal/errors"
+	"github.com/radamesvaz/bakery-app/model/money"
+	oModel "github.com/radamesvaz/bakery-app/model/orders"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseOptionalDateRange(t *testing.T) {
+	from, to, err := ParseOptionalDateRange("delivery_from", "", "delivery_to", "")
+	require.NoError(t, err)
+	assert.Nil(t, from)
+	assert.Nil(t, to)
+
+	from, to, err = ParseOptionalDateRange("delivery_from", "2026-03-01", "delivery_to", "2026-03-01")
+	require.NoError(t, err)
+	assert.Equal(t, time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC), *from)
+	assert.Equal(t, *from, *to)
+
+	_, _, err = ParseOptionalDateRange("delivery_from", "2026-03-02", "delivery_to", "2026-03-01")
+	assertBadRequest(t, err)
+
+	_, _, err = ParseOptionalDateRange("delivery_from", "03/01/2026", "delivery_to", "")
+	assertBadRequest(t, err)
+}
+
+func TestParseOptionalTotalRange(t *testing.T) {
+	minTotal, maxTotal, err := ParseOptionalTotalRange("10", "")
+	require.NoError(t, err)
+	assert.Equal(t, money.Amount(1000), *minTotal)
+	assert.Nil(t, maxTotal)
+
+	_, _, err = ParseOptionalTotalRange("-1", "")
+	assertBadRequest(t, err)
+
+	_, _, err = ParseOptionalTotalRange("", "NaN")
+	assertBadRequest(t, err)
+
+	_, _, err = ParseOptionalTotalRange("", "10.005")
+	assertBadRequest(t, err)
+
+	_, _, err = ParseOptionalTotalRange("20", "10")
+	assertBadRequest(t, err)
+}
+
+func TestParseOptionalBool(t *testing.T) {
+	v, err := ParseOptionalBool("paid", "")
+	require.NoError(t, err)
+	assert.Nil(t, v)
+
+	v, err = ParseOptionalBool("paid", "FALSE")
+	require.NoError(t, err)
+	assert.False(t, *v)
+
+	_, err = ParseOptionalBool("paid", "yes")
+	assertBadRequest(t, err)
+}
+
+func TestParseOptionalFulfillmentType(t *testing.T) {
+	v, err := ParseOptionalFulfillmentType("")
+	require.NoError(t, err)
+	assert.Nil(t, v)
+
+	v, err = ParseOptionalFulfillmentType("Pickup")
+	require.NoError(t, err)
+	assert.Equal(t, oModel.FulfillmentPickup, *v)
+
+	_, err = ParseOptionalFulfillmentType("shipping")
+	assertBadRequest(t, err)
+}
+
+func assertBadRequest(t *testing.T, err error) {
+	t.Helper()
+	var he *errors.HTTPError
+	require.ErrorAs(t, err, &he)
+	assert.Equal(t, http.StatusBadRequest, he.StatusCode)
+}
@@ -0,0 +1,56 @@
+package validators
+
+import (
+	"fmt"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/radamesvaz/bakery-app/internal/errors"
+	opModel "github.com/radamesvaz/bakery-app/model/orderprotection"
+)
+
+// Upper bounds of the order protection settings; 0 disables a limit.
+const (
+	MaxPendingUnpaidOrdersLimit = 100
+	MaxQuantityPerLineLimit     = 100000
+)
+
+// ValidateOrderProtectionSettings checks PUT /auth/order-protection.
+func ValidateOrderProtectionSettings(settings opModel.Settings) error {
+	if settings.MaxPendingUnpaidOrders < 0 || settings.MaxPendingUnpaidOrders > MaxPendingUnpaidOrdersLimit {
+		return errors.NewBadRequest(fmt.Errorf("'max_pending_unpaid_orders' must be between 0 and %d", MaxPendingUnpaidOrdersLimit))
+	}
+	if settings.MaxQuantityPerLine < 0 || settings.MaxQuantityPerLine > MaxQuantityPerLineLimit {
+		return errors.NewBadRequest(fmt.Errorf("'max_quantity_per_line' must be between 0 and %d", MaxQuantityPerLineLimit))
+	}
+	return nil
+}
+
+// ValidateBlocklistEntryRequest checks POST /auth/order-blocklist and returns the entry to store
+// with its value normalized: a valid email, or a phone with 6 to 20 digits. A blank reason is
+// dropped.
+func ValidateBlocklistEntryRequest(req opModel.BlocklistEntryRequest) (opModel.BlocklistEntry, error) {
+	entry := opModel.BlocklistEntry{Kind: req.Kind, Value: opModel.NormalizeValue(req.Kind, req.Value)}
+	switch req.Kind {
+	case opModel.BlocklistEmail:
+		if !IsValidEmail(entry.Value) || len(entry.Value) > 255 {
+			return entry, errors.NewBadRequest(fmt.Errorf("'value' must be a valid email"))
+		}
+	case opModel.BlocklistPhone:
+		if len(entry.Value) < 6 || len(entry.Value) > 20 {
+			return entry, errors.NewBadRequest(fmt.Errorf("'value' must be a phone with 6 to 20 digits"))
+		}
+	default:
+		return entry, errors.NewBadRequest(fmt.Errorf("'kind' must be 'email' or 'phone'"))
+	}
+	if req.Reason != nil {
+		reason := strings.TrimSpace(*req.Reason)
+		if utf8.RuneCountInString(reason) > 255 {
+			return entry, errors.NewBadRequest(fmt.Errorf("'reason' must be at most 255 characters"))
+		}
+		if reason != "" {
+			entry.Reason = &reason
+		}
+	}
+	return entry, nil
+}
@@ -0,0 +1,51 @@
+package validators
+
+import (
+	"strings"
+	"testing"
+
+	opModel "github.com/radamesvaz/bakery-app/model/orderprotection"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidateOrderProtectionSettings(t *testing.T) {
+	require.NoError(t, ValidateOrderProtectionSettings(opModel.DefaultSettings()))
+	require.NoError(t, ValidateOrderProtectionSettings(opModel.Settings{}))
+
+	assertBadRequest(t, ValidateOrderProtectionSettings(opModel.Settings{MaxPendingUnpaidOrders: -1}))
+	assertBadRequest(t, ValidateOrderProtectionSettings(opModel.Settings{MaxPendingUnpaidOrders: MaxPendingUnpaidOrdersLimit + 1}))
+	assertBadRequest(t, ValidateOrderProtectionSettings(opModel.Settings{MaxQuantityPerLine: -1}))
+}
+
+func TestValidateBlocklistEntryRequest(t *testing.T) {
+	blank := "  "
+	entry, err := ValidateBlocklistEntryRequest(opModel.BlocklistEntryRequest{Kind: opModel.BlocklistEmail, Value: " Ana@Example.com ", Reason: &blank})
+	require.NoError(t, err)
+	assert.Equal(t, "ana@example.com", entry.Value)
+	assert.Nil(t, entry.Reason)
+
+	reason := " fake orders "
+	entry, err = ValidateBlocklistEntryRequest(opModel.BlocklistEntryRequest{Kind: opModel.BlocklistPhone, Value: "+58 412-555", Reason: &reason})
+	require.NoError(t, err)
+	assert.Equal(t, "58412555", entry.Value)
+	require.NotNil(t, entry.Reason)
+	assert.Equal(t, "fake orders", *entry.Reason)
+
+	long := strings.Repeat("a", 256)
+	tests := []struct {
+		name string
+		req  opModel.BlocklistEntryRequest
+	}{
+		{name: "unknown kind", req: opModel.BlocklistEntryRequest{Kind: "ip", Value: "10.0.0.1"}},
+		{name: "invalid email", req: opModel.BlocklistEntryRequest{Kind: opModel.BlocklistEmail, Value: "ana"}},
+		{name: "short phone", req: opModel.BlocklistEntryRequest{Kind: opModel.BlocklistPhone, Value: "12-34"}},
+		{name: "long reason", req: opModel.BlocklistEntryRequest{Kind: opModel.BlocklistPhone, Value: "58412555", Reason: &long}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ValidateBlocklistEntryRequest(tt.req)
+			assertBadRequest(t, err)
+		})
+	}
+}
@@ -0,0 +1,57 @@
+package validators
+
+import (
+	"fmt"
+
+	"github.com/radamesvaz/bakery-app/internal/errors"
+	oModel "github.com/radamesvaz/bakery-app/model/orders"
+)
+
+// transitionSources are the statuses a tenant may give outgoing transitions. Cancelled, expired and
+// deleted orders stay final: their stock was already returned.
+var transitionSources = map[oModel.OrderStatus]bool{
+	oModel.StatusPending:   true,
+	oModel.StatusPreparing: true,
+	oModel.StatusReady:     true,
+	oModel.StatusDelivered: true,
+}
+
+// transitionTargets are the statuses an order can be moved to through the API; expired is only
+// set by the pending order expiry worker.
+var transitionTargets = map[oModel.OrderStatus]bool{
+	oModel.StatusPending:   true,
+	oModel.StatusPreparing: true,
+	oModel.StatusReady:     true,
+	oModel.StatusDelivered: true,
+	oModel.StatusCancelled: true,
+	oModel.StatusDeleted:   true,
+}
+
+// ValidateStatusTransitions checks PUT /auth/order-status-transitions and returns the transitions
+// with repeated targets dropped and statuses without targets left out.
+func ValidateStatusTransitions(transitions oModel.StatusTransitions) (oModel.StatusTransitions, error) {
+	cleaned := make(oModel.StatusTransitions, len(transitions))
+	for from, targets := range transitions {
+		if !transitionSources[from] {
+			return nil, errors.NewBadRequest(fmt.Errorf("'transitions' cannot start from '%s'", from))
+		}
+		seen := make(map[oModel.OrderStatus]bool, len(targets))
+		for _, to := range targets {
+			if !transitionTargets[to] {
+				return nil, errors.NewBadRequest(fmt.Errorf("'transitions' cannot move orders to '%s'", to))
+			}
+			if to == from {
+				return nil, errors.NewBadRequest(fmt.Errorf("'transitions' cannot move '%s' to itself", from))
+			}
+			if seen[to] {
+				continue
+			}
+			seen[to] = true
+			cleaned[from] = append(cleaned[from], to)
+		}
+	}
+	if len(cleaned) == 0 {
+		return nil, errors.NewBadRequest(fmt.Errorf("'transitions' must allow at least one status change"))
+	}
+	return cleaned, nil
+}
@@ -0,0 +1,39 @@
+package validators
+
+import (
+	"testing"
+
+	oModel "github.com/radamesvaz/bakery-app/model/orders"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidateStatusTransitions(t *testing.T) {
+	cleaned, err := ValidateStatusTransitions(oModel.StatusTransitions{
+		oModel.StatusPending:   {oModel.StatusDelivered, oModel.StatusCancelled, oModel.StatusDelivered},
+		oModel.StatusPreparing: {},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, oModel.StatusTransitions{
+		oModel.StatusPending: {oModel.StatusDelivered, oModel.StatusCancelled},
+	}, cleaned)
+
+	tests := []struct {
+		name        string
+		transitions oModel.StatusTransitions
+	}{
+		{name: "empty", transitions: oModel.StatusTransitions{}},
+		{name: "only statuses without targets", transitions: oModel.StatusTransitions{oModel.StatusPending: nil}},
+		{name: "leaving cancelled", transitions: oModel.StatusTransitions{oModel.StatusCancelled: {oModel.StatusPending}}},
+		{name: "unknown source", transitions: oModel.StatusTransitions{"baking": {oModel.StatusReady}}},
+		{name: "moving to expired", transitions: oModel.StatusTransitions{oModel.StatusPending: {oModel.StatusExpired}}},
+		{name: "unknown target", transitions: oModel.StatusTransitions{oModel.StatusPending: {"baking"}}},
+		{name: "self transition", transitions: oModel.StatusTransitions{oModel.StatusReady: {oModel.StatusReady}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ValidateStatusTransitions(tt.transitions)
+			assertBadRequest(t, err)
+		})
+	}
+}
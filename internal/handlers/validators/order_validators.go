@@ -2,6 +2,7 @@ package validators
 
 import (
 	"fmt"
+	"slices"
 	"strings"
 
 	"github.com/radamesvaz/bakery-app/internal/errors"
@@ -24,13 +25,95 @@ func ValidateCreateOrderPayload(payload oModel.CreateOrderPayload) error {
 	if strings.TrimSpace(payload.DeliveryDate) == "" {
 		return fmt.Errorf("The 'delivery_date' field is mandatory")
 	}
-	if strings.TrimSpace(payload.DeliveryDirection) == "" {
-		return errors.ErrMissingDeliveryDirection
+	if err := validateOrderFulfillment(payload, true); err != nil {
+		return err
 	}
-	if len(payload.Items) == 0 {
+	return ValidateOrderItemsInput(payload.Items)
+}
+
+// ValidateStaffCreateOrderPayload checks POST /auth/orders bodies. Unlike storefront orders the
+// customer is optional: name and phone go with an email, which creates or finds the customer.
+// delivery_date may be omitted (the handler uses today) and pickups may skip the location.
+func ValidateStaffCreateOrderPayload(payload oModel.StaffCreateOrderPayload) error {
+	switch payload.SalesChannel {
+	case oModel.SalesChannelWeb, oModel.SalesChannelPhone, oModel.SalesChannelWhatsApp, oModel.SalesChannelCounter:
+	case "":
+		return fmt.Errorf("The 'sales_channel' field is mandatory")
+	default:
+		return fmt.Errorf("The 'sales_channel' field must be one of web, phone, whatsapp, counter")
+	}
+	if strings.TrimSpace(payload.Email) != "" {
+		if !IsValidEmail(payload.Email) {
+			return fmt.Errorf("The 'email' field has no valid format")
+		}
+		if strings.TrimSpace(payload.Name) == "" {
+			return fmt.Errorf("The 'name' field is mandatory when 'email' is sent")
+		}
+	} else {
+		if strings.TrimSpace(payload.Name) != "" || strings.TrimSpace(payload.Phone) != "" {
+			return fmt.Errorf("The 'name' and 'phone' fields need an 'email' to create the customer")
+		}
+		if strings.TrimSpace(payload.PromotionCode) != "" {
+			return fmt.Errorf("The 'promotion_code' field needs an 'email'")
+		}
+	}
+	switch payload.Status {
+	case "", oModel.StatusPending, oModel.StatusPreparing, oModel.StatusReady, oModel.StatusDelivered:
+	default:
+		return fmt.Errorf("The 'status' field must be one of pending, preparing, ready, delivered")
+	}
+	if payload.Paid {
+		if payload.PaymentMethod != "" && (!slices.Contains(oModel.PaymentMethods, payload.PaymentMethod) || payload.PaymentMethod == oModel.PaymentMethodOnline) {
+			return fmt.Errorf("The 'payment_method' field must be one of cash, transfer, card, other")
+		}
+		if payload.PaymentReference != nil && len(*payload.PaymentReference) > 255 {
+			return fmt.Errorf("The 'payment_reference' field must be at most 255 characters")
+		}
+	} else if payload.PaymentMethod != "" || payload.PaymentReference != nil {
+		return fmt.Errorf("The 'payment_method' and 'payment_reference' fields are only allowed for paid orders")
+	}
+	if err := validateOrderFulfillment(payload.CreateOrderPayload, false); err != nil {
+		return err
+	}
+	return ValidateOrderItemsInput(payload.Items)
+}
+
+// validateOrderFulfillment checks the delivery or pickup fields of an order; staff pickups at
+// the shop may omit the location.
+func validateOrderFulfillment(payload oModel.CreateOrderPayload, pickupLocationRequired bool) error {
+	switch oModel.NormalizeFulfillmentType(payload.FulfillmentType) {
+	case oModel.FulfillmentDelivery:
+		if strings.TrimSpace(payload.DeliveryDirection) == "" {
+			return errors.ErrMissingDeliveryDirection
+		}
+		if payload.IDDeliveryZone != nil && *payload.IDDeliveryZone == 0 {
+			return fmt.Errorf("The 'id_delivery_zone' field has an invalid ID")
+		}
+		if payload.IDPickupLocation != nil {
+			return fmt.Errorf("The 'id_pickup_location' field is only allowed for pickup orders")
+		}
+	case oModel.FulfillmentPickup:
+		if payload.IDPickupLocation == nil && pickupLocationRequired {
+			return fmt.Errorf("The 'id_pickup_location' field is mandatory for pickup orders")
+		}
+		if payload.IDPickupLocation != nil && *payload.IDPickupLocation == 0 {
+			return fmt.Errorf("The 'id_pickup_location' field has an invalid ID")
+		}
+		if payload.IDDeliveryZone != nil {
+			return fmt.Errorf("The 'id_delivery_zone' field is not allowed for pickup orders")
+		}
+	default:
+		return fmt.Errorf("The 'fulfillment_type' field must be 'delivery' or 'pickup'")
+	}
+	return nil
+}
+
+// ValidateOrderItemsInput checks the line items sent on order create or on PUT /auth/orders/{id}/items.
+func ValidateOrderItemsInput(items []oModel.CreateOrderItemInput) error {
+	if len(items) == 0 {
 		return fmt.Errorf("An item must be sent for the order")
 	}
-	for i, item := range payload.Items {
+	for i, item := range items {
 		if item.IdProduct == 0 {
 			return fmt.Errorf("The product at position %d has an invalid ID", i)
 		}
@@ -72,3 +155,18 @@ func NormalizeAndValidateOrderSearchQuery(q string) (string, error) {
 	}
 	return normalized, nil
 }
+
+// ValidateRecordOrderPaymentPayload checks POST /auth/orders/{id}/payments and /refunds bodies.
+// Staff cannot record "online" payments; those come from the payment provider.
+func ValidateRecordOrderPaymentPayload(payload oModel.RecordOrderPaymentPayload, isRefund bool) error {
+	if payload.Amount <= 0 {
+		return errors.NewBadRequest(fmt.Errorf("amount must be greater than 0"))
+	}
+	if !slices.Contains(oModel.PaymentMethods, payload.Method) || (payload.Method == oModel.PaymentMethodOnline && !isRefund) {
+		return errors.NewBadRequest(fmt.Errorf("method must be one of cash, transfer, card, other"))
+	}
+	if payload.Reference != nil && len(*payload.Reference) > 255 {
+		return errors.NewBadRequest(fmt.Errorf("reference must be at most 255 characters"))
+	}
+	return nil
+}
@@ -11,6 +11,7 @@ import (
 )
 
 func TestValidateCreateOrderPayload(t *testing.T) {
+	pickupLocationID := uint64(3)
 	tests := []struct {
 		name    string
 		payload oModel.CreateOrderPayload
@@ -140,6 +141,97 @@ func TestValidateCreateOrderPayload(t *testing.T) {
 			},
 			wantErr: true,
 		},
+		{
+			name: "Happy path: pickup order without delivery direction",
+			payload: oModel.CreateOrderPayload{
+				Name:             "usuario uno",
+				Email:            "usuario1@gmail.com",
+				Phone:            "55-555",
+				DeliveryDate:     "2025-05-20",
+				FulfillmentType:  oModel.FulfillmentPickup,
+				IDPickupLocation: &pickupLocationID,
+				Items: []oModel.CreateOrderItemInput{
+					{IdProduct: 1, Quantity: 2},
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "Sad path: pickup order without pickup location",
+			payload: oModel.CreateOrderPayload{
+				Name:              "usuario uno",
+				Email:             "usuario1@gmail.com",
+				Phone:             "55-555",
+				DeliveryDate:      "2025-05-20",
+				DeliveryDirection: "direccion de entrega",
+				FulfillmentType:   oModel.FulfillmentPickup,
+				Items: []oModel.CreateOrderItemInput{
+					{IdProduct: 1, Quantity: 2},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "Sad path: pickup order with delivery zone",
+			payload: oModel.CreateOrderPayload{
+				Name:             "usuario uno",
+				Email:            "usuario1@gmail.com",
+				Phone:            "55-555",
+				DeliveryDate:     "2025-05-20",
+				FulfillmentType:  oModel.FulfillmentPickup,
+				IDPickupLocation: &pickupLocationID,
+				IDDeliveryZone:   &pickupLocationID,
+				Items: []oModel.CreateOrderItemInput{
+					{IdProduct: 1, Quantity: 2},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "Sad path: delivery order with pickup location",
+			payload: oModel.CreateOrderPayload{
+				Name:              "usuario uno",
+				Email:             "usuario1@gmail.com",
+				Phone:             "55-555",
+				DeliveryDate:      "2025-05-20",
+				DeliveryDirection: "direccion de entrega",
+				IDPickupLocation:  &pickupLocationID,
+				Items: []oModel.CreateOrderItemInput{
+					{IdProduct: 1, Quantity: 2},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "Sad path: unknown fulfillment type",
+			payload: oModel.CreateOrderPayload{
+				Name:              "usuario uno",
+				Email:             "usuario1@gmail.com",
+				Phone:             "55-555",
+				DeliveryDate:      "2025-05-20",
+				DeliveryDirection: "direccion de entrega",
+				FulfillmentType:   "shipping",
+				Items: []oModel.CreateOrderItemInput{
+					{IdProduct: 1, Quantity: 2},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "Sad path: zero delivery zone",
+			payload: oModel.CreateOrderPayload{
+				Name:              "usuario uno",
+				Email:             "usuario1@gmail.com",
+				Phone:             "55-555",
+				DeliveryDate:      "2025-05-20",
+				DeliveryDirection: "direccion de entrega",
+				IDDeliveryZone:    new(uint64),
+				Items: []oModel.CreateOrderItemInput{
+					{IdProduct: 1, Quantity: 2},
+				},
+			},
+			wantErr: true,
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -156,6 +248,63 @@ func TestValidateCreateOrderPayload(t *testing.T) {
 	}
 }
 
+func TestValidateStaffCreateOrderPayload(t *testing.T) {
+	items := []oModel.CreateOrderItemInput{{IdProduct: 1, Quantity: 2}}
+	counterSale := func(edit func(p *oModel.StaffCreateOrderPayload)) oModel.StaffCreateOrderPayload {
+		p := oModel.StaffCreateOrderPayload{
+			CreateOrderPayload: oModel.CreateOrderPayload{FulfillmentType: oModel.FulfillmentPickup, Items: items},
+			SalesChannel:       oModel.SalesChannelCounter,
+			Status:             oModel.StatusDelivered,
+			Paid:               true,
+		}
+		if edit != nil {
+			edit(&p)
+		}
+		return p
+	}
+	ref := "TRX-1"
+	tests := []struct {
+		name    string
+		payload oModel.StaffCreateOrderPayload
+		wantErr bool
+	}{
+		{name: "Happy path: anonymous counter sale", payload: counterSale(nil)},
+		{name: "Happy path: phone order with customer", payload: counterSale(func(p *oModel.StaffCreateOrderPayload) {
+			p.SalesChannel = oModel.SalesChannelPhone
+			p.Name, p.Email, p.Phone = "Ana", "ana@example.com", "55-555"
+			p.FulfillmentType, p.DeliveryDirection = oModel.FulfillmentDelivery, "direccion"
+			p.Status, p.Paid = "", false
+		})},
+		{name: "Happy path: paid by transfer", payload: counterSale(func(p *oModel.StaffCreateOrderPayload) {
+			p.PaymentMethod, p.PaymentReference = oModel.PaymentMethodTransfer, &ref
+		})},
+		{name: "Sad path: missing sales channel", payload: counterSale(func(p *oModel.StaffCreateOrderPayload) { p.SalesChannel = "" }), wantErr: true},
+		{name: "Sad path: unknown sales channel", payload: counterSale(func(p *oModel.StaffCreateOrderPayload) { p.SalesChannel = "fax" }), wantErr: true},
+		{name: "Sad path: name without email", payload: counterSale(func(p *oModel.StaffCreateOrderPayload) { p.Name = "Ana" }), wantErr: true},
+		{name: "Sad path: email without name", payload: counterSale(func(p *oModel.StaffCreateOrderPayload) { p.Email = "ana@example.com" }), wantErr: true},
+		{name: "Sad path: promotion without email", payload: counterSale(func(p *oModel.StaffCreateOrderPayload) { p.PromotionCode = "PROMO" }), wantErr: true},
+		{name: "Sad path: cancelled status", payload: counterSale(func(p *oModel.StaffCreateOrderPayload) { p.Status = oModel.StatusCancelled }), wantErr: true},
+		{name: "Sad path: online payment method", payload: counterSale(func(p *oModel.StaffCreateOrderPayload) { p.PaymentMethod = oModel.PaymentMethodOnline }), wantErr: true},
+		{name: "Sad path: payment method on unpaid order", payload: counterSale(func(p *oModel.StaffCreateOrderPayload) {
+			p.Paid, p.PaymentMethod = false, oModel.PaymentMethodCash
+		}), wantErr: true},
+		{name: "Sad path: delivery without direction", payload: counterSale(func(p *oModel.StaffCreateOrderPayload) {
+			p.FulfillmentType = oModel.FulfillmentDelivery
+		}), wantErr: true},
+		{name: "Sad path: no items", payload: counterSale(func(p *oModel.StaffCreateOrderPayload) { p.Items = nil }), wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateStaffCreateOrderPayload(tt.payload)
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
 func TestValidateOrderListStatusFilter(t *testing.T) {
 	assert.NoError(t, ValidateOrderListStatusFilter(""))
 	assert.NoError(t, ValidateOrderListStatusFilter(string(oModel.StatusPending)))
@@ -186,3 +335,34 @@ func TestNormalizeAndValidateOrderSearchQuery(t *testing.T) {
 	require.ErrorAs(t, err, &he)
 	assert.Equal(t, http.StatusBadRequest, he.StatusCode)
 }
+
+func TestValidateRecordOrderPaymentPayload(t *testing.T) {
+	ref := "TRX-1"
+	tests := []struct {
+		name     string
+		payload  oModel.RecordOrderPaymentPayload
+		isRefund bool
+		wantErr  bool
+	}{
+		{name: "Happy path: cash deposit", payload: oModel.RecordOrderPaymentPayload{Amount: 2550, Method: oModel.PaymentMethodCash}},
+		{name: "Happy path: transfer with reference", payload: oModel.RecordOrderPaymentPayload{Amount: 1000, Method: oModel.PaymentMethodTransfer, Reference: &ref}},
+		{name: "Happy path: online refund", payload: oModel.RecordOrderPaymentPayload{Amount: 1000, Method: oModel.PaymentMethodOnline}, isRefund: true},
+		{name: "Sad path: zero amount", payload: oModel.RecordOrderPaymentPayload{Amount: 0, Method: oModel.PaymentMethodCash}, wantErr: true},
+		{name: "Sad path: unknown method", payload: oModel.RecordOrderPaymentPayload{Amount: 500, Method: "crypto"}, wantErr: true},
+		{name: "Sad path: online payment recorded by staff", payload: oModel.RecordOrderPaymentPayload{Amount: 500, Method: oModel.PaymentMethodOnline}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateRecordOrderPaymentPayload(tt.payload, tt.isRefund)
+			if !tt.wantErr {
+				assert.NoError(t, err)
+				return
+			}
+			require.Error(t, err)
+			var httpErr *errors.HTTPError
+			require.ErrorAs(t, err, &httpErr)
+			assert.Equal(t, http.StatusBadRequest, httpErr.StatusCode)
+		})
+	}
+}
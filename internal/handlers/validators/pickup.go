@@ -0,0 +1,53 @@
+package validators
+
+import (
+	"fmt"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/radamesvaz/bakery-app/internal/errors"
+	pickupModel "github.com/radamesvaz/bakery-app/model/pickup"
+)
+
+// ValidatePickupLocationRequest checks POST /auth/pickup-locations and PUT /auth/pickup-locations/{id}
+// and returns the location to store with trimmed texts; active defaults to true. Opening hours
+// need one entry per weekday (1 = Monday ... 7 = Sunday) with "HH:MM" opens before closes.
+func ValidatePickupLocationRequest(req pickupModel.LocationRequest) (pickupModel.Location, error) {
+	location := pickupModel.Location{
+		Name:         strings.TrimSpace(req.Name),
+		Address:      strings.TrimSpace(req.Address),
+		OpeningHours: []pickupModel.OpeningHours{},
+		Active:       req.Active == nil || *req.Active,
+	}
+	if location.Name == "" || utf8.RuneCountInString(location.Name) > 100 {
+		return location, errors.NewBadRequest(fmt.Errorf("'name' must be 1 to 100 characters"))
+	}
+	if location.Address == "" {
+		return location, errors.NewBadRequest(fmt.Errorf("'address' is required"))
+	}
+
+	seen := make(map[int]bool)
+	for i, h := range req.OpeningHours {
+		if h.Weekday < 1 || h.Weekday > 7 {
+			return location, errors.NewBadRequest(fmt.Errorf("opening_hours[%d]: 'weekday' must be between 1 and 7", i))
+		}
+		if seen[h.Weekday] {
+			return location, errors.NewBadRequest(fmt.Errorf("opening_hours[%d]: weekday %d is listed twice", i, h.Weekday))
+		}
+		seen[h.Weekday] = true
+		opens, err := time.Parse("15:04", h.Opens)
+		if err != nil {
+			return location, errors.NewBadRequest(fmt.Errorf("opening_hours[%d]: 'opens' must be HH:MM", i))
+		}
+		closes, err := time.Parse("15:04", h.Closes)
+		if err != nil {
+			return location, errors.NewBadRequest(fmt.Errorf("opening_hours[%d]: 'closes' must be HH:MM", i))
+		}
+		if !opens.Before(closes) {
+			return location, errors.NewBadRequest(fmt.Errorf("opening_hours[%d]: 'opens' must be before 'closes'", i))
+		}
+		location.OpeningHours = append(location.OpeningHours, h)
+	}
+	return location, nil
+}
@@ -0,0 +1,55 @@
+package validators
+
+import (
+	"strings"
+	"testing"
+
+	pickupModel "github.com/radamesvaz/bakery-app/model/pickup"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidatePickupLocationRequest(t *testing.T) {
+	saturday := pickupModel.OpeningHours{Weekday: 6, Opens: "09:00", Closes: "13:30"}
+	location, err := ValidatePickupLocationRequest(pickupModel.LocationRequest{
+		Name:         " Tienda Centro ",
+		Address:      " Av. Principal 12 ",
+		OpeningHours: []pickupModel.OpeningHours{saturday},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "Tienda Centro", location.Name)
+	assert.Equal(t, "Av. Principal 12", location.Address)
+	assert.Equal(t, []pickupModel.OpeningHours{saturday}, location.OpeningHours)
+	assert.True(t, location.Active)
+
+	inactive := false
+	location, err = ValidatePickupLocationRequest(pickupModel.LocationRequest{Name: "Norte", Address: "Calle 5", Active: &inactive})
+	require.NoError(t, err)
+	assert.False(t, location.Active)
+	assert.NotNil(t, location.OpeningHours)
+
+	tests := []struct {
+		name  string
+		hours []pickupModel.OpeningHours
+		req   *pickupModel.LocationRequest
+	}{
+		{name: "blank name", req: &pickupModel.LocationRequest{Name: " ", Address: "Calle 5"}},
+		{name: "long name", req: &pickupModel.LocationRequest{Name: strings.Repeat("a", 101), Address: "Calle 5"}},
+		{name: "blank address", req: &pickupModel.LocationRequest{Name: "Norte", Address: " "}},
+		{name: "weekday out of range", hours: []pickupModel.OpeningHours{{Weekday: 0, Opens: "09:00", Closes: "10:00"}}},
+		{name: "duplicate weekday", hours: []pickupModel.OpeningHours{saturday, saturday}},
+		{name: "bad opens", hours: []pickupModel.OpeningHours{{Weekday: 1, Opens: "9am", Closes: "10:00"}}},
+		{name: "bad closes", hours: []pickupModel.OpeningHours{{Weekday: 1, Opens: "09:00", Closes: "25:00"}}},
+		{name: "closes before opens", hours: []pickupModel.OpeningHours{{Weekday: 1, Opens: "18:00", Closes: "09:00"}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := pickupModel.LocationRequest{Name: "Norte", Address: "Calle 5", OpeningHours: tt.hours}
+			if tt.req != nil {
+				req = *tt.req
+			}
+			_, err := ValidatePickupLocationRequest(req)
+			assertBadRequest(t, err)
+		})
+	}
+}
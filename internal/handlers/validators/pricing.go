@@ -0,0 +1,46 @@
+package validators
+
+import (
+	"fmt"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/radamesvaz/bakery-app/internal/errors"
+	pricingModel "github.com/radamesvaz/bakery-app/model/pricing"
+)
+
+// ValidatePricingSettings checks PUT /auth/pricing: a tax rate between 0 and 100 percent and
+// non-negative delivery amounts.
+func ValidatePricingSettings(settings pricingModel.Settings) error {
+	if settings.TaxRate < 0 || settings.TaxRate > 100 {
+		return errors.NewBadRequest(fmt.Errorf("'tax_rate' must be between 0 and 100"))
+	}
+	if settings.DeliveryFee < 0 {
+		return errors.NewBadRequest(fmt.Errorf("'delivery_fee' cannot be negative"))
+	}
+	if settings.FreeDeliveryAbove != nil && *settings.FreeDeliveryAbove < 0 {
+		return errors.NewBadRequest(fmt.Errorf("'free_delivery_above' cannot be negative"))
+	}
+	return nil
+}
+
+// ValidateDeliveryZoneRequest checks POST /auth/delivery-zones and PUT /auth/delivery-zones/{id}
+// and returns the zone to store with a trimmed name; active defaults to true.
+func ValidateDeliveryZoneRequest(req pricingModel.DeliveryZoneRequest) (pricingModel.DeliveryZone, error) {
+	zone := pricingModel.DeliveryZone{
+		Name:              strings.TrimSpace(req.Name),
+		Fee:               req.Fee,
+		FreeDeliveryAbove: req.FreeDeliveryAbove,
+		Active:            req.Active == nil || *req.Active,
+	}
+	if zone.Name == "" || utf8.RuneCountInString(zone.Name) > 100 {
+		return zone, errors.NewBadRequest(fmt.Errorf("'name' must be 1 to 100 characters"))
+	}
+	if zone.Fee < 0 {
+		return zone, errors.NewBadRequest(fmt.Errorf("'fee' cannot be negative"))
+	}
+	if zone.FreeDeliveryAbove != nil && *zone.FreeDeliveryAbove < 0 {
+		return zone, errors.NewBadRequest(fmt.Errorf("'free_delivery_above' cannot be negative"))
+	}
+	return zone, nil
+}
@@ -0,0 +1,50 @@
+package validators
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/radamesvaz/bakery-app/model/money"
+	pricingModel "github.com/radamesvaz/bakery-app/model/pricing"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidatePricingSettings(t *testing.T) {
+	negative := money.Amount(-100)
+	require.NoError(t, ValidatePricingSettings(pricingModel.Settings{TaxRate: 16, DeliveryFee: 250}))
+
+	assertBadRequest(t, ValidatePricingSettings(pricingModel.Settings{TaxRate: -1}))
+	assertBadRequest(t, ValidatePricingSettings(pricingModel.Settings{TaxRate: 101}))
+	assertBadRequest(t, ValidatePricingSettings(pricingModel.Settings{DeliveryFee: -100}))
+	assertBadRequest(t, ValidatePricingSettings(pricingModel.Settings{FreeDeliveryAbove: &negative}))
+}
+
+func TestValidateDeliveryZoneRequest(t *testing.T) {
+	inactive := false
+	zone, err := ValidateDeliveryZoneRequest(pricingModel.DeliveryZoneRequest{Name: " Centro ", Fee: 300})
+	require.NoError(t, err)
+	assert.Equal(t, "Centro", zone.Name)
+	assert.True(t, zone.Active)
+
+	zone, err = ValidateDeliveryZoneRequest(pricingModel.DeliveryZoneRequest{Name: "Norte", Active: &inactive})
+	require.NoError(t, err)
+	assert.False(t, zone.Active)
+
+	negative := money.Amount(-100)
+	tests := []struct {
+		name string
+		req  pricingModel.DeliveryZoneRequest
+	}{
+		{name: "blank name", req: pricingModel.DeliveryZoneRequest{Name: "  "}},
+		{name: "long name", req: pricingModel.DeliveryZoneRequest{Name: strings.Repeat("a", 101)}},
+		{name: "negative fee", req: pricingModel.DeliveryZoneRequest{Name: "Centro", Fee: -100}},
+		{name: "negative threshold", req: pricingModel.DeliveryZoneRequest{Name: "Centro", FreeDeliveryAbove: &negative}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ValidateDeliveryZoneRequest(tt.req)
+			assertBadRequest(t, err)
+		})
+	}
+}
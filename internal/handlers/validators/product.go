@@ -1,6 +1,7 @@
 package validators
 
 import (
+	"github.com/radamesvaz/bakery-app/model/money"
 	pModel "github.com/radamesvaz/bakery-app/model/products"
 )
 
@@ -18,6 +19,6 @@ func NormalizeProductStatus(status pModel.ProductStatus) (pModel.ProductStatus,
 }
 
 // IsNonNegativePrice reports whether price is allowed for create/update (>= 0).
-func IsNonNegativePrice(price float64) bool {
+func IsNonNegativePrice(price money.Amount) bool {
 	return price >= 0
 }
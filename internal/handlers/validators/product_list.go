@@ -29,8 +29,13 @@ func ParseProductSearchQuery(q string) (*string, error) {
 // ProductNameContainsLikePattern builds a LIKE/ILIKE pattern for case-insensitive contains match,
 // escaping %, _ and \ in user input.
 func ProductNameContainsLikePattern(query string) string {
+	return ContainsLikePattern(strings.ToLower(query))
+}
+
+// ContainsLikePattern builds a LIKE/ILIKE contains pattern, escaping %, _ and \ in user input.
+func ContainsLikePattern(query string) string {
 	s := strings.ReplaceAll(query, `\`, `\\`)
 	s = strings.ReplaceAll(s, `%`, `\%`)
 	s = strings.ReplaceAll(s, `_`, `\_`)
-	return "%" + strings.ToLower(s) + "%"
+	return "%" + s + "%"
 }
@@ -39,4 +39,5 @@ func TestProductNameContainsLikePattern(t *testing.T) {
 	assert.Equal(t, "%foo%", ProductNameContainsLikePattern("foo"))
 	assert.Equal(t, `%a\%b%`, ProductNameContainsLikePattern(`a%b`))
 	assert.Equal(t, `%a\_b%`, ProductNameContainsLikePattern(`a_b`))
+	assert.Equal(t, "%Ana%", ContainsLikePattern("Ana"))
 }
@@ -22,6 +22,6 @@ func TestNormalizeProductStatus(t *testing.T) {
 
 func TestIsNonNegativePrice(t *testing.T) {
 	assert.True(t, IsNonNegativePrice(0))
-	assert.True(t, IsNonNegativePrice(10.5))
-	assert.False(t, IsNonNegativePrice(-0.01))
+	assert.True(t, IsNonNegativePrice(1050))
+	assert.False(t, IsNonNegativePrice(-1))
 }
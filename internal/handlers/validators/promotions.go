@@ -0,0 +1,84 @@
+package validators
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/radamesvaz/bakery-app/internal/errors"
+	promoModel "github.com/radamesvaz/bakery-app/model/promotions"
+)
+
+var promotionCodePattern = regexp.MustCompile(`^[A-Za-z0-9_-]{3,64}$`)
+
+// ValidatePromotionRequest checks POST /auth/promotions and PUT /auth/promotions/{id} and returns
+// the promotion to store: trimmed code and description, scope defaulted to order and unique
+// product ids (cleared for order-wide promotions).
+func ValidatePromotionRequest(req promoModel.PromotionRequest) (promoModel.Promotion, error) {
+	p := promoModel.Promotion{
+		Code:               strings.TrimSpace(req.Code),
+		Description:        strings.TrimSpace(req.Description),
+		DiscountType:       req.DiscountType,
+		Value:              req.Value,
+		Scope:              req.Scope,
+		MinOrderTotal:      req.MinOrderTotal,
+		StartsAt:           req.StartsAt,
+		EndsAt:             req.EndsAt,
+		MaxUses:            req.MaxUses,
+		MaxUsesPerCustomer: req.MaxUsesPerCustomer,
+		Active:             req.Active == nil || *req.Active,
+		ProductIDs:         []uint64{},
+	}
+	if !promotionCodePattern.MatchString(p.Code) {
+		return p, errors.NewBadRequest(fmt.Errorf("'code' must be 3 to 64 letters, digits, '-' or '_'"))
+	}
+
+	switch p.DiscountType {
+	case promoModel.DiscountPercentage:
+		if p.Value <= 0 || p.Value > 100 {
+			return p, errors.NewBadRequest(fmt.Errorf("'value' of a percentage promotion must be greater than 0 and at most 100"))
+		}
+	case promoModel.DiscountFixed:
+		if p.Value <= 0 {
+			return p, errors.NewBadRequest(fmt.Errorf("'value' must be greater than 0"))
+		}
+	default:
+		return p, errors.NewBadRequest(fmt.Errorf("'discount_type' must be 'percentage' or 'fixed'"))
+	}
+
+	if p.Scope == "" {
+		p.Scope = promoModel.ScopeOrder
+	}
+	switch p.Scope {
+	case promoModel.ScopeOrder:
+	case promoModel.ScopeProducts:
+		for _, id := range req.ProductIDs {
+			if id == 0 {
+				return p, errors.NewBadRequest(fmt.Errorf("'product_ids' must contain valid product ids"))
+			}
+			if !slices.Contains(p.ProductIDs, id) {
+				p.ProductIDs = append(p.ProductIDs, id)
+			}
+		}
+		if len(p.ProductIDs) == 0 {
+			return p, errors.NewBadRequest(fmt.Errorf("'product_ids' must list at least one product when 'scope' is 'products'"))
+		}
+	default:
+		return p, errors.NewBadRequest(fmt.Errorf("'scope' must be 'order' or 'products'"))
+	}
+
+	if p.MinOrderTotal != nil && *p.MinOrderTotal < 0 {
+		return p, errors.NewBadRequest(fmt.Errorf("'min_order_total' must not be negative"))
+	}
+	if p.StartsAt != nil && p.EndsAt != nil && !p.EndsAt.After(*p.StartsAt) {
+		return p, errors.NewBadRequest(fmt.Errorf("'ends_at' must be after 'starts_at'"))
+	}
+	if p.MaxUses != nil && *p.MaxUses <= 0 {
+		return p, errors.NewBadRequest(fmt.Errorf("'max_uses' must be greater than 0"))
+	}
+	if p.MaxUsesPerCustomer != nil && *p.MaxUsesPerCustomer <= 0 {
+		return p, errors.NewBadRequest(fmt.Errorf("'max_uses_per_customer' must be greater than 0"))
+	}
+	return p, nil
+}
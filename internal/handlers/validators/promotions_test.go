@@ -0,0 +1,66 @@
+package validators
+
+import (
+	"testing"
+	"time"
+
+	"github.com/radamesvaz/bakery-app/model/money"
+	promoModel "github.com/radamesvaz/bakery-app/model/promotions"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func validPromotionRequest() promoModel.PromotionRequest {
+	return promoModel.PromotionRequest{
+		Code:         " PAN-20 ",
+		DiscountType: promoModel.DiscountPercentage,
+		Value:        20,
+		Scope:        promoModel.ScopeProducts,
+		ProductIDs:   []uint64{3, 4, 3},
+	}
+}
+
+func TestValidatePromotionRequest_Normalizes(t *testing.T) {
+	p, err := ValidatePromotionRequest(validPromotionRequest())
+	require.NoError(t, err)
+	assert.Equal(t, "PAN-20", p.Code)
+	assert.Equal(t, []uint64{3, 4}, p.ProductIDs)
+	assert.True(t, p.Active)
+
+	req := validPromotionRequest()
+	req.Scope = ""
+	p, err = ValidatePromotionRequest(req)
+	require.NoError(t, err)
+	assert.Equal(t, promoModel.ScopeOrder, p.Scope)
+	assert.Empty(t, p.ProductIDs)
+}
+
+func TestValidatePromotionRequest_Errors(t *testing.T) {
+	zero := 0
+	negative := money.Amount(-100)
+	start := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name   string
+		mutate func(r *promoModel.PromotionRequest)
+	}{
+		{name: "short code", mutate: func(r *promoModel.PromotionRequest) { r.Code = "AB" }},
+		{name: "code with spaces", mutate: func(r *promoModel.PromotionRequest) { r.Code = "PAN 20" }},
+		{name: "unknown type", mutate: func(r *promoModel.PromotionRequest) { r.DiscountType = "bogo" }},
+		{name: "zero value", mutate: func(r *promoModel.PromotionRequest) { r.Value = 0 }},
+		{name: "percentage over 100", mutate: func(r *promoModel.PromotionRequest) { r.Value = 101 }},
+		{name: "unknown scope", mutate: func(r *promoModel.PromotionRequest) { r.Scope = "category" }},
+		{name: "products scope without products", mutate: func(r *promoModel.PromotionRequest) { r.ProductIDs = nil }},
+		{name: "negative minimum", mutate: func(r *promoModel.PromotionRequest) { r.MinOrderTotal = &negative }},
+		{name: "ends before starts", mutate: func(r *promoModel.PromotionRequest) { r.StartsAt = &start; r.EndsAt = &start }},
+		{name: "zero max uses", mutate: func(r *promoModel.PromotionRequest) { r.MaxUses = &zero }},
+		{name: "zero max uses per customer", mutate: func(r *promoModel.PromotionRequest) { r.MaxUsesPerCustomer = &zero }},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := validPromotionRequest()
+			tt.mutate(&req)
+			_, err := ValidatePromotionRequest(req)
+			assertBadRequest(t, err)
+		})
+	}
+}
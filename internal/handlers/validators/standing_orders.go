@@ -0,0 +1,84 @@
+package validators
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/radamesvaz/bakery-app/internal/errors"
+	soModel "github.com/radamesvaz/bakery-app/model/standingorders"
+)
+
+// ValidateStandingOrderRequest checks POST /auth/standing-orders and PUT /auth/standing-orders/{id}
+// and returns the normalized input: trimmed text, sorted unique weekdays and one line per product.
+func ValidateStandingOrderRequest(req soModel.StandingOrderRequest) (soModel.StandingOrderInput, error) {
+	in := soModel.StandingOrderInput{
+		Name:              strings.TrimSpace(req.Name),
+		Email:             strings.TrimSpace(req.Email),
+		Phone:             strings.TrimSpace(req.Phone),
+		DeliveryDirection: strings.TrimSpace(req.DeliveryDirection),
+		Note:              strings.TrimSpace(req.Note),
+		Active:            req.Active == nil || *req.Active,
+	}
+	if in.Name == "" {
+		return in, errors.NewBadRequest(fmt.Errorf("The 'name' field is mandatory"))
+	}
+	if !IsValidEmail(in.Email) {
+		return in, errors.NewBadRequest(fmt.Errorf("The 'email' field has no valid format"))
+	}
+	if in.Phone == "" {
+		return in, errors.NewBadRequest(fmt.Errorf("The 'phone' field is mandatory"))
+	}
+	if in.DeliveryDirection == "" {
+		return in, errors.NewBadRequest(errors.ErrMissingDeliveryDirection)
+	}
+
+	if len(req.Weekdays) == 0 {
+		return in, errors.NewBadRequest(fmt.Errorf("'weekdays' must list at least one day (1 = Monday ... 7 = Sunday)"))
+	}
+	for _, d := range req.Weekdays {
+		if d < 1 || d > 7 {
+			return in, errors.NewBadRequest(fmt.Errorf("'weekdays' values must be between 1 (Monday) and 7 (Sunday)"))
+		}
+		if !slices.Contains(in.Weekdays, d) {
+			in.Weekdays = append(in.Weekdays, d)
+		}
+	}
+	slices.Sort(in.Weekdays)
+
+	start, err := ParseDeliveryDate("start_date", req.StartDate)
+	if err != nil {
+		return in, err
+	}
+	in.StartDate = start
+	if req.EndDate != nil {
+		end, err := ParseDeliveryDate("end_date", *req.EndDate)
+		if err != nil {
+			return in, err
+		}
+		if end.Before(start) {
+			return in, errors.NewBadRequest(fmt.Errorf("'end_date' must not be before 'start_date'"))
+		}
+		in.EndDate = &end
+	}
+
+	if len(req.Items) == 0 {
+		return in, errors.NewBadRequest(fmt.Errorf("An item must be sent for the standing order"))
+	}
+	indexByProduct := make(map[uint64]int, len(req.Items))
+	for _, item := range req.Items {
+		if item.IdProduct == 0 {
+			return in, errors.NewBadRequest(fmt.Errorf("Each item must have a valid 'id_product'"))
+		}
+		if item.Quantity == 0 {
+			return in, errors.NewBadRequest(fmt.Errorf("Each item must have a 'quantity' greater than 0"))
+		}
+		if idx, ok := indexByProduct[item.IdProduct]; ok {
+			in.Items[idx].Quantity += item.Quantity
+			continue
+		}
+		indexByProduct[item.IdProduct] = len(in.Items)
+		in.Items = append(in.Items, item)
+	}
+	return in, nil
+}
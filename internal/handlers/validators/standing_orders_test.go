@@ -0,0 +1,60 @@
+package validators
+
+import (
+	"testing"
+	"time"
+
+	soModel "github.com/radamesvaz/bakery-app/model/standingorders"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func validStandingOrderRequest() soModel.StandingOrderRequest {
+	return soModel.StandingOrderRequest{
+		Name:              " Cafe Central ",
+		Email:             "cafe@example.com",
+		Phone:             "555",
+		DeliveryDirection: "Main St 1",
+		Weekdays:          []int{5, 1, 3, 1},
+		StartDate:         "2026-03-01",
+		Items:             []soModel.StandingOrderItem{{IdProduct: 3, Quantity: 2}, {IdProduct: 4, Quantity: 1}, {IdProduct: 3, Quantity: 1}},
+	}
+}
+
+func TestValidateStandingOrderRequest_Normalizes(t *testing.T) {
+	in, err := ValidateStandingOrderRequest(validStandingOrderRequest())
+	require.NoError(t, err)
+	assert.Equal(t, "Cafe Central", in.Name)
+	assert.Equal(t, []int{1, 3, 5}, in.Weekdays)
+	assert.Equal(t, time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC), in.StartDate)
+	assert.Nil(t, in.EndDate)
+	assert.True(t, in.Active)
+	assert.Equal(t, []soModel.StandingOrderItem{{IdProduct: 3, Quantity: 3}, {IdProduct: 4, Quantity: 1}}, in.Items)
+}
+
+func TestValidateStandingOrderRequest_Errors(t *testing.T) {
+	endBeforeStart := "2026-02-01"
+	tests := []struct {
+		name   string
+		mutate func(r *soModel.StandingOrderRequest)
+	}{
+		{name: "missing name", mutate: func(r *soModel.StandingOrderRequest) { r.Name = " " }},
+		{name: "invalid email", mutate: func(r *soModel.StandingOrderRequest) { r.Email = "nope" }},
+		{name: "missing phone", mutate: func(r *soModel.StandingOrderRequest) { r.Phone = "" }},
+		{name: "missing delivery direction", mutate: func(r *soModel.StandingOrderRequest) { r.DeliveryDirection = "" }},
+		{name: "no weekdays", mutate: func(r *soModel.StandingOrderRequest) { r.Weekdays = nil }},
+		{name: "weekday out of range", mutate: func(r *soModel.StandingOrderRequest) { r.Weekdays = []int{0} }},
+		{name: "bad start date", mutate: func(r *soModel.StandingOrderRequest) { r.StartDate = "03/01/2026" }},
+		{name: "end before start", mutate: func(r *soModel.StandingOrderRequest) { r.EndDate = &endBeforeStart }},
+		{name: "no items", mutate: func(r *soModel.StandingOrderRequest) { r.Items = nil }},
+		{name: "zero quantity", mutate: func(r *soModel.StandingOrderRequest) { r.Items[0].Quantity = 0 }},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := validStandingOrderRequest()
+			tt.mutate(&req)
+			_, err := ValidateStandingOrderRequest(req)
+			assertBadRequest(t, err)
+		})
+	}
+}
@@ -0,0 +1,45 @@
+package validators
+
+import (
+	"fmt"
+	"net"
+	"net/url"
+	"strings"
+
+	"github.com/radamesvaz/bakery-app/internal/errors"
+	whModel "github.com/radamesvaz/bakery-app/model/webhooks"
+)
+
+// ValidateWebhookURL requires an absolute https URL whose host is not this machine or a loopback,
+// private, link-local or unspecified address. allowInsecure (local development) also accepts http
+// and internal hosts. Hostnames are re-checked on every delivery, after DNS resolution.
+func ValidateWebhookURL(raw string, allowInsecure bool) error {
+	u, err := url.Parse(strings.TrimSpace(raw))
+	if err != nil || u.Host == "" || (u.Scheme != "https" && u.Scheme != "http") {
+		return errors.NewBadRequest(fmt.Errorf("'url' must be an absolute http or https URL"))
+	}
+	if allowInsecure {
+		return nil
+	}
+	if u.Scheme != "https" {
+		return errors.NewBadRequest(fmt.Errorf("'url' must use https"))
+	}
+	host := u.Hostname()
+	if ip := net.ParseIP(host); whModel.IsLocalHostname(host) || (ip != nil && !whModel.IsDeliverableIP(ip)) {
+		return errors.NewBadRequest(fmt.Errorf("'url' must point to a public host"))
+	}
+	return nil
+}
+
+// ValidateWebhookEventTypes requires at least one event type and only known ones.
+func ValidateWebhookEventTypes(types []whModel.EventType) error {
+	if len(types) == 0 {
+		return errors.NewBadRequest(fmt.Errorf("'event_types' must contain at least one event"))
+	}
+	for _, t := range types {
+		if !whModel.IsValidEventType(t) {
+			return errors.NewBadRequest(fmt.Errorf("unknown event type '%s'", t))
+		}
+	}
+	return nil
+}
@@ -0,0 +1,45 @@
+package validators
+
+import (
+	"testing"
+
+	whModel "github.com/radamesvaz/bakery-app/model/webhooks"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidateWebhookURL(t *testing.T) {
+	assert.NoError(t, ValidateWebhookURL("https://example.com/hooks", false))
+	assert.NoError(t, ValidateWebhookURL("https://93.184.216.34/hooks", false))
+	assert.Error(t, ValidateWebhookURL("", false))
+	assert.Error(t, ValidateWebhookURL("/relative/path", false))
+	assert.Error(t, ValidateWebhookURL("ftp://example.com", false))
+	assert.Error(t, ValidateWebhookURL("http://example.com/hooks", false))
+
+	for _, internal := range []string{
+		"https://localhost/hook",
+		"https://api.localhost/hook",
+		"https://127.0.0.1/hook",
+		"https://10.0.0.5/hook",
+		"https://172.16.3.4/hook",
+		"https://192.168.1.10/hook",
+		"https://169.254.169.254/latest/meta-data",
+		"https://0.0.0.0/hook",
+		"https://[::1]/hook",
+		"https://[fd00::1]/hook",
+		"https://[fe80::1]/hook",
+		"https://[::ffff:127.0.0.1]/hook",
+	} {
+		assert.Error(t, ValidateWebhookURL(internal, false), internal)
+	}
+
+	// Local development may point webhooks at plain http services on this machine.
+	assert.NoError(t, ValidateWebhookURL("http://localhost:9000/hook", true))
+	assert.NoError(t, ValidateWebhookURL("http://10.0.0.5/hook", true))
+	assert.Error(t, ValidateWebhookURL("ftp://example.com", true))
+}
+
+func TestValidateWebhookEventTypes(t *testing.T) {
+	assert.NoError(t, ValidateWebhookEventTypes([]whModel.EventType{whModel.EventOrderCreated, whModel.EventProductOutOfStock}))
+	assert.Error(t, ValidateWebhookEventTypes(nil))
+	assert.Error(t, ValidateWebhookEventTypes([]whModel.EventType{"order.shipped"}))
+}
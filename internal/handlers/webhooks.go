@@ -0,0 +1,254 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gorilla/mux"
+	"github.com/radamesvaz/bakery-app/internal/handlers/validators"
+	"github.com/radamesvaz/bakery-app/internal/logger"
+	"github.com/radamesvaz/bakery-app/internal/middleware"
+	"github.com/radamesvaz/bakery-app/internal/pagination"
+	webhooksRepository "github.com/radamesvaz/bakery-app/internal/repository/webhooks"
+	webhooksService "github.com/radamesvaz/bakery-app/internal/services/webhooks"
+	whModel "github.com/radamesvaz/bakery-app/model/webhooks"
+)
+
+type WebhookHandler struct {
+	Repo *webhooksRepository.Repository
+	// AllowInsecureURLs accepts http and internal subscription URLs (local development only).
+	AllowInsecureURLs bool
+}
+
+type webhookSubscriptionsListResponse struct {
+	Items []whModel.Subscription `json:"items"`
+}
+
+type webhookDeliveriesListResponse struct {
+	Items      []whModel.Delivery `json:"items"`
+	NextCursor *string            `json:"next_cursor"`
+}
+
+func parseWebhookID(w http.ResponseWriter, r *http.Request) (uint64, bool) {
+	id, err := strconv.ParseUint(mux.Vars(r)["id"], 10, 64)
+	if err != nil || id == 0 {
+		http.Error(w, "Invalid webhook ID", http.StatusBadRequest)
+		return 0, false
+	}
+	return id, true
+}
+
+// ListSubscriptions returns the tenant's webhook subscriptions (GET /auth/webhooks). Secrets are not included.
+func (h *WebhookHandler) ListSubscriptions(w http.ResponseWriter, r *http.Request) {
+	tenantID, ok := requireTenantID(w, r)
+	if !ok {
+		return
+	}
+
+	subs, err := h.Repo.ListSubscriptions(r.Context(), tenantID)
+	if err != nil {
+		writeRepoError(w, err, "Failed to get webhooks")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(webhookSubscriptionsListResponse{Items: subs})
+}
+
+// GetSubscription returns one webhook subscription (GET /auth/webhooks/{id}).
+func (h *WebhookHandler) GetSubscription(w http.ResponseWriter, r *http.Request) {
+	id, ok := parseWebhookID(w, r)
+	if !ok {
+		return
+	}
+	tenantID, ok := requireTenantID(w, r)
+	if !ok {
+		return
+	}
+
+	sub, err := h.Repo.GetSubscription(r.Context(), tenantID, id)
+	if err != nil {
+		writeRepoError(w, err, "Failed to get webhook")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(sub)
+}
+
+// CreateSubscription registers an endpoint for the given events (POST /auth/webhooks).
+// The signing secret is generated server-side and only returned in this response.
+func (h *WebhookHandler) CreateSubscription(w http.ResponseWriter, r *http.Request) {
+	var req whModel.CreateSubscriptionRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+	req.URL = strings.TrimSpace(req.URL)
+	if err := validators.ValidateWebhookURL(req.URL, h.AllowInsecureURLs); err != nil {
+		writeRepoError(w, err, err.Error())
+		return
+	}
+	if err := validators.ValidateWebhookEventTypes(req.EventTypes); err != nil {
+		writeRepoError(w, err, err.Error())
+		return
+	}
+
+	ctx := r.Context()
+	tenantID, ok := requireTenantID(w, r)
+	if !ok {
+		return
+	}
+	userID, err := middleware.GetUserIDFromContext(ctx)
+	if err != nil {
+		http.Error(w, "Unauthorized: invalid token", http.StatusUnauthorized)
+		return
+	}
+
+	secret, err := webhooksService.NewSecret()
+	if err != nil {
+		logger.Err(err).Msg("Error generating webhook secret")
+		http.Error(w, "Failed to create webhook", http.StatusInternalServerError)
+		return
+	}
+
+	active := true
+	if req.Active != nil {
+		active = *req.Active
+	}
+
+	sub, err := h.Repo.CreateSubscription(ctx, tenantID, req.URL, secret, req.EventTypes, active, userID)
+	if err != nil {
+		writeRepoError(w, err, "Failed to create webhook")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(sub)
+}
+
+// UpdateSubscription changes url, event types and/or active flag (PATCH /auth/webhooks/{id}).
+func (h *WebhookHandler) UpdateSubscription(w http.ResponseWriter, r *http.Request) {
+	id, ok := parseWebhookID(w, r)
+	if !ok {
+		return
+	}
+
+	var req whModel.UpdateSubscriptionRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+	if req.URL == nil && req.EventTypes == nil && req.Active == nil {
+		http.Error(w, "At least one field (url, event_types or active) must be provided", http.StatusBadRequest)
+		return
+	}
+
+	ctx := r.Context()
+	tenantID, ok := requireTenantID(w, r)
+	if !ok {
+		return
+	}
+
+	current, err := h.Repo.GetSubscription(ctx, tenantID, id)
+	if err != nil {
+		writeRepoError(w, err, "Failed to get webhook")
+		return
+	}
+
+	url := current.URL
+	if req.URL != nil {
+		url = strings.TrimSpace(*req.URL)
+		if err := validators.ValidateWebhookURL(url, h.AllowInsecureURLs); err != nil {
+			writeRepoError(w, err, err.Error())
+			return
+		}
+	}
+	eventTypes := current.EventTypes
+	if req.EventTypes != nil {
+		if err := validators.ValidateWebhookEventTypes(req.EventTypes); err != nil {
+			writeRepoError(w, err, err.Error())
+			return
+		}
+		eventTypes = req.EventTypes
+	}
+	active := current.Active
+	if req.Active != nil {
+		active = *req.Active
+	}
+
+	sub, err := h.Repo.UpdateSubscription(ctx, tenantID, id, url, eventTypes, active)
+	if err != nil {
+		writeRepoError(w, err, "Failed to update webhook")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(sub)
+}
+
+// DeleteSubscription removes a subscription and its delivery log (DELETE /auth/webhooks/{id}).
+func (h *WebhookHandler) DeleteSubscription(w http.ResponseWriter, r *http.Request) {
+	id, ok := parseWebhookID(w, r)
+	if !ok {
+		return
+	}
+	tenantID, ok := requireTenantID(w, r)
+	if !ok {
+		return
+	}
+
+	if err := h.Repo.DeleteSubscription(r.Context(), tenantID, id); err != nil {
+		writeRepoError(w, err, "Failed to delete webhook")
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// ListDeliveries returns the delivery log of a subscription, newest first (GET /auth/webhooks/{id}/deliveries).
+// Query: limit, cursor.
+func (h *WebhookHandler) ListDeliveries(w http.ResponseWriter, r *http.Request) {
+	id, ok := parseWebhookID(w, r)
+	if !ok {
+		return
+	}
+	ctx := r.Context()
+	tenantID, ok := requireTenantID(w, r)
+	if !ok {
+		return
+	}
+
+	limit, err := validators.ParseListLimit(r.URL.Query().Get("limit"))
+	if err != nil {
+		writeRepoError(w, err, err.Error())
+		return
+	}
+
+	var afterID *uint64
+	if c := r.URL.Query().Get("cursor"); c != "" {
+		cursorID, err := pagination.DecodeIDCursor(c)
+		if err != nil {
+			http.Error(w, "Invalid cursor", http.StatusBadRequest)
+			return
+		}
+		afterID = &cursorID
+	}
+
+	if _, err := h.Repo.GetSubscription(ctx, tenantID, id); err != nil {
+		writeRepoError(w, err, "Failed to get webhook")
+		return
+	}
+
+	page, err := h.Repo.ListDeliveriesPage(ctx, tenantID, id, limit, afterID)
+	if err != nil {
+		http.Error(w, "Failed to get webhook deliveries", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(webhookDeliveriesListResponse{Items: page.Items, NextCursor: page.NextCursor})
+}
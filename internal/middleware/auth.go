@@ -201,6 +201,25 @@ func RequireAdminRole() func(http.Handler) http.Handler {
 	}
 }
 
+// RejectClientRole answers 403 to customer sessions (UserRoleClient), which may only use the
+// /t/{tenant_slug}/me endpoints; staff roles pass through.
+func RejectClientRole() func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			roleID, err := GetUserRoleFromContext(r.Context())
+			if err != nil {
+				http.Error(w, "Unauthorized: invalid token role", http.StatusUnauthorized)
+				return
+			}
+			if roleID == uint64(uModel.UserRoleClient) {
+				http.Error(w, "Forbidden", http.StatusForbidden)
+				return
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
 func GetTenantIDFromContext(ctx context.Context) (uint64, error) {
 	tenantID, ok := ctx.Value(TenantIDKey).(uint64)
 	if !ok {
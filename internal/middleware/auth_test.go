@@ -285,3 +285,31 @@ func TestRequireAdminRole_RejectsClient(t *testing.T) {
 	h.ServeHTTP(rr, req)
 	assert.Equal(t, http.StatusForbidden, rr.Code)
 }
+
+func TestRejectClientRole(t *testing.T) {
+	mw := RejectClientRole()
+	h := mw(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+
+	tests := []struct {
+		name   string
+		claims jwt.MapClaims
+		want   int
+	}{
+		{name: "admin", claims: jwt.MapClaims{"user_id": float64(1), "role_id": float64(1)}, want: http.StatusOK},
+		{name: "superadmin", claims: jwt.MapClaims{"user_id": float64(1), "role_id": float64(3)}, want: http.StatusOK},
+		{name: "client", claims: jwt.MapClaims{"user_id": float64(2), "role_id": float64(2)}, want: http.StatusForbidden},
+		{name: "no role", claims: jwt.MapClaims{"user_id": float64(2)}, want: http.StatusUnauthorized},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/auth/orders", nil)
+			req = req.WithContext(context.WithValue(req.Context(), UserClaimsKey, tt.claims))
+			rr := httptest.NewRecorder()
+			h.ServeHTTP(rr, req)
+			assert.Equal(t, tt.want, rr.Code)
+		})
+	}
+}
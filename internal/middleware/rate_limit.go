@@ -67,8 +67,37 @@ func (rl *InMemoryRateLimiter) Middleware(opts RateLimitOptions) func(http.Handl
 	}
 }
 
+// KeyedRateLimit limits requests per key the handler reads from the request (e.g. the customer
+// email of an order) instead of per client IP.
+type KeyedRateLimit struct {
+	limiter *InMemoryRateLimiter
+	opts    RateLimitOptions
+}
+
+// Keyed returns a KeyedRateLimit that shares the limiter's buckets. ScopeTenant and ScopeUser
+// work as in Middleware.
+func (rl *InMemoryRateLimiter) Keyed(opts RateLimitOptions) *KeyedRateLimit {
+	return &KeyedRateLimit{limiter: rl, opts: opts}
+}
+
+// Allow counts a request for key and reports whether it is within the limit; when it is not,
+// retryAfter is the time left until the window resets. An empty key or a disabled limit always
+// passes.
+func (k *KeyedRateLimit) Allow(r *http.Request, key string) (allowed bool, retryAfter time.Duration) {
+	key = strings.TrimSpace(key)
+	if k == nil || key == "" || k.opts.MaxRequests <= 0 || k.opts.Window <= 0 {
+		return true, 0
+	}
+	return k.limiter.allow(k.limiter.scopedKey(r, k.opts, "key:"+key), k.opts.MaxRequests, k.opts.Window)
+}
+
 func (rl *InMemoryRateLimiter) buildKey(r *http.Request, opts RateLimitOptions) string {
-	parts := []string{strings.TrimSpace(opts.Name), clientIP(r)}
+	return rl.scopedKey(r, opts, clientIP(r))
+}
+
+// scopedKey builds the bucket key of subject under opts.Name and the requested scopes.
+func (rl *InMemoryRateLimiter) scopedKey(r *http.Request, opts RateLimitOptions, subject string) string {
+	parts := []string{strings.TrimSpace(opts.Name), subject}
 
 	if opts.ScopeTenant {
 		if tenantID, ok := r.Context().Value(TenantIDKey).(uint64); ok && tenantID > 0 {
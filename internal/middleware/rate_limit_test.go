@@ -70,3 +70,32 @@ func TestInMemoryRateLimiter_Middleware_ScopesByTenant(t *testing.T) {
 	h.ServeHTTP(rr2, req2)
 	require.Equal(t, http.StatusOK, rr2.Code)
 }
+
+func TestKeyedRateLimit_Allow_LimitsPerKeyAcrossIPs(t *testing.T) {
+	limiter := NewInMemoryRateLimiter()
+	byEmail := limiter.Keyed(RateLimitOptions{
+		Name:        "order_create_email",
+		MaxRequests: 1,
+		Window:      time.Minute,
+		ScopeTenant: true,
+	})
+
+	newRequest := func(ip string) *http.Request {
+		req := httptest.NewRequest(http.MethodPost, "/t/acme/orders", nil)
+		req.RemoteAddr = ip + ":4000"
+		return req.WithContext(context.WithValue(req.Context(), TenantIDKey, uint64(1)))
+	}
+
+	allowed, _ := byEmail.Allow(newRequest("10.0.0.1"), "ana@example.com")
+	assert.True(t, allowed)
+
+	allowed, retryAfter := byEmail.Allow(newRequest("10.0.0.2"), "ana@example.com")
+	assert.False(t, allowed)
+	assert.Greater(t, retryAfter, time.Duration(0))
+
+	allowed, _ = byEmail.Allow(newRequest("10.0.0.2"), "luis@example.com")
+	assert.True(t, allowed)
+
+	allowed, _ = byEmail.Allow(newRequest("10.0.0.2"), "")
+	assert.True(t, allowed, "an empty key is never limited")
+}
@@ -0,0 +1,79 @@
+package pagination
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+)
+
+const (
+	orderDeliveryCursorVersion = 1
+	orderDeliveryCursorKind    = "delivery_date"
+)
+
+type orderDeliveryCursorPayload struct {
+	V    int    `json:"v"`
+	Kind string `json:"k"`
+	ID   uint64 `json:"id"`
+	Date string `json:"d"` // YYYY-MM-DD, empty when the order has no delivery date
+}
+
+// OrderDeliveryKeyset marks a position in the orders list ordered by delivery_date ASC NULLS LAST,
+// id_order ASC. A nil DeliveryDate is an order without a delivery date (the tail of the list).
+type OrderDeliveryKeyset struct {
+	DeliveryDate *time.Time
+	ID           uint64
+}
+
+// EncodeOrderDeliveryCursor builds the opaque cursor for the last visible order on a page sorted by
+// delivery date. Its payload carries a kind, so it is never accepted as a creation order cursor.
+func EncodeOrderDeliveryCursor(deliveryDate *time.Time, id uint64) (string, error) {
+	if id == 0 {
+		return "", errors.New("pagination: invalid order cursor id")
+	}
+	p := orderDeliveryCursorPayload{V: orderDeliveryCursorVersion, Kind: orderDeliveryCursorKind, ID: id}
+	if deliveryDate != nil {
+		p.Date = deliveryDate.Format(time.DateOnly)
+	}
+	b, err := json.Marshal(p)
+	if err != nil {
+		return "", fmt.Errorf("pagination: encode order delivery cursor: %w", err)
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+// DecodeOrderDeliveryCursor parses a cursor from EncodeOrderDeliveryCursor (version 1 only).
+func DecodeOrderDeliveryCursor(s string) (OrderDeliveryKeyset, error) {
+	var zero OrderDeliveryKeyset
+	if s == "" {
+		return zero, errors.New("pagination: empty cursor")
+	}
+	raw, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return zero, fmt.Errorf("pagination: invalid order cursor encoding: %w", err)
+	}
+	var p orderDeliveryCursorPayload
+	if err := json.Unmarshal(raw, &p); err != nil {
+		return zero, fmt.Errorf("pagination: invalid order cursor payload: %w", err)
+	}
+	if p.Kind != orderDeliveryCursorKind {
+		return zero, errors.New("pagination: cursor is not a delivery date cursor")
+	}
+	if p.V != orderDeliveryCursorVersion {
+		return zero, fmt.Errorf("pagination: unsupported order delivery cursor version %d", p.V)
+	}
+	if p.ID == 0 {
+		return zero, errors.New("pagination: invalid order cursor id")
+	}
+	k := OrderDeliveryKeyset{ID: p.ID}
+	if p.Date != "" {
+		d, err := time.Parse(time.DateOnly, p.Date)
+		if err != nil {
+			return zero, fmt.Errorf("pagination: invalid order cursor date: %w", err)
+		}
+		k.DeliveryDate = &d
+	}
+	return k, nil
+}
@@ -0,0 +1,44 @@
+package pagination
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEncodeDecodeOrderDeliveryCursor_RoundTrip(t *testing.T) {
+	d := time.Date(2026, 3, 5, 0, 0, 0, 0, time.UTC)
+	s, err := EncodeOrderDeliveryCursor(&d, 9)
+	require.NoError(t, err)
+
+	k, err := DecodeOrderDeliveryCursor(s)
+	require.NoError(t, err)
+	assert.Equal(t, uint64(9), k.ID)
+	require.NotNil(t, k.DeliveryDate)
+	assert.True(t, k.DeliveryDate.Equal(d))
+}
+
+func TestEncodeDecodeOrderDeliveryCursor_NoDeliveryDate(t *testing.T) {
+	s, err := EncodeOrderDeliveryCursor(nil, 4)
+	require.NoError(t, err)
+
+	k, err := DecodeOrderDeliveryCursor(s)
+	require.NoError(t, err)
+	assert.Equal(t, uint64(4), k.ID)
+	assert.Nil(t, k.DeliveryDate)
+}
+
+func TestOrderCursors_AreNotInterchangeable(t *testing.T) {
+	created, err := EncodeOrderCursor(time.Now(), 2)
+	require.NoError(t, err)
+	_, err = DecodeOrderDeliveryCursor(created)
+	assert.Error(t, err)
+
+	d := time.Date(2026, 3, 5, 0, 0, 0, 0, time.UTC)
+	delivery, err := EncodeOrderDeliveryCursor(&d, 2)
+	require.NoError(t, err)
+	_, err = DecodeOrderCursor(delivery)
+	assert.Error(t, err)
+}
@@ -0,0 +1,127 @@
+package analytics
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	aModel "github.com/radamesvaz/bakery-app/model/analytics"
+	uModel "github.com/radamesvaz/bakery-app/model/users"
+)
+
+// Repository runs the tenant-scoped analytics queries. Every query takes a [from, to) range on
+// orders.created_on, served by idx_orders_tenant_created_on_id.
+type Repository struct {
+	DB *sql.DB
+}
+
+// salesFilter keeps the orders that count as sales.
+const salesFilter = `o.status NOT IN ('cancelled', 'expired', 'deleted')`
+
+// GetRevenue returns paid and unpaid totals per interval bucket. Buckets without orders are omitted.
+func (r *Repository) GetRevenue(ctx context.Context, tenantID uint64, from, to time.Time, interval aModel.Interval) ([]aModel.RevenueRow, error) {
+	rows, err := r.DB.QueryContext(ctx,
+		`SELECT
+	date_trunc($4, o.created_on) AS period_start,
+	COUNT(*) FILTER (WHERE o.paid),
+	COALESCE(SUM(o.total_price) FILTER (WHERE o.paid), 0),
+	COUNT(*) FILTER (WHERE NOT COALESCE(o.paid, false)),
+	COALESCE(SUM(o.total_price) FILTER (WHERE NOT COALESCE(o.paid, false)), 0)
+FROM orders o
+WHERE o.tenant_id = $1 AND o.created_on >= $2 AND o.created_on < $3 AND `+salesFilter+`
+GROUP BY period_start
+ORDER BY period_start`,
+		tenantID, from, to, string(interval),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("get revenue: %w", err)
+	}
+	defer rows.Close()
+
+	var out []aModel.RevenueRow
+	for rows.Next() {
+		var row aModel.RevenueRow
+		if err := rows.Scan(&row.PeriodStart, &row.PaidOrders, &row.PaidRevenue, &row.UnpaidOrders, &row.UnpaidRevenue); err != nil {
+			return nil, fmt.Errorf("scan revenue row: %w", err)
+		}
+		out = append(out, row)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate revenue rows: %w", err)
+	}
+	return out, nil
+}
+
+// GetTopProducts returns the best products by units or revenue from the order line snapshots,
+// named after their most recent snapshot.
+func (r *Repository) GetTopProducts(ctx context.Context, tenantID uint64, from, to time.Time, sortBy aModel.TopProductsSort, limit int) ([]aModel.TopProduct, error) {
+	orderBy := "units DESC, revenue DESC"
+	if sortBy == aModel.SortByRevenue {
+		orderBy = "revenue DESC, units DESC"
+	}
+	rows, err := r.DB.QueryContext(ctx,
+		`SELECT
+	oi.id_product,
+	COALESCE((array_agg(oi.product_name_snapshot ORDER BY o.created_on DESC, o.id_order DESC))[1], '') AS name,
+	SUM(oi.quantity) AS units,
+	COALESCE(SUM(oi.quantity * oi.unit_price_snapshot), 0) AS revenue,
+	COUNT(DISTINCT o.id_order)
+FROM orders o
+INNER JOIN order_items oi ON oi.tenant_id = o.tenant_id AND oi.id_order = o.id_order
+WHERE o.tenant_id = $1 AND o.created_on >= $2 AND o.created_on < $3 AND `+salesFilter+`
+GROUP BY oi.id_product
+ORDER BY `+orderBy+`, oi.id_product
+LIMIT $4`,
+		tenantID, from, to, limit,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("get top products: %w", err)
+	}
+	defer rows.Close()
+
+	out := []aModel.TopProduct{}
+	for rows.Next() {
+		var p aModel.TopProduct
+		if err := rows.Scan(&p.IDProduct, &p.Name, &p.Units, &p.Revenue, &p.Orders); err != nil {
+			return nil, fmt.Errorf("scan top product: %w", err)
+		}
+		out = append(out, p)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate top products: %w", err)
+	}
+	return out, nil
+}
+
+// GetCancellationCounts classifies the closed orders of the range by their latest cancelled or
+// expired history row: expired rows come from ExpiredOrderCanceller, cancelled rows are split by
+// the role of modified_by (admins vs the customer or other client users).
+func (r *Repository) GetCancellationCounts(ctx context.Context, tenantID uint64, from, to time.Time) (aModel.CancellationCounts, error) {
+	var counts aModel.CancellationCounts
+	err := r.DB.QueryRowContext(ctx,
+		`WITH scoped AS (
+	SELECT o.id_order
+	FROM orders o
+	WHERE o.tenant_id = $1 AND o.created_on >= $2 AND o.created_on < $3 AND o.status <> 'deleted'
+), closing AS (
+	SELECT DISTINCT ON (oh.id_order) oh.id_order, oh.status, oh.modified_by
+	FROM orders_history oh
+	INNER JOIN scoped s ON s.id_order = oh.id_order
+	WHERE oh.tenant_id = $1 AND oh.status IN ('cancelled', 'expired')
+	ORDER BY oh.id_order, oh.modified_on DESC, oh.id_order_history DESC
+)
+SELECT
+	(SELECT COUNT(*) FROM scoped),
+	COUNT(*) FILTER (WHERE c.status = 'cancelled' AND u.id_role IN ($4, $5)),
+	COUNT(*) FILTER (WHERE c.status = 'cancelled' AND (u.id_role IS NULL OR u.id_role NOT IN ($4, $5))),
+	COUNT(*) FILTER (WHERE c.status = 'expired')
+FROM closing c
+LEFT JOIN users u ON u.id_user = c.modified_by`,
+		tenantID, from, to, int(uModel.UserRoleAdmin), int(uModel.UserRoleSuperAdmin),
+	).Scan(&counts.TotalOrders, &counts.CancelledByAdmin, &counts.CancelledByCustomer, &counts.Expired)
+	if err != nil {
+		return aModel.CancellationCounts{}, fmt.Errorf("get cancellation counts: %w", err)
+	}
+	return counts, nil
+}
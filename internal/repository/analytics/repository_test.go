@@ -0,0 +1,75 @@
+package analytics
+
+import (
+	"context"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	aModel "github.com/radamesvaz/bakery-app/model/analytics"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var (
+	rangeFrom = time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+	rangeTo   = time.Date(2026, 4, 1, 0, 0, 0, 0, time.UTC)
+)
+
+func TestRepository_GetRevenue(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	repo := &Repository{DB: db}
+
+	mock.ExpectQuery(regexp.QuoteMeta(`date_trunc($4, o.created_on) AS period_start`)).
+		WithArgs(uint64(1), rangeFrom, rangeTo, "week").
+		WillReturnRows(sqlmock.NewRows([]string{"period_start", "paid_orders", "paid_revenue", "unpaid_orders", "unpaid_revenue"}).
+			AddRow(rangeFrom, 2, 30.0, 1, 12.5))
+
+	rows, err := repo.GetRevenue(context.Background(), 1, rangeFrom, rangeTo, aModel.IntervalWeek)
+	require.NoError(t, err)
+	require.Len(t, rows, 1)
+	assert.Equal(t, aModel.RevenueRow{PeriodStart: rangeFrom, PaidOrders: 2, PaidRevenue: 3000, UnpaidOrders: 1, UnpaidRevenue: 1250}, rows[0])
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestRepository_GetTopProducts_SortByRevenue(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	repo := &Repository{DB: db}
+
+	mock.ExpectQuery(regexp.QuoteMeta(`ORDER BY revenue DESC, units DESC, oi.id_product
+LIMIT $4`)).
+		WithArgs(uint64(1), rangeFrom, rangeTo, 5).
+		WillReturnRows(sqlmock.NewRows([]string{"id_product", "name", "units", "revenue", "orders"}).
+			AddRow(7, "Sourdough", 12, 96.0, 9))
+
+	items, err := repo.GetTopProducts(context.Background(), 1, rangeFrom, rangeTo, aModel.SortByRevenue, 5)
+	require.NoError(t, err)
+	require.Len(t, items, 1)
+	assert.Equal(t, "Sourdough", items[0].Name)
+	assert.Equal(t, uint64(12), items[0].Units)
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestRepository_GetCancellationCounts(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	repo := &Repository{DB: db}
+
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT DISTINCT ON (oh.id_order) oh.id_order, oh.status, oh.modified_by`)).
+		WithArgs(uint64(1), rangeFrom, rangeTo, 1, 3).
+		WillReturnRows(sqlmock.NewRows([]string{"total", "admin", "customer", "expired"}).AddRow(40, 3, 2, 5))
+
+	counts, err := repo.GetCancellationCounts(context.Background(), 1, rangeFrom, rangeTo)
+	require.NoError(t, err)
+	assert.Equal(t, aModel.CancellationCounts{TotalOrders: 40, CancelledByAdmin: 3, CancelledByCustomer: 2, Expired: 5}, counts)
+	require.NoError(t, mock.ExpectationsWereMet())
+}
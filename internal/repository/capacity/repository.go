@@ -0,0 +1,258 @@
+package capacity
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/radamesvaz/bakery-app/internal/errors"
+	capModel "github.com/radamesvaz/bakery-app/model/capacity"
+)
+
+type Repository struct {
+	DB *sql.DB
+}
+
+type queryer interface {
+	QueryRowContext(ctx context.Context, query string, args ...any) *sql.Row
+}
+
+// queryerFrom returns tx when non-nil so reads see the caller's locks.
+func (r *Repository) queryerFrom(tx *sql.Tx) queryer {
+	if tx != nil {
+		return tx
+	}
+	return r.DB
+}
+
+// activeOrderFilter excludes orders that no longer take kitchen capacity.
+const activeOrderFilter = `o.status NOT IN ('cancelled', 'expired', 'deleted')`
+
+func nullIntPtr(v sql.NullInt64) *int {
+	if !v.Valid {
+		return nil
+	}
+	n := int(v.Int64)
+	return &n
+}
+
+func intPtrArg(v *int) sql.NullInt64 {
+	if v == nil {
+		return sql.NullInt64{}
+	}
+	return sql.NullInt64{Int64: int64(*v), Valid: true}
+}
+
+// GetSettings returns the tenant's default limits; a tenant without a row is unlimited.
+func (r *Repository) GetSettings(ctx context.Context, tenantID uint64) (capModel.Settings, error) {
+	return r.getSettings(ctx, nil, tenantID)
+}
+
+func (r *Repository) getSettings(ctx context.Context, tx *sql.Tx, tenantID uint64) (capModel.Settings, error) {
+	var maxOrders, maxUnits sql.NullInt64
+	var settings capModel.Settings
+	err := r.queryerFrom(tx).QueryRowContext(ctx,
+		`SELECT max_orders_per_day, max_units_per_day, min_lead_time_hours
+FROM tenant_delivery_capacity WHERE tenant_id = $1`,
+		tenantID,
+	).Scan(&maxOrders, &maxUnits, &settings.MinLeadTimeHours)
+	if err == sql.ErrNoRows {
+		return capModel.Settings{}, nil
+	}
+	if err != nil {
+		return capModel.Settings{}, fmt.Errorf("get delivery capacity: %w", err)
+	}
+	settings.MaxOrdersPerDay = nullIntPtr(maxOrders)
+	settings.MaxUnitsPerDay = nullIntPtr(maxUnits)
+	return settings, nil
+}
+
+// UpdateSettings replaces the tenant's default limits.
+func (r *Repository) UpdateSettings(ctx context.Context, tenantID uint64, settings capModel.Settings) error {
+	_, err := r.DB.ExecContext(ctx,
+		`INSERT INTO tenant_delivery_capacity (tenant_id, max_orders_per_day, max_units_per_day, min_lead_time_hours)
+VALUES ($1, $2, $3, $4)
+ON CONFLICT (tenant_id) DO UPDATE SET
+	max_orders_per_day = EXCLUDED.max_orders_per_day,
+	max_units_per_day = EXCLUDED.max_units_per_day,
+	min_lead_time_hours = EXCLUDED.min_lead_time_hours,
+	updated_on = NOW()`,
+		tenantID, intPtrArg(settings.MaxOrdersPerDay), intPtrArg(settings.MaxUnitsPerDay), settings.MinLeadTimeHours,
+	)
+	if err != nil {
+		return fmt.Errorf("update delivery capacity: %w", err)
+	}
+	return nil
+}
+
+func scanDayOverride(row interface{ Scan(dest ...any) error }) (capModel.DayOverride, error) {
+	var d capModel.DayOverride
+	var date time.Time
+	var maxOrders, maxUnits sql.NullInt64
+	var note sql.NullString
+	if err := row.Scan(&date, &d.Closed, &maxOrders, &maxUnits, &note); err != nil {
+		return capModel.DayOverride{}, err
+	}
+	d.Date = date.Format(capModel.DateLayout)
+	d.MaxOrders = nullIntPtr(maxOrders)
+	d.MaxUnits = nullIntPtr(maxUnits)
+	if note.Valid {
+		d.Note = &note.String
+	}
+	return d, nil
+}
+
+const dayOverrideColumns = `delivery_date, closed, max_orders, max_units, note`
+
+// ListDayOverrides returns the overrides between from and to (inclusive), ordered by date.
+func (r *Repository) ListDayOverrides(ctx context.Context, tenantID uint64, from, to time.Time) ([]capModel.DayOverride, error) {
+	rows, err := r.DB.QueryContext(ctx,
+		`SELECT `+dayOverrideColumns+`
+FROM tenant_delivery_days
+WHERE tenant_id = $1 AND delivery_date BETWEEN $2 AND $3
+ORDER BY delivery_date`,
+		tenantID, from, to,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("list delivery day overrides: %w", err)
+	}
+	defer rows.Close()
+
+	out := []capModel.DayOverride{}
+	for rows.Next() {
+		d, err := scanDayOverride(rows)
+		if err != nil {
+			return nil, fmt.Errorf("scan delivery day override: %w", err)
+		}
+		out = append(out, d)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate delivery day overrides: %w", err)
+	}
+	return out, nil
+}
+
+// UpsertDayOverride creates or replaces the override of one date.
+func (r *Repository) UpsertDayOverride(ctx context.Context, tenantID uint64, date time.Time, req capModel.UpsertDayOverrideRequest) (capModel.DayOverride, error) {
+	var note sql.NullString
+	if req.Note != nil {
+		note = sql.NullString{String: *req.Note, Valid: true}
+	}
+	row := r.DB.QueryRowContext(ctx,
+		`INSERT INTO tenant_delivery_days (tenant_id, delivery_date, closed, max_orders, max_units, note)
+VALUES ($1, $2, $3, $4, $5, $6)
+ON CONFLICT (tenant_id, delivery_date) DO UPDATE SET
+	closed = EXCLUDED.closed,
+	max_orders = EXCLUDED.max_orders,
+	max_units = EXCLUDED.max_units,
+	note = EXCLUDED.note,
+	updated_on = NOW()
+RETURNING `+dayOverrideColumns,
+		tenantID, date, req.Closed, intPtrArg(req.MaxOrders), intPtrArg(req.MaxUnits), note,
+	)
+	d, err := scanDayOverride(row)
+	if err != nil {
+		return capModel.DayOverride{}, fmt.Errorf("upsert delivery day override: %w", err)
+	}
+	return d, nil
+}
+
+// DeleteDayOverride removes the override of one date so the defaults apply again.
+func (r *Repository) DeleteDayOverride(ctx context.Context, tenantID uint64, date time.Time) error {
+	result, err := r.DB.ExecContext(ctx,
+		`DELETE FROM tenant_delivery_days WHERE tenant_id = $1 AND delivery_date = $2`,
+		tenantID, date,
+	)
+	if err != nil {
+		return fmt.Errorf("delete delivery day override: %w", err)
+	}
+	n, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("get rows affected: %w", err)
+	}
+	if n == 0 {
+		return errors.NewNotFound(errors.ErrDeliveryDayOverrideNotFound)
+	}
+	return nil
+}
+
+// LockDeliveryDayTx takes the row lock of a delivery day for the rest of tx. Order creations for
+// the same day wait for each other here, so the usage read afterwards includes every committed order.
+func (r *Repository) LockDeliveryDayTx(ctx context.Context, tx *sql.Tx, tenantID uint64, date time.Time) error {
+	_, err := tx.ExecContext(ctx,
+		`INSERT INTO tenant_delivery_day_locks (tenant_id, delivery_date) VALUES ($1, $2)
+ON CONFLICT (tenant_id, delivery_date) DO UPDATE SET last_booked_on = NOW()`,
+		tenantID, date,
+	)
+	if err != nil {
+		return fmt.Errorf("lock delivery day: %w", err)
+	}
+	return nil
+}
+
+// GetDayRulesTx returns the effective limits of date (defaults merged with its override).
+func (r *Repository) GetDayRulesTx(ctx context.Context, tx *sql.Tx, tenantID uint64, date time.Time) (capModel.DayRules, error) {
+	settings, err := r.getSettings(ctx, tx, tenantID)
+	if err != nil {
+		return capModel.DayRules{}, err
+	}
+	override, err := scanDayOverride(r.queryerFrom(tx).QueryRowContext(ctx,
+		`SELECT `+dayOverrideColumns+` FROM tenant_delivery_days WHERE tenant_id = $1 AND delivery_date = $2`,
+		tenantID, date,
+	))
+	if err == sql.ErrNoRows {
+		return capModel.EffectiveRules(settings, nil), nil
+	}
+	if err != nil {
+		return capModel.DayRules{}, fmt.Errorf("get delivery day override: %w", err)
+	}
+	return capModel.EffectiveRules(settings, &override), nil
+}
+
+// GetDayUsageTx counts the active orders and units booked on date.
+func (r *Repository) GetDayUsageTx(ctx context.Context, tx *sql.Tx, tenantID uint64, date time.Time) (capModel.DayUsage, error) {
+	var usage capModel.DayUsage
+	err := r.queryerFrom(tx).QueryRowContext(ctx,
+		`SELECT COUNT(DISTINCT o.id_order), COALESCE(SUM(oi.quantity), 0)
+FROM orders o
+LEFT JOIN order_items oi ON oi.tenant_id = o.tenant_id AND oi.id_order = o.id_order
+WHERE o.tenant_id = $1 AND o.delivery_date = $2 AND `+activeOrderFilter,
+		tenantID, date,
+	).Scan(&usage.Orders, &usage.Units)
+	if err != nil {
+		return capModel.DayUsage{}, fmt.Errorf("get delivery day usage: %w", err)
+	}
+	return usage, nil
+}
+
+// ListUsage returns the usage of every date between from and to (inclusive) with active orders,
+// keyed by date (YYYY-MM-DD).
+func (r *Repository) ListUsage(ctx context.Context, tenantID uint64, from, to time.Time) (map[string]capModel.DayUsage, error) {
+	rows, err := r.DB.QueryContext(ctx,
+		`SELECT o.delivery_date, COUNT(DISTINCT o.id_order), COALESCE(SUM(oi.quantity), 0)
+FROM orders o
+LEFT JOIN order_items oi ON oi.tenant_id = o.tenant_id AND oi.id_order = o.id_order
+WHERE o.tenant_id = $1 AND o.delivery_date BETWEEN $2 AND $3 AND `+activeOrderFilter+`
+GROUP BY o.delivery_date`,
+		tenantID, from, to,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("list delivery usage: %w", err)
+	}
+	defer rows.Close()
+
+	out := make(map[string]capModel.DayUsage)
+	for rows.Next() {
+		var date time.Time
+		var usage capModel.DayUsage
+		if err := rows.Scan(&date, &usage.Orders, &usage.Units); err != nil {
+			return nil, fmt.Errorf("scan delivery usage: %w", err)
+		}
+		out[date.Format(capModel.DateLayout)] = usage
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate delivery usage: %w", err)
+	}
+	return out, nil
+}
@@ -0,0 +1,109 @@
+package capacity
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"net/http"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	appErrors "github.com/radamesvaz/bakery-app/internal/errors"
+	capModel "github.com/radamesvaz/bakery-app/model/capacity"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var christmas = time.Date(2026, 12, 25, 0, 0, 0, 0, time.UTC)
+
+func TestRepository_GetSettings_NoRowIsUnlimited(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	repo := &Repository{DB: db}
+
+	mock.ExpectQuery(regexp.QuoteMeta(`FROM tenant_delivery_capacity WHERE tenant_id = $1`)).
+		WithArgs(uint64(1)).
+		WillReturnError(sql.ErrNoRows)
+
+	settings, err := repo.GetSettings(context.Background(), 1)
+	require.NoError(t, err)
+	assert.Equal(t, capModel.Settings{}, settings)
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestRepository_GetDayRulesTx_OverrideReplacesDefaults(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	repo := &Repository{DB: db}
+
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta(`INSERT INTO tenant_delivery_day_locks (tenant_id, delivery_date) VALUES ($1, $2)
+ON CONFLICT (tenant_id, delivery_date) DO UPDATE SET last_booked_on = NOW()`)).
+		WithArgs(uint64(1), christmas).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectQuery(regexp.QuoteMeta(`FROM tenant_delivery_capacity WHERE tenant_id = $1`)).
+		WithArgs(uint64(1)).
+		WillReturnRows(sqlmock.NewRows([]string{"max_orders_per_day", "max_units_per_day", "min_lead_time_hours"}).
+			AddRow(20, 200, 12))
+	mock.ExpectQuery(regexp.QuoteMeta(`FROM tenant_delivery_days WHERE tenant_id = $1 AND delivery_date = $2`)).
+		WithArgs(uint64(1), christmas).
+		WillReturnRows(sqlmock.NewRows([]string{"delivery_date", "closed", "max_orders", "max_units", "note"}).
+			AddRow(christmas, false, 60, nil, "Holiday staff"))
+	mock.ExpectCommit()
+
+	tx, err := db.Begin()
+	require.NoError(t, err)
+	require.NoError(t, repo.LockDeliveryDayTx(context.Background(), tx, 1, christmas))
+	rules, err := repo.GetDayRulesTx(context.Background(), tx, 1, christmas)
+	require.NoError(t, err)
+	require.NoError(t, tx.Commit())
+
+	require.NotNil(t, rules.MaxOrders)
+	require.NotNil(t, rules.MaxUnits)
+	assert.Equal(t, 60, *rules.MaxOrders)
+	assert.Equal(t, 200, *rules.MaxUnits)
+	assert.Equal(t, 12, rules.MinLeadTimeHours)
+	assert.False(t, rules.Closed)
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestRepository_GetDayUsageTx_CountsActiveOrders(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	repo := &Repository{DB: db}
+
+	mock.ExpectQuery(regexp.QuoteMeta(`WHERE o.tenant_id = $1 AND o.delivery_date = $2 AND o.status NOT IN ('cancelled', 'expired', 'deleted')`)).
+		WithArgs(uint64(1), christmas).
+		WillReturnRows(sqlmock.NewRows([]string{"count", "sum"}).AddRow(4, 37))
+
+	usage, err := repo.GetDayUsageTx(context.Background(), nil, 1, christmas)
+	require.NoError(t, err)
+	assert.Equal(t, capModel.DayUsage{Orders: 4, Units: 37}, usage)
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestRepository_DeleteDayOverride_NotFound(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	repo := &Repository{DB: db}
+
+	mock.ExpectExec(regexp.QuoteMeta(`DELETE FROM tenant_delivery_days WHERE tenant_id = $1 AND delivery_date = $2`)).
+		WithArgs(uint64(1), christmas).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+
+	err = repo.DeleteDayOverride(context.Background(), 1, christmas)
+	var httpErr *appErrors.HTTPError
+	require.True(t, errors.As(err, &httpErr))
+	assert.Equal(t, http.StatusNotFound, httpErr.StatusCode)
+	require.NoError(t, mock.ExpectationsWereMet())
+}
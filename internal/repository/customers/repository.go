@@ -0,0 +1,285 @@
+package customers
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/lib/pq"
+	"github.com/radamesvaz/bakery-app/internal/errors"
+	"github.com/radamesvaz/bakery-app/internal/pagination"
+	customerModel "github.com/radamesvaz/bakery-app/model/customers"
+	uModel "github.com/radamesvaz/bakery-app/model/users"
+)
+
+// Repository reads the tenant's client accounts (users with UserRoleClient) together with their
+// order stats.
+type Repository struct {
+	DB *sql.DB
+}
+
+// ListCustomersPageResult is one page of the customer directory (cursor pagination on id DESC).
+type ListCustomersPageResult struct {
+	Items      []customerModel.Customer
+	NextCursor *string
+}
+
+// customerSelect joins each live client account with its orders. Deleted orders are left out;
+// lifetime spend only counts paid orders that were not cancelled or expired.
+const customerSelect = `SELECT u.id_user, u.name, u.email, COALESCE(u.phone, ''), u.created_on,
+	COUNT(o.id_order),
+	COALESCE(SUM(o.total_price) FILTER (WHERE o.paid AND o.status NOT IN ('cancelled', 'expired')), 0),
+	MAX(o.created_on), MIN(o.created_on)
+FROM users u
+LEFT JOIN orders o ON o.id_user = u.id_user AND o.tenant_id = u.tenant_id AND o.status <> 'deleted'
+WHERE u.tenant_id = $1 AND u.id_role = $2 AND u.deleted_at IS NULL`
+
+func scanCustomer(row interface{ Scan(dest ...any) error }) (customerModel.CustomerDetail, error) {
+	var (
+		c          customerModel.CustomerDetail
+		createdOn  sql.NullTime
+		lastOrder  sql.NullTime
+		firstOrder sql.NullTime
+	)
+	if err := row.Scan(&c.ID, &c.Name, &c.Email, &c.Phone, &createdOn,
+		&c.OrderCount, &c.LifetimeSpend, &lastOrder, &firstOrder); err != nil {
+		return customerModel.CustomerDetail{}, err
+	}
+	if createdOn.Valid {
+		c.CreatedOn = createdOn.Time
+	}
+	c.LastOrderAt = timePtr(lastOrder)
+	c.FirstOrderAt = timePtr(firstOrder)
+	return c, nil
+}
+
+func timePtr(t sql.NullTime) *time.Time {
+	if !t.Valid {
+		return nil
+	}
+	v := t.Time
+	return &v
+}
+
+func idsArray(ids []uint64) pq.Int64Array {
+	out := make(pq.Int64Array, len(ids))
+	for i, id := range ids {
+		out[i] = int64(id)
+	}
+	return out
+}
+
+// ListCustomersPage returns up to limit customers, newest account first. searchPattern, when set,
+// is an ILIKE pattern matched against name, email and phone. If afterID is non-nil, only accounts
+// with id < *afterID are considered (next page).
+func (r *Repository) ListCustomersPage(ctx context.Context, tenantID uint64, searchPattern *string, limit int, afterID *uint64) (ListCustomersPageResult, error) {
+	if limit < 1 {
+		return ListCustomersPageResult{}, fmt.Errorf("limit must be at least 1")
+	}
+
+	q := customerSelect
+	args := []interface{}{tenantID, uModel.UserRoleClient}
+	argPos := 3
+	if searchPattern != nil && *searchPattern != "" {
+		q += fmt.Sprintf(" AND (u.name ILIKE $%d OR u.email ILIKE $%d OR u.phone ILIKE $%d)", argPos, argPos, argPos)
+		args = append(args, *searchPattern)
+		argPos++
+	}
+	if afterID != nil {
+		q += fmt.Sprintf(" AND u.id_user < $%d", argPos)
+		args = append(args, *afterID)
+		argPos++
+	}
+	q += fmt.Sprintf(" GROUP BY u.id_user ORDER BY u.id_user DESC LIMIT $%d", argPos)
+	args = append(args, limit+1)
+
+	items, err := r.queryCustomers(ctx, q, args...)
+	if err != nil {
+		return ListCustomersPageResult{}, err
+	}
+
+	var next *string
+	if len(items) > limit {
+		items = items[:limit]
+		cursor, err := pagination.EncodeIDCursor(items[limit-1].ID)
+		if err != nil {
+			return ListCustomersPageResult{}, err
+		}
+		next = &cursor
+	}
+	return ListCustomersPageResult{Items: items, NextCursor: next}, nil
+}
+
+// GetCustomer returns a customer of the tenant with its stats and the other client accounts that
+// share its phone number.
+func (r *Repository) GetCustomer(ctx context.Context, tenantID, id uint64) (customerModel.CustomerDetail, error) {
+	detail, err := scanCustomer(r.DB.QueryRowContext(ctx,
+		customerSelect+` AND u.id_user = $3 GROUP BY u.id_user`,
+		tenantID, uModel.UserRoleClient, id,
+	))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return customerModel.CustomerDetail{}, errors.NewNotFound(errors.ErrCustomerNotFound)
+		}
+		return customerModel.CustomerDetail{}, fmt.Errorf("get customer: %w", err)
+	}
+
+	detail.PossibleDuplicates = []customerModel.Customer{}
+	digits := strings.Map(func(r rune) rune {
+		if r >= '0' && r <= '9' {
+			return r
+		}
+		return -1
+	}, detail.Phone)
+	if digits == "" {
+		return detail, nil
+	}
+	// Phones compare by their digits, so "+58 412-555" and "58412555" match.
+	q := customerSelect + ` AND u.id_user <> $3 AND regexp_replace(COALESCE(u.phone, ''), '[^0-9]', '', 'g') = $4
+GROUP BY u.id_user ORDER BY u.id_user`
+	duplicates, err := r.queryCustomers(ctx, q, tenantID, uModel.UserRoleClient, id, digits)
+	if err != nil {
+		return customerModel.CustomerDetail{}, err
+	}
+	detail.PossibleDuplicates = duplicates
+	return detail, nil
+}
+
+func (r *Repository) queryCustomers(ctx context.Context, q string, args ...interface{}) ([]customerModel.Customer, error) {
+	rows, err := r.DB.QueryContext(ctx, q, args...)
+	if err != nil {
+		return nil, fmt.Errorf("list customers: %w", err)
+	}
+	defer rows.Close()
+
+	items := []customerModel.Customer{}
+	for rows.Next() {
+		c, err := scanCustomer(rows)
+		if err != nil {
+			return nil, fmt.Errorf("scan customer: %w", err)
+		}
+		items = append(items, c.Customer)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate customers: %w", err)
+	}
+	return items, nil
+}
+
+// MergeCustomers folds the duplicate accounts into the customer id in one transaction: their
+// orders and order history (including the changes they made, e.g. self-service cancels) move to
+// id, promotion redemptions and standing orders made with their emails use id's email, and the
+// duplicate accounts are deleted so their emails can sign up again.
+func (r *Repository) MergeCustomers(ctx context.Context, tenantID, id uint64, duplicateIDs []uint64) (customerModel.MergeResult, error) {
+	tx, err := r.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return customerModel.MergeResult{}, fmt.Errorf("begin tx: %w", err)
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	// Lock every account involved so a concurrent order cannot attach to a duplicate mid-merge.
+	rows, err := tx.QueryContext(ctx,
+		`SELECT id_user, email FROM users
+WHERE tenant_id = $1 AND id_role = $2 AND deleted_at IS NULL AND id_user = ANY($3)
+ORDER BY id_user FOR UPDATE`,
+		tenantID, uModel.UserRoleClient, idsArray(append([]uint64{id}, duplicateIDs...)),
+	)
+	if err != nil {
+		return customerModel.MergeResult{}, fmt.Errorf("lock customers: %w", err)
+	}
+	emails := map[uint64]string{}
+	for rows.Next() {
+		var (
+			userID uint64
+			email  string
+		)
+		if err := rows.Scan(&userID, &email); err != nil {
+			rows.Close()
+			return customerModel.MergeResult{}, fmt.Errorf("scan customer: %w", err)
+		}
+		emails[userID] = email
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return customerModel.MergeResult{}, fmt.Errorf("iterate customers: %w", err)
+	}
+
+	targetEmail, ok := emails[id]
+	if !ok {
+		return customerModel.MergeResult{}, errors.NewNotFound(errors.ErrCustomerNotFound)
+	}
+	duplicateEmails := make(pq.StringArray, 0, len(duplicateIDs))
+	for _, dupID := range duplicateIDs {
+		email, ok := emails[dupID]
+		if !ok {
+			return customerModel.MergeResult{}, errors.ErrCustomerMergeInvalid
+		}
+		duplicateEmails = append(duplicateEmails, strings.ToLower(email))
+	}
+
+	result := customerModel.MergeResult{MergedIDs: duplicateIDs}
+	moved, err := tx.ExecContext(ctx,
+		`UPDATE orders SET id_user = $1 WHERE tenant_id = $2 AND id_user = ANY($3)`,
+		id, tenantID, idsArray(duplicateIDs),
+	)
+	if err != nil {
+		return customerModel.MergeResult{}, fmt.Errorf("move orders: %w", err)
+	}
+	if result.OrdersMoved, err = moved.RowsAffected(); err != nil {
+		return customerModel.MergeResult{}, fmt.Errorf("get rows affected: %w", err)
+	}
+
+	moved, err = tx.ExecContext(ctx,
+		`UPDATE orders_history SET id_user = $1 WHERE tenant_id = $2 AND id_user = ANY($3)`,
+		id, tenantID, idsArray(duplicateIDs),
+	)
+	if err != nil {
+		return customerModel.MergeResult{}, fmt.Errorf("move order history: %w", err)
+	}
+	if result.HistoryMoved, err = moved.RowsAffected(); err != nil {
+		return customerModel.MergeResult{}, fmt.Errorf("get rows affected: %w", err)
+	}
+
+	// modified_by has no foreign key, so rows the duplicates wrote must be re-pointed explicitly.
+	if _, err := tx.ExecContext(ctx,
+		`UPDATE orders_history SET modified_by = $1 WHERE tenant_id = $2 AND modified_by = ANY($3)`,
+		id, tenantID, idsArray(duplicateIDs),
+	); err != nil {
+		return customerModel.MergeResult{}, fmt.Errorf("move order history authorship: %w", err)
+	}
+
+	if _, err := tx.ExecContext(ctx,
+		`UPDATE promotion_redemptions SET email = $1 WHERE tenant_id = $2 AND LOWER(email) = ANY($3)`,
+		targetEmail, tenantID, duplicateEmails,
+	); err != nil {
+		return customerModel.MergeResult{}, fmt.Errorf("move promotion redemptions: %w", err)
+	}
+
+	// Standing orders find their customer by email; left alone they would recreate the duplicate.
+	if _, err := tx.ExecContext(ctx,
+		`UPDATE standing_orders SET email = $1 WHERE tenant_id = $2 AND LOWER(email) = ANY($3)`,
+		targetEmail, tenantID, duplicateEmails,
+	); err != nil {
+		return customerModel.MergeResult{}, fmt.Errorf("move standing orders: %w", err)
+	}
+
+	if _, err := tx.ExecContext(ctx,
+		`DELETE FROM users WHERE tenant_id = $1 AND id_user = ANY($2)`,
+		tenantID, idsArray(duplicateIDs),
+	); err != nil {
+		return customerModel.MergeResult{}, fmt.Errorf("delete duplicate customers: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return customerModel.MergeResult{}, fmt.Errorf("commit tx: %w", err)
+	}
+
+	detail, err := r.GetCustomer(ctx, tenantID, id)
+	if err != nil {
+		return customerModel.MergeResult{}, err
+	}
+	result.Customer = detail.Customer
+	return result, nil
+}
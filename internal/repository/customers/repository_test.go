@@ -0,0 +1,175 @@
+package customers
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/lib/pq"
+	appErrors "github.com/radamesvaz/bakery-app/internal/errors"
+	"github.com/radamesvaz/bakery-app/internal/pagination"
+	"github.com/radamesvaz/bakery-app/model/money"
+	uModel "github.com/radamesvaz/bakery-app/model/users"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var (
+	createdOn = time.Date(2026, 2, 1, 9, 0, 0, 0, time.UTC)
+	lastOrder = time.Date(2026, 3, 4, 15, 30, 0, 0, time.UTC)
+)
+
+func customerRows() *sqlmock.Rows {
+	return sqlmock.NewRows([]string{"id_user", "name", "email", "phone", "created_on", "count", "spend", "max", "min"})
+}
+
+func TestRepository_ListCustomersPage(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	repo := &Repository{DB: db}
+	pattern := "%0414%"
+	afterID := uint64(50)
+	mock.ExpectQuery(regexp.QuoteMeta(`AND (u.name ILIKE $3 OR u.email ILIKE $3 OR u.phone ILIKE $3) AND u.id_user < $4 GROUP BY u.id_user ORDER BY u.id_user DESC LIMIT $5`)).
+		WithArgs(uint64(1), uModel.UserRoleClient, pattern, afterID, 3).
+		WillReturnRows(customerRows().
+			AddRow(12, "Ana", "ana@example.com", "0414-555", createdOn, 3, 42.5, lastOrder, createdOn).
+			AddRow(11, "Luis", "luis@example.com", "0414-777", createdOn, 0, 0, nil, nil).
+			AddRow(10, "Eva", "eva@example.com", "0414-999", createdOn, 1, 0, lastOrder, lastOrder))
+
+	page, err := repo.ListCustomersPage(context.Background(), 1, &pattern, 2, &afterID)
+
+	require.NoError(t, err)
+	require.Len(t, page.Items, 2)
+	assert.Equal(t, 3, page.Items[0].OrderCount)
+	assert.Equal(t, money.Amount(4250), page.Items[0].LifetimeSpend)
+	require.NotNil(t, page.Items[0].LastOrderAt)
+	assert.Equal(t, lastOrder, *page.Items[0].LastOrderAt)
+	assert.Nil(t, page.Items[1].LastOrderAt)
+	require.NotNil(t, page.NextCursor)
+	cursorID, err := pagination.DecodeIDCursor(*page.NextCursor)
+	require.NoError(t, err)
+	assert.Equal(t, uint64(11), cursorID)
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestRepository_GetCustomer(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	repo := &Repository{DB: db}
+	mock.ExpectQuery(regexp.QuoteMeta(`AND u.id_user = $3 GROUP BY u.id_user`)).
+		WithArgs(uint64(1), uModel.UserRoleClient, uint64(12)).
+		WillReturnRows(customerRows().AddRow(12, "Ana", "ana@example.com", "+58 414-555", createdOn, 3, 42.5, lastOrder, createdOn))
+	mock.ExpectQuery(regexp.QuoteMeta(`AND u.id_user <> $3 AND regexp_replace(COALESCE(u.phone, ''), '[^0-9]', '', 'g') = $4`)).
+		WithArgs(uint64(1), uModel.UserRoleClient, uint64(12), "58414555").
+		WillReturnRows(customerRows().AddRow(15, "Ana", "ana@exmaple.com", "58414555", createdOn, 1, 10.0, lastOrder, lastOrder))
+
+	customer, err := repo.GetCustomer(context.Background(), 1, 12)
+
+	require.NoError(t, err)
+	assert.Equal(t, "ana@example.com", customer.Email)
+	require.NotNil(t, customer.FirstOrderAt)
+	assert.Equal(t, createdOn, *customer.FirstOrderAt)
+	require.Len(t, customer.PossibleDuplicates, 1)
+	assert.Equal(t, uint64(15), customer.PossibleDuplicates[0].ID)
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestRepository_GetCustomer_NotFound(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	repo := &Repository{DB: db}
+	mock.ExpectQuery(regexp.QuoteMeta(`AND u.id_user = $3 GROUP BY u.id_user`)).
+		WithArgs(uint64(1), uModel.UserRoleClient, uint64(9)).
+		WillReturnError(sql.ErrNoRows)
+
+	_, err = repo.GetCustomer(context.Background(), 1, 9)
+
+	assert.True(t, errors.Is(err, appErrors.ErrCustomerNotFound))
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestRepository_MergeCustomers(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	repo := &Repository{DB: db}
+	mock.ExpectBegin()
+	mock.ExpectQuery(regexp.QuoteMeta(`ORDER BY id_user FOR UPDATE`)).
+		WithArgs(uint64(1), uModel.UserRoleClient, pq.Int64Array{12, 15}).
+		WillReturnRows(sqlmock.NewRows([]string{"id_user", "email"}).
+			AddRow(12, "ana@example.com").
+			AddRow(15, "Ana@Exmaple.com"))
+	mock.ExpectExec(regexp.QuoteMeta(`UPDATE orders SET id_user = $1 WHERE tenant_id = $2 AND id_user = ANY($3)`)).
+		WithArgs(uint64(12), uint64(1), pq.Int64Array{15}).
+		WillReturnResult(sqlmock.NewResult(0, 2))
+	mock.ExpectExec(regexp.QuoteMeta(`UPDATE orders_history SET id_user = $1 WHERE tenant_id = $2 AND id_user = ANY($3)`)).
+		WithArgs(uint64(12), uint64(1), pq.Int64Array{15}).
+		WillReturnResult(sqlmock.NewResult(0, 5))
+	mock.ExpectExec(regexp.QuoteMeta(`UPDATE orders_history SET modified_by = $1 WHERE tenant_id = $2 AND modified_by = ANY($3)`)).
+		WithArgs(uint64(12), uint64(1), pq.Int64Array{15}).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(regexp.QuoteMeta(`UPDATE promotion_redemptions SET email = $1 WHERE tenant_id = $2 AND LOWER(email) = ANY($3)`)).
+		WithArgs("ana@example.com", uint64(1), pq.StringArray{"ana@exmaple.com"}).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(regexp.QuoteMeta(`UPDATE standing_orders SET email = $1 WHERE tenant_id = $2 AND LOWER(email) = ANY($3)`)).
+		WithArgs("ana@example.com", uint64(1), pq.StringArray{"ana@exmaple.com"}).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(regexp.QuoteMeta(`DELETE FROM users WHERE tenant_id = $1 AND id_user = ANY($2)`)).
+		WithArgs(uint64(1), pq.Int64Array{15}).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+	mock.ExpectQuery(regexp.QuoteMeta(`AND u.id_user = $3 GROUP BY u.id_user`)).
+		WithArgs(uint64(1), uModel.UserRoleClient, uint64(12)).
+		WillReturnRows(customerRows().AddRow(12, "Ana", "ana@example.com", "", createdOn, 5, 52.5, lastOrder, createdOn))
+
+	result, err := repo.MergeCustomers(context.Background(), 1, 12, []uint64{15})
+
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), result.OrdersMoved)
+	assert.Equal(t, int64(5), result.HistoryMoved)
+	assert.Equal(t, []uint64{15}, result.MergedIDs)
+	assert.Equal(t, 5, result.Customer.OrderCount)
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestRepository_MergeCustomers_RejectsUnknownAccounts(t *testing.T) {
+	tests := []struct {
+		name    string
+		rows    *sqlmock.Rows
+		wantErr error
+	}{
+		{name: "unknown customer", rows: sqlmock.NewRows([]string{"id_user", "email"}).AddRow(15, "ana@exmaple.com"), wantErr: appErrors.ErrCustomerNotFound},
+		{name: "duplicate is not a live client", rows: sqlmock.NewRows([]string{"id_user", "email"}).AddRow(12, "ana@example.com"), wantErr: appErrors.ErrCustomerMergeInvalid},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db, mock, err := sqlmock.New()
+			require.NoError(t, err)
+			defer db.Close()
+
+			repo := &Repository{DB: db}
+			mock.ExpectBegin()
+			mock.ExpectQuery(regexp.QuoteMeta(`ORDER BY id_user FOR UPDATE`)).
+				WithArgs(uint64(1), uModel.UserRoleClient, pq.Int64Array{12, 15}).
+				WillReturnRows(tt.rows)
+			mock.ExpectRollback()
+
+			_, err = repo.MergeCustomers(context.Background(), 1, 12, []uint64{15})
+
+			assert.True(t, errors.Is(err, tt.wantErr))
+			require.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
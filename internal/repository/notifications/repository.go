@@ -0,0 +1,235 @@
+package notifications
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/radamesvaz/bakery-app/internal/errors"
+	nModel "github.com/radamesvaz/bakery-app/model/notifications"
+	uModel "github.com/radamesvaz/bakery-app/model/users"
+)
+
+type Repository struct {
+	DB *sql.DB
+}
+
+type execer interface {
+	ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error)
+}
+
+// execerFrom returns tx when non-nil so queued emails commit together with the caller's changes.
+func (r *Repository) execerFrom(tx *sql.Tx) execer {
+	if tx != nil {
+		return tx
+	}
+	return r.DB
+}
+
+// notDisabledClause skips the insert when the tenant switched the notification off ($1 tenant, $3 kind).
+const notDisabledClause = `NOT EXISTS (
+	SELECT 1 FROM tenant_notification_settings s
+	WHERE s.tenant_id = $1 AND s.kind = $3 AND s.enabled = FALSE
+)`
+
+// EnqueueOrderNotificationTx queues the email of kind for an order. Customer notifications go to
+// the order's user; admin notifications get one row per active tenant admin. Nothing is queued
+// when the tenant disabled the kind or there is no recipient. tx nil uses DB directly.
+func (r *Repository) EnqueueOrderNotificationTx(ctx context.Context, tx *sql.Tx, tenantID, orderID uint64, kind nModel.Kind) error {
+	var q string
+	var args []any
+	if nModel.IsAdminKind(kind) {
+		q = `INSERT INTO email_outbox (tenant_id, id_order, kind, to_email)
+SELECT $1, $2, $3, u.email
+FROM users u
+WHERE u.tenant_id = $1 AND u.id_role = $4 AND u.deleted_at IS NULL AND u.email <> ''
+AND ` + notDisabledClause
+		args = []any{tenantID, orderID, string(kind), int(uModel.UserRoleAdmin)}
+	} else {
+		q = `INSERT INTO email_outbox (tenant_id, id_order, kind, to_email)
+SELECT o.tenant_id, o.id_order, $3, u.email
+FROM orders o
+JOIN users u ON u.id_user = o.id_user
+WHERE o.tenant_id = $1 AND o.id_order = $2 AND u.email <> ''
+AND ` + notDisabledClause
+		args = []any{tenantID, orderID, string(kind)}
+	}
+	if _, err := r.execerFrom(tx).ExecContext(ctx, q, args...); err != nil {
+		return fmt.Errorf("enqueue %s email: %w", kind, err)
+	}
+	return nil
+}
+
+// ClaimDueEmails leases up to limit pending emails whose next attempt is due, pushing
+// next_attempt_at to leaseUntil so a crashed worker's claims are retried after the lease.
+func (r *Repository) ClaimDueEmails(ctx context.Context, now, leaseUntil time.Time, limit int) ([]nModel.PendingEmail, error) {
+	q := `UPDATE email_outbox
+SET next_attempt_at = $2, updated_on = NOW()
+WHERE id IN (
+	SELECT id FROM email_outbox
+	WHERE status = 'pending' AND next_attempt_at <= $1
+	ORDER BY next_attempt_at, id
+	LIMIT $3
+	FOR UPDATE SKIP LOCKED
+)
+RETURNING id, tenant_id, id_order, kind, to_email, attempts`
+	rows, err := r.DB.QueryContext(ctx, q, now, leaseUntil, limit)
+	if err != nil {
+		return nil, fmt.Errorf("claim due emails: %w", err)
+	}
+	defer rows.Close()
+
+	var out []nModel.PendingEmail
+	for rows.Next() {
+		var e nModel.PendingEmail
+		if err := rows.Scan(&e.ID, &e.TenantID, &e.IDOrder, &e.Kind, &e.ToEmail, &e.Attempts); err != nil {
+			return nil, fmt.Errorf("scan claimed email: %w", err)
+		}
+		out = append(out, e)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate claimed emails: %w", err)
+	}
+	return out, nil
+}
+
+// GetOrderEmailData loads the current order state used to render its emails.
+func (r *Repository) GetOrderEmailData(ctx context.Context, tenantID, orderID uint64) (nModel.OrderEmailData, error) {
+	var data nModel.OrderEmailData
+	var reason sql.NullString
+	err := r.DB.QueryRowContext(ctx,
+		`SELECT t.name, COALESCE(u.name, ''), o.id_order, o.status, o.total_price, o.currency, o.delivery_date, o.cancellation_reason
+FROM orders o
+JOIN tenants t ON t.id = o.tenant_id
+LEFT JOIN users u ON u.id_user = o.id_user
+WHERE o.tenant_id = $1 AND o.id_order = $2`,
+		tenantID, orderID,
+	).Scan(&data.TenantName, &data.CustomerName, &data.IDOrder, &data.Status, &data.TotalPrice, &data.Currency, &data.DeliveryDate, &reason)
+	if err == sql.ErrNoRows {
+		return nModel.OrderEmailData{}, errors.NewNotFound(errors.ErrOrderNotFound)
+	}
+	if err != nil {
+		return nModel.OrderEmailData{}, fmt.Errorf("get order email data: %w", err)
+	}
+	if reason.Valid {
+		data.CancellationReason = &reason.String
+	}
+
+	rows, err := r.DB.QueryContext(ctx,
+		`SELECT COALESCE(product_name_snapshot, ''), quantity, COALESCE(unit_price_snapshot, 0)
+FROM order_items
+WHERE tenant_id = $1 AND id_order = $2
+ORDER BY id_order_item`,
+		tenantID, orderID,
+	)
+	if err != nil {
+		return nModel.OrderEmailData{}, fmt.Errorf("get order email items: %w", err)
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var item nModel.OrderEmailItem
+		if err := rows.Scan(&item.Name, &item.Quantity, &item.UnitPrice); err != nil {
+			return nModel.OrderEmailData{}, fmt.Errorf("scan order email item: %w", err)
+		}
+		data.Items = append(data.Items, item)
+	}
+	if err := rows.Err(); err != nil {
+		return nModel.OrderEmailData{}, fmt.Errorf("iterate order email items: %w", err)
+	}
+	return data, nil
+}
+
+// MarkEmailSent records a successful send.
+func (r *Repository) MarkEmailSent(ctx context.Context, id uint64) error {
+	_, err := r.DB.ExecContext(ctx,
+		`UPDATE email_outbox
+SET status = 'sent', attempts = attempts + 1, last_error = NULL, sent_on = NOW(), updated_on = NOW()
+WHERE id = $1`,
+		id,
+	)
+	if err != nil {
+		return fmt.Errorf("mark email sent: %w", err)
+	}
+	return nil
+}
+
+// MarkEmailAttemptFailed records a failed attempt. When nextAttemptAt is nil the email gives up
+// (status failed); otherwise it stays pending until nextAttemptAt.
+func (r *Repository) MarkEmailAttemptFailed(ctx context.Context, id uint64, lastError string, nextAttemptAt *time.Time) error {
+	status := "pending"
+	var next sql.NullTime
+	if nextAttemptAt != nil {
+		next = sql.NullTime{Time: *nextAttemptAt, Valid: true}
+	} else {
+		status = "failed"
+	}
+	_, err := r.DB.ExecContext(ctx,
+		`UPDATE email_outbox
+SET status = $1, attempts = attempts + 1, last_error = $2,
+	next_attempt_at = COALESCE($3, next_attempt_at), updated_on = NOW()
+WHERE id = $4`,
+		status, lastError, next, id,
+	)
+	if err != nil {
+		return fmt.Errorf("mark email attempt failed: %w", err)
+	}
+	return nil
+}
+
+// ListSettings returns every notification kind for the tenant; kinds without a stored row are enabled.
+func (r *Repository) ListSettings(ctx context.Context, tenantID uint64) ([]nModel.Setting, error) {
+	rows, err := r.DB.QueryContext(ctx,
+		`SELECT kind, enabled FROM tenant_notification_settings WHERE tenant_id = $1`,
+		tenantID,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("list notification settings: %w", err)
+	}
+	defer rows.Close()
+
+	stored := make(map[nModel.Kind]bool)
+	for rows.Next() {
+		var kind nModel.Kind
+		var enabled bool
+		if err := rows.Scan(&kind, &enabled); err != nil {
+			return nil, fmt.Errorf("scan notification setting: %w", err)
+		}
+		stored[kind] = enabled
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate notification settings: %w", err)
+	}
+
+	out := make([]nModel.Setting, 0, len(nModel.Kinds))
+	for _, kind := range nModel.Kinds {
+		enabled, ok := stored[kind]
+		out = append(out, nModel.Setting{Kind: kind, Enabled: !ok || enabled})
+	}
+	return out, nil
+}
+
+// UpdateSettings stores the enabled flag of each given kind for the tenant in one transaction.
+func (r *Repository) UpdateSettings(ctx context.Context, tenantID uint64, settings []nModel.Setting) error {
+	tx, err := r.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	for _, setting := range settings {
+		_, err := tx.ExecContext(ctx,
+			`INSERT INTO tenant_notification_settings (tenant_id, kind, enabled)
+VALUES ($1, $2, $3)
+ON CONFLICT (tenant_id, kind) DO UPDATE SET enabled = EXCLUDED.enabled, updated_on = NOW()`,
+			tenantID, string(setting.Kind), setting.Enabled,
+		)
+		if err != nil {
+			return fmt.Errorf("update notification setting %s: %w", setting.Kind, err)
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("commit transaction: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,92 @@
+package notifications
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"regexp"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	appErrors "github.com/radamesvaz/bakery-app/internal/errors"
+	nModel "github.com/radamesvaz/bakery-app/model/notifications"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRepository_EnqueueOrderNotificationTx_CustomerKindUsesOrderUser(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	repo := &Repository{DB: db}
+
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta(`FROM orders o
+JOIN users u ON u.id_user = o.id_user`)).
+		WithArgs(uint64(1), uint64(9), "order_ready").
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectCommit()
+
+	tx, err := db.Begin()
+	require.NoError(t, err)
+	err = repo.EnqueueOrderNotificationTx(context.Background(), tx, 1, 9, nModel.KindOrderReady)
+	require.NoError(t, err)
+	require.NoError(t, tx.Commit())
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestRepository_EnqueueOrderNotificationTx_AdminKindTargetsTenantAdmins(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	repo := &Repository{DB: db}
+
+	mock.ExpectExec(regexp.QuoteMeta(`WHERE u.tenant_id = $1 AND u.id_role = $4 AND u.deleted_at IS NULL`)).
+		WithArgs(uint64(1), uint64(9), "admin_new_order", 1).
+		WillReturnResult(sqlmock.NewResult(0, 2))
+
+	err = repo.EnqueueOrderNotificationTx(context.Background(), nil, 1, 9, nModel.KindAdminNewOrder)
+	require.NoError(t, err)
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestRepository_ListSettings_DefaultsToEnabled(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	repo := &Repository{DB: db}
+
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT kind, enabled FROM tenant_notification_settings WHERE tenant_id = $1`)).
+		WithArgs(uint64(1)).
+		WillReturnRows(sqlmock.NewRows([]string{"kind", "enabled"}).AddRow("admin_new_order", false))
+
+	settings, err := repo.ListSettings(context.Background(), 1)
+	require.NoError(t, err)
+	require.Len(t, settings, len(nModel.Kinds))
+	for _, s := range settings {
+		assert.Equal(t, s.Kind != nModel.KindAdminNewOrder, s.Enabled, s.Kind)
+	}
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestRepository_GetOrderEmailData_NotFound(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	repo := &Repository{DB: db}
+
+	mock.ExpectQuery(regexp.QuoteMeta(`FROM orders o
+JOIN tenants t ON t.id = o.tenant_id`)).
+		WithArgs(uint64(1), uint64(9)).
+		WillReturnRows(sqlmock.NewRows([]string{"name"}))
+
+	_, err = repo.GetOrderEmailData(context.Background(), 1, 9)
+	var httpErr *appErrors.HTTPError
+	require.True(t, errors.As(err, &httpErr))
+	assert.Equal(t, http.StatusNotFound, httpErr.StatusCode)
+	require.NoError(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,170 @@
+package orderprotection
+
+import (
+	"context"
+	"database/sql"
+	stdErrors "errors"
+	"fmt"
+
+	"github.com/lib/pq"
+	"github.com/radamesvaz/bakery-app/internal/errors"
+	opModel "github.com/radamesvaz/bakery-app/model/orderprotection"
+)
+
+// Repository stores the tenant's abuse limits for storefront orders and its blocklist of emails
+// and phones.
+type Repository struct {
+	DB *sql.DB
+}
+
+func isUniqueViolation(err error) bool {
+	var pqErr *pq.Error
+	return stdErrors.As(err, &pqErr) && string(pqErr.Code) == "23505"
+}
+
+// GetSettings returns the tenant's order protection settings; a tenant without a row gets
+// opModel.DefaultSettings.
+func (r *Repository) GetSettings(ctx context.Context, tenantID uint64) (opModel.Settings, error) {
+	var settings opModel.Settings
+	err := r.DB.QueryRowContext(ctx,
+		`SELECT max_pending_unpaid_orders, max_quantity_per_line FROM tenant_order_protection WHERE tenant_id = $1`,
+		tenantID,
+	).Scan(&settings.MaxPendingUnpaidOrders, &settings.MaxQuantityPerLine)
+	if err == sql.ErrNoRows {
+		return opModel.DefaultSettings(), nil
+	}
+	if err != nil {
+		return opModel.Settings{}, fmt.Errorf("get order protection settings: %w", err)
+	}
+	return settings, nil
+}
+
+// UpdateSettings replaces the tenant's order protection settings. Orders already placed are not
+// affected.
+func (r *Repository) UpdateSettings(ctx context.Context, tenantID uint64, settings opModel.Settings) error {
+	_, err := r.DB.ExecContext(ctx,
+		`INSERT INTO tenant_order_protection (tenant_id, max_pending_unpaid_orders, max_quantity_per_line)
+VALUES ($1, $2, $3)
+ON CONFLICT (tenant_id) DO UPDATE SET
+	max_pending_unpaid_orders = EXCLUDED.max_pending_unpaid_orders,
+	max_quantity_per_line = EXCLUDED.max_quantity_per_line,
+	updated_on = NOW()`,
+		tenantID, settings.MaxPendingUnpaidOrders, settings.MaxQuantityPerLine,
+	)
+	if err != nil {
+		return fmt.Errorf("update order protection settings: %w", err)
+	}
+	return nil
+}
+
+// CountPendingUnpaidOrdersTx returns how many pending, unpaid orders the customer userID has that
+// are waiting to expire; staff and standing orders never expire and do not count. The customer row stays locked until tx ends, so two concurrent orders of the same customer
+// cannot both pass a limit on this count.
+func (r *Repository) CountPendingUnpaidOrdersTx(ctx context.Context, tx *sql.Tx, tenantID, userID uint64) (int, error) {
+	if _, err := tx.ExecContext(ctx,
+		`SELECT id_user FROM users WHERE tenant_id = $1 AND id_user = $2 FOR UPDATE`,
+		tenantID, userID,
+	); err != nil {
+		return 0, fmt.Errorf("lock customer: %w", err)
+	}
+	var count int
+	err := tx.QueryRowContext(ctx,
+		`SELECT COUNT(*) FROM orders WHERE tenant_id = $1 AND id_user = $2 AND status = 'pending' AND paid IS NOT TRUE AND expires_at IS NOT NULL`,
+		tenantID, userID,
+	).Scan(&count)
+	if err != nil {
+		return 0, fmt.Errorf("count pending unpaid orders: %w", err)
+	}
+	return count, nil
+}
+
+// IsBlocked reports whether the tenant blocked the email or the phone. Both are normalized
+// before matching; an empty value never matches.
+func (r *Repository) IsBlocked(ctx context.Context, tenantID uint64, email, phone string) (bool, error) {
+	var blocked bool
+	err := r.DB.QueryRowContext(ctx,
+		`SELECT EXISTS (
+	SELECT 1 FROM order_blocklist
+	WHERE tenant_id = $1 AND ((kind = 'email' AND value = $2) OR (kind = 'phone' AND value = $3))
+)`,
+		tenantID, opModel.NormalizeEmail(email), opModel.NormalizePhone(phone),
+	).Scan(&blocked)
+	if err != nil {
+		return false, fmt.Errorf("check order blocklist: %w", err)
+	}
+	return blocked, nil
+}
+
+const blocklistColumns = `id_blocklist_entry, tenant_id, kind, value, reason, created_on`
+
+func scanBlocklistEntry(row interface{ Scan(dest ...any) error }) (opModel.BlocklistEntry, error) {
+	var (
+		e      opModel.BlocklistEntry
+		reason sql.NullString
+	)
+	if err := row.Scan(&e.ID, &e.TenantID, &e.Kind, &e.Value, &reason, &e.CreatedOn); err != nil {
+		return opModel.BlocklistEntry{}, err
+	}
+	if reason.Valid {
+		e.Reason = &reason.String
+	}
+	return e, nil
+}
+
+// ListBlocklist returns the tenant's blocked emails and phones, newest first.
+func (r *Repository) ListBlocklist(ctx context.Context, tenantID uint64) ([]opModel.BlocklistEntry, error) {
+	rows, err := r.DB.QueryContext(ctx,
+		`SELECT `+blocklistColumns+` FROM order_blocklist WHERE tenant_id = $1 ORDER BY id_blocklist_entry DESC`,
+		tenantID,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("list order blocklist: %w", err)
+	}
+	defer rows.Close()
+
+	entries := []opModel.BlocklistEntry{}
+	for rows.Next() {
+		e, err := scanBlocklistEntry(rows)
+		if err != nil {
+			return nil, fmt.Errorf("scan blocklist entry: %w", err)
+		}
+		entries = append(entries, e)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate order blocklist: %w", err)
+	}
+	return entries, nil
+}
+
+// CreateBlocklistEntry blocks an email or phone. in.Value must already be normalized.
+func (r *Repository) CreateBlocklistEntry(ctx context.Context, tenantID uint64, in opModel.BlocklistEntry) (opModel.BlocklistEntry, error) {
+	e, err := scanBlocklistEntry(r.DB.QueryRowContext(ctx,
+		`INSERT INTO order_blocklist (tenant_id, kind, value, reason)
+VALUES ($1, $2, $3, $4)
+RETURNING `+blocklistColumns,
+		tenantID, in.Kind, in.Value, in.Reason,
+	))
+	if err != nil {
+		if isUniqueViolation(err) {
+			return opModel.BlocklistEntry{}, errors.ErrBlocklistEntryExists
+		}
+		return opModel.BlocklistEntry{}, fmt.Errorf("create blocklist entry: %w", err)
+	}
+	return e, nil
+}
+
+// DeleteBlocklistEntry unblocks an email or phone.
+func (r *Repository) DeleteBlocklistEntry(ctx context.Context, tenantID, id uint64) error {
+	result, err := r.DB.ExecContext(ctx, `DELETE FROM order_blocklist WHERE id_blocklist_entry = $1 AND tenant_id = $2`, id, tenantID)
+	if err != nil {
+		return fmt.Errorf("delete blocklist entry: %w", err)
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("get rows affected: %w", err)
+	}
+	if rows == 0 {
+		return errors.NewNotFound(errors.ErrBlocklistEntryNotFound)
+	}
+	return nil
+}
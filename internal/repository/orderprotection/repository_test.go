@@ -0,0 +1,131 @@
+package orderprotection
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/lib/pq"
+	appErrors "github.com/radamesvaz/bakery-app/internal/errors"
+	opModel "github.com/radamesvaz/bakery-app/model/orderprotection"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var createdOn = time.Date(2026, 4, 6, 9, 0, 0, 0, time.UTC)
+
+func blocklistRows() *sqlmock.Rows {
+	return sqlmock.NewRows([]string{"id_blocklist_entry", "tenant_id", "kind", "value", "reason", "created_on"})
+}
+
+func TestRepository_GetSettings(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	repo := &Repository{DB: db}
+	query := regexp.QuoteMeta(`SELECT max_pending_unpaid_orders, max_quantity_per_line FROM tenant_order_protection WHERE tenant_id = $1`)
+
+	mock.ExpectQuery(query).WithArgs(uint64(1)).
+		WillReturnRows(sqlmock.NewRows([]string{"max_pending_unpaid_orders", "max_quantity_per_line"}).AddRow(1, 0))
+	mock.ExpectQuery(query).WithArgs(uint64(2)).WillReturnError(sql.ErrNoRows)
+
+	settings, err := repo.GetSettings(context.Background(), 1)
+	require.NoError(t, err)
+	assert.Equal(t, opModel.Settings{MaxPendingUnpaidOrders: 1, MaxQuantityPerLine: 0}, settings)
+
+	settings, err = repo.GetSettings(context.Background(), 2)
+	require.NoError(t, err)
+	assert.Equal(t, opModel.DefaultSettings(), settings)
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestRepository_CountPendingUnpaidOrdersTx(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	repo := &Repository{DB: db}
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta(`SELECT id_user FROM users WHERE tenant_id = $1 AND id_user = $2 FOR UPDATE`)).
+		WithArgs(uint64(1), uint64(12)).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectQuery(regexp.QuoteMeta(`AND status = 'pending' AND paid IS NOT TRUE AND expires_at IS NOT NULL`)).
+		WithArgs(uint64(1), uint64(12)).
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(2))
+	mock.ExpectRollback()
+
+	tx, err := db.Begin()
+	require.NoError(t, err)
+	count, err := repo.CountPendingUnpaidOrdersTx(context.Background(), tx, 1, 12)
+	require.NoError(t, tx.Rollback())
+
+	require.NoError(t, err)
+	assert.Equal(t, 2, count)
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestRepository_IsBlocked_NormalizesValues(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	repo := &Repository{DB: db}
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT 1 FROM order_blocklist`)).
+		WithArgs(uint64(1), "ana@example.com", "58412555").
+		WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(true))
+
+	blocked, err := repo.IsBlocked(context.Background(), 1, " Ana@Example.com", "+58 412-555")
+
+	require.NoError(t, err)
+	assert.True(t, blocked)
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestRepository_CreateBlocklistEntry(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	repo := &Repository{DB: db}
+	reason := "fake orders"
+	in := opModel.BlocklistEntry{Kind: opModel.BlocklistPhone, Value: "58412555", Reason: &reason}
+	query := regexp.QuoteMeta(`INSERT INTO order_blocklist (tenant_id, kind, value, reason)`)
+
+	mock.ExpectQuery(query).
+		WithArgs(uint64(1), opModel.BlocklistPhone, "58412555", &reason).
+		WillReturnRows(blocklistRows().AddRow(3, 1, "phone", "58412555", reason, createdOn))
+	mock.ExpectQuery(query).
+		WithArgs(uint64(1), opModel.BlocklistPhone, "58412555", &reason).
+		WillReturnError(&pq.Error{Code: "23505"})
+
+	entry, err := repo.CreateBlocklistEntry(context.Background(), 1, in)
+	require.NoError(t, err)
+	assert.Equal(t, uint64(3), entry.ID)
+	require.NotNil(t, entry.Reason)
+	assert.Equal(t, reason, *entry.Reason)
+
+	_, err = repo.CreateBlocklistEntry(context.Background(), 1, in)
+	assert.True(t, errors.Is(err, appErrors.ErrBlocklistEntryExists))
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestRepository_DeleteBlocklistEntry_NotFound(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	repo := &Repository{DB: db}
+	mock.ExpectExec(regexp.QuoteMeta(`DELETE FROM order_blocklist WHERE id_blocklist_entry = $1 AND tenant_id = $2`)).
+		WithArgs(uint64(9), uint64(1)).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+
+	err = repo.DeleteBlocklistEntry(context.Background(), 1, 9)
+
+	assert.True(t, errors.Is(err, appErrors.ErrBlocklistEntryNotFound))
+	require.NoError(t, mock.ExpectationsWereMet())
+}
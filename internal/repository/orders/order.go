@@ -14,7 +14,9 @@ import (
 	"github.com/radamesvaz/bakery-app/internal/errors"
 	"github.com/radamesvaz/bakery-app/internal/logger"
 	"github.com/radamesvaz/bakery-app/internal/pagination"
+	"github.com/radamesvaz/bakery-app/model/money"
 	oModel "github.com/radamesvaz/bakery-app/model/orders"
+	pricingModel "github.com/radamesvaz/bakery-app/model/pricing"
 )
 
 type OrderRepository struct {
@@ -66,6 +68,19 @@ func (r *OrderRepository) GetOrdersWithFilters(
             o.created_on,
             o.expires_at,
             o.cancellation_reason,
+            o.discount_amount,
+            o.promotion_code,
+            o.id_promotion,
+            o.subtotal,
+            o.tax_rate,
+            o.tax_inclusive,
+            o.tax_amount,
+            o.delivery_fee,
+            o.id_delivery_zone,
+            o.currency,
+            o.fulfillment_type,
+            o.id_pickup_location,
+            o.sales_channel,
             u.name AS user_name, 
             u.phone,
             oi.id_order_item, 
@@ -100,32 +115,82 @@ func (r *OrderRepository) GetOrdersWithFilters(
 	}
 	defer rows.Close()
 
-	return ordersFromJoinRows(rows, orderJoinSortIDAsc)
+	orders, err := ordersFromJoinRows(rows, orderJoinSortIDAsc)
+	if err != nil {
+		return nil, err
+	}
+	if err := r.attachPaymentTotals(ctx, tenantID, orders); err != nil {
+		return nil, err
+	}
+	return orders, nil
 }
 
-// ListOrdersPageResult is one page of orders (cursor on created_on ASC, id_order ASC).
+// ListOrdersPageResult is one page of orders; NextCursor follows the sort of the filter.
 type ListOrdersPageResult struct {
 	Items      []oModel.OrderResponse
 	NextCursor *string
 }
 
+// OrderListSort is the order of ListOrdersWithFiltersPage. Each sort has its own cursor encoding.
+type OrderListSort string
+
+const (
+	// OrderSortCreatedOn lists oldest orders first (created_on ASC, id_order ASC); see pagination.OrderKeyset.
+	OrderSortCreatedOn OrderListSort = "created_on"
+	// OrderSortDeliveryDate lists the next deliveries first (delivery_date ASC NULLS LAST, id_order ASC);
+	// see pagination.OrderDeliveryKeyset.
+	OrderSortDeliveryDate OrderListSort = "delivery_date"
+)
+
+// IsValidOrderListSort reports whether s is a known sort; empty means OrderSortCreatedOn.
+func IsValidOrderListSort(s OrderListSort) bool {
+	return s == "" || s == OrderSortCreatedOn || s == OrderSortDeliveryDate
+}
+
+// ErrInvalidOrderCursor is returned when a cursor does not decode for the requested sort.
+var ErrInvalidOrderCursor = errors.NewBadRequest(fmt.Errorf("Invalid cursor"))
+
+// OrderListFilter narrows ListOrdersWithFiltersPage. Nil fields do not filter.
+type OrderListFilter struct {
+	// IgnoreStatus includes deleted orders when Status is nil.
+	IgnoreStatus bool
+	Status       *string
+	UserID       *uint64
+	// Search matches the customer name or email, or the order id when numeric.
+	Search *string
+	// CreatedFrom and CreatedTo bound o.created_on as [CreatedFrom, CreatedTo).
+	CreatedFrom *time.Time
+	CreatedTo   *time.Time
+	// DeliveryFrom and DeliveryTo bound o.delivery_date, both inclusive.
+	DeliveryFrom *time.Time
+	DeliveryTo   *time.Time
+	Paid         *bool
+	// MinTotal and MaxTotal bound o.total_price, both inclusive.
+	MinTotal *money.Amount
+	MaxTotal *money.Amount
+	// FulfillmentType keeps only delivery or pickup orders.
+	FulfillmentType *oModel.FulfillmentType
+	// Sort defaults to OrderSortCreatedOn.
+	Sort OrderListSort
+}
+
 // ListOrdersWithFiltersPage returns orders for the tenant with the same status semantics as GetOrdersWithFilters,
-// ordered by creation time ascending (then id_order), paginated with an opaque cursor (see pagination.OrderKeyset).
+// in the order of filter.Sort, paginated with an opaque cursor of that sort (empty for the first page).
 func (r *OrderRepository) ListOrdersWithFiltersPage(
 	ctx context.Context,
 	tenantID uint64,
-	ignoreStatus bool,
-	statusFilter *string,
+	filter OrderListFilter,
 	limit int,
-	after *pagination.OrderKeyset,
-	filterUserID *uint64,
-	searchQuery *string,
+	cursor string,
 ) (ListOrdersPageResult, error) {
 	if limit < 1 {
 		return ListOrdersPageResult{}, fmt.Errorf("limit must be at least 1")
 	}
 
-	idQuery, idArgs := buildOrderIDPageQuery(tenantID, ignoreStatus, statusFilter, after, filterUserID, searchQuery, limit+1)
+	idQuery, idArgs, err := buildOrderIDPageQuery(tenantID, filter, cursor, limit+1)
+	if err != nil {
+		return ListOrdersPageResult{}, err
+	}
 	idRows, err := r.DB.QueryContext(ctx, idQuery, idArgs...)
 	if err != nil {
 		return ListOrdersPageResult{}, fmt.Errorf("listing order ids: %w", err)
@@ -167,6 +232,19 @@ func (r *OrderRepository) ListOrdersWithFiltersPage(
             o.created_on,
             o.expires_at,
             o.cancellation_reason,
+            o.discount_amount,
+            o.promotion_code,
+            o.id_promotion,
+            o.subtotal,
+            o.tax_rate,
+            o.tax_inclusive,
+            o.tax_amount,
+            o.delivery_fee,
+            o.id_delivery_zone,
+            o.currency,
+            o.fulfillment_type,
+            o.id_pickup_location,
+            o.sales_channel,
             u.name AS user_name, 
             u.phone,
             oi.id_order_item, 
@@ -186,15 +264,31 @@ func (r *OrderRepository) ListOrdersWithFiltersPage(
 	}
 	defer rows.Close()
 
-	orders, err := ordersFromJoinRows(rows, orderJoinSortCreatedOnAscIDAsc)
+	sortMode := orderJoinSortCreatedOnAscIDAsc
+	if filter.Sort == OrderSortDeliveryDate {
+		sortMode = orderJoinSortDeliveryDateAscIDAsc
+	}
+	orders, err := ordersFromJoinRows(rows, sortMode)
 	if err != nil {
 		return ListOrdersPageResult{}, err
 	}
+	if err := r.attachPaymentTotals(ctx, tenantID, orders); err != nil {
+		return ListOrdersPageResult{}, err
+	}
 
 	var next *string
 	if hasNext && len(orders) > 0 {
 		last := orders[len(orders)-1]
-		s, err := pagination.EncodeOrderCursor(last.CreatedOn, last.ID)
+		var s string
+		if filter.Sort == OrderSortDeliveryDate {
+			var deliveryDate *time.Time
+			if !last.DeliveryDate.IsZero() {
+				deliveryDate = &last.DeliveryDate
+			}
+			s, err = pagination.EncodeOrderDeliveryCursor(deliveryDate, last.ID)
+		} else {
+			s, err = pagination.EncodeOrderCursor(last.CreatedOn, last.ID)
+		}
 		if err != nil {
 			return ListOrdersPageResult{}, fmt.Errorf("encoding next cursor: %w", err)
 		}
@@ -204,24 +298,64 @@ func (r *OrderRepository) ListOrdersWithFiltersPage(
 	return ListOrdersPageResult{Items: orders, NextCursor: next}, nil
 }
 
-func buildOrderIDPageQuery(tenantID uint64, ignoreStatus bool, statusFilter *string, after *pagination.OrderKeyset, filterUserID *uint64, searchQuery *string, limit int) (string, []interface{}) {
+func buildOrderIDPageQuery(tenantID uint64, filter OrderListFilter, cursor string, limit int) (string, []interface{}, error) {
 	q := `SELECT o.id_order FROM orders o WHERE o.tenant_id = $1`
 	args := []interface{}{tenantID}
 	idx := 2
-	if statusFilter != nil {
+	if filter.Status != nil {
 		q += fmt.Sprintf(" AND o.status = $%d", idx)
-		args = append(args, *statusFilter)
+		args = append(args, *filter.Status)
 		idx++
-	} else if !ignoreStatus {
+	} else if !filter.IgnoreStatus {
 		q += " AND o.status != 'deleted'"
 	}
-	if filterUserID != nil {
+	if filter.UserID != nil {
 		q += fmt.Sprintf(" AND o.id_user = $%d", idx)
-		args = append(args, *filterUserID)
+		args = append(args, *filter.UserID)
+		idx++
+	}
+	if filter.CreatedFrom != nil {
+		q += fmt.Sprintf(" AND o.created_on >= $%d", idx)
+		args = append(args, *filter.CreatedFrom)
+		idx++
+	}
+	if filter.CreatedTo != nil {
+		q += fmt.Sprintf(" AND o.created_on < $%d", idx)
+		args = append(args, *filter.CreatedTo)
+		idx++
+	}
+	if filter.DeliveryFrom != nil {
+		q += fmt.Sprintf(" AND o.delivery_date >= $%d", idx)
+		args = append(args, *filter.DeliveryFrom)
+		idx++
+	}
+	if filter.DeliveryTo != nil {
+		q += fmt.Sprintf(" AND o.delivery_date <= $%d", idx)
+		args = append(args, *filter.DeliveryTo)
+		idx++
+	}
+	if filter.Paid != nil {
+		q += fmt.Sprintf(" AND o.paid = $%d", idx)
+		args = append(args, *filter.Paid)
+		idx++
+	}
+	if filter.MinTotal != nil {
+		q += fmt.Sprintf(" AND o.total_price >= $%d", idx)
+		args = append(args, *filter.MinTotal)
+		idx++
+	}
+	if filter.MaxTotal != nil {
+		q += fmt.Sprintf(" AND o.total_price <= $%d", idx)
+		args = append(args, *filter.MaxTotal)
+		idx++
+	}
+	if filter.FulfillmentType != nil {
+		q += fmt.Sprintf(" AND o.fulfillment_type = $%d", idx)
+		args = append(args, string(*filter.FulfillmentType))
 		idx++
 	}
-	if searchQuery != nil {
-		trimmed := strings.TrimSpace(*searchQuery)
+	if filter.Search != nil {
+		trimmed := strings.TrimSpace(*filter.Search)
 		if trimmed != "" {
 			pattern := "%" + trimmed + "%"
 			q += fmt.Sprintf(" AND (EXISTS (SELECT 1 FROM users u WHERE u.id_user = o.id_user AND u.tenant_id = o.tenant_id AND (u.name ILIKE $%d OR u.email ILIKE $%d))", idx, idx)
@@ -236,7 +370,34 @@ func buildOrderIDPageQuery(tenantID uint64, ignoreStatus bool, statusFilter *str
 			q += ")"
 		}
 	}
-	if after != nil {
+	if filter.Sort == OrderSortDeliveryDate {
+		if cursor != "" {
+			after, err := pagination.DecodeOrderDeliveryCursor(cursor)
+			if err != nil {
+				return "", nil, ErrInvalidOrderCursor
+			}
+			if after.DeliveryDate != nil {
+				dArg := idx
+				idArg := idx + 1
+				q += fmt.Sprintf(" AND (o.delivery_date > $%d OR (o.delivery_date = $%d AND o.id_order > $%d) OR o.delivery_date IS NULL)", dArg, dArg, idArg)
+				args = append(args, *after.DeliveryDate, after.ID)
+				idx += 2
+			} else {
+				q += fmt.Sprintf(" AND o.delivery_date IS NULL AND o.id_order > $%d", idx)
+				args = append(args, after.ID)
+				idx++
+			}
+		}
+		q += fmt.Sprintf(" ORDER BY o.delivery_date ASC NULLS LAST, o.id_order ASC LIMIT $%d", idx)
+		args = append(args, limit)
+		return q, args, nil
+	}
+
+	if cursor != "" {
+		after, err := pagination.DecodeOrderCursor(cursor)
+		if err != nil {
+			return "", nil, ErrInvalidOrderCursor
+		}
 		tArg := idx
 		idArg := idx + 1
 		q += fmt.Sprintf(" AND (o.created_on > $%d OR (o.created_on = $%d AND o.id_order > $%d))", tArg, tArg, idArg)
@@ -245,7 +406,7 @@ func buildOrderIDPageQuery(tenantID uint64, ignoreStatus bool, statusFilter *str
 	}
 	q += fmt.Sprintf(" ORDER BY o.created_on ASC, o.id_order ASC LIMIT $%d", idx)
 	args = append(args, limit)
-	return q, args
+	return q, args, nil
 }
 
 type orderJoinSort int
@@ -254,6 +415,7 @@ const (
 	orderJoinSortIDAsc orderJoinSort = iota
 	orderJoinSortIDDesc
 	orderJoinSortCreatedOnAscIDAsc
+	orderJoinSortDeliveryDateAscIDAsc
 )
 
 func ordersFromJoinRows(rows *sql.Rows, sortMode orderJoinSort) ([]oModel.OrderResponse, error) {
@@ -264,7 +426,7 @@ func ordersFromJoinRows(rows *sql.Rows, sortMode orderJoinSort) ([]oModel.OrderR
 			idOrder            uint64
 			tenantIDRow        uint64
 			idUser             sql.NullInt64
-			totalPrice         float64
+			totalPrice         money.Amount
 			status             string
 			note               string
 			deliveryDate       time.Time
@@ -273,12 +435,25 @@ func ordersFromJoinRows(rows *sql.Rows, sortMode orderJoinSort) ([]oModel.OrderR
 			createdOn          time.Time
 			expiresAt          sql.NullTime
 			cancellationReason sql.NullString
+			discountAmount     money.Amount
+			promotionCode      sql.NullString
+			idPromotion        sql.NullInt64
+			subtotal           money.Amount
+			taxRate            float64
+			taxInclusive       bool
+			taxAmount          money.Amount
+			deliveryFee        money.Amount
+			idDeliveryZone     sql.NullInt64
+			currency           money.Currency
+			fulfillmentType    string
+			idPickupLocation   sql.NullInt64
+			salesChannel       string
 			userName           sql.NullString
 			phone              sql.NullString
 			idOrderItem        uint64
 			idProduct          uint64
 			productName        string
-			unitPrice          float64
+			unitPrice          money.Amount
 			quantity           uint64
 		)
 
@@ -295,6 +470,19 @@ func ordersFromJoinRows(rows *sql.Rows, sortMode orderJoinSort) ([]oModel.OrderR
 			&createdOn,
 			&expiresAt,
 			&cancellationReason,
+			&discountAmount,
+			&promotionCode,
+			&idPromotion,
+			&subtotal,
+			&taxRate,
+			&taxInclusive,
+			&taxAmount,
+			&deliveryFee,
+			&idDeliveryZone,
+			&currency,
+			&fulfillmentType,
+			&idPickupLocation,
+			&salesChannel,
 			&userName,
 			&phone,
 			&idOrderItem,
@@ -329,6 +517,30 @@ func ordersFromJoinRows(rows *sql.Rows, sortMode orderJoinSort) ([]oModel.OrderR
 			if cancellationReason.Valid {
 				resp.CancellationReason = &cancellationReason.String
 			}
+			resp.DiscountAmount = discountAmount
+			if promotionCode.Valid {
+				resp.PromotionCode = &promotionCode.String
+			}
+			if idPromotion.Valid {
+				id := uint64(idPromotion.Int64)
+				resp.IDPromotion = &id
+			}
+			resp.Subtotal = subtotal
+			resp.TaxRate = taxRate
+			resp.TaxInclusive = taxInclusive
+			resp.TaxAmount = taxAmount
+			resp.DeliveryFee = deliveryFee
+			if idDeliveryZone.Valid {
+				id := uint64(idDeliveryZone.Int64)
+				resp.IDDeliveryZone = &id
+			}
+			resp.Currency = currency
+			resp.FulfillmentType = oModel.FulfillmentType(fulfillmentType)
+			if idPickupLocation.Valid {
+				id := uint64(idPickupLocation.Int64)
+				resp.IDPickupLocation = &id
+			}
+			resp.SalesChannel = oModel.SalesChannel(salesChannel)
 			if userName.Valid {
 				resp.User = userName.String
 			}
@@ -373,6 +585,14 @@ func ordersFromJoinRows(rows *sql.Rows, sortMode orderJoinSort) ([]oModel.OrderR
 			}
 			return a.ID < b.ID
 		})
+	case orderJoinSortDeliveryDateAscIDAsc:
+		sort.SliceStable(orders, func(i, j int) bool {
+			a, b := orders[i], orders[j]
+			if a.DeliveryDate.Equal(b.DeliveryDate) {
+				return a.ID < b.ID
+			}
+			return a.DeliveryDate.Before(b.DeliveryDate)
+		})
 	default:
 		sort.Slice(orders, func(i, j int) bool { return orders[i].ID < orders[j].ID })
 	}
@@ -395,6 +615,19 @@ func (r *OrderRepository) GetOrderByID(ctx context.Context, tenantID, id uint64)
             o.created_on,
             o.expires_at,
             o.cancellation_reason,
+            o.discount_amount,
+            o.promotion_code,
+            o.id_promotion,
+            o.subtotal,
+            o.tax_rate,
+            o.tax_inclusive,
+            o.tax_amount,
+            o.delivery_fee,
+            o.id_delivery_zone,
+            o.currency,
+            o.fulfillment_type,
+            o.id_pickup_location,
+            o.sales_channel,
             u.name AS user_name, 
             u.phone,
             oi.id_order_item, 
@@ -429,7 +662,7 @@ func (r *OrderRepository) GetOrderByID(ctx context.Context, tenantID, id uint64)
 			idOrder            uint64
 			tenantIDRow        uint64
 			idUser             sql.NullInt64
-			totalPrice         float64
+			totalPrice         money.Amount
 			status             string
 			note               string
 			deliveryDate       time.Time
@@ -438,12 +671,25 @@ func (r *OrderRepository) GetOrderByID(ctx context.Context, tenantID, id uint64)
 			createdOn          time.Time
 			expiresAt          sql.NullTime
 			cancellationReason sql.NullString
+			discountAmount     money.Amount
+			promotionCode      sql.NullString
+			idPromotion        sql.NullInt64
+			subtotal           money.Amount
+			taxRate            float64
+			taxInclusive       bool
+			taxAmount          money.Amount
+			deliveryFee        money.Amount
+			idDeliveryZone     sql.NullInt64
+			currency           money.Currency
+			fulfillmentType    string
+			idPickupLocation   sql.NullInt64
+			salesChannel       string
 			userName           sql.NullString
 			phone              sql.NullString
 			idOrderItem        uint64
 			idProduct          uint64
 			productName        string
-			unitPrice          float64
+			unitPrice          money.Amount
 			quantity           uint64
 		)
 
@@ -460,6 +706,19 @@ func (r *OrderRepository) GetOrderByID(ctx context.Context, tenantID, id uint64)
 			&createdOn,
 			&expiresAt,
 			&cancellationReason,
+			&discountAmount,
+			&promotionCode,
+			&idPromotion,
+			&subtotal,
+			&taxRate,
+			&taxInclusive,
+			&taxAmount,
+			&deliveryFee,
+			&idDeliveryZone,
+			&currency,
+			&fulfillmentType,
+			&idPickupLocation,
+			&salesChannel,
 			&userName,
 			&phone,
 			&idOrderItem,
@@ -491,6 +750,30 @@ func (r *OrderRepository) GetOrderByID(ctx context.Context, tenantID, id uint64)
 			if cancellationReason.Valid {
 				order.CancellationReason = &cancellationReason.String
 			}
+			order.DiscountAmount = discountAmount
+			if promotionCode.Valid {
+				order.PromotionCode = &promotionCode.String
+			}
+			if idPromotion.Valid {
+				id := uint64(idPromotion.Int64)
+				order.IDPromotion = &id
+			}
+			order.Subtotal = subtotal
+			order.TaxRate = taxRate
+			order.TaxInclusive = taxInclusive
+			order.TaxAmount = taxAmount
+			order.DeliveryFee = deliveryFee
+			if idDeliveryZone.Valid {
+				id := uint64(idDeliveryZone.Int64)
+				order.IDDeliveryZone = &id
+			}
+			order.Currency = currency
+			order.FulfillmentType = oModel.FulfillmentType(fulfillmentType)
+			if idPickupLocation.Valid {
+				id := uint64(idPickupLocation.Int64)
+				order.IDPickupLocation = &id
+			}
+			order.SalesChannel = oModel.SalesChannel(salesChannel)
 			if userName.Valid {
 				order.User = userName.String
 			}
@@ -519,7 +802,12 @@ func (r *OrderRepository) GetOrderByID(ctx context.Context, tenantID, id uint64)
 		return order, errors.NewNotFound(errors.ErrOrderNotFound)
 	}
 
-	return order, nil
+	withTotals := []oModel.OrderResponse{order}
+	if err := r.attachPaymentTotals(ctx, tenantID, withTotals); err != nil {
+		return oModel.OrderResponse{}, err
+	}
+
+	return withTotals[0], nil
 }
 
 // GetOrderItemsByOrderID gets all items for a specific order
@@ -586,6 +874,66 @@ func (r *OrderRepository) CreateOrderItems(ctx context.Context, tx *sql.Tx, tena
 	return r.createOrderItemTx(ctx, tx, tenantID, items)
 }
 
+// LockOrderStatusTx locks the order row for the rest of the transaction and returns its current status.
+func (r *OrderRepository) LockOrderStatusTx(ctx context.Context, tx *sql.Tx, tenantID, orderID uint64) (oModel.OrderStatus, error) {
+	var status oModel.OrderStatus
+	err := tx.QueryRowContext(ctx,
+		`SELECT status FROM orders WHERE id_order = $1 AND tenant_id = $2 FOR UPDATE`,
+		orderID, tenantID,
+	).Scan(&status)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return "", errors.NewNotFound(errors.ErrOrderNotFound)
+		}
+		return "", fmt.Errorf("error locking order: %w", err)
+	}
+	return status, nil
+}
+
+// GetOrderIDByTrackingTokenHash resolves a public tracking token (already hashed) to its order.
+func (r *OrderRepository) GetOrderIDByTrackingTokenHash(ctx context.Context, tenantID uint64, tokenHash string) (uint64, error) {
+	var id uint64
+	err := r.DB.QueryRowContext(ctx,
+		`SELECT id_order FROM orders WHERE tenant_id = $1 AND tracking_token_hash = $2`,
+		tenantID, tokenHash,
+	).Scan(&id)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return 0, errors.NewNotFound(errors.ErrOrderNotFound)
+		}
+		return 0, fmt.Errorf("error getting order by tracking token: %w", err)
+	}
+	return id, nil
+}
+
+// DeleteOrderItemsTx removes every line of the order within a transaction (used to replace the item list).
+func (r *OrderRepository) DeleteOrderItemsTx(ctx context.Context, tx *sql.Tx, tenantID, orderID uint64) error {
+	_, err := tx.ExecContext(ctx, `DELETE FROM order_items WHERE id_order = $1 AND tenant_id = $2`, orderID, tenantID)
+	if err != nil {
+		return fmt.Errorf("error deleting order items: %w", err)
+	}
+	return nil
+}
+
+// UpdateOrderTotalsTx stores the re-priced totals of the order within a transaction.
+func (r *OrderRepository) UpdateOrderTotalsTx(ctx context.Context, tx *sql.Tx, tenantID, orderID uint64, totals pricingModel.Totals) error {
+	result, err := tx.ExecContext(ctx,
+		`UPDATE orders SET total_price = $1, subtotal = $2, discount_amount = $3, tax_rate = $4, tax_inclusive = $5, tax_amount = $6, delivery_fee = $7 WHERE id_order = $8 AND tenant_id = $9`,
+		totals.Total, totals.Subtotal, totals.DiscountAmount, totals.TaxRate, totals.TaxInclusive, totals.TaxAmount, totals.DeliveryFee, orderID, tenantID,
+	)
+	if err != nil {
+		return fmt.Errorf("error updating order total price: %w", err)
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("error getting rows affected: %w", err)
+	}
+	if rowsAffected == 0 {
+		return errors.NewNotFound(errors.ErrOrderNotFound)
+	}
+	return nil
+}
+
 // BeginTx starts a new transaction (for use by services that orchestrate order + product operations).
 func (r *OrderRepository) BeginTx(ctx context.Context) (*sql.Tx, error) {
 	return r.DB.BeginTx(ctx, nil)
@@ -595,25 +943,57 @@ func (r *OrderRepository) createOrderTx(ctx context.Context, tx *sql.Tx, order o
 	logger.Debug().
 		Uint64("tenant_id", order.TenantID).
 		Uint64("user_id", order.IdUser).
-		Float64("total_price", order.Price).
+		Stringer("total_price", order.Price).
 		Str("status", string(order.Status)).
 		Msg("Creating order for user")
 
-	query := `INSERT INTO orders (tenant_id, id_user, total_price, status, note, delivery_date, delivery_direction, paid, expires_at) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9) RETURNING id_order`
+	query := `INSERT INTO orders (tenant_id, id_user, total_price, status, note, delivery_date, delivery_direction, paid, expires_at, tracking_token_hash, discount_amount, promotion_code, id_promotion, subtotal, tax_rate, tax_inclusive, tax_amount, delivery_fee, id_delivery_zone, fulfillment_type, id_pickup_location, sales_channel, currency) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18, $19, $20, $21, $22, (SELECT currency FROM tenants WHERE id = $1)) RETURNING id_order`
+
+	var idPromotion sql.NullInt64
+	if order.IDPromotion != nil {
+		idPromotion = sql.NullInt64{Int64: int64(*order.IDPromotion), Valid: true}
+	}
+	var idDeliveryZone sql.NullInt64
+	if order.IDDeliveryZone != nil {
+		idDeliveryZone = sql.NullInt64{Int64: int64(*order.IDDeliveryZone), Valid: true}
+	}
+	var idPickupLocation sql.NullInt64
+	if order.IDPickupLocation != nil {
+		idPickupLocation = sql.NullInt64{Int64: int64(*order.IDPickupLocation), Valid: true}
+	}
+
+	// Staff orders taken without an email have no customer (id_user NULL).
+	var idUser sql.NullInt64
+	if order.IdUser != 0 {
+		idUser = sql.NullInt64{Int64: int64(order.IdUser), Valid: true}
+	}
 
 	var insertedID uint64
 	err = tx.QueryRowContext(
 		ctx,
 		query,
 		order.TenantID,
-		order.IdUser,
+		idUser,
 		order.Price,
 		order.Status,
 		order.Note,
 		order.DeliveryDate,
 		order.DeliveryDirection,
 		order.Paid,
-		order.ExpiresAt,
+		sql.NullTime{Time: order.ExpiresAt, Valid: !order.ExpiresAt.IsZero()},
+		sql.NullString{String: order.TrackingTokenHash, Valid: order.TrackingTokenHash != ""},
+		order.DiscountAmount,
+		nullStringFromPtr(order.PromotionCode),
+		idPromotion,
+		order.Subtotal,
+		order.TaxRate,
+		order.TaxInclusive,
+		order.TaxAmount,
+		order.DeliveryFee,
+		idDeliveryZone,
+		oModel.NormalizeFulfillmentType(order.FulfillmentType),
+		idPickupLocation,
+		oModel.NormalizeSalesChannel(order.SalesChannel),
 	).Scan(&insertedID)
 
 	if err != nil {
@@ -647,7 +1027,7 @@ func (r *OrderRepository) createOrderItemTx(ctx context.Context, tx *sql.Tx, ten
 				Uint64("order_id", item.IdOrder).
 				Uint64("product_id", item.IdProduct).
 				Str("product_name_snapshot", item.ProductNameSnapshot).
-				Float64("unit_price_snapshot", item.UnitPriceSnapshot).
+				Stringer("unit_price_snapshot", item.UnitPriceSnapshot).
 				Uint64("quantity", item.Quantity).
 				Msg("Error inserting order item")
 			return errors.NewInternalServerError(errors.ErrCreatingOrderItem)
@@ -715,7 +1095,9 @@ func (r *OrderRepository) createOrderHistoryExec(tx *sql.Tx, fallback *sql.DB, o
 		paid,
 		cancellation_reason,
 		modified_by, 
-		action
+		action,
+		discount_amount,
+		promotion_code
 		) 
 		VALUES (
 		$1,
@@ -729,7 +1111,9 @@ func (r *OrderRepository) createOrderHistoryExec(tx *sql.Tx, fallback *sql.DB, o
 		$9, 
 		$10, 
 		$11,
-		$12)`,
+		$12,
+		$13,
+		$14)`,
 		order.TenantID,
 		order.IDOrder,
 		idUserVal,
@@ -742,6 +1126,8 @@ func (r *OrderRepository) createOrderHistoryExec(tx *sql.Tx, fallback *sql.DB, o
 		nullStringFromPtr(order.CancellationReason),
 		order.ModifiedBy,
 		order.Action,
+		order.DiscountAmount,
+		nullStringFromPtr(order.PromotionCode),
 	)
 
 	if err != nil {
@@ -765,6 +1151,13 @@ func (r *OrderRepository) createOrderHistoryExec(tx *sql.Tx, fallback *sql.DB, o
 		return errors.NewNotFound(errors.ErrOrderNotFound)
 	}
 
+	if err := notifyOrderEvents(context.Background(), exec, order.TenantID); err != nil {
+		logger.Err(err).
+			Uint64("order_id", order.IDOrder).
+			Msg("Error notifying the order history row")
+		return errors.NewInternalServerError(errors.ErrCreatingOrderHistory)
+	}
+
 	logger.Debug().
 		Uint64("order_id", order.IDOrder).
 		Int64("rows_affected", rows).
@@ -777,7 +1170,8 @@ func (r *OrderRepository) createOrderHistoryExec(tx *sql.Tx, fallback *sql.DB, o
 func (r *OrderRepository) GetOrderHistoryByOrderID(ctx context.Context, tenantID, orderID uint64) ([]oModel.OrderHistory, error) {
 	query := `
 		SELECT id_order_history, tenant_id, id_order, id_user, status, total_price, note, 
-			delivery_date, delivery_direction, paid, cancellation_reason, modified_on, modified_by, action
+			delivery_date, delivery_direction, paid, cancellation_reason, modified_on, modified_by, action,
+			discount_amount, promotion_code
 		FROM orders_history 
 		WHERE id_order = $1 AND tenant_id = $2
 		ORDER BY modified_on DESC
@@ -795,6 +1189,7 @@ func (r *OrderRepository) GetOrderHistoryByOrderID(ctx context.Context, tenantID
 			history            oModel.OrderHistory
 			idUser             sql.NullInt64
 			cancellationReason sql.NullString
+			promotionCode      sql.NullString
 		)
 		err := rows.Scan(
 			&history.ID,
@@ -811,6 +1206,8 @@ func (r *OrderRepository) GetOrderHistoryByOrderID(ctx context.Context, tenantID
 			&history.ModifiedOn,
 			&history.ModifiedBy,
 			&history.Action,
+			&history.DiscountAmount,
+			&promotionCode,
 		)
 		if err != nil {
 			return nil, fmt.Errorf("error scanning order history row: %w", err)
@@ -822,6 +1219,9 @@ func (r *OrderRepository) GetOrderHistoryByOrderID(ctx context.Context, tenantID
 		if cancellationReason.Valid {
 			history.CancellationReason = &cancellationReason.String
 		}
+		if promotionCode.Valid {
+			history.PromotionCode = &promotionCode.String
+		}
 		histories = append(histories, history)
 	}
 
@@ -832,6 +1232,80 @@ func (r *OrderRepository) GetOrderHistoryByOrderID(ctx context.Context, tenantID
 	return histories, nil
 }
 
+// GetOrderHistoryTimeline returns the order history oldest first, with the name of the user
+// in modified_by resolved within the same tenant (empty when the actor is the system or was removed).
+// Changes are not computed here; see services/orders.HistoryReader.
+func (r *OrderRepository) GetOrderHistoryTimeline(ctx context.Context, tenantID, orderID uint64) ([]oModel.OrderHistoryEntry, error) {
+	query := `
+		SELECT oh.id_order_history, oh.action, oh.status, oh.total_price, COALESCE(oh.note, ''),
+			oh.delivery_date, COALESCE(oh.delivery_direction, ''), COALESCE(oh.paid, false), oh.cancellation_reason,
+			oh.modified_on, COALESCE(oh.modified_by, 0), u.name, oh.discount_amount, oh.promotion_code
+		FROM orders_history oh
+		LEFT JOIN users u ON u.id_user = oh.modified_by AND u.tenant_id = oh.tenant_id
+		WHERE oh.id_order = $1 AND oh.tenant_id = $2
+		ORDER BY oh.modified_on ASC, oh.id_order_history ASC
+	`
+
+	rows, err := r.DB.QueryContext(ctx, query, orderID, tenantID)
+	if err != nil {
+		return nil, fmt.Errorf("error querying order history timeline: %w", err)
+	}
+	defer rows.Close()
+
+	entries := []oModel.OrderHistoryEntry{}
+	for rows.Next() {
+		var (
+			entry              oModel.OrderHistoryEntry
+			deliveryDate       sql.NullTime
+			cancellationReason sql.NullString
+			modifiedOn         sql.NullTime
+			modifiedByName     sql.NullString
+			promotionCode      sql.NullString
+		)
+		err := rows.Scan(
+			&entry.ID,
+			&entry.Action,
+			&entry.Status,
+			&entry.Price,
+			&entry.Note,
+			&deliveryDate,
+			&entry.DeliveryDirection,
+			&entry.Paid,
+			&cancellationReason,
+			&modifiedOn,
+			&entry.ModifiedBy,
+			&modifiedByName,
+			&entry.DiscountAmount,
+			&promotionCode,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("error scanning order history timeline row: %w", err)
+		}
+		if deliveryDate.Valid {
+			entry.DeliveryDate = &deliveryDate.Time
+		}
+		if cancellationReason.Valid {
+			entry.CancellationReason = &cancellationReason.String
+		}
+		if modifiedOn.Valid {
+			entry.ModifiedOn = &modifiedOn.Time
+		}
+		if modifiedByName.Valid {
+			entry.ModifiedByName = modifiedByName.String
+		}
+		if promotionCode.Valid {
+			entry.PromotionCode = &promotionCode.String
+		}
+		entries = append(entries, entry)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating order history timeline rows: %w", err)
+	}
+
+	return entries, nil
+}
+
 // GetExpiredPendingOrders returns orders that are pending, unpaid, and created before the given expiration time (ghost orders) for a tenant.
 func (r *OrderRepository) GetExpiredPendingOrders(ctx context.Context, tenantID uint64, expirationTime time.Time) ([]oModel.Order, error) {
 	query := `
@@ -887,6 +1361,9 @@ func (r *OrderRepository) GetExpiredPendingOrders(ctx context.Context, tenantID
 // the claimed rows. Used by the ghost-order cron so that overlapping runs or multiple workers
 // never process the same order twice. Must be called within an existing transaction; the caller
 // then performs stock reversion and history insert for each claimed order before committing.
+// Orders with an in-flight payment (pending and not yet past its own expires_at) are skipped so
+// a customer who is paying at the checkout page is not cancelled under them, and so are orders
+// with any line in the order_payments ledger (e.g. a deposit), whose money staff must settle.
 func (r *OrderRepository) ClaimExpiredPendingOrdersTx(
 	ctx context.Context,
 	tx *sql.Tx,
@@ -899,7 +1376,17 @@ func (r *OrderRepository) ClaimExpiredPendingOrdersTx(
 		UPDATE orders
 		SET status = $1, cancellation_reason = $2
 		WHERE tenant_id = $3 AND status = 'pending' AND paid = false AND expires_at < $4
-		RETURNING id_order, tenant_id, id_user, total_price, status, note, created_on, delivery_date, delivery_direction, paid, cancellation_reason
+		  AND NOT EXISTS (
+			SELECT 1 FROM payments p
+			WHERE p.tenant_id = orders.tenant_id AND p.id_order = orders.id_order
+			  AND p.status = 'pending' AND p.expires_at > $4
+		  )
+		  AND NOT EXISTS (
+			SELECT 1 FROM order_payments op
+			WHERE op.tenant_id = orders.tenant_id AND op.id_order = orders.id_order
+		  )
+		RETURNING id_order, tenant_id, id_user, total_price, status, note, created_on, delivery_date, delivery_direction, paid, cancellation_reason,
+			discount_amount, promotion_code
 	`
 	rows, err := tx.QueryContext(ctx, query, status, nullStringFromPtr(cancellationReason), tenantID, currentTime)
 	if err != nil {
@@ -910,7 +1397,7 @@ func (r *OrderRepository) ClaimExpiredPendingOrdersTx(
 	var orders []oModel.Order
 	for rows.Next() {
 		var o oModel.Order
-		var note, deliveryDirection, reason sql.NullString
+		var note, deliveryDirection, reason, promotionCode sql.NullString
 		err := rows.Scan(
 			&o.ID,
 			&o.TenantID,
@@ -923,6 +1410,8 @@ func (r *OrderRepository) ClaimExpiredPendingOrdersTx(
 			&deliveryDirection,
 			&o.Paid,
 			&reason,
+			&o.DiscountAmount,
+			&promotionCode,
 		)
 		if err != nil {
 			return nil, fmt.Errorf("error scanning claimed order row: %w", err)
@@ -936,29 +1425,40 @@ func (r *OrderRepository) ClaimExpiredPendingOrdersTx(
 		if reason.Valid {
 			o.CancellationReason = &reason.String
 		}
+		if promotionCode.Valid {
+			o.PromotionCode = &promotionCode.String
+		}
 		orders = append(orders, o)
 	}
 	if err := rows.Err(); err != nil {
 		return nil, fmt.Errorf("error iterating claimed orders: %w", err)
 	}
+	if len(orders) > 0 {
+		if err := notifyOrderEvents(ctx, tx, tenantID); err != nil {
+			return nil, err
+		}
+	}
 	return orders, nil
 }
 
-// UpdateOrderStatus updates the status of an order and optionally sets the cancellation reason.
-func (r *OrderRepository) UpdateOrderStatus(ctx context.Context, tenantID, orderID uint64, status oModel.OrderStatus, cancellationReason *string) error {
-	return r.updateOrderStatusExec(ctx, r.DB, tenantID, orderID, status, cancellationReason)
+// UpdateOrderStatus moves an order from status `from` to `status` and optionally sets the cancellation reason.
+// It returns ErrOrderStatusChanged when the order is no longer in `from` (a concurrent update won).
+func (r *OrderRepository) UpdateOrderStatus(ctx context.Context, tenantID, orderID uint64, from, status oModel.OrderStatus, cancellationReason *string) error {
+	return r.updateOrderStatusExec(ctx, r.DB, tenantID, orderID, from, status, cancellationReason)
 }
 
-// UpdateOrderStatusTx updates the order status within a transaction (for use in CancelExpiredOrders).
-func (r *OrderRepository) UpdateOrderStatusTx(ctx context.Context, tx *sql.Tx, tenantID, orderID uint64, status oModel.OrderStatus, cancellationReason *string) error {
-	return r.updateOrderStatusExec(ctx, tx, tenantID, orderID, status, cancellationReason)
+// UpdateOrderStatusTx is UpdateOrderStatus within a transaction.
+func (r *OrderRepository) UpdateOrderStatusTx(ctx context.Context, tx *sql.Tx, tenantID, orderID uint64, from, status oModel.OrderStatus, cancellationReason *string) error {
+	return r.updateOrderStatusExec(ctx, tx, tenantID, orderID, from, status, cancellationReason)
 }
 
 func (r *OrderRepository) updateOrderStatusExec(ctx context.Context, exec interface {
 	ExecContext(context.Context, string, ...any) (sql.Result, error)
-}, tenantID, orderID uint64, status oModel.OrderStatus, cancellationReason *string) error {
-	query := `UPDATE orders SET status = $1, cancellation_reason = $2 WHERE id_order = $3 AND tenant_id = $4`
-	result, err := exec.ExecContext(ctx, query, status, nullStringFromPtr(cancellationReason), orderID, tenantID)
+}, tenantID, orderID uint64, from, status oModel.OrderStatus, cancellationReason *string) error {
+	// The status guard makes the transition atomic: a concurrent update blocks on the row lock and
+	// then finds the status already changed, so the state machine is never bypassed.
+	query := `UPDATE orders SET status = $1, cancellation_reason = $2 WHERE id_order = $3 AND tenant_id = $4 AND status = $5`
+	result, err := exec.ExecContext(ctx, query, status, nullStringFromPtr(cancellationReason), orderID, tenantID, from)
 	if err != nil {
 		return fmt.Errorf("error updating order status: %w", err)
 	}
@@ -967,7 +1467,7 @@ func (r *OrderRepository) updateOrderStatusExec(ctx context.Context, exec interf
 		return fmt.Errorf("error getting rows affected: %w", err)
 	}
 	if rowsAffected == 0 {
-		return errors.NewNotFound(errors.ErrOrderNotFound)
+		return errors.ErrOrderStatusChanged
 	}
 	return nil
 }
@@ -0,0 +1,155 @@
+package order
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/lib/pq"
+	"github.com/radamesvaz/bakery-app/internal/logger"
+	oModel "github.com/radamesvaz/bakery-app/model/orders"
+)
+
+// OrderEventsChannel is the Postgres NOTIFY channel order writes signal on. The payload is the
+// tenant id; listeners read the new orders_history rows themselves, so a notification that is
+// lost or repeated only delays or repeats a read.
+const OrderEventsChannel = "order_events"
+
+// notifyOrderEvents signals the order_events channel for tenantID. Inside a transaction the
+// notification is delivered on commit and dropped on rollback.
+func notifyOrderEvents(ctx context.Context, exec interface {
+	ExecContext(context.Context, string, ...any) (sql.Result, error)
+}, tenantID uint64) error {
+	if _, err := exec.ExecContext(ctx, `SELECT pg_notify($1, $2)`, OrderEventsChannel, strconv.FormatUint(tenantID, 10)); err != nil {
+		return fmt.Errorf("error notifying order events: %w", err)
+	}
+	return nil
+}
+
+// SettledOrderHistoryID returns the newest id_order_history of the tenant written more than
+// settleWindow ago (0 when there is none), where a stream opened without Last-Event-ID starts.
+// modified_on is compared with the database clock, which also wrote it.
+func (r *OrderRepository) SettledOrderHistoryID(ctx context.Context, tenantID uint64, settleWindow time.Duration) (uint64, error) {
+	var id uint64
+	err := r.DB.QueryRowContext(ctx,
+		`SELECT COALESCE(MAX(id_order_history), 0) FROM orders_history
+		WHERE tenant_id = $1 AND modified_on < LOCALTIMESTAMP - $2 * INTERVAL '1 second'`,
+		tenantID, settleWindow.Seconds(),
+	).Scan(&id)
+	if err != nil {
+		return 0, fmt.Errorf("error getting settled order history id: %w", err)
+	}
+	return id, nil
+}
+
+// ListOrderStreamEvents returns up to limit history rows of the tenant after afterID, oldest
+// first, each classified against the previous row of its order.
+func (r *OrderRepository) ListOrderStreamEvents(ctx context.Context, tenantID, afterID uint64, limit int) ([]oModel.OrderStreamEvent, error) {
+	query := `
+		SELECT h.id_order_history, h.id_order, h.action, h.status, h.paid, h.total_price, h.delivery_date,
+			h.modified_on, prev.status, prev.paid
+		FROM orders_history h
+		LEFT JOIN LATERAL (
+			SELECT p.status, p.paid
+			FROM orders_history p
+			WHERE p.tenant_id = h.tenant_id AND p.id_order = h.id_order AND p.id_order_history < h.id_order_history
+			ORDER BY p.id_order_history DESC
+			LIMIT 1
+		) prev ON TRUE
+		WHERE h.tenant_id = $1 AND h.id_order_history > $2 AND h.id_order IS NOT NULL
+		ORDER BY h.id_order_history ASC
+		LIMIT $3
+	`
+	rows, err := r.DB.QueryContext(ctx, query, tenantID, afterID, limit)
+	if err != nil {
+		return nil, fmt.Errorf("error listing order stream events: %w", err)
+	}
+	defer rows.Close()
+
+	events := []oModel.OrderStreamEvent{}
+	for rows.Next() {
+		var (
+			e            oModel.OrderStreamEvent
+			action       string
+			deliveryDate sql.NullTime
+			modifiedOn   sql.NullTime
+			prevStatus   sql.NullString
+			prevPaid     sql.NullBool
+		)
+		if err := rows.Scan(&e.ID, &e.IDOrder, &action, &e.Status, &e.Paid, &e.TotalPrice, &deliveryDate,
+			&modifiedOn, &prevStatus, &prevPaid); err != nil {
+			return nil, fmt.Errorf("error scanning order stream event: %w", err)
+		}
+		if deliveryDate.Valid {
+			d := deliveryDate.Time
+			e.DeliveryDate = &d
+		}
+		if modifiedOn.Valid {
+			e.At = modifiedOn.Time
+		}
+		var prevPaidPtr *bool
+		if prevStatus.Valid {
+			s := oModel.OrderStatus(prevStatus.String)
+			e.PreviousStatus = &s
+			prevPaidPtr = &prevPaid.Bool
+		}
+		e.Type = oModel.ClassifyOrderStreamEvent(oModel.OrderAction(action), e.Status, e.Paid, e.PreviousStatus, prevPaidPtr)
+		events = append(events, e)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating order stream events: %w", err)
+	}
+	return events, nil
+}
+
+// OrderEventListener holds a dedicated LISTEN connection on OrderEventsChannel, so every API
+// instance hears the order writes of all the others.
+type OrderEventListener struct {
+	listener *pq.Listener
+}
+
+// orderEventListenerPing is how often an idle listener checks its connection.
+const orderEventListenerPing = 90 * time.Second
+
+// NewOrderEventListener connects to dsn and listens on OrderEventsChannel. dsn must reach Postgres
+// directly or through a session pooler; transaction poolers do not deliver notifications.
+func NewOrderEventListener(dsn string) (*OrderEventListener, error) {
+	listener := pq.NewListener(dsn, time.Second, 30*time.Second, func(ev pq.ListenerEventType, err error) {
+		if err != nil {
+			logger.Warn().Err(err).Int("event", int(ev)).Msg("Order events listener connection problem")
+		}
+	})
+	if err := listener.Listen(OrderEventsChannel); err != nil {
+		_ = listener.Close()
+		return nil, fmt.Errorf("error listening on %s: %w", OrderEventsChannel, err)
+	}
+	return &OrderEventListener{listener: listener}, nil
+}
+
+// Run calls onTenant with the tenant of each notification until ctx ends, then closes the
+// connection. onReconnect is called after the connection was re-established, when notifications
+// may have been missed.
+func (l *OrderEventListener) Run(ctx context.Context, onTenant func(tenantID uint64), onReconnect func()) {
+	defer l.listener.Close()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case n := <-l.listener.Notify:
+			if n == nil {
+				onReconnect()
+				continue
+			}
+			tenantID, err := strconv.ParseUint(n.Extra, 10, 64)
+			if err != nil {
+				logger.Warn().Str("payload", n.Extra).Msg("Ignoring malformed order event notification")
+				continue
+			}
+			onTenant(tenantID)
+		case <-time.After(orderEventListenerPing):
+			go func() { _ = l.listener.Ping() }()
+		}
+	}
+}
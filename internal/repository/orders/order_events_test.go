@@ -0,0 +1,65 @@
+package order
+
+import (
+	"context"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/radamesvaz/bakery-app/model/money"
+	oModel "github.com/radamesvaz/bakery-app/model/orders"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOrderRepository_ListOrderStreamEvents(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	repo := &OrderRepository{DB: db}
+	modifiedOn := time.Date(2026, 3, 2, 9, 0, 0, 0, time.UTC)
+	deliveryDate := time.Date(2026, 3, 4, 0, 0, 0, 0, time.UTC)
+	mock.ExpectQuery(regexp.QuoteMeta(`WHERE h.tenant_id = $1 AND h.id_order_history > $2 AND h.id_order IS NOT NULL`)).
+		WithArgs(uint64(1), uint64(40), 50).
+		WillReturnRows(sqlmock.NewRows([]string{
+			"id_order_history", "id_order", "action", "status", "paid", "total_price", "delivery_date",
+			"modified_on", "status", "paid",
+		}).
+			AddRow(41, 7, "create", "pending", false, 12.5, deliveryDate, modifiedOn, nil, nil).
+			AddRow(42, 7, "update", "pending", true, 12.5, deliveryDate, modifiedOn, "pending", false).
+			AddRow(43, 7, "update", "preparing", true, 12.5, nil, modifiedOn, "pending", true))
+
+	events, err := repo.ListOrderStreamEvents(context.Background(), 1, 40, 50)
+
+	require.NoError(t, err)
+	require.Len(t, events, 3)
+	assert.Equal(t, oModel.StreamOrderCreated, events[0].Type)
+	assert.Nil(t, events[0].PreviousStatus)
+	assert.Equal(t, money.Amount(1250), events[0].TotalPrice)
+	assert.Equal(t, oModel.StreamOrderPaid, events[1].Type)
+	assert.Equal(t, uint64(43), events[2].ID)
+	assert.Equal(t, oModel.StreamOrderStatusChanged, events[2].Type)
+	require.NotNil(t, events[2].PreviousStatus)
+	assert.Equal(t, oModel.StatusPending, *events[2].PreviousStatus)
+	assert.Nil(t, events[2].DeliveryDate)
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestOrderRepository_SettledOrderHistoryID(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	repo := &OrderRepository{DB: db}
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT COALESCE(MAX(id_order_history), 0) FROM orders_history`)).
+		WithArgs(uint64(1), float64(30)).
+		WillReturnRows(sqlmock.NewRows([]string{"coalesce"}).AddRow(42))
+
+	id, err := repo.SettledOrderHistoryID(context.Background(), 1, 30*time.Second)
+
+	require.NoError(t, err)
+	assert.Equal(t, uint64(42), id)
+	require.NoError(t, mock.ExpectationsWereMet())
+}
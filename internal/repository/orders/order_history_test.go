@@ -40,7 +40,7 @@ func TestOrderRepository_CreateOrderHistory(t *testing.T) {
 				IDOrder:      1,
 				IdUser:       &idUserOne,
 				Status:       oModel.StatusPending,
-				Price:        50.0,
+				Price:        5000,
 				Note:         "Test order",
 				DeliveryDate: sql.NullTime{Time: deliveryDate, Valid: true},
 				Paid:         false,
@@ -57,7 +57,7 @@ func TestOrderRepository_CreateOrderHistory(t *testing.T) {
 				IDOrder:      1,
 				IdUser:       &idUserOne,
 				Status:       oModel.StatusPreparing,
-				Price:        50.0,
+				Price:        5000,
 				Note:         "Updated order",
 				DeliveryDate: sql.NullTime{Time: deliveryDate, Valid: true},
 				Paid:         true,
@@ -74,7 +74,7 @@ func TestOrderRepository_CreateOrderHistory(t *testing.T) {
 				IDOrder:      1,
 				IdUser:       &idUserOne,
 				Status:       oModel.StatusCancelled,
-				Price:        50.0,
+				Price:        5000,
 				Note:         "Cancelled order",
 				DeliveryDate: sql.NullTime{Time: deliveryDate, Valid: true},
 				Paid:         false,
@@ -91,7 +91,7 @@ func TestOrderRepository_CreateOrderHistory(t *testing.T) {
 				IDOrder:      1,
 				IdUser:       &idUserOne,
 				Status:       oModel.StatusPending,
-				Price:        50.0,
+				Price:        5000,
 				Note:         "Test order",
 				DeliveryDate: sql.NullTime{Time: deliveryDate, Valid: true},
 				Paid:         false,
@@ -120,7 +120,9 @@ func TestOrderRepository_CreateOrderHistory(t *testing.T) {
 		paid,
 		cancellation_reason,
 		modified_by, 
-		action
+		action,
+		discount_amount,
+		promotion_code
 		) 
 		VALUES (
 		$1,
@@ -134,7 +136,9 @@ func TestOrderRepository_CreateOrderHistory(t *testing.T) {
 		$9, 
 		$10, 
 		$11,
-		$12)`,
+		$12,
+		$13,
+		$14)`,
 					),
 				).WillReturnError(tt.mockError)
 			} else {
@@ -152,7 +156,9 @@ func TestOrderRepository_CreateOrderHistory(t *testing.T) {
 		paid,
 		cancellation_reason,
 		modified_by, 
-		action
+		action,
+		discount_amount,
+		promotion_code
 		) 
 		VALUES (
 		$1,
@@ -166,9 +172,14 @@ func TestOrderRepository_CreateOrderHistory(t *testing.T) {
 		$9, 
 		$10, 
 		$11,
-		$12)`,
+		$12,
+		$13,
+		$14)`,
 					),
 				).WillReturnResult(sqlmock.NewResult(1, 1))
+				mock.ExpectExec(regexp.QuoteMeta(`SELECT pg_notify($1, $2)`)).
+					WithArgs(OrderEventsChannel, "1").
+					WillReturnResult(sqlmock.NewResult(0, 1))
 			}
 
 			err := repo.CreateOrderHistory(context.Background(), tt.payload)
@@ -183,3 +194,72 @@ func TestOrderRepository_CreateOrderHistory(t *testing.T) {
 		})
 	}
 }
+
+func TestOrderRepository_GetOrderHistoryTimeline(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("Error setting up the mock: %v", err)
+	}
+	defer db.Close()
+
+	repo := &OrderRepository{DB: db}
+
+	query := regexp.QuoteMeta(`
+		SELECT oh.id_order_history, oh.action, oh.status, oh.total_price, COALESCE(oh.note, ''),
+			oh.delivery_date, COALESCE(oh.delivery_direction, ''), COALESCE(oh.paid, false), oh.cancellation_reason,
+			oh.modified_on, COALESCE(oh.modified_by, 0), u.name, oh.discount_amount, oh.promotion_code
+		FROM orders_history oh
+		LEFT JOIN users u ON u.id_user = oh.modified_by AND u.tenant_id = oh.tenant_id
+		WHERE oh.id_order = $1 AND oh.tenant_id = $2
+		ORDER BY oh.modified_on ASC, oh.id_order_history ASC
+	`)
+	columns := []string{
+		"id_order_history", "action", "status", "total_price", "note",
+		"delivery_date", "delivery_direction", "paid", "cancellation_reason",
+		"modified_on", "modified_by", "name", "discount_amount", "promotion_code",
+	}
+	deliveryDate := time.Date(2026, 1, 10, 0, 0, 0, 0, time.UTC)
+	modifiedOn := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	t.Run("HAPPY PATH: resolves actor names and nullable columns", func(t *testing.T) {
+		mock.ExpectQuery(query).
+			WithArgs(uint64(5), uint64(1)).
+			WillReturnRows(sqlmock.NewRows(columns).
+				AddRow(1, "create", "pending", 50.0, "note", deliveryDate, "Main St", false, nil, modifiedOn, 2, "Ana", 0.0, nil).
+				AddRow(2, "update", "cancelled", 50.0, "note", nil, "Main St", false, "expired", modifiedOn, 0, nil, 0.0, nil))
+
+		entries, err := repo.GetOrderHistoryTimeline(context.Background(), 1, 5)
+		assert.NoError(t, err)
+		assert.Len(t, entries, 2)
+		assert.Equal(t, "Ana", entries[0].ModifiedByName)
+		assert.NotNil(t, entries[0].DeliveryDate)
+		assert.Nil(t, entries[0].CancellationReason)
+		assert.Equal(t, uint64(0), entries[1].ModifiedBy)
+		assert.Empty(t, entries[1].ModifiedByName)
+		assert.Nil(t, entries[1].DeliveryDate)
+		assert.Equal(t, "expired", *entries[1].CancellationReason)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("HAPPY PATH: no history returns empty slice", func(t *testing.T) {
+		mock.ExpectQuery(query).
+			WithArgs(uint64(5), uint64(1)).
+			WillReturnRows(sqlmock.NewRows(columns))
+
+		entries, err := repo.GetOrderHistoryTimeline(context.Background(), 1, 5)
+		assert.NoError(t, err)
+		assert.NotNil(t, entries)
+		assert.Empty(t, entries)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("ERROR PATH: database error", func(t *testing.T) {
+		mock.ExpectQuery(query).
+			WithArgs(uint64(5), uint64(1)).
+			WillReturnError(assert.AnError)
+
+		_, err := repo.GetOrderHistoryTimeline(context.Background(), 1, 5)
+		assert.Error(t, err)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
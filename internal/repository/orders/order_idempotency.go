@@ -0,0 +1,69 @@
+package order
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	oModel "github.com/radamesvaz/bakery-app/model/orders"
+)
+
+// ReserveIdempotencyKeyTx claims key for the creating transaction. Keys created before
+// expiredBefore are taken over. When another transaction holds the same key, PostgreSQL
+// blocks on the unique index until it finishes, so reserved=false means the key was
+// committed by someone else and the caller should replay it.
+func (r *OrderRepository) ReserveIdempotencyKeyTx(ctx context.Context, tx *sql.Tx, tenantID uint64, key, requestHash string, expiredBefore time.Time) (reserved bool, err error) {
+	query := `INSERT INTO order_idempotency_keys (tenant_id, idempotency_key, request_hash)
+VALUES ($1, $2, $3)
+ON CONFLICT (tenant_id, idempotency_key) DO UPDATE
+SET request_hash = EXCLUDED.request_hash, id_order = NULL, created_on = NOW()
+WHERE order_idempotency_keys.created_on < $4
+RETURNING id`
+
+	var id uint64
+	err = tx.QueryRowContext(ctx, query, tenantID, key, requestHash, expiredBefore).Scan(&id)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return false, nil
+		}
+		return false, fmt.Errorf("error reserving idempotency key: %w", err)
+	}
+	return true, nil
+}
+
+// SetIdempotencyKeyOrderTx links a reserved key to the order created in the same transaction.
+func (r *OrderRepository) SetIdempotencyKeyOrderTx(ctx context.Context, tx *sql.Tx, tenantID uint64, key string, orderID uint64) error {
+	_, err := tx.ExecContext(ctx,
+		`UPDATE order_idempotency_keys SET id_order = $1 WHERE tenant_id = $2 AND idempotency_key = $3`,
+		orderID, tenantID, key,
+	)
+	if err != nil {
+		return fmt.Errorf("error linking idempotency key to order: %w", err)
+	}
+	return nil
+}
+
+// GetIdempotencyKey returns the stored key, or nil when it does not exist or was created before expiredBefore.
+func (r *OrderRepository) GetIdempotencyKey(ctx context.Context, tenantID uint64, key string, expiredBefore time.Time) (*oModel.OrderIdempotencyKey, error) {
+	query := `SELECT idempotency_key, request_hash, id_order, created_on
+FROM order_idempotency_keys
+WHERE tenant_id = $1 AND idempotency_key = $2 AND created_on >= $3`
+
+	var (
+		out     oModel.OrderIdempotencyKey
+		idOrder sql.NullInt64
+	)
+	err := r.DB.QueryRowContext(ctx, query, tenantID, key, expiredBefore).Scan(&out.Key, &out.RequestHash, &idOrder, &out.CreatedOn)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("error getting idempotency key: %w", err)
+	}
+	if idOrder.Valid {
+		id := uint64(idOrder.Int64)
+		out.IDOrder = &id
+	}
+	return &out, nil
+}
@@ -0,0 +1,81 @@
+package order
+
+import (
+	"context"
+	"database/sql"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOrderRepository_ReserveIdempotencyKeyTx(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	repo := &OrderRepository{DB: db}
+	expiredBefore := time.Date(2026, 4, 1, 10, 0, 0, 0, time.UTC)
+	query := regexp.QuoteMeta(`INSERT INTO order_idempotency_keys (tenant_id, idempotency_key, request_hash)`)
+
+	t.Run("new key is reserved", func(t *testing.T) {
+		mock.ExpectBegin()
+		mock.ExpectQuery(query).WithArgs(uint64(1), "key-1", "hash", expiredBefore).
+			WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+		mock.ExpectRollback()
+
+		tx, err := db.Begin()
+		require.NoError(t, err)
+		reserved, err := repo.ReserveIdempotencyKeyTx(context.Background(), tx, 1, "key-1", "hash", expiredBefore)
+		require.NoError(t, err)
+		assert.True(t, reserved)
+		require.NoError(t, tx.Rollback())
+		require.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("live key held by another request is not reserved", func(t *testing.T) {
+		mock.ExpectBegin()
+		mock.ExpectQuery(query).WithArgs(uint64(1), "key-1", "hash", expiredBefore).WillReturnError(sql.ErrNoRows)
+		mock.ExpectRollback()
+
+		tx, err := db.Begin()
+		require.NoError(t, err)
+		reserved, err := repo.ReserveIdempotencyKeyTx(context.Background(), tx, 1, "key-1", "hash", expiredBefore)
+		require.NoError(t, err)
+		assert.False(t, reserved)
+		require.NoError(t, tx.Rollback())
+		require.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestOrderRepository_GetIdempotencyKey(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	repo := &OrderRepository{DB: db}
+	expiredBefore := time.Date(2026, 4, 1, 10, 0, 0, 0, time.UTC)
+	createdOn := expiredBefore.Add(time.Hour)
+	query := regexp.QuoteMeta(`SELECT idempotency_key, request_hash, id_order, created_on
+FROM order_idempotency_keys
+WHERE tenant_id = $1 AND idempotency_key = $2 AND created_on >= $3`)
+
+	mock.ExpectQuery(query).WithArgs(uint64(1), "key-1", expiredBefore).
+		WillReturnRows(sqlmock.NewRows([]string{"idempotency_key", "request_hash", "id_order", "created_on"}).AddRow("key-1", "hash", 9, createdOn))
+	key, err := repo.GetIdempotencyKey(context.Background(), 1, "key-1", expiredBefore)
+	require.NoError(t, err)
+	require.NotNil(t, key)
+	require.NotNil(t, key.IDOrder)
+	assert.Equal(t, uint64(9), *key.IDOrder)
+	assert.Equal(t, "hash", key.RequestHash)
+
+	mock.ExpectQuery(query).WithArgs(uint64(1), "key-2", expiredBefore).WillReturnError(sql.ErrNoRows)
+	key, err = repo.GetIdempotencyKey(context.Background(), 1, "key-2", expiredBefore)
+	require.NoError(t, err)
+	assert.Nil(t, key)
+
+	require.NoError(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,158 @@
+package order
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/lib/pq"
+
+	"github.com/radamesvaz/bakery-app/internal/errors"
+	"github.com/radamesvaz/bakery-app/model/money"
+	oModel "github.com/radamesvaz/bakery-app/model/orders"
+)
+
+// netAmountPaidExpr sums the ledger of an order, refunds subtracted.
+const netAmountPaidExpr = `COALESCE(SUM(CASE WHEN op.is_refund THEN -op.amount ELSE op.amount END), 0)`
+
+// CreateOrderPaymentTx appends a line to the order_payments ledger.
+func (r *OrderRepository) CreateOrderPaymentTx(ctx context.Context, tx *sql.Tx, p oModel.OrderPaymentRequest) (oModel.OrderPayment, error) {
+	query := `INSERT INTO order_payments (tenant_id, id_order, amount, method, reference, is_refund, recorded_by_user_id, id_payment)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+RETURNING id, created_on`
+
+	var recordedBy, idPayment sql.NullInt64
+	if p.RecordedBy != nil {
+		recordedBy = sql.NullInt64{Int64: int64(*p.RecordedBy), Valid: true}
+	}
+	if p.IDPayment != nil {
+		idPayment = sql.NullInt64{Int64: int64(*p.IDPayment), Valid: true}
+	}
+
+	out := oModel.OrderPayment{
+		TenantID:   p.TenantID,
+		IDOrder:    p.IDOrder,
+		Amount:     p.Amount,
+		Method:     p.Method,
+		Reference:  p.Reference,
+		IsRefund:   p.IsRefund,
+		RecordedBy: p.RecordedBy,
+		IDPayment:  p.IDPayment,
+	}
+	err := tx.QueryRowContext(ctx, query,
+		p.TenantID, p.IDOrder, p.Amount, string(p.Method), nullStringFromPtr(p.Reference), p.IsRefund, recordedBy, idPayment,
+	).Scan(&out.ID, &out.CreatedOn)
+	if err != nil {
+		return oModel.OrderPayment{}, fmt.Errorf("error creating order payment: %w", err)
+	}
+	return out, nil
+}
+
+// ListOrderPayments returns the ledger of an order, oldest first.
+func (r *OrderRepository) ListOrderPayments(ctx context.Context, tenantID, orderID uint64) ([]oModel.OrderPayment, error) {
+	query := `SELECT id, tenant_id, id_order, amount, method, reference, is_refund, recorded_by_user_id, id_payment, created_on
+FROM order_payments
+WHERE tenant_id = $1 AND id_order = $2
+ORDER BY id ASC`
+	rows, err := r.DB.QueryContext(ctx, query, tenantID, orderID)
+	if err != nil {
+		return nil, fmt.Errorf("error listing order payments: %w", err)
+	}
+	defer rows.Close()
+
+	payments := []oModel.OrderPayment{}
+	for rows.Next() {
+		var (
+			p                     oModel.OrderPayment
+			method                string
+			reference             sql.NullString
+			recordedBy, idPayment sql.NullInt64
+		)
+		if err := rows.Scan(&p.ID, &p.TenantID, &p.IDOrder, &p.Amount, &method, &reference, &p.IsRefund, &recordedBy, &idPayment, &p.CreatedOn); err != nil {
+			return nil, fmt.Errorf("error scanning order payment: %w", err)
+		}
+		p.Method = oModel.PaymentMethod(method)
+		if reference.Valid {
+			p.Reference = &reference.String
+		}
+		if recordedBy.Valid {
+			v := uint64(recordedBy.Int64)
+			p.RecordedBy = &v
+		}
+		if idPayment.Valid {
+			v := uint64(idPayment.Int64)
+			p.IDPayment = &v
+		}
+		payments = append(payments, p)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating order payments: %w", err)
+	}
+	return payments, nil
+}
+
+// LockOrderPaymentStateTx locks the order row and returns what is needed to apply a ledger line.
+func (r *OrderRepository) LockOrderPaymentStateTx(ctx context.Context, tx *sql.Tx, tenantID, orderID uint64) (oModel.OrderPaymentState, error) {
+	query := `SELECT o.status, o.total_price, o.paid,
+    (SELECT ` + netAmountPaidExpr + ` FROM order_payments op WHERE op.tenant_id = o.tenant_id AND op.id_order = o.id_order)
+FROM orders o
+WHERE o.id_order = $1 AND o.tenant_id = $2
+FOR UPDATE OF o`
+
+	var state oModel.OrderPaymentState
+	var status string
+	err := tx.QueryRowContext(ctx, query, orderID, tenantID).Scan(&status, &state.TotalPrice, &state.Paid, &state.AmountPaid)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return oModel.OrderPaymentState{}, errors.NewNotFound(errors.ErrOrderNotFound)
+		}
+		return oModel.OrderPaymentState{}, fmt.Errorf("error locking order for payment: %w", err)
+	}
+	state.Status = oModel.OrderStatus(status)
+	return state, nil
+}
+
+// attachPaymentTotals fills AmountPaid, BalanceDue and RefundDue from the ledger with one query.
+func (r *OrderRepository) attachPaymentTotals(ctx context.Context, tenantID uint64, orders []oModel.OrderResponse) error {
+	if len(orders) == 0 {
+		return nil
+	}
+	ids := make([]int64, len(orders))
+	for i, o := range orders {
+		ids[i] = int64(o.ID)
+	}
+
+	query := `SELECT op.id_order, ` + netAmountPaidExpr + `
+FROM order_payments op
+WHERE op.tenant_id = $1 AND op.id_order = ANY($2)
+GROUP BY op.id_order`
+	rows, err := r.DB.QueryContext(ctx, query, tenantID, pq.Int64Array(ids))
+	if err != nil {
+		return fmt.Errorf("error fetching order payment totals: %w", err)
+	}
+	defer rows.Close()
+
+	paidByOrder := make(map[uint64]money.Amount, len(orders))
+	for rows.Next() {
+		var id uint64
+		var amount money.Amount
+		if err := rows.Scan(&id, &amount); err != nil {
+			return fmt.Errorf("error scanning order payment totals: %w", err)
+		}
+		paidByOrder[id] = amount
+	}
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("error iterating order payment totals: %w", err)
+	}
+
+	for i := range orders {
+		o := &orders[i]
+		o.AmountPaid = paidByOrder[o.ID]
+		o.BalanceDue, o.RefundDue = oModel.ComputeBalance(o.Status, o.Price, o.AmountPaid)
+		if o.Paid {
+			// Marked paid outside the ledger (PATCH paid=true); nothing is left to collect.
+			o.BalanceDue = 0
+		}
+	}
+	return nil
+}
@@ -0,0 +1,116 @@
+package order
+
+import (
+	"context"
+	"database/sql"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/radamesvaz/bakery-app/internal/errors"
+	oModel "github.com/radamesvaz/bakery-app/model/orders"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOrderRepository_CreateOrderPaymentTx(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	repo := &OrderRepository{DB: db}
+	createdOn := time.Date(2026, 4, 2, 10, 0, 0, 0, time.UTC)
+	reference := "TRX-1"
+	recordedBy := uint64(7)
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(regexp.QuoteMeta(`INSERT INTO order_payments (tenant_id, id_order, amount, method, reference, is_refund, recorded_by_user_id, id_payment)`)).
+		WithArgs(uint64(1), uint64(9), "20.00", "transfer", reference, false, sql.NullInt64{Int64: 7, Valid: true}, sql.NullInt64{}).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "created_on"}).AddRow(3, createdOn))
+	mock.ExpectRollback()
+
+	tx, err := db.Begin()
+	require.NoError(t, err)
+	payment, err := repo.CreateOrderPaymentTx(context.Background(), tx, oModel.OrderPaymentRequest{
+		TenantID:   1,
+		IDOrder:    9,
+		Amount:     2000,
+		Method:     oModel.PaymentMethodTransfer,
+		Reference:  &reference,
+		RecordedBy: &recordedBy,
+	})
+	require.NoError(t, err)
+	assert.Equal(t, uint64(3), payment.ID)
+	assert.Equal(t, createdOn, payment.CreatedOn)
+	assert.Equal(t, oModel.PaymentMethodTransfer, payment.Method)
+
+	require.NoError(t, tx.Rollback())
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestOrderRepository_ListOrderPayments(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	repo := &OrderRepository{DB: db}
+	createdOn := time.Date(2026, 4, 2, 10, 0, 0, 0, time.UTC)
+
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT id, tenant_id, id_order, amount, method, reference, is_refund, recorded_by_user_id, id_payment, created_on
+FROM order_payments
+WHERE tenant_id = $1 AND id_order = $2`)).
+		WithArgs(uint64(1), uint64(9)).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "tenant_id", "id_order", "amount", "method", "reference", "is_refund", "recorded_by_user_id", "id_payment", "created_on"}).
+			AddRow(1, 1, 9, 20.0, "cash", nil, false, 7, nil, createdOn).
+			AddRow(2, 1, 9, 15.5, "online", "fake_abc", false, nil, 4, createdOn))
+
+	payments, err := repo.ListOrderPayments(context.Background(), 1, 9)
+	require.NoError(t, err)
+	require.Len(t, payments, 2)
+	assert.Nil(t, payments[0].Reference)
+	require.NotNil(t, payments[0].RecordedBy)
+	assert.Equal(t, uint64(7), *payments[0].RecordedBy)
+	assert.Equal(t, oModel.PaymentMethodOnline, payments[1].Method)
+	require.NotNil(t, payments[1].IDPayment)
+	assert.Equal(t, uint64(4), *payments[1].IDPayment)
+	assert.Nil(t, payments[1].RecordedBy)
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestOrderRepository_LockOrderPaymentStateTx(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	repo := &OrderRepository{DB: db}
+	query := regexp.QuoteMeta(`SELECT o.status, o.total_price, o.paid,`)
+
+	t.Run("returns locked state with net amount paid", func(t *testing.T) {
+		mock.ExpectBegin()
+		mock.ExpectQuery(query).WithArgs(uint64(9), uint64(1)).
+			WillReturnRows(sqlmock.NewRows([]string{"status", "total_price", "paid", "amount_paid"}).AddRow("ready", 50.0, false, 20.0))
+		mock.ExpectRollback()
+
+		tx, err := db.Begin()
+		require.NoError(t, err)
+		state, err := repo.LockOrderPaymentStateTx(context.Background(), tx, 1, 9)
+		require.NoError(t, err)
+		assert.Equal(t, oModel.OrderPaymentState{Status: oModel.StatusReady, TotalPrice: 5000, AmountPaid: 2000}, state)
+		require.NoError(t, tx.Rollback())
+		require.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("order of another tenant is not found", func(t *testing.T) {
+		mock.ExpectBegin()
+		mock.ExpectQuery(query).WithArgs(uint64(9), uint64(2)).WillReturnError(sql.ErrNoRows)
+		mock.ExpectRollback()
+
+		tx, err := db.Begin()
+		require.NoError(t, err)
+		_, err = repo.LockOrderPaymentStateTx(context.Background(), tx, 2, 9)
+		assertHTTPError(t, err, 404, errors.ErrOrderNotFound.Error())
+		require.NoError(t, tx.Rollback())
+		require.NoError(t, mock.ExpectationsWereMet())
+	})
+}
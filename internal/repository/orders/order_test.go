@@ -11,8 +11,12 @@ import (
 	stdErrors "errors"
 
 	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/lib/pq"
 	"github.com/radamesvaz/bakery-app/internal/errors"
+	"github.com/radamesvaz/bakery-app/internal/pagination"
+	"github.com/radamesvaz/bakery-app/model/money"
 	oModel "github.com/radamesvaz/bakery-app/model/orders"
+	pricingModel "github.com/radamesvaz/bakery-app/model/pricing"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -54,6 +58,19 @@ func TestOrderRepository_GetOrders(t *testing.T) {
 				"created_on",
 				"expires_at",
 				"cancellation_reason",
+				"discount_amount",
+				"promotion_code",
+				"id_promotion",
+				"subtotal",
+				"tax_rate",
+				"tax_inclusive",
+				"tax_amount",
+				"delivery_fee",
+				"id_delivery_zone",
+				"currency",
+				"fulfillment_type",
+				"id_pickup_location",
+				"sales_channel",
 				"user_name",
 				"phone",
 				"id_order_item",
@@ -75,6 +92,19 @@ func TestOrderRepository_GetOrders(t *testing.T) {
 					time.Date(2025, 4, 10, 10, 0, 0, 0, time.UTC),
 					nil,
 					nil,
+					0.0,
+					nil,
+					nil,
+					0.0,
+					0.0,
+					false,
+					0.0,
+					0.0,
+					nil,
+					"USD",
+					"delivery",
+					nil,
+					"web",
 					"Client Example",
 					"66-6666",
 					1,
@@ -96,6 +126,19 @@ func TestOrderRepository_GetOrders(t *testing.T) {
 					createdOn,
 					nil,
 					nil,
+					0.0,
+					nil,
+					nil,
+					0.0,
+					0.0,
+					false,
+					0.0,
+					0.0,
+					nil,
+					"USD",
+					"delivery",
+					nil,
+					"web",
 					"Client Example",
 					"66-6666",
 					2,
@@ -116,6 +159,19 @@ func TestOrderRepository_GetOrders(t *testing.T) {
 				time.Date(2025, 4, 10, 10, 0, 0, 0, time.UTC),
 				nil,
 				nil,
+				0.0,
+				nil,
+				nil,
+				0.0,
+				0.0,
+				false,
+				0.0,
+				0.0,
+				nil,
+				"USD",
+				"delivery",
+				nil,
+				"web",
 				"Client Example",
 				"66-6666",
 				3,
@@ -129,7 +185,10 @@ func TestOrderRepository_GetOrders(t *testing.T) {
 					ID:           1,
 					TenantID:     1,
 					IdUser:       2,
-					Price:        50.0,
+					Price:        5000,
+					Currency:     "USD",
+					FulfillmentType: oModel.FulfillmentDelivery,
+					SalesChannel: oModel.SalesChannelWeb,
 					Status:       oModel.StatusPending,
 					Note:         "note testing",
 					DeliveryDate: time.Date(2025, 4, 15, 10, 0, 0, 0, time.UTC),
@@ -138,6 +197,8 @@ func TestOrderRepository_GetOrders(t *testing.T) {
 					CreatedOn:    time.Date(2025, 4, 10, 10, 0, 0, 0, time.UTC),
 					User:         "Client Example",
 					Phone:        "66-6666",
+					AmountPaid:   2000,
+					BalanceDue:   3000,
 					OrderItems: []oModel.OrderItems{
 						{
 							ID:        1,
@@ -159,7 +220,10 @@ func TestOrderRepository_GetOrders(t *testing.T) {
 					ID:           2,
 					TenantID:     1,
 					IdUser:       2,
-					Price:        25,
+					Price:        2500,
+					Currency:     "USD",
+					FulfillmentType: oModel.FulfillmentDelivery,
+					SalesChannel: oModel.SalesChannelWeb,
 					Status:       oModel.StatusDelivered,
 					Note:         "note testing",
 					DeliveryDate: time.Date(2025, 4, 15, 10, 0, 0, 0, time.UTC),
@@ -168,6 +232,7 @@ func TestOrderRepository_GetOrders(t *testing.T) {
 					CreatedOn:    time.Date(2025, 4, 10, 10, 0, 0, 0, time.UTC),
 					User:         "Client Example",
 					Phone:        "66-6666",
+					AmountPaid:   2500,
 					OrderItems: []oModel.OrderItems{
 						{
 							ID:        3,
@@ -203,6 +268,19 @@ func TestOrderRepository_GetOrders(t *testing.T) {
             o.created_on,
             o.expires_at,
             o.cancellation_reason,
+            o.discount_amount,
+            o.promotion_code,
+            o.id_promotion,
+            o.subtotal,
+            o.tax_rate,
+            o.tax_inclusive,
+            o.tax_amount,
+            o.delivery_fee,
+            o.id_delivery_zone,
+            o.currency,
+            o.fulfillment_type,
+            o.id_pickup_location,
+            o.sales_channel,
             u.name AS user_name, 
             u.phone,
             oi.id_order_item, 
@@ -234,6 +312,19 @@ func TestOrderRepository_GetOrders(t *testing.T) {
             o.created_on,
             o.expires_at,
             o.cancellation_reason,
+            o.discount_amount,
+            o.promotion_code,
+            o.id_promotion,
+            o.subtotal,
+            o.tax_rate,
+            o.tax_inclusive,
+            o.tax_amount,
+            o.delivery_fee,
+            o.id_delivery_zone,
+            o.currency,
+            o.fulfillment_type,
+            o.id_pickup_location,
+            o.sales_channel,
             u.name AS user_name, 
             u.phone,
             oi.id_order_item, 
@@ -249,6 +340,12 @@ func TestOrderRepository_GetOrders(t *testing.T) {
 					),
 				).
 					WillReturnRows(tt.mockRows)
+				mock.ExpectQuery(regexp.QuoteMeta(`FROM order_payments op
+WHERE op.tenant_id = $1 AND op.id_order = ANY($2)`)).
+					WithArgs(uint64(1), pq.Int64Array{1, 2}).
+					WillReturnRows(sqlmock.NewRows([]string{"id_order", "amount_paid"}).
+						AddRow(1, 20.0).
+						AddRow(2, 25.0))
 			}
 
 			const tenantID = uint64(1)
@@ -303,6 +400,19 @@ func TestOrderRepository_GetOrderByID(t *testing.T) {
 				"created_on",
 				"expires_at",
 				"cancellation_reason",
+				"discount_amount",
+				"promotion_code",
+				"id_promotion",
+				"subtotal",
+				"tax_rate",
+				"tax_inclusive",
+				"tax_amount",
+				"delivery_fee",
+				"id_delivery_zone",
+				"currency",
+				"fulfillment_type",
+				"id_pickup_location",
+				"sales_channel",
 				"user_name",
 				"phone",
 				"id_order_item",
@@ -324,6 +434,19 @@ func TestOrderRepository_GetOrderByID(t *testing.T) {
 					createdOn,
 					nil,
 					nil,
+					0.0,
+					nil,
+					nil,
+					0.0,
+					0.0,
+					false,
+					0.0,
+					0.0,
+					nil,
+					"USD",
+					"delivery",
+					nil,
+					"web",
 					"Client Example",
 					"66-6666",
 					1,
@@ -345,6 +468,19 @@ func TestOrderRepository_GetOrderByID(t *testing.T) {
 					createdOn,
 					nil,
 					nil,
+					0.0,
+					nil,
+					nil,
+					0.0,
+					0.0,
+					false,
+					0.0,
+					0.0,
+					nil,
+					"USD",
+					"delivery",
+					nil,
+					"web",
 					"Client Example",
 					"66-6666",
 					2,
@@ -357,7 +493,10 @@ func TestOrderRepository_GetOrderByID(t *testing.T) {
 				ID:           1,
 				TenantID:     1,
 				IdUser:       2,
-				Price:        50.0,
+				Price:        5000,
+				Currency:     "USD",
+				FulfillmentType: oModel.FulfillmentDelivery,
+				SalesChannel: oModel.SalesChannelWeb,
 				Status:       oModel.StatusPending,
 				Note:         "note testing",
 				DeliveryDate: time.Date(2025, 4, 30, 10, 0, 0, 0, time.UTC),
@@ -366,6 +505,7 @@ func TestOrderRepository_GetOrderByID(t *testing.T) {
 				CreatedOn:    time.Date(2025, 4, 25, 10, 0, 0, 0, time.UTC),
 				User:         "Client Example",
 				Phone:        "66-6666",
+				BalanceDue:   5000,
 				OrderItems: []oModel.OrderItems{
 					{
 						ID:        1,
@@ -402,6 +542,19 @@ func TestOrderRepository_GetOrderByID(t *testing.T) {
 				"created_on",
 				"expires_at",
 				"cancellation_reason",
+				"discount_amount",
+				"promotion_code",
+				"id_promotion",
+				"subtotal",
+				"tax_rate",
+				"tax_inclusive",
+				"tax_amount",
+				"delivery_fee",
+				"id_delivery_zone",
+				"currency",
+				"fulfillment_type",
+				"id_pickup_location",
+				"sales_channel",
 				"user_name",
 				"phone",
 				"id_order_item",
@@ -410,14 +563,17 @@ func TestOrderRepository_GetOrderByID(t *testing.T) {
 				"unit_price_snapshot",
 				"quantity",
 			}).
-				AddRow(1, 1, 2, 50.0, "pending", "note testing", deliveryDate, "direccion 1", false, createdOn, nil, nil, "Client Example", "66-6666",
+				AddRow(1, 1, 2, 50.0, "pending", "note testing", deliveryDate, "direccion 1", false, createdOn, nil, nil, 0.0, nil, nil, 0.0, 0.0, false, 0.0, 0.0, nil, "USD", "delivery", nil, "web", "Client Example", "66-6666",
 					1, 2, "Product A", 0.0, 2).
-				AddRow(1, 1, 2, 50.0, "pending", "note testing", deliveryDate, "direccion 1", false, createdOn, nil, nil, "Client Example", "66-6666",
+				AddRow(1, 1, 2, 50.0, "pending", "note testing", deliveryDate, "direccion 1", false, createdOn, nil, nil, 0.0, nil, nil, 0.0, 0.0, false, 0.0, 0.0, nil, "USD", "delivery", nil, "web", "Client Example", "66-6666",
 					2, 1, "Product B", 0.0, 3),
 			expected: oModel.OrderResponse{
 				ID:           1,
 				IdUser:       2,
-				Price:        50.0,
+				Price:        5000,
+				Currency:     "USD",
+				FulfillmentType: oModel.FulfillmentDelivery,
+				SalesChannel: oModel.SalesChannelWeb,
 				Status:       oModel.StatusPending,
 				Note:         "note testing",
 				DeliveryDate: time.Date(2025, 4, 30, 10, 0, 0, 0, time.UTC),
@@ -468,6 +624,19 @@ func TestOrderRepository_GetOrderByID(t *testing.T) {
             o.created_on,
             o.expires_at,
             o.cancellation_reason,
+            o.discount_amount,
+            o.promotion_code,
+            o.id_promotion,
+            o.subtotal,
+            o.tax_rate,
+            o.tax_inclusive,
+            o.tax_amount,
+            o.delivery_fee,
+            o.id_delivery_zone,
+            o.currency,
+            o.fulfillment_type,
+            o.id_pickup_location,
+            o.sales_channel,
             u.name AS user_name, 
             u.phone,
             oi.id_order_item, 
@@ -499,6 +668,19 @@ func TestOrderRepository_GetOrderByID(t *testing.T) {
             o.created_on,
             o.expires_at,
             o.cancellation_reason,
+            o.discount_amount,
+            o.promotion_code,
+            o.id_promotion,
+            o.subtotal,
+            o.tax_rate,
+            o.tax_inclusive,
+            o.tax_amount,
+            o.delivery_fee,
+            o.id_delivery_zone,
+            o.currency,
+            o.fulfillment_type,
+            o.id_pickup_location,
+            o.sales_channel,
             u.name AS user_name, 
             u.phone,
             oi.id_order_item, 
@@ -514,6 +696,9 @@ func TestOrderRepository_GetOrderByID(t *testing.T) {
 				).
 					WithArgs(tt.idOrderForLookup, uint64(1)).
 					WillReturnRows(tt.mockRows)
+				mock.ExpectQuery(regexp.QuoteMeta(`FROM order_payments op`)).
+					WithArgs(uint64(1), pq.Int64Array{int64(tt.idOrderForLookup)}).
+					WillReturnRows(sqlmock.NewRows([]string{"id_order", "amount_paid"}))
 			}
 
 			const tenantID = uint64(1)
@@ -574,10 +759,11 @@ func TestOrderRepository_CreateOrder(t *testing.T) {
 				DeliveryDate:      deliveryDate,
 				DeliveryDirection: "direccion de prueba",
 				Note:              "entregar a la tarde",
-				Price:             20,
+				Price:             2000,
 				Status:            oModel.StatusPending,
 				Paid:              false,
 				ExpiresAt:         time.Date(2025, 4, 30, 10, 30, 0, 0, time.UTC),
+				TrackingTokenHash: "5f2b",
 			},
 			expectedError: false,
 			errorStatus:   0,
@@ -593,7 +779,7 @@ func TestOrderRepository_CreateOrder(t *testing.T) {
 
 			if tt.expectedError {
 				mock.ExpectQuery(regexp.QuoteMeta(
-					"INSERT INTO orders (tenant_id, id_user, total_price, status, note, delivery_date, delivery_direction, paid, expires_at) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9) RETURNING id_order",
+					"INSERT INTO orders (tenant_id, id_user, total_price, status, note, delivery_date, delivery_direction, paid, expires_at, tracking_token_hash, discount_amount, promotion_code, id_promotion, subtotal, tax_rate, tax_inclusive, tax_amount, delivery_fee, id_delivery_zone, fulfillment_type, id_pickup_location, sales_channel, currency) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18, $19, $20, $21, $22, (SELECT currency FROM tenants WHERE id = $1)) RETURNING id_order",
 				)).WithArgs(
 					tt.orderRequest.TenantID,
 					tt.orderRequest.IdUser,
@@ -604,10 +790,23 @@ func TestOrderRepository_CreateOrder(t *testing.T) {
 					tt.orderRequest.DeliveryDirection,
 					tt.orderRequest.Paid,
 					tt.orderRequest.ExpiresAt,
+					sql.NullString{String: tt.orderRequest.TrackingTokenHash, Valid: tt.orderRequest.TrackingTokenHash != ""},
+					tt.orderRequest.DiscountAmount,
+					sql.NullString{},
+					sql.NullInt64{},
+					tt.orderRequest.Subtotal,
+					tt.orderRequest.TaxRate,
+					tt.orderRequest.TaxInclusive,
+					tt.orderRequest.TaxAmount,
+					tt.orderRequest.DeliveryFee,
+					sql.NullInt64{},
+					oModel.FulfillmentDelivery,
+					sql.NullInt64{},
+					oModel.SalesChannelWeb,
 				).WillReturnError(tt.mockError)
 			} else {
 				mock.ExpectQuery(regexp.QuoteMeta(
-					"INSERT INTO orders (tenant_id, id_user, total_price, status, note, delivery_date, delivery_direction, paid, expires_at) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9) RETURNING id_order",
+					"INSERT INTO orders (tenant_id, id_user, total_price, status, note, delivery_date, delivery_direction, paid, expires_at, tracking_token_hash, discount_amount, promotion_code, id_promotion, subtotal, tax_rate, tax_inclusive, tax_amount, delivery_fee, id_delivery_zone, fulfillment_type, id_pickup_location, sales_channel, currency) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18, $19, $20, $21, $22, (SELECT currency FROM tenants WHERE id = $1)) RETURNING id_order",
 				)).WithArgs(
 					tt.orderRequest.TenantID,
 					tt.orderRequest.IdUser,
@@ -618,6 +817,19 @@ func TestOrderRepository_CreateOrder(t *testing.T) {
 					tt.orderRequest.DeliveryDirection,
 					tt.orderRequest.Paid,
 					tt.orderRequest.ExpiresAt,
+					sql.NullString{String: tt.orderRequest.TrackingTokenHash, Valid: tt.orderRequest.TrackingTokenHash != ""},
+					tt.orderRequest.DiscountAmount,
+					sql.NullString{},
+					sql.NullInt64{},
+					tt.orderRequest.Subtotal,
+					tt.orderRequest.TaxRate,
+					tt.orderRequest.TaxInclusive,
+					tt.orderRequest.TaxAmount,
+					tt.orderRequest.DeliveryFee,
+					sql.NullInt64{},
+					oModel.FulfillmentDelivery,
+					sql.NullInt64{},
+					oModel.SalesChannelWeb,
 				).WillReturnRows(sqlmock.NewRows([]string{"id_order"}).AddRow(tt.expected))
 			}
 
@@ -678,7 +890,7 @@ func TestOrderRepository_CreateOrderItems(t *testing.T) {
 			for i, item := range tt.orderItemsRequest {
 				exec := mock.ExpectExec(regexp.QuoteMeta(
 					"INSERT INTO order_items (tenant_id, id_order, id_product, product_name_snapshot, unit_price_snapshot, quantity) VALUES ($1, $2, $3, $4, $5, $6)",
-				)).WithArgs(tenantID, item.IdOrder, item.IdProduct, "", 0.0, item.Quantity)
+				)).WithArgs(tenantID, item.IdOrder, item.IdProduct, "", "0.00", item.Quantity)
 
 				if tt.expectedError && i == 1 {
 					exec.WillReturnError(tt.mockError)
@@ -745,7 +957,7 @@ queryExp := regexp.QuoteMeta(`SELECT id_order, tenant_id, id_user, total_price,
 		require.Len(t, orders, 1)
 		assert.Equal(t, uint64(1), orders[0].ID)
 		assert.Equal(t, uint64(2), orders[0].IdUser)
-		assert.Equal(t, 25.5, orders[0].Price)
+		assert.Equal(t, money.Amount(2550), orders[0].Price)
 		assert.Equal(t, oModel.StatusPending, orders[0].Status)
 		assert.Equal(t, "test note", orders[0].Note)
 		assert.Equal(t, "direccion vencida", orders[0].DeliveryDirection)
@@ -781,6 +993,7 @@ func TestOrderRepository_ClaimExpiredPendingOrdersTx(t *testing.T) {
 			WithArgs("cancelled", reason, tenantID, expirationTime).
 			WillReturnRows(sqlmock.NewRows([]string{
 				"id_order", "tenant_id", "id_user", "total_price", "status", "note", "created_on", "delivery_date", "delivery_direction", "paid", "cancellation_reason",
+				"discount_amount", "promotion_code",
 			}))
 
 		tx, err := db.BeginTx(ctx, nil)
@@ -800,8 +1013,12 @@ func TestOrderRepository_ClaimExpiredPendingOrdersTx(t *testing.T) {
 			WithArgs("cancelled", reason, tenantID, expirationTime).
 			WillReturnRows(sqlmock.NewRows([]string{
 				"id_order", "tenant_id", "id_user", "total_price", "status", "note", "created_on", "delivery_date", "delivery_direction", "paid", "cancellation_reason",
+				"discount_amount", "promotion_code",
 			}).
-				AddRow(1, tenantID, 2, 25.5, "cancelled", "test note", createdOn, deliveryDate, "direccion reclamada", false, reason))
+				AddRow(1, tenantID, 2, 25.5, "cancelled", "test note", createdOn, deliveryDate, "direccion reclamada", false, reason, 0.0, nil))
+		mock.ExpectExec(regexp.QuoteMeta(`SELECT pg_notify($1, $2)`)).
+			WithArgs(OrderEventsChannel, "1").
+			WillReturnResult(sqlmock.NewResult(0, 1))
 
 		tx, err := db.BeginTx(ctx, nil)
 		require.NoError(t, err)
@@ -810,7 +1027,7 @@ func TestOrderRepository_ClaimExpiredPendingOrdersTx(t *testing.T) {
 		require.Len(t, orders, 1)
 		assert.Equal(t, uint64(1), orders[0].ID)
 		assert.Equal(t, uint64(2), orders[0].IdUser)
-		assert.Equal(t, 25.5, orders[0].Price)
+		assert.Equal(t, money.Amount(2550), orders[0].Price)
 		assert.Equal(t, oModel.OrderStatus("cancelled"), orders[0].Status)
 		assert.Equal(t, "test note", orders[0].Note)
 		assert.Equal(t, "direccion reclamada", orders[0].DeliveryDirection)
@@ -830,6 +1047,39 @@ func TestOrderRepository_ClaimExpiredPendingOrdersTx(t *testing.T) {
 		assert.Error(t, err)
 		require.NoError(t, mock.ExpectationsWereMet())
 	})
+
+	t.Run("skips_orders_with_in_flight_payments", func(t *testing.T) {
+		mock.ExpectBegin()
+		mock.ExpectQuery(`UPDATE orders.*NOT EXISTS \(\s*SELECT 1 FROM payments p.*p\.status = 'pending' AND p\.expires_at > \$4`).
+			WithArgs("cancelled", reason, tenantID, expirationTime).
+			WillReturnRows(sqlmock.NewRows([]string{
+				"id_order", "tenant_id", "id_user", "total_price", "status", "note", "created_on", "delivery_date", "delivery_direction", "paid", "cancellation_reason",
+				"discount_amount", "promotion_code",
+			}))
+
+		tx, err := db.BeginTx(ctx, nil)
+		require.NoError(t, err)
+		_, err = repo.ClaimExpiredPendingOrdersTx(ctx, tx, tenantID, expirationTime, oModel.StatusCancelled, &reason)
+		require.NoError(t, err)
+		require.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("skips_orders_with_recorded_deposits", func(t *testing.T) {
+		mock.ExpectBegin()
+		mock.ExpectQuery(`UPDATE orders.*AND NOT EXISTS \(\s*SELECT 1 FROM order_payments op\s+WHERE op\.tenant_id = orders\.tenant_id AND op\.id_order = orders\.id_order\s*\)`).
+			WithArgs("cancelled", reason, tenantID, expirationTime).
+			WillReturnRows(sqlmock.NewRows([]string{
+				"id_order", "tenant_id", "id_user", "total_price", "status", "note", "created_on", "delivery_date", "delivery_direction", "paid", "cancellation_reason",
+				"discount_amount", "promotion_code",
+			}))
+
+		tx, err := db.BeginTx(ctx, nil)
+		require.NoError(t, err)
+		orders, err := repo.ClaimExpiredPendingOrdersTx(ctx, tx, tenantID, expirationTime, oModel.StatusCancelled, &reason)
+		require.NoError(t, err)
+		assert.Empty(t, orders)
+		require.NoError(t, mock.ExpectationsWereMet())
+	})
 }
 
 // Validates the error to be of *HTTPError type, have the correct status and message
@@ -843,7 +1093,8 @@ func assertHTTPError(t *testing.T, err error, expectedStatus int, expectedMessag
 }
 
 func TestOrderRepository_BuildOrderIDPageQuery_WithSearch(t *testing.T) {
-	q, args := buildOrderIDPageQuery(1, false, nil, nil, nil, ptrString("client"), 21)
+	q, args, err := buildOrderIDPageQuery(1, OrderListFilter{Search: ptrString("client")}, "", 21)
+	require.NoError(t, err)
 	assert.False(t, strings.Contains(q, "o.note ILIKE"))
 	assert.True(t, strings.Contains(q, "EXISTS (SELECT 1 FROM users u"))
 	assert.True(t, strings.Contains(q, "u.name ILIKE"))
@@ -856,7 +1107,8 @@ func TestOrderRepository_BuildOrderIDPageQuery_WithSearch(t *testing.T) {
 }
 
 func TestOrderRepository_BuildOrderIDPageQuery_WithNumericSearch(t *testing.T) {
-	q, args := buildOrderIDPageQuery(1, false, nil, nil, nil, ptrString("2"), 21)
+	q, args, err := buildOrderIDPageQuery(1, OrderListFilter{Search: ptrString("2")}, "", 21)
+	require.NoError(t, err)
 	assert.True(t, strings.Contains(q, "OR o.id_order ="))
 	require.Len(t, args, 4)
 	assert.Equal(t, "%2%", args[1])
@@ -864,6 +1116,182 @@ func TestOrderRepository_BuildOrderIDPageQuery_WithNumericSearch(t *testing.T) {
 	assert.Equal(t, 21, args[3])
 }
 
+func TestOrderRepository_BuildOrderIDPageQuery_WithCreatedRange(t *testing.T) {
+	from := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2026, 4, 1, 0, 0, 0, 0, time.UTC)
+	q, args, err := buildOrderIDPageQuery(1, OrderListFilter{CreatedFrom: &from, CreatedTo: &to}, "", 21)
+	require.NoError(t, err)
+	assert.True(t, strings.Contains(q, "AND o.created_on >= $2 AND o.created_on < $3"))
+	require.Len(t, args, 4)
+	assert.Equal(t, from, args[1])
+	assert.Equal(t, to, args[2])
+	assert.Equal(t, 21, args[3])
+}
+
+func TestOrderRepository_BuildOrderIDPageQuery_WithDeliveryPaidAndTotalFilters(t *testing.T) {
+	from := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2026, 3, 7, 0, 0, 0, 0, time.UTC)
+	paid := false
+	minTotal, maxTotal := money.Amount(1000), money.Amount(5000)
+	q, args, err := buildOrderIDPageQuery(1, OrderListFilter{
+		DeliveryFrom: &from,
+		DeliveryTo:   &to,
+		Paid:         &paid,
+		MinTotal:     &minTotal,
+		MaxTotal:     &maxTotal,
+	}, "", 21)
+	require.NoError(t, err)
+	assert.True(t, strings.Contains(q, "AND o.delivery_date >= $2 AND o.delivery_date <= $3 AND o.paid = $4 AND o.total_price >= $5 AND o.total_price <= $6"))
+	assert.Equal(t, []interface{}{uint64(1), from, to, false, money.Amount(1000), money.Amount(5000), 21}, args)
+}
+
+func TestOrderRepository_BuildOrderIDPageQuery_WithFulfillmentType(t *testing.T) {
+	pickup := oModel.FulfillmentPickup
+	q, args, err := buildOrderIDPageQuery(1, OrderListFilter{FulfillmentType: &pickup}, "", 21)
+	require.NoError(t, err)
+	assert.True(t, strings.Contains(q, "AND o.fulfillment_type = $2"))
+	assert.Equal(t, []interface{}{uint64(1), "pickup", 21}, args)
+}
+
+func TestOrderRepository_BuildOrderIDPageQuery_SortByDeliveryDateWithCursor(t *testing.T) {
+	d := time.Date(2026, 3, 5, 0, 0, 0, 0, time.UTC)
+	cursor, err := pagination.EncodeOrderDeliveryCursor(&d, 7)
+	require.NoError(t, err)
+
+	q, args, err := buildOrderIDPageQuery(1, OrderListFilter{Sort: OrderSortDeliveryDate}, cursor, 21)
+	require.NoError(t, err)
+	assert.True(t, strings.Contains(q, "(o.delivery_date > $2 OR (o.delivery_date = $2 AND o.id_order > $3) OR o.delivery_date IS NULL)"))
+	assert.True(t, strings.Contains(q, "ORDER BY o.delivery_date ASC NULLS LAST, o.id_order ASC LIMIT $4"))
+	assert.Equal(t, []interface{}{uint64(1), d, uint64(7), 21}, args)
+}
+
+func TestOrderRepository_BuildOrderIDPageQuery_RejectsCursorOfOtherSort(t *testing.T) {
+	cursor, err := pagination.EncodeOrderCursor(time.Now(), 7)
+	require.NoError(t, err)
+
+	_, _, err = buildOrderIDPageQuery(1, OrderListFilter{Sort: OrderSortDeliveryDate}, cursor, 21)
+	assert.ErrorIs(t, err, ErrInvalidOrderCursor)
+}
+
 func ptrString(v string) *string {
 	return &v
 }
+
+func TestOrderRepository_LockOrderStatusTx(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	repo := &OrderRepository{DB: db}
+	query := regexp.QuoteMeta(`SELECT status FROM orders WHERE id_order = $1 AND tenant_id = $2 FOR UPDATE`)
+
+	t.Run("returns locked status", func(t *testing.T) {
+		mock.ExpectBegin()
+		mock.ExpectQuery(query).WithArgs(uint64(9), uint64(1)).
+			WillReturnRows(sqlmock.NewRows([]string{"status"}).AddRow("preparing"))
+		mock.ExpectRollback()
+
+		tx, err := db.Begin()
+		require.NoError(t, err)
+		status, err := repo.LockOrderStatusTx(context.Background(), tx, 1, 9)
+		require.NoError(t, err)
+		assert.Equal(t, oModel.StatusPreparing, status)
+		require.NoError(t, tx.Rollback())
+		require.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("order of another tenant is not found", func(t *testing.T) {
+		mock.ExpectBegin()
+		mock.ExpectQuery(query).WithArgs(uint64(9), uint64(2)).WillReturnError(sql.ErrNoRows)
+		mock.ExpectRollback()
+
+		tx, err := db.Begin()
+		require.NoError(t, err)
+		_, err = repo.LockOrderStatusTx(context.Background(), tx, 2, 9)
+		assertHTTPError(t, err, 404, errors.ErrOrderNotFound.Error())
+		require.NoError(t, tx.Rollback())
+		require.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestOrderRepository_GetOrderIDByTrackingTokenHash(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	repo := &OrderRepository{DB: db}
+	query := regexp.QuoteMeta(`SELECT id_order FROM orders WHERE tenant_id = $1 AND tracking_token_hash = $2`)
+
+	mock.ExpectQuery(query).WithArgs(uint64(1), "abc123").
+		WillReturnRows(sqlmock.NewRows([]string{"id_order"}).AddRow(9))
+	id, err := repo.GetOrderIDByTrackingTokenHash(context.Background(), 1, "abc123")
+	require.NoError(t, err)
+	assert.Equal(t, uint64(9), id)
+
+	mock.ExpectQuery(query).WithArgs(uint64(2), "abc123").WillReturnError(sql.ErrNoRows)
+	_, err = repo.GetOrderIDByTrackingTokenHash(context.Background(), 2, "abc123")
+	assertHTTPError(t, err, 404, errors.ErrOrderNotFound.Error())
+
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestOrderRepository_ReplaceItemsAndTotalTx(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	repo := &OrderRepository{DB: db}
+
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta(`DELETE FROM order_items WHERE id_order = $1 AND tenant_id = $2`)).
+		WithArgs(uint64(9), uint64(1)).
+		WillReturnResult(sqlmock.NewResult(0, 2))
+	mock.ExpectExec(regexp.QuoteMeta(`UPDATE orders SET total_price = $1, subtotal = $2, discount_amount = $3, tax_rate = $4, tax_inclusive = $5, tax_amount = $6, delivery_fee = $7 WHERE id_order = $8 AND tenant_id = $9`)).
+		WithArgs("32.50", "30.00", "2.50", 10.0, false, "2.75", "2.25", uint64(9), uint64(1)).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(regexp.QuoteMeta(`UPDATE orders SET total_price = $1, subtotal = $2, discount_amount = $3, tax_rate = $4, tax_inclusive = $5, tax_amount = $6, delivery_fee = $7 WHERE id_order = $8 AND tenant_id = $9`)).
+		WithArgs("32.50", "30.00", "2.50", 10.0, false, "2.75", "2.25", uint64(10), uint64(1)).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectRollback()
+
+	tx, err := db.Begin()
+	require.NoError(t, err)
+
+	totals := pricingModel.Totals{Subtotal: 3000, DiscountAmount: 250, TaxRate: 10, TaxAmount: 275, DeliveryFee: 225, Total: 3250}
+	require.NoError(t, repo.DeleteOrderItemsTx(context.Background(), tx, 1, 9))
+	require.NoError(t, repo.UpdateOrderTotalsTx(context.Background(), tx, 1, 9, totals))
+	err = repo.UpdateOrderTotalsTx(context.Background(), tx, 1, 10, totals)
+	assertHTTPError(t, err, 404, errors.ErrOrderNotFound.Error())
+
+	require.NoError(t, tx.Rollback())
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestOrderRepository_UpdateOrderStatus_GuardsCurrentStatus(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	repo := &OrderRepository{DB: db}
+	query := regexp.QuoteMeta(`UPDATE orders SET status = $1, cancellation_reason = $2 WHERE id_order = $3 AND tenant_id = $4 AND status = $5`)
+
+	t.Run("applies the transition from the expected status", func(t *testing.T) {
+		mock.ExpectExec(query).
+			WithArgs(oModel.StatusPreparing, nil, uint64(9), uint64(1), oModel.StatusPending).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+
+		err := repo.UpdateOrderStatus(context.Background(), 1, 9, oModel.StatusPending, oModel.StatusPreparing, nil)
+		require.NoError(t, err)
+		require.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("status changed concurrently", func(t *testing.T) {
+		mock.ExpectExec(query).
+			WithArgs(oModel.StatusCancelled, nil, uint64(9), uint64(1), oModel.StatusPending).
+			WillReturnResult(sqlmock.NewResult(0, 0))
+
+		err := repo.UpdateOrderStatus(context.Background(), 1, 9, oModel.StatusPending, oModel.StatusCancelled, nil)
+		assertHTTPError(t, err, 409, errors.ErrOrderStatusChanged.Error())
+		require.NoError(t, mock.ExpectationsWereMet())
+	})
+}
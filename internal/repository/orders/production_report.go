@@ -0,0 +1,59 @@
+package order
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/lib/pq"
+
+	oModel "github.com/radamesvaz/bakery-app/model/orders"
+)
+
+// productionStatuses are the orders the kitchen still has to bake.
+var productionStatuses = []string{
+	string(oModel.StatusPending),
+	string(oModel.StatusPreparing),
+	string(oModel.StatusReady),
+}
+
+// GetProductionReportRows returns every line of the tenant's pending, preparing and ready orders
+// delivered on date, ordered by order so later rows carry the newest name snapshots.
+func (r *OrderRepository) GetProductionReportRows(ctx context.Context, tenantID uint64, date time.Time) ([]oModel.ProductionReportRow, error) {
+	query := `
+        SELECT
+            o.id_order,
+            oi.id_product,
+            COALESCE(oi.product_name_snapshot, ''),
+            oi.quantity,
+            o.status,
+            COALESCE(u.name, ''),
+            COALESCE(o.note, ''),
+            o.fulfillment_type,
+            COALESCE(pl.name, '')
+        FROM orders o
+        INNER JOIN order_items oi ON oi.tenant_id = o.tenant_id AND oi.id_order = o.id_order
+        LEFT JOIN users u ON o.id_user = u.id_user
+        LEFT JOIN pickup_locations pl ON pl.id_pickup_location = o.id_pickup_location
+        WHERE o.tenant_id = $1 AND o.delivery_date = $2 AND o.status = ANY($3)
+        ORDER BY o.id_order ASC, oi.id_order_item ASC
+	`
+	rows, err := r.DB.QueryContext(ctx, query, tenantID, date, pq.Array(productionStatuses))
+	if err != nil {
+		return nil, fmt.Errorf("fetching production report: %w", err)
+	}
+	defer rows.Close()
+
+	var out []oModel.ProductionReportRow
+	for rows.Next() {
+		var row oModel.ProductionReportRow
+		if err := rows.Scan(&row.IDOrder, &row.IDProduct, &row.ProductName, &row.Quantity, &row.Status, &row.CustomerName, &row.Note, &row.FulfillmentType, &row.PickupLocation); err != nil {
+			return nil, fmt.Errorf("scanning production report row: %w", err)
+		}
+		out = append(out, row)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterating production report rows: %w", err)
+	}
+	return out, nil
+}
@@ -0,0 +1,38 @@
+package order
+
+import (
+	"context"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/lib/pq"
+	oModel "github.com/radamesvaz/bakery-app/model/orders"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOrderRepository_GetProductionReportRows_ScopesTenantDateAndStatus(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	repo := &OrderRepository{DB: db}
+	date := time.Date(2026, 12, 24, 0, 0, 0, 0, time.UTC)
+
+	mock.ExpectQuery(regexp.QuoteMeta(`WHERE o.tenant_id = $1 AND o.delivery_date = $2 AND o.status = ANY($3)`)).
+		WithArgs(uint64(1), date, pq.Array([]string{"pending", "preparing", "ready"})).
+		WillReturnRows(sqlmock.NewRows([]string{"id_order", "id_product", "product_name_snapshot", "quantity", "status", "name", "note", "fulfillment_type", "pickup_location"}).
+			AddRow(10, 2, "Pan dulce", 3, "pending", "Ana", "", "delivery", "").
+			AddRow(12, 2, "Pan dulce", 5, "ready", "Luis", "sin pasas", "pickup", "Centro"))
+
+	rows, err := repo.GetProductionReportRows(context.Background(), 1, date)
+
+	require.NoError(t, err)
+	assert.Equal(t, []oModel.ProductionReportRow{
+		{IDOrder: 10, IDProduct: 2, ProductName: "Pan dulce", Quantity: 3, Status: oModel.StatusPending, CustomerName: "Ana", FulfillmentType: oModel.FulfillmentDelivery},
+		{IDOrder: 12, IDProduct: 2, ProductName: "Pan dulce", Quantity: 5, Status: oModel.StatusReady, CustomerName: "Luis", Note: "sin pasas", FulfillmentType: oModel.FulfillmentPickup, PickupLocation: "Centro"},
+	}, rows)
+	require.NoError(t, mock.ExpectationsWereMet())
+}
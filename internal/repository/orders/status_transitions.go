@@ -0,0 +1,95 @@
+package order
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/lib/pq"
+
+	oModel "github.com/radamesvaz/bakery-app/model/orders"
+)
+
+// GetStatusTransitions returns the order status transitions the tenant configured, or nil when it
+// uses the default lifecycle.
+func (r *OrderRepository) GetStatusTransitions(ctx context.Context, tenantID uint64) (oModel.StatusTransitions, error) {
+	rows, err := r.DB.QueryContext(ctx,
+		`SELECT from_status, to_status FROM tenant_order_status_transitions WHERE tenant_id = $1 ORDER BY from_status, to_status`,
+		tenantID,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("get order status transitions: %w", err)
+	}
+	defer rows.Close()
+
+	var transitions oModel.StatusTransitions
+	for rows.Next() {
+		var from, to oModel.OrderStatus
+		if err := rows.Scan(&from, &to); err != nil {
+			return nil, fmt.Errorf("scan order status transition: %w", err)
+		}
+		if transitions == nil {
+			transitions = oModel.StatusTransitions{}
+		}
+		transitions[from] = append(transitions[from], to)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("get order status transitions: %w", err)
+	}
+	return transitions, nil
+}
+
+// ReplaceStatusTransitions replaces the tenant's order lifecycle with transitions. Orders keep
+// their status; only later changes are checked against the new transitions.
+func (r *OrderRepository) ReplaceStatusTransitions(ctx context.Context, tenantID uint64, transitions oModel.StatusTransitions) error {
+	statuses := make([]oModel.OrderStatus, 0, len(transitions))
+	for status := range transitions {
+		statuses = append(statuses, status)
+	}
+	sort.Slice(statuses, func(i, j int) bool { return statuses[i] < statuses[j] })
+
+	var from, to []string
+	for _, status := range statuses {
+		for _, target := range transitions[status] {
+			from = append(from, string(status))
+			to = append(to, string(target))
+		}
+	}
+
+	tx, err := r.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("error beginning transaction: %w", err)
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	if _, err := tx.ExecContext(ctx,
+		`DELETE FROM tenant_order_status_transitions WHERE tenant_id = $1`,
+		tenantID,
+	); err != nil {
+		return fmt.Errorf("delete order status transitions: %w", err)
+	}
+	if _, err := tx.ExecContext(ctx,
+		`INSERT INTO tenant_order_status_transitions (tenant_id, from_status, to_status)
+SELECT $1, t.from_status::order_status, t.to_status::order_status
+FROM unnest($2::text[], $3::text[]) AS t(from_status, to_status)`,
+		tenantID, pq.Array(from), pq.Array(to),
+	); err != nil {
+		return fmt.Errorf("insert order status transitions: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("error committing transaction: %w", err)
+	}
+	return nil
+}
+
+// DeleteStatusTransitions puts the tenant back on the default lifecycle.
+func (r *OrderRepository) DeleteStatusTransitions(ctx context.Context, tenantID uint64) error {
+	if _, err := r.DB.ExecContext(ctx,
+		`DELETE FROM tenant_order_status_transitions WHERE tenant_id = $1`,
+		tenantID,
+	); err != nil {
+		return fmt.Errorf("delete order status transitions: %w", err)
+	}
+	return nil
+}
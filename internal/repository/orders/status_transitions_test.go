@@ -0,0 +1,76 @@
+package order
+
+import (
+	"context"
+	"regexp"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/lib/pq"
+	oModel "github.com/radamesvaz/bakery-app/model/orders"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOrderRepository_GetStatusTransitions(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	repo := &OrderRepository{DB: db}
+	query := regexp.QuoteMeta(`SELECT from_status, to_status FROM tenant_order_status_transitions WHERE tenant_id = $1`)
+
+	t.Run("groups targets by status", func(t *testing.T) {
+		mock.ExpectQuery(query).WithArgs(uint64(1)).
+			WillReturnRows(sqlmock.NewRows([]string{"from_status", "to_status"}).
+				AddRow("pending", "delivered").
+				AddRow("pending", "cancelled").
+				AddRow("delivered", "deleted"))
+
+		transitions, err := repo.GetStatusTransitions(context.Background(), 1)
+
+		require.NoError(t, err)
+		assert.Equal(t, oModel.StatusTransitions{
+			oModel.StatusPending:   {oModel.StatusDelivered, oModel.StatusCancelled},
+			oModel.StatusDelivered: {oModel.StatusDeleted},
+		}, transitions)
+		require.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("tenant without rows gets nil", func(t *testing.T) {
+		mock.ExpectQuery(query).WithArgs(uint64(2)).
+			WillReturnRows(sqlmock.NewRows([]string{"from_status", "to_status"}))
+
+		transitions, err := repo.GetStatusTransitions(context.Background(), 2)
+
+		require.NoError(t, err)
+		assert.Nil(t, transitions)
+		require.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestOrderRepository_ReplaceStatusTransitions(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	repo := &OrderRepository{DB: db}
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta(`DELETE FROM tenant_order_status_transitions WHERE tenant_id = $1`)).
+		WithArgs(uint64(1)).
+		WillReturnResult(sqlmock.NewResult(0, 4))
+	mock.ExpectExec(regexp.QuoteMeta(`INSERT INTO tenant_order_status_transitions (tenant_id, from_status, to_status)`)).
+		WithArgs(uint64(1),
+			pq.Array([]string{"delivered", "pending", "pending"}),
+			pq.Array([]string{"deleted", "delivered", "cancelled"})).
+		WillReturnResult(sqlmock.NewResult(0, 3))
+	mock.ExpectCommit()
+
+	err = repo.ReplaceStatusTransitions(context.Background(), 1, oModel.StatusTransitions{
+		oModel.StatusPending:   {oModel.StatusDelivered, oModel.StatusCancelled},
+		oModel.StatusDelivered: {oModel.StatusDeleted},
+	})
+
+	require.NoError(t, err)
+	require.NoError(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,120 @@
+package payments
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/radamesvaz/bakery-app/internal/errors"
+	pModel "github.com/radamesvaz/bakery-app/model/payments"
+)
+
+type Repository struct {
+	DB *sql.DB
+}
+
+const paymentColumns = `id, tenant_id, id_order, provider, provider_payment_id, status, amount, checkout_url, expires_at, paid_on, created_on`
+
+func scanPayment(row interface{ Scan(dest ...any) error }) (pModel.Payment, error) {
+	var p pModel.Payment
+	var paidOn sql.NullTime
+	err := row.Scan(
+		&p.ID,
+		&p.TenantID,
+		&p.IDOrder,
+		&p.Provider,
+		&p.ProviderPaymentID,
+		&p.Status,
+		&p.Amount,
+		&p.CheckoutURL,
+		&p.ExpiresAt,
+		&paidOn,
+		&p.CreatedOn,
+	)
+	if err != nil {
+		return pModel.Payment{}, err
+	}
+	if paidOn.Valid {
+		p.PaidOn = &paidOn.Time
+	}
+	return p, nil
+}
+
+// CreatePayment stores a pending payment for an intent the provider already accepted.
+func (r *Repository) CreatePayment(ctx context.Context, req pModel.CreatePaymentRequest) (pModel.Payment, error) {
+	q := `INSERT INTO payments (tenant_id, id_order, provider, provider_payment_id, status, amount, checkout_url, expires_at)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+RETURNING ` + paymentColumns
+	p, err := scanPayment(r.DB.QueryRowContext(ctx, q,
+		req.TenantID,
+		req.IDOrder,
+		req.Provider,
+		req.ProviderPaymentID,
+		pModel.StatusPending,
+		req.Amount,
+		req.CheckoutURL,
+		req.ExpiresAt,
+	))
+	if err != nil {
+		return pModel.Payment{}, fmt.Errorf("create payment: %w", err)
+	}
+	return p, nil
+}
+
+// GetInFlightPayment returns the newest pending payment of the order that has not expired at now,
+// or nil when there is none.
+func (r *Repository) GetInFlightPayment(ctx context.Context, tenantID, orderID uint64, now time.Time) (*pModel.Payment, error) {
+	q := `SELECT ` + paymentColumns + ` FROM payments
+WHERE tenant_id = $1 AND id_order = $2 AND status = 'pending' AND expires_at > $3
+ORDER BY id DESC
+LIMIT 1`
+	p, err := scanPayment(r.DB.QueryRowContext(ctx, q, tenantID, orderID, now))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("get in-flight payment: %w", err)
+	}
+	return &p, nil
+}
+
+// LockPaymentByProviderIDTx loads a payment by the provider's id and locks the row for the rest of tx.
+// Provider webhooks carry no tenant, so the lookup relies on the (provider, provider_payment_id) unique key.
+func (r *Repository) LockPaymentByProviderIDTx(ctx context.Context, tx *sql.Tx, provider, providerPaymentID string) (pModel.Payment, error) {
+	q := `SELECT ` + paymentColumns + ` FROM payments
+WHERE provider = $1 AND provider_payment_id = $2
+FOR UPDATE`
+	p, err := scanPayment(tx.QueryRowContext(ctx, q, provider, providerPaymentID))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return pModel.Payment{}, errors.NewNotFound(errors.ErrPaymentNotFound)
+		}
+		return pModel.Payment{}, fmt.Errorf("lock payment: %w", err)
+	}
+	return p, nil
+}
+
+// MarkPaymentSucceededTx sets the payment to succeeded with paid_on.
+func (r *Repository) MarkPaymentSucceededTx(ctx context.Context, tx *sql.Tx, tenantID, id uint64, paidOn time.Time) error {
+	_, err := tx.ExecContext(ctx,
+		`UPDATE payments SET status = $1, paid_on = $2, updated_on = NOW() WHERE id = $3 AND tenant_id = $4`,
+		pModel.StatusSucceeded, paidOn, id, tenantID,
+	)
+	if err != nil {
+		return fmt.Errorf("mark payment succeeded: %w", err)
+	}
+	return nil
+}
+
+// UpdatePaymentStatusTx sets a terminal non-success status (failed, cancelled).
+func (r *Repository) UpdatePaymentStatusTx(ctx context.Context, tx *sql.Tx, tenantID, id uint64, status pModel.PaymentStatus) error {
+	_, err := tx.ExecContext(ctx,
+		`UPDATE payments SET status = $1, updated_on = NOW() WHERE id = $2 AND tenant_id = $3`,
+		status, id, tenantID,
+	)
+	if err != nil {
+		return fmt.Errorf("update payment status: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,80 @@
+package payments
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	appErrors "github.com/radamesvaz/bakery-app/internal/errors"
+	pModel "github.com/radamesvaz/bakery-app/model/payments"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var paymentRowColumns = []string{"id", "tenant_id", "id_order", "provider", "provider_payment_id", "status", "amount", "checkout_url", "expires_at", "paid_on", "created_on"}
+
+func TestRepository_GetInFlightPayment(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	repo := &Repository{DB: db}
+	now := time.Date(2026, 4, 2, 10, 0, 0, 0, time.UTC)
+	query := regexp.QuoteMeta(`WHERE tenant_id = $1 AND id_order = $2 AND status = 'pending' AND expires_at > $3`)
+
+	t.Run("returns_newest_pending_payment", func(t *testing.T) {
+		mock.ExpectQuery(query).
+			WithArgs(uint64(1), uint64(42), now).
+			WillReturnRows(sqlmock.NewRows(paymentRowColumns).
+				AddRow(3, 1, 42, "fake", "fake_abc", "pending", 35.5, "https://pay.test/x", now.Add(time.Minute), nil, now))
+
+		p, err := repo.GetInFlightPayment(context.Background(), 1, 42, now)
+
+		require.NoError(t, err)
+		require.NotNil(t, p)
+		assert.Equal(t, uint64(3), p.ID)
+		assert.Equal(t, pModel.StatusPending, p.Status)
+		assert.Nil(t, p.PaidOn)
+		require.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("returns_nil_when_none", func(t *testing.T) {
+		mock.ExpectQuery(query).
+			WithArgs(uint64(1), uint64(42), now).
+			WillReturnRows(sqlmock.NewRows(paymentRowColumns))
+
+		p, err := repo.GetInFlightPayment(context.Background(), 1, 42, now)
+
+		require.NoError(t, err)
+		assert.Nil(t, p)
+		require.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestRepository_LockPaymentByProviderIDTx_NotFound(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	repo := &Repository{DB: db}
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(regexp.QuoteMeta(`WHERE provider = $1 AND provider_payment_id = $2
+FOR UPDATE`)).
+		WithArgs("fake", "missing").
+		WillReturnRows(sqlmock.NewRows(paymentRowColumns))
+
+	tx, err := db.Begin()
+	require.NoError(t, err)
+	_, err = repo.LockPaymentByProviderIDTx(context.Background(), tx, "fake", "missing")
+
+	var httpErr *appErrors.HTTPError
+	require.True(t, errors.As(err, &httpErr))
+	assert.Equal(t, http.StatusNotFound, httpErr.StatusCode)
+	assert.ErrorIs(t, err, appErrors.ErrPaymentNotFound)
+	require.NoError(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,174 @@
+package pickup
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	stdErrors "errors"
+	"fmt"
+
+	"github.com/lib/pq"
+	"github.com/radamesvaz/bakery-app/internal/errors"
+	pickupModel "github.com/radamesvaz/bakery-app/model/pickup"
+)
+
+type Repository struct {
+	DB *sql.DB
+}
+
+type queryer interface {
+	QueryRowContext(ctx context.Context, query string, args ...any) *sql.Row
+}
+
+// queryerFrom returns tx when non-nil so reads happen in the caller's transaction.
+func (r *Repository) queryerFrom(tx *sql.Tx) queryer {
+	if tx != nil {
+		return tx
+	}
+	return r.DB
+}
+
+func isUniqueViolation(err error) bool {
+	var pqErr *pq.Error
+	return stdErrors.As(err, &pqErr) && string(pqErr.Code) == "23505"
+}
+
+const locationColumns = `id_pickup_location, tenant_id, name, address, opening_hours, active, created_on, updated_on`
+
+func scanLocation(row interface{ Scan(dest ...any) error }) (pickupModel.Location, error) {
+	var (
+		l     pickupModel.Location
+		hours []byte
+	)
+	if err := row.Scan(&l.ID, &l.TenantID, &l.Name, &l.Address, &hours, &l.Active, &l.CreatedOn, &l.UpdatedOn); err != nil {
+		return pickupModel.Location{}, err
+	}
+	l.OpeningHours = []pickupModel.OpeningHours{}
+	if err := json.Unmarshal(hours, &l.OpeningHours); err != nil {
+		return pickupModel.Location{}, fmt.Errorf("decode opening hours: %w", err)
+	}
+	return l, nil
+}
+
+func openingHoursJSON(hours []pickupModel.OpeningHours) ([]byte, error) {
+	if hours == nil {
+		hours = []pickupModel.OpeningHours{}
+	}
+	b, err := json.Marshal(hours)
+	if err != nil {
+		return nil, fmt.Errorf("encode opening hours: %w", err)
+	}
+	return b, nil
+}
+
+// ListLocations returns the tenant's pickup locations ordered by name; activeOnly hides the
+// locations the storefront cannot offer.
+func (r *Repository) ListLocations(ctx context.Context, tenantID uint64, activeOnly bool) ([]pickupModel.Location, error) {
+	query := `SELECT ` + locationColumns + ` FROM pickup_locations WHERE tenant_id = $1`
+	if activeOnly {
+		query += ` AND active = TRUE`
+	}
+	query += ` ORDER BY name, id_pickup_location`
+
+	rows, err := r.DB.QueryContext(ctx, query, tenantID)
+	if err != nil {
+		return nil, fmt.Errorf("list pickup locations: %w", err)
+	}
+	defer rows.Close()
+
+	locations := []pickupModel.Location{}
+	for rows.Next() {
+		l, err := scanLocation(rows)
+		if err != nil {
+			return nil, fmt.Errorf("scan pickup location: %w", err)
+		}
+		locations = append(locations, l)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate pickup locations: %w", err)
+	}
+	return locations, nil
+}
+
+// GetLocation returns one pickup location of the tenant.
+func (r *Repository) GetLocation(ctx context.Context, tenantID, id uint64) (pickupModel.Location, error) {
+	return r.GetLocationTx(ctx, nil, tenantID, id)
+}
+
+// GetLocationTx is GetLocation within the order transaction.
+func (r *Repository) GetLocationTx(ctx context.Context, tx *sql.Tx, tenantID, id uint64) (pickupModel.Location, error) {
+	l, err := scanLocation(r.queryerFrom(tx).QueryRowContext(ctx,
+		`SELECT `+locationColumns+` FROM pickup_locations WHERE id_pickup_location = $1 AND tenant_id = $2`,
+		id, tenantID,
+	))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return pickupModel.Location{}, errors.NewNotFound(errors.ErrPickupLocationNotFound)
+		}
+		return pickupModel.Location{}, fmt.Errorf("get pickup location: %w", err)
+	}
+	return l, nil
+}
+
+// CreateLocation stores a pickup location.
+func (r *Repository) CreateLocation(ctx context.Context, tenantID uint64, in pickupModel.Location) (pickupModel.Location, error) {
+	hours, err := openingHoursJSON(in.OpeningHours)
+	if err != nil {
+		return pickupModel.Location{}, err
+	}
+	l, err := scanLocation(r.DB.QueryRowContext(ctx,
+		`INSERT INTO pickup_locations (tenant_id, name, address, opening_hours, active)
+VALUES ($1, $2, $3, $4, $5)
+RETURNING `+locationColumns,
+		tenantID, in.Name, in.Address, hours, in.Active,
+	))
+	if err != nil {
+		if isUniqueViolation(err) {
+			return pickupModel.Location{}, errors.ErrPickupLocationNameExists
+		}
+		return pickupModel.Location{}, fmt.Errorf("create pickup location: %w", err)
+	}
+	return l, nil
+}
+
+// UpdateLocation replaces a pickup location. Orders already placed for it are not changed.
+func (r *Repository) UpdateLocation(ctx context.Context, tenantID, id uint64, in pickupModel.Location) (pickupModel.Location, error) {
+	hours, err := openingHoursJSON(in.OpeningHours)
+	if err != nil {
+		return pickupModel.Location{}, err
+	}
+	l, err := scanLocation(r.DB.QueryRowContext(ctx,
+		`UPDATE pickup_locations
+SET name = $1, address = $2, opening_hours = $3, active = $4, updated_on = NOW()
+WHERE id_pickup_location = $5 AND tenant_id = $6
+RETURNING `+locationColumns,
+		in.Name, in.Address, hours, in.Active, id, tenantID,
+	))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return pickupModel.Location{}, errors.NewNotFound(errors.ErrPickupLocationNotFound)
+		}
+		if isUniqueViolation(err) {
+			return pickupModel.Location{}, errors.ErrPickupLocationNameExists
+		}
+		return pickupModel.Location{}, fmt.Errorf("update pickup location: %w", err)
+	}
+	return l, nil
+}
+
+// DeleteLocation removes a pickup location. Orders placed for it keep their fulfillment type and
+// lose the location reference.
+func (r *Repository) DeleteLocation(ctx context.Context, tenantID, id uint64) error {
+	result, err := r.DB.ExecContext(ctx, `DELETE FROM pickup_locations WHERE id_pickup_location = $1 AND tenant_id = $2`, id, tenantID)
+	if err != nil {
+		return fmt.Errorf("delete pickup location: %w", err)
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("get rows affected: %w", err)
+	}
+	if rows == 0 {
+		return errors.NewNotFound(errors.ErrPickupLocationNotFound)
+	}
+	return nil
+}
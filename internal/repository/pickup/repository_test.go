@@ -0,0 +1,105 @@
+package pickup
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/lib/pq"
+	appErrors "github.com/radamesvaz/bakery-app/internal/errors"
+	pickupModel "github.com/radamesvaz/bakery-app/model/pickup"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var createdOn = time.Date(2026, 3, 2, 9, 0, 0, 0, time.UTC)
+
+func locationRows() *sqlmock.Rows {
+	return sqlmock.NewRows([]string{"id_pickup_location", "tenant_id", "name", "address", "opening_hours", "active", "created_on", "updated_on"})
+}
+
+func TestRepository_ListLocations(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	repo := &Repository{DB: db}
+	mock.ExpectQuery(regexp.QuoteMeta(`FROM pickup_locations WHERE tenant_id = $1 AND active = TRUE ORDER BY name, id_pickup_location`)).
+		WithArgs(uint64(1)).
+		WillReturnRows(locationRows().
+			AddRow(3, 1, "Centro", "Av. Principal 12", []byte(`[{"weekday":6,"opens":"09:00","closes":"13:00"}]`), true, createdOn, createdOn).
+			AddRow(4, 1, "Norte", "Calle 5", []byte(`[]`), true, createdOn, createdOn))
+
+	locations, err := repo.ListLocations(context.Background(), 1, true)
+
+	require.NoError(t, err)
+	require.Len(t, locations, 2)
+	assert.Equal(t, "Centro", locations[0].Name)
+	assert.Equal(t, []pickupModel.OpeningHours{{Weekday: 6, Opens: "09:00", Closes: "13:00"}}, locations[0].OpeningHours)
+	assert.Empty(t, locations[1].OpeningHours)
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestRepository_GetLocation_NotFound(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	repo := &Repository{DB: db}
+	mock.ExpectQuery(regexp.QuoteMeta(`FROM pickup_locations WHERE id_pickup_location = $1 AND tenant_id = $2`)).
+		WithArgs(uint64(9), uint64(1)).
+		WillReturnError(sql.ErrNoRows)
+
+	_, err = repo.GetLocation(context.Background(), 1, 9)
+
+	assert.True(t, errors.Is(err, appErrors.ErrPickupLocationNotFound))
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestRepository_CreateLocation(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	repo := &Repository{DB: db}
+	hours := []byte(`[{"weekday":1,"opens":"08:00","closes":"18:00"}]`)
+	mock.ExpectQuery(regexp.QuoteMeta(`INSERT INTO pickup_locations`)).
+		WithArgs(uint64(1), "Centro", "Av. Principal 12", hours, true).
+		WillReturnRows(locationRows().AddRow(3, 1, "Centro", "Av. Principal 12", hours, true, createdOn, createdOn))
+	mock.ExpectQuery(regexp.QuoteMeta(`INSERT INTO pickup_locations`)).
+		WithArgs(uint64(1), "Centro", "Otra", []byte(`[]`), true).
+		WillReturnError(&pq.Error{Code: "23505"})
+
+	location, err := repo.CreateLocation(context.Background(), 1, pickupModel.Location{
+		Name:         "Centro",
+		Address:      "Av. Principal 12",
+		OpeningHours: []pickupModel.OpeningHours{{Weekday: 1, Opens: "08:00", Closes: "18:00"}},
+		Active:       true,
+	})
+	require.NoError(t, err)
+	assert.Equal(t, uint64(3), location.ID)
+
+	_, err = repo.CreateLocation(context.Background(), 1, pickupModel.Location{Name: "Centro", Address: "Otra", Active: true})
+	assert.True(t, errors.Is(err, appErrors.ErrPickupLocationNameExists))
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestRepository_DeleteLocation_NotFound(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	repo := &Repository{DB: db}
+	mock.ExpectExec(regexp.QuoteMeta(`DELETE FROM pickup_locations WHERE id_pickup_location = $1 AND tenant_id = $2`)).
+		WithArgs(uint64(9), uint64(1)).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+
+	err = repo.DeleteLocation(context.Background(), 1, 9)
+
+	assert.True(t, errors.Is(err, appErrors.ErrPickupLocationNotFound))
+	require.NoError(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,186 @@
+package pricing
+
+import (
+	"context"
+	"database/sql"
+	stdErrors "errors"
+	"fmt"
+
+	"github.com/lib/pq"
+	"github.com/radamesvaz/bakery-app/internal/errors"
+	pricingModel "github.com/radamesvaz/bakery-app/model/pricing"
+)
+
+type Repository struct {
+	DB *sql.DB
+}
+
+type queryer interface {
+	QueryRowContext(ctx context.Context, query string, args ...any) *sql.Row
+}
+
+// queryerFrom returns tx when non-nil so reads happen in the caller's transaction.
+func (r *Repository) queryerFrom(tx *sql.Tx) queryer {
+	if tx != nil {
+		return tx
+	}
+	return r.DB
+}
+
+func isUniqueViolation(err error) bool {
+	var pqErr *pq.Error
+	return stdErrors.As(err, &pqErr) && string(pqErr.Code) == "23505"
+}
+
+// GetSettings returns the tenant's tax and delivery fee; a tenant without a row charges neither.
+func (r *Repository) GetSettings(ctx context.Context, tenantID uint64) (pricingModel.Settings, error) {
+	return r.GetSettingsTx(ctx, nil, tenantID)
+}
+
+// GetSettingsTx is GetSettings within the order transaction.
+func (r *Repository) GetSettingsTx(ctx context.Context, tx *sql.Tx, tenantID uint64) (pricingModel.Settings, error) {
+	var settings pricingModel.Settings
+	err := r.queryerFrom(tx).QueryRowContext(ctx,
+		`SELECT tax_rate, tax_inclusive, delivery_fee, free_delivery_above FROM tenant_pricing WHERE tenant_id = $1`,
+		tenantID,
+	).Scan(&settings.TaxRate, &settings.TaxInclusive, &settings.DeliveryFee, &settings.FreeDeliveryAbove)
+	if err == sql.ErrNoRows {
+		return pricingModel.Settings{}, nil
+	}
+	if err != nil {
+		return pricingModel.Settings{}, fmt.Errorf("get pricing settings: %w", err)
+	}
+	return settings, nil
+}
+
+// UpdateSettings replaces the tenant's tax and delivery fee. Existing orders keep their totals.
+func (r *Repository) UpdateSettings(ctx context.Context, tenantID uint64, settings pricingModel.Settings) error {
+	_, err := r.DB.ExecContext(ctx,
+		`INSERT INTO tenant_pricing (tenant_id, tax_rate, tax_inclusive, delivery_fee, free_delivery_above)
+VALUES ($1, $2, $3, $4, $5)
+ON CONFLICT (tenant_id) DO UPDATE SET
+	tax_rate = EXCLUDED.tax_rate,
+	tax_inclusive = EXCLUDED.tax_inclusive,
+	delivery_fee = EXCLUDED.delivery_fee,
+	free_delivery_above = EXCLUDED.free_delivery_above,
+	updated_on = NOW()`,
+		tenantID, settings.TaxRate, settings.TaxInclusive, settings.DeliveryFee, settings.FreeDeliveryAbove,
+	)
+	if err != nil {
+		return fmt.Errorf("update pricing settings: %w", err)
+	}
+	return nil
+}
+
+const deliveryZoneColumns = `id_delivery_zone, tenant_id, name, fee, free_delivery_above, active, created_on, updated_on`
+
+func scanDeliveryZone(row interface{ Scan(dest ...any) error }) (pricingModel.DeliveryZone, error) {
+	var z pricingModel.DeliveryZone
+	if err := row.Scan(&z.ID, &z.TenantID, &z.Name, &z.Fee, &z.FreeDeliveryAbove, &z.Active, &z.CreatedOn, &z.UpdatedOn); err != nil {
+		return pricingModel.DeliveryZone{}, err
+	}
+	return z, nil
+}
+
+// ListDeliveryZones returns the tenant's delivery zones ordered by name; activeOnly hides the
+// zones the storefront cannot offer.
+func (r *Repository) ListDeliveryZones(ctx context.Context, tenantID uint64, activeOnly bool) ([]pricingModel.DeliveryZone, error) {
+	query := `SELECT ` + deliveryZoneColumns + ` FROM delivery_zones WHERE tenant_id = $1`
+	if activeOnly {
+		query += ` AND active = TRUE`
+	}
+	query += ` ORDER BY name, id_delivery_zone`
+
+	rows, err := r.DB.QueryContext(ctx, query, tenantID)
+	if err != nil {
+		return nil, fmt.Errorf("list delivery zones: %w", err)
+	}
+	defer rows.Close()
+
+	zones := []pricingModel.DeliveryZone{}
+	for rows.Next() {
+		z, err := scanDeliveryZone(rows)
+		if err != nil {
+			return nil, fmt.Errorf("scan delivery zone: %w", err)
+		}
+		zones = append(zones, z)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate delivery zones: %w", err)
+	}
+	return zones, nil
+}
+
+// GetDeliveryZone returns one delivery zone of the tenant.
+func (r *Repository) GetDeliveryZone(ctx context.Context, tenantID, id uint64) (pricingModel.DeliveryZone, error) {
+	return r.GetDeliveryZoneTx(ctx, nil, tenantID, id)
+}
+
+// GetDeliveryZoneTx is GetDeliveryZone within the order transaction.
+func (r *Repository) GetDeliveryZoneTx(ctx context.Context, tx *sql.Tx, tenantID, id uint64) (pricingModel.DeliveryZone, error) {
+	z, err := scanDeliveryZone(r.queryerFrom(tx).QueryRowContext(ctx,
+		`SELECT `+deliveryZoneColumns+` FROM delivery_zones WHERE id_delivery_zone = $1 AND tenant_id = $2`,
+		id, tenantID,
+	))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return pricingModel.DeliveryZone{}, errors.NewNotFound(errors.ErrDeliveryZoneNotFound)
+		}
+		return pricingModel.DeliveryZone{}, fmt.Errorf("get delivery zone: %w", err)
+	}
+	return z, nil
+}
+
+// CreateDeliveryZone stores a delivery zone.
+func (r *Repository) CreateDeliveryZone(ctx context.Context, tenantID uint64, in pricingModel.DeliveryZone) (pricingModel.DeliveryZone, error) {
+	z, err := scanDeliveryZone(r.DB.QueryRowContext(ctx,
+		`INSERT INTO delivery_zones (tenant_id, name, fee, free_delivery_above, active)
+VALUES ($1, $2, $3, $4, $5)
+RETURNING `+deliveryZoneColumns,
+		tenantID, in.Name, in.Fee, in.FreeDeliveryAbove, in.Active,
+	))
+	if err != nil {
+		if isUniqueViolation(err) {
+			return pricingModel.DeliveryZone{}, errors.ErrDeliveryZoneNameExists
+		}
+		return pricingModel.DeliveryZone{}, fmt.Errorf("create delivery zone: %w", err)
+	}
+	return z, nil
+}
+
+// UpdateDeliveryZone replaces a delivery zone. Orders already placed in it keep their fee.
+func (r *Repository) UpdateDeliveryZone(ctx context.Context, tenantID, id uint64, in pricingModel.DeliveryZone) (pricingModel.DeliveryZone, error) {
+	z, err := scanDeliveryZone(r.DB.QueryRowContext(ctx,
+		`UPDATE delivery_zones
+SET name = $1, fee = $2, free_delivery_above = $3, active = $4, updated_on = NOW()
+WHERE id_delivery_zone = $5 AND tenant_id = $6
+RETURNING `+deliveryZoneColumns,
+		in.Name, in.Fee, in.FreeDeliveryAbove, in.Active, id, tenantID,
+	))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return pricingModel.DeliveryZone{}, errors.NewNotFound(errors.ErrDeliveryZoneNotFound)
+		}
+		if isUniqueViolation(err) {
+			return pricingModel.DeliveryZone{}, errors.ErrDeliveryZoneNameExists
+		}
+		return pricingModel.DeliveryZone{}, fmt.Errorf("update delivery zone: %w", err)
+	}
+	return z, nil
+}
+
+// DeleteDeliveryZone removes a delivery zone. Orders placed in it keep their fee.
+func (r *Repository) DeleteDeliveryZone(ctx context.Context, tenantID, id uint64) error {
+	result, err := r.DB.ExecContext(ctx, `DELETE FROM delivery_zones WHERE id_delivery_zone = $1 AND tenant_id = $2`, id, tenantID)
+	if err != nil {
+		return fmt.Errorf("delete delivery zone: %w", err)
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("get rows affected: %w", err)
+	}
+	if rows == 0 {
+		return errors.NewNotFound(errors.ErrDeliveryZoneNotFound)
+	}
+	return nil
+}
@@ -0,0 +1,138 @@
+package pricing
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/lib/pq"
+	appErrors "github.com/radamesvaz/bakery-app/internal/errors"
+	"github.com/radamesvaz/bakery-app/model/money"
+	pricingModel "github.com/radamesvaz/bakery-app/model/pricing"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var createdOn = time.Date(2026, 3, 2, 9, 0, 0, 0, time.UTC)
+
+func deliveryZoneRows() *sqlmock.Rows {
+	return sqlmock.NewRows([]string{"id_delivery_zone", "tenant_id", "name", "fee", "free_delivery_above", "active", "created_on", "updated_on"})
+}
+
+func TestRepository_GetSettings(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	repo := &Repository{DB: db}
+	query := regexp.QuoteMeta(`SELECT tax_rate, tax_inclusive, delivery_fee, free_delivery_above FROM tenant_pricing WHERE tenant_id = $1`)
+
+	mock.ExpectQuery(query).WithArgs(uint64(1)).
+		WillReturnRows(sqlmock.NewRows([]string{"tax_rate", "tax_inclusive", "delivery_fee", "free_delivery_above"}).
+			AddRow(16.0, true, 2.5, 40.0))
+	mock.ExpectQuery(query).WithArgs(uint64(2)).WillReturnError(sql.ErrNoRows)
+
+	settings, err := repo.GetSettings(context.Background(), 1)
+	require.NoError(t, err)
+	require.NotNil(t, settings.FreeDeliveryAbove)
+	assert.Equal(t, money.Amount(4000), *settings.FreeDeliveryAbove)
+	assert.Equal(t, 16.0, settings.TaxRate)
+	assert.True(t, settings.TaxInclusive)
+	assert.Equal(t, money.Amount(250), settings.DeliveryFee)
+
+	settings, err = repo.GetSettings(context.Background(), 2)
+	require.NoError(t, err)
+	assert.Equal(t, pricingModel.Settings{}, settings)
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestRepository_UpdateSettings(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	repo := &Repository{DB: db}
+	mock.ExpectExec(regexp.QuoteMeta(`INSERT INTO tenant_pricing`)).
+		WithArgs(uint64(1), 16.0, false, "3.00", nil).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	err = repo.UpdateSettings(context.Background(), 1, pricingModel.Settings{TaxRate: 16, DeliveryFee: 300})
+
+	require.NoError(t, err)
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestRepository_ListDeliveryZones(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	repo := &Repository{DB: db}
+	mock.ExpectQuery(regexp.QuoteMeta(`FROM delivery_zones WHERE tenant_id = $1 AND active = TRUE ORDER BY name, id_delivery_zone`)).
+		WithArgs(uint64(1)).
+		WillReturnRows(deliveryZoneRows().
+			AddRow(3, 1, "Centro", 2.0, nil, true, createdOn, createdOn).
+			AddRow(4, 1, "Norte", 4.5, 60.0, true, createdOn, createdOn))
+
+	zones, err := repo.ListDeliveryZones(context.Background(), 1, true)
+
+	require.NoError(t, err)
+	require.Len(t, zones, 2)
+	assert.Equal(t, "Centro", zones[0].Name)
+	assert.Nil(t, zones[0].FreeDeliveryAbove)
+	require.NotNil(t, zones[1].FreeDeliveryAbove)
+	assert.Equal(t, money.Amount(6000), *zones[1].FreeDeliveryAbove)
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestRepository_GetDeliveryZone_NotFound(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	repo := &Repository{DB: db}
+	mock.ExpectQuery(regexp.QuoteMeta(`FROM delivery_zones WHERE id_delivery_zone = $1 AND tenant_id = $2`)).
+		WithArgs(uint64(9), uint64(1)).
+		WillReturnError(sql.ErrNoRows)
+
+	_, err = repo.GetDeliveryZone(context.Background(), 1, 9)
+
+	assert.True(t, errors.Is(err, appErrors.ErrDeliveryZoneNotFound))
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestRepository_CreateDeliveryZone_DuplicateName(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	repo := &Repository{DB: db}
+	mock.ExpectQuery(regexp.QuoteMeta(`INSERT INTO delivery_zones`)).
+		WithArgs(uint64(1), "Centro", "2.00", nil, true).
+		WillReturnError(&pq.Error{Code: "23505"})
+
+	_, err = repo.CreateDeliveryZone(context.Background(), 1, pricingModel.DeliveryZone{Name: "Centro", Fee: 200, Active: true})
+
+	assert.True(t, errors.Is(err, appErrors.ErrDeliveryZoneNameExists))
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestRepository_DeleteDeliveryZone_NotFound(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	repo := &Repository{DB: db}
+	mock.ExpectExec(regexp.QuoteMeta(`DELETE FROM delivery_zones WHERE id_delivery_zone = $1 AND tenant_id = $2`)).
+		WithArgs(uint64(9), uint64(1)).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+
+	err = repo.DeleteDeliveryZone(context.Background(), 1, 9)
+
+	assert.True(t, errors.Is(err, appErrors.ErrDeliveryZoneNotFound))
+	require.NoError(t, mock.ExpectationsWereMet())
+}
@@ -239,7 +239,7 @@ func (r *ProductRepository) GetProductsByIDs(ctx context.Context, tenantID uint6
 func (r *ProductRepository) CreateProduct(_ context.Context, tenantID uint64, product pModel.Product) (pModel.Product, error) {
 	logger.Debug().
 		Str("name", product.Name).
-		Float64("price", product.Price).
+		Stringer("price", product.Price).
 		Msg("Creating product")
 
 	createdProduct := pModel.Product{}
@@ -4,9 +4,12 @@ import (
 	"context"
 	"database/sql"
 	"encoding/json"
+	"fmt"
+	"slices"
 
 	"github.com/radamesvaz/bakery-app/internal/errors"
 	"github.com/radamesvaz/bakery-app/internal/logger"
+	"github.com/radamesvaz/bakery-app/internal/pagination"
 	pModel "github.com/radamesvaz/bakery-app/model/products"
 )
 
@@ -116,3 +119,139 @@ func (r *ProductRepository) CreateProductHistory(_ context.Context, tenantID uin
 	return nil
 
 }
+
+// ListProductHistoryPageResult is one page of product history (cursor pagination on id_products_history DESC).
+type ListProductHistoryPageResult struct {
+	Items      []pModel.ProductHistoryEntry
+	NextCursor *string
+}
+
+// ListProductHistoryPage returns up to limit history snapshots of a product for the tenant, newest first.
+// If afterID is non-nil, only rows with id_products_history < *afterID are considered (next page).
+// Each entry carries the changes versus the snapshot right before it; the extra row fetched to detect
+// a following page doubles as the previous snapshot of the last visible entry.
+func (r *ProductRepository) ListProductHistoryPage(
+	ctx context.Context,
+	tenantID uint64,
+	idProduct uint64,
+	limit int,
+	afterID *uint64,
+) (ListProductHistoryPageResult, error) {
+	logger.Debug().Uint64("tenant_id", tenantID).Uint64("product_id", idProduct).Int("limit", limit).Msg("Listing product history page")
+	if limit < 1 {
+		return ListProductHistoryPageResult{}, fmt.Errorf("limit must be at least 1")
+	}
+
+	q := `SELECT id_products_history, id_product, name, COALESCE(description, ''), price, track_inventory, stock, status, image_urls, thumbnail_url, modified_on, COALESCE(modified_by, 0), action
+FROM products_history WHERE tenant_id = $1 AND id_product = $2`
+	args := []interface{}{tenantID, idProduct}
+	argPos := 3
+	if afterID != nil {
+		q += fmt.Sprintf(" AND id_products_history < $%d", argPos)
+		args = append(args, *afterID)
+		argPos++
+	}
+	q += fmt.Sprintf(" ORDER BY id_products_history DESC LIMIT $%d", argPos)
+	args = append(args, limit+1)
+
+	rows, err := r.DB.QueryContext(ctx, q, args...)
+	if err != nil {
+		logger.Err(err).Uint64("product_id", idProduct).Msg("Error listing product history page")
+		return ListProductHistoryPageResult{}, err
+	}
+	defer rows.Close()
+
+	entries := []pModel.ProductHistoryEntry{}
+	for rows.Next() {
+		var entry pModel.ProductHistoryEntry
+		var imageURLsJSON sql.NullString
+		var thumbnailURL sql.NullString
+		var modifiedOn sql.NullTime
+		if err := rows.Scan(
+			&entry.ID,
+			&entry.IDProduct,
+			&entry.Name,
+			&entry.Description,
+			&entry.Price,
+			&entry.TrackInventory,
+			&entry.Stock,
+			&entry.Status,
+			&imageURLsJSON,
+			&thumbnailURL,
+			&modifiedOn,
+			&entry.ModifiedBy,
+			&entry.Action,
+		); err != nil {
+			logger.Err(err).Msg("Error mapping the product history")
+			return ListProductHistoryPageResult{}, err
+		}
+
+		entry.ImageURLs = []string{}
+		if imageURLsJSON.Valid && imageURLsJSON.String != "" {
+			var imageURLs []string
+			if err := json.Unmarshal([]byte(imageURLsJSON.String), &imageURLs); err != nil {
+				logger.Warn().Err(err).Uint64("product_history_id", entry.ID).Msg("Error parsing image URLs")
+			} else if imageURLs != nil {
+				entry.ImageURLs = imageURLs
+			}
+		}
+		if thumbnailURL.Valid {
+			entry.ThumbnailURL = thumbnailURL.String
+		}
+		if modifiedOn.Valid {
+			entry.ModifiedOn = &modifiedOn.Time
+		}
+
+		entries = append(entries, entry)
+	}
+	if err := rows.Err(); err != nil {
+		return ListProductHistoryPageResult{}, err
+	}
+
+	for i := range entries {
+		if i+1 < len(entries) {
+			entries[i].Changes = diffProductHistoryEntries(entries[i+1], entries[i])
+		} else {
+			entries[i].Changes = []pModel.ProductHistoryChange{}
+		}
+	}
+
+	hasNext := len(entries) > limit
+	if hasNext {
+		entries = entries[:limit]
+	}
+
+	var next *string
+	if hasNext && len(entries) > 0 {
+		last := entries[len(entries)-1].ID
+		enc, err := pagination.EncodeIDCursor(last)
+		if err != nil {
+			return ListProductHistoryPageResult{}, fmt.Errorf("encoding next cursor: %w", err)
+		}
+		next = &enc
+	}
+
+	logger.Debug().Int("count", len(entries)).Bool("has_next", hasNext).Msg("Product history page retrieved")
+	return ListProductHistoryPageResult{Items: entries, NextCursor: next}, nil
+}
+
+// diffProductHistoryEntries lists the audited product fields that changed from prev to cur.
+func diffProductHistoryEntries(prev, cur pModel.ProductHistoryEntry) []pModel.ProductHistoryChange {
+	changes := []pModel.ProductHistoryChange{}
+	if prev.Price != cur.Price {
+		changes = append(changes, pModel.ProductHistoryChange{Field: "price", From: prev.Price, To: cur.Price})
+	}
+	if prev.Stock != cur.Stock {
+		changes = append(changes, pModel.ProductHistoryChange{Field: "stock", From: prev.Stock, To: cur.Stock})
+	}
+	if prev.Status != cur.Status {
+		changes = append(changes, pModel.ProductHistoryChange{Field: "status", From: prev.Status, To: cur.Status})
+	}
+	if !slices.Equal(prev.ImageURLs, cur.ImageURLs) {
+		changes = append(changes, pModel.ProductHistoryChange{Field: "image_urls", From: prev.ImageURLs, To: cur.ImageURLs})
+	}
+	if prev.ThumbnailURL != cur.ThumbnailURL {
+		changes = append(changes, pModel.ProductHistoryChange{Field: "thumbnail_url", From: prev.ThumbnailURL, To: cur.ThumbnailURL})
+	}
+	return changes
+}
@@ -6,8 +6,11 @@ import (
 	"testing"
 
 	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/radamesvaz/bakery-app/internal/pagination"
+	"github.com/radamesvaz/bakery-app/model/money"
 	pModel "github.com/radamesvaz/bakery-app/model/products"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestProductRepository_CreateProductHistory(t *testing.T) {
@@ -34,7 +37,7 @@ func TestProductRepository_CreateProductHistory(t *testing.T) {
 				IDProduct:      1,
 				Name:           "Producto prueba test OK",
 				Description:    "Esta es la descripcion del producto de prueba",
-				Price:          20.3,
+				Price:          2030,
 				TrackInventory: true,
 				Stock:          5,
 				Status:         pModel.StatusActive,
@@ -52,7 +55,7 @@ func TestProductRepository_CreateProductHistory(t *testing.T) {
 				IDProduct:      1,
 				Name:           "Producto prueba test OK",
 				Description:    "Esta es la descripcion del producto de prueba",
-				Price:          20.3,
+				Price:          2030,
 				TrackInventory: true,
 				Stock:          5,
 				Status:         pModel.StatusInactive,
@@ -70,7 +73,7 @@ func TestProductRepository_CreateProductHistory(t *testing.T) {
 				IDProduct:      1,
 				Name:           "Producto prueba test OK",
 				Description:    "Esta es la descripcion del producto de prueba",
-				Price:          20.3,
+				Price:          2030,
 				TrackInventory: true,
 				Stock:          5,
 				Status:         pModel.StatusDeleted,
@@ -193,3 +196,82 @@ func TestProductRepository_CreateProductHistory(t *testing.T) {
 		})
 	}
 }
+
+func TestProductRepository_ListProductHistoryPage(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	repo := &ProductRepository{DB: db}
+
+	const tenantID = uint64(1)
+	const productID = uint64(3)
+	columns := []string{
+		"id_products_history", "id_product", "name", "description", "price", "track_inventory", "stock", "status",
+		"image_urls", "thumbnail_url", "modified_on", "modified_by", "action",
+	}
+	query := regexp.QuoteMeta(`SELECT id_products_history, id_product, name, COALESCE(description, ''), price, track_inventory, stock, status, image_urls, thumbnail_url, modified_on, COALESCE(modified_by, 0), action
+FROM products_history WHERE tenant_id = $1 AND id_product = $2`)
+
+	t.Run("empty page", func(t *testing.T) {
+		mock.ExpectQuery(query).
+			WithArgs(tenantID, productID, 3).
+			WillReturnRows(sqlmock.NewRows(columns))
+
+		page, err := repo.ListProductHistoryPage(context.Background(), tenantID, productID, 2, nil)
+		require.NoError(t, err)
+		assert.Empty(t, page.Items)
+		assert.Nil(t, page.NextCursor)
+		require.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("diffs against previous snapshot including the look-ahead row", func(t *testing.T) {
+		rows := sqlmock.NewRows(columns).
+			AddRow(12, productID, "Cake", "d", 12.5, true, 4, "active", `["a.jpg","b.jpg"]`, "b.jpg", nil, 1, "update").
+			AddRow(11, productID, "Cake", "d", 10.0, true, 4, "active", `["a.jpg"]`, "a.jpg", nil, 1, "update").
+			AddRow(10, productID, "Cake", "d", 10.0, true, 6, "inactive", `["a.jpg"]`, "a.jpg", nil, 1, "create")
+
+		mock.ExpectQuery(query).
+			WithArgs(tenantID, productID, 3).
+			WillReturnRows(rows)
+
+		page, err := repo.ListProductHistoryPage(context.Background(), tenantID, productID, 2, nil)
+		require.NoError(t, err)
+		require.Len(t, page.Items, 2)
+
+		assert.Equal(t, []pModel.ProductHistoryChange{
+			{Field: "price", From: money.Amount(1000), To: money.Amount(1250)},
+			{Field: "image_urls", From: []string{"a.jpg"}, To: []string{"a.jpg", "b.jpg"}},
+			{Field: "thumbnail_url", From: "a.jpg", To: "b.jpg"},
+		}, page.Items[0].Changes)
+		assert.Equal(t, []pModel.ProductHistoryChange{
+			{Field: "stock", From: uint64(6), To: uint64(4)},
+			{Field: "status", From: pModel.ProductStatus("inactive"), To: pModel.ProductStatus("active")},
+		}, page.Items[1].Changes)
+
+		require.NotNil(t, page.NextCursor)
+		cursorID, err := pagination.DecodeIDCursor(*page.NextCursor)
+		require.NoError(t, err)
+		assert.Equal(t, uint64(11), cursorID)
+		require.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("last page oldest snapshot has no changes", func(t *testing.T) {
+		after := uint64(11)
+		rows := sqlmock.NewRows(columns).
+			AddRow(10, productID, "Cake", "", 10.0, true, 6, "active", nil, nil, nil, 0, "create")
+
+		mock.ExpectQuery(query+".*"+regexp.QuoteMeta("AND id_products_history < $3")).
+			WithArgs(tenantID, productID, after, 3).
+			WillReturnRows(rows)
+
+		page, err := repo.ListProductHistoryPage(context.Background(), tenantID, productID, 2, &after)
+		require.NoError(t, err)
+		require.Len(t, page.Items, 1)
+		assert.NotNil(t, page.Items[0].Changes)
+		assert.Empty(t, page.Items[0].Changes)
+		assert.Equal(t, []string{}, page.Items[0].ImageURLs)
+		assert.Nil(t, page.NextCursor)
+		require.NoError(t, mock.ExpectationsWereMet())
+	})
+}
@@ -183,7 +183,7 @@ func TestProductRepository_GetProductByID(t *testing.T) {
 				TenantID:       1,
 				Name:           "Torta de chocolate test",
 				Description:    "Test descripcion de la torta test",
-				Price:          30,
+				Price:          3000,
 				TrackInventory: true,
 				Stock:          5,
 				Status:         "active",
@@ -270,7 +270,7 @@ func TestProductRepository_CreateProduct(t *testing.T) {
 				TenantID:       1,
 				Name:           "Producto prueba test OK",
 				Description:    "Esta es la descripcion del producto de prueba",
-				Price:          20.3,
+				Price:          2030,
 				TrackInventory: true,
 				Stock:          66,
 				Status:         pModel.StatusActive,
@@ -283,7 +283,7 @@ func TestProductRepository_CreateProduct(t *testing.T) {
 				TenantID:       1,
 				Name:           "Producto prueba test OK",
 				Description:    "Esta es la descripcion del producto de prueba",
-				Price:          20.3,
+				Price:          2030,
 				TrackInventory: true,
 				Stock:          66,
 				Status:         pModel.StatusActive,
@@ -325,7 +325,7 @@ func TestProductRepository_CreateProduct(t *testing.T) {
 			payload: pModel.Product{
 				Name:           "",
 				Description:    "Esta es la descripcion del producto de prueba",
-				Price:          20.3,
+				Price:          2030,
 				TrackInventory: true,
 				ImageURLs:      []string{},
 				ThumbnailURL:   "",
@@ -340,7 +340,7 @@ func TestProductRepository_CreateProduct(t *testing.T) {
 			payload: pModel.Product{
 				Name:           "Name",
 				Description:    "",
-				Price:          20.3,
+				Price:          2030,
 				TrackInventory: true,
 				ImageURLs:      []string{},
 				ThumbnailURL:   "",
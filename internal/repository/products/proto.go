@@ -3,6 +3,7 @@ package products
 import (
 	"time"
 
+	"github.com/radamesvaz/bakery-app/model/money"
 	pModel "github.com/radamesvaz/bakery-app/model/products"
 )
 
@@ -11,7 +12,7 @@ type ProductResponse struct {
 	ID             uint64               `json:"id_product" gorm:"primaryKey"`
 	Name           string               `json:"name" gorm:"not null;unique"`
 	Description    string               `json:"description"`
-	Price          float64              `json:"price" gorm:"not null;check:price >= 0"`
+	Price          money.Amount         `json:"price" gorm:"not null;check:price >= 0"`
 	TrackInventory bool                 `json:"track_inventory"`
 	Stock          uint64               `json:"stock"`
 	Status         pModel.ProductStatus `json:"status"`
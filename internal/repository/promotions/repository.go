@@ -0,0 +1,338 @@
+package promotions
+
+import (
+	"context"
+	"database/sql"
+	stdErrors "errors"
+	"fmt"
+	"time"
+
+	"github.com/lib/pq"
+	"github.com/radamesvaz/bakery-app/internal/errors"
+	"github.com/radamesvaz/bakery-app/model/money"
+	promoModel "github.com/radamesvaz/bakery-app/model/promotions"
+)
+
+type Repository struct {
+	DB *sql.DB
+}
+
+type queryer interface {
+	QueryContext(ctx context.Context, query string, args ...any) (*sql.Rows, error)
+	QueryRowContext(ctx context.Context, query string, args ...any) *sql.Row
+}
+
+// liveRedemptionsExpr counts the redemptions of p whose order still stands; cancelled, expired and
+// deleted orders give their use back.
+const liveRedemptionsExpr = `(SELECT COUNT(*) FROM promotion_redemptions r
+	JOIN orders o ON o.id_order = r.id_order
+	WHERE r.id_promotion = p.id_promotion AND o.status NOT IN ('cancelled', 'expired', 'deleted'))`
+
+const promotionColumns = `p.id_promotion, p.tenant_id, p.code, p.description, p.discount_type, p.discount_value, p.scope,
+	p.min_order_total, p.starts_at, p.ends_at, p.max_uses, p.max_uses_per_customer, p.active, p.created_on, p.updated_on, ` + liveRedemptionsExpr
+
+func scanPromotion(row interface{ Scan(dest ...any) error }) (promoModel.Promotion, error) {
+	var (
+		p                  promoModel.Promotion
+		minOrderTotal      sql.Null[money.Amount]
+		startsAt, endsAt   sql.NullTime
+		maxUses, maxPerCus sql.NullInt64
+	)
+	err := row.Scan(&p.ID, &p.TenantID, &p.Code, &p.Description, &p.DiscountType, &p.Value, &p.Scope,
+		&minOrderTotal, &startsAt, &endsAt, &maxUses, &maxPerCus, &p.Active, &p.CreatedOn, &p.UpdatedOn, &p.Uses)
+	if err != nil {
+		return promoModel.Promotion{}, err
+	}
+	if minOrderTotal.Valid {
+		p.MinOrderTotal = &minOrderTotal.V
+	}
+	if startsAt.Valid {
+		p.StartsAt = &startsAt.Time
+	}
+	if endsAt.Valid {
+		p.EndsAt = &endsAt.Time
+	}
+	if maxUses.Valid {
+		n := int(maxUses.Int64)
+		p.MaxUses = &n
+	}
+	if maxPerCus.Valid {
+		n := int(maxPerCus.Int64)
+		p.MaxUsesPerCustomer = &n
+	}
+	p.ProductIDs = []uint64{}
+	return p, nil
+}
+
+func nullTime(t *time.Time) sql.NullTime {
+	if t == nil {
+		return sql.NullTime{}
+	}
+	return sql.NullTime{Time: *t, Valid: true}
+}
+
+func nullInt(n *int) sql.NullInt64 {
+	if n == nil {
+		return sql.NullInt64{}
+	}
+	return sql.NullInt64{Int64: int64(*n), Valid: true}
+}
+
+func isUniqueViolation(err error) bool {
+	var pqErr *pq.Error
+	return stdErrors.As(err, &pqErr) && string(pqErr.Code) == "23505"
+}
+
+// attachProducts loads the discounted products of the given promotions.
+func attachProducts(ctx context.Context, q queryer, tenantID uint64, promotions []promoModel.Promotion) error {
+	if len(promotions) == 0 {
+		return nil
+	}
+	ids := make([]int64, len(promotions))
+	byID := make(map[uint64]int, len(promotions))
+	for i, p := range promotions {
+		ids[i] = int64(p.ID)
+		byID[p.ID] = i
+	}
+	rows, err := q.QueryContext(ctx,
+		`SELECT id_promotion, id_product FROM promotion_products
+WHERE tenant_id = $1 AND id_promotion = ANY($2)
+ORDER BY id_promotion, id_product`,
+		tenantID, pq.Int64Array(ids),
+	)
+	if err != nil {
+		return fmt.Errorf("list promotion products: %w", err)
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var id, idProduct uint64
+		if err := rows.Scan(&id, &idProduct); err != nil {
+			return fmt.Errorf("scan promotion product: %w", err)
+		}
+		if i, ok := byID[id]; ok {
+			promotions[i].ProductIDs = append(promotions[i].ProductIDs, idProduct)
+		}
+	}
+	return rows.Err()
+}
+
+// replaceProductsTx stores the discounted products of a promotion. Products of other tenants are
+// reported as not found.
+func replaceProductsTx(ctx context.Context, tx *sql.Tx, tenantID, id uint64, productIDs []uint64) error {
+	if _, err := tx.ExecContext(ctx, `DELETE FROM promotion_products WHERE id_promotion = $1 AND tenant_id = $2`, id, tenantID); err != nil {
+		return fmt.Errorf("delete promotion products: %w", err)
+	}
+	for _, idProduct := range productIDs {
+		result, err := tx.ExecContext(ctx,
+			`INSERT INTO promotion_products (id_promotion, tenant_id, id_product)
+SELECT $1, $2, id_product FROM products WHERE id_product = $3 AND tenant_id = $2`,
+			id, tenantID, idProduct,
+		)
+		if err != nil {
+			return fmt.Errorf("insert promotion product: %w", err)
+		}
+		rows, err := result.RowsAffected()
+		if err != nil {
+			return fmt.Errorf("get rows affected: %w", err)
+		}
+		if rows == 0 {
+			return errors.NewNotFound(errors.ErrProductNotFound)
+		}
+	}
+	return nil
+}
+
+// CreatePromotion stores a promotion and its products.
+func (r *Repository) CreatePromotion(ctx context.Context, tenantID uint64, in promoModel.Promotion) (promoModel.Promotion, error) {
+	tx, err := r.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return promoModel.Promotion{}, fmt.Errorf("begin tx: %w", err)
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	var id uint64
+	err = tx.QueryRowContext(ctx,
+		`INSERT INTO promotions (tenant_id, code, description, discount_type, discount_value, scope, min_order_total,
+	starts_at, ends_at, max_uses, max_uses_per_customer, active)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12)
+RETURNING id_promotion`,
+		tenantID, in.Code, in.Description, string(in.DiscountType), in.Value, string(in.Scope), in.MinOrderTotal,
+		nullTime(in.StartsAt), nullTime(in.EndsAt), nullInt(in.MaxUses), nullInt(in.MaxUsesPerCustomer), in.Active,
+	).Scan(&id)
+	if err != nil {
+		if isUniqueViolation(err) {
+			return promoModel.Promotion{}, errors.ErrPromotionCodeExists
+		}
+		return promoModel.Promotion{}, fmt.Errorf("create promotion: %w", err)
+	}
+	if err := replaceProductsTx(ctx, tx, tenantID, id, in.ProductIDs); err != nil {
+		return promoModel.Promotion{}, err
+	}
+	if err := tx.Commit(); err != nil {
+		return promoModel.Promotion{}, fmt.Errorf("commit tx: %w", err)
+	}
+	return r.GetPromotion(ctx, tenantID, id)
+}
+
+// ListPromotions returns every promotion of the tenant, newest first.
+func (r *Repository) ListPromotions(ctx context.Context, tenantID uint64) ([]promoModel.Promotion, error) {
+	rows, err := r.DB.QueryContext(ctx,
+		`SELECT `+promotionColumns+` FROM promotions p WHERE p.tenant_id = $1 ORDER BY p.id_promotion DESC`,
+		tenantID,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("list promotions: %w", err)
+	}
+	defer rows.Close()
+
+	out := []promoModel.Promotion{}
+	for rows.Next() {
+		p, err := scanPromotion(rows)
+		if err != nil {
+			return nil, fmt.Errorf("scan promotion: %w", err)
+		}
+		out = append(out, p)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate promotions: %w", err)
+	}
+	if err := attachProducts(ctx, r.DB, tenantID, out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// GetPromotion returns one promotion of the tenant.
+func (r *Repository) GetPromotion(ctx context.Context, tenantID, id uint64) (promoModel.Promotion, error) {
+	return getPromotion(ctx, r.DB, tenantID, `p.id_promotion = $2`, "", id)
+}
+
+// GetPromotionByIDTx returns one promotion of the tenant within a transaction.
+func (r *Repository) GetPromotionByIDTx(ctx context.Context, tx *sql.Tx, tenantID, id uint64) (promoModel.Promotion, error) {
+	return getPromotion(ctx, tx, tenantID, `p.id_promotion = $2`, "", id)
+}
+
+// GetPromotionByCodeForUpdateTx returns the promotion with the code (case-insensitive) and locks it
+// until the transaction ends, so concurrent orders redeeming it are counted one at a time.
+// An unknown code is ErrPromotionCodeInvalid.
+func (r *Repository) GetPromotionByCodeForUpdateTx(ctx context.Context, tx *sql.Tx, tenantID uint64, code string) (promoModel.Promotion, error) {
+	p, err := getPromotion(ctx, tx, tenantID, `UPPER(p.code) = UPPER($2)`, " FOR UPDATE OF p", code)
+	if stdErrors.Is(err, errors.ErrPromotionNotFound) {
+		return promoModel.Promotion{}, errors.ErrPromotionCodeInvalid
+	}
+	return p, err
+}
+
+func getPromotion(ctx context.Context, q queryer, tenantID uint64, where, lock string, arg any) (promoModel.Promotion, error) {
+	p, err := scanPromotion(q.QueryRowContext(ctx,
+		`SELECT `+promotionColumns+` FROM promotions p WHERE p.tenant_id = $1 AND `+where+lock,
+		tenantID, arg,
+	))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return promoModel.Promotion{}, errors.NewNotFound(errors.ErrPromotionNotFound)
+		}
+		return promoModel.Promotion{}, fmt.Errorf("get promotion: %w", err)
+	}
+	promotions := []promoModel.Promotion{p}
+	if err := attachProducts(ctx, q, tenantID, promotions); err != nil {
+		return promoModel.Promotion{}, err
+	}
+	return promotions[0], nil
+}
+
+// UpdatePromotion replaces a promotion and its products. Orders that already used it keep their discount.
+func (r *Repository) UpdatePromotion(ctx context.Context, tenantID, id uint64, in promoModel.Promotion) (promoModel.Promotion, error) {
+	tx, err := r.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return promoModel.Promotion{}, fmt.Errorf("begin tx: %w", err)
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	result, err := tx.ExecContext(ctx,
+		`UPDATE promotions
+SET code = $1, description = $2, discount_type = $3, discount_value = $4, scope = $5, min_order_total = $6,
+	starts_at = $7, ends_at = $8, max_uses = $9, max_uses_per_customer = $10, active = $11, updated_on = NOW()
+WHERE id_promotion = $12 AND tenant_id = $13`,
+		in.Code, in.Description, string(in.DiscountType), in.Value, string(in.Scope), in.MinOrderTotal,
+		nullTime(in.StartsAt), nullTime(in.EndsAt), nullInt(in.MaxUses), nullInt(in.MaxUsesPerCustomer), in.Active, id, tenantID,
+	)
+	if err != nil {
+		if isUniqueViolation(err) {
+			return promoModel.Promotion{}, errors.ErrPromotionCodeExists
+		}
+		return promoModel.Promotion{}, fmt.Errorf("update promotion: %w", err)
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return promoModel.Promotion{}, fmt.Errorf("get rows affected: %w", err)
+	}
+	if rows == 0 {
+		return promoModel.Promotion{}, errors.NewNotFound(errors.ErrPromotionNotFound)
+	}
+	if err := replaceProductsTx(ctx, tx, tenantID, id, in.ProductIDs); err != nil {
+		return promoModel.Promotion{}, err
+	}
+	if err := tx.Commit(); err != nil {
+		return promoModel.Promotion{}, fmt.Errorf("commit tx: %w", err)
+	}
+	return r.GetPromotion(ctx, tenantID, id)
+}
+
+// DeletePromotion removes a promotion and its redemption log. Orders that used it keep their
+// discount and promotion_code.
+func (r *Repository) DeletePromotion(ctx context.Context, tenantID, id uint64) error {
+	result, err := r.DB.ExecContext(ctx, `DELETE FROM promotions WHERE id_promotion = $1 AND tenant_id = $2`, id, tenantID)
+	if err != nil {
+		return fmt.Errorf("delete promotion: %w", err)
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("get rows affected: %w", err)
+	}
+	if rows == 0 {
+		return errors.NewNotFound(errors.ErrPromotionNotFound)
+	}
+	return nil
+}
+
+// CountRedemptionsTx returns the live redemptions of a promotion: all of them and those of email
+// (case-insensitive). Orders that are cancelled, expired or deleted are not counted.
+func (r *Repository) CountRedemptionsTx(ctx context.Context, tx *sql.Tx, tenantID, promotionID uint64, email string) (total, byCustomer int, err error) {
+	err = tx.QueryRowContext(ctx,
+		`SELECT COUNT(*), COUNT(*) FILTER (WHERE LOWER(r.email) = LOWER($3))
+FROM promotion_redemptions r
+JOIN orders o ON o.id_order = r.id_order
+WHERE r.tenant_id = $1 AND r.id_promotion = $2 AND o.status NOT IN ('cancelled', 'expired', 'deleted')`,
+		tenantID, promotionID, email,
+	).Scan(&total, &byCustomer)
+	if err != nil {
+		return 0, 0, fmt.Errorf("count promotion redemptions: %w", err)
+	}
+	return total, byCustomer, nil
+}
+
+// UpdateRedemptionDiscountTx sets the discount an order got from its promotion after its items
+// were edited. Orders without a redemption are left alone.
+func (r *Repository) UpdateRedemptionDiscountTx(ctx context.Context, tx *sql.Tx, tenantID, orderID uint64, discount money.Amount) error {
+	_, err := tx.ExecContext(ctx,
+		`UPDATE promotion_redemptions SET discount_amount = $1 WHERE tenant_id = $2 AND id_order = $3`,
+		discount, tenantID, orderID,
+	)
+	if err != nil {
+		return fmt.Errorf("update promotion redemption: %w", err)
+	}
+	return nil
+}
+
+// CreateRedemptionTx records that an order used a promotion.
+func (r *Repository) CreateRedemptionTx(ctx context.Context, tx *sql.Tx, redemption promoModel.Redemption) error {
+	_, err := tx.ExecContext(ctx,
+		`INSERT INTO promotion_redemptions (tenant_id, id_promotion, id_order, email, discount_amount) VALUES ($1, $2, $3, $4, $5)`,
+		redemption.TenantID, redemption.IDPromotion, redemption.IDOrder, redemption.Email, redemption.DiscountAmount,
+	)
+	if err != nil {
+		return fmt.Errorf("create promotion redemption: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,185 @@
+package promotions
+
+import (
+	"context"
+	"errors"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/lib/pq"
+	appErrors "github.com/radamesvaz/bakery-app/internal/errors"
+	"github.com/radamesvaz/bakery-app/model/money"
+	promoModel "github.com/radamesvaz/bakery-app/model/promotions"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var createdOn = time.Date(2026, 2, 20, 9, 0, 0, 0, time.UTC)
+
+func promotionRows() *sqlmock.Rows {
+	return sqlmock.NewRows([]string{"id_promotion", "tenant_id", "code", "description", "discount_type", "discount_value", "scope",
+		"min_order_total", "starts_at", "ends_at", "max_uses", "max_uses_per_customer", "active", "created_on", "updated_on", "uses"})
+}
+
+func TestRepository_CreatePromotion(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	repo := &Repository{DB: db}
+	maxUses := 100
+	in := promoModel.Promotion{
+		Code:         "PAN20",
+		DiscountType: promoModel.DiscountPercentage,
+		Value:        20,
+		Scope:        promoModel.ScopeProducts,
+		ProductIDs:   []uint64{3},
+		MaxUses:      &maxUses,
+		Active:       true,
+	}
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(regexp.QuoteMeta(`INSERT INTO promotions`)).
+		WithArgs(uint64(1), "PAN20", "", "percentage", 20.0, "products", nil, nil, nil, int64(100), nil, true).
+		WillReturnRows(sqlmock.NewRows([]string{"id_promotion"}).AddRow(4))
+	mock.ExpectExec(regexp.QuoteMeta(`DELETE FROM promotion_products WHERE id_promotion = $1 AND tenant_id = $2`)).
+		WithArgs(uint64(4), uint64(1)).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec(regexp.QuoteMeta(`INSERT INTO promotion_products`)).
+		WithArgs(uint64(4), uint64(1), uint64(3)).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+	mock.ExpectQuery(regexp.QuoteMeta(`FROM promotions p WHERE p.tenant_id = $1 AND p.id_promotion = $2`)).
+		WithArgs(uint64(1), uint64(4)).
+		WillReturnRows(promotionRows().
+			AddRow(4, 1, "PAN20", "", "percentage", 20.0, "products", nil, nil, nil, 100, nil, true, createdOn, createdOn, 0))
+	mock.ExpectQuery(regexp.QuoteMeta(`FROM promotion_products`)).
+		WithArgs(uint64(1), pq.Int64Array{4}).
+		WillReturnRows(sqlmock.NewRows([]string{"id_promotion", "id_product"}).AddRow(4, 3))
+
+	p, err := repo.CreatePromotion(context.Background(), 1, in)
+	require.NoError(t, err)
+	assert.Equal(t, uint64(4), p.ID)
+	assert.Equal(t, []uint64{3}, p.ProductIDs)
+	require.NotNil(t, p.MaxUses)
+	assert.Equal(t, 100, *p.MaxUses)
+	assert.Nil(t, p.MaxUsesPerCustomer)
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestRepository_CreatePromotion_DuplicateCode(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	repo := &Repository{DB: db}
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(regexp.QuoteMeta(`INSERT INTO promotions`)).
+		WillReturnError(&pq.Error{Code: "23505"})
+	mock.ExpectRollback()
+
+	_, err = repo.CreatePromotion(context.Background(), 1, promoModel.Promotion{Code: "PAN20", DiscountType: promoModel.DiscountFixed, Value: 5, Scope: promoModel.ScopeOrder})
+	assert.True(t, errors.Is(err, appErrors.ErrPromotionCodeExists))
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestRepository_CreatePromotion_ProductOfOtherTenant(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	repo := &Repository{DB: db}
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(regexp.QuoteMeta(`INSERT INTO promotions`)).
+		WillReturnRows(sqlmock.NewRows([]string{"id_promotion"}).AddRow(4))
+	mock.ExpectExec(regexp.QuoteMeta(`DELETE FROM promotion_products`)).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec(regexp.QuoteMeta(`INSERT INTO promotion_products`)).
+		WithArgs(uint64(4), uint64(1), uint64(99)).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectRollback()
+
+	_, err = repo.CreatePromotion(context.Background(), 1, promoModel.Promotion{
+		Code: "PAN20", DiscountType: promoModel.DiscountFixed, Value: 5, Scope: promoModel.ScopeProducts, ProductIDs: []uint64{99},
+	})
+	assert.True(t, errors.Is(err, appErrors.ErrProductNotFound))
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestRepository_GetPromotionByCodeForUpdateTx_UnknownCode(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	repo := &Repository{DB: db}
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(regexp.QuoteMeta(`WHERE p.tenant_id = $1 AND UPPER(p.code) = UPPER($2) FOR UPDATE OF p`)).
+		WithArgs(uint64(1), "nope").
+		WillReturnRows(promotionRows())
+
+	tx, err := db.Begin()
+	require.NoError(t, err)
+	_, err = repo.GetPromotionByCodeForUpdateTx(context.Background(), tx, 1, "nope")
+	assert.ErrorIs(t, err, appErrors.ErrPromotionCodeInvalid)
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestRepository_CountRedemptionsTx(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	repo := &Repository{DB: db}
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT COUNT(*), COUNT(*) FILTER (WHERE LOWER(r.email) = LOWER($3))`)).
+		WithArgs(uint64(1), uint64(4), "ana@example.com").
+		WillReturnRows(sqlmock.NewRows([]string{"count", "count"}).AddRow(7, 2))
+
+	tx, err := db.Begin()
+	require.NoError(t, err)
+	total, byCustomer, err := repo.CountRedemptionsTx(context.Background(), tx, 1, 4, "ana@example.com")
+	require.NoError(t, err)
+	assert.Equal(t, 7, total)
+	assert.Equal(t, 2, byCustomer)
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestRepository_UpdateRedemptionDiscountTx(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	repo := &Repository{DB: db}
+
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta(`UPDATE promotion_redemptions SET discount_amount = $1 WHERE tenant_id = $2 AND id_order = $3`)).
+		WithArgs(money.Amount(0), uint64(1), uint64(9)).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	tx, err := db.Begin()
+	require.NoError(t, err)
+	require.NoError(t, repo.UpdateRedemptionDiscountTx(context.Background(), tx, 1, 9, 0))
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestRepository_DeletePromotion_NotFound(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	repo := &Repository{DB: db}
+
+	mock.ExpectExec(regexp.QuoteMeta(`DELETE FROM promotions WHERE id_promotion = $1 AND tenant_id = $2`)).
+		WithArgs(uint64(4), uint64(1)).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+
+	err = repo.DeletePromotion(context.Background(), 1, 4)
+	assert.ErrorIs(t, err, appErrors.ErrPromotionNotFound)
+	require.NoError(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,353 @@
+package standingorders
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/lib/pq"
+	"github.com/radamesvaz/bakery-app/internal/errors"
+	soModel "github.com/radamesvaz/bakery-app/model/standingorders"
+)
+
+// MaxListedOccurrences bounds ListOccurrences (the most recent delivery dates first).
+const MaxListedOccurrences = 100
+
+type Repository struct {
+	DB *sql.DB
+}
+
+const standingOrderColumns = `id_standing_order, tenant_id, name, email, phone, delivery_direction, note, weekdays, start_date, end_date, active, created_on, updated_on`
+
+func scanStandingOrder(row interface{ Scan(dest ...any) error }) (soModel.StandingOrder, error) {
+	var (
+		s         soModel.StandingOrder
+		weekdays  pq.Int64Array
+		startDate time.Time
+		endDate   sql.NullTime
+	)
+	err := row.Scan(&s.ID, &s.TenantID, &s.Name, &s.Email, &s.Phone, &s.DeliveryDirection, &s.Note,
+		&weekdays, &startDate, &endDate, &s.Active, &s.CreatedOn, &s.UpdatedOn)
+	if err != nil {
+		return soModel.StandingOrder{}, err
+	}
+	s.Weekdays = make([]int, len(weekdays))
+	for i, d := range weekdays {
+		s.Weekdays[i] = int(d)
+	}
+	s.StartDate = startDate.Format(soModel.DateLayout)
+	if endDate.Valid {
+		end := endDate.Time.Format(soModel.DateLayout)
+		s.EndDate = &end
+	}
+	s.Items = []soModel.StandingOrderItem{}
+	return s, nil
+}
+
+func weekdaysArray(weekdays []int) pq.Int64Array {
+	out := make(pq.Int64Array, len(weekdays))
+	for i, d := range weekdays {
+		out[i] = int64(d)
+	}
+	return out
+}
+
+func nullDate(t *time.Time) sql.NullTime {
+	if t == nil {
+		return sql.NullTime{}
+	}
+	return sql.NullTime{Time: *t, Valid: true}
+}
+
+// insertItemsTx stores the lines of a standing order. Products of other tenants are reported as not found.
+func insertItemsTx(ctx context.Context, tx *sql.Tx, tenantID, id uint64, items []soModel.StandingOrderItem) error {
+	for _, item := range items {
+		result, err := tx.ExecContext(ctx,
+			`INSERT INTO standing_order_items (id_standing_order, tenant_id, id_product, quantity)
+SELECT $1, $2, id_product, $4 FROM products WHERE id_product = $3 AND tenant_id = $2`,
+			id, tenantID, item.IdProduct, item.Quantity,
+		)
+		if err != nil {
+			return fmt.Errorf("insert standing order item: %w", err)
+		}
+		rows, err := result.RowsAffected()
+		if err != nil {
+			return fmt.Errorf("get rows affected: %w", err)
+		}
+		if rows == 0 {
+			return errors.NewNotFound(errors.ErrProductNotFound)
+		}
+	}
+	return nil
+}
+
+// attachItems loads the lines of the given standing orders.
+func (r *Repository) attachItems(ctx context.Context, tenantID uint64, orders []soModel.StandingOrder) error {
+	if len(orders) == 0 {
+		return nil
+	}
+	ids := make([]int64, len(orders))
+	byID := make(map[uint64]int, len(orders))
+	for i, o := range orders {
+		ids[i] = int64(o.ID)
+		byID[o.ID] = i
+	}
+	rows, err := r.DB.QueryContext(ctx,
+		`SELECT id_standing_order, id_product, quantity FROM standing_order_items
+WHERE tenant_id = $1 AND id_standing_order = ANY($2)
+ORDER BY id_standing_order, id_product`,
+		tenantID, pq.Int64Array(ids),
+	)
+	if err != nil {
+		return fmt.Errorf("list standing order items: %w", err)
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var (
+			id   uint64
+			item soModel.StandingOrderItem
+		)
+		if err := rows.Scan(&id, &item.IdProduct, &item.Quantity); err != nil {
+			return fmt.Errorf("scan standing order item: %w", err)
+		}
+		if i, ok := byID[id]; ok {
+			orders[i].Items = append(orders[i].Items, item)
+		}
+	}
+	return rows.Err()
+}
+
+// CreateStandingOrder stores a standing order and its lines.
+func (r *Repository) CreateStandingOrder(ctx context.Context, tenantID uint64, in soModel.StandingOrderInput) (soModel.StandingOrder, error) {
+	tx, err := r.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return soModel.StandingOrder{}, fmt.Errorf("begin tx: %w", err)
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	s, err := scanStandingOrder(tx.QueryRowContext(ctx,
+		`INSERT INTO standing_orders (tenant_id, name, email, phone, delivery_direction, note, weekdays, start_date, end_date, active)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
+RETURNING `+standingOrderColumns,
+		tenantID, in.Name, in.Email, in.Phone, in.DeliveryDirection, in.Note, weekdaysArray(in.Weekdays), in.StartDate, nullDate(in.EndDate), in.Active,
+	))
+	if err != nil {
+		return soModel.StandingOrder{}, fmt.Errorf("create standing order: %w", err)
+	}
+	if err := insertItemsTx(ctx, tx, tenantID, s.ID, in.Items); err != nil {
+		return soModel.StandingOrder{}, err
+	}
+	if err := tx.Commit(); err != nil {
+		return soModel.StandingOrder{}, fmt.Errorf("commit tx: %w", err)
+	}
+	s.Items = in.Items
+	return s, nil
+}
+
+// ListStandingOrders returns every standing order of the tenant, newest first.
+func (r *Repository) ListStandingOrders(ctx context.Context, tenantID uint64) ([]soModel.StandingOrder, error) {
+	return r.listStandingOrders(ctx, tenantID,
+		`SELECT `+standingOrderColumns+` FROM standing_orders WHERE tenant_id = $1 ORDER BY id_standing_order DESC`,
+		tenantID,
+	)
+}
+
+// ListDueStandingOrders returns the active standing orders of the tenant whose date range overlaps
+// [from, to] (both inclusive).
+func (r *Repository) ListDueStandingOrders(ctx context.Context, tenantID uint64, from, to time.Time) ([]soModel.StandingOrder, error) {
+	return r.listStandingOrders(ctx, tenantID,
+		`SELECT `+standingOrderColumns+` FROM standing_orders
+WHERE tenant_id = $1 AND active AND start_date <= $3 AND (end_date IS NULL OR end_date >= $2)
+ORDER BY id_standing_order`,
+		tenantID, from, to,
+	)
+}
+
+func (r *Repository) listStandingOrders(ctx context.Context, tenantID uint64, query string, args ...any) ([]soModel.StandingOrder, error) {
+	rows, err := r.DB.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("list standing orders: %w", err)
+	}
+	defer rows.Close()
+
+	out := []soModel.StandingOrder{}
+	for rows.Next() {
+		s, err := scanStandingOrder(rows)
+		if err != nil {
+			return nil, fmt.Errorf("scan standing order: %w", err)
+		}
+		out = append(out, s)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate standing orders: %w", err)
+	}
+	if err := r.attachItems(ctx, tenantID, out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// GetStandingOrder returns one standing order of the tenant with its lines.
+func (r *Repository) GetStandingOrder(ctx context.Context, tenantID, id uint64) (soModel.StandingOrder, error) {
+	s, err := scanStandingOrder(r.DB.QueryRowContext(ctx,
+		`SELECT `+standingOrderColumns+` FROM standing_orders WHERE id_standing_order = $1 AND tenant_id = $2`,
+		id, tenantID,
+	))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return soModel.StandingOrder{}, errors.NewNotFound(errors.ErrStandingOrderNotFound)
+		}
+		return soModel.StandingOrder{}, fmt.Errorf("get standing order: %w", err)
+	}
+	orders := []soModel.StandingOrder{s}
+	if err := r.attachItems(ctx, tenantID, orders); err != nil {
+		return soModel.StandingOrder{}, err
+	}
+	return orders[0], nil
+}
+
+// UpdateStandingOrder replaces a standing order and its lines. Orders already created are not changed.
+func (r *Repository) UpdateStandingOrder(ctx context.Context, tenantID, id uint64, in soModel.StandingOrderInput) (soModel.StandingOrder, error) {
+	tx, err := r.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return soModel.StandingOrder{}, fmt.Errorf("begin tx: %w", err)
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	s, err := scanStandingOrder(tx.QueryRowContext(ctx,
+		`UPDATE standing_orders
+SET name = $1, email = $2, phone = $3, delivery_direction = $4, note = $5, weekdays = $6, start_date = $7, end_date = $8, active = $9, updated_on = NOW()
+WHERE id_standing_order = $10 AND tenant_id = $11
+RETURNING `+standingOrderColumns,
+		in.Name, in.Email, in.Phone, in.DeliveryDirection, in.Note, weekdaysArray(in.Weekdays), in.StartDate, nullDate(in.EndDate), in.Active, id, tenantID,
+	))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return soModel.StandingOrder{}, errors.NewNotFound(errors.ErrStandingOrderNotFound)
+		}
+		return soModel.StandingOrder{}, fmt.Errorf("update standing order: %w", err)
+	}
+	if _, err := tx.ExecContext(ctx, `DELETE FROM standing_order_items WHERE id_standing_order = $1 AND tenant_id = $2`, id, tenantID); err != nil {
+		return soModel.StandingOrder{}, fmt.Errorf("delete standing order items: %w", err)
+	}
+	if err := insertItemsTx(ctx, tx, tenantID, id, in.Items); err != nil {
+		return soModel.StandingOrder{}, err
+	}
+	if err := tx.Commit(); err != nil {
+		return soModel.StandingOrder{}, fmt.Errorf("commit tx: %w", err)
+	}
+	s.Items = in.Items
+	return s, nil
+}
+
+// DeleteStandingOrder removes a standing order with its lines and occurrence log. Orders already
+// created are kept.
+func (r *Repository) DeleteStandingOrder(ctx context.Context, tenantID, id uint64) error {
+	result, err := r.DB.ExecContext(ctx, `DELETE FROM standing_orders WHERE id_standing_order = $1 AND tenant_id = $2`, id, tenantID)
+	if err != nil {
+		return fmt.Errorf("delete standing order: %w", err)
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("get rows affected: %w", err)
+	}
+	if rows == 0 {
+		return errors.NewNotFound(errors.ErrStandingOrderNotFound)
+	}
+	return nil
+}
+
+const occurrenceColumns = `id_standing_order, delivery_date, status, id_order, attempts, last_error, updated_on`
+
+func scanOccurrence(row interface{ Scan(dest ...any) error }) (soModel.Occurrence, error) {
+	var (
+		o            soModel.Occurrence
+		deliveryDate time.Time
+		idOrder      sql.NullInt64
+		lastError    sql.NullString
+	)
+	if err := row.Scan(&o.IDStandingOrder, &deliveryDate, &o.Status, &idOrder, &o.Attempts, &lastError, &o.UpdatedOn); err != nil {
+		return soModel.Occurrence{}, err
+	}
+	o.DeliveryDate = deliveryDate.Format(soModel.DateLayout)
+	if idOrder.Valid {
+		id := uint64(idOrder.Int64)
+		o.IDOrder = &id
+	}
+	if lastError.Valid {
+		o.LastError = &lastError.String
+	}
+	return o, nil
+}
+
+func (r *Repository) listOccurrences(ctx context.Context, query string, args ...any) ([]soModel.Occurrence, error) {
+	rows, err := r.DB.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("list standing order occurrences: %w", err)
+	}
+	defer rows.Close()
+
+	out := []soModel.Occurrence{}
+	for rows.Next() {
+		o, err := scanOccurrence(rows)
+		if err != nil {
+			return nil, fmt.Errorf("scan standing order occurrence: %w", err)
+		}
+		out = append(out, o)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate standing order occurrences: %w", err)
+	}
+	return out, nil
+}
+
+// ListOccurrences returns the latest MaxListedOccurrences delivery dates handled for a standing
+// order of the tenant, newest first.
+func (r *Repository) ListOccurrences(ctx context.Context, tenantID, id uint64) ([]soModel.Occurrence, error) {
+	if _, err := r.GetStandingOrder(ctx, tenantID, id); err != nil {
+		return nil, err
+	}
+	return r.listOccurrences(ctx,
+		`SELECT `+occurrenceColumns+` FROM standing_order_occurrences
+WHERE tenant_id = $1 AND id_standing_order = $2
+ORDER BY delivery_date DESC
+LIMIT $3`,
+		tenantID, id, MaxListedOccurrences,
+	)
+}
+
+// ListOccurrencesBetween returns the occurrences of the tenant's standing orders with a delivery
+// date in [from, to] (both inclusive).
+func (r *Repository) ListOccurrencesBetween(ctx context.Context, tenantID uint64, from, to time.Time) ([]soModel.Occurrence, error) {
+	return r.listOccurrences(ctx,
+		`SELECT `+occurrenceColumns+` FROM standing_order_occurrences
+WHERE tenant_id = $1 AND delivery_date BETWEEN $2 AND $3`,
+		tenantID, from, to,
+	)
+}
+
+// RecordOccurrence stores the outcome of a delivery date, counting one more attempt when the date
+// was already recorded.
+func (r *Repository) RecordOccurrence(ctx context.Context, tenantID, id uint64, deliveryDate time.Time, status soModel.OccurrenceStatus, orderID *uint64, lastError *string) error {
+	var idOrder sql.NullInt64
+	if orderID != nil {
+		idOrder = sql.NullInt64{Int64: int64(*orderID), Valid: true}
+	}
+	var errMsg sql.NullString
+	if lastError != nil {
+		errMsg = sql.NullString{String: *lastError, Valid: true}
+	}
+	_, err := r.DB.ExecContext(ctx,
+		`INSERT INTO standing_order_occurrences (id_standing_order, delivery_date, tenant_id, status, id_order, last_error)
+VALUES ($1, $2, $3, $4, $5, $6)
+ON CONFLICT (id_standing_order, delivery_date) DO UPDATE
+SET status = EXCLUDED.status, id_order = EXCLUDED.id_order, last_error = EXCLUDED.last_error,
+    attempts = standing_order_occurrences.attempts + 1, updated_on = NOW()`,
+		id, deliveryDate, tenantID, string(status), idOrder, errMsg,
+	)
+	if err != nil {
+		return fmt.Errorf("record standing order occurrence: %w", err)
+	}
+	return nil
+}
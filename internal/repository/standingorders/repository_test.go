@@ -0,0 +1,146 @@
+package standingorders
+
+import (
+	"context"
+	"errors"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/lib/pq"
+	appErrors "github.com/radamesvaz/bakery-app/internal/errors"
+	soModel "github.com/radamesvaz/bakery-app/model/standingorders"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var (
+	startDate = time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+	createdOn = time.Date(2026, 2, 20, 9, 0, 0, 0, time.UTC)
+)
+
+func standingOrderRows() *sqlmock.Rows {
+	return sqlmock.NewRows([]string{"id_standing_order", "tenant_id", "name", "email", "phone", "delivery_direction", "note",
+		"weekdays", "start_date", "end_date", "active", "created_on", "updated_on"})
+}
+
+func TestRepository_CreateStandingOrder(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	repo := &Repository{DB: db}
+	in := soModel.StandingOrderInput{
+		Name:      "Cafe Central",
+		Email:     "cafe@example.com",
+		Weekdays:  []int{1, 3, 5},
+		StartDate: startDate,
+		Active:    true,
+		Items:     []soModel.StandingOrderItem{{IdProduct: 3, Quantity: 2}},
+	}
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(regexp.QuoteMeta(`INSERT INTO standing_orders`)).
+		WithArgs(uint64(1), "Cafe Central", "cafe@example.com", "", "", "", pq.Int64Array{1, 3, 5}, startDate, nil, true).
+		WillReturnRows(standingOrderRows().
+			AddRow(9, 1, "Cafe Central", "cafe@example.com", "", "", "", "{1,3,5}", startDate, nil, true, createdOn, createdOn))
+	mock.ExpectExec(regexp.QuoteMeta(`INSERT INTO standing_order_items`)).
+		WithArgs(uint64(9), uint64(1), uint64(3), uint64(2)).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	s, err := repo.CreateStandingOrder(context.Background(), 1, in)
+	require.NoError(t, err)
+	assert.Equal(t, uint64(9), s.ID)
+	assert.Equal(t, []int{1, 3, 5}, s.Weekdays)
+	assert.Equal(t, "2026-03-01", s.StartDate)
+	assert.Nil(t, s.EndDate)
+	assert.Equal(t, in.Items, s.Items)
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestRepository_GetStandingOrder_LoadsItems(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	repo := &Repository{DB: db}
+	endDate := time.Date(2026, 6, 30, 0, 0, 0, 0, time.UTC)
+
+	mock.ExpectQuery(regexp.QuoteMeta(`FROM standing_orders WHERE id_standing_order = $1 AND tenant_id = $2`)).
+		WithArgs(uint64(9), uint64(1)).
+		WillReturnRows(standingOrderRows().
+			AddRow(9, 1, "Cafe Central", "cafe@example.com", "", "", "", "{2}", startDate, endDate, true, createdOn, createdOn))
+	mock.ExpectQuery(regexp.QuoteMeta(`FROM standing_order_items`)).
+		WithArgs(uint64(1), pq.Int64Array{9}).
+		WillReturnRows(sqlmock.NewRows([]string{"id_standing_order", "id_product", "quantity"}).
+			AddRow(9, 3, 2).
+			AddRow(9, 4, 1))
+
+	s, err := repo.GetStandingOrder(context.Background(), 1, 9)
+	require.NoError(t, err)
+	require.NotNil(t, s.EndDate)
+	assert.Equal(t, "2026-06-30", *s.EndDate)
+	assert.Equal(t, []soModel.StandingOrderItem{{IdProduct: 3, Quantity: 2}, {IdProduct: 4, Quantity: 1}}, s.Items)
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestRepository_DeleteStandingOrder_NotFound(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	repo := &Repository{DB: db}
+
+	mock.ExpectExec(regexp.QuoteMeta(`DELETE FROM standing_orders`)).
+		WithArgs(uint64(9), uint64(1)).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+
+	err = repo.DeleteStandingOrder(context.Background(), 1, 9)
+	assert.True(t, errors.Is(err, appErrors.ErrStandingOrderNotFound))
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestRepository_RecordOccurrence_CountsAttempts(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	repo := &Repository{DB: db}
+	deliveryDate := time.Date(2026, 3, 4, 0, 0, 0, 0, time.UTC)
+	msg := "not enough product stock"
+
+	mock.ExpectExec(regexp.QuoteMeta(`attempts = standing_order_occurrences.attempts + 1`)).
+		WithArgs(uint64(9), deliveryDate, uint64(1), "failed", nil, msg).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	err = repo.RecordOccurrence(context.Background(), 1, 9, deliveryDate, soModel.OccurrenceFailed, nil, &msg)
+	require.NoError(t, err)
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestRepository_ListOccurrencesBetween(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	repo := &Repository{DB: db}
+	from := time.Date(2026, 3, 2, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2026, 3, 8, 0, 0, 0, 0, time.UTC)
+
+	mock.ExpectQuery(regexp.QuoteMeta(`WHERE tenant_id = $1 AND delivery_date BETWEEN $2 AND $3`)).
+		WithArgs(uint64(1), from, to).
+		WillReturnRows(sqlmock.NewRows([]string{"id_standing_order", "delivery_date", "status", "id_order", "attempts", "last_error", "updated_on"}).
+			AddRow(9, from, "created", 42, 1, nil, createdOn))
+
+	occurrences, err := repo.ListOccurrencesBetween(context.Background(), 1, from, to)
+	require.NoError(t, err)
+	require.Len(t, occurrences, 1)
+	assert.Equal(t, "2026-03-02", occurrences[0].DeliveryDate)
+	assert.Equal(t, soModel.OccurrenceCreated, occurrences[0].Status)
+	require.NotNil(t, occurrences[0].IDOrder)
+	assert.Equal(t, uint64(42), *occurrences[0].IDOrder)
+	assert.Nil(t, occurrences[0].LastError)
+	require.NoError(t, mock.ExpectationsWereMet())
+}
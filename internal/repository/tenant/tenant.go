@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"time"
 
+	"github.com/radamesvaz/bakery-app/model/money"
 	tenantModel "github.com/radamesvaz/bakery-app/model/tenant"
 )
 
@@ -22,13 +23,15 @@ type BrandingColors struct {
 
 // TenantBranding is the full branding snapshot (logo + palette) for a tenant.
 // Logo pixel dimensions are not stored; layout is fixed in CSS and uploads are validated on the server.
-// TenantName maps tenants.name (JSON key tenant_name for clients).
+// TenantName maps tenants.name (JSON key tenant_name for clients). Currency is what storefronts
+// show prices in.
 type TenantBranding struct {
-	TenantName     string `json:"tenant_name"`
-	LogoURL        string `json:"logo_url"`
-	PrimaryColor   string `json:"primary_color"`
-	SecondaryColor string `json:"secondary_color"`
-	AccentColor    string `json:"accent_color"`
+	TenantName     string         `json:"tenant_name"`
+	LogoURL        string         `json:"logo_url"`
+	PrimaryColor   string         `json:"primary_color"`
+	SecondaryColor string         `json:"secondary_color"`
+	AccentColor    string         `json:"accent_color"`
+	Currency       money.Currency `json:"currency"`
 }
 
 type UpdateBrandingColorsRequest struct {
@@ -236,13 +239,14 @@ func (r *Repository) GetBranding(ctx context.Context, tenantID uint64) (TenantBr
 	var displayName string
 	var logoURL sql.NullString
 	var primaryColor, secondaryColor, accentColor sql.NullString
+	var currency money.Currency
 
 	err := r.DB.QueryRowContext(ctx,
-		`SELECT name, logo_url, primary_color, secondary_color, accent_color
+		`SELECT name, logo_url, primary_color, secondary_color, accent_color, currency
 		 FROM tenants
 		 WHERE id = $1`,
 		tenantID,
-	).Scan(&displayName, &logoURL, &primaryColor, &secondaryColor, &accentColor)
+	).Scan(&displayName, &logoURL, &primaryColor, &secondaryColor, &accentColor, &currency)
 	if err != nil {
 		if err == sql.ErrNoRows {
 			return TenantBranding{}, fmt.Errorf("tenant not found when reading branding: %d", tenantID)
@@ -256,6 +260,7 @@ func (r *Repository) GetBranding(ctx context.Context, tenantID uint64) (TenantBr
 		PrimaryColor:   nullStringToString(primaryColor),
 		SecondaryColor: nullStringToString(secondaryColor),
 		AccentColor:    nullStringToString(accentColor),
+		Currency:       currency,
 	}, nil
 }
 
@@ -292,6 +297,26 @@ func (r *Repository) UpdateTenantName(ctx context.Context, tenantID uint64, name
 	return nil
 }
 
+// UpdateTenantCurrency sets tenants.currency. Existing orders keep the currency they were placed in.
+func (r *Repository) UpdateTenantCurrency(ctx context.Context, tenantID uint64, currency money.Currency) error {
+	result, err := r.DB.ExecContext(ctx,
+		`UPDATE tenants SET currency = $1, updated_on = NOW() WHERE id = $2`,
+		currency.String(),
+		tenantID,
+	)
+	if err != nil {
+		return fmt.Errorf("updating tenant currency for %d: %w", tenantID, err)
+	}
+	n, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("reading rows affected for tenant currency update %d: %w", tenantID, err)
+	}
+	if n == 0 {
+		return fmt.Errorf("tenant not found when updating currency: %d", tenantID)
+	}
+	return nil
+}
+
 // GetBrandingColors returns the branding colors configured for a tenant.
 func (r *Repository) GetBrandingColors(ctx context.Context, tenantID uint64) (BrandingColors, error) {
 	branding, err := r.GetBranding(ctx, tenantID)
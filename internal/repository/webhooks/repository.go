@@ -0,0 +1,384 @@
+package webhooks
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/lib/pq"
+	"github.com/radamesvaz/bakery-app/internal/errors"
+	"github.com/radamesvaz/bakery-app/internal/logger"
+	"github.com/radamesvaz/bakery-app/internal/pagination"
+	whModel "github.com/radamesvaz/bakery-app/model/webhooks"
+)
+
+type Repository struct {
+	DB *sql.DB
+}
+
+// ListDeliveriesPageResult is one page of the delivery log (cursor pagination on id DESC).
+type ListDeliveriesPageResult struct {
+	Items      []whModel.Delivery
+	NextCursor *string
+}
+
+type execer interface {
+	ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error)
+}
+
+// execerFrom returns tx when non-nil so outbox rows commit together with the caller's changes.
+func (r *Repository) execerFrom(tx *sql.Tx) execer {
+	if tx != nil {
+		return tx
+	}
+	return r.DB
+}
+
+const subscriptionColumns = `id, tenant_id, url, event_types, active, created_on, updated_on`
+
+func scanSubscription(row interface{ Scan(dest ...any) error }) (whModel.Subscription, error) {
+	var s whModel.Subscription
+	var eventTypes pq.StringArray
+	if err := row.Scan(&s.ID, &s.TenantID, &s.URL, &eventTypes, &s.Active, &s.CreatedOn, &s.UpdatedOn); err != nil {
+		return whModel.Subscription{}, err
+	}
+	s.EventTypes = make([]whModel.EventType, len(eventTypes))
+	for i, t := range eventTypes {
+		s.EventTypes[i] = whModel.EventType(t)
+	}
+	return s, nil
+}
+
+func eventTypesArray(types []whModel.EventType) pq.StringArray {
+	out := make(pq.StringArray, len(types))
+	for i, t := range types {
+		out[i] = string(t)
+	}
+	return out
+}
+
+// CreateSubscription stores a new subscription for the tenant. The returned value includes the secret.
+func (r *Repository) CreateSubscription(ctx context.Context, tenantID uint64, url, secret string, eventTypes []whModel.EventType, active bool, createdByUserID uint64) (whModel.Subscription, error) {
+	q := `INSERT INTO webhook_subscriptions (tenant_id, url, secret, event_types, active, created_by_user_id)
+VALUES ($1, $2, $3, $4, $5, $6)
+RETURNING ` + subscriptionColumns
+	var createdBy sql.NullInt64
+	if createdByUserID != 0 {
+		createdBy = sql.NullInt64{Int64: int64(createdByUserID), Valid: true}
+	}
+	s, err := scanSubscription(r.DB.QueryRowContext(ctx, q, tenantID, url, secret, eventTypesArray(eventTypes), active, createdBy))
+	if err != nil {
+		logger.Err(err).Uint64("tenant_id", tenantID).Msg("Error creating webhook subscription")
+		return whModel.Subscription{}, fmt.Errorf("create webhook subscription: %w", err)
+	}
+	s.Secret = secret
+	return s, nil
+}
+
+// ListSubscriptions returns every subscription of the tenant (secrets omitted), newest first.
+func (r *Repository) ListSubscriptions(ctx context.Context, tenantID uint64) ([]whModel.Subscription, error) {
+	rows, err := r.DB.QueryContext(ctx,
+		`SELECT `+subscriptionColumns+` FROM webhook_subscriptions WHERE tenant_id = $1 ORDER BY id DESC`,
+		tenantID,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("list webhook subscriptions: %w", err)
+	}
+	defer rows.Close()
+
+	subs := []whModel.Subscription{}
+	for rows.Next() {
+		s, err := scanSubscription(rows)
+		if err != nil {
+			return nil, fmt.Errorf("scan webhook subscription: %w", err)
+		}
+		subs = append(subs, s)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate webhook subscriptions: %w", err)
+	}
+	return subs, nil
+}
+
+// GetSubscription returns a subscription of the tenant (secret omitted).
+func (r *Repository) GetSubscription(ctx context.Context, tenantID, id uint64) (whModel.Subscription, error) {
+	s, err := scanSubscription(r.DB.QueryRowContext(ctx,
+		`SELECT `+subscriptionColumns+` FROM webhook_subscriptions WHERE id = $1 AND tenant_id = $2`,
+		id, tenantID,
+	))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return whModel.Subscription{}, errors.NewNotFound(errors.ErrWebhookSubscriptionNotFound)
+		}
+		return whModel.Subscription{}, fmt.Errorf("get webhook subscription: %w", err)
+	}
+	return s, nil
+}
+
+// UpdateSubscription overwrites url, event types and active flag of a subscription of the tenant.
+func (r *Repository) UpdateSubscription(ctx context.Context, tenantID, id uint64, url string, eventTypes []whModel.EventType, active bool) (whModel.Subscription, error) {
+	q := `UPDATE webhook_subscriptions
+SET url = $1, event_types = $2, active = $3, updated_on = NOW()
+WHERE id = $4 AND tenant_id = $5
+RETURNING ` + subscriptionColumns
+	s, err := scanSubscription(r.DB.QueryRowContext(ctx, q, url, eventTypesArray(eventTypes), active, id, tenantID))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return whModel.Subscription{}, errors.NewNotFound(errors.ErrWebhookSubscriptionNotFound)
+		}
+		return whModel.Subscription{}, fmt.Errorf("update webhook subscription: %w", err)
+	}
+	return s, nil
+}
+
+// DeleteSubscription removes a subscription of the tenant together with its delivery log.
+func (r *Repository) DeleteSubscription(ctx context.Context, tenantID, id uint64) error {
+	result, err := r.DB.ExecContext(ctx, `DELETE FROM webhook_subscriptions WHERE id = $1 AND tenant_id = $2`, id, tenantID)
+	if err != nil {
+		return fmt.Errorf("delete webhook subscription: %w", err)
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("get rows affected: %w", err)
+	}
+	if rows == 0 {
+		return errors.NewNotFound(errors.ErrWebhookSubscriptionNotFound)
+	}
+	return nil
+}
+
+// ListDeliveriesPage returns up to limit deliveries of a subscription, newest first.
+// If afterID is non-nil, only rows with id < *afterID are considered (next page).
+func (r *Repository) ListDeliveriesPage(ctx context.Context, tenantID, subscriptionID uint64, limit int, afterID *uint64) (ListDeliveriesPageResult, error) {
+	if limit < 1 {
+		return ListDeliveriesPageResult{}, fmt.Errorf("limit must be at least 1")
+	}
+
+	q := `SELECT d.id, d.webhook_subscription_id, d.webhook_event_id, e.event_type, e.payload, d.status, d.attempts,
+	d.next_attempt_at, d.last_status_code, d.last_error, d.delivered_on, d.created_on
+FROM webhook_deliveries d
+JOIN webhook_outbox e ON e.id = d.webhook_event_id
+WHERE d.tenant_id = $1 AND d.webhook_subscription_id = $2`
+	args := []interface{}{tenantID, subscriptionID}
+	argPos := 3
+	if afterID != nil {
+		q += fmt.Sprintf(" AND d.id < $%d", argPos)
+		args = append(args, *afterID)
+		argPos++
+	}
+	q += fmt.Sprintf(" ORDER BY d.id DESC LIMIT $%d", argPos)
+	args = append(args, limit+1)
+
+	rows, err := r.DB.QueryContext(ctx, q, args...)
+	if err != nil {
+		return ListDeliveriesPageResult{}, fmt.Errorf("list webhook deliveries: %w", err)
+	}
+	defer rows.Close()
+
+	items := []whModel.Delivery{}
+	for rows.Next() {
+		var (
+			d              whModel.Delivery
+			payload        []byte
+			nextAttemptAt  sql.NullTime
+			lastStatusCode sql.NullInt64
+			lastError      sql.NullString
+			deliveredOn    sql.NullTime
+		)
+		if err := rows.Scan(
+			&d.ID, &d.SubscriptionID, &d.EventID, &d.EventType, &payload, &d.Status, &d.Attempts,
+			&nextAttemptAt, &lastStatusCode, &lastError, &deliveredOn, &d.CreatedOn,
+		); err != nil {
+			return ListDeliveriesPageResult{}, fmt.Errorf("scan webhook delivery: %w", err)
+		}
+		d.Payload = json.RawMessage(payload)
+		if nextAttemptAt.Valid && d.Status == whModel.DeliveryStatusPending {
+			d.NextAttemptAt = &nextAttemptAt.Time
+		}
+		if lastStatusCode.Valid {
+			code := int(lastStatusCode.Int64)
+			d.LastStatusCode = &code
+		}
+		if lastError.Valid {
+			d.LastError = &lastError.String
+		}
+		if deliveredOn.Valid {
+			d.DeliveredOn = &deliveredOn.Time
+		}
+		items = append(items, d)
+	}
+	if err := rows.Err(); err != nil {
+		return ListDeliveriesPageResult{}, fmt.Errorf("iterate webhook deliveries: %w", err)
+	}
+
+	hasNext := len(items) > limit
+	if hasNext {
+		items = items[:limit]
+	}
+
+	var next *string
+	if hasNext && len(items) > 0 {
+		enc, err := pagination.EncodeIDCursor(items[len(items)-1].ID)
+		if err != nil {
+			return ListDeliveriesPageResult{}, fmt.Errorf("encoding next cursor: %w", err)
+		}
+		next = &enc
+	}
+	return ListDeliveriesPageResult{Items: items, NextCursor: next}, nil
+}
+
+// EnqueueEventTx writes an event to the outbox. tx nil uses DB directly.
+func (r *Repository) EnqueueEventTx(ctx context.Context, tx *sql.Tx, tenantID uint64, eventType whModel.EventType, data any) error {
+	payload, err := json.Marshal(data)
+	if err != nil {
+		return fmt.Errorf("marshal webhook event %s: %w", eventType, err)
+	}
+	_, err = r.execerFrom(tx).ExecContext(ctx,
+		`INSERT INTO webhook_outbox (tenant_id, event_type, payload) VALUES ($1, $2, $3)`,
+		tenantID, string(eventType), payload,
+	)
+	if err != nil {
+		return fmt.Errorf("enqueue webhook event %s: %w", eventType, err)
+	}
+	return nil
+}
+
+// EnqueueOutOfStockTx writes a product.out_of_stock event for every given product that tracks
+// inventory and currently has no stock left (as seen by tx). tx nil uses DB directly.
+func (r *Repository) EnqueueOutOfStockTx(ctx context.Context, tx *sql.Tx, tenantID uint64, productIDs []uint64) error {
+	if len(productIDs) == 0 {
+		return nil
+	}
+	ids := make(pq.Int64Array, len(productIDs))
+	for i, id := range productIDs {
+		ids[i] = int64(id)
+	}
+	_, err := r.execerFrom(tx).ExecContext(ctx,
+		`INSERT INTO webhook_outbox (tenant_id, event_type, payload)
+SELECT tenant_id, $3, json_build_object('id_product', id_product, 'name', name, 'stock', stock)
+FROM products
+WHERE tenant_id = $1 AND id_product = ANY($2) AND track_inventory = TRUE AND stock = 0`,
+		tenantID, ids, string(whModel.EventProductOutOfStock),
+	)
+	if err != nil {
+		return fmt.Errorf("enqueue out of stock events: %w", err)
+	}
+	return nil
+}
+
+// FanOutEvents claims up to limit undispatched outbox events and creates one pending delivery per
+// matching active subscription in a single statement. Safe for concurrent dispatchers (SKIP LOCKED).
+// Returns the number of deliveries created.
+func (r *Repository) FanOutEvents(ctx context.Context, limit int) (int64, error) {
+	q := `WITH claimed AS (
+	UPDATE webhook_outbox SET dispatched_on = NOW()
+	WHERE id IN (
+		SELECT id FROM webhook_outbox
+		WHERE dispatched_on IS NULL
+		ORDER BY id
+		LIMIT $1
+		FOR UPDATE SKIP LOCKED
+	)
+	RETURNING id, tenant_id, event_type
+)
+INSERT INTO webhook_deliveries (tenant_id, webhook_subscription_id, webhook_event_id)
+SELECT c.tenant_id, s.id, c.id
+FROM claimed c
+JOIN webhook_subscriptions s
+	ON s.tenant_id = c.tenant_id AND s.active = TRUE AND c.event_type = ANY(s.event_types)
+ON CONFLICT (webhook_subscription_id, webhook_event_id) DO NOTHING`
+	result, err := r.DB.ExecContext(ctx, q, limit)
+	if err != nil {
+		return 0, fmt.Errorf("fan out webhook events: %w", err)
+	}
+	n, err := result.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("get rows affected: %w", err)
+	}
+	return n, nil
+}
+
+// ClaimDueDeliveries leases up to limit pending deliveries whose next attempt is due, pushing
+// next_attempt_at to leaseUntil so a crashed dispatcher's claims are retried after the lease.
+func (r *Repository) ClaimDueDeliveries(ctx context.Context, now, leaseUntil time.Time, limit int) ([]whModel.PendingDelivery, error) {
+	q := `UPDATE webhook_deliveries d
+SET next_attempt_at = $2, updated_on = NOW()
+FROM webhook_subscriptions s, webhook_outbox e
+WHERE d.id IN (
+	SELECT id FROM webhook_deliveries
+	WHERE status = 'pending' AND next_attempt_at <= $1
+	ORDER BY next_attempt_at, id
+	LIMIT $3
+	FOR UPDATE SKIP LOCKED
+)
+AND s.id = d.webhook_subscription_id AND s.active = TRUE
+AND e.id = d.webhook_event_id
+RETURNING d.id, d.tenant_id, d.webhook_subscription_id, s.url, s.secret, e.id, e.event_type, e.payload, e.created_on, d.attempts`
+	rows, err := r.DB.QueryContext(ctx, q, now, leaseUntil, limit)
+	if err != nil {
+		return nil, fmt.Errorf("claim due webhook deliveries: %w", err)
+	}
+	defer rows.Close()
+
+	var out []whModel.PendingDelivery
+	for rows.Next() {
+		var d whModel.PendingDelivery
+		var payload []byte
+		if err := rows.Scan(
+			&d.ID, &d.TenantID, &d.SubscriptionID, &d.URL, &d.Secret,
+			&d.EventID, &d.EventType, &payload, &d.EventCreatedOn, &d.Attempts,
+		); err != nil {
+			return nil, fmt.Errorf("scan claimed webhook delivery: %w", err)
+		}
+		d.Payload = json.RawMessage(payload)
+		out = append(out, d)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate claimed webhook deliveries: %w", err)
+	}
+	return out, nil
+}
+
+// MarkDeliverySucceeded records a 2xx response.
+func (r *Repository) MarkDeliverySucceeded(ctx context.Context, id uint64, statusCode int) error {
+	_, err := r.DB.ExecContext(ctx,
+		`UPDATE webhook_deliveries
+SET status = 'succeeded', attempts = attempts + 1, last_status_code = $1, last_error = NULL,
+	delivered_on = NOW(), updated_on = NOW()
+WHERE id = $2`,
+		statusCode, id,
+	)
+	if err != nil {
+		return fmt.Errorf("mark webhook delivery succeeded: %w", err)
+	}
+	return nil
+}
+
+// MarkDeliveryAttemptFailed records a failed attempt. When nextAttemptAt is nil the delivery
+// gives up (status failed); otherwise it stays pending until nextAttemptAt.
+func (r *Repository) MarkDeliveryAttemptFailed(ctx context.Context, id uint64, statusCode *int, lastError string, nextAttemptAt *time.Time) error {
+	var code sql.NullInt64
+	if statusCode != nil {
+		code = sql.NullInt64{Int64: int64(*statusCode), Valid: true}
+	}
+	status := whModel.DeliveryStatusPending
+	var next sql.NullTime
+	if nextAttemptAt != nil {
+		next = sql.NullTime{Time: *nextAttemptAt, Valid: true}
+	} else {
+		status = whModel.DeliveryStatusFailed
+	}
+	_, err := r.DB.ExecContext(ctx,
+		`UPDATE webhook_deliveries
+SET status = $1, attempts = attempts + 1, last_status_code = $2, last_error = $3,
+	next_attempt_at = COALESCE($4, next_attempt_at), updated_on = NOW()
+WHERE id = $5`,
+		string(status), code, lastError, next, id,
+	)
+	if err != nil {
+		return fmt.Errorf("mark webhook delivery attempt failed: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,72 @@
+package webhooks
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"regexp"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	appErrors "github.com/radamesvaz/bakery-app/internal/errors"
+	whModel "github.com/radamesvaz/bakery-app/model/webhooks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRepository_EnqueueEventTx(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	repo := &Repository{DB: db}
+
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta(`INSERT INTO webhook_outbox (tenant_id, event_type, payload) VALUES ($1, $2, $3)`)).
+		WithArgs(uint64(1), "order.created", []byte(`{"id_order":9,"status":"pending","price":12.50,"paid":false}`)).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectCommit()
+
+	tx, err := db.Begin()
+	require.NoError(t, err)
+	err = repo.EnqueueEventTx(context.Background(), tx, 1, whModel.EventOrderCreated, whModel.OrderEventData{
+		IDOrder: 9,
+		Status:  "pending",
+		Price:   1250,
+	})
+	require.NoError(t, err)
+	require.NoError(t, tx.Commit())
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestRepository_EnqueueOutOfStockTx_NoProductsIsNoop(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	repo := &Repository{DB: db}
+
+	err = repo.EnqueueOutOfStockTx(context.Background(), nil, 1, nil)
+	require.NoError(t, err)
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestRepository_GetSubscription_NotFound(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	repo := &Repository{DB: db}
+
+	mock.ExpectQuery(regexp.QuoteMeta(`FROM webhook_subscriptions WHERE id = $1 AND tenant_id = $2`)).
+		WithArgs(uint64(4), uint64(1)).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}))
+
+	_, err = repo.GetSubscription(context.Background(), 1, 4)
+
+	var httpErr *appErrors.HTTPError
+	require.True(t, errors.As(err, &httpErr))
+	assert.Equal(t, http.StatusNotFound, httpErr.StatusCode)
+	assert.ErrorIs(t, err, appErrors.ErrWebhookSubscriptionNotFound)
+	require.NoError(t, mock.ExpectationsWereMet())
+}
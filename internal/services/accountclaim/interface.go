@@ -0,0 +1,8 @@
+package accountclaim
+
+import "context"
+
+type Service interface {
+	RequestClaim(ctx context.Context, tenantID uint64, tenantSlug string, email string) error
+	CompleteClaim(ctx context.Context, tenantID uint64, token string, newPassword string) error
+}
@@ -0,0 +1,99 @@
+package accountclaim
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+
+	appErrors "github.com/radamesvaz/bakery-app/internal/errors"
+	"github.com/radamesvaz/bakery-app/internal/logger"
+	"github.com/radamesvaz/bakery-app/internal/repository/user"
+	authService "github.com/radamesvaz/bakery-app/internal/services/auth"
+	authActionTokens "github.com/radamesvaz/bakery-app/internal/services/auth_action_tokens"
+	"github.com/radamesvaz/bakery-app/internal/services/email"
+	authModel "github.com/radamesvaz/bakery-app/model/auth"
+	uModel "github.com/radamesvaz/bakery-app/model/users"
+)
+
+// AccountClaimService lets a customer created by its first order (a client user without a
+// password) set a password through an emailed one-time link, so it can log in and see its orders.
+type AccountClaimService struct {
+	Users        *user.UserRepository
+	AuthService  authService.Service
+	TokenService authActionTokens.Service
+	EmailSender  email.Sender
+	AppBaseURL   string
+}
+
+func (s *AccountClaimService) RequestClaim(ctx context.Context, tenantID uint64, tenantSlug string, emailAddr string) error {
+	emailAddr = strings.TrimSpace(emailAddr)
+	if emailAddr == "" {
+		return appErrors.NewBadRequest(appErrors.ErrCouldNotGetTheUser)
+	}
+
+	u, err := s.Users.GetUserByEmail(tenantID, emailAddr)
+	if err != nil {
+		// Neutral response policy: unknown emails do not produce an outward error.
+		if errors.Is(err, appErrors.ErrUserNotFound) {
+			return nil
+		}
+		return err
+	}
+	// Staff accounts and customers that already claimed theirs use the password reset flow.
+	if u.IDRole != uModel.UserRoleClient || u.Password != "" {
+		return nil
+	}
+
+	tokenResp, err := s.TokenService.CreateToken(ctx, authModel.CreateActionTokenRequest{
+		TenantID:      tenantID,
+		Email:         emailAddr,
+		Purpose:       authModel.ActionTokenPurposeAccountClaim,
+		SubjectUserID: &u.ID,
+	})
+	if err != nil {
+		return err
+	}
+
+	claimURL, err := buildClaimURL(s.AppBaseURL, tenantSlug, tokenResp.Token)
+	if err != nil {
+		return err
+	}
+	if s.EmailSender != nil {
+		sendErr := s.EmailSender.SendAccountClaim(ctx, email.AccountClaimPayload{
+			ToEmail:  emailAddr,
+			ClaimURL: claimURL,
+		})
+		if sendErr != nil {
+			// Keep the response neutral. Do not leak if send failed.
+			logger.Warn().Err(sendErr).Uint64("tenant_id", tenantID).Msg("account claim email delivery failed")
+		}
+	}
+	return nil
+}
+
+func (s *AccountClaimService) CompleteClaim(ctx context.Context, tenantID uint64, token string, newPassword string) error {
+	rec, err := s.TokenService.ConsumeToken(ctx, tenantID, authModel.ActionTokenPurposeAccountClaim, token)
+	if err != nil {
+		return err
+	}
+	if rec.SubjectUserID == nil || *rec.SubjectUserID == 0 {
+		return appErrors.ErrInvalidToken
+	}
+
+	passwordHash, err := s.AuthService.HashPassword(newPassword)
+	if err != nil {
+		return err
+	}
+	return s.Users.UpdatePasswordHash(ctx, tenantID, *rec.SubjectUserID, passwordHash)
+}
+
+func buildClaimURL(baseURL string, tenantSlug string, token string) (string, error) {
+	base := strings.TrimRight(strings.TrimSpace(baseURL), "/")
+	if base == "" {
+		return "", appErrors.NewInternalServerError(errors.New("APP_BASE_URL is required for account claim links"))
+	}
+	slug := strings.TrimSpace(tenantSlug)
+	return fmt.Sprintf("%s/t/%s/auth/account/claim?token=%s", base, url.PathEscape(slug), url.QueryEscape(strings.TrimSpace(token))), nil
+}
@@ -0,0 +1,153 @@
+package analytics
+
+import (
+	"context"
+	"math"
+	"time"
+
+	aModel "github.com/radamesvaz/bakery-app/model/analytics"
+	"github.com/radamesvaz/bakery-app/model/money"
+)
+
+const (
+	// MaxRangeDays caps the date range of one analytics request.
+	MaxRangeDays = 366
+	// DefaultTopProductsLimit and MaxTopProductsLimit bound GET /auth/analytics/top-products.
+	DefaultTopProductsLimit = 10
+	MaxTopProductsLimit     = 100
+
+	dateLayout = "2006-01-02"
+)
+
+// Repository defines the queries needed by the analytics service.
+type Repository interface {
+	GetRevenue(ctx context.Context, tenantID uint64, from, to time.Time, interval aModel.Interval) ([]aModel.RevenueRow, error)
+	GetTopProducts(ctx context.Context, tenantID uint64, from, to time.Time, sortBy aModel.TopProductsSort, limit int) ([]aModel.TopProduct, error)
+	GetCancellationCounts(ctx context.Context, tenantID uint64, from, to time.Time) (aModel.CancellationCounts, error)
+}
+
+// Service builds the sales reports of a tenant. Ranges are calendar days in UTC, from and to
+// inclusive, applied to the order creation time.
+type Service struct {
+	Repo Repository
+}
+
+func NewService(repo Repository) *Service {
+	return &Service{Repo: repo}
+}
+
+// endExclusive turns the inclusive to date into the exclusive upper bound of the queries.
+func endExclusive(to time.Time) time.Time {
+	return to.AddDate(0, 0, 1)
+}
+
+// GetRevenue returns the revenue summary and one bucket per interval in the range, zero-filled.
+func (s *Service) GetRevenue(ctx context.Context, tenantID uint64, from, to time.Time, interval aModel.Interval) (aModel.RevenueReport, error) {
+	rows, err := s.Repo.GetRevenue(ctx, tenantID, from, endExclusive(to), interval)
+	if err != nil {
+		return aModel.RevenueReport{}, err
+	}
+	byPeriod := make(map[string]aModel.RevenueRow, len(rows))
+	for _, row := range rows {
+		byPeriod[row.PeriodStart.Format(dateLayout)] = row
+	}
+
+	report := aModel.RevenueReport{
+		From:     from.Format(dateLayout),
+		To:       to.Format(dateLayout),
+		Interval: interval,
+		Buckets:  []aModel.RevenueBucket{},
+	}
+	var summary aModel.RevenueRow
+	for period := truncate(from, interval); !period.After(to); period = next(period, interval) {
+		key := period.Format(dateLayout)
+		row := byPeriod[key]
+		report.Buckets = append(report.Buckets, aModel.RevenueBucket{PeriodStart: key, RevenueTotals: totals(row)})
+		summary.PaidOrders += row.PaidOrders
+		summary.PaidRevenue += row.PaidRevenue
+		summary.UnpaidOrders += row.UnpaidOrders
+		summary.UnpaidRevenue += row.UnpaidRevenue
+	}
+	report.Summary = totals(summary)
+	return report, nil
+}
+
+func totals(row aModel.RevenueRow) aModel.RevenueTotals {
+	t := aModel.RevenueTotals{
+		Orders:        row.PaidOrders + row.UnpaidOrders,
+		Revenue:       row.PaidRevenue + row.UnpaidRevenue,
+		PaidOrders:    row.PaidOrders,
+		PaidRevenue:   row.PaidRevenue,
+		UnpaidOrders:  row.UnpaidOrders,
+		UnpaidRevenue: row.UnpaidRevenue,
+	}
+	if t.Orders > 0 {
+		t.AverageOrderValue = money.Amount(math.Round(float64(t.Revenue) / float64(t.Orders)))
+	}
+	return t
+}
+
+// truncate matches Postgres date_trunc: weeks start on Monday.
+func truncate(day time.Time, interval aModel.Interval) time.Time {
+	switch interval {
+	case aModel.IntervalWeek:
+		offset := (int(day.Weekday()) + 6) % 7
+		return day.AddDate(0, 0, -offset)
+	case aModel.IntervalMonth:
+		return time.Date(day.Year(), day.Month(), 1, 0, 0, 0, 0, day.Location())
+	default:
+		return day
+	}
+}
+
+func next(period time.Time, interval aModel.Interval) time.Time {
+	switch interval {
+	case aModel.IntervalWeek:
+		return period.AddDate(0, 0, 7)
+	case aModel.IntervalMonth:
+		return period.AddDate(0, 1, 0)
+	default:
+		return period.AddDate(0, 0, 1)
+	}
+}
+
+// GetTopProducts returns the best selling products of the range.
+func (s *Service) GetTopProducts(ctx context.Context, tenantID uint64, from, to time.Time, sortBy aModel.TopProductsSort, limit int) (aModel.TopProductsReport, error) {
+	items, err := s.Repo.GetTopProducts(ctx, tenantID, from, endExclusive(to), sortBy, limit)
+	if err != nil {
+		return aModel.TopProductsReport{}, err
+	}
+	return aModel.TopProductsReport{
+		From:  from.Format(dateLayout),
+		To:    to.Format(dateLayout),
+		Sort:  sortBy,
+		Items: items,
+	}, nil
+}
+
+// GetCancellations returns how many orders of the range were cancelled by admins, cancelled by
+// customers or expired unpaid, with their share of all orders.
+func (s *Service) GetCancellations(ctx context.Context, tenantID uint64, from, to time.Time) (aModel.CancellationReport, error) {
+	counts, err := s.Repo.GetCancellationCounts(ctx, tenantID, from, endExclusive(to))
+	if err != nil {
+		return aModel.CancellationReport{}, err
+	}
+	return aModel.CancellationReport{
+		From:                    from.Format(dateLayout),
+		To:                      to.Format(dateLayout),
+		TotalOrders:             counts.TotalOrders,
+		CancelledByAdmin:        counts.CancelledByAdmin,
+		CancelledByCustomer:     counts.CancelledByCustomer,
+		Expired:                 counts.Expired,
+		CancelledByAdminRate:    rate(counts.CancelledByAdmin, counts.TotalOrders),
+		CancelledByCustomerRate: rate(counts.CancelledByCustomer, counts.TotalOrders),
+		ExpiredRate:             rate(counts.Expired, counts.TotalOrders),
+	}, nil
+}
+
+func rate(part, total int) float64 {
+	if total == 0 {
+		return 0
+	}
+	return math.Round(float64(part)/float64(total)*10000) / 10000
+}
@@ -0,0 +1,133 @@
+package analytics
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	aModel "github.com/radamesvaz/bakery-app/model/analytics"
+	"github.com/radamesvaz/bakery-app/model/money"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+type MockRepository struct {
+	mock.Mock
+}
+
+func (m *MockRepository) GetRevenue(ctx context.Context, tenantID uint64, from, to time.Time, interval aModel.Interval) ([]aModel.RevenueRow, error) {
+	args := m.Called(ctx, tenantID, from, to, interval)
+	rows, _ := args.Get(0).([]aModel.RevenueRow)
+	return rows, args.Error(1)
+}
+
+func (m *MockRepository) GetTopProducts(ctx context.Context, tenantID uint64, from, to time.Time, sortBy aModel.TopProductsSort, limit int) ([]aModel.TopProduct, error) {
+	args := m.Called(ctx, tenantID, from, to, sortBy, limit)
+	items, _ := args.Get(0).([]aModel.TopProduct)
+	return items, args.Error(1)
+}
+
+func (m *MockRepository) GetCancellationCounts(ctx context.Context, tenantID uint64, from, to time.Time) (aModel.CancellationCounts, error) {
+	args := m.Called(ctx, tenantID, from, to)
+	return args.Get(0).(aModel.CancellationCounts), args.Error(1)
+}
+
+func day(y int, m time.Month, d int) time.Time {
+	return time.Date(y, m, d, 0, 0, 0, 0, time.UTC)
+}
+
+func TestService_GetRevenue_ZeroFillsDays(t *testing.T) {
+	repo := new(MockRepository)
+	svc := NewService(repo)
+	ctx := context.Background()
+
+	repo.On("GetRevenue", ctx, uint64(1), day(2026, 3, 1), day(2026, 3, 4), aModel.IntervalDay).Return([]aModel.RevenueRow{
+		{PeriodStart: day(2026, 3, 1), PaidOrders: 2, PaidRevenue: 3000, UnpaidOrders: 1, UnpaidRevenue: 1500},
+		{PeriodStart: day(2026, 3, 3), PaidOrders: 1, PaidRevenue: 1050},
+	}, nil)
+
+	report, err := svc.GetRevenue(ctx, 1, day(2026, 3, 1), day(2026, 3, 3), aModel.IntervalDay)
+	require.NoError(t, err)
+
+	require.Len(t, report.Buckets, 3)
+	assert.Equal(t, "2026-03-01", report.Buckets[0].PeriodStart)
+	assert.Equal(t, 3, report.Buckets[0].Orders)
+	assert.Equal(t, money.Amount(4500), report.Buckets[0].Revenue)
+	assert.Equal(t, money.Amount(1500), report.Buckets[0].AverageOrderValue)
+	assert.Equal(t, "2026-03-02", report.Buckets[1].PeriodStart)
+	assert.Equal(t, aModel.RevenueTotals{}, report.Buckets[1].RevenueTotals)
+	assert.Equal(t, "2026-03-03", report.Buckets[2].PeriodStart)
+
+	assert.Equal(t, 4, report.Summary.Orders)
+	assert.Equal(t, 3, report.Summary.PaidOrders)
+	assert.Equal(t, money.Amount(4050), report.Summary.PaidRevenue)
+	assert.Equal(t, money.Amount(1500), report.Summary.UnpaidRevenue)
+	assert.Equal(t, money.Amount(1388), report.Summary.AverageOrderValue)
+	repo.AssertExpectations(t)
+}
+
+func TestService_GetRevenue_WeeksStartOnMonday(t *testing.T) {
+	repo := new(MockRepository)
+	svc := NewService(repo)
+	ctx := context.Background()
+
+	// 2026-03-04 is a Wednesday; its week starts on Monday 2026-03-02.
+	repo.On("GetRevenue", ctx, uint64(1), day(2026, 3, 4), day(2026, 3, 17), aModel.IntervalWeek).Return([]aModel.RevenueRow{
+		{PeriodStart: day(2026, 3, 9), PaidOrders: 1, PaidRevenue: 2000},
+	}, nil)
+
+	report, err := svc.GetRevenue(ctx, 1, day(2026, 3, 4), day(2026, 3, 16), aModel.IntervalWeek)
+	require.NoError(t, err)
+
+	require.Len(t, report.Buckets, 3)
+	assert.Equal(t, "2026-03-02", report.Buckets[0].PeriodStart)
+	assert.Equal(t, "2026-03-09", report.Buckets[1].PeriodStart)
+	assert.Equal(t, money.Amount(2000), report.Buckets[1].Revenue)
+	assert.Equal(t, "2026-03-16", report.Buckets[2].PeriodStart)
+	repo.AssertExpectations(t)
+}
+
+func TestService_GetRevenue_RepoError(t *testing.T) {
+	repo := new(MockRepository)
+	svc := NewService(repo)
+	ctx := context.Background()
+
+	repo.On("GetRevenue", ctx, uint64(1), day(2026, 3, 1), day(2026, 4, 1), aModel.IntervalMonth).Return(nil, errors.New("db down"))
+
+	_, err := svc.GetRevenue(ctx, 1, day(2026, 3, 1), day(2026, 3, 31), aModel.IntervalMonth)
+	require.Error(t, err)
+	repo.AssertExpectations(t)
+}
+
+func TestService_GetCancellations_ComputesRates(t *testing.T) {
+	repo := new(MockRepository)
+	svc := NewService(repo)
+	ctx := context.Background()
+
+	repo.On("GetCancellationCounts", ctx, uint64(1), day(2026, 3, 1), day(2026, 4, 1)).Return(aModel.CancellationCounts{
+		TotalOrders: 3, CancelledByAdmin: 1, Expired: 1,
+	}, nil)
+
+	report, err := svc.GetCancellations(ctx, 1, day(2026, 3, 1), day(2026, 3, 31))
+	require.NoError(t, err)
+
+	assert.Equal(t, 0.3333, report.CancelledByAdminRate)
+	assert.Equal(t, 0.0, report.CancelledByCustomerRate)
+	assert.Equal(t, 0.3333, report.ExpiredRate)
+	repo.AssertExpectations(t)
+}
+
+func TestService_GetCancellations_NoOrders(t *testing.T) {
+	repo := new(MockRepository)
+	svc := NewService(repo)
+	ctx := context.Background()
+
+	repo.On("GetCancellationCounts", ctx, uint64(1), day(2026, 3, 1), day(2026, 3, 2)).Return(aModel.CancellationCounts{}, nil)
+
+	report, err := svc.GetCancellations(ctx, 1, day(2026, 3, 1), day(2026, 3, 1))
+	require.NoError(t, err)
+	assert.Equal(t, 0.0, report.ExpiredRate)
+	repo.AssertExpectations(t)
+}
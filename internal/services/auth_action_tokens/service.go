@@ -17,6 +17,7 @@ import (
 const (
 	DefaultInviteTTLMinutes        = 60 * 24 * 7
 	DefaultPasswordResetTTLMinutes = 60
+	DefaultAccountClaimTTLMinutes  = 60 * 24
 )
 
 type ActionTokenService struct {
@@ -76,6 +77,9 @@ func (s *ActionTokenService) CreateToken(
 	case authModel.ActionTokenPurposePasswordReset:
 		hist.Action = authModel.ActionTokenPasswordResetIssued
 		hist.SubjectUserID = req.SubjectUserID
+	case authModel.ActionTokenPurposeAccountClaim:
+		hist.Action = authModel.ActionTokenAccountClaimIssued
+		hist.SubjectUserID = req.SubjectUserID
 	}
 	if err := s.Repo.InsertHistory(ctx, tx, hist); err != nil {
 		return authModel.CreateActionTokenResponse{}, err
@@ -110,12 +114,12 @@ func (s *ActionTokenService) ConsumeToken(ctx context.Context, tenantID uint64,
 	if err := s.Repo.ConsumeToken(ctx, tx, rec.ID); err != nil {
 		return authModel.ActionTokenRecord{}, err
 	}
-	if normalizePurpose(purpose) == authModel.ActionTokenPurposePasswordReset {
+	if completed, ok := completedActionByPurpose(normalizePurpose(purpose)); ok {
 		if err := s.Repo.InsertHistory(ctx, tx, repo.InsertHistoryInput{
 			TenantID:           rec.TenantID,
 			AuthActionTokenID:  rec.ID,
-			Purpose:            authModel.ActionTokenPurposePasswordReset,
-			Action:             authModel.ActionTokenPasswordResetCompleted,
+			Purpose:            normalizePurpose(purpose),
+			Action:             completed,
 			SubjectUserID:      rec.SubjectUserID,
 			ModifiedByUserID:   nil,
 			MetadataJSON:       nil,
@@ -318,7 +322,21 @@ func normalizePurpose(p authModel.ActionTokenPurpose) authModel.ActionTokenPurpo
 }
 
 func isAllowedPurpose(p authModel.ActionTokenPurpose) bool {
-	return p == authModel.ActionTokenPurposeInvite || p == authModel.ActionTokenPurposePasswordReset
+	return p == authModel.ActionTokenPurposeInvite || p == authModel.ActionTokenPurposePasswordReset ||
+		p == authModel.ActionTokenPurposeAccountClaim
+}
+
+// completedActionByPurpose is the history action recorded when a token is consumed. Invites record
+// theirs with RecordInvitationAccepted once the user exists.
+func completedActionByPurpose(p authModel.ActionTokenPurpose) (authModel.ActionTokenHistoryAction, bool) {
+	switch p {
+	case authModel.ActionTokenPurposePasswordReset:
+		return authModel.ActionTokenPasswordResetCompleted, true
+	case authModel.ActionTokenPurposeAccountClaim:
+		return authModel.ActionTokenAccountClaimCompleted, true
+	default:
+		return "", false
+	}
 }
 
 func defaultTTLByPurpose(p authModel.ActionTokenPurpose) int {
@@ -327,6 +345,8 @@ func defaultTTLByPurpose(p authModel.ActionTokenPurpose) int {
 		return DefaultInviteTTLMinutes
 	case authModel.ActionTokenPurposePasswordReset:
 		return DefaultPasswordResetTTLMinutes
+	case authModel.ActionTokenPurposeAccountClaim:
+		return DefaultAccountClaimTTLMinutes
 	default:
 		return DefaultPasswordResetTTLMinutes
 	}
@@ -8,6 +8,7 @@ import (
 	"github.com/DATA-DOG/go-sqlmock"
 	appErrors "github.com/radamesvaz/bakery-app/internal/errors"
 	repo "github.com/radamesvaz/bakery-app/internal/repository/auth_action_tokens"
+	authService "github.com/radamesvaz/bakery-app/internal/services/auth"
 	authModel "github.com/radamesvaz/bakery-app/model/auth"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -87,3 +88,36 @@ func TestActionTokenService_GetTokenByIDScoped_TenantMismatch_ReturnsInvalidToke
 	assert.ErrorIs(t, err, appErrors.ErrInvalidToken)
 	assert.NoError(t, mock.ExpectationsWereMet())
 }
+
+func TestActionTokenService_ConsumeToken_AccountClaimRecordsCompletion(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	auth := authService.New("secret", 60)
+	svc := &ActionTokenService{
+		DB:          db,
+		Repo:        &repo.SQLRepository{DB: db},
+		AuthService: auth,
+	}
+	subjectUserID := uint64(31)
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(`FROM auth_action_tokens\s+WHERE tenant_id = \$1 AND purpose = \$2 AND token_hash = \$3\s+FOR UPDATE`).
+		WithArgs(uint64(5), string(authModel.ActionTokenPurposeAccountClaim), auth.HashOneTimeToken("plain-token")).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "tenant_id", "email", "purpose", "subject_user_id", "metadata_json", "expires_at", "used_at", "revoked_at"}).
+			AddRow(uint64(90), uint64(5), "client@example.com", "account_claim", subjectUserID, nil, time.Now().UTC().Add(time.Hour), nil, nil))
+	mock.ExpectExec(`UPDATE auth_action_tokens SET used_at = NOW\(\), updated_on = NOW\(\) WHERE id = \$1`).
+		WithArgs(uint64(90)).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(`INSERT INTO auth_action_tokens_history`).
+		WithArgs(uint64(5), uint64(90), string(authModel.ActionTokenPurposeAccountClaim), string(authModel.ActionTokenAccountClaimCompleted), nil, subjectUserID, nil).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	rec, err := svc.ConsumeToken(context.Background(), 5, authModel.ActionTokenPurposeAccountClaim, "plain-token")
+	require.NoError(t, err)
+	require.NotNil(t, rec.SubjectUserID)
+	assert.Equal(t, subjectUserID, *rec.SubjectUserID)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -5,6 +5,7 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"html"
 	"io"
 	"net/http"
 	"strings"
@@ -49,6 +50,26 @@ func (s *BrevoSender) SendPasswordReset(ctx context.Context, payload PasswordRes
 	return s.send(ctx, reqBody)
 }
 
+func (s *BrevoSender) SendAccountClaim(ctx context.Context, payload AccountClaimPayload) error {
+	reqBody := map[string]interface{}{
+		"sender": map[string]string{
+			"email": s.FromEmail,
+			"name":  s.FromName,
+		},
+		"to": []map[string]string{
+			{"email": payload.ToEmail},
+		},
+		"subject": "Set up your account",
+		"htmlContent": fmt.Sprintf(
+			"<p>Choose a password to see your orders.</p><p><a href=\"%s\">Set up your account</a></p><p>If you did not request this, ignore this email.</p>",
+			payload.ClaimURL,
+		),
+		"textContent": "Choose a password to see your orders. Open this link: " + payload.ClaimURL,
+	}
+
+	return s.send(ctx, reqBody)
+}
+
 func (s *BrevoSender) SendTenantInvitation(ctx context.Context, payload TenantInvitationPayload) error {
 	reqBody := map[string]interface{}{
 		"sender": map[string]string{
@@ -102,6 +123,87 @@ func (s *BrevoSender) SendTenantSignupCode(ctx context.Context, payload TenantSi
 	return s.send(ctx, reqBody)
 }
 
+func (s *BrevoSender) SendOrderConfirmation(ctx context.Context, payload OrderEmailPayload) error {
+	intro := fmt.Sprintf("Thank you for your order at %s. We received it and will let you know when it is ready.", payload.TenantName)
+	return s.sendOrderEmail(ctx, payload, fmt.Sprintf("Order #%d received", payload.OrderID), intro)
+}
+
+func (s *BrevoSender) SendOrderStatusUpdate(ctx context.Context, payload OrderEmailPayload) error {
+	var subject, intro string
+	switch payload.Status {
+	case "ready":
+		subject = fmt.Sprintf("Order #%d is ready", payload.OrderID)
+		intro = "Good news: your order is ready."
+	case "delivered":
+		subject = fmt.Sprintf("Order #%d was delivered", payload.OrderID)
+		intro = "Your order was delivered. Enjoy!"
+	case "cancelled":
+		subject = fmt.Sprintf("Order #%d was cancelled", payload.OrderID)
+		intro = "Your order was cancelled."
+		if reason := strings.TrimSpace(payload.CancellationReason); reason != "" {
+			intro += " Reason: " + reason
+		}
+	default:
+		subject = fmt.Sprintf("Order #%d update", payload.OrderID)
+		intro = "Your order is now " + payload.Status + "."
+	}
+	return s.sendOrderEmail(ctx, payload, subject, intro)
+}
+
+func (s *BrevoSender) SendOrderExpired(ctx context.Context, payload OrderEmailPayload) error {
+	intro := "Your order expired before it was confirmed, so it was cancelled. You can place a new order at any time."
+	return s.sendOrderEmail(ctx, payload, fmt.Sprintf("Order #%d expired", payload.OrderID), intro)
+}
+
+func (s *BrevoSender) SendNewOrderAlert(ctx context.Context, payload OrderEmailPayload) error {
+	customer := strings.TrimSpace(payload.CustomerName)
+	if customer == "" {
+		customer = "A customer"
+	}
+	intro := fmt.Sprintf("%s placed a new order.", customer)
+	return s.sendOrderEmail(ctx, payload, fmt.Sprintf("New order #%d", payload.OrderID), intro)
+}
+
+// sendOrderEmail renders the shared order summary (items, total, delivery date) below intro.
+func (s *BrevoSender) sendOrderEmail(ctx context.Context, payload OrderEmailPayload, subject, intro string) error {
+	var itemsHTML, itemsText strings.Builder
+	for _, item := range payload.Items {
+		fmt.Fprintf(&itemsHTML, "<li>%d x %s (%s %s)</li>", item.Quantity, html.EscapeString(item.Name), payload.Currency, item.UnitPrice)
+		fmt.Fprintf(&itemsText, "- %d x %s (%s %s)\n", item.Quantity, item.Name, payload.Currency, item.UnitPrice)
+	}
+
+	reqBody := map[string]interface{}{
+		"sender": map[string]string{
+			"email": s.FromEmail,
+			"name":  s.FromName,
+		},
+		"to": []map[string]string{
+			{"email": payload.ToEmail},
+		},
+		"subject": subject,
+		"htmlContent": fmt.Sprintf(
+			"<p>%s</p><p>Order #%d</p><ul>%s</ul><p>Total: %s %s</p><p>Delivery date: %s</p>",
+			html.EscapeString(intro),
+			payload.OrderID,
+			itemsHTML.String(),
+			payload.Currency,
+			payload.TotalPrice,
+			html.EscapeString(payload.DeliveryDate),
+		),
+		"textContent": fmt.Sprintf(
+			"%s\nOrder #%d\n%sTotal: %s %s\nDelivery date: %s\n",
+			intro,
+			payload.OrderID,
+			itemsText.String(),
+			payload.Currency,
+			payload.TotalPrice,
+			payload.DeliveryDate,
+		),
+	}
+
+	return s.send(ctx, reqBody)
+}
+
 func (s *BrevoSender) send(ctx context.Context, reqBody map[string]interface{}) error {
 	raw, err := json.Marshal(reqBody)
 	if err != nil {
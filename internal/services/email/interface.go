@@ -1,12 +1,22 @@
 package email
 
-import "context"
+import (
+	"context"
+
+	"github.com/radamesvaz/bakery-app/model/money"
+)
 
 type PasswordResetPayload struct {
 	ToEmail  string
 	ResetURL string
 }
 
+// AccountClaimPayload is the link a customer created by an order follows to set its password.
+type AccountClaimPayload struct {
+	ToEmail  string
+	ClaimURL string
+}
+
 type TenantInvitationPayload struct {
 	ToEmail   string
 	InviteURL string
@@ -18,8 +28,34 @@ type TenantSignupCodePayload struct {
 	ExpiresAt   string
 }
 
+type OrderEmailItem struct {
+	Name      string
+	Quantity  uint64
+	UnitPrice money.Amount
+}
+
+// OrderEmailPayload carries the order details rendered in order notifications.
+// CancellationReason is only set on cancellation notices.
+type OrderEmailPayload struct {
+	ToEmail            string
+	TenantName         string
+	CustomerName       string
+	OrderID            uint64
+	Status             string
+	TotalPrice         money.Amount
+	Currency           money.Currency
+	DeliveryDate       string
+	Items              []OrderEmailItem
+	CancellationReason string
+}
+
 type Sender interface {
 	SendPasswordReset(ctx context.Context, payload PasswordResetPayload) error
+	SendAccountClaim(ctx context.Context, payload AccountClaimPayload) error
 	SendTenantInvitation(ctx context.Context, payload TenantInvitationPayload) error
 	SendTenantSignupCode(ctx context.Context, payload TenantSignupCodePayload) error
+	SendOrderConfirmation(ctx context.Context, payload OrderEmailPayload) error
+	SendOrderStatusUpdate(ctx context.Context, payload OrderEmailPayload) error
+	SendOrderExpired(ctx context.Context, payload OrderEmailPayload) error
+	SendNewOrderAlert(ctx context.Context, payload OrderEmailPayload) error
 }
@@ -8,6 +8,10 @@ func (NoopSender) SendPasswordReset(_ context.Context, _ PasswordResetPayload) e
 	return nil
 }
 
+func (NoopSender) SendAccountClaim(_ context.Context, _ AccountClaimPayload) error {
+	return nil
+}
+
 func (NoopSender) SendTenantInvitation(_ context.Context, _ TenantInvitationPayload) error {
 	return nil
 }
@@ -15,3 +19,19 @@ func (NoopSender) SendTenantInvitation(_ context.Context, _ TenantInvitationPayl
 func (NoopSender) SendTenantSignupCode(_ context.Context, _ TenantSignupCodePayload) error {
 	return nil
 }
+
+func (NoopSender) SendOrderConfirmation(_ context.Context, _ OrderEmailPayload) error {
+	return nil
+}
+
+func (NoopSender) SendOrderStatusUpdate(_ context.Context, _ OrderEmailPayload) error {
+	return nil
+}
+
+func (NoopSender) SendOrderExpired(_ context.Context, _ OrderEmailPayload) error {
+	return nil
+}
+
+func (NoopSender) SendNewOrderAlert(_ context.Context, _ OrderEmailPayload) error {
+	return nil
+}
@@ -0,0 +1,181 @@
+package notifications
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/radamesvaz/bakery-app/internal/logger"
+	"github.com/radamesvaz/bakery-app/internal/services/email"
+	"github.com/radamesvaz/bakery-app/internal/services/retryqueue"
+	nModel "github.com/radamesvaz/bakery-app/model/notifications"
+)
+
+const (
+	DefaultMaxAttempts = 5
+	DefaultBatchSize   = 50
+	DefaultBaseBackoff = time.Minute
+	DefaultMaxBackoff  = 6 * time.Hour
+
+	// sendTimeout is the longest one email can take (the Brevo client's timeout); the batch lease
+	// allows it for every claimed email.
+	sendTimeout = 30 * time.Second
+)
+
+// Repository defines the persistence needed by the worker.
+type Repository interface {
+	ClaimDueEmails(ctx context.Context, now, leaseUntil time.Time, limit int) ([]nModel.PendingEmail, error)
+	GetOrderEmailData(ctx context.Context, tenantID, orderID uint64) (nModel.OrderEmailData, error)
+	MarkEmailSent(ctx context.Context, id uint64) error
+	MarkEmailAttemptFailed(ctx context.Context, id uint64, lastError string, nextAttemptAt *time.Time) error
+}
+
+// Worker sends queued order emails with retries and exponential backoff.
+type Worker struct {
+	retryqueue.Policy
+	Repo      Repository
+	Sender    email.Sender
+	BatchSize int
+	Now       func() time.Time
+}
+
+func NewWorker(repo Repository, sender email.Sender, maxAttempts int) *Worker {
+	if maxAttempts <= 0 {
+		maxAttempts = DefaultMaxAttempts
+	}
+	return &Worker{
+		Policy: retryqueue.Policy{
+			MaxAttempts: maxAttempts,
+			BaseBackoff: DefaultBaseBackoff,
+			MaxBackoff:  DefaultMaxBackoff,
+		},
+		Repo:      repo,
+		Sender:    sender,
+		BatchSize: DefaultBatchSize,
+		Now:       time.Now,
+	}
+}
+
+// SendResult summarizes one worker run.
+type SendResult struct {
+	Sent     int
+	Retrying int
+	Failed   int
+}
+
+// SendPending sends every due email once.
+func (w *Worker) SendPending(ctx context.Context) (SendResult, error) {
+	var result SendResult
+
+	now := w.Now()
+	lease := retryqueue.NewLease(now, w.BatchSize, sendTimeout)
+	emails, err := w.Repo.ClaimDueEmails(ctx, now, lease.Until, w.BatchSize)
+	if err != nil {
+		return result, err
+	}
+
+	for _, pending := range emails {
+		if lease.Expired(w.Now()) {
+			break
+		}
+		sendErr := w.send(ctx, pending)
+		if sendErr == nil {
+			if err := w.Repo.MarkEmailSent(ctx, pending.ID); err != nil {
+				return result, err
+			}
+			result.Sent++
+			continue
+		}
+
+		attempt := pending.Attempts + 1
+		nextAttemptAt := w.NextAttempt(w.Now(), attempt)
+		if nextAttemptAt != nil {
+			result.Retrying++
+		} else {
+			result.Failed++
+		}
+
+		logger.Warn().Err(sendErr).
+			Uint64("tenant_id", pending.TenantID).
+			Uint64("email_id", pending.ID).
+			Uint64("order_id", pending.IDOrder).
+			Str("kind", string(pending.Kind)).
+			Int("attempt", attempt).
+			Bool("gave_up", nextAttemptAt == nil).
+			Msg("Order email failed")
+
+		if err := w.Repo.MarkEmailAttemptFailed(ctx, pending.ID, retryqueue.Truncate(sendErr.Error(), retryqueue.MaxStoredErrorLength), nextAttemptAt); err != nil {
+			return result, err
+		}
+	}
+
+	return result, nil
+}
+
+// send renders the email from the current order state, so a status notice sent late still
+// shows what the customer would see now.
+func (w *Worker) send(ctx context.Context, pending nModel.PendingEmail) error {
+	data, err := w.Repo.GetOrderEmailData(ctx, pending.TenantID, pending.IDOrder)
+	if err != nil {
+		return fmt.Errorf("load order: %w", err)
+	}
+
+	payload := email.OrderEmailPayload{
+		ToEmail:      pending.ToEmail,
+		TenantName:   data.TenantName,
+		CustomerName: data.CustomerName,
+		OrderID:      data.IDOrder,
+		Status:       data.Status,
+		TotalPrice:   data.TotalPrice,
+		Currency:     data.Currency,
+		DeliveryDate: data.DeliveryDate.Format("2006-01-02"),
+	}
+	for _, item := range data.Items {
+		payload.Items = append(payload.Items, email.OrderEmailItem{
+			Name:      item.Name,
+			Quantity:  item.Quantity,
+			UnitPrice: item.UnitPrice,
+		})
+	}
+
+	switch pending.Kind {
+	case nModel.KindOrderConfirmation:
+		return w.Sender.SendOrderConfirmation(ctx, payload)
+	case nModel.KindOrderReady:
+		payload.Status = "ready"
+		return w.Sender.SendOrderStatusUpdate(ctx, payload)
+	case nModel.KindOrderDelivered:
+		payload.Status = "delivered"
+		return w.Sender.SendOrderStatusUpdate(ctx, payload)
+	case nModel.KindOrderCancelled:
+		payload.Status = "cancelled"
+		if data.CancellationReason != nil {
+			payload.CancellationReason = *data.CancellationReason
+		}
+		return w.Sender.SendOrderStatusUpdate(ctx, payload)
+	case nModel.KindOrderExpired:
+		return w.Sender.SendOrderExpired(ctx, payload)
+	case nModel.KindAdminNewOrder:
+		return w.Sender.SendNewOrderAlert(ctx, payload)
+	default:
+		return fmt.Errorf("unknown notification kind %q", pending.Kind)
+	}
+}
+
+// RunWorker runs SendPending every intervalSeconds until ctx is cancelled.
+func RunWorker(ctx context.Context, w *Worker, intervalSeconds int) {
+	retryqueue.Run(ctx, "Order email worker", intervalSeconds, w.MaxAttempts, func(ctx context.Context) error {
+		result, err := w.SendPending(ctx)
+		if err != nil {
+			return err
+		}
+		if result.Sent > 0 || result.Retrying > 0 || result.Failed > 0 {
+			logger.Info().
+				Int("sent", result.Sent).
+				Int("retrying", result.Retrying).
+				Int("failed", result.Failed).
+				Msg("Order email worker: run finished")
+		}
+		return nil
+	})
+}
@@ -0,0 +1,129 @@
+package notifications
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/radamesvaz/bakery-app/internal/services/email"
+	"github.com/radamesvaz/bakery-app/model/money"
+	nModel "github.com/radamesvaz/bakery-app/model/notifications"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+type MockRepository struct {
+	mock.Mock
+}
+
+func (m *MockRepository) ClaimDueEmails(ctx context.Context, now, leaseUntil time.Time, limit int) ([]nModel.PendingEmail, error) {
+	args := m.Called(ctx, now, leaseUntil, limit)
+	return args.Get(0).([]nModel.PendingEmail), args.Error(1)
+}
+
+func (m *MockRepository) GetOrderEmailData(ctx context.Context, tenantID, orderID uint64) (nModel.OrderEmailData, error) {
+	args := m.Called(ctx, tenantID, orderID)
+	return args.Get(0).(nModel.OrderEmailData), args.Error(1)
+}
+
+func (m *MockRepository) MarkEmailSent(ctx context.Context, id uint64) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+func (m *MockRepository) MarkEmailAttemptFailed(ctx context.Context, id uint64, lastError string, nextAttemptAt *time.Time) error {
+	args := m.Called(ctx, id, lastError, nextAttemptAt)
+	return args.Error(0)
+}
+
+type recordingSender struct {
+	email.NoopSender
+	statusUpdates []email.OrderEmailPayload
+	err           error
+}
+
+func (s *recordingSender) SendOrderStatusUpdate(_ context.Context, payload email.OrderEmailPayload) error {
+	s.statusUpdates = append(s.statusUpdates, payload)
+	return s.err
+}
+
+var fixedNow = time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+
+func newTestWorker(repo Repository, sender email.Sender) *Worker {
+	w := NewWorker(repo, sender, 3)
+	w.Now = func() time.Time { return fixedNow }
+	return w
+}
+
+func cancelledOrderData() nModel.OrderEmailData {
+	reason := "Out of flour"
+	return nModel.OrderEmailData{
+		TenantName:         "Bakery",
+		CustomerName:       "Ana",
+		IDOrder:            9,
+		Status:             "cancelled",
+		TotalPrice:         1250,
+		Currency:           "EUR",
+		DeliveryDate:       time.Date(2026, 3, 5, 0, 0, 0, 0, time.UTC),
+		CancellationReason: &reason,
+		Items:              []nModel.OrderEmailItem{{Name: "Bread", Quantity: 2, UnitPrice: 625}},
+	}
+}
+
+func TestWorker_SendPending_SendsCancellationWithReason(t *testing.T) {
+	repo := new(MockRepository)
+	sender := &recordingSender{}
+	repo.On("ClaimDueEmails", mock.Anything, fixedNow, fixedNow.Add(DefaultBatchSize*sendTimeout+time.Minute), DefaultBatchSize).
+		Return([]nModel.PendingEmail{{ID: 3, TenantID: 1, IDOrder: 9, Kind: nModel.KindOrderCancelled, ToEmail: "ana@example.com"}}, nil)
+	repo.On("GetOrderEmailData", mock.Anything, uint64(1), uint64(9)).Return(cancelledOrderData(), nil)
+	repo.On("MarkEmailSent", mock.Anything, uint64(3)).Return(nil)
+
+	result, err := newTestWorker(repo, sender).SendPending(context.Background())
+
+	require.NoError(t, err)
+	assert.Equal(t, SendResult{Sent: 1}, result)
+	require.Len(t, sender.statusUpdates, 1)
+	got := sender.statusUpdates[0]
+	assert.Equal(t, "ana@example.com", got.ToEmail)
+	assert.Equal(t, "cancelled", got.Status)
+	assert.Equal(t, "Out of flour", got.CancellationReason)
+	assert.Equal(t, "2026-03-05", got.DeliveryDate)
+	assert.Equal(t, money.Currency("EUR"), got.Currency)
+	assert.Equal(t, []email.OrderEmailItem{{Name: "Bread", Quantity: 2, UnitPrice: 625}}, got.Items)
+	repo.AssertExpectations(t)
+}
+
+func TestWorker_SendPending_SchedulesRetryWithBackoff(t *testing.T) {
+	repo := new(MockRepository)
+	sender := &recordingSender{err: errors.New("provider down")}
+	repo.On("ClaimDueEmails", mock.Anything, fixedNow, mock.Anything, DefaultBatchSize).
+		Return([]nModel.PendingEmail{{ID: 3, TenantID: 1, IDOrder: 9, Kind: nModel.KindOrderReady, ToEmail: "ana@example.com", Attempts: 1}}, nil)
+	repo.On("GetOrderEmailData", mock.Anything, uint64(1), uint64(9)).Return(cancelledOrderData(), nil)
+	next := fixedNow.Add(2 * DefaultBaseBackoff)
+	repo.On("MarkEmailAttemptFailed", mock.Anything, uint64(3), "provider down", &next).Return(nil)
+
+	result, err := newTestWorker(repo, sender).SendPending(context.Background())
+
+	require.NoError(t, err)
+	assert.Equal(t, SendResult{Retrying: 1}, result)
+	require.Len(t, sender.statusUpdates, 1)
+	assert.Equal(t, "ready", sender.statusUpdates[0].Status)
+	repo.AssertExpectations(t)
+}
+
+func TestWorker_SendPending_GivesUpAfterMaxAttempts(t *testing.T) {
+	repo := new(MockRepository)
+	sender := &recordingSender{err: errors.New("provider down")}
+	repo.On("ClaimDueEmails", mock.Anything, fixedNow, mock.Anything, DefaultBatchSize).
+		Return([]nModel.PendingEmail{{ID: 3, TenantID: 1, IDOrder: 9, Kind: nModel.KindOrderDelivered, ToEmail: "ana@example.com", Attempts: 2}}, nil)
+	repo.On("GetOrderEmailData", mock.Anything, uint64(1), uint64(9)).Return(cancelledOrderData(), nil)
+	repo.On("MarkEmailAttemptFailed", mock.Anything, uint64(3), "provider down", (*time.Time)(nil)).Return(nil)
+
+	result, err := newTestWorker(repo, sender).SendPending(context.Background())
+
+	require.NoError(t, err)
+	assert.Equal(t, SendResult{Failed: 1}, result)
+	repo.AssertExpectations(t)
+}
@@ -12,7 +12,9 @@ import (
 	orderRepo "github.com/radamesvaz/bakery-app/internal/repository/orders"
 	productRepo "github.com/radamesvaz/bakery-app/internal/repository/products"
 	tenantRepo "github.com/radamesvaz/bakery-app/internal/repository/tenant"
+	nModel "github.com/radamesvaz/bakery-app/model/notifications"
 	oModel "github.com/radamesvaz/bakery-app/model/orders"
+	whModel "github.com/radamesvaz/bakery-app/model/webhooks"
 )
 
 const (
@@ -27,6 +29,10 @@ type ExpiredOrderCanceller struct {
 	ProductRepo    *productRepo.ProductRepository
 	TenantRepo     *tenantRepo.Repository
 	TimeoutMinutes int // From env today; in multi-tenant will come from DB per tenant (see NewExpiredOrderCanceller doc).
+	// Events receives order.expired in the claim transaction; nil disables webhooks.
+	Events OrderEventPublisher
+	// Notifications queues the customer's expiry email in the claim transaction; nil disables it.
+	Notifications OrderNotifier
 }
 
 // NewExpiredOrderCanceller builds an ExpiredOrderCanceller reading GHOST_ORDER_TIMEOUT_MINUTES from env (default 30).
@@ -167,6 +173,8 @@ func (c *ExpiredOrderCanceller) revertStockAndRecordHistoryTx(ctx context.Contex
 		CancellationReason: &reason,
 		ModifiedBy:         systemModifiedByID,
 		Action:             oModel.ActionUpdate,
+		DiscountAmount:     order.DiscountAmount,
+		PromotionCode:      order.PromotionCode,
 	}
 	if order.IdUser != 0 {
 		orderHistory.IdUser = &order.IdUser
@@ -176,5 +184,18 @@ func (c *ExpiredOrderCanceller) revertStockAndRecordHistoryTx(ctx context.Contex
 	if err := c.OrderRepo.CreateOrderHistoryTx(ctx, tx, orderHistory); err != nil {
 		return fmt.Errorf("create order history: %w", err)
 	}
+
+	if c.Events != nil {
+		data := buildOrderEventData(order.ID, string(oModel.StatusExpired), string(oModel.StatusPending), order.Price, order.Paid, order.DeliveryDate, &reason)
+		if err := c.Events.EnqueueEventTx(ctx, tx, order.TenantID, whModel.EventOrderExpired, data); err != nil {
+			return err
+		}
+	}
+
+	if c.Notifications != nil {
+		if err := c.Notifications.EnqueueOrderNotificationTx(ctx, tx, order.TenantID, order.ID, nModel.KindOrderExpired); err != nil {
+			return err
+		}
+	}
 	return nil
 }
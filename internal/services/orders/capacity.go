@@ -0,0 +1,144 @@
+package orders
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/radamesvaz/bakery-app/internal/errors"
+	capModel "github.com/radamesvaz/bakery-app/model/capacity"
+)
+
+// MaxAvailabilityDays caps the range of one availability request.
+const MaxAvailabilityDays = 92
+
+// DeliveryCapacityRepository exposes the tenant delivery limits and bookings.
+// It is implemented by the capacity repository.
+type DeliveryCapacityRepository interface {
+	LockDeliveryDayTx(ctx context.Context, tx *sql.Tx, tenantID uint64, date time.Time) error
+	GetDayRulesTx(ctx context.Context, tx *sql.Tx, tenantID uint64, date time.Time) (capModel.DayRules, error)
+	GetDayUsageTx(ctx context.Context, tx *sql.Tx, tenantID uint64, date time.Time) (capModel.DayUsage, error)
+	GetSettings(ctx context.Context, tenantID uint64) (capModel.Settings, error)
+	ListDayOverrides(ctx context.Context, tenantID uint64, from, to time.Time) ([]capModel.DayOverride, error)
+	ListUsage(ctx context.Context, tenantID uint64, from, to time.Time) (map[string]capModel.DayUsage, error)
+}
+
+// reserveDeliveryCapacityTx checks that an order of units fits on date. The day row stays locked
+// until tx ends, so two orders for the last slot cannot both pass the check.
+func reserveDeliveryCapacityTx(ctx context.Context, repo DeliveryCapacityRepository, tx *sql.Tx, tenantID uint64, date time.Time, units int, now time.Time) error {
+	if err := repo.LockDeliveryDayTx(ctx, tx, tenantID, date); err != nil {
+		return err
+	}
+	rules, err := repo.GetDayRulesTx(ctx, tx, tenantID, date)
+	if err != nil {
+		return err
+	}
+	var usage capModel.DayUsage
+	if rules.HasLimits() {
+		usage, err = repo.GetDayUsageTx(ctx, tx, tenantID, date)
+		if err != nil {
+			return err
+		}
+	}
+	switch rules.Check(date, now, usage, units) {
+	case capModel.ReasonClosed:
+		return errors.ErrDeliveryDateClosed
+	case capModel.ReasonLeadTime:
+		return errors.ErrDeliveryLeadTimeNotMet
+	case capModel.ReasonFull:
+		return errors.ErrDeliveryDateFullyBooked
+	}
+	return nil
+}
+
+// reserveAddedUnitsTx checks that units more fit on date for an order already booked there (item
+// edits). The day row stays locked until tx ends, like in reserveDeliveryCapacityTx.
+func reserveAddedUnitsTx(ctx context.Context, repo DeliveryCapacityRepository, tx *sql.Tx, tenantID uint64, date time.Time, units int) error {
+	if err := repo.LockDeliveryDayTx(ctx, tx, tenantID, date); err != nil {
+		return err
+	}
+	rules, err := repo.GetDayRulesTx(ctx, tx, tenantID, date)
+	if err != nil {
+		return err
+	}
+	if rules.MaxUnits == nil {
+		return nil
+	}
+	usage, err := repo.GetDayUsageTx(ctx, tx, tenantID, date)
+	if err != nil {
+		return err
+	}
+	if rules.CheckAddedUnits(usage, units) == capModel.ReasonFull {
+		return errors.ErrDeliveryDateFullyBooked
+	}
+	return nil
+}
+
+// Availability answers which delivery dates the storefront can offer.
+type Availability struct {
+	Repo DeliveryCapacityRepository
+	Now  func() time.Time
+}
+
+func NewAvailability(repo DeliveryCapacityRepository) *Availability {
+	return &Availability{Repo: repo, Now: time.Now}
+}
+
+// ListAvailability returns one entry per date from from to to (inclusive). A day is available
+// when a single-unit order would be accepted right now.
+func (a *Availability) ListAvailability(ctx context.Context, tenantID uint64, from, to time.Time) (capModel.AvailabilityResponse, error) {
+	settings, err := a.Repo.GetSettings(ctx, tenantID)
+	if err != nil {
+		return capModel.AvailabilityResponse{}, err
+	}
+	overrides, err := a.Repo.ListDayOverrides(ctx, tenantID, from, to)
+	if err != nil {
+		return capModel.AvailabilityResponse{}, err
+	}
+	usage, err := a.Repo.ListUsage(ctx, tenantID, from, to)
+	if err != nil {
+		return capModel.AvailabilityResponse{}, err
+	}
+	overrideByDate := make(map[string]capModel.DayOverride, len(overrides))
+	for _, o := range overrides {
+		overrideByDate[o.Date] = o
+	}
+
+	now := a.Now()
+	resp := capModel.AvailabilityResponse{
+		From: from.Format(capModel.DateLayout),
+		To:   to.Format(capModel.DateLayout),
+		Days: []capModel.DayAvailability{},
+	}
+	for day := from; !day.After(to); day = day.AddDate(0, 0, 1) {
+		key := day.Format(capModel.DateLayout)
+		var override *capModel.DayOverride
+		if o, ok := overrideByDate[key]; ok {
+			override = &o
+		}
+		rules := capModel.EffectiveRules(settings, override)
+		dayUsage := usage[key]
+
+		entry := capModel.DayAvailability{Date: key}
+		entry.Reason = rules.Check(day, now, dayUsage, 1)
+		entry.Available = entry.Reason == ""
+		if !rules.Closed {
+			if rules.MaxOrders != nil {
+				entry.RemainingOrders = remaining(*rules.MaxOrders, dayUsage.Orders)
+			}
+			if rules.MaxUnits != nil {
+				entry.RemainingUnits = remaining(*rules.MaxUnits, dayUsage.Units)
+			}
+		}
+		resp.Days = append(resp.Days, entry)
+	}
+	return resp, nil
+}
+
+func remaining(limit, used int) *int {
+	n := limit - used
+	if n < 0 {
+		n = 0
+	}
+	return &n
+}
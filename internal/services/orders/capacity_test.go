@@ -0,0 +1,169 @@
+package orders
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	appErrors "github.com/radamesvaz/bakery-app/internal/errors"
+	capModel "github.com/radamesvaz/bakery-app/model/capacity"
+	oModel "github.com/radamesvaz/bakery-app/model/orders"
+	pModel "github.com/radamesvaz/bakery-app/model/products"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+type MockDeliveryCapacityRepository struct {
+	mock.Mock
+}
+
+func (m *MockDeliveryCapacityRepository) LockDeliveryDayTx(ctx context.Context, tx *sql.Tx, tenantID uint64, date time.Time) error {
+	args := m.Called(ctx, tx, tenantID, date)
+	return args.Error(0)
+}
+
+func (m *MockDeliveryCapacityRepository) GetDayRulesTx(ctx context.Context, tx *sql.Tx, tenantID uint64, date time.Time) (capModel.DayRules, error) {
+	args := m.Called(ctx, tx, tenantID, date)
+	return args.Get(0).(capModel.DayRules), args.Error(1)
+}
+
+func (m *MockDeliveryCapacityRepository) GetDayUsageTx(ctx context.Context, tx *sql.Tx, tenantID uint64, date time.Time) (capModel.DayUsage, error) {
+	args := m.Called(ctx, tx, tenantID, date)
+	return args.Get(0).(capModel.DayUsage), args.Error(1)
+}
+
+func (m *MockDeliveryCapacityRepository) GetSettings(ctx context.Context, tenantID uint64) (capModel.Settings, error) {
+	args := m.Called(ctx, tenantID)
+	return args.Get(0).(capModel.Settings), args.Error(1)
+}
+
+func (m *MockDeliveryCapacityRepository) ListDayOverrides(ctx context.Context, tenantID uint64, from, to time.Time) ([]capModel.DayOverride, error) {
+	args := m.Called(ctx, tenantID, from, to)
+	return args.Get(0).([]capModel.DayOverride), args.Error(1)
+}
+
+func (m *MockDeliveryCapacityRepository) ListUsage(ctx context.Context, tenantID uint64, from, to time.Time) (map[string]capModel.DayUsage, error) {
+	args := m.Called(ctx, tenantID, from, to)
+	return args.Get(0).(map[string]capModel.DayUsage), args.Error(1)
+}
+
+func capacityIntPtr(n int) *int { return &n }
+
+func TestReserveDeliveryCapacityTx(t *testing.T) {
+	date := time.Date(2026, 12, 24, 0, 0, 0, 0, time.UTC)
+	now := time.Date(2026, 12, 20, 10, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name    string
+		rules   capModel.DayRules
+		usage   capModel.DayUsage
+		units   int
+		wantErr error
+	}{
+		{name: "no limits", rules: capModel.DayRules{}, units: 50},
+		{name: "closed", rules: capModel.DayRules{Closed: true}, units: 1, wantErr: appErrors.ErrDeliveryDateClosed},
+		{name: "lead time", rules: capModel.DayRules{MinLeadTimeHours: 120}, units: 1, wantErr: appErrors.ErrDeliveryLeadTimeNotMet},
+		{name: "last order slot", rules: capModel.DayRules{MaxOrders: capacityIntPtr(3)}, usage: capModel.DayUsage{Orders: 2}, units: 1},
+		{name: "orders full", rules: capModel.DayRules{MaxOrders: capacityIntPtr(3)}, usage: capModel.DayUsage{Orders: 3}, units: 1, wantErr: appErrors.ErrDeliveryDateFullyBooked},
+		{name: "units exceed", rules: capModel.DayRules{MaxUnits: capacityIntPtr(100)}, usage: capModel.DayUsage{Units: 95}, units: 6, wantErr: appErrors.ErrDeliveryDateFullyBooked},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := new(MockDeliveryCapacityRepository)
+			repo.On("LockDeliveryDayTx", mock.Anything, mock.Anything, uint64(1), date).Return(nil)
+			repo.On("GetDayRulesTx", mock.Anything, mock.Anything, uint64(1), date).Return(tt.rules, nil)
+			repo.On("GetDayUsageTx", mock.Anything, mock.Anything, uint64(1), date).Return(tt.usage, nil)
+
+			err := reserveDeliveryCapacityTx(context.Background(), repo, nil, 1, date, tt.units, now)
+
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+			} else {
+				assert.NoError(t, err)
+			}
+			if !tt.rules.HasLimits() {
+				repo.AssertNotCalled(t, "GetDayUsageTx", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+			}
+		})
+	}
+}
+
+func TestCreator_CreateOrder_RejectsFullyBookedDayBeforeStockChanges(t *testing.T) {
+	db, sqlMock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+	sqlMock.ExpectBegin()
+	sqlMock.ExpectRollback()
+
+	deliveryDate := time.Now().UTC().AddDate(0, 0, 7).Truncate(24 * time.Hour)
+	capacity := new(MockDeliveryCapacityRepository)
+	capacity.On("LockDeliveryDayTx", mock.Anything, mock.Anything, uint64(1), deliveryDate).Return(nil)
+	capacity.On("GetDayRulesTx", mock.Anything, mock.Anything, uint64(1), deliveryDate).
+		Return(capModel.DayRules{MaxUnits: capacityIntPtr(10)}, nil)
+	capacity.On("GetDayUsageTx", mock.Anything, mock.Anything, uint64(1), deliveryDate).
+		Return(capModel.DayUsage{Orders: 2, Units: 8}, nil)
+
+	productRepo := &MockProductRepo2{
+		Products:     map[uint64]pModel.Product{1: activeProduct(1, "Pan", 2.50, 10)},
+		StockUpdates: make(map[uint64]uint64),
+	}
+	orderRepo := &MockOrderRepo2{DB: db}
+	service := Creator{
+		UserRepo:    &MockUserRepo{ShouldCreate: false},
+		ProductRepo: productRepo,
+		OrderRepo:   orderRepo,
+		Capacity:    capacity,
+	}
+
+	payload := oModel.CreateOrderPayload{
+		Name:              "Cliente Test",
+		Email:             "test@example.com",
+		Phone:             "12345678",
+		DeliveryDirection: "https://maps.app.goo.gl/test-direction-1",
+		Items:             []oModel.CreateOrderItemInput{{IdProduct: 1, Quantity: 2}, {IdProduct: 1, Quantity: 1}},
+		DeliveryDate:      deliveryDate.Format("2006-01-02"),
+	}
+	_, err = service.CreateOrder(context.Background(), 1, payload, deliveryDate)
+
+	assert.ErrorIs(t, err, appErrors.ErrDeliveryDateFullyBooked)
+	assert.False(t, orderRepo.OrderCreated)
+	assert.Empty(t, productRepo.StockUpdates)
+	require.NoError(t, sqlMock.ExpectationsWereMet())
+}
+
+func TestAvailability_ListAvailability(t *testing.T) {
+	from := time.Date(2026, 12, 23, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2026, 12, 26, 0, 0, 0, 0, time.UTC)
+
+	repo := new(MockDeliveryCapacityRepository)
+	repo.On("GetSettings", mock.Anything, uint64(1)).
+		Return(capModel.Settings{MaxOrdersPerDay: capacityIntPtr(5), MinLeadTimeHours: 24}, nil)
+	repo.On("ListDayOverrides", mock.Anything, uint64(1), from, to).
+		Return([]capModel.DayOverride{
+			{Date: "2026-12-25", Closed: true},
+			{Date: "2026-12-26", MaxOrders: capacityIntPtr(10)},
+		}, nil)
+	repo.On("ListUsage", mock.Anything, uint64(1), from, to).
+		Return(map[string]capModel.DayUsage{
+			"2026-12-24": {Orders: 5, Units: 40},
+			"2026-12-26": {Orders: 4, Units: 12},
+		}, nil)
+
+	availability := NewAvailability(repo)
+	availability.Now = func() time.Time { return time.Date(2026, 12, 22, 9, 0, 0, 0, time.UTC) }
+
+	resp, err := availability.ListAvailability(context.Background(), 1, from, to)
+
+	require.NoError(t, err)
+	assert.Equal(t, "2026-12-23", resp.From)
+	assert.Equal(t, "2026-12-26", resp.To)
+	assert.Equal(t, []capModel.DayAvailability{
+		{Date: "2026-12-23", Available: false, Reason: capModel.ReasonLeadTime, RemainingOrders: capacityIntPtr(5)},
+		{Date: "2026-12-24", Available: false, Reason: capModel.ReasonFull, RemainingOrders: capacityIntPtr(0)},
+		{Date: "2026-12-25", Available: false, Reason: capModel.ReasonClosed},
+		{Date: "2026-12-26", Available: true, RemainingOrders: capacityIntPtr(6)},
+	}, resp.Days)
+}
@@ -2,19 +2,29 @@ package orders
 
 import (
 	"context"
+	"crypto/sha256"
 	"database/sql"
+	"encoding/hex"
+	"encoding/json"
 	stdErrors "errors"
 	"fmt"
 	"os"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/radamesvaz/bakery-app/internal/errors"
 	"github.com/radamesvaz/bakery-app/internal/logger"
 	userRepo "github.com/radamesvaz/bakery-app/internal/repository/user"
+	"github.com/radamesvaz/bakery-app/internal/services/tokens"
+	"github.com/radamesvaz/bakery-app/model/money"
+	nModel "github.com/radamesvaz/bakery-app/model/notifications"
+	opModel "github.com/radamesvaz/bakery-app/model/orderprotection"
 	oModel "github.com/radamesvaz/bakery-app/model/orders"
 	pModel "github.com/radamesvaz/bakery-app/model/products"
+	promoModel "github.com/radamesvaz/bakery-app/model/promotions"
 	uModel "github.com/radamesvaz/bakery-app/model/users"
+	whModel "github.com/radamesvaz/bakery-app/model/webhooks"
 )
 
 // Interfaces for dependencies (to enable testing without DB)
@@ -23,8 +33,19 @@ type orderCreatorRepository interface {
 	CreateOrder(ctx context.Context, tx *sql.Tx, order oModel.CreateOrderRequest) (uint64, error)
 	CreateOrderItems(ctx context.Context, tx *sql.Tx, tenantID uint64, items []oModel.OrderItemRequest) error
 	CreateOrderHistoryTx(ctx context.Context, tx *sql.Tx, order oModel.OrderHistory) error
+	GetOrderByID(ctx context.Context, tenantID, id uint64) (oModel.OrderResponse, error)
 }
 
+// OrderIdempotencyRepository stores the Idempotency-Key values of order creation.
+type OrderIdempotencyRepository interface {
+	ReserveIdempotencyKeyTx(ctx context.Context, tx *sql.Tx, tenantID uint64, key, requestHash string, expiredBefore time.Time) (bool, error)
+	SetIdempotencyKeyOrderTx(ctx context.Context, tx *sql.Tx, tenantID uint64, key string, orderID uint64) error
+	GetIdempotencyKey(ctx context.Context, tenantID uint64, key string, expiredBefore time.Time) (*oModel.OrderIdempotencyKey, error)
+}
+
+// IdempotencyKeyTTL is how long a retried request with the same Idempotency-Key replays the first order.
+const IdempotencyKeyTTL = 24 * time.Hour
+
 type productCreatorRepository interface {
 	GetProductsByIDs(ctx context.Context, tenantID uint64, ids []uint64) ([]pModel.Product, error)
 	// AssertProductActiveTx locks the row and returns the current track_inventory flag.
@@ -43,6 +64,32 @@ type Creator struct {
 	UserRepo    userRepo.Repository
 	ProductRepo productCreatorRepository
 	TenantRepo  TenantConfigRepository
+	// Events receives order.created and product.out_of_stock in the order transaction; nil disables webhooks.
+	Events OrderEventPublisher
+	// Notifications queues the customer confirmation and the admins' new-order alert; nil disables them.
+	Notifications OrderNotifier
+	// Capacity enforces the tenant's delivery limits in the order transaction; nil accepts any date.
+	Capacity DeliveryCapacityRepository
+	// TrackingTokens issues the customer's public tracking token; nil creates orders without one.
+	TrackingTokens tokens.OneTimeTokenManager
+	// IdempotencyKeys backs CreateOrderWithIdempotencyKey; nil ignores the key.
+	IdempotencyKeys OrderIdempotencyRepository
+	// Promotions redeems the payload's promotion_code in the order transaction; nil rejects any code.
+	Promotions PromotionRepository
+	// Pricing adds the tenant's tax and delivery fee to the order; nil charges neither.
+	Pricing PricingRepository
+	// PickupLocations checks the location of pickup orders; nil rejects pickup orders.
+	PickupLocations PickupLocationRepository
+	// PaymentLedger records the payment of staff orders created paid; nil rejects them.
+	PaymentLedger OrderPaymentLedgerRepository
+	// Protection enforces the tenant's blocklist, line quantity and pending unpaid order limits
+	// on storefront orders; nil disables them.
+	Protection OrderProtectionRepository
+}
+
+// OrderPaymentLedgerRepository appends lines to the order_payments ledger.
+type OrderPaymentLedgerRepository interface {
+	CreateOrderPaymentTx(ctx context.Context, tx *sql.Tx, p oModel.OrderPaymentRequest) (oModel.OrderPayment, error)
 }
 
 // TODO multi-tenant: when tenant-specific config exists, this timeout should come from the
@@ -90,16 +137,106 @@ func mergeOrderItemsByProduct(items []oModel.CreateOrderItemInput) []oModel.Crea
 	return merged
 }
 
-// CreateOrder creates a costumer order
-func (c *Creator) CreateOrder(ctx context.Context, tenantID uint64, payload oModel.CreateOrderPayload, deliveryDate time.Time) error {
-	// Find user or create it if not found (scoped to tenant)
-	user, err := c.GetOrCreateUser(ctx, tenantID, payload)
-	if err != nil {
-		return fmt.Errorf("error getting or creating user: %w", err)
+// CreateOrder creates a costumer order and returns it with, when TrackingTokens is set, the plain tracking token.
+func (c *Creator) CreateOrder(ctx context.Context, tenantID uint64, payload oModel.CreateOrderPayload, deliveryDate time.Time) (oModel.CreateOrderResult, error) {
+	return c.CreateOrderWithIdempotencyKey(ctx, tenantID, "", payload, deliveryDate)
+}
+
+// CreateOrderOptions tune CreateOrderWithOptions for callers other than the public storefront.
+type CreateOrderOptions struct {
+	// IdempotencyKey makes retries return the first order; see CreateOrderWithIdempotencyKey.
+	IdempotencyKey string
+	// NoExpiry stores the order without expires_at, so the ghost-order worker never expires it
+	// (e.g. standing orders, which are invoiced instead of paid at checkout).
+	NoExpiry bool
+	// SalesChannel is stored on the order; empty means web.
+	SalesChannel oModel.SalesChannel
+	// Staff marks an order entered by staff; nil for customer orders.
+	Staff *StaffOrderOptions
+}
+
+// StaffOrderOptions describe an order entered by staff. Staff orders never expire, may have no
+// customer (no email), may be picked up at the shop without a pickup location, are not held to
+// the storefront's delivery capacity rules (they still count towards the day's usage) and do
+// not alert the admins about a new order.
+type StaffOrderOptions struct {
+	// UserID is the staff member, written as modified_by of the history row and recorded_by of
+	// the payment.
+	UserID uint64
+	// Status is the status the order starts in; empty means pending.
+	Status oModel.OrderStatus
+	// PaidWith records a ledger payment of the full total with this method; empty leaves the
+	// order unpaid.
+	PaidWith         oModel.PaymentMethod
+	PaymentReference *string
+}
+
+// CreateOrderWithIdempotencyKey is CreateOrder for requests carrying an Idempotency-Key header.
+// The key is reserved in the order transaction, so concurrent retries serialize on it and only
+// one order (and one stock reservation) is created; later requests with the same key and body
+// get that order back. Reusing a key with a different body is rejected.
+func (c *Creator) CreateOrderWithIdempotencyKey(ctx context.Context, tenantID uint64, key string, payload oModel.CreateOrderPayload, deliveryDate time.Time) (oModel.CreateOrderResult, error) {
+	return c.CreateOrderWithOptions(ctx, tenantID, payload, deliveryDate, CreateOrderOptions{IdempotencyKey: key})
+}
+
+// CreateOrderWithOptions is the order creation path shared by every caller.
+func (c *Creator) CreateOrderWithOptions(ctx context.Context, tenantID uint64, payload oModel.CreateOrderPayload, deliveryDate time.Time, opts CreateOrderOptions) (oModel.CreateOrderResult, error) {
+	key := opts.IdempotencyKey
+	useKey := key != "" && c.IdempotencyKeys != nil
+	var requestHash string
+	if useKey {
+		var err error
+		if opts.Staff != nil {
+			// The staff fields change the order too, so a key cannot be replayed across them.
+			requestHash, err = hashCreateOrderPayload(struct {
+				Payload          oModel.CreateOrderPayload
+				SalesChannel     oModel.SalesChannel
+				Status           oModel.OrderStatus
+				PaidWith         oModel.PaymentMethod
+				PaymentReference *string
+			}{payload, opts.SalesChannel, opts.Staff.Status, opts.Staff.PaidWith, opts.Staff.PaymentReference})
+		} else {
+			requestHash, err = hashCreateOrderPayload(payload)
+		}
+		if err != nil {
+			return oModel.CreateOrderResult{}, err
+		}
+		existing, err := c.IdempotencyKeys.GetIdempotencyKey(ctx, tenantID, key, time.Now().Add(-IdempotencyKeyTTL))
+		if err != nil {
+			return oModel.CreateOrderResult{}, err
+		}
+		if existing != nil {
+			return c.replayIdempotentOrder(ctx, tenantID, *existing, requestHash)
+		}
 	}
 
 	mergedItems := mergeOrderItemsByProduct(payload.Items)
 
+	// Abuse limits only apply to storefront orders; staff and standing orders are trusted.
+	protect := c.Protection != nil && opts.Staff == nil && !opts.NoExpiry
+	var protection opModel.Settings
+	if protect {
+		var err error
+		protection, err = checkCustomerAllowed(ctx, c.Protection, tenantID, payload, mergedItems)
+		if err != nil {
+			return oModel.CreateOrderResult{}, err
+		}
+	}
+
+	// Find user or create it if not found (scoped to tenant). Staff orders without an email
+	// have no customer.
+	user := &uModel.User{}
+	if opts.Staff == nil || strings.TrimSpace(payload.Email) != "" {
+		var err error
+		user, err = c.GetOrCreateUser(ctx, tenantID, payload)
+		if err != nil {
+			return oModel.CreateOrderResult{}, fmt.Errorf("error getting or creating user: %w", err)
+		}
+	}
+	if opts.Staff != nil && opts.Staff.PaidWith != "" && c.PaymentLedger == nil {
+		return oModel.CreateOrderResult{}, fmt.Errorf("error creating paid order: payment ledger is not configured")
+	}
+
 	// Get all of the products by their ID
 	productIDs := make([]uint64, len(mergedItems))
 	for i, item := range mergedItems {
@@ -108,84 +245,211 @@ func (c *Creator) CreateOrder(ctx context.Context, tenantID uint64, payload oMod
 
 	products, err := c.ProductRepo.GetProductsByIDs(ctx, tenantID, productIDs)
 	if err != nil {
-		return fmt.Errorf("error getting products: %w", err)
+		return oModel.CreateOrderResult{}, fmt.Errorf("error getting products: %w", err)
 	}
 
 	if len(products) != len(productIDs) {
-		return errors.ErrProductNotFound
+		return oModel.CreateOrderResult{}, errors.ErrProductNotFound
 	}
 
 	productMap := make(map[uint64]pModel.Product)
 	for _, p := range products {
 		if p.Status != pModel.StatusActive {
-			return errors.ErrProductNotPurchasable
+			return oModel.CreateOrderResult{}, errors.ErrProductNotPurchasable
 		}
 		productMap[p.ID] = p
 	}
 
 	// Calculate the total price (stock is validated atomically in the tx below)
-	var totalPrice float64
-	for _, item := range mergedItems {
+	lines := make([]promoModel.Line, len(mergedItems))
+	for i, item := range mergedItems {
 		product := productMap[item.IdProduct]
-		totalPrice += product.Price * float64(item.Quantity)
+		lines[i] = promoModel.Line{IdProduct: item.IdProduct, Amount: product.Price.Times(item.Quantity)}
+	}
+	subtotal := promoModel.Subtotal(lines)
+
+	promotionCode := strings.TrimSpace(payload.PromotionCode)
+	if promotionCode != "" && c.Promotions == nil {
+		return oModel.CreateOrderResult{}, errors.ErrPromotionCodeInvalid
 	}
 
 	// Compute per-order expiration snapshot using the current timeout.
 	// Prefer the tenant-specific configuration from the tenants table when available;
 	// fall back to the global env-based timeout otherwise.
-	timeoutMinutes := getGhostOrderTimeoutMinutes()
-	if c.TenantRepo != nil {
-		if perTenantMinutes, err := c.TenantRepo.GetGhostOrderTimeoutMinutes(ctx, tenantID); err != nil {
-			logger.Warn().
-				Err(err).
-				Uint64("tenant_id", tenantID).
-				Msg("Failed to read tenant-specific ghost order timeout, falling back to global env value")
-		} else if perTenantMinutes > 0 {
-			timeoutMinutes = perTenantMinutes
+	var expiresAt time.Time
+	if !opts.NoExpiry && opts.Staff == nil {
+		timeoutMinutes := getGhostOrderTimeoutMinutes()
+		if c.TenantRepo != nil {
+			if perTenantMinutes, err := c.TenantRepo.GetGhostOrderTimeoutMinutes(ctx, tenantID); err != nil {
+				logger.Warn().
+					Err(err).
+					Uint64("tenant_id", tenantID).
+					Msg("Failed to read tenant-specific ghost order timeout, falling back to global env value")
+			} else if perTenantMinutes > 0 {
+				timeoutMinutes = perTenantMinutes
+			}
+		}
+		expiresAt = time.Now().Add(time.Duration(timeoutMinutes) * time.Minute)
+	}
+
+	var trackingToken, trackingTokenHash string
+	if c.TrackingTokens != nil {
+		trackingToken, trackingTokenHash, err = c.TrackingTokens.Generate()
+		if err != nil {
+			return oModel.CreateOrderResult{}, fmt.Errorf("error generating tracking token: %w", err)
 		}
 	}
-	expiresAt := time.Now().Add(time.Duration(timeoutMinutes) * time.Minute)
 
 	tx, err := c.OrderRepo.BeginTx(ctx)
 	if err != nil {
-		return fmt.Errorf("error beginning transaction: %w", err)
+		return oModel.CreateOrderResult{}, fmt.Errorf("error beginning transaction: %w", err)
 	}
 	defer func() { _ = tx.Rollback() }()
 
+	if useKey {
+		reserved, err := c.IdempotencyKeys.ReserveIdempotencyKeyTx(ctx, tx, tenantID, key, requestHash, time.Now().Add(-IdempotencyKeyTTL))
+		if err != nil {
+			return oModel.CreateOrderResult{}, err
+		}
+		if !reserved {
+			// A concurrent request with the same key committed first.
+			_ = tx.Rollback()
+			existing, err := c.IdempotencyKeys.GetIdempotencyKey(ctx, tenantID, key, time.Now().Add(-IdempotencyKeyTTL))
+			if err != nil {
+				return oModel.CreateOrderResult{}, err
+			}
+			if existing == nil {
+				return oModel.CreateOrderResult{}, errors.ErrIdempotencyKeyInProgress
+			}
+			return c.replayIdempotentOrder(ctx, tenantID, *existing, requestHash)
+		}
+	}
+
+	if protect {
+		if err := checkPendingUnpaidOrdersTx(ctx, c.Protection, tx, tenantID, user.ID, protection); err != nil {
+			return oModel.CreateOrderResult{}, err
+		}
+	}
+
+	if c.Capacity != nil && opts.Staff == nil {
+		var units int
+		for _, item := range mergedItems {
+			units += int(item.Quantity)
+		}
+		if err := reserveDeliveryCapacityTx(ctx, c.Capacity, tx, tenantID, deliveryDate, units, time.Now()); err != nil {
+			return oModel.CreateOrderResult{}, err
+		}
+	}
+
 	// Re-validate active status under row lock, then decrement stock for tracked inventory.
 	// Pre-tx TrackInventory/status from GetProductsByIDs are racy if an admin flips them mid-create.
+	var decrementedProductIDs []uint64
 	for _, item := range mergedItems {
 		trackInventory, err := c.ProductRepo.AssertProductActiveTx(ctx, tx, tenantID, item.IdProduct)
 		if err != nil {
-			return err
+			return oModel.CreateOrderResult{}, err
 		}
 		if !trackInventory {
 			continue
 		}
 		rows, err := c.ProductRepo.DecrementProductStockTx(ctx, tx, tenantID, item.IdProduct, item.Quantity)
 		if err != nil {
-			return fmt.Errorf("error reserving stock: %w", err)
+			return oModel.CreateOrderResult{}, fmt.Errorf("error reserving stock: %w", err)
 		}
 		if rows == 0 {
-			return errors.ErrNotEnoughProductStock
+			return oModel.CreateOrderResult{}, errors.ErrNotEnoughProductStock
 		}
+		decrementedProductIDs = append(decrementedProductIDs, item.IdProduct)
+	}
+
+	var (
+		promo    promoModel.Promotion
+		discount money.Amount
+	)
+	if promotionCode != "" {
+		promo, discount, err = applyPromotionTx(ctx, c.Promotions, tx, tenantID, promotionCode, payload.Email, lines, time.Now())
+		if err != nil {
+			return oModel.CreateOrderResult{}, err
+		}
+	}
+
+	fulfillment := oModel.NormalizeFulfillmentType(payload.FulfillmentType)
+	deliveryDirection := payload.DeliveryDirection
+	if fulfillment == oModel.FulfillmentPickup {
+		switch {
+		case payload.IDPickupLocation != nil:
+			if err := checkPickupLocationTx(ctx, c.PickupLocations, tx, tenantID, *payload.IDPickupLocation, deliveryDate); err != nil {
+				return oModel.CreateOrderResult{}, err
+			}
+		case opts.Staff == nil:
+			return oModel.CreateOrderResult{}, errors.ErrPickupLocationInvalid
+		}
+		deliveryDirection = ""
+	}
+
+	totals, err := priceOrderTx(ctx, c.Pricing, tx, tenantID, fulfillment, payload.IDDeliveryZone, true, subtotal, discount)
+	if err != nil {
+		return oModel.CreateOrderResult{}, err
+	}
+
+	status := oModel.StatusPending
+	paid := false
+	modifiedBy := user.ID
+	if opts.Staff != nil {
+		if opts.Staff.Status != "" {
+			status = opts.Staff.Status
+		}
+		paid = opts.Staff.PaidWith != ""
+		modifiedBy = opts.Staff.UserID
 	}
 
 	orderRequest := oModel.CreateOrderRequest{
 		TenantID:          tenantID,
 		IdUser:            user.ID,
 		DeliveryDate:      deliveryDate,
-		DeliveryDirection: payload.DeliveryDirection,
+		DeliveryDirection: deliveryDirection,
 		Note:              payload.Note,
-		Price:             totalPrice,
-		Status:            oModel.StatusPending,
-		Paid:              false,
+		Price:             totals.Total,
+		Status:            status,
+		Paid:              paid,
 		ExpiresAt:         expiresAt,
+		TrackingTokenHash: trackingTokenHash,
+		DiscountAmount:    totals.DiscountAmount,
+		Subtotal:          totals.Subtotal,
+		TaxRate:           totals.TaxRate,
+		TaxInclusive:      totals.TaxInclusive,
+		TaxAmount:         totals.TaxAmount,
+		DeliveryFee:       totals.DeliveryFee,
+		IDDeliveryZone:    payload.IDDeliveryZone,
+		FulfillmentType:   fulfillment,
+		IDPickupLocation:  payload.IDPickupLocation,
+		SalesChannel:      oModel.NormalizeSalesChannel(opts.SalesChannel),
+	}
+	if discount > 0 {
+		orderRequest.PromotionCode = &promo.Code
+		orderRequest.IDPromotion = &promo.ID
 	}
 
 	orderID, err := c.OrderRepo.CreateOrder(ctx, tx, orderRequest)
 	if err != nil {
-		return fmt.Errorf("error creating order: %w", err)
+		return oModel.CreateOrderResult{}, fmt.Errorf("error creating order: %w", err)
+	}
+	if orderRequest.IDPromotion != nil {
+		redemption := promoModel.Redemption{
+			TenantID:       tenantID,
+			IDPromotion:    promo.ID,
+			IDOrder:        orderID,
+			Email:          payload.Email,
+			DiscountAmount: discount,
+		}
+		if err := c.Promotions.CreateRedemptionTx(ctx, tx, redemption); err != nil {
+			return oModel.CreateOrderResult{}, err
+		}
+	}
+	if useKey {
+		if err := c.IdempotencyKeys.SetIdempotencyKeyOrderTx(ctx, tx, tenantID, key, orderID); err != nil {
+			return oModel.CreateOrderResult{}, err
+		}
 	}
 
 	orderItems := make([]oModel.OrderItemRequest, len(mergedItems))
@@ -200,14 +464,32 @@ func (c *Creator) CreateOrder(ctx context.Context, tenantID uint64, payload oMod
 		}
 	}
 	if err := c.OrderRepo.CreateOrderItems(ctx, tx, tenantID, orderItems); err != nil {
-		return fmt.Errorf("error creating order items: %w", err)
+		return oModel.CreateOrderResult{}, fmt.Errorf("error creating order items: %w", err)
 	}
 
-	idUser := user.ID
+	if paid && orderRequest.Price > 0 {
+		recordedBy := opts.Staff.UserID
+		if _, err := c.PaymentLedger.CreateOrderPaymentTx(ctx, tx, oModel.OrderPaymentRequest{
+			TenantID:   tenantID,
+			IDOrder:    orderID,
+			Amount:     orderRequest.Price,
+			Method:     opts.Staff.PaidWith,
+			Reference:  opts.Staff.PaymentReference,
+			RecordedBy: &recordedBy,
+		}); err != nil {
+			return oModel.CreateOrderResult{}, err
+		}
+	}
+
+	var historyUser *uint64
+	if user.ID != 0 {
+		idUser := user.ID
+		historyUser = &idUser
+	}
 	orderHistory := oModel.OrderHistory{
 		TenantID:          tenantID,
 		IDOrder:           orderID,
-		IdUser:            &idUser,
+		IdUser:            historyUser,
 		Status:            orderRequest.Status,
 		Price:             orderRequest.Price,
 		Note:              orderRequest.Note,
@@ -216,19 +498,116 @@ func (c *Creator) CreateOrder(ctx context.Context, tenantID uint64, payload oMod
 			Time:  deliveryDate,
 			Valid: !deliveryDate.IsZero(),
 		},
-		Paid:       orderRequest.Paid,
-		ModifiedBy: user.ID,
-		Action:     oModel.ActionCreate,
+		Paid:           orderRequest.Paid,
+		ModifiedBy:     modifiedBy,
+		Action:         oModel.ActionCreate,
+		DiscountAmount: orderRequest.DiscountAmount,
+		PromotionCode:  orderRequest.PromotionCode,
 	}
 	if err := c.OrderRepo.CreateOrderHistoryTx(ctx, tx, orderHistory); err != nil {
 		logger.Warn().Err(err).Uint64("order_id", orderID).Msg("Failed to create order history")
 		// Continue and commit order+items; history is best-effort for new orders
 	}
 
+	if c.Events != nil {
+		data := buildOrderEventData(orderID, string(orderRequest.Status), "", orderRequest.Price, orderRequest.Paid, deliveryDate, nil)
+		if err := c.Events.EnqueueEventTx(ctx, tx, tenantID, whModel.EventOrderCreated, data); err != nil {
+			return oModel.CreateOrderResult{}, err
+		}
+		if err := c.Events.EnqueueOutOfStockTx(ctx, tx, tenantID, decrementedProductIDs); err != nil {
+			return oModel.CreateOrderResult{}, err
+		}
+	}
+
+	if c.Notifications != nil {
+		var kinds []nModel.Kind
+		if user.ID != 0 {
+			kinds = append(kinds, nModel.KindOrderConfirmation)
+		}
+		if opts.Staff == nil {
+			kinds = append(kinds, nModel.KindAdminNewOrder)
+		}
+		for _, kind := range kinds {
+			if err := c.Notifications.EnqueueOrderNotificationTx(ctx, tx, tenantID, orderID, kind); err != nil {
+				return oModel.CreateOrderResult{}, err
+			}
+		}
+	}
+
 	if err := tx.Commit(); err != nil {
-		return fmt.Errorf("error committing transaction: %w", err)
+		return oModel.CreateOrderResult{}, fmt.Errorf("error committing transaction: %w", err)
+	}
+
+	order, err := c.OrderRepo.GetOrderByID(ctx, tenantID, orderID)
+	if err != nil {
+		// The order is committed; answer with what was written rather than failing the request.
+		logger.Warn().Err(err).Uint64("order_id", orderID).Msg("Failed to read back created order")
+		order = oModel.OrderResponse{
+			ID:                orderID,
+			TenantID:          tenantID,
+			IdUser:            user.ID,
+			User:              user.Name,
+			Phone:             user.Phone,
+			Status:            orderRequest.Status,
+			Price:             orderRequest.Price,
+			Note:              orderRequest.Note,
+			DeliveryDirection: orderRequest.DeliveryDirection,
+			DeliveryDate:      deliveryDate,
+			ExpiresAt:         expiresAt,
+			DiscountAmount:    orderRequest.DiscountAmount,
+			PromotionCode:     orderRequest.PromotionCode,
+			IDPromotion:       orderRequest.IDPromotion,
+			Subtotal:          orderRequest.Subtotal,
+			TaxRate:           orderRequest.TaxRate,
+			TaxInclusive:      orderRequest.TaxInclusive,
+			TaxAmount:         orderRequest.TaxAmount,
+			DeliveryFee:       orderRequest.DeliveryFee,
+			IDDeliveryZone:    orderRequest.IDDeliveryZone,
+			FulfillmentType:   orderRequest.FulfillmentType,
+			IDPickupLocation:  orderRequest.IDPickupLocation,
+			SalesChannel:      orderRequest.SalesChannel,
+			Paid:              orderRequest.Paid,
+		}
+		if orderRequest.Paid {
+			order.AmountPaid = orderRequest.Price
+		} else {
+			order.BalanceDue = orderRequest.Price
+		}
+	}
+	return oModel.CreateOrderResult{Order: order, TrackingToken: trackingToken}, nil
+}
+
+// replayIdempotentOrder answers a retried request with the order created for its key. The plain
+// tracking token is never stored, so the replay carries none; issuing a new one would break the
+// tracking link the first response already handed out.
+func (c *Creator) replayIdempotentOrder(ctx context.Context, tenantID uint64, existing oModel.OrderIdempotencyKey, requestHash string) (oModel.CreateOrderResult, error) {
+	if existing.RequestHash != requestHash {
+		return oModel.CreateOrderResult{}, errors.ErrIdempotencyKeyReused
+	}
+	if existing.IDOrder == nil {
+		return oModel.CreateOrderResult{}, errors.ErrIdempotencyKeyInProgress
+	}
+
+	order, err := c.OrderRepo.GetOrderByID(ctx, tenantID, *existing.IDOrder)
+	if err != nil {
+		return oModel.CreateOrderResult{}, err
+	}
+
+	logger.Info().
+		Uint64("tenant_id", tenantID).
+		Uint64("order_id", order.ID).
+		Msg("Replaying order creation for idempotency key")
+	return oModel.CreateOrderResult{Order: order, Replayed: true}, nil
+}
+
+// hashCreateOrderPayload fingerprints the request body so a key cannot be reused for a different order.
+func hashCreateOrderPayload(payload any) (string, error) {
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return "", fmt.Errorf("error hashing order request: %w", err)
 	}
-	return nil
+	sum := sha256.Sum256(body)
+	return hex.EncodeToString(sum[:]), nil
 }
 
 func (c *Creator) GetOrCreateUser(ctx context.Context, tenantID uint64, payload oModel.CreateOrderPayload) (*uModel.User, error) {
@@ -9,10 +9,14 @@ import (
 
 	"github.com/DATA-DOG/go-sqlmock"
 	internalErrors "github.com/radamesvaz/bakery-app/internal/errors"
+	"github.com/radamesvaz/bakery-app/internal/services/tokens"
+	"github.com/radamesvaz/bakery-app/model/money"
+	nModel "github.com/radamesvaz/bakery-app/model/notifications"
 	oModel "github.com/radamesvaz/bakery-app/model/orders"
 	pModel "github.com/radamesvaz/bakery-app/model/products"
 	uModel "github.com/radamesvaz/bakery-app/model/users"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/require"
 )
 
@@ -115,6 +119,7 @@ type MockOrderRepo2 struct {
 	OrderID        uint64
 	HistoryCreated bool
 	LastItems      []oModel.OrderItemRequest
+	LastOrder      oModel.CreateOrderRequest
 }
 
 func (m *MockOrderRepo2) BeginTx(ctx context.Context) (*sql.Tx, error) {
@@ -127,9 +132,22 @@ func (m *MockOrderRepo2) BeginTx(ctx context.Context) (*sql.Tx, error) {
 func (m *MockOrderRepo2) CreateOrder(ctx context.Context, tx *sql.Tx, order oModel.CreateOrderRequest) (uint64, error) {
 	m.OrderCreated = true
 	m.OrderID = 123
+	m.LastOrder = order
 	return m.OrderID, nil
 }
 
+func (m *MockOrderRepo2) GetOrderByID(ctx context.Context, tenantID, id uint64) (oModel.OrderResponse, error) {
+	return oModel.OrderResponse{
+		ID:         id,
+		TenantID:   tenantID,
+		IdUser:     m.LastOrder.IdUser,
+		Status:     m.LastOrder.Status,
+		Price:      m.LastOrder.Price,
+		ExpiresAt:  m.LastOrder.ExpiresAt,
+		BalanceDue: m.LastOrder.Price,
+	}, nil
+}
+
 func (m *MockOrderRepo2) CreateOrderItems(ctx context.Context, tx *sql.Tx, tenantID uint64, items []oModel.OrderItemRequest) error {
 	m.LastItems = append([]oModel.OrderItemRequest(nil), items...)
 	return nil
@@ -144,7 +162,7 @@ func activeProduct(id uint64, name string, price float64, stock uint64) pModel.P
 	return pModel.Product{
 		ID:             id,
 		Name:           name,
-		Price:          price,
+		Price:          money.FromMajor(price),
 		Stock:          stock,
 		Status:         pModel.StatusActive,
 		TrackInventory: true,
@@ -248,7 +266,7 @@ func TestCreateOrder_UpdatesProductStock(t *testing.T) {
 	}
 
 	deliveryDate, _ := time.Parse("2006-01-02", payload.DeliveryDate)
-	err = service.CreateOrder(ctx, 1, payload, deliveryDate)
+	_, err = service.CreateOrder(ctx, 1, payload, deliveryDate)
 
 	assert.NoError(t, err)
 	assert.True(t, mockOrderRepo.OrderCreated)
@@ -293,7 +311,7 @@ func TestCreateOrder_MergesDuplicateProductLines(t *testing.T) {
 		DeliveryDate: "2024-12-25",
 	}
 	deliveryDate, _ := time.Parse("2006-01-02", payload.DeliveryDate)
-	err = service.CreateOrder(context.Background(), 1, payload, deliveryDate)
+	_, err = service.CreateOrder(context.Background(), 1, payload, deliveryDate)
 
 	require.NoError(t, err)
 	assert.Equal(t, []uint64{1}, mockProductRepo.LastIDs)
@@ -302,7 +320,7 @@ func TestCreateOrder_MergesDuplicateProductLines(t *testing.T) {
 	require.Len(t, mockOrderRepo.LastItems, 1)
 	assert.Equal(t, uint64(5), mockOrderRepo.LastItems[0].Quantity)
 	assert.Equal(t, "Pan", mockOrderRepo.LastItems[0].ProductNameSnapshot)
-	assert.Equal(t, 2.50, mockOrderRepo.LastItems[0].UnitPriceSnapshot)
+	assert.Equal(t, money.Amount(250), mockOrderRepo.LastItems[0].UnitPriceSnapshot)
 	require.NoError(t, mock.ExpectationsWereMet())
 }
 
@@ -339,7 +357,7 @@ func TestCreateOrder_SkipsStockDecrementWhenNotTrackingInventory(t *testing.T) {
 		DeliveryDate: "2024-12-25",
 	}
 	deliveryDate, _ := time.Parse("2006-01-02", payload.DeliveryDate)
-	err = service.CreateOrder(context.Background(), 1, payload, deliveryDate)
+	_, err = service.CreateOrder(context.Background(), 1, payload, deliveryDate)
 
 	require.NoError(t, err)
 	assert.Equal(t, 0, mockProductRepo.DecrementCalls)
@@ -373,7 +391,7 @@ func TestCreateOrder_RejectsInactiveProduct(t *testing.T) {
 		DeliveryDate: "2024-12-25",
 	}
 	deliveryDate, _ := time.Parse("2006-01-02", payload.DeliveryDate)
-	err := service.CreateOrder(context.Background(), 1, payload, deliveryDate)
+	_, err := service.CreateOrder(context.Background(), 1, payload, deliveryDate)
 
 	assert.ErrorIs(t, err, internalErrors.ErrProductNotPurchasable)
 	assert.False(t, mockOrderRepo.OrderCreated)
@@ -415,7 +433,7 @@ func TestCreateOrder_RejectsWhenProductDeactivatedInsideTx(t *testing.T) {
 		DeliveryDate: "2024-12-25",
 	}
 	deliveryDate, _ := time.Parse("2006-01-02", payload.DeliveryDate)
-	err = service.CreateOrder(context.Background(), 1, payload, deliveryDate)
+	_, err = service.CreateOrder(context.Background(), 1, payload, deliveryDate)
 
 	assert.ErrorIs(t, err, internalErrors.ErrProductNotPurchasable)
 	assert.False(t, mockOrderRepo.OrderCreated)
@@ -462,7 +480,7 @@ func TestCreateOrder_UsesLockedTrackInventoryWhenEnabledMidCreate(t *testing.T)
 		DeliveryDate: "2024-12-25",
 	}
 	deliveryDate, _ := time.Parse("2006-01-02", payload.DeliveryDate)
-	err = service.CreateOrder(context.Background(), 1, payload, deliveryDate)
+	_, err = service.CreateOrder(context.Background(), 1, payload, deliveryDate)
 
 	require.NoError(t, err)
 	assert.Equal(t, 1, mockProductRepo.DecrementCalls, "must decrement using locked track_inventory, not pre-tx snapshot")
@@ -506,7 +524,7 @@ func TestCreateOrder_RejectsOrderWhenInsufficientStock(t *testing.T) {
 	}
 
 	deliveryDate, _ := time.Parse("2006-01-02", payload.DeliveryDate)
-	err = service.CreateOrder(ctx, 1, payload, deliveryDate)
+	_, err = service.CreateOrder(ctx, 1, payload, deliveryDate)
 
 	assert.ErrorIs(t, err, internalErrors.ErrNotEnoughProductStock)
 	assert.False(t, mockOrderRepo.OrderCreated)
@@ -552,7 +570,7 @@ func TestCreateOrder_SecondOrderFailsAfterStockDepletion(t *testing.T) {
 		DeliveryDate: "2024-12-25",
 	}
 	deliveryDate, _ := time.Parse("2006-01-02", firstPayload.DeliveryDate)
-	err = service.CreateOrder(ctx, 1, firstPayload, deliveryDate)
+	_, err = service.CreateOrder(ctx, 1, firstPayload, deliveryDate)
 	assert.NoError(t, err)
 	assert.True(t, mockOrderRepo.OrderCreated)
 	assert.Equal(t, uint64(0), mockProductRepo.StockUpdates[1])
@@ -574,9 +592,303 @@ func TestCreateOrder_SecondOrderFailsAfterStockDepletion(t *testing.T) {
 		DeliveryDate: "2024-12-26",
 	}
 	deliveryDate2, _ := time.Parse("2006-01-02", secondPayload.DeliveryDate)
-	err2 := service.CreateOrder(ctx, 1, secondPayload, deliveryDate2)
+	_, err2 := service.CreateOrder(ctx, 1, secondPayload, deliveryDate2)
 	assert.ErrorIs(t, err2, internalErrors.ErrNotEnoughProductStock)
 	assert.False(t, mockOrderRepo.OrderCreated)
 	assert.False(t, mockOrderRepo.HistoryCreated)
 	require.NoError(t, mock.ExpectationsWereMet())
 }
+
+func TestCreateOrder_ReturnsTrackingToken(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+	mock.ExpectBegin()
+	mock.ExpectCommit()
+
+	tokenManager := tokens.NewSHA256OneTimeTokenManager(32)
+	mockOrderRepo := &MockOrderRepo2{DB: db}
+	service := Creator{
+		UserRepo: &MockUserRepo{ShouldCreate: false},
+		ProductRepo: &MockProductRepo2{
+			Products:     map[uint64]pModel.Product{1: activeProduct(1, "Pan", 2.50, 10)},
+			StockUpdates: make(map[uint64]uint64),
+		},
+		OrderRepo:      mockOrderRepo,
+		TrackingTokens: tokenManager,
+	}
+
+	payload := oModel.CreateOrderPayload{
+		Name:              "Cliente Test",
+		Email:             "test@example.com",
+		Phone:             "12345678",
+		DeliveryDirection: "https://maps.app.goo.gl/test-direction-1",
+		Items:             []oModel.CreateOrderItemInput{{IdProduct: 1, Quantity: 1}},
+		DeliveryDate:      "2024-12-25",
+	}
+	deliveryDate, _ := time.Parse("2006-01-02", payload.DeliveryDate)
+
+	result, err := service.CreateOrder(context.Background(), 1, payload, deliveryDate)
+
+	require.NoError(t, err)
+	assert.Equal(t, uint64(123), result.Order.ID)
+	assert.Equal(t, money.Amount(250), result.Order.Price)
+	assert.False(t, result.Replayed)
+	require.NotEmpty(t, result.TrackingToken)
+	assert.Equal(t, tokenManager.Hash(result.TrackingToken), mockOrderRepo.LastOrder.TrackingTokenHash)
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+type MockOrderIdempotencyRepository struct {
+	mock.Mock
+}
+
+func (m *MockOrderIdempotencyRepository) ReserveIdempotencyKeyTx(ctx context.Context, tx *sql.Tx, tenantID uint64, key, requestHash string, expiredBefore time.Time) (bool, error) {
+	args := m.Called(ctx, tx, tenantID, key, requestHash, expiredBefore)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockOrderIdempotencyRepository) SetIdempotencyKeyOrderTx(ctx context.Context, tx *sql.Tx, tenantID uint64, key string, orderID uint64) error {
+	args := m.Called(ctx, tx, tenantID, key, orderID)
+	return args.Error(0)
+}
+
+func (m *MockOrderIdempotencyRepository) GetIdempotencyKey(ctx context.Context, tenantID uint64, key string, expiredBefore time.Time) (*oModel.OrderIdempotencyKey, error) {
+	args := m.Called(ctx, tenantID, key, expiredBefore)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*oModel.OrderIdempotencyKey), args.Error(1)
+}
+
+func idempotencyTestPayload() oModel.CreateOrderPayload {
+	return oModel.CreateOrderPayload{
+		Name:              "Cliente Test",
+		Email:             "test@example.com",
+		Phone:             "12345678",
+		DeliveryDirection: "https://maps.app.goo.gl/test-direction-1",
+		Items:             []oModel.CreateOrderItemInput{{IdProduct: 1, Quantity: 2}},
+		DeliveryDate:      "2024-12-25",
+	}
+}
+
+func TestCreateOrderWithIdempotencyKey_FirstRequestReservesKey(t *testing.T) {
+	db, sqlMock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+	sqlMock.ExpectBegin()
+	sqlMock.ExpectCommit()
+
+	idempotency := new(MockOrderIdempotencyRepository)
+	mockProductRepo := &MockProductRepo2{
+		Products:     map[uint64]pModel.Product{1: activeProduct(1, "Pan", 2.50, 10)},
+		StockUpdates: make(map[uint64]uint64),
+	}
+	service := Creator{
+		UserRepo:        &MockUserRepo{ShouldCreate: false},
+		ProductRepo:     mockProductRepo,
+		OrderRepo:       &MockOrderRepo2{DB: db},
+		IdempotencyKeys: idempotency,
+	}
+
+	idempotency.On("GetIdempotencyKey", mock.Anything, uint64(1), "key-1", mock.Anything).Return(nil, nil)
+	idempotency.On("ReserveIdempotencyKeyTx", mock.Anything, mock.Anything, uint64(1), "key-1", mock.AnythingOfType("string"), mock.Anything).Return(true, nil)
+	idempotency.On("SetIdempotencyKeyOrderTx", mock.Anything, mock.Anything, uint64(1), "key-1", uint64(123)).Return(nil)
+
+	payload := idempotencyTestPayload()
+	deliveryDate, _ := time.Parse("2006-01-02", payload.DeliveryDate)
+	result, err := service.CreateOrderWithIdempotencyKey(context.Background(), 1, "key-1", payload, deliveryDate)
+
+	require.NoError(t, err)
+	assert.Equal(t, uint64(123), result.Order.ID)
+	assert.False(t, result.Replayed)
+	assert.Equal(t, uint64(8), mockProductRepo.StockUpdates[1])
+	idempotency.AssertExpectations(t)
+	require.NoError(t, sqlMock.ExpectationsWereMet())
+}
+
+func TestCreateOrderWithIdempotencyKey_RetryReplaysOrderWithoutStockChange(t *testing.T) {
+	payload := idempotencyTestPayload()
+	requestHash, err := hashCreateOrderPayload(payload)
+	require.NoError(t, err)
+
+	idempotency := new(MockOrderIdempotencyRepository)
+	mockProductRepo := &MockProductRepo2{
+		Products:     map[uint64]pModel.Product{1: activeProduct(1, "Pan", 2.50, 10)},
+		StockUpdates: make(map[uint64]uint64),
+	}
+	tokenManager := tokens.NewSHA256OneTimeTokenManager(32)
+	service := Creator{
+		UserRepo:        &MockUserRepo{ShouldCreate: false},
+		ProductRepo:     mockProductRepo,
+		OrderRepo:       &MockOrderRepo2{},
+		IdempotencyKeys: idempotency,
+		TrackingTokens:  tokenManager,
+	}
+
+	orderID := uint64(77)
+	idempotency.On("GetIdempotencyKey", mock.Anything, uint64(1), "key-1", mock.Anything).
+		Return(&oModel.OrderIdempotencyKey{Key: "key-1", RequestHash: requestHash, IDOrder: &orderID}, nil)
+
+	deliveryDate, _ := time.Parse("2006-01-02", payload.DeliveryDate)
+	result, err := service.CreateOrderWithIdempotencyKey(context.Background(), 1, "key-1", payload, deliveryDate)
+
+	require.NoError(t, err)
+	assert.True(t, result.Replayed)
+	assert.Equal(t, orderID, result.Order.ID)
+	assert.Empty(t, result.TrackingToken)
+	assert.Empty(t, mockProductRepo.StockUpdates)
+	idempotency.AssertExpectations(t)
+}
+
+func TestCreateOrderWithIdempotencyKey_KeyReusedWithDifferentBody(t *testing.T) {
+	idempotency := new(MockOrderIdempotencyRepository)
+	service := Creator{
+		UserRepo:        &MockUserRepo{ShouldCreate: false},
+		OrderRepo:       &MockOrderRepo2{},
+		IdempotencyKeys: idempotency,
+	}
+
+	orderID := uint64(77)
+	idempotency.On("GetIdempotencyKey", mock.Anything, uint64(1), "key-1", mock.Anything).
+		Return(&oModel.OrderIdempotencyKey{Key: "key-1", RequestHash: "other", IDOrder: &orderID}, nil)
+
+	payload := idempotencyTestPayload()
+	deliveryDate, _ := time.Parse("2006-01-02", payload.DeliveryDate)
+	_, err := service.CreateOrderWithIdempotencyKey(context.Background(), 1, "key-1", payload, deliveryDate)
+
+	assert.ErrorIs(t, err, internalErrors.ErrIdempotencyKeyReused)
+}
+
+func TestCreateOrderWithIdempotencyKey_ConcurrentRequestCommittedFirst(t *testing.T) {
+	db, sqlMock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+	sqlMock.ExpectBegin()
+	sqlMock.ExpectRollback()
+
+	payload := idempotencyTestPayload()
+	requestHash, err := hashCreateOrderPayload(payload)
+	require.NoError(t, err)
+
+	idempotency := new(MockOrderIdempotencyRepository)
+	mockProductRepo := &MockProductRepo2{
+		Products:     map[uint64]pModel.Product{1: activeProduct(1, "Pan", 2.50, 10)},
+		StockUpdates: make(map[uint64]uint64),
+	}
+	service := Creator{
+		UserRepo:        &MockUserRepo{ShouldCreate: false},
+		ProductRepo:     mockProductRepo,
+		OrderRepo:       &MockOrderRepo2{DB: db},
+		IdempotencyKeys: idempotency,
+	}
+
+	orderID := uint64(77)
+	idempotency.On("GetIdempotencyKey", mock.Anything, uint64(1), "key-1", mock.Anything).Return(nil, nil).Once()
+	idempotency.On("ReserveIdempotencyKeyTx", mock.Anything, mock.Anything, uint64(1), "key-1", requestHash, mock.Anything).Return(false, nil)
+	idempotency.On("GetIdempotencyKey", mock.Anything, uint64(1), "key-1", mock.Anything).
+		Return(&oModel.OrderIdempotencyKey{Key: "key-1", RequestHash: requestHash, IDOrder: &orderID}, nil).Once()
+
+	deliveryDate, _ := time.Parse("2006-01-02", payload.DeliveryDate)
+	result, err := service.CreateOrderWithIdempotencyKey(context.Background(), 1, "key-1", payload, deliveryDate)
+
+	require.NoError(t, err)
+	assert.True(t, result.Replayed)
+	assert.Equal(t, orderID, result.Order.ID)
+	assert.Empty(t, mockProductRepo.StockUpdates)
+	require.NoError(t, sqlMock.ExpectationsWereMet())
+}
+
+type stubPaymentLedger struct {
+	payments []oModel.OrderPaymentRequest
+}
+
+func (s *stubPaymentLedger) CreateOrderPaymentTx(ctx context.Context, tx *sql.Tx, p oModel.OrderPaymentRequest) (oModel.OrderPayment, error) {
+	s.payments = append(s.payments, p)
+	return oModel.OrderPayment{ID: uint64(len(s.payments)), IDOrder: p.IDOrder, Amount: p.Amount, Method: p.Method}, nil
+}
+
+func TestCreateOrderWithOptions_StaffCounterSale(t *testing.T) {
+	db, sqlMock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+	sqlMock.ExpectBegin()
+	sqlMock.ExpectCommit()
+
+	service, orderRepo := promotionCreator(db, nil)
+	userRepo := &MockUserRepo{ShouldCreate: true}
+	service.UserRepo = userRepo
+	ledger := &stubPaymentLedger{}
+	service.PaymentLedger = ledger
+	notifier := new(MockOrderNotifier)
+	service.Notifications = notifier
+	payload := oModel.CreateOrderPayload{
+		FulfillmentType: oModel.FulfillmentPickup,
+		Items:           []oModel.CreateOrderItemInput{{IdProduct: 1, Quantity: 2}},
+	}
+
+	result, err := service.CreateOrderWithOptions(context.Background(), 1, payload, time.Now(), CreateOrderOptions{
+		SalesChannel: oModel.SalesChannelCounter,
+		Staff:        &StaffOrderOptions{UserID: 9, Status: oModel.StatusDelivered, PaidWith: oModel.PaymentMethodCash},
+	})
+
+	require.NoError(t, err)
+	assert.Equal(t, uint64(123), result.Order.ID)
+	assert.False(t, userRepo.UserWasCreated)
+	assert.Equal(t, uint64(0), orderRepo.LastOrder.IdUser)
+	assert.Equal(t, oModel.SalesChannelCounter, orderRepo.LastOrder.SalesChannel)
+	assert.Equal(t, oModel.StatusDelivered, orderRepo.LastOrder.Status)
+	assert.True(t, orderRepo.LastOrder.Paid)
+	assert.True(t, orderRepo.LastOrder.ExpiresAt.IsZero())
+	assert.Nil(t, orderRepo.LastOrder.IDPickupLocation)
+	assert.Equal(t, map[uint64]uint64{1: 8}, service.ProductRepo.(*MockProductRepo2).StockUpdates) // stock 10 - 2
+	assert.True(t, orderRepo.HistoryCreated)
+	require.Len(t, ledger.payments, 1)
+	assert.Equal(t, money.Amount(500), ledger.payments[0].Amount)
+	assert.Equal(t, oModel.PaymentMethodCash, ledger.payments[0].Method)
+	require.NotNil(t, ledger.payments[0].RecordedBy)
+	assert.Equal(t, uint64(9), *ledger.payments[0].RecordedBy)
+	notifier.AssertNotCalled(t, "EnqueueOrderNotificationTx", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	require.NoError(t, sqlMock.ExpectationsWereMet())
+}
+
+func TestCreateOrderWithOptions_StaffOrderWithCustomerQueuesOnlyConfirmation(t *testing.T) {
+	db, sqlMock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+	sqlMock.ExpectBegin()
+	sqlMock.ExpectCommit()
+
+	service, orderRepo := promotionCreator(db, nil)
+	notifier := new(MockOrderNotifier)
+	notifier.On("EnqueueOrderNotificationTx", mock.Anything, mock.Anything, uint64(1), uint64(123), nModel.KindOrderConfirmation).Return(nil)
+	service.Notifications = notifier
+	payload := promotionOrderPayload("")
+	deliveryDate, _ := time.Parse("2006-01-02", payload.DeliveryDate)
+
+	_, err = service.CreateOrderWithOptions(context.Background(), 1, payload, deliveryDate, CreateOrderOptions{
+		SalesChannel: oModel.SalesChannelPhone,
+		Staff:        &StaffOrderOptions{UserID: 9},
+	})
+
+	require.NoError(t, err)
+	assert.Equal(t, uint64(1), orderRepo.LastOrder.IdUser)
+	assert.Equal(t, oModel.StatusPending, orderRepo.LastOrder.Status)
+	assert.False(t, orderRepo.LastOrder.Paid)
+	assert.True(t, orderRepo.LastOrder.ExpiresAt.IsZero())
+	notifier.AssertExpectations(t)
+	notifier.AssertNotCalled(t, "EnqueueOrderNotificationTx", mock.Anything, mock.Anything, mock.Anything, mock.Anything, nModel.KindAdminNewOrder)
+	require.NoError(t, sqlMock.ExpectationsWereMet())
+}
+
+func TestCreateOrderWithOptions_PaidStaffOrderNeedsLedger(t *testing.T) {
+	service, orderRepo := promotionCreator(nil, nil)
+	payload := promotionOrderPayload("")
+
+	_, err := service.CreateOrderWithOptions(context.Background(), 1, payload, time.Now(), CreateOrderOptions{
+		Staff: &StaffOrderOptions{UserID: 9, PaidWith: oModel.PaymentMethodCash},
+	})
+
+	assert.Error(t, err)
+	assert.False(t, orderRepo.OrderCreated)
+}
@@ -0,0 +1,33 @@
+package orders
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/radamesvaz/bakery-app/model/money"
+	whModel "github.com/radamesvaz/bakery-app/model/webhooks"
+)
+
+// OrderEventPublisher writes webhook events to the outbox in the caller's transaction,
+// so an event exists if and only if the change it describes was committed.
+// It is implemented by the webhooks repository.
+type OrderEventPublisher interface {
+	EnqueueEventTx(ctx context.Context, tx *sql.Tx, tenantID uint64, eventType whModel.EventType, data any) error
+	EnqueueOutOfStockTx(ctx context.Context, tx *sql.Tx, tenantID uint64, productIDs []uint64) error
+}
+
+func buildOrderEventData(orderID uint64, status, previousStatus string, price money.Amount, paid bool, deliveryDate time.Time, cancellationReason *string) whModel.OrderEventData {
+	data := whModel.OrderEventData{
+		IDOrder:            orderID,
+		Status:             status,
+		PreviousStatus:     previousStatus,
+		Price:              price,
+		Paid:               paid,
+		CancellationReason: cancellationReason,
+	}
+	if !deliveryDate.IsZero() {
+		data.DeliveryDate = deliveryDate.Format("2006-01-02")
+	}
+	return data
+}
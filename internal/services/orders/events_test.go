@@ -0,0 +1,95 @@
+package orders
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	oModel "github.com/radamesvaz/bakery-app/model/orders"
+	whModel "github.com/radamesvaz/bakery-app/model/webhooks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// MockOrderEventPublisher records webhook events enqueued by the order services
+type MockOrderEventPublisher struct {
+	mock.Mock
+}
+
+func (m *MockOrderEventPublisher) EnqueueEventTx(ctx context.Context, tx *sql.Tx, tenantID uint64, eventType whModel.EventType, data any) error {
+	args := m.Called(ctx, tx, tenantID, eventType, data)
+	return args.Error(0)
+}
+
+func (m *MockOrderEventPublisher) EnqueueOutOfStockTx(ctx context.Context, tx *sql.Tx, tenantID uint64, productIDs []uint64) error {
+	args := m.Called(ctx, tx, tenantID, productIDs)
+	return args.Error(0)
+}
+
+func TestStatusUpdaterWithStock_UpdateOrderStatus_EnqueuesStatusChangedEvent(t *testing.T) {
+	db, sqlMock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+	sqlMock.ExpectBegin()
+	sqlMock.ExpectCommit()
+
+	const tenantID = uint64(1)
+	deliveryDate := time.Date(2026, 5, 10, 0, 0, 0, 0, time.UTC)
+	order := oModel.OrderResponse{ID: 7, TenantID: tenantID, IdUser: 3, Status: oModel.StatusPending, Price: 20, DeliveryDate: deliveryDate}
+
+	mockOrderRepo := &MockOrderStatusRepositoryWithStock{DB: db}
+	mockOrderRepo.On("GetOrderByID", mock.Anything, tenantID, uint64(7)).Return(order, nil)
+	mockOrderRepo.On("UpdateOrderStatusTx", mock.Anything, mock.Anything, tenantID, uint64(7), order.Status, oModel.StatusPreparing, (*string)(nil)).Return(nil)
+	mockOrderRepo.On("CreateOrderHistoryTx", mock.Anything, mock.Anything, mock.Anything).Return(nil)
+
+	events := new(MockOrderEventPublisher)
+	expected := whModel.OrderEventData{
+		IDOrder:        7,
+		Status:         string(oModel.StatusPreparing),
+		PreviousStatus: string(oModel.StatusPending),
+		Price:          20,
+		DeliveryDate:   "2026-05-10",
+	}
+	events.On("EnqueueEventTx", mock.Anything, mock.Anything, tenantID, whModel.EventOrderStatusChanged, expected).Return(nil)
+
+	updater := NewStatusUpdaterWithStock(mockOrderRepo, new(MockProductRepositoryWithStock))
+	updater.Events = events
+
+	err = updater.UpdateOrderStatusWithStockReversion(context.Background(), tenantID, 7, oModel.StatusPreparing, 1, false, nil, nil)
+
+	require.NoError(t, err)
+	mockOrderRepo.AssertNotCalled(t, "UpdateOrderStatus", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	events.AssertExpectations(t)
+	require.NoError(t, sqlMock.ExpectationsWereMet())
+}
+
+func TestStatusUpdaterWithStock_UpdateOrderStatus_EnqueueFailureRollsBack(t *testing.T) {
+	db, sqlMock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+	sqlMock.ExpectBegin()
+	sqlMock.ExpectRollback()
+
+	const tenantID = uint64(1)
+	order := oModel.OrderResponse{ID: 7, TenantID: tenantID, Status: oModel.StatusPending, Price: 20}
+
+	mockOrderRepo := &MockOrderStatusRepositoryWithStock{DB: db}
+	mockOrderRepo.On("GetOrderByID", mock.Anything, tenantID, uint64(7)).Return(order, nil)
+	mockOrderRepo.On("UpdateOrderStatusTx", mock.Anything, mock.Anything, tenantID, uint64(7), order.Status, oModel.StatusPreparing, (*string)(nil)).Return(nil)
+	mockOrderRepo.On("CreateOrderHistoryTx", mock.Anything, mock.Anything, mock.Anything).Return(nil)
+
+	events := new(MockOrderEventPublisher)
+	events.On("EnqueueEventTx", mock.Anything, mock.Anything, tenantID, whModel.EventOrderStatusChanged, mock.Anything).Return(errors.New("outbox down"))
+
+	updater := NewStatusUpdaterWithStock(mockOrderRepo, new(MockProductRepositoryWithStock))
+	updater.Events = events
+
+	err = updater.UpdateOrderStatusWithStockReversion(context.Background(), tenantID, 7, oModel.StatusPreparing, 1, false, nil, nil)
+
+	assert.EqualError(t, err, "outbox down")
+	require.NoError(t, sqlMock.ExpectationsWereMet())
+}
@@ -0,0 +1,205 @@
+package orders
+
+import (
+	"context"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	ordersRepository "github.com/radamesvaz/bakery-app/internal/repository/orders"
+	"github.com/radamesvaz/bakery-app/internal/xlsx"
+	oModel "github.com/radamesvaz/bakery-app/model/orders"
+)
+
+// ExportFormat is the file type of an order export.
+type ExportFormat string
+
+const (
+	ExportFormatCSV  ExportFormat = "csv"
+	ExportFormatXLSX ExportFormat = "xlsx"
+)
+
+// ExportRows selects one row per order or one row per order item.
+type ExportRows string
+
+const (
+	ExportRowsOrders ExportRows = "orders"
+	ExportRowsItems  ExportRows = "items"
+)
+
+// DefaultExportPageSize is how many orders are loaded per page while exporting.
+const DefaultExportPageSize = 200
+
+// OrderPageLister is implemented by the order repository.
+type OrderPageLister interface {
+	ListOrdersWithFiltersPage(ctx context.Context, tenantID uint64, filter ordersRepository.OrderListFilter, limit int, cursor string) (ordersRepository.ListOrdersPageResult, error)
+}
+
+// OrderExporter streams the orders matching a list filter as a spreadsheet, one keyset page at a
+// time, so memory use does not grow with the number of orders.
+type OrderExporter struct {
+	OrderRepo OrderPageLister
+	PageSize  int
+}
+
+func NewOrderExporter(orderRepo OrderPageLister) *OrderExporter {
+	return &OrderExporter{OrderRepo: orderRepo, PageSize: DefaultExportPageSize}
+}
+
+// exportRowWriter is the common surface of the CSV and XLSX writers.
+type exportRowWriter interface {
+	WriteRow(cells ...any) error
+	Flush() error
+	Close() error
+}
+
+var (
+	exportOrderColumns = []string{
+		"Order ID", "Created On", "Delivery Date", "Status", "Customer", "Phone", "Fulfillment", "Delivery Direction",
+		"Note", "Units", "Total", "Paid", "Amount Paid", "Balance Due",
+	}
+	exportItemColumns = []string{
+		"Order ID", "Created On", "Delivery Date", "Status", "Customer", "Phone", "Product ID",
+		"Product", "Unit Price", "Quantity", "Line Total", "Order Total", "Paid",
+	}
+)
+
+// Export writes the header row and every matching order to w, in the order of filter.Sort. The first page
+// is loaded before anything is written, so a failing query leaves w untouched. When w is an
+// http.Flusher it is flushed after each page.
+func (e *OrderExporter) Export(ctx context.Context, w io.Writer, tenantID uint64, filter ordersRepository.OrderListFilter, format ExportFormat, rows ExportRows) error {
+	pageSize := e.PageSize
+	if pageSize < 1 {
+		pageSize = DefaultExportPageSize
+	}
+	page, err := e.OrderRepo.ListOrdersWithFiltersPage(ctx, tenantID, filter, pageSize, "")
+	if err != nil {
+		return err
+	}
+
+	out, err := newExportRowWriter(w, format)
+	if err != nil {
+		return err
+	}
+	columns := exportOrderColumns
+	if rows == ExportRowsItems {
+		columns = exportItemColumns
+	}
+	header := make([]any, len(columns))
+	for i, c := range columns {
+		header[i] = c
+	}
+	if err := out.WriteRow(header...); err != nil {
+		return err
+	}
+
+	for {
+		for _, order := range page.Items {
+			if err := writeExportOrder(out, order, rows); err != nil {
+				return err
+			}
+		}
+		if err := out.Flush(); err != nil {
+			return err
+		}
+		if f, ok := w.(http.Flusher); ok {
+			f.Flush()
+		}
+		if page.NextCursor == nil {
+			break
+		}
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		page, err = e.OrderRepo.ListOrdersWithFiltersPage(ctx, tenantID, filter, pageSize, *page.NextCursor)
+		if err != nil {
+			return err
+		}
+	}
+	return out.Close()
+}
+
+func newExportRowWriter(w io.Writer, format ExportFormat) (exportRowWriter, error) {
+	if format == ExportFormatXLSX {
+		return xlsx.NewWriter(w, "Orders")
+	}
+	return &csvRowWriter{w: csv.NewWriter(w)}, nil
+}
+
+func writeExportOrder(out exportRowWriter, order oModel.OrderResponse, rows ExportRows) error {
+	createdOn := order.CreatedOn.UTC().Format(time.RFC3339)
+	deliveryDate := ""
+	if !order.DeliveryDate.IsZero() {
+		deliveryDate = order.DeliveryDate.Format(time.DateOnly)
+	}
+	if rows == ExportRowsItems {
+		for _, item := range order.OrderItems {
+			err := out.WriteRow(
+				order.ID, createdOn, deliveryDate, string(order.Status), order.User, order.Phone,
+				item.IdProduct, item.Name, item.UnitPrice.Major(), item.Quantity, item.UnitPrice.Times(item.Quantity).Major(),
+				order.Price.Major(), order.Paid,
+			)
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	var units uint64
+	for _, item := range order.OrderItems {
+		units += item.Quantity
+	}
+	return out.WriteRow(
+		order.ID, createdOn, deliveryDate, string(order.Status), order.User, order.Phone, string(order.FulfillmentType), order.DeliveryDirection,
+		order.Note, units, order.Price.Major(), order.Paid, order.AmountPaid.Major(), order.BalanceDue.Major(),
+	)
+}
+
+// csvRowWriter formats cells as text. Strings that a spreadsheet would run as a formula are
+// prefixed with a quote.
+type csvRowWriter struct {
+	w      *csv.Writer
+	record []string
+}
+
+func (c *csvRowWriter) WriteRow(cells ...any) error {
+	c.record = c.record[:0]
+	for _, cell := range cells {
+		c.record = append(c.record, csvCell(cell))
+	}
+	return c.w.Write(c.record)
+}
+
+func (c *csvRowWriter) Flush() error {
+	c.w.Flush()
+	return c.w.Error()
+}
+
+func (c *csvRowWriter) Close() error {
+	return c.Flush()
+}
+
+func csvCell(cell any) string {
+	switch v := cell.(type) {
+	case nil:
+		return ""
+	case string:
+		if v != "" && strings.ContainsRune("=+-@\t\r", rune(v[0])) {
+			return "'" + v
+		}
+		return v
+	case bool:
+		return strconv.FormatBool(v)
+	case uint64:
+		return strconv.FormatUint(v, 10)
+	case float64:
+		return strconv.FormatFloat(v, 'f', 2, 64)
+	default:
+		return fmt.Sprint(v)
+	}
+}
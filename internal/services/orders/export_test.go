@@ -0,0 +1,111 @@
+package orders
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/radamesvaz/bakery-app/internal/pagination"
+	ordersRepository "github.com/radamesvaz/bakery-app/internal/repository/orders"
+	oModel "github.com/radamesvaz/bakery-app/model/orders"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+type MockOrderPageLister struct {
+	mock.Mock
+}
+
+func (m *MockOrderPageLister) ListOrdersWithFiltersPage(ctx context.Context, tenantID uint64, filter ordersRepository.OrderListFilter, limit int, cursor string) (ordersRepository.ListOrdersPageResult, error) {
+	args := m.Called(ctx, tenantID, filter, limit, cursor)
+	return args.Get(0).(ordersRepository.ListOrdersPageResult), args.Error(1)
+}
+
+func exportTestOrder(id uint64, createdOn time.Time) oModel.OrderResponse {
+	return oModel.OrderResponse{
+		ID:              id,
+		User:            "=HYPERLINK(\"x\")",
+		Phone:           "555-0100",
+		Status:          oModel.StatusPending,
+		Price:           2000,
+		CreatedOn:       createdOn,
+		DeliveryDate:    time.Date(2026, 3, 5, 0, 0, 0, 0, time.UTC),
+		FulfillmentType: oModel.FulfillmentPickup,
+		OrderItems: []oModel.OrderItems{
+			{IdProduct: 1, Name: "Bread", UnitPrice: 500, Quantity: 2},
+			{IdProduct: 2, Name: "Cake", UnitPrice: 1000, Quantity: 1},
+		},
+		BalanceDue: 2000,
+	}
+}
+
+func TestOrderExporter_Export_FollowsCursors(t *testing.T) {
+	repo := new(MockOrderPageLister)
+	exporter := &OrderExporter{OrderRepo: repo, PageSize: 1}
+	ctx := context.Background()
+	filter := ordersRepository.OrderListFilter{}
+
+	first := exportTestOrder(1, time.Date(2026, 3, 1, 9, 0, 0, 0, time.UTC))
+	second := exportTestOrder(2, time.Date(2026, 3, 1, 10, 0, 0, 0, time.UTC))
+	cursor, err := pagination.EncodeOrderCursor(first.CreatedOn, first.ID)
+	require.NoError(t, err)
+
+	repo.On("ListOrdersWithFiltersPage", ctx, uint64(1), filter, 1, "").
+		Return(ordersRepository.ListOrdersPageResult{Items: []oModel.OrderResponse{first}, NextCursor: &cursor}, nil)
+	repo.On("ListOrdersWithFiltersPage", ctx, uint64(1), filter, 1, cursor).
+		Return(ordersRepository.ListOrdersPageResult{Items: []oModel.OrderResponse{second}}, nil)
+
+	var buf bytes.Buffer
+	require.NoError(t, exporter.Export(ctx, &buf, 1, filter, ExportFormatCSV, ExportRowsOrders))
+
+	records, err := csv.NewReader(&buf).ReadAll()
+	require.NoError(t, err)
+	require.Len(t, records, 3)
+	assert.Equal(t, exportOrderColumns, records[0])
+	assert.Equal(t, []string{
+		"1", "2026-03-01T09:00:00Z", "2026-03-05", "pending", "'=HYPERLINK(\"x\")", "555-0100", "pickup", "",
+		"", "3", "20.00", "false", "0.00", "20.00",
+	}, records[1])
+	assert.Equal(t, "2", records[2][0])
+	repo.AssertExpectations(t)
+}
+
+func TestOrderExporter_Export_ItemRows(t *testing.T) {
+	repo := new(MockOrderPageLister)
+	exporter := NewOrderExporter(repo)
+	ctx := context.Background()
+	filter := ordersRepository.OrderListFilter{}
+
+	repo.On("ListOrdersWithFiltersPage", ctx, uint64(1), filter, DefaultExportPageSize, "").
+		Return(ordersRepository.ListOrdersPageResult{Items: []oModel.OrderResponse{exportTestOrder(1, time.Now())}}, nil)
+
+	var buf bytes.Buffer
+	require.NoError(t, exporter.Export(ctx, &buf, 1, filter, ExportFormatCSV, ExportRowsItems))
+
+	records, err := csv.NewReader(&buf).ReadAll()
+	require.NoError(t, err)
+	require.Len(t, records, 3)
+	assert.Equal(t, exportItemColumns, records[0])
+	assert.Equal(t, []string{"1", "Bread", "5.00", "2", "10.00"}, records[1][6:11])
+	assert.Equal(t, "Cake", records[2][7])
+	repo.AssertExpectations(t)
+}
+
+func TestOrderExporter_Export_FirstPageErrorWritesNothing(t *testing.T) {
+	repo := new(MockOrderPageLister)
+	exporter := NewOrderExporter(repo)
+	ctx := context.Background()
+	filter := ordersRepository.OrderListFilter{}
+
+	repo.On("ListOrdersWithFiltersPage", ctx, uint64(1), filter, DefaultExportPageSize, "").
+		Return(ordersRepository.ListOrdersPageResult{}, errors.New("db down"))
+
+	var buf bytes.Buffer
+	err := exporter.Export(ctx, &buf, 1, filter, ExportFormatXLSX, ExportRowsOrders)
+	require.Error(t, err)
+	assert.Zero(t, buf.Len())
+}
@@ -0,0 +1,106 @@
+package orders
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	oModel "github.com/radamesvaz/bakery-app/model/orders"
+)
+
+// systemActorName is shown as modified_by_name for rows written by background jobs (modified_by = 0).
+const systemActorName = "system"
+
+// OrderHistoryRepository defines the reads needed to build an order timeline.
+type OrderHistoryRepository interface {
+	GetOrderByID(ctx context.Context, tenantID, id uint64) (oModel.OrderResponse, error)
+	GetOrderHistoryTimeline(ctx context.Context, tenantID, orderID uint64) ([]oModel.OrderHistoryEntry, error)
+}
+
+// HistoryReader builds the audit trail of an order from orders_history.
+type HistoryReader struct {
+	OrderRepo OrderHistoryRepository
+}
+
+func NewHistoryReader(orderRepo OrderHistoryRepository) *HistoryReader {
+	return &HistoryReader{OrderRepo: orderRepo}
+}
+
+// GetOrderTimeline returns every history entry of the order (oldest first) with the acting
+// user's name and the field-level changes versus the previous entry.
+// Returns the repository's not-found error when the order does not belong to the tenant.
+func (h *HistoryReader) GetOrderTimeline(ctx context.Context, tenantID, orderID uint64) (oModel.OrderHistoryTimeline, error) {
+	if _, err := h.OrderRepo.GetOrderByID(ctx, tenantID, orderID); err != nil {
+		return oModel.OrderHistoryTimeline{}, err
+	}
+
+	entries, err := h.OrderRepo.GetOrderHistoryTimeline(ctx, tenantID, orderID)
+	if err != nil {
+		return oModel.OrderHistoryTimeline{}, fmt.Errorf("error getting order history: %w", err)
+	}
+
+	for i := range entries {
+		if entries[i].ModifiedBy == systemModifiedByID {
+			entries[i].ModifiedByName = systemActorName
+		}
+		if i == 0 {
+			entries[i].Changes = []oModel.OrderHistoryChange{}
+			continue
+		}
+		entries[i].Changes = diffOrderHistoryEntries(entries[i-1], entries[i])
+	}
+
+	return oModel.OrderHistoryTimeline{IDOrder: orderID, Items: entries}, nil
+}
+
+// diffOrderHistoryEntries lists the order fields that changed from prev to cur.
+func diffOrderHistoryEntries(prev, cur oModel.OrderHistoryEntry) []oModel.OrderHistoryChange {
+	changes := []oModel.OrderHistoryChange{}
+	if prev.Status != cur.Status {
+		changes = append(changes, oModel.OrderHistoryChange{Field: "status", From: prev.Status, To: cur.Status})
+	}
+	if prev.Paid != cur.Paid {
+		changes = append(changes, oModel.OrderHistoryChange{Field: "paid", From: prev.Paid, To: cur.Paid})
+	}
+	if prev.Price != cur.Price {
+		changes = append(changes, oModel.OrderHistoryChange{Field: "total_price", From: prev.Price, To: cur.Price})
+	}
+	if prev.DiscountAmount != cur.DiscountAmount {
+		changes = append(changes, oModel.OrderHistoryChange{Field: "discount_amount", From: prev.DiscountAmount, To: cur.DiscountAmount})
+	}
+	if stringPtrValue(prev.PromotionCode) != stringPtrValue(cur.PromotionCode) {
+		changes = append(changes, oModel.OrderHistoryChange{Field: "promotion_code", From: prev.PromotionCode, To: cur.PromotionCode})
+	}
+	if prev.Note != cur.Note {
+		changes = append(changes, oModel.OrderHistoryChange{Field: "note", From: prev.Note, To: cur.Note})
+	}
+	if prev.DeliveryDirection != cur.DeliveryDirection {
+		changes = append(changes, oModel.OrderHistoryChange{Field: "delivery_direction", From: prev.DeliveryDirection, To: cur.DeliveryDirection})
+	}
+	if !sameDate(prev.DeliveryDate, cur.DeliveryDate) {
+		changes = append(changes, oModel.OrderHistoryChange{Field: "delivery_date", From: formatDate(prev.DeliveryDate), To: formatDate(cur.DeliveryDate)})
+	}
+	if stringPtrValue(prev.CancellationReason) != stringPtrValue(cur.CancellationReason) {
+		changes = append(changes, oModel.OrderHistoryChange{Field: "cancellation_reason", From: prev.CancellationReason, To: cur.CancellationReason})
+	}
+	return changes
+}
+
+func sameDate(a, b *time.Time) bool {
+	return formatDate(a) == formatDate(b)
+}
+
+// formatDate renders delivery dates as YYYY-MM-DD (the column is a DATE); nil stays nil.
+func formatDate(t *time.Time) interface{} {
+	if t == nil {
+		return nil
+	}
+	return t.Format("2006-01-02")
+}
+
+func stringPtrValue(s *string) string {
+	if s == nil {
+		return ""
+	}
+	return *s
+}
@@ -0,0 +1,84 @@
+package orders
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	appErrors "github.com/radamesvaz/bakery-app/internal/errors"
+	oModel "github.com/radamesvaz/bakery-app/model/orders"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// MockOrderHistoryRepository for testing the order timeline
+type MockOrderHistoryRepository struct {
+	mock.Mock
+}
+
+func (m *MockOrderHistoryRepository) GetOrderByID(ctx context.Context, tenantID, id uint64) (oModel.OrderResponse, error) {
+	args := m.Called(ctx, tenantID, id)
+	return args.Get(0).(oModel.OrderResponse), args.Error(1)
+}
+
+func (m *MockOrderHistoryRepository) GetOrderHistoryTimeline(ctx context.Context, tenantID, orderID uint64) ([]oModel.OrderHistoryEntry, error) {
+	args := m.Called(ctx, tenantID, orderID)
+	return args.Get(0).([]oModel.OrderHistoryEntry), args.Error(1)
+}
+
+func TestHistoryReader_GetOrderTimeline_ComputesChanges(t *testing.T) {
+	repo := new(MockOrderHistoryRepository)
+	reader := NewHistoryReader(repo)
+	ctx := context.Background()
+
+	firstDate := time.Date(2026, 1, 10, 0, 0, 0, 0, time.UTC)
+	secondDate := time.Date(2026, 1, 12, 0, 0, 0, 0, time.UTC)
+	reason := "expired"
+
+	repo.On("GetOrderByID", ctx, uint64(1), uint64(7)).Return(oModel.OrderResponse{ID: 7}, nil)
+	repo.On("GetOrderHistoryTimeline", ctx, uint64(1), uint64(7)).Return([]oModel.OrderHistoryEntry{
+		{ID: 1, Action: oModel.ActionCreate, Status: oModel.StatusPending, Price: 20, DeliveryDate: &firstDate, ModifiedBy: 3, ModifiedByName: "Ana"},
+		{ID: 2, Action: oModel.ActionUpdate, Status: oModel.StatusPreparing, Price: 20, Paid: true, DeliveryDate: &secondDate, ModifiedBy: 3, ModifiedByName: "Ana"},
+		{ID: 3, Action: oModel.ActionUpdate, Status: oModel.StatusCancelled, Price: 20, Paid: true, DeliveryDate: &secondDate, CancellationReason: &reason, ModifiedBy: systemModifiedByID},
+	}, nil)
+
+	timeline, err := reader.GetOrderTimeline(ctx, 1, 7)
+	require.NoError(t, err)
+	assert.Equal(t, uint64(7), timeline.IDOrder)
+	require.Len(t, timeline.Items, 3)
+
+	assert.Empty(t, timeline.Items[0].Changes)
+	assert.NotNil(t, timeline.Items[0].Changes)
+
+	assert.Equal(t, []oModel.OrderHistoryChange{
+		{Field: "status", From: oModel.StatusPending, To: oModel.StatusPreparing},
+		{Field: "paid", From: false, To: true},
+		{Field: "delivery_date", From: "2026-01-10", To: "2026-01-12"},
+	}, timeline.Items[1].Changes)
+
+	assert.Equal(t, systemActorName, timeline.Items[2].ModifiedByName)
+	require.Len(t, timeline.Items[2].Changes, 2)
+	assert.Equal(t, "status", timeline.Items[2].Changes[0].Field)
+	assert.Equal(t, "cancellation_reason", timeline.Items[2].Changes[1].Field)
+
+	repo.AssertExpectations(t)
+}
+
+func TestHistoryReader_GetOrderTimeline_OrderNotFound(t *testing.T) {
+	repo := new(MockOrderHistoryRepository)
+	reader := NewHistoryReader(repo)
+	ctx := context.Background()
+
+	repo.On("GetOrderByID", ctx, uint64(1), uint64(99)).
+		Return(oModel.OrderResponse{}, appErrors.NewNotFound(appErrors.ErrOrderNotFound))
+
+	_, err := reader.GetOrderTimeline(ctx, 1, 99)
+	require.Error(t, err)
+
+	var httpErr *appErrors.HTTPError
+	require.ErrorAs(t, err, &httpErr)
+	assert.Equal(t, http.StatusNotFound, httpErr.StatusCode)
+	repo.AssertNotCalled(t, "GetOrderHistoryTimeline", mock.Anything, mock.Anything, mock.Anything)
+}
@@ -0,0 +1,30 @@
+package orders
+
+import (
+	"context"
+	"database/sql"
+
+	nModel "github.com/radamesvaz/bakery-app/model/notifications"
+	oModel "github.com/radamesvaz/bakery-app/model/orders"
+)
+
+// OrderNotifier queues order emails in the caller's transaction; a background worker sends them
+// after commit so the transaction never waits on the email provider. It is implemented by the
+// notifications repository, which also skips kinds the tenant disabled.
+type OrderNotifier interface {
+	EnqueueOrderNotificationTx(ctx context.Context, tx *sql.Tx, tenantID, orderID uint64, kind nModel.Kind) error
+}
+
+// statusNotificationKind returns the customer notice for an order entering status, if any.
+func statusNotificationKind(status oModel.OrderStatus) (nModel.Kind, bool) {
+	switch status {
+	case oModel.StatusReady:
+		return nModel.KindOrderReady, true
+	case oModel.StatusDelivered:
+		return nModel.KindOrderDelivered, true
+	case oModel.StatusCancelled:
+		return nModel.KindOrderCancelled, true
+	default:
+		return "", false
+	}
+}
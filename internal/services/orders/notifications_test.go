@@ -0,0 +1,145 @@
+package orders
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	nModel "github.com/radamesvaz/bakery-app/model/notifications"
+	oModel "github.com/radamesvaz/bakery-app/model/orders"
+	pModel "github.com/radamesvaz/bakery-app/model/products"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// MockOrderNotifier records order emails queued by the order services
+type MockOrderNotifier struct {
+	mock.Mock
+}
+
+func (m *MockOrderNotifier) EnqueueOrderNotificationTx(ctx context.Context, tx *sql.Tx, tenantID, orderID uint64, kind nModel.Kind) error {
+	args := m.Called(ctx, tx, tenantID, orderID, kind)
+	return args.Error(0)
+}
+
+func TestCreator_CreateOrder_QueuesConfirmationAndAdminAlert(t *testing.T) {
+	db, sqlMock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+	sqlMock.ExpectBegin()
+	sqlMock.ExpectCommit()
+
+	notifier := new(MockOrderNotifier)
+	notifier.On("EnqueueOrderNotificationTx", mock.Anything, mock.Anything, uint64(1), mock.Anything, nModel.KindOrderConfirmation).Return(nil)
+	notifier.On("EnqueueOrderNotificationTx", mock.Anything, mock.Anything, uint64(1), mock.Anything, nModel.KindAdminNewOrder).Return(nil)
+
+	service := Creator{
+		UserRepo: &MockUserRepo{ShouldCreate: false},
+		ProductRepo: &MockProductRepo2{
+			Products:     map[uint64]pModel.Product{1: activeProduct(1, "Pan", 2.50, 10)},
+			StockUpdates: make(map[uint64]uint64),
+		},
+		OrderRepo:     &MockOrderRepo2{DB: db},
+		Notifications: notifier,
+	}
+
+	payload := oModel.CreateOrderPayload{
+		Name:              "Cliente Test",
+		Email:             "test@example.com",
+		Phone:             "12345678",
+		DeliveryDirection: "https://maps.app.goo.gl/test-direction-1",
+		Items:             []oModel.CreateOrderItemInput{{IdProduct: 1, Quantity: 1}},
+		DeliveryDate:      "2024-12-25",
+	}
+	_, err = service.CreateOrder(context.Background(), 1, payload, time.Date(2024, 12, 25, 0, 0, 0, 0, time.UTC))
+
+	require.NoError(t, err)
+	notifier.AssertExpectations(t)
+	require.NoError(t, sqlMock.ExpectationsWereMet())
+}
+
+func TestStatusUpdaterWithStock_UpdateOrderStatus_QueuesReadyEmail(t *testing.T) {
+	db, sqlMock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+	sqlMock.ExpectBegin()
+	sqlMock.ExpectCommit()
+
+	const tenantID = uint64(1)
+	order := oModel.OrderResponse{ID: 7, TenantID: tenantID, Status: oModel.StatusPreparing, Price: 20}
+
+	mockOrderRepo := &MockOrderStatusRepositoryWithStock{DB: db}
+	mockOrderRepo.On("GetOrderByID", mock.Anything, tenantID, uint64(7)).Return(order, nil)
+	mockOrderRepo.On("UpdateOrderStatusTx", mock.Anything, mock.Anything, tenantID, uint64(7), order.Status, oModel.StatusReady, (*string)(nil)).Return(nil)
+	mockOrderRepo.On("CreateOrderHistoryTx", mock.Anything, mock.Anything, mock.Anything).Return(nil)
+
+	notifier := new(MockOrderNotifier)
+	notifier.On("EnqueueOrderNotificationTx", mock.Anything, mock.Anything, tenantID, uint64(7), nModel.KindOrderReady).Return(nil)
+
+	updater := NewStatusUpdaterWithStock(mockOrderRepo, new(MockProductRepositoryWithStock))
+	updater.Notifications = notifier
+
+	err = updater.UpdateOrderStatusWithStockReversion(context.Background(), tenantID, 7, oModel.StatusReady, 1, true, nil, nil)
+
+	require.NoError(t, err)
+	notifier.AssertExpectations(t)
+	require.NoError(t, sqlMock.ExpectationsWereMet())
+}
+
+func TestStatusUpdaterWithStock_UpdateOrderStatus_NoEmailForPreparing(t *testing.T) {
+	db, sqlMock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+	sqlMock.ExpectBegin()
+	sqlMock.ExpectCommit()
+
+	const tenantID = uint64(1)
+	order := oModel.OrderResponse{ID: 7, TenantID: tenantID, Status: oModel.StatusPending, Price: 20}
+
+	mockOrderRepo := &MockOrderStatusRepositoryWithStock{DB: db}
+	mockOrderRepo.On("GetOrderByID", mock.Anything, tenantID, uint64(7)).Return(order, nil)
+	mockOrderRepo.On("UpdateOrderStatusTx", mock.Anything, mock.Anything, tenantID, uint64(7), order.Status, oModel.StatusPreparing, (*string)(nil)).Return(nil)
+	mockOrderRepo.On("CreateOrderHistoryTx", mock.Anything, mock.Anything, mock.Anything).Return(nil)
+
+	notifier := new(MockOrderNotifier)
+
+	updater := NewStatusUpdaterWithStock(mockOrderRepo, new(MockProductRepositoryWithStock))
+	updater.Notifications = notifier
+
+	err = updater.UpdateOrderStatusWithStockReversion(context.Background(), tenantID, 7, oModel.StatusPreparing, 1, true, nil, nil)
+
+	require.NoError(t, err)
+	notifier.AssertNotCalled(t, "EnqueueOrderNotificationTx", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	require.NoError(t, sqlMock.ExpectationsWereMet())
+}
+
+func TestStatusUpdaterWithStock_UpdateOrderStatus_EmailEnqueueFailureRollsBack(t *testing.T) {
+	db, sqlMock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+	sqlMock.ExpectBegin()
+	sqlMock.ExpectRollback()
+
+	const tenantID = uint64(1)
+	order := oModel.OrderResponse{ID: 7, TenantID: tenantID, Status: oModel.StatusReady, Price: 20}
+
+	mockOrderRepo := &MockOrderStatusRepositoryWithStock{DB: db}
+	mockOrderRepo.On("GetOrderByID", mock.Anything, tenantID, uint64(7)).Return(order, nil)
+	mockOrderRepo.On("UpdateOrderStatusTx", mock.Anything, mock.Anything, tenantID, uint64(7), order.Status, oModel.StatusDelivered, (*string)(nil)).Return(nil)
+	mockOrderRepo.On("CreateOrderHistoryTx", mock.Anything, mock.Anything, mock.Anything).Return(nil)
+
+	notifier := new(MockOrderNotifier)
+	notifier.On("EnqueueOrderNotificationTx", mock.Anything, mock.Anything, tenantID, uint64(7), nModel.KindOrderDelivered).Return(errors.New("queue down"))
+
+	updater := NewStatusUpdaterWithStock(mockOrderRepo, new(MockProductRepositoryWithStock))
+	updater.Notifications = notifier
+
+	err = updater.UpdateOrderStatusWithStockReversion(context.Background(), tenantID, 7, oModel.StatusDelivered, 1, true, nil, nil)
+
+	assert.EqualError(t, err, "queue down")
+	require.NoError(t, sqlMock.ExpectationsWereMet())
+}
@@ -0,0 +1,99 @@
+package orders
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/radamesvaz/bakery-app/internal/errors"
+	oModel "github.com/radamesvaz/bakery-app/model/orders"
+)
+
+// OrderPaymentsRepository defines the order operations needed to keep the payments ledger
+type OrderPaymentsRepository interface {
+	BeginTx(ctx context.Context) (*sql.Tx, error)
+	GetOrderByID(ctx context.Context, tenantID, id uint64) (oModel.OrderResponse, error)
+	LockOrderPaymentStateTx(ctx context.Context, tx *sql.Tx, tenantID, orderID uint64) (oModel.OrderPaymentState, error)
+	CreateOrderPaymentTx(ctx context.Context, tx *sql.Tx, p oModel.OrderPaymentRequest) (oModel.OrderPayment, error)
+	UpdateOrderPaidStatusTx(ctx context.Context, tx *sql.Tx, tenantID, orderID uint64, paid bool) error
+	CreateOrderHistoryTx(ctx context.Context, tx *sql.Tx, order oModel.OrderHistory) error
+}
+
+// PaymentRecorder records deposits, balances and refunds against an order.
+type PaymentRecorder struct {
+	OrderRepo OrderPaymentsRepository
+}
+
+func NewPaymentRecorder(orderRepo OrderPaymentsRepository) *PaymentRecorder {
+	return &PaymentRecorder{OrderRepo: orderRepo}
+}
+
+// RecordPayment appends a payment (or refund when isRefund) to the ledger under the order row lock.
+// Payments cannot exceed the balance due and are rejected for closed orders; refunds cannot exceed
+// the net amount paid. orders.paid follows the ledger: true once the net amount covers the total.
+// A history row is written whenever paid flips.
+func (p *PaymentRecorder) RecordPayment(ctx context.Context, tenantID, orderID uint64, payload oModel.RecordOrderPaymentPayload, isRefund bool, userID uint64) (oModel.OrderResponse, error) {
+	order, err := p.OrderRepo.GetOrderByID(ctx, tenantID, orderID)
+	if err != nil {
+		return oModel.OrderResponse{}, err
+	}
+
+	tx, err := p.OrderRepo.BeginTx(ctx)
+	if err != nil {
+		return oModel.OrderResponse{}, fmt.Errorf("error beginning transaction: %w", err)
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	state, err := p.OrderRepo.LockOrderPaymentStateTx(ctx, tx, tenantID, orderID)
+	if err != nil {
+		return oModel.OrderResponse{}, err
+	}
+
+	amountPaid := state.AmountPaid
+	if isRefund {
+		if payload.Amount > amountPaid {
+			return oModel.OrderResponse{}, errors.ErrRefundExceedsAmountPaid
+		}
+		amountPaid -= payload.Amount
+	} else {
+		if oModel.IsClosedStatus(state.Status) {
+			return oModel.OrderResponse{}, errors.ErrOrderNotPayable
+		}
+		balanceDue, _ := oModel.ComputeBalance(state.Status, state.TotalPrice, state.AmountPaid)
+		if payload.Amount > balanceDue {
+			return oModel.OrderResponse{}, errors.ErrPaymentExceedsBalance
+		}
+		amountPaid += payload.Amount
+	}
+
+	recordedBy := userID
+	if _, err := p.OrderRepo.CreateOrderPaymentTx(ctx, tx, oModel.OrderPaymentRequest{
+		TenantID:   tenantID,
+		IDOrder:    orderID,
+		Amount:     payload.Amount,
+		Method:     payload.Method,
+		Reference:  payload.Reference,
+		IsRefund:   isRefund,
+		RecordedBy: &recordedBy,
+	}); err != nil {
+		return oModel.OrderResponse{}, err
+	}
+
+	paid := oModel.IsFullyPaid(state.TotalPrice, amountPaid)
+	if paid != state.Paid {
+		if err := p.OrderRepo.UpdateOrderPaidStatusTx(ctx, tx, tenantID, orderID, paid); err != nil {
+			return oModel.OrderResponse{}, err
+		}
+		order.Price = state.TotalPrice
+		history := buildStatusUpdateHistory(tenantID, orderID, order, state.Status, userID, order.CancellationReason, paid)
+		if err := p.OrderRepo.CreateOrderHistoryTx(ctx, tx, history); err != nil {
+			return oModel.OrderResponse{}, fmt.Errorf("error creating order history: %w", err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return oModel.OrderResponse{}, fmt.Errorf("error committing transaction: %w", err)
+	}
+
+	return p.OrderRepo.GetOrderByID(ctx, tenantID, orderID)
+}
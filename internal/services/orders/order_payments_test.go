@@ -0,0 +1,168 @@
+package orders
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/radamesvaz/bakery-app/internal/errors"
+	"github.com/radamesvaz/bakery-app/model/money"
+	oModel "github.com/radamesvaz/bakery-app/model/orders"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+type MockOrderPaymentsRepository struct {
+	mock.Mock
+	DB *sql.DB
+}
+
+func (m *MockOrderPaymentsRepository) BeginTx(ctx context.Context) (*sql.Tx, error) {
+	return m.DB.BeginTx(ctx, nil)
+}
+
+func (m *MockOrderPaymentsRepository) GetOrderByID(ctx context.Context, tenantID, id uint64) (oModel.OrderResponse, error) {
+	args := m.Called(ctx, tenantID, id)
+	return args.Get(0).(oModel.OrderResponse), args.Error(1)
+}
+
+func (m *MockOrderPaymentsRepository) LockOrderPaymentStateTx(ctx context.Context, tx *sql.Tx, tenantID, orderID uint64) (oModel.OrderPaymentState, error) {
+	args := m.Called(ctx, tx, tenantID, orderID)
+	return args.Get(0).(oModel.OrderPaymentState), args.Error(1)
+}
+
+func (m *MockOrderPaymentsRepository) CreateOrderPaymentTx(ctx context.Context, tx *sql.Tx, p oModel.OrderPaymentRequest) (oModel.OrderPayment, error) {
+	args := m.Called(ctx, tx, p)
+	return args.Get(0).(oModel.OrderPayment), args.Error(1)
+}
+
+func (m *MockOrderPaymentsRepository) UpdateOrderPaidStatusTx(ctx context.Context, tx *sql.Tx, tenantID, orderID uint64, paid bool) error {
+	args := m.Called(ctx, tx, tenantID, orderID, paid)
+	return args.Error(0)
+}
+
+func (m *MockOrderPaymentsRepository) CreateOrderHistoryTx(ctx context.Context, tx *sql.Tx, order oModel.OrderHistory) error {
+	args := m.Called(ctx, tx, order)
+	return args.Error(0)
+}
+
+func newPaymentRecorderForTest(t *testing.T) (*PaymentRecorder, *MockOrderPaymentsRepository, sqlmock.Sqlmock) {
+	db, sqlMock, err := sqlmock.New()
+	require.NoError(t, err)
+	t.Cleanup(func() { db.Close() })
+	repo := &MockOrderPaymentsRepository{DB: db}
+	return NewPaymentRecorder(repo), repo, sqlMock
+}
+
+func TestPaymentRecorder_RecordPayment_DepositKeepsOrderUnpaid(t *testing.T) {
+	recorder, repo, sqlMock := newPaymentRecorderForTest(t)
+	sqlMock.ExpectBegin()
+	sqlMock.ExpectCommit()
+
+	order := oModel.OrderResponse{ID: 1, TenantID: 1, Status: oModel.StatusPending, Price: 5000}
+	repo.On("GetOrderByID", mock.Anything, uint64(1), uint64(1)).Return(order, nil)
+	repo.On("LockOrderPaymentStateTx", mock.Anything, mock.Anything, uint64(1), uint64(1)).
+		Return(oModel.OrderPaymentState{Status: oModel.StatusPending, TotalPrice: 5000}, nil)
+	repo.On("CreateOrderPaymentTx", mock.Anything, mock.Anything, mock.MatchedBy(func(p oModel.OrderPaymentRequest) bool {
+		return p.Amount == 2000 && p.Method == oModel.PaymentMethodCash && !p.IsRefund && p.RecordedBy != nil && *p.RecordedBy == 9
+	})).Return(oModel.OrderPayment{ID: 1}, nil)
+
+	_, err := recorder.RecordPayment(context.Background(), 1, 1, oModel.RecordOrderPaymentPayload{Amount: 2000, Method: oModel.PaymentMethodCash}, false, 9)
+
+	require.NoError(t, err)
+	repo.AssertNotCalled(t, "UpdateOrderPaidStatusTx", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	require.NoError(t, sqlMock.ExpectationsWereMet())
+}
+
+func TestPaymentRecorder_RecordPayment_BalanceMarksOrderPaid(t *testing.T) {
+	recorder, repo, sqlMock := newPaymentRecorderForTest(t)
+	sqlMock.ExpectBegin()
+	sqlMock.ExpectCommit()
+
+	order := oModel.OrderResponse{ID: 1, TenantID: 1, Status: oModel.StatusReady, Price: 5000}
+	repo.On("GetOrderByID", mock.Anything, uint64(1), uint64(1)).Return(order, nil)
+	repo.On("LockOrderPaymentStateTx", mock.Anything, mock.Anything, uint64(1), uint64(1)).
+		Return(oModel.OrderPaymentState{Status: oModel.StatusReady, TotalPrice: 5000, AmountPaid: 2000}, nil)
+	repo.On("CreateOrderPaymentTx", mock.Anything, mock.Anything, mock.Anything).Return(oModel.OrderPayment{ID: 2}, nil)
+	repo.On("UpdateOrderPaidStatusTx", mock.Anything, mock.Anything, uint64(1), uint64(1), true).Return(nil)
+	repo.On("CreateOrderHistoryTx", mock.Anything, mock.Anything, mock.MatchedBy(func(h oModel.OrderHistory) bool {
+		return h.Paid && h.Status == oModel.StatusReady && h.ModifiedBy == 9
+	})).Return(nil)
+
+	_, err := recorder.RecordPayment(context.Background(), 1, 1, oModel.RecordOrderPaymentPayload{Amount: 3000, Method: oModel.PaymentMethodCard}, false, 9)
+
+	require.NoError(t, err)
+	repo.AssertExpectations(t)
+	require.NoError(t, sqlMock.ExpectationsWereMet())
+}
+
+func TestPaymentRecorder_RecordPayment_RefundClearsPaid(t *testing.T) {
+	recorder, repo, sqlMock := newPaymentRecorderForTest(t)
+	sqlMock.ExpectBegin()
+	sqlMock.ExpectCommit()
+
+	order := oModel.OrderResponse{ID: 1, TenantID: 1, Status: oModel.StatusCancelled, Price: 5000, Paid: true}
+	repo.On("GetOrderByID", mock.Anything, uint64(1), uint64(1)).Return(order, nil)
+	repo.On("LockOrderPaymentStateTx", mock.Anything, mock.Anything, uint64(1), uint64(1)).
+		Return(oModel.OrderPaymentState{Status: oModel.StatusCancelled, TotalPrice: 5000, Paid: true, AmountPaid: 5000}, nil)
+	repo.On("CreateOrderPaymentTx", mock.Anything, mock.Anything, mock.MatchedBy(func(p oModel.OrderPaymentRequest) bool {
+		return p.IsRefund && p.Amount == 5000
+	})).Return(oModel.OrderPayment{ID: 3}, nil)
+	repo.On("UpdateOrderPaidStatusTx", mock.Anything, mock.Anything, uint64(1), uint64(1), false).Return(nil)
+	repo.On("CreateOrderHistoryTx", mock.Anything, mock.Anything, mock.Anything).Return(nil)
+
+	_, err := recorder.RecordPayment(context.Background(), 1, 1, oModel.RecordOrderPaymentPayload{Amount: 5000, Method: oModel.PaymentMethodTransfer}, true, 9)
+
+	require.NoError(t, err)
+	repo.AssertExpectations(t)
+	require.NoError(t, sqlMock.ExpectationsWereMet())
+}
+
+func TestPaymentRecorder_RecordPayment_Rejections(t *testing.T) {
+	tests := []struct {
+		name     string
+		state    oModel.OrderPaymentState
+		amount   money.Amount
+		isRefund bool
+		wantErr  error
+	}{
+		{
+			name:    "payment exceeds balance",
+			state:   oModel.OrderPaymentState{Status: oModel.StatusPending, TotalPrice: 5000, AmountPaid: 4000},
+			amount:  1001,
+			wantErr: errors.ErrPaymentExceedsBalance,
+		},
+		{
+			name:    "payment on cancelled order",
+			state:   oModel.OrderPaymentState{Status: oModel.StatusCancelled, TotalPrice: 5000},
+			amount:  1000,
+			wantErr: errors.ErrOrderNotPayable,
+		},
+		{
+			name:     "refund exceeds amount paid",
+			state:    oModel.OrderPaymentState{Status: oModel.StatusCancelled, TotalPrice: 5000, AmountPaid: 2000},
+			amount:   2500,
+			isRefund: true,
+			wantErr:  errors.ErrRefundExceedsAmountPaid,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			recorder, repo, sqlMock := newPaymentRecorderForTest(t)
+			sqlMock.ExpectBegin()
+			sqlMock.ExpectRollback()
+
+			repo.On("GetOrderByID", mock.Anything, uint64(1), uint64(1)).Return(oModel.OrderResponse{ID: 1}, nil)
+			repo.On("LockOrderPaymentStateTx", mock.Anything, mock.Anything, uint64(1), uint64(1)).Return(tt.state, nil)
+
+			_, err := recorder.RecordPayment(context.Background(), 1, 1, oModel.RecordOrderPaymentPayload{Amount: tt.amount, Method: oModel.PaymentMethodCash}, tt.isRefund, 9)
+
+			assert.ErrorIs(t, err, tt.wantErr)
+			repo.AssertNotCalled(t, "CreateOrderPaymentTx", mock.Anything, mock.Anything, mock.Anything)
+			require.NoError(t, sqlMock.ExpectationsWereMet())
+		})
+	}
+}
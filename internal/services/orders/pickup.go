@@ -0,0 +1,39 @@
+package orders
+
+import (
+	"context"
+	"database/sql"
+	stdErrors "errors"
+	"time"
+
+	"github.com/radamesvaz/bakery-app/internal/errors"
+	pickupModel "github.com/radamesvaz/bakery-app/model/pickup"
+)
+
+// PickupLocationRepository looks up the tenant's pickup locations in the order transaction.
+// It is implemented by the pickup repository.
+type PickupLocationRepository interface {
+	GetLocationTx(ctx context.Context, tx *sql.Tx, tenantID, id uint64) (pickupModel.Location, error)
+}
+
+// checkPickupLocationTx verifies that a pickup order can be collected at location id on
+// deliveryDate: the location must belong to the tenant, be active and be open that weekday.
+func checkPickupLocationTx(ctx context.Context, repo PickupLocationRepository, tx *sql.Tx, tenantID, id uint64, deliveryDate time.Time) error {
+	if repo == nil {
+		return errors.ErrPickupLocationInvalid
+	}
+	location, err := repo.GetLocationTx(ctx, tx, tenantID, id)
+	if err != nil {
+		if stdErrors.Is(err, errors.ErrPickupLocationNotFound) {
+			return errors.ErrPickupLocationInvalid
+		}
+		return err
+	}
+	if !location.Active {
+		return errors.ErrPickupLocationInvalid
+	}
+	if !location.OpenOn(deliveryDate) {
+		return errors.ErrPickupLocationClosed
+	}
+	return nil
+}
@@ -0,0 +1,123 @@
+package orders
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	internalErrors "github.com/radamesvaz/bakery-app/internal/errors"
+	"github.com/radamesvaz/bakery-app/model/money"
+	oModel "github.com/radamesvaz/bakery-app/model/orders"
+	pickupModel "github.com/radamesvaz/bakery-app/model/pickup"
+	pricingModel "github.com/radamesvaz/bakery-app/model/pricing"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type stubPickupLocationRepository map[uint64]pickupModel.Location
+
+func (s stubPickupLocationRepository) GetLocationTx(ctx context.Context, tx *sql.Tx, tenantID, id uint64) (pickupModel.Location, error) {
+	location, ok := s[id]
+	if !ok {
+		return pickupModel.Location{}, internalErrors.NewNotFound(internalErrors.ErrPickupLocationNotFound)
+	}
+	return location, nil
+}
+
+// pickupLocations has an active location open on Wednesdays (the payload's delivery date), one
+// open only on Mondays and an inactive one.
+var pickupLocations = stubPickupLocationRepository{
+	5: {ID: 5, Name: "Centro", Active: true, OpeningHours: []pickupModel.OpeningHours{{Weekday: 3, Opens: "09:00", Closes: "18:00"}}},
+	6: {ID: 6, Name: "Norte", Active: true, OpeningHours: []pickupModel.OpeningHours{{Weekday: 1, Opens: "09:00", Closes: "18:00"}}},
+	7: {ID: 7, Name: "Sur", Active: false, OpeningHours: []pickupModel.OpeningHours{{Weekday: 3, Opens: "09:00", Closes: "18:00"}}},
+}
+
+func pickupOrderPayload(locationID uint64) oModel.CreateOrderPayload {
+	payload := promotionOrderPayload("")
+	payload.FulfillmentType = oModel.FulfillmentPickup
+	payload.IDPickupLocation = &locationID
+	return payload
+}
+
+func TestCreateOrder_PickupSkipsDeliveryFee(t *testing.T) {
+	db, sqlMock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+	sqlMock.ExpectBegin()
+	sqlMock.ExpectCommit()
+
+	service, orderRepo := promotionCreator(db, nil)
+	service.Pricing = stubPricingRepository{settings: pricingModel.Settings{TaxRate: 10, DeliveryFee: 200}}
+	service.PickupLocations = pickupLocations
+	payload := pickupOrderPayload(5)
+	deliveryDate, _ := time.Parse("2006-01-02", payload.DeliveryDate)
+
+	_, err = service.CreateOrder(context.Background(), 1, payload, deliveryDate)
+
+	require.NoError(t, err)
+	assert.Equal(t, oModel.FulfillmentPickup, orderRepo.LastOrder.FulfillmentType)
+	require.NotNil(t, orderRepo.LastOrder.IDPickupLocation)
+	assert.Equal(t, uint64(5), *orderRepo.LastOrder.IDPickupLocation)
+	assert.Empty(t, orderRepo.LastOrder.DeliveryDirection)
+	assert.Equal(t, money.Amount(0), orderRepo.LastOrder.DeliveryFee)
+	assert.Equal(t, money.Amount(118), orderRepo.LastOrder.TaxAmount)
+	assert.Equal(t, money.Amount(1298), orderRepo.LastOrder.Price)
+	require.NoError(t, sqlMock.ExpectationsWereMet())
+}
+
+func TestCreateOrder_DeliveryIsTheDefaultFulfillment(t *testing.T) {
+	db, sqlMock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+	sqlMock.ExpectBegin()
+	sqlMock.ExpectCommit()
+
+	service, orderRepo := promotionCreator(db, nil)
+	payload := promotionOrderPayload("")
+	deliveryDate, _ := time.Parse("2006-01-02", payload.DeliveryDate)
+
+	_, err = service.CreateOrder(context.Background(), 1, payload, deliveryDate)
+
+	require.NoError(t, err)
+	assert.Equal(t, oModel.FulfillmentDelivery, orderRepo.LastOrder.FulfillmentType)
+	assert.Nil(t, orderRepo.LastOrder.IDPickupLocation)
+	assert.Equal(t, payload.DeliveryDirection, orderRepo.LastOrder.DeliveryDirection)
+	require.NoError(t, sqlMock.ExpectationsWereMet())
+}
+
+func TestCreateOrder_RejectsUnavailablePickupLocation(t *testing.T) {
+	tests := []struct {
+		name       string
+		locations  PickupLocationRepository
+		locationID uint64
+		wantErr    error
+	}{
+		{name: "unknown location", locations: pickupLocations, locationID: 9, wantErr: internalErrors.ErrPickupLocationInvalid},
+		{name: "inactive location", locations: pickupLocations, locationID: 7, wantErr: internalErrors.ErrPickupLocationInvalid},
+		{name: "closed on delivery date", locations: pickupLocations, locationID: 6, wantErr: internalErrors.ErrPickupLocationClosed},
+		{name: "pickup not configured", locations: nil, locationID: 5, wantErr: internalErrors.ErrPickupLocationInvalid},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db, sqlMock, err := sqlmock.New()
+			require.NoError(t, err)
+			defer db.Close()
+			sqlMock.ExpectBegin()
+			sqlMock.ExpectRollback()
+
+			service, orderRepo := promotionCreator(db, nil)
+			service.PickupLocations = tt.locations
+			payload := pickupOrderPayload(tt.locationID)
+			deliveryDate, _ := time.Parse("2006-01-02", payload.DeliveryDate)
+
+			_, err = service.CreateOrder(context.Background(), 1, payload, deliveryDate)
+
+			assert.ErrorIs(t, err, tt.wantErr)
+			assert.False(t, orderRepo.OrderCreated)
+			require.NoError(t, sqlMock.ExpectationsWereMet())
+		})
+	}
+}
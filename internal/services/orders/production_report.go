@@ -0,0 +1,131 @@
+package orders
+
+import (
+	"context"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	oModel "github.com/radamesvaz/bakery-app/model/orders"
+)
+
+// ProductionReportRepository is implemented by the order repository.
+type ProductionReportRepository interface {
+	GetProductionReportRows(ctx context.Context, tenantID uint64, date time.Time) ([]oModel.ProductionReportRow, error)
+}
+
+// ProductionReporter builds the kitchen production plan of a delivery date.
+type ProductionReporter struct {
+	OrderRepo ProductionReportRepository
+}
+
+func NewProductionReporter(orderRepo ProductionReportRepository) *ProductionReporter {
+	return &ProductionReporter{OrderRepo: orderRepo}
+}
+
+// GetProductionReport sums the quantities of every product to bake for date.
+func (p *ProductionReporter) GetProductionReport(ctx context.Context, tenantID uint64, date time.Time) (oModel.ProductionReport, error) {
+	rows, err := p.OrderRepo.GetProductionReportRows(ctx, tenantID, date)
+	if err != nil {
+		return oModel.ProductionReport{}, err
+	}
+	return buildProductionReport(date, rows), nil
+}
+
+// buildProductionReport groups rows by product, sorted by name. Rows come ordered by order id,
+// so a product renamed between orders is shown with its newest name snapshot.
+func buildProductionReport(date time.Time, rows []oModel.ProductionReportRow) oModel.ProductionReport {
+	report := oModel.ProductionReport{
+		Date:     date.Format("2006-01-02"),
+		Products: []oModel.ProductionReportProduct{},
+	}
+	indexByProduct := make(map[uint64]int)
+	orders := make(map[uint64]struct{})
+	for _, row := range rows {
+		idx, ok := indexByProduct[row.IDProduct]
+		if !ok {
+			idx = len(report.Products)
+			indexByProduct[row.IDProduct] = idx
+			report.Products = append(report.Products, oModel.ProductionReportProduct{IDProduct: row.IDProduct})
+		}
+		product := &report.Products[idx]
+		if row.ProductName != "" {
+			product.Name = row.ProductName
+		}
+		product.TotalQuantity += row.Quantity
+		product.Orders = append(product.Orders, oModel.ProductionReportOrder{
+			IDOrder:         row.IDOrder,
+			CustomerName:    row.CustomerName,
+			Status:          row.Status,
+			Quantity:        row.Quantity,
+			Note:            row.Note,
+			FulfillmentType: row.FulfillmentType,
+			PickupLocation:  row.PickupLocation,
+		})
+		report.TotalUnits += row.Quantity
+		orders[row.IDOrder] = struct{}{}
+	}
+	report.TotalOrders = len(orders)
+	sort.SliceStable(report.Products, func(i, j int) bool {
+		return strings.ToLower(report.Products[i].Name) < strings.ToLower(report.Products[j].Name)
+	})
+	return report
+}
+
+// WriteProductionReportCSV writes one row per product and order, with the product total repeated
+// on every row so the sheet can be filtered or pivoted.
+func WriteProductionReportCSV(w io.Writer, report oModel.ProductionReport) error {
+	cw := csv.NewWriter(w)
+	if err := cw.Write([]string{"delivery_date", "id_product", "product", "product_total", "id_order", "customer", "status", "quantity", "note", "fulfillment_type", "pickup_location"}); err != nil {
+		return err
+	}
+	for _, product := range report.Products {
+		for _, order := range product.Orders {
+			record := []string{
+				report.Date,
+				strconv.FormatUint(product.IDProduct, 10),
+				product.Name,
+				strconv.FormatUint(product.TotalQuantity, 10),
+				strconv.FormatUint(order.IDOrder, 10),
+				order.CustomerName,
+				string(order.Status),
+				strconv.FormatUint(order.Quantity, 10),
+				order.Note,
+				string(order.FulfillmentType),
+				order.PickupLocation,
+			}
+			if err := cw.Write(record); err != nil {
+				return err
+			}
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+// WriteProductionReportText writes a printable plan: each product with its total, then the orders
+// it comes from.
+func WriteProductionReportText(w io.Writer, report oModel.ProductionReport) error {
+	var b strings.Builder
+	fmt.Fprintf(&b, "PRODUCTION PLAN - %s\n", report.Date)
+	fmt.Fprintf(&b, "%d orders, %d units\n", report.TotalOrders, report.TotalUnits)
+	if len(report.Products) == 0 {
+		b.WriteString("\nNothing to bake.\n")
+	}
+	for _, product := range report.Products {
+		fmt.Fprintf(&b, "\n%5d  %s\n", product.TotalQuantity, product.Name)
+		for _, order := range product.Orders {
+			fmt.Fprintf(&b, "       %4d  #%d %s (%s)", order.Quantity, order.IDOrder, order.CustomerName, order.Status)
+			if order.FulfillmentType == oModel.FulfillmentPickup {
+				fmt.Fprintf(&b, " - pickup at %s", order.PickupLocation)
+			}
+			b.WriteString("\n")
+		}
+	}
+	_, err := io.WriteString(w, b.String())
+	return err
+}
@@ -0,0 +1,89 @@
+package orders
+
+import (
+	"bytes"
+	"context"
+	"testing"
+	"time"
+
+	oModel "github.com/radamesvaz/bakery-app/model/orders"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+type MockProductionReportRepository struct {
+	mock.Mock
+}
+
+func (m *MockProductionReportRepository) GetProductionReportRows(ctx context.Context, tenantID uint64, date time.Time) ([]oModel.ProductionReportRow, error) {
+	args := m.Called(ctx, tenantID, date)
+	return args.Get(0).([]oModel.ProductionReportRow), args.Error(1)
+}
+
+var productionDate = time.Date(2026, 12, 24, 0, 0, 0, 0, time.UTC)
+
+func productionRows() []oModel.ProductionReportRow {
+	return []oModel.ProductionReportRow{
+		{IDOrder: 10, IDProduct: 2, ProductName: "Pan dulce", Quantity: 3, Status: oModel.StatusPending, CustomerName: "Ana", FulfillmentType: oModel.FulfillmentDelivery},
+		{IDOrder: 10, IDProduct: 1, ProductName: "Baguette", Quantity: 2, Status: oModel.StatusPending, CustomerName: "Ana", FulfillmentType: oModel.FulfillmentDelivery},
+		{IDOrder: 12, IDProduct: 2, ProductName: "Pan dulce grande", Quantity: 5, Status: oModel.StatusReady, CustomerName: "Luis", Note: "sin pasas", FulfillmentType: oModel.FulfillmentPickup, PickupLocation: "Centro"},
+	}
+}
+
+func TestProductionReporter_GetProductionReport_GroupsByProduct(t *testing.T) {
+	repo := new(MockProductionReportRepository)
+	repo.On("GetProductionReportRows", mock.Anything, uint64(1), productionDate).Return(productionRows(), nil)
+
+	report, err := NewProductionReporter(repo).GetProductionReport(context.Background(), 1, productionDate)
+
+	require.NoError(t, err)
+	assert.Equal(t, "2026-12-24", report.Date)
+	assert.Equal(t, 2, report.TotalOrders)
+	assert.Equal(t, uint64(10), report.TotalUnits)
+	require.Len(t, report.Products, 2)
+	assert.Equal(t, "Baguette", report.Products[0].Name)
+	assert.Equal(t, uint64(2), report.Products[0].TotalQuantity)
+	// Renamed product keeps one line with the newest snapshot name.
+	assert.Equal(t, "Pan dulce grande", report.Products[1].Name)
+	assert.Equal(t, uint64(8), report.Products[1].TotalQuantity)
+	assert.Equal(t, []oModel.ProductionReportOrder{
+		{IDOrder: 10, CustomerName: "Ana", Status: oModel.StatusPending, Quantity: 3, FulfillmentType: oModel.FulfillmentDelivery},
+		{IDOrder: 12, CustomerName: "Luis", Status: oModel.StatusReady, Quantity: 5, Note: "sin pasas", FulfillmentType: oModel.FulfillmentPickup, PickupLocation: "Centro"},
+	}, report.Products[1].Orders)
+}
+
+func TestProductionReporter_GetProductionReport_EmptyDay(t *testing.T) {
+	repo := new(MockProductionReportRepository)
+	repo.On("GetProductionReportRows", mock.Anything, uint64(1), productionDate).Return([]oModel.ProductionReportRow(nil), nil)
+
+	report, err := NewProductionReporter(repo).GetProductionReport(context.Background(), 1, productionDate)
+
+	require.NoError(t, err)
+	assert.Equal(t, 0, report.TotalOrders)
+	assert.NotNil(t, report.Products)
+	assert.Empty(t, report.Products)
+}
+
+func TestWriteProductionReportCSV(t *testing.T) {
+	var buf bytes.Buffer
+	require.NoError(t, WriteProductionReportCSV(&buf, buildProductionReport(productionDate, productionRows())))
+
+	assert.Equal(t, "delivery_date,id_product,product,product_total,id_order,customer,status,quantity,note,fulfillment_type,pickup_location\n"+
+		"2026-12-24,1,Baguette,2,10,Ana,pending,2,,delivery,\n"+
+		"2026-12-24,2,Pan dulce grande,8,10,Ana,pending,3,,delivery,\n"+
+		"2026-12-24,2,Pan dulce grande,8,12,Luis,ready,5,sin pasas,pickup,Centro\n", buf.String())
+}
+
+func TestWriteProductionReportText(t *testing.T) {
+	var buf bytes.Buffer
+	require.NoError(t, WriteProductionReportText(&buf, buildProductionReport(productionDate, productionRows())))
+
+	assert.Equal(t, "PRODUCTION PLAN - 2026-12-24\n"+
+		"2 orders, 10 units\n"+
+		"\n    2  Baguette\n"+
+		"          2  #10 Ana (pending)\n"+
+		"\n    8  Pan dulce grande\n"+
+		"          3  #10 Ana (pending)\n"+
+		"          5  #12 Luis (ready) - pickup at Centro\n", buf.String())
+}
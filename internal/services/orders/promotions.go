@@ -0,0 +1,59 @@
+package orders
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/radamesvaz/bakery-app/internal/errors"
+	"github.com/radamesvaz/bakery-app/model/money"
+	promoModel "github.com/radamesvaz/bakery-app/model/promotions"
+)
+
+// PromotionRepository looks up and counts discount codes in the order transaction.
+// It is implemented by the promotions repository.
+type PromotionRepository interface {
+	GetPromotionByCodeForUpdateTx(ctx context.Context, tx *sql.Tx, tenantID uint64, code string) (promoModel.Promotion, error)
+	CountRedemptionsTx(ctx context.Context, tx *sql.Tx, tenantID, promotionID uint64, email string) (total, byCustomer int, err error)
+	CreateRedemptionTx(ctx context.Context, tx *sql.Tx, redemption promoModel.Redemption) error
+}
+
+// PromotionReader loads the promotion of an order to re-price it when its items change, and keeps
+// the discount of its redemption in step.
+type PromotionReader interface {
+	GetPromotionByIDTx(ctx context.Context, tx *sql.Tx, tenantID, id uint64) (promoModel.Promotion, error)
+	UpdateRedemptionDiscountTx(ctx context.Context, tx *sql.Tx, tenantID, orderID uint64, discount money.Amount) error
+}
+
+// applyPromotionTx validates code for an order of lines placed by email and returns the promotion
+// with the discount it grants. The promotion row stays locked until tx ends, so two orders racing
+// for its last use cannot both pass the usage limits.
+func applyPromotionTx(ctx context.Context, repo PromotionRepository, tx *sql.Tx, tenantID uint64, code, email string, lines []promoModel.Line, now time.Time) (promoModel.Promotion, money.Amount, error) {
+	promo, err := repo.GetPromotionByCodeForUpdateTx(ctx, tx, tenantID, code)
+	if err != nil {
+		return promoModel.Promotion{}, 0, err
+	}
+	if !promo.IsRedeemableAt(now) {
+		return promoModel.Promotion{}, 0, errors.ErrPromotionCodeInvalid
+	}
+	if !promo.MeetsMinimum(lines) {
+		return promoModel.Promotion{}, 0, errors.ErrPromotionMinOrderTotalNotMet
+	}
+	discount := promo.Discount(lines)
+	if discount <= 0 {
+		return promoModel.Promotion{}, 0, errors.ErrPromotionNotApplicable
+	}
+	if promo.MaxUses != nil || promo.MaxUsesPerCustomer != nil {
+		total, byCustomer, err := repo.CountRedemptionsTx(ctx, tx, tenantID, promo.ID, email)
+		if err != nil {
+			return promoModel.Promotion{}, 0, err
+		}
+		if promo.MaxUses != nil && total >= *promo.MaxUses {
+			return promoModel.Promotion{}, 0, errors.ErrPromotionUsageLimitReached
+		}
+		if promo.MaxUsesPerCustomer != nil && byCustomer >= *promo.MaxUsesPerCustomer {
+			return promoModel.Promotion{}, 0, errors.ErrPromotionCustomerLimitReached
+		}
+	}
+	return promo, discount, nil
+}
@@ -0,0 +1,216 @@
+package orders
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	internalErrors "github.com/radamesvaz/bakery-app/internal/errors"
+	"github.com/radamesvaz/bakery-app/model/money"
+	oModel "github.com/radamesvaz/bakery-app/model/orders"
+	pModel "github.com/radamesvaz/bakery-app/model/products"
+	promoModel "github.com/radamesvaz/bakery-app/model/promotions"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+type MockPromotionRepository struct {
+	mock.Mock
+}
+
+func (m *MockPromotionRepository) GetPromotionByCodeForUpdateTx(ctx context.Context, tx *sql.Tx, tenantID uint64, code string) (promoModel.Promotion, error) {
+	args := m.Called(ctx, tx, tenantID, code)
+	return args.Get(0).(promoModel.Promotion), args.Error(1)
+}
+
+func (m *MockPromotionRepository) CountRedemptionsTx(ctx context.Context, tx *sql.Tx, tenantID, promotionID uint64, email string) (int, int, error) {
+	args := m.Called(ctx, tx, tenantID, promotionID, email)
+	return args.Int(0), args.Int(1), args.Error(2)
+}
+
+func (m *MockPromotionRepository) CreateRedemptionTx(ctx context.Context, tx *sql.Tx, redemption promoModel.Redemption) error {
+	args := m.Called(ctx, tx, redemption)
+	return args.Error(0)
+}
+
+func promotionOrderPayload(code string) oModel.CreateOrderPayload {
+	return oModel.CreateOrderPayload{
+		Name:              "Cliente Test",
+		Email:             "test@example.com",
+		Phone:             "12345678",
+		DeliveryDirection: "https://maps.app.goo.gl/test-direction-1",
+		Items: []oModel.CreateOrderItemInput{
+			{IdProduct: 1, Quantity: 4}, // 10.00
+			{IdProduct: 2, Quantity: 1}, // 1.80
+		},
+		DeliveryDate:  "2024-12-25",
+		PromotionCode: code,
+	}
+}
+
+func promotionCreator(db *sql.DB, promotions PromotionRepository) (*Creator, *MockOrderRepo2) {
+	orderRepo := &MockOrderRepo2{DB: db}
+	return &Creator{
+		UserRepo: &MockUserRepo{ShouldCreate: false},
+		ProductRepo: &MockProductRepo2{
+			Products: map[uint64]pModel.Product{
+				1: activeProduct(1, "Pan", 2.50, 10),
+				2: activeProduct(2, "Leche", 1.80, 5),
+			},
+			StockUpdates: make(map[uint64]uint64),
+		},
+		OrderRepo:  orderRepo,
+		Promotions: promotions,
+	}, orderRepo
+}
+
+func TestCreateOrder_AppliesPromotionAndRecordsRedemption(t *testing.T) {
+	db, sqlMock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+	sqlMock.ExpectBegin()
+	sqlMock.ExpectCommit()
+
+	maxUses := 10
+	promo := promoModel.Promotion{
+		ID: 4, Code: "PAN20", DiscountType: promoModel.DiscountPercentage, Value: 20,
+		Scope: promoModel.ScopeProducts, ProductIDs: []uint64{1}, MaxUses: &maxUses, Active: true,
+	}
+	promotions := new(MockPromotionRepository)
+	promotions.On("GetPromotionByCodeForUpdateTx", mock.Anything, mock.Anything, uint64(1), "pan20").Return(promo, nil)
+	promotions.On("CountRedemptionsTx", mock.Anything, mock.Anything, uint64(1), uint64(4), "test@example.com").Return(3, 0, nil)
+	promotions.On("CreateRedemptionTx", mock.Anything, mock.Anything, promoModel.Redemption{
+		TenantID: 1, IDPromotion: 4, IDOrder: 123, Email: "test@example.com", DiscountAmount: 200,
+	}).Return(nil)
+
+	service, orderRepo := promotionCreator(db, promotions)
+	payload := promotionOrderPayload(" pan20 ")
+	deliveryDate, _ := time.Parse("2006-01-02", payload.DeliveryDate)
+
+	_, err = service.CreateOrder(context.Background(), 1, payload, deliveryDate)
+
+	require.NoError(t, err)
+	assert.Equal(t, money.Amount(980), orderRepo.LastOrder.Price)
+	assert.Equal(t, money.Amount(200), orderRepo.LastOrder.DiscountAmount)
+	require.NotNil(t, orderRepo.LastOrder.PromotionCode)
+	assert.Equal(t, "PAN20", *orderRepo.LastOrder.PromotionCode)
+	require.NotNil(t, orderRepo.LastOrder.IDPromotion)
+	assert.Equal(t, uint64(4), *orderRepo.LastOrder.IDPromotion)
+	promotions.AssertExpectations(t)
+	require.NoError(t, sqlMock.ExpectationsWereMet())
+}
+
+func TestCreateOrder_RejectsPromotion(t *testing.T) {
+	one := 1
+	minTotal := money.Amount(5000)
+	future := time.Now().Add(time.Hour)
+	base := promoModel.Promotion{ID: 4, Code: "WELCOME", DiscountType: promoModel.DiscountFixed, Value: 5, Scope: promoModel.ScopeOrder, Active: true}
+
+	tests := []struct {
+		name            string
+		mutate          func(p *promoModel.Promotion)
+		total, customer int
+		expected        error
+	}{
+		{name: "inactive", mutate: func(p *promoModel.Promotion) { p.Active = false }, expected: internalErrors.ErrPromotionCodeInvalid},
+		{name: "not started", mutate: func(p *promoModel.Promotion) { p.StartsAt = &future }, expected: internalErrors.ErrPromotionCodeInvalid},
+		{name: "minimum not met", mutate: func(p *promoModel.Promotion) { p.MinOrderTotal = &minTotal }, expected: internalErrors.ErrPromotionMinOrderTotalNotMet},
+		{name: "no eligible product", mutate: func(p *promoModel.Promotion) { p.Scope = promoModel.ScopeProducts; p.ProductIDs = []uint64{9} }, expected: internalErrors.ErrPromotionNotApplicable},
+		{name: "usage limit", mutate: func(p *promoModel.Promotion) { p.MaxUses = &one }, total: 1, expected: internalErrors.ErrPromotionUsageLimitReached},
+		{name: "customer limit", mutate: func(p *promoModel.Promotion) { p.MaxUsesPerCustomer = &one }, total: 1, customer: 1, expected: internalErrors.ErrPromotionCustomerLimitReached},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db, sqlMock, err := sqlmock.New()
+			require.NoError(t, err)
+			defer db.Close()
+			sqlMock.ExpectBegin()
+			sqlMock.ExpectRollback()
+
+			promo := base
+			tt.mutate(&promo)
+			promotions := new(MockPromotionRepository)
+			promotions.On("GetPromotionByCodeForUpdateTx", mock.Anything, mock.Anything, uint64(1), "WELCOME").Return(promo, nil)
+			promotions.On("CountRedemptionsTx", mock.Anything, mock.Anything, uint64(1), uint64(4), "test@example.com").Return(tt.total, tt.customer, nil).Maybe()
+
+			service, orderRepo := promotionCreator(db, promotions)
+			payload := promotionOrderPayload("WELCOME")
+			deliveryDate, _ := time.Parse("2006-01-02", payload.DeliveryDate)
+
+			_, err = service.CreateOrder(context.Background(), 1, payload, deliveryDate)
+
+			assert.ErrorIs(t, err, tt.expected)
+			assert.False(t, orderRepo.OrderCreated)
+			promotions.AssertNotCalled(t, "CreateRedemptionTx", mock.Anything, mock.Anything, mock.Anything)
+			require.NoError(t, sqlMock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestCreateOrder_RejectsPromotionCodeWithoutRepository(t *testing.T) {
+	service, orderRepo := promotionCreator(nil, nil)
+	payload := promotionOrderPayload("WELCOME")
+	deliveryDate, _ := time.Parse("2006-01-02", payload.DeliveryDate)
+
+	_, err := service.CreateOrder(context.Background(), 1, payload, deliveryDate)
+
+	assert.ErrorIs(t, err, internalErrors.ErrPromotionCodeInvalid)
+	assert.False(t, orderRepo.OrderCreated)
+}
+
+type stubPromotionReader struct {
+	promo promoModel.Promotion
+	// redemptions records UpdateRedemptionDiscountTx calls by order id.
+	redemptions map[uint64]money.Amount
+}
+
+func (s stubPromotionReader) GetPromotionByIDTx(ctx context.Context, tx *sql.Tx, tenantID, id uint64) (promoModel.Promotion, error) {
+	return s.promo, nil
+}
+
+func (s stubPromotionReader) UpdateRedemptionDiscountTx(ctx context.Context, tx *sql.Tx, tenantID, orderID uint64, discount money.Amount) error {
+	s.redemptions[orderID] = discount
+	return nil
+}
+
+func TestItemsUpdater_RepriceDiscountTx(t *testing.T) {
+	minTotal := money.Amount(2000)
+	idPromotion := uint64(4)
+	promo := promoModel.Promotion{ID: 4, DiscountType: promoModel.DiscountPercentage, Value: 10, Scope: promoModel.ScopeOrder, MinOrderTotal: &minTotal}
+	withPromotion := oModel.OrderResponse{ID: 9, DiscountAmount: 300, IDPromotion: &idPromotion}
+
+	tests := []struct {
+		name       string
+		reader     bool
+		order      oModel.OrderResponse
+		lines      []promoModel.Line
+		expected   money.Amount
+		redemption map[uint64]money.Amount
+	}{
+		{name: "re-applies the promotion", reader: true, order: withPromotion, lines: []promoModel.Line{{IdProduct: 1, Amount: 4500}}, expected: 450, redemption: map[uint64]money.Amount{9: 450}},
+		{name: "drops the discount below the minimum", reader: true, order: withPromotion, lines: []promoModel.Line{{IdProduct: 1, Amount: 1500}}, expected: 0, redemption: map[uint64]money.Amount{9: 0}},
+		{name: "leaves an unchanged redemption alone", reader: true, order: withPromotion, lines: []promoModel.Line{{IdProduct: 1, Amount: 3000}}, expected: 300, redemption: map[uint64]money.Amount{}},
+		{name: "keeps the discount without a reader", order: withPromotion, lines: []promoModel.Line{{IdProduct: 1, Amount: 4500}}, expected: 300},
+		{name: "caps the kept discount at the subtotal", order: withPromotion, lines: []promoModel.Line{{IdProduct: 1, Amount: 200}}, expected: 200},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var updater ItemsUpdater
+			reader := stubPromotionReader{promo: promo, redemptions: map[uint64]money.Amount{}}
+			if tt.reader {
+				updater.Promotions = reader
+			}
+			discount, err := updater.repriceDiscountTx(context.Background(), nil, 1, tt.order, tt.lines)
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, discount)
+			if tt.reader {
+				assert.Equal(t, tt.redemption, reader.redemptions)
+			}
+		})
+	}
+}
@@ -0,0 +1,62 @@
+package orders
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/radamesvaz/bakery-app/internal/errors"
+	opModel "github.com/radamesvaz/bakery-app/model/orderprotection"
+	oModel "github.com/radamesvaz/bakery-app/model/orders"
+)
+
+// OrderProtectionRepository exposes the tenant's abuse limits for storefront orders.
+// It is implemented by the order protection repository.
+type OrderProtectionRepository interface {
+	GetSettings(ctx context.Context, tenantID uint64) (opModel.Settings, error)
+	IsBlocked(ctx context.Context, tenantID uint64, email, phone string) (bool, error)
+	CountPendingUnpaidOrdersTx(ctx context.Context, tx *sql.Tx, tenantID, userID uint64) (int, error)
+}
+
+// checkCustomerAllowed runs the checks of a storefront order that need no transaction: the
+// customer's email and phone must not be blocked and no product line (after merging duplicate
+// lines) may go over the tenant's maximum quantity. It returns the settings for
+// checkPendingUnpaidOrdersTx.
+func checkCustomerAllowed(ctx context.Context, repo OrderProtectionRepository, tenantID uint64, payload oModel.CreateOrderPayload, items []oModel.CreateOrderItemInput) (opModel.Settings, error) {
+	blocked, err := repo.IsBlocked(ctx, tenantID, payload.Email, payload.Phone)
+	if err != nil {
+		return opModel.Settings{}, err
+	}
+	if blocked {
+		return opModel.Settings{}, errors.ErrOrderCustomerBlocked
+	}
+
+	settings, err := repo.GetSettings(ctx, tenantID)
+	if err != nil {
+		return opModel.Settings{}, err
+	}
+	if settings.MaxQuantityPerLine > 0 {
+		for _, item := range items {
+			if item.Quantity > uint64(settings.MaxQuantityPerLine) {
+				return opModel.Settings{}, fmt.Errorf("%w: at most %d per product", errors.ErrOrderLineQuantityExceeded, settings.MaxQuantityPerLine)
+			}
+		}
+	}
+	return settings, nil
+}
+
+// checkPendingUnpaidOrdersTx rejects a storefront order when the customer already holds the
+// tenant's maximum of pending unpaid orders, each of which reserves stock until it expires.
+func checkPendingUnpaidOrdersTx(ctx context.Context, repo OrderProtectionRepository, tx *sql.Tx, tenantID, userID uint64, settings opModel.Settings) error {
+	if settings.MaxPendingUnpaidOrders <= 0 {
+		return nil
+	}
+	pending, err := repo.CountPendingUnpaidOrdersTx(ctx, tx, tenantID, userID)
+	if err != nil {
+		return err
+	}
+	if pending >= settings.MaxPendingUnpaidOrders {
+		return fmt.Errorf("%w (limit %d)", errors.ErrTooManyPendingOrders, settings.MaxPendingUnpaidOrders)
+	}
+	return nil
+}
@@ -0,0 +1,135 @@
+package orders
+
+import (
+	"context"
+	"database/sql"
+	stdErrors "errors"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	internalErrors "github.com/radamesvaz/bakery-app/internal/errors"
+	opModel "github.com/radamesvaz/bakery-app/model/orderprotection"
+	oModel "github.com/radamesvaz/bakery-app/model/orders"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type stubOrderProtectionRepository struct {
+	settings opModel.Settings
+	blocked  bool
+	pending  int
+	// counted records the customer whose pending orders were counted.
+	counted uint64
+}
+
+func (s *stubOrderProtectionRepository) GetSettings(ctx context.Context, tenantID uint64) (opModel.Settings, error) {
+	return s.settings, nil
+}
+
+func (s *stubOrderProtectionRepository) IsBlocked(ctx context.Context, tenantID uint64, email, phone string) (bool, error) {
+	return s.blocked, nil
+}
+
+func (s *stubOrderProtectionRepository) CountPendingUnpaidOrdersTx(ctx context.Context, tx *sql.Tx, tenantID, userID uint64) (int, error) {
+	s.counted = userID
+	return s.pending, nil
+}
+
+func TestCreateOrder_ProtectionAcceptsOrderWithinLimits(t *testing.T) {
+	db, sqlMock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+	sqlMock.ExpectBegin()
+	sqlMock.ExpectCommit()
+
+	service, orderRepo := promotionCreator(db, nil)
+	protection := &stubOrderProtectionRepository{settings: opModel.Settings{MaxPendingUnpaidOrders: 3, MaxQuantityPerLine: 4}, pending: 2}
+	service.Protection = protection
+	payload := promotionOrderPayload("")
+	deliveryDate, _ := time.Parse("2006-01-02", payload.DeliveryDate)
+
+	_, err = service.CreateOrder(context.Background(), 1, payload, deliveryDate)
+
+	require.NoError(t, err)
+	assert.True(t, orderRepo.OrderCreated)
+	assert.Equal(t, uint64(1), protection.counted)
+	require.NoError(t, sqlMock.ExpectationsWereMet())
+}
+
+func TestCreateOrder_ProtectionRejections(t *testing.T) {
+	tests := []struct {
+		name       string
+		protection *stubOrderProtectionRepository
+		items      []oModel.CreateOrderItemInput
+		wantErr    error
+		// inTx is true when the check runs inside the order transaction.
+		inTx bool
+	}{
+		{
+			name:       "blocked email or phone",
+			protection: &stubOrderProtectionRepository{settings: opModel.DefaultSettings(), blocked: true},
+			wantErr:    internalErrors.ErrOrderCustomerBlocked,
+		},
+		{
+			name:       "merged lines above the maximum quantity",
+			protection: &stubOrderProtectionRepository{settings: opModel.Settings{MaxQuantityPerLine: 4}},
+			items:      []oModel.CreateOrderItemInput{{IdProduct: 1, Quantity: 3}, {IdProduct: 1, Quantity: 2}},
+			wantErr:    internalErrors.ErrOrderLineQuantityExceeded,
+		},
+		{
+			name:       "too many pending unpaid orders",
+			protection: &stubOrderProtectionRepository{settings: opModel.Settings{MaxPendingUnpaidOrders: 2}, pending: 2},
+			wantErr:    internalErrors.ErrTooManyPendingOrders,
+			inTx:       true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db, sqlMock, err := sqlmock.New()
+			require.NoError(t, err)
+			defer db.Close()
+			if tt.inTx {
+				sqlMock.ExpectBegin()
+				sqlMock.ExpectRollback()
+			}
+
+			service, orderRepo := promotionCreator(db, nil)
+			service.Protection = tt.protection
+			payload := promotionOrderPayload("")
+			if tt.items != nil {
+				payload.Items = tt.items
+			}
+			deliveryDate, _ := time.Parse("2006-01-02", payload.DeliveryDate)
+
+			_, err = service.CreateOrder(context.Background(), 1, payload, deliveryDate)
+
+			assert.True(t, stdErrors.Is(err, tt.wantErr))
+			assert.False(t, orderRepo.OrderCreated)
+			require.NoError(t, sqlMock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestCreateOrderWithOptions_StaffOrdersSkipProtection(t *testing.T) {
+	db, sqlMock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+	sqlMock.ExpectBegin()
+	sqlMock.ExpectCommit()
+
+	service, orderRepo := promotionCreator(db, nil)
+	service.Protection = &stubOrderProtectionRepository{settings: opModel.Settings{MaxPendingUnpaidOrders: 1, MaxQuantityPerLine: 1}, blocked: true, pending: 5}
+	payload := promotionOrderPayload("")
+	deliveryDate, _ := time.Parse("2006-01-02", payload.DeliveryDate)
+
+	_, err = service.CreateOrderWithOptions(context.Background(), 1, payload, deliveryDate, CreateOrderOptions{
+		SalesChannel: oModel.SalesChannelPhone,
+		Staff:        &StaffOrderOptions{UserID: 9},
+	})
+
+	require.NoError(t, err)
+	assert.True(t, orderRepo.OrderCreated)
+	require.NoError(t, sqlMock.ExpectationsWereMet())
+}
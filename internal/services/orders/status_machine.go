@@ -0,0 +1,105 @@
+package orders
+
+import (
+	"context"
+
+	"github.com/radamesvaz/bakery-app/internal/errors"
+	oModel "github.com/radamesvaz/bakery-app/model/orders"
+)
+
+// StatusMachine declares which order status changes are allowed through the API.
+// Statuses without outgoing edges are terminal. Expiry of pending orders is driven by the
+// ghost-order worker (ExpiredOrderCanceller) and is intentionally not an edge here.
+type StatusMachine struct {
+	edges map[oModel.OrderStatus][]oModel.OrderStatus
+}
+
+// NewStatusMachine builds a machine from an adjacency list (from -> allowed targets).
+func NewStatusMachine(edges map[oModel.OrderStatus][]oModel.OrderStatus) *StatusMachine {
+	copied := make(map[oModel.OrderStatus][]oModel.OrderStatus, len(edges))
+	for from, targets := range edges {
+		copied[from] = append([]oModel.OrderStatus(nil), targets...)
+	}
+	return &StatusMachine{edges: copied}
+}
+
+// DefaultStatusMachine is the order lifecycle of tenants that did not configure their own:
+// pending -> preparing -> ready -> delivered, cancel from any non-terminal state,
+// and soft delete from any non-terminal state or after delivery.
+var DefaultStatusMachine = NewStatusMachine(map[oModel.OrderStatus][]oModel.OrderStatus{
+	oModel.StatusPending:   {oModel.StatusPreparing, oModel.StatusCancelled, oModel.StatusDeleted},
+	oModel.StatusPreparing: {oModel.StatusReady, oModel.StatusCancelled, oModel.StatusDeleted},
+	oModel.StatusReady:     {oModel.StatusDelivered, oModel.StatusCancelled, oModel.StatusDeleted},
+	oModel.StatusDelivered: {oModel.StatusDeleted},
+})
+
+// StatusTransitionsRepository loads the transitions a tenant configured.
+type StatusTransitionsRepository interface {
+	GetStatusTransitions(ctx context.Context, tenantID uint64) (oModel.StatusTransitions, error)
+}
+
+// TenantStatusMachine returns the lifecycle of tenantID: the transitions it configured through
+// PUT /auth/order-status-transitions, or DefaultStatusMachine when it has none.
+func TenantStatusMachine(ctx context.Context, repo StatusTransitionsRepository, tenantID uint64) (*StatusMachine, error) {
+	transitions, err := repo.GetStatusTransitions(ctx, tenantID)
+	if err != nil {
+		return nil, err
+	}
+	if len(transitions) == 0 {
+		return DefaultStatusMachine, nil
+	}
+	return NewStatusMachine(transitions), nil
+}
+
+// Transitions returns a copy of the machine's edges.
+func (m *StatusMachine) Transitions() oModel.StatusTransitions {
+	copied := make(oModel.StatusTransitions, len(m.edges))
+	for from, targets := range m.edges {
+		copied[from] = append([]oModel.OrderStatus(nil), targets...)
+	}
+	return copied
+}
+
+// AllowedTransitions returns the statuses an order in status from can move to (never nil).
+func (m *StatusMachine) AllowedTransitions(from oModel.OrderStatus) []oModel.OrderStatus {
+	return append([]oModel.OrderStatus{}, m.edges[from]...)
+}
+
+// CanTransition reports whether from -> to is an edge of the machine.
+func (m *StatusMachine) CanTransition(from, to oModel.OrderStatus) bool {
+	for _, target := range m.edges[from] {
+		if target == to {
+			return true
+		}
+	}
+	return false
+}
+
+// IsTarget reports whether status can be reached from any state (i.e. it may be requested via the API).
+func (m *StatusMachine) IsTarget(status oModel.OrderStatus) bool {
+	for _, targets := range m.edges {
+		for _, target := range targets {
+			if target == status {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// Validate returns nil when from -> to is allowed. Otherwise it returns ErrOrderAlreadyCancelled
+// for repeated cancels, ErrOrderAlreadyDelivered when leaving delivered, or ErrInvalidStatusTransition.
+func (m *StatusMachine) Validate(from, to oModel.OrderStatus) error {
+	if m.CanTransition(from, to) {
+		return nil
+	}
+	switch from {
+	case oModel.StatusCancelled:
+		if to == oModel.StatusCancelled {
+			return errors.ErrOrderAlreadyCancelled
+		}
+	case oModel.StatusDelivered:
+		return errors.ErrOrderAlreadyDelivered
+	}
+	return errors.ErrInvalidStatusTransition
+}
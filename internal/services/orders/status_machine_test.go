@@ -0,0 +1,101 @@
+package orders
+
+import (
+	"context"
+	"testing"
+
+	appErrors "github.com/radamesvaz/bakery-app/internal/errors"
+	oModel "github.com/radamesvaz/bakery-app/model/orders"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStatusMachine_Validate_DefaultLifecycle(t *testing.T) {
+	m := DefaultStatusMachine
+
+	tests := []struct {
+		name    string
+		from    oModel.OrderStatus
+		to      oModel.OrderStatus
+		wantErr error
+	}{
+		{name: "pending to preparing", from: oModel.StatusPending, to: oModel.StatusPreparing},
+		{name: "preparing to ready", from: oModel.StatusPreparing, to: oModel.StatusReady},
+		{name: "ready to delivered", from: oModel.StatusReady, to: oModel.StatusDelivered},
+		{name: "cancel from preparing", from: oModel.StatusPreparing, to: oModel.StatusCancelled},
+		{name: "delete after delivery", from: oModel.StatusDelivered, to: oModel.StatusDeleted},
+		{name: "pending cannot skip to delivered", from: oModel.StatusPending, to: oModel.StatusDelivered, wantErr: appErrors.ErrInvalidStatusTransition},
+		{name: "ready cannot go back to pending", from: oModel.StatusReady, to: oModel.StatusPending, wantErr: appErrors.ErrInvalidStatusTransition},
+		{name: "delivered cannot go back to pending", from: oModel.StatusDelivered, to: oModel.StatusPending, wantErr: appErrors.ErrOrderAlreadyDelivered},
+		{name: "delivered cannot be cancelled", from: oModel.StatusDelivered, to: oModel.StatusCancelled, wantErr: appErrors.ErrOrderAlreadyDelivered},
+		{name: "cancelled twice", from: oModel.StatusCancelled, to: oModel.StatusCancelled, wantErr: appErrors.ErrOrderAlreadyCancelled},
+		{name: "expired is terminal", from: oModel.StatusExpired, to: oModel.StatusCancelled, wantErr: appErrors.ErrInvalidStatusTransition},
+		{name: "deleted is terminal", from: oModel.StatusDeleted, to: oModel.StatusPending, wantErr: appErrors.ErrInvalidStatusTransition},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := m.Validate(tt.from, tt.to)
+			if tt.wantErr == nil {
+				assert.NoError(t, err)
+				return
+			}
+			assert.ErrorIs(t, err, tt.wantErr)
+		})
+	}
+}
+
+func TestStatusMachine_AllowedTransitions(t *testing.T) {
+	m := DefaultStatusMachine
+
+	assert.Equal(t,
+		[]oModel.OrderStatus{oModel.StatusPreparing, oModel.StatusCancelled, oModel.StatusDeleted},
+		m.AllowedTransitions(oModel.StatusPending))
+	assert.Equal(t, []oModel.OrderStatus{}, m.AllowedTransitions(oModel.StatusCancelled))
+
+	// Callers must not be able to mutate the machine through the returned slice.
+	allowed := m.AllowedTransitions(oModel.StatusPending)
+	allowed[0] = oModel.StatusDelivered
+	assert.True(t, m.CanTransition(oModel.StatusPending, oModel.StatusPreparing))
+}
+
+func TestStatusMachine_IsTarget(t *testing.T) {
+	m := DefaultStatusMachine
+
+	assert.True(t, m.IsTarget(oModel.StatusDelivered))
+	assert.True(t, m.IsTarget(oModel.StatusDeleted))
+	assert.False(t, m.IsTarget(oModel.StatusPending))
+	assert.False(t, m.IsTarget(oModel.StatusExpired))
+	assert.False(t, m.IsTarget(oModel.OrderStatus("shipped")))
+}
+
+type stubStatusTransitionsRepository struct {
+	transitions oModel.StatusTransitions
+}
+
+func (s stubStatusTransitionsRepository) GetStatusTransitions(ctx context.Context, tenantID uint64) (oModel.StatusTransitions, error) {
+	return s.transitions, nil
+}
+
+func TestTenantStatusMachine(t *testing.T) {
+	t.Run("tenant without transitions uses the default lifecycle", func(t *testing.T) {
+		m, err := TenantStatusMachine(context.Background(), stubStatusTransitionsRepository{}, 1)
+
+		require.NoError(t, err)
+		assert.Same(t, DefaultStatusMachine, m)
+	})
+
+	t.Run("tenant transitions replace the default lifecycle", func(t *testing.T) {
+		repo := stubStatusTransitionsRepository{transitions: oModel.StatusTransitions{
+			oModel.StatusPending: {oModel.StatusDelivered, oModel.StatusCancelled},
+		}}
+
+		m, err := TenantStatusMachine(context.Background(), repo, 1)
+
+		require.NoError(t, err)
+		assert.NoError(t, m.Validate(oModel.StatusPending, oModel.StatusDelivered))
+		assert.ErrorIs(t, m.Validate(oModel.StatusPending, oModel.StatusPreparing), appErrors.ErrInvalidStatusTransition)
+		assert.ErrorIs(t, m.Validate(oModel.StatusDelivered, oModel.StatusDeleted), appErrors.ErrOrderAlreadyDelivered)
+		assert.Equal(t, repo.transitions, m.Transitions())
+	})
+}
@@ -0,0 +1,271 @@
+package orders
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	oModel "github.com/radamesvaz/bakery-app/model/orders"
+)
+
+const (
+	defaultOrderStreamPollInterval = 15 * time.Second
+	defaultOrderStreamBatchSize    = 100
+	defaultOrderStreamSettleWindow = 30 * time.Second
+)
+
+// OrderStreamRepository defines the orders_history reads behind the live order stream.
+type OrderStreamRepository interface {
+	SettledOrderHistoryID(ctx context.Context, tenantID uint64, settleWindow time.Duration) (uint64, error)
+	ListOrderStreamEvents(ctx context.Context, tenantID, afterID uint64, limit int) ([]oModel.OrderStreamEvent, error)
+}
+
+// OrderStreamBroker fans order change signals out to the open streams of a tenant. A signal
+// carries no data: subscribers re-read orders_history, so signals may be coalesced freely.
+type OrderStreamBroker struct {
+	mu     sync.Mutex
+	subs   map[uint64]map[chan struct{}]struct{}
+	closed bool
+}
+
+func NewOrderStreamBroker() *OrderStreamBroker {
+	return &OrderStreamBroker{subs: map[uint64]map[chan struct{}]struct{}{}}
+}
+
+// Subscribe registers a stream of tenantID. The returned channel receives a value after each
+// Publish and is closed by Close; cancel unregisters it.
+func (b *OrderStreamBroker) Subscribe(tenantID uint64) (<-chan struct{}, func()) {
+	ch := make(chan struct{}, 1)
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.closed {
+		close(ch)
+		return ch, func() {}
+	}
+	if b.subs[tenantID] == nil {
+		b.subs[tenantID] = map[chan struct{}]struct{}{}
+	}
+	b.subs[tenantID][ch] = struct{}{}
+
+	var once sync.Once
+	return ch, func() {
+		once.Do(func() {
+			b.mu.Lock()
+			defer b.mu.Unlock()
+			if _, ok := b.subs[tenantID][ch]; !ok {
+				return
+			}
+			delete(b.subs[tenantID], ch)
+			if len(b.subs[tenantID]) == 0 {
+				delete(b.subs, tenantID)
+			}
+			close(ch)
+		})
+	}
+}
+
+// Publish wakes every stream of tenantID without blocking.
+func (b *OrderStreamBroker) Publish(tenantID uint64) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	for ch := range b.subs[tenantID] {
+		wakeStream(ch)
+	}
+}
+
+// PublishAll wakes every stream, e.g. after the listener reconnected and may have missed signals.
+func (b *OrderStreamBroker) PublishAll() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	for _, subs := range b.subs {
+		for ch := range subs {
+			wakeStream(ch)
+		}
+	}
+}
+
+// Close ends every open stream; later subscriptions end immediately.
+func (b *OrderStreamBroker) Close() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.closed {
+		return
+	}
+	b.closed = true
+	for tenantID, subs := range b.subs {
+		for ch := range subs {
+			close(ch)
+		}
+		delete(b.subs, tenantID)
+	}
+}
+
+func wakeStream(ch chan struct{}) {
+	select {
+	case ch <- struct{}{}:
+	default:
+	}
+}
+
+// OrderStreamer pushes the order changes of a tenant as they are written to orders_history.
+// Broker signals trigger a read; PollInterval is both the keep-alive period and the fallback
+// read interval when signals are unavailable or lost.
+//
+// History ids are taken when a row is inserted but become visible when its transaction commits,
+// so a row can appear after rows with higher ids were already streamed. Every read therefore
+// re-reads the rows written during the last SettleWindow and skips the ids already sent, and the
+// resume id given to send only covers rows older than that window.
+type OrderStreamer struct {
+	Repo         OrderStreamRepository
+	Broker       *OrderStreamBroker
+	PollInterval time.Duration
+	BatchSize    int
+	// SettleWindow must exceed the longest transaction writing order history.
+	SettleWindow time.Duration
+	Now          func() time.Time
+}
+
+func NewOrderStreamer(repo OrderStreamRepository, broker *OrderStreamBroker) *OrderStreamer {
+	return &OrderStreamer{
+		Repo:         repo,
+		Broker:       broker,
+		PollInterval: defaultOrderStreamPollInterval,
+		BatchSize:    defaultOrderStreamBatchSize,
+		SettleWindow: defaultOrderStreamSettleWindow,
+		Now:          time.Now,
+	}
+}
+
+// streamCheckpoint is the highest id sent as of a read.
+type streamCheckpoint struct {
+	at time.Time
+	id uint64
+}
+
+// Stream calls send for every change of the tenant after lastEventID until ctx ends, send or
+// keepAlive fail, or the broker is closed. Without lastEventID only changes that become visible
+// after the stream opened are sent: it starts at the newest row older than SettleWindow and
+// skips the newer rows already visible, so a row still being committed is not lost. resumeID is
+// the id to resume from after a disconnect: every change up to it has been sent, while changes
+// after it may be sent again, so clients should drop events whose ID they already have.
+func (s *OrderStreamer) Stream(
+	ctx context.Context,
+	tenantID uint64,
+	lastEventID *uint64,
+	send func(e oModel.OrderStreamEvent, resumeID uint64) error,
+	keepAlive func() error,
+) error {
+	var wake <-chan struct{}
+	if s.Broker != nil {
+		ch, cancel := s.Broker.Subscribe(tenantID)
+		defer cancel()
+		wake = ch
+	}
+
+	now := s.Now
+	if now == nil {
+		now = time.Now
+	}
+	settleWindow := s.SettleWindow
+	if settleWindow < 0 {
+		settleWindow = 0
+	}
+
+	var after uint64
+	if lastEventID != nil {
+		after = *lastEventID
+	} else {
+		settledID, err := s.Repo.SettledOrderHistoryID(ctx, tenantID, settleWindow)
+		if err != nil {
+			return err
+		}
+		after = settledID
+	}
+
+	pollInterval := s.PollInterval
+	if pollInterval <= 0 {
+		pollInterval = defaultOrderStreamPollInterval
+	}
+	ticker := time.NewTicker(pollInterval)
+	defer ticker.Stop()
+
+	// floor is the settled resume id: reads start after it, ids above it that were already
+	// sent are in sent.
+	floor := after
+	sent := map[uint64]struct{}{}
+	var checkpoints []streamCheckpoint
+
+	// drain sends the unsent rows after floor; with skip it only marks them as sent.
+	drain := func(skip bool) error {
+		batchSize := s.BatchSize
+		if batchSize <= 0 {
+			batchSize = defaultOrderStreamBatchSize
+		}
+		readAt := now()
+		cursor := floor
+		for {
+			events, err := s.Repo.ListOrderStreamEvents(ctx, tenantID, cursor, batchSize)
+			if err != nil {
+				if ctx.Err() != nil {
+					return nil
+				}
+				return err
+			}
+			for _, e := range events {
+				cursor = e.ID
+				if _, ok := sent[e.ID]; ok {
+					continue
+				}
+				if !skip {
+					if err := send(e, floor); err != nil {
+						return fmt.Errorf("error sending order event %d: %w", e.ID, err)
+					}
+				}
+				sent[e.ID] = struct{}{}
+				if e.ID > after {
+					after = e.ID
+				}
+			}
+			if len(events) < batchSize {
+				break
+			}
+		}
+
+		// Rows up to a checkpoint older than the window have committed and were read, so the
+		// floor can move up to it.
+		checkpoints = append(checkpoints, streamCheckpoint{at: readAt, id: after})
+		settled := readAt.Add(-settleWindow)
+		for len(checkpoints) > 0 && !checkpoints[0].at.After(settled) {
+			floor = checkpoints[0].id
+			checkpoints = checkpoints[1:]
+		}
+		for id := range sent {
+			if id <= floor {
+				delete(sent, id)
+			}
+		}
+		return nil
+	}
+
+	if err := drain(lastEventID == nil); err != nil {
+		return err
+	}
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case _, ok := <-wake:
+			if !ok {
+				return nil
+			}
+		case <-ticker.C:
+			if err := keepAlive(); err != nil {
+				return fmt.Errorf("error sending keep-alive: %w", err)
+			}
+		}
+		if err := drain(false); err != nil {
+			return err
+		}
+	}
+}
@@ -0,0 +1,281 @@
+package orders
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"testing"
+	"time"
+
+	oModel "github.com/radamesvaz/bakery-app/model/orders"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// stubOrderStreamRepository serves events from an in-memory history guarded by a mutex, so a
+// test can append rows while a stream is open.
+type stubOrderStreamRepository struct {
+	mu     sync.Mutex
+	events []oModel.OrderStreamEvent
+	reads  int
+}
+
+// add makes e visible; rows may be added out of id order, like transactions committing out of order.
+func (s *stubOrderStreamRepository) add(e oModel.OrderStreamEvent) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.events = append(s.events, e)
+	sort.Slice(s.events, func(i, j int) bool { return s.events[i].ID < s.events[j].ID })
+}
+
+func (s *stubOrderStreamRepository) SettledOrderHistoryID(ctx context.Context, tenantID uint64, settleWindow time.Duration) (uint64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	settled := time.Now().Add(-settleWindow)
+	var id uint64
+	for _, e := range s.events {
+		if e.At.Before(settled) {
+			id = max(id, e.ID)
+		}
+	}
+	return id, nil
+}
+
+func (s *stubOrderStreamRepository) ListOrderStreamEvents(ctx context.Context, tenantID, afterID uint64, limit int) ([]oModel.OrderStreamEvent, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.reads++
+	out := []oModel.OrderStreamEvent{}
+	for _, e := range s.events {
+		if e.ID > afterID && len(out) < limit {
+			out = append(out, e)
+		}
+	}
+	return out, nil
+}
+
+func TestOrderStreamBroker_PublishWakesOnlyTheTenant(t *testing.T) {
+	broker := NewOrderStreamBroker()
+	first, cancelFirst := broker.Subscribe(1)
+	other, cancelOther := broker.Subscribe(2)
+	defer cancelOther()
+
+	broker.Publish(1)
+	broker.Publish(1)
+
+	assert.Len(t, first, 1, "signals are coalesced")
+	assert.Len(t, other, 0)
+
+	<-first
+	cancelFirst()
+	_, ok := <-first
+	assert.False(t, ok)
+	assert.NotPanics(t, func() { broker.Publish(1) })
+}
+
+func TestOrderStreamBroker_CloseEndsSubscriptions(t *testing.T) {
+	broker := NewOrderStreamBroker()
+	ch, cancel := broker.Subscribe(1)
+
+	broker.Close()
+	_, ok := <-ch
+	assert.False(t, ok)
+	assert.NotPanics(t, cancel)
+
+	late, _ := broker.Subscribe(1)
+	_, ok = <-late
+	assert.False(t, ok)
+}
+
+func TestOrderStreamer_ResumesAfterLastEventID(t *testing.T) {
+	repo := &stubOrderStreamRepository{}
+	for id := uint64(1); id <= 5; id++ {
+		repo.add(oModel.OrderStreamEvent{ID: id, IDOrder: 7})
+	}
+	streamer := NewOrderStreamer(repo, nil)
+	streamer.BatchSize = 2
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	var got []uint64
+	lastEventID := uint64(2)
+	err := streamer.Stream(ctx, 1, &lastEventID, func(e oModel.OrderStreamEvent, resumeID uint64) error {
+		got = append(got, e.ID)
+		if e.ID == 5 {
+			cancel()
+		}
+		return nil
+	}, func() error { return nil })
+
+	require.NoError(t, err)
+	assert.Equal(t, []uint64{3, 4, 5}, got)
+}
+
+func TestOrderStreamer_StartsAfterLatestWithoutLastEventID(t *testing.T) {
+	repo := &stubOrderStreamRepository{}
+	repo.add(oModel.OrderStreamEvent{ID: 1, IDOrder: 7})
+	streamer := NewOrderStreamer(repo, nil)
+	streamer.PollInterval = time.Millisecond
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	var got []uint64
+	err := streamer.Stream(ctx, 1, nil, func(e oModel.OrderStreamEvent, resumeID uint64) error {
+		got = append(got, e.ID)
+		return nil
+	}, func() error {
+		cancel()
+		return nil
+	})
+
+	require.NoError(t, err)
+	assert.Empty(t, got)
+}
+
+func TestOrderStreamer_SendsLateCommitsWithoutLastEventID(t *testing.T) {
+	repo := &stubOrderStreamRepository{}
+	repo.add(oModel.OrderStreamEvent{ID: 1, IDOrder: 7})
+	repo.add(oModel.OrderStreamEvent{ID: 3, IDOrder: 8, At: time.Now()})
+	broker := NewOrderStreamBroker()
+	streamer := NewOrderStreamer(repo, broker)
+	streamer.PollInterval = time.Hour
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	received := make(chan uint64, 10)
+	done := make(chan error, 1)
+	go func() {
+		done <- streamer.Stream(ctx, 1, nil, func(e oModel.OrderStreamEvent, resumeID uint64) error {
+			received <- e.ID
+			return nil
+		}, func() error { return nil })
+	}()
+
+	// 2 was still being committed when the stream opened: it is sent, while 3, already visible,
+	// is not.
+	require.Eventually(t, func() bool {
+		repo.mu.Lock()
+		defer repo.mu.Unlock()
+		return repo.reads > 0
+	}, 2*time.Second, time.Millisecond)
+	repo.add(oModel.OrderStreamEvent{ID: 2, IDOrder: 9, At: time.Now()})
+	var got uint64
+	require.Eventually(t, func() bool {
+		broker.Publish(1)
+		select {
+		case got = <-received:
+			return true
+		default:
+			return false
+		}
+	}, 2*time.Second, 10*time.Millisecond)
+	assert.Equal(t, uint64(2), got)
+
+	broker.Close()
+	assert.NoError(t, <-done)
+	assert.Empty(t, received)
+}
+
+func TestOrderStreamer_PushesPublishedChanges(t *testing.T) {
+	repo := &stubOrderStreamRepository{}
+	repo.add(oModel.OrderStreamEvent{ID: 1, IDOrder: 7})
+	broker := NewOrderStreamBroker()
+	streamer := NewOrderStreamer(repo, broker)
+	streamer.PollInterval = time.Hour
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	received := make(chan oModel.OrderStreamEvent, 1)
+	done := make(chan error, 1)
+	lastEventID := uint64(1)
+	go func() {
+		done <- streamer.Stream(ctx, 1, &lastEventID, func(e oModel.OrderStreamEvent, resumeID uint64) error {
+			received <- e
+			return nil
+		}, func() error { return nil })
+	}()
+
+	// Publish until the new row is read, since the stream may not have subscribed yet when the
+	// first signal is sent.
+	repo.add(oModel.OrderStreamEvent{ID: 2, IDOrder: 8, Type: oModel.StreamOrderCreated})
+	var e oModel.OrderStreamEvent
+	require.Eventually(t, func() bool {
+		broker.Publish(1)
+		select {
+		case e = <-received:
+			return true
+		default:
+			return false
+		}
+	}, 2*time.Second, 10*time.Millisecond)
+	assert.Equal(t, uint64(2), e.ID)
+
+	broker.Close()
+	assert.NoError(t, <-done)
+}
+
+func TestOrderStreamer_SendsRowsCommittedOutOfOrder(t *testing.T) {
+	repo := &stubOrderStreamRepository{}
+	repo.add(oModel.OrderStreamEvent{ID: 10, IDOrder: 7})
+	broker := NewOrderStreamBroker()
+	streamer := NewOrderStreamer(repo, broker)
+	streamer.PollInterval = time.Hour
+	streamer.SettleWindow = 30 * time.Second
+	var clockMu sync.Mutex
+	clock := time.Date(2026, 4, 1, 10, 0, 0, 0, time.UTC)
+	streamer.Now = func() time.Time {
+		clockMu.Lock()
+		defer clockMu.Unlock()
+		return clock
+	}
+	advance := func(d time.Duration) {
+		clockMu.Lock()
+		defer clockMu.Unlock()
+		clock = clock.Add(d)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	type sentEvent struct{ id, resumeID uint64 }
+	received := make(chan sentEvent, 10)
+	done := make(chan error, 1)
+	lastEventID := uint64(10)
+	go func() {
+		done <- streamer.Stream(ctx, 1, &lastEventID, func(e oModel.OrderStreamEvent, resumeID uint64) error {
+			received <- sentEvent{e.ID, resumeID}
+			return nil
+		}, func() error { return nil })
+	}()
+	next := func() sentEvent {
+		var got sentEvent
+		require.Eventually(t, func() bool {
+			broker.Publish(1)
+			select {
+			case got = <-received:
+				return true
+			default:
+				return false
+			}
+		}, 2*time.Second, 10*time.Millisecond)
+		return got
+	}
+
+	// 12 commits before 11: both are sent once, and the resume id stays below 11 until it settles.
+	repo.add(oModel.OrderStreamEvent{ID: 12, IDOrder: 8})
+	assert.Equal(t, sentEvent{12, 10}, next())
+	advance(time.Second)
+	repo.add(oModel.OrderStreamEvent{ID: 11, IDOrder: 9})
+	assert.Equal(t, sentEvent{11, 10}, next())
+
+	// The resume id only passes rows that were read at least a window ago.
+	advance(time.Minute)
+	repo.add(oModel.OrderStreamEvent{ID: 13, IDOrder: 9})
+	assert.Equal(t, sentEvent{13, 10}, next())
+	advance(time.Minute)
+	repo.add(oModel.OrderStreamEvent{ID: 14, IDOrder: 9})
+	assert.Equal(t, sentEvent{14, 12}, next())
+
+	broker.Close()
+	assert.NoError(t, <-done)
+	assert.Empty(t, received, "no event is sent twice")
+}
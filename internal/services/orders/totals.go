@@ -0,0 +1,62 @@
+package orders
+
+import (
+	"context"
+	"database/sql"
+	stdErrors "errors"
+
+	"github.com/radamesvaz/bakery-app/internal/errors"
+	"github.com/radamesvaz/bakery-app/model/money"
+	oModel "github.com/radamesvaz/bakery-app/model/orders"
+	pricingModel "github.com/radamesvaz/bakery-app/model/pricing"
+)
+
+// PricingRepository exposes the tenant's tax and delivery fee configuration.
+// It is implemented by the pricing repository.
+type PricingRepository interface {
+	GetSettingsTx(ctx context.Context, tx *sql.Tx, tenantID uint64) (pricingModel.Settings, error)
+	GetDeliveryZoneTx(ctx context.Context, tx *sql.Tx, tenantID, id uint64) (pricingModel.DeliveryZone, error)
+}
+
+// priceOrderTx computes the totals of an order whose lines add up to subtotal with discount off
+// them, using the tenant's current tax and delivery fee. It is the one place order totals are
+// calculated, both when an order is created and when its items change. A nil repo prices the
+// order without tax or delivery fee. zoneID must name an active zone of the tenant when
+// requireActiveZone is set (new orders); edits keep pricing with a zone deactivated since.
+// Pickup orders are taxed but never pay a delivery fee.
+func priceOrderTx(ctx context.Context, repo PricingRepository, tx *sql.Tx, tenantID uint64, fulfillment oModel.FulfillmentType, zoneID *uint64, requireActiveZone bool, subtotal, discount money.Amount) (pricingModel.Totals, error) {
+	if repo == nil {
+		if zoneID != nil && requireActiveZone {
+			return pricingModel.Totals{}, errors.ErrDeliveryZoneInvalid
+		}
+		return pricingModel.Calculate(subtotal, discount, pricingModel.Settings{}, nil), nil
+	}
+
+	settings, err := repo.GetSettingsTx(ctx, tx, tenantID)
+	if err != nil {
+		return pricingModel.Totals{}, err
+	}
+	if oModel.NormalizeFulfillmentType(fulfillment) == oModel.FulfillmentPickup {
+		settings.DeliveryFee = 0
+		return pricingModel.Calculate(subtotal, discount, settings, nil), nil
+	}
+
+	var zone *pricingModel.DeliveryZone
+	if zoneID != nil {
+		z, err := repo.GetDeliveryZoneTx(ctx, tx, tenantID, *zoneID)
+		switch {
+		case stdErrors.Is(err, errors.ErrDeliveryZoneNotFound):
+			if requireActiveZone {
+				return pricingModel.Totals{}, errors.ErrDeliveryZoneInvalid
+			}
+		case err != nil:
+			return pricingModel.Totals{}, err
+		case requireActiveZone && !z.Active:
+			return pricingModel.Totals{}, errors.ErrDeliveryZoneInvalid
+		default:
+			zone = &z
+		}
+	}
+
+	return pricingModel.Calculate(subtotal, discount, settings, zone), nil
+}
@@ -0,0 +1,96 @@
+package orders
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	internalErrors "github.com/radamesvaz/bakery-app/internal/errors"
+	"github.com/radamesvaz/bakery-app/model/money"
+	pricingModel "github.com/radamesvaz/bakery-app/model/pricing"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type stubPricingRepository struct {
+	settings pricingModel.Settings
+	zones    map[uint64]pricingModel.DeliveryZone
+}
+
+func (s stubPricingRepository) GetSettingsTx(ctx context.Context, tx *sql.Tx, tenantID uint64) (pricingModel.Settings, error) {
+	return s.settings, nil
+}
+
+func (s stubPricingRepository) GetDeliveryZoneTx(ctx context.Context, tx *sql.Tx, tenantID, id uint64) (pricingModel.DeliveryZone, error) {
+	zone, ok := s.zones[id]
+	if !ok {
+		return pricingModel.DeliveryZone{}, internalErrors.NewNotFound(internalErrors.ErrDeliveryZoneNotFound)
+	}
+	return zone, nil
+}
+
+func TestCreateOrder_PersistsTotalsBreakdown(t *testing.T) {
+	db, sqlMock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+	sqlMock.ExpectBegin()
+	sqlMock.ExpectCommit()
+
+	service, orderRepo := promotionCreator(db, nil)
+	service.Pricing = stubPricingRepository{
+		settings: pricingModel.Settings{TaxRate: 10, DeliveryFee: 200},
+		zones:    map[uint64]pricingModel.DeliveryZone{7: {ID: 7, Fee: 300, Active: true}},
+	}
+	zoneID := uint64(7)
+	payload := promotionOrderPayload("")
+	payload.IDDeliveryZone = &zoneID
+	deliveryDate, _ := time.Parse("2006-01-02", payload.DeliveryDate)
+
+	_, err = service.CreateOrder(context.Background(), 1, payload, deliveryDate)
+
+	require.NoError(t, err)
+	assert.Equal(t, money.Amount(1180), orderRepo.LastOrder.Subtotal)
+	assert.Equal(t, 10.0, orderRepo.LastOrder.TaxRate)
+	assert.Equal(t, money.Amount(118), orderRepo.LastOrder.TaxAmount)
+	assert.Equal(t, money.Amount(300), orderRepo.LastOrder.DeliveryFee)
+	assert.Equal(t, money.Amount(1598), orderRepo.LastOrder.Price)
+	require.NotNil(t, orderRepo.LastOrder.IDDeliveryZone)
+	assert.Equal(t, uint64(7), *orderRepo.LastOrder.IDDeliveryZone)
+	require.NoError(t, sqlMock.ExpectationsWereMet())
+}
+
+func TestCreateOrder_RejectsUnavailableDeliveryZone(t *testing.T) {
+	tests := []struct {
+		name   string
+		zoneID uint64
+	}{
+		{name: "unknown zone", zoneID: 9},
+		{name: "inactive zone", zoneID: 8},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db, sqlMock, err := sqlmock.New()
+			require.NoError(t, err)
+			defer db.Close()
+			sqlMock.ExpectBegin()
+			sqlMock.ExpectRollback()
+
+			service, orderRepo := promotionCreator(db, nil)
+			service.Pricing = stubPricingRepository{
+				zones: map[uint64]pricingModel.DeliveryZone{8: {ID: 8, Fee: 300, Active: false}},
+			}
+			payload := promotionOrderPayload("")
+			payload.IDDeliveryZone = &tt.zoneID
+			deliveryDate, _ := time.Parse("2006-01-02", payload.DeliveryDate)
+
+			_, err = service.CreateOrder(context.Background(), 1, payload, deliveryDate)
+
+			assert.ErrorIs(t, err, internalErrors.ErrDeliveryZoneInvalid)
+			assert.False(t, orderRepo.OrderCreated)
+			require.NoError(t, sqlMock.ExpectationsWereMet())
+		})
+	}
+}
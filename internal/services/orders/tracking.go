@@ -0,0 +1,147 @@
+package orders
+
+import (
+	"context"
+	stdErrors "errors"
+	"fmt"
+	"strings"
+
+	"github.com/radamesvaz/bakery-app/internal/errors"
+	"github.com/radamesvaz/bakery-app/internal/services/tokens"
+	oModel "github.com/radamesvaz/bakery-app/model/orders"
+)
+
+const (
+	defaultCustomerCancellationReason = "Cancelled by customer"
+	maxCancellationReasonLength       = 255
+)
+
+// OrderTrackingRepository defines the reads needed to serve the public tracking link.
+type OrderTrackingRepository interface {
+	GetOrderIDByTrackingTokenHash(ctx context.Context, tenantID uint64, tokenHash string) (uint64, error)
+	GetOrderByID(ctx context.Context, tenantID, id uint64) (oModel.OrderResponse, error)
+	GetOrderHistoryTimeline(ctx context.Context, tenantID, orderID uint64) ([]oModel.OrderHistoryEntry, error)
+}
+
+// OrderStatusChanger applies a status transition from a known status (implemented by StatusUpdaterWithStock).
+type OrderStatusChanger interface {
+	UpdateOrderStatusFrom(ctx context.Context, tenantID, orderID uint64, from, newStatus oModel.OrderStatus, userID uint64, isAdmin bool, cancellationReason *string, paidOverride *bool) error
+}
+
+// Tracker serves customer self-service through the tracking token returned on order creation.
+type Tracker struct {
+	OrderRepo     OrderTrackingRepository
+	Tokens        tokens.OneTimeTokenManager
+	StatusUpdater OrderStatusChanger
+}
+
+func NewTracker(orderRepo OrderTrackingRepository, tokenManager tokens.OneTimeTokenManager, statusUpdater OrderStatusChanger) *Tracker {
+	return &Tracker{
+		OrderRepo:     orderRepo,
+		Tokens:        tokenManager,
+		StatusUpdater: statusUpdater,
+	}
+}
+
+func (t *Tracker) resolveOrder(ctx context.Context, tenantID uint64, token string) (oModel.OrderResponse, error) {
+	if strings.TrimSpace(token) == "" {
+		return oModel.OrderResponse{}, errors.NewNotFound(errors.ErrOrderNotFound)
+	}
+	orderID, err := t.OrderRepo.GetOrderIDByTrackingTokenHash(ctx, tenantID, t.Tokens.Hash(token))
+	if err != nil {
+		return oModel.OrderResponse{}, err
+	}
+	return t.OrderRepo.GetOrderByID(ctx, tenantID, orderID)
+}
+
+// Track returns the public view of the order behind token. Unknown tokens and tokens of other
+// tenants are reported as not found.
+func (t *Tracker) Track(ctx context.Context, tenantID uint64, token string) (oModel.OrderTrackingResponse, error) {
+	order, err := t.resolveOrder(ctx, tenantID, token)
+	if err != nil {
+		return oModel.OrderTrackingResponse{}, err
+	}
+	return t.buildTrackingResponse(ctx, tenantID, order)
+}
+
+// Cancel lets the customer cancel their own order while it is still pending. Stock is reverted
+// in the same transaction as the status change and the customer is recorded as the actor. The change
+// only applies while the order is still pending, so it cannot race staff or the expiry worker.
+func (t *Tracker) Cancel(ctx context.Context, tenantID uint64, token string, reason *string) (oModel.OrderTrackingResponse, error) {
+	order, err := t.resolveOrder(ctx, tenantID, token)
+	if err != nil {
+		return oModel.OrderTrackingResponse{}, err
+	}
+	if order.Status != oModel.StatusPending {
+		return oModel.OrderTrackingResponse{}, errors.ErrOrderNotCancellableByCustomer
+	}
+
+	cancellationReason := defaultCustomerCancellationReason
+	if reason != nil && strings.TrimSpace(*reason) != "" {
+		cancellationReason = strings.TrimSpace(*reason)
+	}
+	if runes := []rune(cancellationReason); len(runes) > maxCancellationReasonLength {
+		cancellationReason = string(runes[:maxCancellationReasonLength])
+	}
+
+	// isAdmin=true: a customer cancel releases the reserved stock just like a staff cancel.
+	err = t.StatusUpdater.UpdateOrderStatusFrom(ctx, tenantID, order.ID, oModel.StatusPending, oModel.StatusCancelled, order.IdUser, true, &cancellationReason, nil)
+	if stdErrors.Is(err, errors.ErrOrderStatusChanged) {
+		return oModel.OrderTrackingResponse{}, errors.ErrOrderNotCancellableByCustomer
+	}
+	if err != nil {
+		return oModel.OrderTrackingResponse{}, err
+	}
+
+	order, err = t.OrderRepo.GetOrderByID(ctx, tenantID, order.ID)
+	if err != nil {
+		return oModel.OrderTrackingResponse{}, err
+	}
+	return t.buildTrackingResponse(ctx, tenantID, order)
+}
+
+func (t *Tracker) buildTrackingResponse(ctx context.Context, tenantID uint64, order oModel.OrderResponse) (oModel.OrderTrackingResponse, error) {
+	entries, err := t.OrderRepo.GetOrderHistoryTimeline(ctx, tenantID, order.ID)
+	if err != nil {
+		return oModel.OrderTrackingResponse{}, fmt.Errorf("error getting order history: %w", err)
+	}
+
+	items := make([]oModel.OrderTrackingItem, len(order.OrderItems))
+	for i, item := range order.OrderItems {
+		items[i] = oModel.OrderTrackingItem{
+			Name:      item.Name,
+			Quantity:  item.Quantity,
+			UnitPrice: item.UnitPrice,
+		}
+	}
+
+	return oModel.OrderTrackingResponse{
+		IDOrder:            order.ID,
+		Status:             order.Status,
+		TotalPrice:         order.Price,
+		Currency:           order.Currency,
+		Paid:               order.Paid,
+		AmountPaid:         order.AmountPaid,
+		BalanceDue:         order.BalanceDue,
+		DeliveryDate:       order.DeliveryDate,
+		FulfillmentType:    order.FulfillmentType,
+		IDPickupLocation:   order.IDPickupLocation,
+		CreatedOn:          order.CreatedOn,
+		CancellationReason: order.CancellationReason,
+		CanCancel:          order.Status == oModel.StatusPending,
+		Items:              items,
+		Timeline:           buildTrackingTimeline(entries),
+	}, nil
+}
+
+// buildTrackingTimeline keeps only the history entries where the status changed (oldest first).
+func buildTrackingTimeline(entries []oModel.OrderHistoryEntry) []oModel.OrderTrackingEvent {
+	timeline := []oModel.OrderTrackingEvent{}
+	for i, entry := range entries {
+		if i > 0 && entries[i-1].Status == entry.Status {
+			continue
+		}
+		timeline = append(timeline, oModel.OrderTrackingEvent{Status: entry.Status, At: entry.ModifiedOn})
+	}
+	return timeline
+}
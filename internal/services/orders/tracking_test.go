@@ -0,0 +1,165 @@
+package orders
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+	"unicode/utf8"
+
+	"github.com/radamesvaz/bakery-app/internal/errors"
+	"github.com/radamesvaz/bakery-app/internal/services/tokens"
+	oModel "github.com/radamesvaz/bakery-app/model/orders"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+type MockOrderTrackingRepository struct {
+	mock.Mock
+}
+
+func (m *MockOrderTrackingRepository) GetOrderIDByTrackingTokenHash(ctx context.Context, tenantID uint64, tokenHash string) (uint64, error) {
+	args := m.Called(ctx, tenantID, tokenHash)
+	return args.Get(0).(uint64), args.Error(1)
+}
+
+func (m *MockOrderTrackingRepository) GetOrderByID(ctx context.Context, tenantID, id uint64) (oModel.OrderResponse, error) {
+	args := m.Called(ctx, tenantID, id)
+	return args.Get(0).(oModel.OrderResponse), args.Error(1)
+}
+
+func (m *MockOrderTrackingRepository) GetOrderHistoryTimeline(ctx context.Context, tenantID, orderID uint64) ([]oModel.OrderHistoryEntry, error) {
+	args := m.Called(ctx, tenantID, orderID)
+	return args.Get(0).([]oModel.OrderHistoryEntry), args.Error(1)
+}
+
+type MockOrderStatusChanger struct {
+	mock.Mock
+}
+
+func (m *MockOrderStatusChanger) UpdateOrderStatusFrom(ctx context.Context, tenantID, orderID uint64, from, newStatus oModel.OrderStatus, userID uint64, isAdmin bool, cancellationReason *string, paidOverride *bool) error {
+	args := m.Called(ctx, tenantID, orderID, from, newStatus, userID, isAdmin, cancellationReason, paidOverride)
+	return args.Error(0)
+}
+
+const testTrackingToken = "ABCDEF0123"
+
+func newTrackerForTest() (*Tracker, *MockOrderTrackingRepository, *MockOrderStatusChanger, string) {
+	repo := new(MockOrderTrackingRepository)
+	updater := new(MockOrderStatusChanger)
+	tokenManager := tokens.NewSHA256OneTimeTokenManager(32)
+	return NewTracker(repo, tokenManager, updater), repo, updater, tokenManager.Hash(testTrackingToken)
+}
+
+func TestTracker_Track_ReturnsPublicView(t *testing.T) {
+	tracker, repo, _, hash := newTrackerForTest()
+
+	created := time.Date(2026, 4, 1, 10, 0, 0, 0, time.UTC)
+	preparing := created.Add(time.Hour)
+	order := oModel.OrderResponse{
+		ID:         5,
+		TenantID:   1,
+		IdUser:     7,
+		Phone:      "555-0101",
+		Status:     oModel.StatusPreparing,
+		Price:      12,
+		BalanceDue: 12,
+		OrderItems: []oModel.OrderItems{{ID: 1, IdOrder: 5, IdProduct: 3, Name: "Brownie", UnitPrice: 6, Quantity: 2}},
+	}
+	repo.On("GetOrderIDByTrackingTokenHash", mock.Anything, uint64(1), hash).Return(uint64(5), nil)
+	repo.On("GetOrderByID", mock.Anything, uint64(1), uint64(5)).Return(order, nil)
+	repo.On("GetOrderHistoryTimeline", mock.Anything, uint64(1), uint64(5)).Return([]oModel.OrderHistoryEntry{
+		{Status: oModel.StatusPending, ModifiedOn: &created},
+		{Status: oModel.StatusPending, ModifiedOn: &created},
+		{Status: oModel.StatusPreparing, ModifiedOn: &preparing},
+	}, nil)
+
+	// Tokens are case-insensitive, like the other one-time tokens.
+	tracking, err := tracker.Track(context.Background(), 1, "abcdef0123")
+
+	require.NoError(t, err)
+	assert.Equal(t, uint64(5), tracking.IDOrder)
+	assert.False(t, tracking.CanCancel)
+	assert.Equal(t, []oModel.OrderTrackingItem{{Name: "Brownie", Quantity: 2, UnitPrice: 6}}, tracking.Items)
+	assert.Equal(t, []oModel.OrderTrackingEvent{
+		{Status: oModel.StatusPending, At: &created},
+		{Status: oModel.StatusPreparing, At: &preparing},
+	}, tracking.Timeline)
+}
+
+func TestTracker_Track_UnknownToken(t *testing.T) {
+	tracker, repo, _, hash := newTrackerForTest()
+	repo.On("GetOrderIDByTrackingTokenHash", mock.Anything, uint64(2), hash).Return(uint64(0), errors.NewNotFound(errors.ErrOrderNotFound))
+
+	_, err := tracker.Track(context.Background(), 2, testTrackingToken)
+
+	var httpErr *errors.HTTPError
+	require.ErrorAs(t, err, &httpErr)
+	assert.Equal(t, 404, httpErr.StatusCode)
+}
+
+func TestTracker_Cancel_PendingOrderRevertsStock(t *testing.T) {
+	tracker, repo, updater, hash := newTrackerForTest()
+
+	pending := oModel.OrderResponse{ID: 5, TenantID: 1, IdUser: 7, Status: oModel.StatusPending}
+	reason := "Cancelled by customer"
+	cancelled := pending
+	cancelled.Status = oModel.StatusCancelled
+	cancelled.CancellationReason = &reason
+
+	repo.On("GetOrderIDByTrackingTokenHash", mock.Anything, uint64(1), hash).Return(uint64(5), nil)
+	repo.On("GetOrderByID", mock.Anything, uint64(1), uint64(5)).Return(pending, nil).Once()
+	repo.On("GetOrderByID", mock.Anything, uint64(1), uint64(5)).Return(cancelled, nil).Once()
+	repo.On("GetOrderHistoryTimeline", mock.Anything, uint64(1), uint64(5)).Return([]oModel.OrderHistoryEntry{}, nil)
+	updater.On("UpdateOrderStatusFrom", mock.Anything, uint64(1), uint64(5), oModel.StatusPending, oModel.StatusCancelled, uint64(7), true,
+		mock.MatchedBy(func(r *string) bool { return r != nil && *r == reason }), (*bool)(nil)).Return(nil)
+
+	tracking, err := tracker.Cancel(context.Background(), 1, testTrackingToken, nil)
+
+	require.NoError(t, err)
+	assert.Equal(t, oModel.StatusCancelled, tracking.Status)
+	updater.AssertExpectations(t)
+}
+
+func TestTracker_Cancel_RejectsNonPendingOrder(t *testing.T) {
+	tracker, repo, updater, hash := newTrackerForTest()
+	repo.On("GetOrderIDByTrackingTokenHash", mock.Anything, uint64(1), hash).Return(uint64(5), nil)
+	repo.On("GetOrderByID", mock.Anything, uint64(1), uint64(5)).Return(oModel.OrderResponse{ID: 5, Status: oModel.StatusPreparing}, nil)
+
+	_, err := tracker.Cancel(context.Background(), 1, testTrackingToken, nil)
+
+	assert.ErrorIs(t, err, errors.ErrOrderNotCancellableByCustomer)
+	updater.AssertNotCalled(t, "UpdateOrderStatusFrom", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestTracker_Cancel_StatusChangedConcurrently(t *testing.T) {
+	tracker, repo, updater, hash := newTrackerForTest()
+	repo.On("GetOrderIDByTrackingTokenHash", mock.Anything, uint64(1), hash).Return(uint64(5), nil)
+	repo.On("GetOrderByID", mock.Anything, uint64(1), uint64(5)).Return(oModel.OrderResponse{ID: 5, IdUser: 7, Status: oModel.StatusPending}, nil)
+	updater.On("UpdateOrderStatusFrom", mock.Anything, uint64(1), uint64(5), oModel.StatusPending, oModel.StatusCancelled, uint64(7), true, mock.Anything, (*bool)(nil)).
+		Return(fmt.Errorf("error updating order status: %w", errors.ErrOrderStatusChanged))
+
+	_, err := tracker.Cancel(context.Background(), 1, testTrackingToken, nil)
+
+	assert.ErrorIs(t, err, errors.ErrOrderNotCancellableByCustomer)
+}
+
+func TestTracker_Cancel_TruncatesReasonByRunes(t *testing.T) {
+	tracker, repo, updater, hash := newTrackerForTest()
+	pending := oModel.OrderResponse{ID: 5, IdUser: 7, Status: oModel.StatusPending}
+	repo.On("GetOrderIDByTrackingTokenHash", mock.Anything, uint64(1), hash).Return(uint64(5), nil)
+	repo.On("GetOrderByID", mock.Anything, uint64(1), uint64(5)).Return(pending, nil)
+	repo.On("GetOrderHistoryTimeline", mock.Anything, uint64(1), uint64(5)).Return([]oModel.OrderHistoryEntry{}, nil)
+	updater.On("UpdateOrderStatusFrom", mock.Anything, uint64(1), uint64(5), oModel.StatusPending, oModel.StatusCancelled, uint64(7), true,
+		mock.MatchedBy(func(r *string) bool {
+			return r != nil && utf8.ValidString(*r) && utf8.RuneCountInString(*r) == maxCancellationReasonLength
+		}), (*bool)(nil)).Return(nil)
+
+	reason := strings.Repeat("ñ", maxCancellationReasonLength+10)
+	_, err := tracker.Cancel(context.Background(), 1, testTrackingToken, &reason)
+
+	require.NoError(t, err)
+	updater.AssertExpectations(t)
+}
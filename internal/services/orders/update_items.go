@@ -0,0 +1,303 @@
+package orders
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"sort"
+
+	"github.com/radamesvaz/bakery-app/internal/errors"
+	"github.com/radamesvaz/bakery-app/internal/logger"
+	"github.com/radamesvaz/bakery-app/model/money"
+	oModel "github.com/radamesvaz/bakery-app/model/orders"
+	pricingModel "github.com/radamesvaz/bakery-app/model/pricing"
+	pModel "github.com/radamesvaz/bakery-app/model/products"
+	promoModel "github.com/radamesvaz/bakery-app/model/promotions"
+)
+
+// OrderItemsRepository defines the order operations needed to replace the items of an order
+type OrderItemsRepository interface {
+	BeginTx(ctx context.Context) (*sql.Tx, error)
+	GetOrderByID(ctx context.Context, tenantID, id uint64) (oModel.OrderResponse, error)
+	LockOrderPaymentStateTx(ctx context.Context, tx *sql.Tx, tenantID, orderID uint64) (oModel.OrderPaymentState, error)
+	GetOrderItemsByOrderIDTx(ctx context.Context, tx *sql.Tx, tenantID, orderID uint64) ([]oModel.OrderItems, error)
+	DeleteOrderItemsTx(ctx context.Context, tx *sql.Tx, tenantID, orderID uint64) error
+	CreateOrderItems(ctx context.Context, tx *sql.Tx, tenantID uint64, items []oModel.OrderItemRequest) error
+	UpdateOrderTotalsTx(ctx context.Context, tx *sql.Tx, tenantID, orderID uint64, totals pricingModel.Totals) error
+	UpdateOrderPaidStatusTx(ctx context.Context, tx *sql.Tx, tenantID, orderID uint64, paid bool) error
+	CreateOrderHistoryTx(ctx context.Context, tx *sql.Tx, order oModel.OrderHistory) error
+}
+
+// ProductItemsRepository defines the product operations needed to move stock when items change
+type ProductItemsRepository interface {
+	GetProductsByIDs(ctx context.Context, tenantID uint64, ids []uint64) ([]pModel.Product, error)
+	AssertProductActiveTx(ctx context.Context, tx *sql.Tx, tenantID, idProduct uint64) (trackInventory bool, err error)
+	DecrementProductStockTx(ctx context.Context, tx *sql.Tx, tenantID, idProduct uint64, quantity uint64) (int64, error)
+	RevertProductStockTx(ctx context.Context, tx *sql.Tx, tenantID, idProduct uint64, quantityToRevert uint64) error
+}
+
+// ItemsUpdater replaces the line items of an existing order.
+type ItemsUpdater struct {
+	OrderRepo   OrderItemsRepository
+	ProductRepo ProductItemsRepository
+	// Events receives product.out_of_stock for products drained by the edit; nil disables webhooks.
+	Events OrderEventPublisher
+	// Promotions re-applies the order's promotion to the new lines; nil keeps the discount already
+	// granted (capped at the new subtotal).
+	Promotions PromotionReader
+	// Pricing re-prices the order with the tenant's current tax and delivery fee; nil keeps the tax
+	// rate and delivery fee the order was placed with.
+	Pricing PricingRepository
+	// Capacity holds edits that add units to the delivery day's unit limit; nil accepts any edit.
+	Capacity DeliveryCapacityRepository
+}
+
+func NewItemsUpdater(orderRepo OrderItemsRepository, productRepo ProductItemsRepository) *ItemsUpdater {
+	return &ItemsUpdater{
+		OrderRepo:   orderRepo,
+		ProductRepo: productRepo,
+	}
+}
+
+// isItemsEditableStatus reports whether the kitchen has not finished the order yet.
+func isItemsEditableStatus(status oModel.OrderStatus) bool {
+	return status == oModel.StatusPending || status == oModel.StatusPreparing
+}
+
+// UpdateOrderItems replaces the order lines with items in one transaction: only the quantity
+// difference per product is reserved or reverted, names/prices are re-snapshotted from the
+// current catalog, total_price and the promotion discount are recomputed, paid is re-derived from
+// the payment ledger against the new total and an orders_history row is written.
+// Only allowed while the order is pending or preparing. New lines and lines whose quantity grows
+// need an active product; lines kept or lowered may hold products that are no longer sold and keep
+// the name and price they were ordered at. Edits that add units must fit the unit limit of the
+// delivery day.
+func (u *ItemsUpdater) UpdateOrderItems(ctx context.Context, tenantID, orderID uint64, items []oModel.CreateOrderItemInput, userID uint64) (oModel.OrderResponse, error) {
+	order, err := u.OrderRepo.GetOrderByID(ctx, tenantID, orderID)
+	if err != nil {
+		return oModel.OrderResponse{}, err
+	}
+	if !isItemsEditableStatus(order.Status) {
+		return oModel.OrderResponse{}, errors.ErrOrderItemsNotEditable
+	}
+
+	mergedItems := mergeOrderItemsByProduct(items)
+
+	productIDs := make([]uint64, len(mergedItems))
+	for i, item := range mergedItems {
+		productIDs[i] = item.IdProduct
+	}
+
+	products, err := u.ProductRepo.GetProductsByIDs(ctx, tenantID, productIDs)
+	if err != nil {
+		return oModel.OrderResponse{}, fmt.Errorf("error getting products: %w", err)
+	}
+	if len(products) != len(productIDs) {
+		return oModel.OrderResponse{}, errors.ErrProductNotFound
+	}
+
+	// Whether a product may be ordered is checked under its row lock in applyStockDelta, and only
+	// for the lines that take more of it.
+	productMap := make(map[uint64]pModel.Product, len(products))
+	for _, p := range products {
+		productMap[p.ID] = p
+	}
+
+	tx, err := u.OrderRepo.BeginTx(ctx)
+	if err != nil {
+		return oModel.OrderResponse{}, fmt.Errorf("error beginning transaction: %w", err)
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	// Re-check status under the row lock so a concurrent status change or payment cannot slip in.
+	state, err := u.OrderRepo.LockOrderPaymentStateTx(ctx, tx, tenantID, orderID)
+	if err != nil {
+		return oModel.OrderResponse{}, err
+	}
+	status := state.Status
+	if !isItemsEditableStatus(status) {
+		return oModel.OrderResponse{}, errors.ErrOrderItemsNotEditable
+	}
+
+	currentItems, err := u.OrderRepo.GetOrderItemsByOrderIDTx(ctx, tx, tenantID, orderID)
+	if err != nil {
+		return oModel.OrderResponse{}, fmt.Errorf("error getting order items: %w", err)
+	}
+
+	if u.Capacity != nil {
+		addedUnits := 0
+		for _, item := range mergedItems {
+			addedUnits += int(item.Quantity)
+		}
+		for _, item := range currentItems {
+			addedUnits -= int(item.Quantity)
+		}
+		if addedUnits > 0 {
+			if err := reserveAddedUnitsTx(ctx, u.Capacity, tx, tenantID, order.DeliveryDate, addedUnits); err != nil {
+				return oModel.OrderResponse{}, err
+			}
+		}
+	}
+
+	decrementedProductIDs, err := u.applyStockDelta(ctx, tx, tenantID, currentItems, mergedItems)
+	if err != nil {
+		return oModel.OrderResponse{}, err
+	}
+
+	if err := u.OrderRepo.DeleteOrderItemsTx(ctx, tx, tenantID, orderID); err != nil {
+		return oModel.OrderResponse{}, err
+	}
+
+	currentSnapshots := make(map[uint64]oModel.OrderItems, len(currentItems))
+	for _, item := range currentItems {
+		currentSnapshots[item.IdProduct] = item
+	}
+
+	lines := make([]promoModel.Line, len(mergedItems))
+	orderItems := make([]oModel.OrderItemRequest, len(mergedItems))
+	for i, item := range mergedItems {
+		product := productMap[item.IdProduct]
+		name, price := product.Name, product.Price
+		if current, ok := currentSnapshots[item.IdProduct]; ok && product.Status != pModel.StatusActive {
+			name, price = current.Name, current.UnitPrice
+		}
+		lines[i] = promoModel.Line{IdProduct: item.IdProduct, Amount: price.Times(item.Quantity)}
+		orderItems[i] = oModel.OrderItemRequest{
+			IdOrder:             orderID,
+			IdProduct:           item.IdProduct,
+			ProductNameSnapshot: name,
+			UnitPriceSnapshot:   price,
+			Quantity:            item.Quantity,
+		}
+	}
+	if err := u.OrderRepo.CreateOrderItems(ctx, tx, tenantID, orderItems); err != nil {
+		return oModel.OrderResponse{}, fmt.Errorf("error creating order items: %w", err)
+	}
+
+	discount, err := u.repriceDiscountTx(ctx, tx, tenantID, order, lines)
+	if err != nil {
+		return oModel.OrderResponse{}, err
+	}
+	totals, err := u.repriceTotalsTx(ctx, tx, tenantID, order, promoModel.Subtotal(lines), discount)
+	if err != nil {
+		return oModel.OrderResponse{}, err
+	}
+	if err := u.OrderRepo.UpdateOrderTotalsTx(ctx, tx, tenantID, orderID, totals); err != nil {
+		return oModel.OrderResponse{}, err
+	}
+
+	// A grown total can leave a paid order with a balance due, a shrunk one can settle it.
+	paid := oModel.IsFullyPaid(totals.Total, state.AmountPaid)
+	if paid != state.Paid {
+		if err := u.OrderRepo.UpdateOrderPaidStatusTx(ctx, tx, tenantID, orderID, paid); err != nil {
+			return oModel.OrderResponse{}, fmt.Errorf("error updating order paid status: %w", err)
+		}
+	}
+
+	order.Status = status
+	order.Price = totals.Total
+	order.DiscountAmount = totals.DiscountAmount
+	order.Paid = paid
+	orderHistory := buildStatusUpdateHistory(tenantID, orderID, order, status, userID, order.CancellationReason, order.Paid)
+	if err := u.OrderRepo.CreateOrderHistoryTx(ctx, tx, orderHistory); err != nil {
+		logger.Warn().Err(err).
+			Uint64("order_id", orderID).
+			Msg("Failed to create order history")
+		// History is best-effort; still commit items/stock/total
+	}
+
+	if u.Events != nil {
+		if err := u.Events.EnqueueOutOfStockTx(ctx, tx, tenantID, decrementedProductIDs); err != nil {
+			return oModel.OrderResponse{}, err
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return oModel.OrderResponse{}, fmt.Errorf("error committing transaction: %w", err)
+	}
+
+	return u.OrderRepo.GetOrderByID(ctx, tenantID, orderID)
+}
+
+// repriceDiscountTx returns the discount of order once its lines become lines and records it on the
+// order's redemption. The promotion is applied again as it stands now (a minimum no longer met
+// drops the discount to 0); its validity window and usage limits are not re-checked because the
+// code was already redeemed by this order, and the redemption keeps counting as a use so a later
+// edit can grant the discount again.
+func (u *ItemsUpdater) repriceDiscountTx(ctx context.Context, tx *sql.Tx, tenantID uint64, order oModel.OrderResponse, lines []promoModel.Line) (money.Amount, error) {
+	subtotal := promoModel.Subtotal(lines)
+	if order.IDPromotion == nil || u.Promotions == nil {
+		return min(order.DiscountAmount, subtotal), nil
+	}
+	promo, err := u.Promotions.GetPromotionByIDTx(ctx, tx, tenantID, *order.IDPromotion)
+	if err != nil {
+		return 0, err
+	}
+	var discount money.Amount
+	if promo.MeetsMinimum(lines) {
+		discount = promo.Discount(lines)
+	}
+	if discount != order.DiscountAmount {
+		if err := u.Promotions.UpdateRedemptionDiscountTx(ctx, tx, tenantID, order.ID, discount); err != nil {
+			return 0, err
+		}
+	}
+	return discount, nil
+}
+
+// repriceTotalsTx prices the edited order. Without a pricing repository the order keeps the tax
+// rate and delivery fee it was placed with.
+func (u *ItemsUpdater) repriceTotalsTx(ctx context.Context, tx *sql.Tx, tenantID uint64, order oModel.OrderResponse, subtotal, discount money.Amount) (pricingModel.Totals, error) {
+	if u.Pricing == nil {
+		placed := pricingModel.Settings{TaxRate: order.TaxRate, TaxInclusive: order.TaxInclusive, DeliveryFee: order.DeliveryFee}
+		return pricingModel.Calculate(subtotal, discount, placed, nil), nil
+	}
+	return priceOrderTx(ctx, u.Pricing, tx, tenantID, order.FulfillmentType, order.IDDeliveryZone, false, subtotal, discount)
+}
+
+// applyStockDelta reserves stock for quantities that grew and reverts it for quantities that shrank
+// or lines that were removed. Products are visited in id order so concurrent edits lock rows consistently.
+// Returns the tracked products whose stock was decremented.
+func (u *ItemsUpdater) applyStockDelta(ctx context.Context, tx *sql.Tx, tenantID uint64, currentItems []oModel.OrderItems, newItems []oModel.CreateOrderItemInput) ([]uint64, error) {
+	delta := make(map[uint64]int64)
+	for _, item := range currentItems {
+		delta[item.IdProduct] -= int64(item.Quantity)
+	}
+	for _, item := range newItems {
+		delta[item.IdProduct] += int64(item.Quantity)
+	}
+
+	productIDs := make([]uint64, 0, len(delta))
+	for id := range delta {
+		productIDs = append(productIDs, id)
+	}
+	sort.Slice(productIDs, func(i, j int) bool { return productIDs[i] < productIDs[j] })
+
+	var decremented []uint64
+	for _, idProduct := range productIDs {
+		d := delta[idProduct]
+		switch {
+		case d > 0:
+			trackInventory, err := u.ProductRepo.AssertProductActiveTx(ctx, tx, tenantID, idProduct)
+			if err != nil {
+				return nil, err
+			}
+			if !trackInventory {
+				continue
+			}
+			rows, err := u.ProductRepo.DecrementProductStockTx(ctx, tx, tenantID, idProduct, uint64(d))
+			if err != nil {
+				return nil, fmt.Errorf("error reserving stock: %w", err)
+			}
+			if rows == 0 {
+				return nil, errors.ErrNotEnoughProductStock
+			}
+			decremented = append(decremented, idProduct)
+		case d < 0:
+			if err := u.ProductRepo.RevertProductStockTx(ctx, tx, tenantID, idProduct, uint64(-d)); err != nil {
+				return nil, fmt.Errorf("error reverting stock for product %d: %w", idProduct, err)
+			}
+		}
+	}
+	return decremented, nil
+}
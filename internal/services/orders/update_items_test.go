@@ -0,0 +1,364 @@
+package orders
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	appErrors "github.com/radamesvaz/bakery-app/internal/errors"
+	capModel "github.com/radamesvaz/bakery-app/model/capacity"
+	"github.com/radamesvaz/bakery-app/model/money"
+	oModel "github.com/radamesvaz/bakery-app/model/orders"
+	pricingModel "github.com/radamesvaz/bakery-app/model/pricing"
+	pModel "github.com/radamesvaz/bakery-app/model/products"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// MockOrderItemsRepository for testing order item edits
+type MockOrderItemsRepository struct {
+	mock.Mock
+	DB *sql.DB
+}
+
+func (m *MockOrderItemsRepository) BeginTx(ctx context.Context) (*sql.Tx, error) {
+	return m.DB.BeginTx(ctx, nil)
+}
+
+func (m *MockOrderItemsRepository) GetOrderByID(ctx context.Context, tenantID, id uint64) (oModel.OrderResponse, error) {
+	args := m.Called(ctx, tenantID, id)
+	return args.Get(0).(oModel.OrderResponse), args.Error(1)
+}
+
+func (m *MockOrderItemsRepository) LockOrderPaymentStateTx(ctx context.Context, tx *sql.Tx, tenantID, orderID uint64) (oModel.OrderPaymentState, error) {
+	args := m.Called(ctx, tx, tenantID, orderID)
+	return args.Get(0).(oModel.OrderPaymentState), args.Error(1)
+}
+
+func (m *MockOrderItemsRepository) GetOrderItemsByOrderIDTx(ctx context.Context, tx *sql.Tx, tenantID, orderID uint64) ([]oModel.OrderItems, error) {
+	args := m.Called(ctx, tx, tenantID, orderID)
+	return args.Get(0).([]oModel.OrderItems), args.Error(1)
+}
+
+func (m *MockOrderItemsRepository) DeleteOrderItemsTx(ctx context.Context, tx *sql.Tx, tenantID, orderID uint64) error {
+	args := m.Called(ctx, tx, tenantID, orderID)
+	return args.Error(0)
+}
+
+func (m *MockOrderItemsRepository) CreateOrderItems(ctx context.Context, tx *sql.Tx, tenantID uint64, items []oModel.OrderItemRequest) error {
+	args := m.Called(ctx, tx, tenantID, items)
+	return args.Error(0)
+}
+
+func (m *MockOrderItemsRepository) UpdateOrderTotalsTx(ctx context.Context, tx *sql.Tx, tenantID, orderID uint64, totals pricingModel.Totals) error {
+	args := m.Called(ctx, tx, tenantID, orderID, totals)
+	return args.Error(0)
+}
+
+func (m *MockOrderItemsRepository) UpdateOrderPaidStatusTx(ctx context.Context, tx *sql.Tx, tenantID, orderID uint64, paid bool) error {
+	args := m.Called(ctx, tx, tenantID, orderID, paid)
+	return args.Error(0)
+}
+
+func (m *MockOrderItemsRepository) CreateOrderHistoryTx(ctx context.Context, tx *sql.Tx, order oModel.OrderHistory) error {
+	args := m.Called(ctx, tx, order)
+	return args.Error(0)
+}
+
+// MockProductItemsRepository for testing stock movements on item edits
+type MockProductItemsRepository struct {
+	mock.Mock
+}
+
+func (m *MockProductItemsRepository) GetProductsByIDs(ctx context.Context, tenantID uint64, ids []uint64) ([]pModel.Product, error) {
+	args := m.Called(ctx, tenantID, ids)
+	return args.Get(0).([]pModel.Product), args.Error(1)
+}
+
+func (m *MockProductItemsRepository) AssertProductActiveTx(ctx context.Context, tx *sql.Tx, tenantID, idProduct uint64) (bool, error) {
+	args := m.Called(ctx, tx, tenantID, idProduct)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockProductItemsRepository) DecrementProductStockTx(ctx context.Context, tx *sql.Tx, tenantID, idProduct uint64, quantity uint64) (int64, error) {
+	args := m.Called(ctx, tx, tenantID, idProduct, quantity)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *MockProductItemsRepository) RevertProductStockTx(ctx context.Context, tx *sql.Tx, tenantID, idProduct uint64, quantityToRevert uint64) error {
+	args := m.Called(ctx, tx, tenantID, idProduct, quantityToRevert)
+	return args.Error(0)
+}
+
+func TestItemsUpdater_UpdateOrderItems_AppliesStockDeltaAndRecomputesTotal(t *testing.T) {
+	db, sqlMock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+	sqlMock.ExpectBegin()
+	sqlMock.ExpectCommit()
+
+	orderRepo := &MockOrderItemsRepository{DB: db}
+	productRepo := new(MockProductItemsRepository)
+	const tenantID = uint64(1)
+
+	order := oModel.OrderResponse{ID: 9, TenantID: tenantID, IdUser: 4, Status: oModel.StatusPending, Price: 3000}
+	updated := order
+	updated.Price = 3200
+
+	// Before: product 1 x2, product 2 x1. After: product 1 x3, product 3 x1 (product 2 removed).
+	currentItems := []oModel.OrderItems{
+		{ID: 1, IdOrder: 9, IdProduct: 1, Quantity: 2},
+		{ID: 2, IdOrder: 9, IdProduct: 2, Quantity: 1},
+	}
+	items := []oModel.CreateOrderItemInput{
+		{IdProduct: 1, Quantity: 1},
+		{IdProduct: 3, Quantity: 1},
+		{IdProduct: 1, Quantity: 2},
+	}
+
+	orderRepo.On("GetOrderByID", mock.Anything, tenantID, uint64(9)).Return(order, nil).Once()
+	productRepo.On("GetProductsByIDs", mock.Anything, tenantID, []uint64{1, 3}).Return([]pModel.Product{
+		{ID: 1, Name: "Brownie", Price: 1000, Status: pModel.StatusActive},
+		{ID: 3, Name: "Cookie", Price: 200, Status: pModel.StatusActive},
+	}, nil)
+	orderRepo.On("LockOrderPaymentStateTx", mock.Anything, mock.Anything, tenantID, uint64(9)).
+		Return(oModel.OrderPaymentState{Status: oModel.StatusPending}, nil)
+	orderRepo.On("GetOrderItemsByOrderIDTx", mock.Anything, mock.Anything, tenantID, uint64(9)).Return(currentItems, nil)
+
+	productRepo.On("AssertProductActiveTx", mock.Anything, mock.Anything, tenantID, uint64(1)).Return(true, nil)
+	productRepo.On("DecrementProductStockTx", mock.Anything, mock.Anything, tenantID, uint64(1), uint64(1)).Return(int64(1), nil)
+	productRepo.On("RevertProductStockTx", mock.Anything, mock.Anything, tenantID, uint64(2), uint64(1)).Return(nil)
+	productRepo.On("AssertProductActiveTx", mock.Anything, mock.Anything, tenantID, uint64(3)).Return(false, nil)
+
+	orderRepo.On("DeleteOrderItemsTx", mock.Anything, mock.Anything, tenantID, uint64(9)).Return(nil)
+	orderRepo.On("CreateOrderItems", mock.Anything, mock.Anything, tenantID, []oModel.OrderItemRequest{
+		{IdOrder: 9, IdProduct: 1, ProductNameSnapshot: "Brownie", UnitPriceSnapshot: 1000, Quantity: 3},
+		{IdOrder: 9, IdProduct: 3, ProductNameSnapshot: "Cookie", UnitPriceSnapshot: 200, Quantity: 1},
+	}).Return(nil)
+	orderRepo.On("UpdateOrderTotalsTx", mock.Anything, mock.Anything, tenantID, uint64(9), pricingModel.Totals{Subtotal: 3200, Total: 3200}).Return(nil)
+	orderRepo.On("CreateOrderHistoryTx", mock.Anything, mock.Anything, mock.MatchedBy(func(h oModel.OrderHistory) bool {
+		return h.Action == oModel.ActionUpdate && h.Price == 3200 && h.Status == oModel.StatusPending && h.ModifiedBy == 7
+	})).Return(nil)
+	orderRepo.On("GetOrderByID", mock.Anything, tenantID, uint64(9)).Return(updated, nil).Once()
+
+	updater := NewItemsUpdater(orderRepo, productRepo)
+	got, err := updater.UpdateOrderItems(context.Background(), tenantID, 9, items, 7)
+
+	require.NoError(t, err)
+	assert.Equal(t, money.Amount(3200), got.Price)
+	productRepo.AssertNotCalled(t, "DecrementProductStockTx", mock.Anything, mock.Anything, tenantID, uint64(3), mock.Anything)
+	orderRepo.AssertExpectations(t)
+	productRepo.AssertExpectations(t)
+	require.NoError(t, sqlMock.ExpectationsWereMet())
+}
+
+func TestItemsUpdater_UpdateOrderItems_RejectsWhenNotEditable(t *testing.T) {
+	orderRepo := new(MockOrderItemsRepository)
+	productRepo := new(MockProductItemsRepository)
+
+	orderRepo.On("GetOrderByID", mock.Anything, uint64(1), uint64(9)).
+		Return(oModel.OrderResponse{ID: 9, Status: oModel.StatusReady}, nil)
+
+	updater := NewItemsUpdater(orderRepo, productRepo)
+	_, err := updater.UpdateOrderItems(context.Background(), 1, 9, []oModel.CreateOrderItemInput{{IdProduct: 1, Quantity: 1}}, 7)
+
+	assert.ErrorIs(t, err, appErrors.ErrOrderItemsNotEditable)
+	productRepo.AssertNotCalled(t, "GetProductsByIDs", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestItemsUpdater_UpdateOrderItems_StatusChangedUnderLock_RollsBack(t *testing.T) {
+	db, sqlMock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+	sqlMock.ExpectBegin()
+	sqlMock.ExpectRollback()
+
+	orderRepo := &MockOrderItemsRepository{DB: db}
+	productRepo := new(MockProductItemsRepository)
+
+	orderRepo.On("GetOrderByID", mock.Anything, uint64(1), uint64(9)).
+		Return(oModel.OrderResponse{ID: 9, Status: oModel.StatusPreparing}, nil)
+	productRepo.On("GetProductsByIDs", mock.Anything, uint64(1), []uint64{1}).
+		Return([]pModel.Product{{ID: 1, Price: 500, Status: pModel.StatusActive}}, nil)
+	orderRepo.On("LockOrderPaymentStateTx", mock.Anything, mock.Anything, uint64(1), uint64(9)).
+		Return(oModel.OrderPaymentState{Status: oModel.StatusDelivered}, nil)
+
+	updater := NewItemsUpdater(orderRepo, productRepo)
+	_, err = updater.UpdateOrderItems(context.Background(), 1, 9, []oModel.CreateOrderItemInput{{IdProduct: 1, Quantity: 1}}, 7)
+
+	assert.ErrorIs(t, err, appErrors.ErrOrderItemsNotEditable)
+	orderRepo.AssertNotCalled(t, "DeleteOrderItemsTx", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	require.NoError(t, sqlMock.ExpectationsWereMet())
+}
+
+func TestItemsUpdater_UpdateOrderItems_NotEnoughStock_RollsBack(t *testing.T) {
+	db, sqlMock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+	sqlMock.ExpectBegin()
+	sqlMock.ExpectRollback()
+
+	orderRepo := &MockOrderItemsRepository{DB: db}
+	productRepo := new(MockProductItemsRepository)
+	const tenantID = uint64(1)
+
+	orderRepo.On("GetOrderByID", mock.Anything, tenantID, uint64(9)).
+		Return(oModel.OrderResponse{ID: 9, Status: oModel.StatusPending}, nil)
+	productRepo.On("GetProductsByIDs", mock.Anything, tenantID, []uint64{1}).
+		Return([]pModel.Product{{ID: 1, Price: 500, Status: pModel.StatusActive}}, nil)
+	orderRepo.On("LockOrderPaymentStateTx", mock.Anything, mock.Anything, tenantID, uint64(9)).
+		Return(oModel.OrderPaymentState{Status: oModel.StatusPending}, nil)
+	orderRepo.On("GetOrderItemsByOrderIDTx", mock.Anything, mock.Anything, tenantID, uint64(9)).
+		Return([]oModel.OrderItems{{IdProduct: 1, Quantity: 1}}, nil)
+	productRepo.On("AssertProductActiveTx", mock.Anything, mock.Anything, tenantID, uint64(1)).Return(true, nil)
+	productRepo.On("DecrementProductStockTx", mock.Anything, mock.Anything, tenantID, uint64(1), uint64(4)).Return(int64(0), nil)
+
+	updater := NewItemsUpdater(orderRepo, productRepo)
+	_, err = updater.UpdateOrderItems(context.Background(), tenantID, 9, []oModel.CreateOrderItemInput{{IdProduct: 1, Quantity: 5}}, 7)
+
+	assert.ErrorIs(t, err, appErrors.ErrNotEnoughProductStock)
+	orderRepo.AssertNotCalled(t, "DeleteOrderItemsTx", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	require.NoError(t, sqlMock.ExpectationsWereMet())
+}
+
+func TestItemsUpdater_UpdateOrderItems_GrownTotalClearsPaid(t *testing.T) {
+	db, sqlMock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+	sqlMock.ExpectBegin()
+	sqlMock.ExpectCommit()
+
+	orderRepo := &MockOrderItemsRepository{DB: db}
+	productRepo := new(MockProductItemsRepository)
+	const tenantID = uint64(1)
+
+	order := oModel.OrderResponse{ID: 9, TenantID: tenantID, Status: oModel.StatusPending, Price: 1000, Paid: true}
+	orderRepo.On("GetOrderByID", mock.Anything, tenantID, uint64(9)).Return(order, nil)
+	productRepo.On("GetProductsByIDs", mock.Anything, tenantID, []uint64{1}).
+		Return([]pModel.Product{{ID: 1, Name: "Brownie", Price: 1000, Status: pModel.StatusActive}}, nil)
+	orderRepo.On("LockOrderPaymentStateTx", mock.Anything, mock.Anything, tenantID, uint64(9)).
+		Return(oModel.OrderPaymentState{Status: oModel.StatusPending, TotalPrice: 1000, Paid: true, AmountPaid: 1000}, nil)
+	orderRepo.On("GetOrderItemsByOrderIDTx", mock.Anything, mock.Anything, tenantID, uint64(9)).
+		Return([]oModel.OrderItems{{IdProduct: 1, Quantity: 1}}, nil)
+	productRepo.On("AssertProductActiveTx", mock.Anything, mock.Anything, tenantID, uint64(1)).Return(false, nil)
+	orderRepo.On("DeleteOrderItemsTx", mock.Anything, mock.Anything, tenantID, uint64(9)).Return(nil)
+	orderRepo.On("CreateOrderItems", mock.Anything, mock.Anything, tenantID, mock.Anything).Return(nil)
+	orderRepo.On("UpdateOrderTotalsTx", mock.Anything, mock.Anything, tenantID, uint64(9), pricingModel.Totals{Subtotal: 2000, Total: 2000}).Return(nil)
+	orderRepo.On("UpdateOrderPaidStatusTx", mock.Anything, mock.Anything, tenantID, uint64(9), false).Return(nil)
+	orderRepo.On("CreateOrderHistoryTx", mock.Anything, mock.Anything, mock.MatchedBy(func(h oModel.OrderHistory) bool {
+		return h.Price == 2000 && !h.Paid
+	})).Return(nil)
+
+	updater := NewItemsUpdater(orderRepo, productRepo)
+	_, err = updater.UpdateOrderItems(context.Background(), tenantID, 9, []oModel.CreateOrderItemInput{{IdProduct: 1, Quantity: 2}}, 7)
+
+	require.NoError(t, err)
+	orderRepo.AssertExpectations(t)
+	require.NoError(t, sqlMock.ExpectationsWereMet())
+}
+
+func TestItemsUpdater_UpdateOrderItems_AddedUnitsOverDayLimit_RollsBack(t *testing.T) {
+	db, sqlMock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+	sqlMock.ExpectBegin()
+	sqlMock.ExpectRollback()
+
+	orderRepo := &MockOrderItemsRepository{DB: db}
+	productRepo := new(MockProductItemsRepository)
+	capacity := new(MockDeliveryCapacityRepository)
+	const tenantID = uint64(1)
+	deliveryDate := time.Date(2026, 12, 24, 0, 0, 0, 0, time.UTC)
+
+	orderRepo.On("GetOrderByID", mock.Anything, tenantID, uint64(9)).
+		Return(oModel.OrderResponse{ID: 9, Status: oModel.StatusPending, DeliveryDate: deliveryDate}, nil)
+	productRepo.On("GetProductsByIDs", mock.Anything, tenantID, []uint64{1}).
+		Return([]pModel.Product{{ID: 1, Price: 500, Status: pModel.StatusActive}}, nil)
+	orderRepo.On("LockOrderPaymentStateTx", mock.Anything, mock.Anything, tenantID, uint64(9)).
+		Return(oModel.OrderPaymentState{Status: oModel.StatusPending}, nil)
+	orderRepo.On("GetOrderItemsByOrderIDTx", mock.Anything, mock.Anything, tenantID, uint64(9)).
+		Return([]oModel.OrderItems{{IdProduct: 1, Quantity: 2}}, nil)
+	capacity.On("LockDeliveryDayTx", mock.Anything, mock.Anything, tenantID, deliveryDate).Return(nil)
+	capacity.On("GetDayRulesTx", mock.Anything, mock.Anything, tenantID, deliveryDate).
+		Return(capModel.DayRules{MaxUnits: capacityIntPtr(10)}, nil)
+	// The day's 8 units include the 2 this order already has; 3 more do not fit.
+	capacity.On("GetDayUsageTx", mock.Anything, mock.Anything, tenantID, deliveryDate).
+		Return(capModel.DayUsage{Orders: 3, Units: 8}, nil)
+
+	updater := NewItemsUpdater(orderRepo, productRepo)
+	updater.Capacity = capacity
+	_, err = updater.UpdateOrderItems(context.Background(), tenantID, 9, []oModel.CreateOrderItemInput{{IdProduct: 1, Quantity: 5}}, 7)
+
+	assert.ErrorIs(t, err, appErrors.ErrDeliveryDateFullyBooked)
+	productRepo.AssertNotCalled(t, "DecrementProductStockTx", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	require.NoError(t, sqlMock.ExpectationsWereMet())
+}
+
+func TestItemsUpdater_UpdateOrderItems_LowersLineOfInactiveProduct(t *testing.T) {
+	db, sqlMock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+	sqlMock.ExpectBegin()
+	sqlMock.ExpectCommit()
+
+	orderRepo := &MockOrderItemsRepository{DB: db}
+	productRepo := new(MockProductItemsRepository)
+	const tenantID = uint64(1)
+
+	orderRepo.On("GetOrderByID", mock.Anything, tenantID, uint64(9)).
+		Return(oModel.OrderResponse{ID: 9, Status: oModel.StatusPending, Price: 1500}, nil)
+	productRepo.On("GetProductsByIDs", mock.Anything, tenantID, []uint64{1}).
+		Return([]pModel.Product{{ID: 1, Name: "Panettone 2027", Price: 900, Status: pModel.StatusInactive}}, nil)
+	orderRepo.On("LockOrderPaymentStateTx", mock.Anything, mock.Anything, tenantID, uint64(9)).
+		Return(oModel.OrderPaymentState{Status: oModel.StatusPending}, nil)
+	orderRepo.On("GetOrderItemsByOrderIDTx", mock.Anything, mock.Anything, tenantID, uint64(9)).
+		Return([]oModel.OrderItems{{IdProduct: 1, Name: "Panettone", UnitPrice: 500, Quantity: 3}}, nil)
+	productRepo.On("RevertProductStockTx", mock.Anything, mock.Anything, tenantID, uint64(1), uint64(1)).Return(nil)
+	orderRepo.On("DeleteOrderItemsTx", mock.Anything, mock.Anything, tenantID, uint64(9)).Return(nil)
+	orderRepo.On("CreateOrderItems", mock.Anything, mock.Anything, tenantID, []oModel.OrderItemRequest{
+		{IdOrder: 9, IdProduct: 1, ProductNameSnapshot: "Panettone", UnitPriceSnapshot: 500, Quantity: 2},
+	}).Return(nil)
+	orderRepo.On("UpdateOrderTotalsTx", mock.Anything, mock.Anything, tenantID, uint64(9), pricingModel.Totals{Subtotal: 1000, Total: 1000}).Return(nil)
+	orderRepo.On("CreateOrderHistoryTx", mock.Anything, mock.Anything, mock.Anything).Return(nil)
+
+	updater := NewItemsUpdater(orderRepo, productRepo)
+	_, err = updater.UpdateOrderItems(context.Background(), tenantID, 9, []oModel.CreateOrderItemInput{{IdProduct: 1, Quantity: 2}}, 7)
+
+	require.NoError(t, err)
+	productRepo.AssertNotCalled(t, "AssertProductActiveTx", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	orderRepo.AssertExpectations(t)
+	require.NoError(t, sqlMock.ExpectationsWereMet())
+}
+
+func TestItemsUpdater_UpdateOrderItems_GrowingInactiveProduct_RollsBack(t *testing.T) {
+	db, sqlMock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+	sqlMock.ExpectBegin()
+	sqlMock.ExpectRollback()
+
+	orderRepo := &MockOrderItemsRepository{DB: db}
+	productRepo := new(MockProductItemsRepository)
+	const tenantID = uint64(1)
+
+	orderRepo.On("GetOrderByID", mock.Anything, tenantID, uint64(9)).
+		Return(oModel.OrderResponse{ID: 9, Status: oModel.StatusPending}, nil)
+	productRepo.On("GetProductsByIDs", mock.Anything, tenantID, []uint64{1}).
+		Return([]pModel.Product{{ID: 1, Price: 500, Status: pModel.StatusInactive}}, nil)
+	orderRepo.On("LockOrderPaymentStateTx", mock.Anything, mock.Anything, tenantID, uint64(9)).
+		Return(oModel.OrderPaymentState{Status: oModel.StatusPending}, nil)
+	orderRepo.On("GetOrderItemsByOrderIDTx", mock.Anything, mock.Anything, tenantID, uint64(9)).
+		Return([]oModel.OrderItems{{IdProduct: 1, Quantity: 1}}, nil)
+	productRepo.On("AssertProductActiveTx", mock.Anything, mock.Anything, tenantID, uint64(1)).
+		Return(false, appErrors.ErrProductNotPurchasable)
+
+	updater := NewItemsUpdater(orderRepo, productRepo)
+	_, err = updater.UpdateOrderItems(context.Background(), tenantID, 9, []oModel.CreateOrderItemInput{{IdProduct: 1, Quantity: 2}}, 7)
+
+	assert.ErrorIs(t, err, appErrors.ErrProductNotPurchasable)
+	orderRepo.AssertNotCalled(t, "DeleteOrderItemsTx", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	require.NoError(t, sqlMock.ExpectationsWereMet())
+}
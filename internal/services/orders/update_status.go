@@ -8,14 +8,15 @@ import (
 	"github.com/radamesvaz/bakery-app/internal/errors"
 	"github.com/radamesvaz/bakery-app/internal/logger"
 	oModel "github.com/radamesvaz/bakery-app/model/orders"
+	whModel "github.com/radamesvaz/bakery-app/model/webhooks"
 )
 
 // OrderStatusRepository defines the interface for order status operations
 type OrderStatusRepository interface {
 	BeginTx(ctx context.Context) (*sql.Tx, error)
 	GetOrderByID(ctx context.Context, tenantID, id uint64) (oModel.OrderResponse, error)
-	UpdateOrderStatus(ctx context.Context, tenantID, orderID uint64, status oModel.OrderStatus, cancellationReason *string) error
-	UpdateOrderStatusTx(ctx context.Context, tx *sql.Tx, tenantID, orderID uint64, status oModel.OrderStatus, cancellationReason *string) error
+	UpdateOrderStatus(ctx context.Context, tenantID, orderID uint64, from, status oModel.OrderStatus, cancellationReason *string) error
+	UpdateOrderStatusTx(ctx context.Context, tx *sql.Tx, tenantID, orderID uint64, from, status oModel.OrderStatus, cancellationReason *string) error
 	UpdateOrderPaidStatusTx(ctx context.Context, tx *sql.Tx, tenantID, orderID uint64, paid bool) error
 	CreateOrderHistory(ctx context.Context, order oModel.OrderHistory) error
 	CreateOrderHistoryTx(ctx context.Context, tx *sql.Tx, order oModel.OrderHistory) error
@@ -32,39 +33,70 @@ type ProductStockRepository interface {
 type StatusUpdaterWithStock struct {
 	OrderRepo   OrderStatusRepository
 	ProductRepo ProductStockRepository
+	// Machine defines the allowed transitions (see TenantStatusMachine); nil means DefaultStatusMachine.
+	Machine *StatusMachine
+	// Events receives order.status_changed in the status transaction; nil disables webhooks.
+	Events OrderEventPublisher
+	// Notifications queues the customer's ready/delivered/cancelled email; nil disables them.
+	Notifications OrderNotifier
 }
 
 func NewStatusUpdaterWithStock(orderRepo OrderStatusRepository, productRepo ProductStockRepository) *StatusUpdaterWithStock {
 	return &StatusUpdaterWithStock{
 		OrderRepo:   orderRepo,
 		ProductRepo: productRepo,
+		Machine:     DefaultStatusMachine,
 	}
 }
 
 func (s *StatusUpdaterWithStock) validateStatusTransition(currentStatus, newStatus oModel.OrderStatus) error {
-	if currentStatus == oModel.StatusCancelled {
-		if newStatus == oModel.StatusCancelled {
-			return errors.ErrOrderAlreadyCancelled
-		}
-		return errors.ErrInvalidStatusTransition
-	}
-	if currentStatus == oModel.StatusExpired {
-		return errors.ErrInvalidStatusTransition
+	machine := s.Machine
+	if machine == nil {
+		machine = DefaultStatusMachine
 	}
-	return nil
+	return machine.Validate(currentStatus, newStatus)
 }
 
 // UpdateOrderStatusWithStockReversion updates order status and reverts stock if admin cancels order.
 // cancellationReason is optional; only used when newStatus is cancelled (e.g. user-provided reason or nil).
 // paidOverride, when non-nil, updates paid in the same transaction as status and is used for history
 // (combined status+paid PATCH stays atomic).
+// The transition is validated against the status read here and the UPDATE only applies while the order
+// still has that status, so concurrent requests cannot both pass the state machine; the loser gets
+// ErrOrderStatusChanged.
 func (s *StatusUpdaterWithStock) UpdateOrderStatusWithStockReversion(ctx context.Context, tenantID, orderID uint64, newStatus oModel.OrderStatus, userID uint64, isAdmin bool, cancellationReason *string, paidOverride *bool) error {
 	// Get the current order
 	order, err := s.OrderRepo.GetOrderByID(ctx, tenantID, orderID)
 	if err != nil {
 		return err
 	}
+	return s.applyStatusChange(ctx, tenantID, orderID, order, newStatus, userID, isAdmin, cancellationReason, paidOverride)
+}
 
+// UpdateOrderStatusFrom is UpdateOrderStatusWithStockReversion for callers that only allow the change
+// while the order is in status from (e.g. customer cancel while pending). It returns
+// ErrOrderStatusChanged when the order is, or concurrently becomes, anything else.
+func (s *StatusUpdaterWithStock) UpdateOrderStatusFrom(ctx context.Context, tenantID, orderID uint64, from, newStatus oModel.OrderStatus, userID uint64, isAdmin bool, cancellationReason *string, paidOverride *bool) error {
+	order, err := s.OrderRepo.GetOrderByID(ctx, tenantID, orderID)
+	if err != nil {
+		return err
+	}
+	if order.Status != from {
+		return errors.ErrOrderStatusChanged
+	}
+	return s.applyStatusChange(ctx, tenantID, orderID, order, newStatus, userID, isAdmin, cancellationReason, paidOverride)
+}
+
+func (s *StatusUpdaterWithStock) applyStatusChange(
+	ctx context.Context,
+	tenantID, orderID uint64,
+	order oModel.OrderResponse,
+	newStatus oModel.OrderStatus,
+	userID uint64,
+	isAdmin bool,
+	cancellationReason *string,
+	paidOverride *bool,
+) error {
 	// Validate status transition
 	if err := s.validateStatusTransition(order.Status, newStatus); err != nil {
 		return err
@@ -83,13 +115,13 @@ func (s *StatusUpdaterWithStock) UpdateOrderStatusWithStockReversion(ctx context
 	needsStockRevert := isAdmin && newStatus == oModel.StatusCancelled
 	needsPaidUpdate := paidOverride != nil
 
-	// Use a transaction when status must stay atomic with stock reversion and/or paid.
-	if needsStockRevert || needsPaidUpdate {
+	// Use a transaction when status must stay atomic with stock reversion, paid, the webhook event
+	// and/or the queued email.
+	if needsStockRevert || needsPaidUpdate || s.Events != nil || s.Notifications != nil {
 		return s.updateStatusInTx(ctx, tenantID, orderID, order, newStatus, userID, effectiveCancellationReason, paidOverride, paidForHistory, needsStockRevert)
 	}
 
-	err = s.OrderRepo.UpdateOrderStatus(ctx, tenantID, orderID, newStatus, effectiveCancellationReason)
-	if err != nil {
+	if err := s.OrderRepo.UpdateOrderStatus(ctx, tenantID, orderID, order.Status, newStatus, effectiveCancellationReason); err != nil {
 		return fmt.Errorf("error updating order status: %w", err)
 	}
 
@@ -114,7 +146,7 @@ func (s *StatusUpdaterWithStock) updateStatusInTx(
 	}
 	defer func() { _ = tx.Rollback() }()
 
-	if err := s.OrderRepo.UpdateOrderStatusTx(ctx, tx, tenantID, orderID, newStatus, cancellationReason); err != nil {
+	if err := s.OrderRepo.UpdateOrderStatusTx(ctx, tx, tenantID, orderID, order.Status, newStatus, cancellationReason); err != nil {
 		return fmt.Errorf("error updating order status: %w", err)
 	}
 
@@ -142,6 +174,19 @@ func (s *StatusUpdaterWithStock) updateStatusInTx(
 		// History is best-effort; still commit status/paid/stock
 	}
 
+	if s.Events != nil {
+		data := buildOrderEventData(orderID, string(newStatus), string(order.Status), order.Price, paidForHistory, order.DeliveryDate, cancellationReason)
+		if err := s.Events.EnqueueEventTx(ctx, tx, tenantID, whModel.EventOrderStatusChanged, data); err != nil {
+			return err
+		}
+	}
+
+	if kind, ok := statusNotificationKind(newStatus); ok && s.Notifications != nil {
+		if err := s.Notifications.EnqueueOrderNotificationTx(ctx, tx, tenantID, orderID, kind); err != nil {
+			return err
+		}
+	}
+
 	if err := tx.Commit(); err != nil {
 		return fmt.Errorf("error committing transaction: %w", err)
 	}
@@ -194,6 +239,8 @@ func buildStatusUpdateHistory(
 		CancellationReason: cancellationReason,
 		ModifiedBy:         userID,
 		Action:             oModel.ActionUpdate,
+		DiscountAmount:     order.DiscountAmount,
+		PromotionCode:      order.PromotionCode,
 	}
 }
 
@@ -38,13 +38,13 @@ func (m *MockOrderStatusRepositoryWithStock) GetOrderByID(ctx context.Context, t
 	return args.Get(0).(oModel.OrderResponse), args.Error(1)
 }
 
-func (m *MockOrderStatusRepositoryWithStock) UpdateOrderStatus(ctx context.Context, tenantID, orderID uint64, status oModel.OrderStatus, cancellationReason *string) error {
-	args := m.Called(ctx, tenantID, orderID, status, cancellationReason)
+func (m *MockOrderStatusRepositoryWithStock) UpdateOrderStatus(ctx context.Context, tenantID, orderID uint64, from, status oModel.OrderStatus, cancellationReason *string) error {
+	args := m.Called(ctx, tenantID, orderID, from, status, cancellationReason)
 	return args.Error(0)
 }
 
-func (m *MockOrderStatusRepositoryWithStock) UpdateOrderStatusTx(ctx context.Context, tx *sql.Tx, tenantID, orderID uint64, status oModel.OrderStatus, cancellationReason *string) error {
-	args := m.Called(ctx, tx, tenantID, orderID, status, cancellationReason)
+func (m *MockOrderStatusRepositoryWithStock) UpdateOrderStatusTx(ctx context.Context, tx *sql.Tx, tenantID, orderID uint64, from, status oModel.OrderStatus, cancellationReason *string) error {
+	args := m.Called(ctx, tx, tenantID, orderID, from, status, cancellationReason)
 	return args.Error(0)
 }
 
@@ -133,7 +133,7 @@ func TestUpdateOrderStatus_AdminCancelsOrder_RevertsStock(t *testing.T) {
 
 	const tenantID = uint64(1)
 	mockOrderRepo.On("GetOrderByID", mock.Anything, tenantID, uint64(1)).Return(order, nil)
-	mockOrderRepo.On("UpdateOrderStatusTx", mock.Anything, mock.Anything, tenantID, uint64(1), oModel.StatusCancelled, (*string)(nil)).Return(nil)
+	mockOrderRepo.On("UpdateOrderStatusTx", mock.Anything, mock.Anything, tenantID, uint64(1), order.Status, oModel.StatusCancelled, (*string)(nil)).Return(nil)
 	mockOrderRepo.On("CreateOrderHistoryTx", mock.Anything, mock.Anything, mock.Anything).Return(nil)
 	mockOrderRepo.On("GetOrderItemsByOrderIDTx", mock.Anything, mock.Anything, tenantID, uint64(1)).Return(orderItems, nil)
 
@@ -167,7 +167,7 @@ func TestUpdateOrderStatus_ClientCancelsOrder_NoStockRevert(t *testing.T) {
 
 	const tenantID = uint64(1)
 	mockOrderRepo.On("GetOrderByID", mock.Anything, tenantID, uint64(1)).Return(order, nil)
-	mockOrderRepo.On("UpdateOrderStatus", mock.Anything, tenantID, uint64(1), oModel.StatusCancelled, (*string)(nil)).Return(nil)
+	mockOrderRepo.On("UpdateOrderStatus", mock.Anything, tenantID, uint64(1), order.Status, oModel.StatusCancelled, (*string)(nil)).Return(nil)
 	mockOrderRepo.On("CreateOrderHistory", mock.Anything, mock.Anything).Return(nil)
 
 	statusUpdater := &StatusUpdaterWithStock{
@@ -197,7 +197,7 @@ func TestUpdateOrderStatus_NonCancelledStatus_NoStockRevert(t *testing.T) {
 
 	const tenantID = uint64(1)
 	mockOrderRepo.On("GetOrderByID", mock.Anything, tenantID, uint64(1)).Return(order, nil)
-	mockOrderRepo.On("UpdateOrderStatus", mock.Anything, tenantID, uint64(1), oModel.StatusPreparing, (*string)(nil)).Return(nil)
+	mockOrderRepo.On("UpdateOrderStatus", mock.Anything, tenantID, uint64(1), order.Status, oModel.StatusPreparing, (*string)(nil)).Return(nil)
 	mockOrderRepo.On("CreateOrderHistory", mock.Anything, mock.Anything).Return(nil)
 
 	statusUpdater := &StatusUpdaterWithStock{
@@ -236,7 +236,7 @@ func TestUpdateOrderStatus_StockRevertFails_ReturnsError(t *testing.T) {
 
 	const tenantID = uint64(1)
 	mockOrderRepo.On("GetOrderByID", mock.Anything, tenantID, uint64(1)).Return(order, nil)
-	mockOrderRepo.On("UpdateOrderStatusTx", mock.Anything, mock.Anything, tenantID, uint64(1), oModel.StatusCancelled, (*string)(nil)).Return(nil)
+	mockOrderRepo.On("UpdateOrderStatusTx", mock.Anything, mock.Anything, tenantID, uint64(1), order.Status, oModel.StatusCancelled, (*string)(nil)).Return(nil)
 	mockOrderRepo.On("GetOrderItemsByOrderIDTx", mock.Anything, mock.Anything, tenantID, uint64(1)).Return(orderItems, nil)
 
 	mockProductRepo.On("RevertProductStockTx", mock.Anything, mock.Anything, tenantID, uint64(1), uint64(3)).Return(appErrors.ErrDatabaseOperation)
@@ -299,7 +299,7 @@ func TestUpdateOrderStatus_PaidOverrideUsedInHistory(t *testing.T) {
 	paidTrue := true
 	mockOrderRepo.On("GetOrderByID", mock.Anything, tenantID, uint64(1)).Return(order, nil)
 	sqlMock.ExpectBegin()
-	mockOrderRepo.On("UpdateOrderStatusTx", mock.Anything, mock.Anything, tenantID, uint64(1), oModel.StatusPreparing, (*string)(nil)).Return(nil)
+	mockOrderRepo.On("UpdateOrderStatusTx", mock.Anything, mock.Anything, tenantID, uint64(1), order.Status, oModel.StatusPreparing, (*string)(nil)).Return(nil)
 	mockOrderRepo.On("UpdateOrderPaidStatusTx", mock.Anything, mock.Anything, tenantID, uint64(1), true).Return(nil)
 	mockOrderRepo.On("CreateOrderHistoryTx", mock.Anything, mock.Anything, mock.MatchedBy(func(h oModel.OrderHistory) bool {
 		return h.Status == oModel.StatusPreparing &&
@@ -335,7 +335,7 @@ func TestUpdateOrderStatus_HistoryIncludesDeliveryDirection(t *testing.T) {
 
 	const tenantID = uint64(1)
 	mockOrderRepo.On("GetOrderByID", mock.Anything, tenantID, uint64(1)).Return(order, nil)
-	mockOrderRepo.On("UpdateOrderStatus", mock.Anything, tenantID, uint64(1), oModel.StatusPreparing, (*string)(nil)).Return(nil)
+	mockOrderRepo.On("UpdateOrderStatus", mock.Anything, tenantID, uint64(1), order.Status, oModel.StatusPreparing, (*string)(nil)).Return(nil)
 	mockOrderRepo.On("CreateOrderHistory", mock.Anything, mock.MatchedBy(func(h oModel.OrderHistory) bool {
 		return h.DeliveryDirection == "Av. Siempre Viva 742" && h.Status == oModel.StatusPreparing
 	})).Return(nil)
@@ -368,7 +368,7 @@ func TestUpdateOrderStatus_WrappedNotFound_PreservesHTTPError(t *testing.T) {
 	paidTrue := true
 	mockOrderRepo.On("GetOrderByID", mock.Anything, tenantID, uint64(1)).Return(order, nil)
 	sqlMock.ExpectBegin()
-	mockOrderRepo.On("UpdateOrderStatusTx", mock.Anything, mock.Anything, tenantID, uint64(1), oModel.StatusPreparing, (*string)(nil)).
+	mockOrderRepo.On("UpdateOrderStatusTx", mock.Anything, mock.Anything, tenantID, uint64(1), order.Status, oModel.StatusPreparing, (*string)(nil)).
 		Return(appErrors.NewNotFound(appErrors.ErrOrderNotFound))
 	sqlMock.ExpectRollback()
 
@@ -408,7 +408,7 @@ func TestUpdateOrderStatus_PaidOverride_StatusFails_DoesNotUpdatePaid(t *testing
 	paidTrue := true
 	mockOrderRepo.On("GetOrderByID", mock.Anything, tenantID, uint64(1)).Return(order, nil)
 	sqlMock.ExpectBegin()
-	mockOrderRepo.On("UpdateOrderStatusTx", mock.Anything, mock.Anything, tenantID, uint64(1), oModel.StatusPreparing, (*string)(nil)).
+	mockOrderRepo.On("UpdateOrderStatusTx", mock.Anything, mock.Anything, tenantID, uint64(1), order.Status, oModel.StatusPreparing, (*string)(nil)).
 		Return(fmt.Errorf("status update failed"))
 	sqlMock.ExpectRollback()
 
@@ -422,3 +422,55 @@ func TestUpdateOrderStatus_PaidOverride_StatusFails_DoesNotUpdatePaid(t *testing
 	mockOrderRepo.AssertNotCalled(t, "UpdateOrderPaidStatusTx")
 	assert.NoError(t, sqlMock.ExpectationsWereMet())
 }
+
+func TestUpdateOrderStatus_ConcurrentStatusChange_DoesNotRevertStock(t *testing.T) {
+	db, sqlMock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	mockOrderRepo := &MockOrderStatusRepositoryWithStock{DB: db}
+	mockProductRepo := new(MockProductRepositoryWithStock)
+
+	// Read as pending, but another request cancelled it before our UPDATE ran.
+	order := oModel.OrderResponse{
+		ID:     1,
+		Status: oModel.StatusPending,
+	}
+
+	const tenantID = uint64(1)
+	mockOrderRepo.On("GetOrderByID", mock.Anything, tenantID, uint64(1)).Return(order, nil)
+	sqlMock.ExpectBegin()
+	mockOrderRepo.On("UpdateOrderStatusTx", mock.Anything, mock.Anything, tenantID, uint64(1), oModel.StatusPending, oModel.StatusCancelled, (*string)(nil)).
+		Return(appErrors.ErrOrderStatusChanged)
+	sqlMock.ExpectRollback()
+
+	statusUpdater := &StatusUpdaterWithStock{
+		OrderRepo:   mockOrderRepo,
+		ProductRepo: mockProductRepo,
+	}
+
+	err = statusUpdater.UpdateOrderStatusWithStockReversion(context.Background(), tenantID, 1, oModel.StatusCancelled, 1, true, nil, nil)
+	assert.ErrorIs(t, err, appErrors.ErrOrderStatusChanged)
+	mockOrderRepo.AssertNotCalled(t, "GetOrderItemsByOrderIDTx", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	mockProductRepo.AssertNotCalled(t, "RevertProductStockTx", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	assert.NoError(t, sqlMock.ExpectationsWereMet())
+}
+
+func TestUpdateOrderStatusFrom_RejectsOtherCurrentStatus(t *testing.T) {
+	mockOrderRepo := new(MockOrderStatusRepositoryWithStock)
+	mockProductRepo := new(MockProductRepositoryWithStock)
+
+	const tenantID = uint64(1)
+	order := oModel.OrderResponse{ID: 1, Status: oModel.StatusPreparing}
+	mockOrderRepo.On("GetOrderByID", mock.Anything, tenantID, uint64(1)).Return(order, nil)
+
+	statusUpdater := &StatusUpdaterWithStock{
+		OrderRepo:   mockOrderRepo,
+		ProductRepo: mockProductRepo,
+	}
+
+	err := statusUpdater.UpdateOrderStatusFrom(context.Background(), tenantID, 1, oModel.StatusPending, oModel.StatusCancelled, 1, true, nil, nil)
+	assert.ErrorIs(t, err, appErrors.ErrOrderStatusChanged)
+	mockOrderRepo.AssertNotCalled(t, "BeginTx", mock.Anything)
+	mockOrderRepo.AssertNotCalled(t, "UpdateOrderStatusTx", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
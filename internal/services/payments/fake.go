@@ -0,0 +1,77 @@
+package payments
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+
+	pModel "github.com/radamesvaz/bakery-app/model/payments"
+)
+
+// FakeProvider is a local provider for development and tests: intents are created in memory and
+// callbacks are plain signed JSON {"payment_id": "...", "status": "succeeded|failed|cancelled"}.
+type FakeProvider struct {
+	BaseURL       string
+	WebhookSecret string
+}
+
+func NewFakeProvider(baseURL, webhookSecret string) *FakeProvider {
+	return &FakeProvider{
+		BaseURL:       strings.TrimRight(baseURL, "/"),
+		WebhookSecret: webhookSecret,
+	}
+}
+
+func (p *FakeProvider) Name() string { return "fake" }
+
+func (p *FakeProvider) CreateIntent(_ context.Context, req IntentRequest) (Intent, error) {
+	b := make([]byte, 12)
+	if _, err := rand.Read(b); err != nil {
+		return Intent{}, fmt.Errorf("generate fake payment id: %w", err)
+	}
+	id := "fake_" + hex.EncodeToString(b)
+	return Intent{
+		ProviderPaymentID: id,
+		CheckoutURL:       fmt.Sprintf("%s/fake-checkout/%s?reference=%s", p.BaseURL, id, req.Reference),
+	}, nil
+}
+
+type fakeWebhookPayload struct {
+	PaymentID string `json:"payment_id"`
+	Status    string `json:"status"`
+}
+
+func (p *FakeProvider) ParseWebhook(header http.Header, body []byte) (WebhookEvent, error) {
+	if err := verifySignature(p.WebhookSecret, header, body); err != nil {
+		return WebhookEvent{}, err
+	}
+	var payload fakeWebhookPayload
+	if err := json.Unmarshal(body, &payload); err != nil {
+		return WebhookEvent{}, fmt.Errorf("decode fake webhook: %w", err)
+	}
+	status, err := parseOutcome(payload.Status)
+	if err != nil {
+		return WebhookEvent{}, err
+	}
+	if payload.PaymentID == "" {
+		return WebhookEvent{}, fmt.Errorf("decode fake webhook: missing payment_id")
+	}
+	return WebhookEvent{ProviderPaymentID: payload.PaymentID, Status: status}, nil
+}
+
+// parseOutcome maps a callback status to a terminal payment status.
+func parseOutcome(status string) (pModel.PaymentStatus, error) {
+	switch pModel.PaymentStatus(strings.ToLower(strings.TrimSpace(status))) {
+	case pModel.StatusSucceeded:
+		return pModel.StatusSucceeded, nil
+	case pModel.StatusFailed:
+		return pModel.StatusFailed, nil
+	case pModel.StatusCancelled:
+		return pModel.StatusCancelled, nil
+	}
+	return "", fmt.Errorf("unsupported payment status %q", status)
+}
@@ -0,0 +1,131 @@
+package payments
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/radamesvaz/bakery-app/model/money"
+)
+
+const defaultHostedRequestTimeout = 15 * time.Second
+
+// HostedCheckoutProvider talks to a generic hosted-checkout API:
+//
+//	POST {APIURL} with Bearer APIKey and {"amount","currency","reference","success_url","cancel_url","metadata"}
+//	-> {"id","checkout_url","expires_at"}
+//
+// amount is a JSON number in major units of currency (e.g. 12.50 with "EUR").
+//
+// Callbacks are {"id","status"} signed with WebhookSecret (see HeaderSignature).
+type HostedCheckoutProvider struct {
+	APIURL        string
+	APIKey        string
+	WebhookSecret string
+	SuccessURL    string
+	CancelURL     string
+	Client        *http.Client
+}
+
+func NewHostedCheckoutProvider(apiURL, apiKey, webhookSecret, successURL, cancelURL string) *HostedCheckoutProvider {
+	return &HostedCheckoutProvider{
+		APIURL:        apiURL,
+		APIKey:        apiKey,
+		WebhookSecret: webhookSecret,
+		SuccessURL:    successURL,
+		CancelURL:     cancelURL,
+		Client:        &http.Client{Timeout: defaultHostedRequestTimeout},
+	}
+}
+
+func (p *HostedCheckoutProvider) Name() string { return "hosted" }
+
+type hostedIntentRequest struct {
+	Amount     money.Amount      `json:"amount"`
+	Currency   money.Currency    `json:"currency"`
+	Reference  string            `json:"reference"`
+	SuccessURL string            `json:"success_url,omitempty"`
+	CancelURL  string            `json:"cancel_url,omitempty"`
+	Metadata   map[string]string `json:"metadata"`
+}
+
+type hostedIntentResponse struct {
+	ID          string    `json:"id"`
+	CheckoutURL string    `json:"checkout_url"`
+	ExpiresAt   time.Time `json:"expires_at"`
+}
+
+func (p *HostedCheckoutProvider) CreateIntent(ctx context.Context, req IntentRequest) (Intent, error) {
+	body, err := json.Marshal(hostedIntentRequest{
+		Amount:     req.Amount,
+		Currency:   req.Currency,
+		Reference:  req.Reference,
+		SuccessURL: p.SuccessURL,
+		CancelURL:  p.CancelURL,
+		Metadata: map[string]string{
+			"tenant_id": fmt.Sprint(req.TenantID),
+			"id_order":  fmt.Sprint(req.OrderID),
+		},
+	})
+	if err != nil {
+		return Intent{}, fmt.Errorf("marshal payment intent: %w", err)
+	}
+
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, p.APIURL, bytes.NewReader(body))
+	if err != nil {
+		return Intent{}, fmt.Errorf("build payment intent request: %w", err)
+	}
+	httpReq.Header.Set("Content-Type", "application/json")
+	httpReq.Header.Set("Authorization", "Bearer "+p.APIKey)
+	httpReq.Header.Set("Idempotency-Key", req.Reference)
+
+	resp, err := p.Client.Do(httpReq)
+	if err != nil {
+		return Intent{}, fmt.Errorf("create payment intent: %w", err)
+	}
+	defer resp.Body.Close()
+	respBody, err := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	if err != nil {
+		return Intent{}, fmt.Errorf("read payment intent response: %w", err)
+	}
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return Intent{}, fmt.Errorf("create payment intent: unexpected status %d: %s", resp.StatusCode, strings.TrimSpace(string(respBody)))
+	}
+
+	var out hostedIntentResponse
+	if err := json.Unmarshal(respBody, &out); err != nil {
+		return Intent{}, fmt.Errorf("decode payment intent response: %w", err)
+	}
+	if out.ID == "" || out.CheckoutURL == "" {
+		return Intent{}, fmt.Errorf("create payment intent: response missing id or checkout_url")
+	}
+	return Intent{ProviderPaymentID: out.ID, CheckoutURL: out.CheckoutURL, ExpiresAt: out.ExpiresAt}, nil
+}
+
+type hostedWebhookPayload struct {
+	ID     string `json:"id"`
+	Status string `json:"status"`
+}
+
+func (p *HostedCheckoutProvider) ParseWebhook(header http.Header, body []byte) (WebhookEvent, error) {
+	if err := verifySignature(p.WebhookSecret, header, body); err != nil {
+		return WebhookEvent{}, err
+	}
+	var payload hostedWebhookPayload
+	if err := json.Unmarshal(body, &payload); err != nil {
+		return WebhookEvent{}, fmt.Errorf("decode payment webhook: %w", err)
+	}
+	if payload.ID == "" {
+		return WebhookEvent{}, fmt.Errorf("decode payment webhook: missing id")
+	}
+	status, err := parseOutcome(payload.Status)
+	if err != nil {
+		return WebhookEvent{}, err
+	}
+	return WebhookEvent{ProviderPaymentID: payload.ID, Status: status}, nil
+}
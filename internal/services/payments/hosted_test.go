@@ -0,0 +1,74 @@
+package payments
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/radamesvaz/bakery-app/model/money"
+	pModel "github.com/radamesvaz/bakery-app/model/payments"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHostedCheckoutProvider_CreateIntent(t *testing.T) {
+	expiresAt := time.Date(2026, 4, 2, 10, 30, 0, 0, time.UTC)
+	var got hostedIntentRequest
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "Bearer key-123", r.Header.Get("Authorization"))
+		assert.Equal(t, "order-1-42-99", r.Header.Get("Idempotency-Key"))
+		body, err := io.ReadAll(r.Body)
+		require.NoError(t, err)
+		assert.Contains(t, string(body), `"amount":12.50,"currency":"EUR"`)
+		require.NoError(t, json.Unmarshal(body, &got))
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(hostedIntentResponse{ID: "pi_1", CheckoutURL: "https://checkout.test/pi_1", ExpiresAt: expiresAt})
+	}))
+	defer server.Close()
+
+	p := NewHostedCheckoutProvider(server.URL, "key-123", "secret", "https://shop.test/ok", "https://shop.test/cancel")
+	intent, err := p.CreateIntent(context.Background(), IntentRequest{TenantID: 1, OrderID: 42, Amount: 1250, Currency: "EUR", Reference: "order-1-42-99"})
+
+	require.NoError(t, err)
+	assert.Equal(t, Intent{ProviderPaymentID: "pi_1", CheckoutURL: "https://checkout.test/pi_1", ExpiresAt: expiresAt}, intent)
+	assert.Equal(t, money.Amount(1250), got.Amount)
+	assert.Equal(t, money.Currency("EUR"), got.Currency)
+	assert.Equal(t, "42", got.Metadata["id_order"])
+	assert.Equal(t, "https://shop.test/ok", got.SuccessURL)
+}
+
+func TestHostedCheckoutProvider_CreateIntent_ProviderError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "bad amount", http.StatusUnprocessableEntity)
+	}))
+	defer server.Close()
+
+	p := NewHostedCheckoutProvider(server.URL, "key", "secret", "", "")
+	_, err := p.CreateIntent(context.Background(), IntentRequest{Amount: -100, Reference: "r"})
+
+	assert.ErrorContains(t, err, "unexpected status 422")
+}
+
+func TestHostedCheckoutProvider_ParseWebhook(t *testing.T) {
+	p := NewHostedCheckoutProvider("", "", "secret", "", "")
+	body := []byte(`{"id":"pi_1","status":"SUCCEEDED"}`)
+	header := http.Header{}
+	header.Set(HeaderSignature, Sign("secret", body))
+
+	event, err := p.ParseWebhook(header, body)
+
+	require.NoError(t, err)
+	assert.Equal(t, WebhookEvent{ProviderPaymentID: "pi_1", Status: pModel.StatusSucceeded}, event)
+
+	_, err = p.ParseWebhook(http.Header{}, body)
+	assert.Error(t, err)
+
+	unknown := []byte(`{"id":"pi_1","status":"refunded"}`)
+	header.Set(HeaderSignature, Sign("secret", unknown))
+	_, err = p.ParseWebhook(header, unknown)
+	assert.ErrorContains(t, err, "unsupported payment status")
+}
@@ -0,0 +1,65 @@
+package payments
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/radamesvaz/bakery-app/internal/errors"
+	"github.com/radamesvaz/bakery-app/model/money"
+	pModel "github.com/radamesvaz/bakery-app/model/payments"
+)
+
+// HeaderSignature carries "sha256=" + hex(HMAC-SHA256(webhook secret, raw body)) on provider callbacks.
+const HeaderSignature = "X-Payment-Signature"
+
+// Provider creates hosted payment intents and authenticates their callbacks.
+type Provider interface {
+	// Name is stored in payments.provider and scopes provider payment ids.
+	Name() string
+	CreateIntent(ctx context.Context, req IntentRequest) (Intent, error)
+	// ParseWebhook verifies the callback signature and decodes the payment outcome.
+	ParseWebhook(header http.Header, body []byte) (WebhookEvent, error)
+}
+
+type IntentRequest struct {
+	TenantID uint64
+	OrderID  uint64
+	Amount   money.Amount
+	// Currency is the order's currency; Amount is in its minor units.
+	Currency  money.Currency
+	Reference string
+}
+
+type Intent struct {
+	ProviderPaymentID string
+	CheckoutURL       string
+	// ExpiresAt is when the checkout session stops accepting payment; zero means the service default.
+	ExpiresAt time.Time
+}
+
+// WebhookEvent is the provider-agnostic outcome of a payment.
+type WebhookEvent struct {
+	ProviderPaymentID string
+	Status            pModel.PaymentStatus
+}
+
+// Sign returns the HeaderSignature value for body.
+func Sign(secret string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// verifySignature checks HeaderSignature in constant time.
+func verifySignature(secret string, header http.Header, body []byte) error {
+	got := strings.TrimSpace(header.Get(HeaderSignature))
+	if secret == "" || got == "" || !hmac.Equal([]byte(got), []byte(Sign(secret, body))) {
+		return errors.ErrInvalidPaymentSignature
+	}
+	return nil
+}
@@ -0,0 +1,278 @@
+package payments
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/radamesvaz/bakery-app/internal/errors"
+	"github.com/radamesvaz/bakery-app/internal/logger"
+	"github.com/radamesvaz/bakery-app/internal/services/tokens"
+	oModel "github.com/radamesvaz/bakery-app/model/orders"
+	pModel "github.com/radamesvaz/bakery-app/model/payments"
+)
+
+const (
+	DefaultCheckoutTTL = 30 * time.Minute
+
+	// systemModifiedByID marks history rows written by provider callbacks (no user).
+	systemModifiedByID = 0
+)
+
+// OrderPaymentRepository defines the order operations needed to take payments
+type OrderPaymentRepository interface {
+	BeginTx(ctx context.Context) (*sql.Tx, error)
+	GetOrderByID(ctx context.Context, tenantID, id uint64) (oModel.OrderResponse, error)
+	GetOrderIDByTrackingTokenHash(ctx context.Context, tenantID uint64, tokenHash string) (uint64, error)
+	LockOrderPaymentStateTx(ctx context.Context, tx *sql.Tx, tenantID, orderID uint64) (oModel.OrderPaymentState, error)
+	CreateOrderPaymentTx(ctx context.Context, tx *sql.Tx, p oModel.OrderPaymentRequest) (oModel.OrderPayment, error)
+	UpdateOrderPaidStatusTx(ctx context.Context, tx *sql.Tx, tenantID, orderID uint64, paid bool) error
+	CreateOrderHistoryTx(ctx context.Context, tx *sql.Tx, order oModel.OrderHistory) error
+}
+
+// PaymentRepository defines the persistence of payment attempts
+type PaymentRepository interface {
+	CreatePayment(ctx context.Context, req pModel.CreatePaymentRequest) (pModel.Payment, error)
+	GetInFlightPayment(ctx context.Context, tenantID, orderID uint64, now time.Time) (*pModel.Payment, error)
+	LockPaymentByProviderIDTx(ctx context.Context, tx *sql.Tx, provider, providerPaymentID string) (pModel.Payment, error)
+	MarkPaymentSucceededTx(ctx context.Context, tx *sql.Tx, tenantID, id uint64, paidOn time.Time) error
+	UpdatePaymentStatusTx(ctx context.Context, tx *sql.Tx, tenantID, id uint64, status pModel.PaymentStatus) error
+}
+
+type Service struct {
+	OrderRepo   OrderPaymentRepository
+	PaymentRepo PaymentRepository
+	Provider    Provider
+	// Tokens hashes the customer's tracking token, which authorizes checkout of the order.
+	Tokens tokens.OneTimeTokenManager
+	// CheckoutTTL applies when the provider does not return its own session expiry.
+	CheckoutTTL time.Duration
+	Now         func() time.Time
+}
+
+func NewService(orderRepo OrderPaymentRepository, paymentRepo PaymentRepository, provider Provider, tokenManager tokens.OneTimeTokenManager) *Service {
+	return &Service{
+		OrderRepo:   orderRepo,
+		PaymentRepo: paymentRepo,
+		Provider:    provider,
+		Tokens:      tokenManager,
+		CheckoutTTL: DefaultCheckoutTTL,
+		Now:         time.Now,
+	}
+}
+
+func isPayableStatus(status oModel.OrderStatus) bool {
+	return status == oModel.StatusPending || status == oModel.StatusPreparing
+}
+
+// StartCheckout returns a hosted checkout for the balance due on the order behind the customer's
+// tracking token (the total minus any deposit already recorded); unknown tokens are reported as not
+// found, so order ids cannot be probed. An in-flight payment for the same amount is reused so repeated clicks do not open several sessions. While a payment is in flight the
+// ghost-order cron leaves the order alone (see ClaimExpiredPendingOrdersTx).
+func (s *Service) StartCheckout(ctx context.Context, tenantID uint64, trackingToken string) (pModel.CheckoutResponse, error) {
+	if s.Provider == nil {
+		return pModel.CheckoutResponse{}, errors.ErrPaymentProviderNotEnabled
+	}
+	if s.Tokens == nil || strings.TrimSpace(trackingToken) == "" {
+		return pModel.CheckoutResponse{}, errors.NewNotFound(errors.ErrOrderNotFound)
+	}
+
+	orderID, err := s.OrderRepo.GetOrderIDByTrackingTokenHash(ctx, tenantID, s.Tokens.Hash(trackingToken))
+	if err != nil {
+		return pModel.CheckoutResponse{}, err
+	}
+	order, err := s.OrderRepo.GetOrderByID(ctx, tenantID, orderID)
+	if err != nil {
+		return pModel.CheckoutResponse{}, err
+	}
+	if order.Paid || order.BalanceDue <= 0 {
+		return pModel.CheckoutResponse{}, errors.ErrOrderAlreadyPaid
+	}
+	if !isPayableStatus(order.Status) {
+		return pModel.CheckoutResponse{}, errors.ErrOrderNotPayable
+	}
+
+	now := s.Now()
+	inFlight, err := s.PaymentRepo.GetInFlightPayment(ctx, tenantID, orderID, now)
+	if err != nil {
+		return pModel.CheckoutResponse{}, err
+	}
+	if inFlight != nil && inFlight.Amount == order.BalanceDue {
+		return checkoutResponse(*inFlight), nil
+	}
+	// Without an in-flight payment a pending order past its expiry is about to be cancelled by the cron.
+	if inFlight == nil && order.Status == oModel.StatusPending && !order.ExpiresAt.IsZero() && !now.Before(order.ExpiresAt) {
+		return pModel.CheckoutResponse{}, errors.ErrOrderNotPayable
+	}
+
+	intent, err := s.Provider.CreateIntent(ctx, IntentRequest{
+		TenantID:  tenantID,
+		OrderID:   orderID,
+		Amount:    order.BalanceDue,
+		Currency:  order.Currency,
+		Reference: fmt.Sprintf("order-%d-%d-%d", tenantID, orderID, now.Unix()),
+	})
+	if err != nil {
+		return pModel.CheckoutResponse{}, fmt.Errorf("error creating payment intent: %w", err)
+	}
+
+	expiresAt := intent.ExpiresAt
+	if expiresAt.IsZero() {
+		expiresAt = now.Add(s.CheckoutTTL)
+	}
+
+	payment, err := s.PaymentRepo.CreatePayment(ctx, pModel.CreatePaymentRequest{
+		TenantID:          tenantID,
+		IDOrder:           orderID,
+		Provider:          s.Provider.Name(),
+		ProviderPaymentID: intent.ProviderPaymentID,
+		Amount:            order.BalanceDue,
+		CheckoutURL:       intent.CheckoutURL,
+		ExpiresAt:         expiresAt,
+	})
+	if err != nil {
+		return pModel.CheckoutResponse{}, err
+	}
+	return checkoutResponse(payment), nil
+}
+
+func checkoutResponse(p pModel.Payment) pModel.CheckoutResponse {
+	return pModel.CheckoutResponse{
+		PaymentID:   p.ID,
+		IDOrder:     p.IDOrder,
+		Provider:    p.Provider,
+		Amount:      p.Amount,
+		CheckoutURL: p.CheckoutURL,
+		ExpiresAt:   p.ExpiresAt,
+	}
+}
+
+// HandleWebhook verifies a provider callback and applies it in one transaction: on success the
+// payment row, an online line in the order_payments ledger, orders.paid and an orders_history
+// row are written together. Replayed callbacks
+// are no-ops; a succeeded payment is never downgraded.
+func (s *Service) HandleWebhook(ctx context.Context, header http.Header, body []byte) error {
+	if s.Provider == nil {
+		return errors.ErrPaymentProviderNotEnabled
+	}
+
+	event, err := s.Provider.ParseWebhook(header, body)
+	if err != nil {
+		return err
+	}
+
+	tx, err := s.OrderRepo.BeginTx(ctx)
+	if err != nil {
+		return fmt.Errorf("error beginning transaction: %w", err)
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	payment, err := s.PaymentRepo.LockPaymentByProviderIDTx(ctx, tx, s.Provider.Name(), event.ProviderPaymentID)
+	if err != nil {
+		return err
+	}
+
+	if payment.Status == event.Status || payment.Status == pModel.StatusSucceeded {
+		logger.Info().
+			Uint64("payment_id", payment.ID).
+			Str("current_status", string(payment.Status)).
+			Str("event_status", string(event.Status)).
+			Msg("Payment webhook ignored: already applied")
+		return nil
+	}
+
+	if event.Status != pModel.StatusSucceeded {
+		if err := s.PaymentRepo.UpdatePaymentStatusTx(ctx, tx, payment.TenantID, payment.ID, event.Status); err != nil {
+			return err
+		}
+		return commit(tx)
+	}
+
+	if err := s.markOrderPaidTx(ctx, tx, payment); err != nil {
+		return err
+	}
+	return commit(tx)
+}
+
+func (s *Service) markOrderPaidTx(ctx context.Context, tx *sql.Tx, payment pModel.Payment) error {
+	order, err := s.OrderRepo.GetOrderByID(ctx, payment.TenantID, payment.IDOrder)
+	if err != nil {
+		return err
+	}
+	// Lock the order so a concurrent admin PATCH, a staff payment or the cron serializes behind this callback.
+	state, err := s.OrderRepo.LockOrderPaymentStateTx(ctx, tx, payment.TenantID, payment.IDOrder)
+	if err != nil {
+		return err
+	}
+	if !isPayableStatus(state.Status) {
+		// Money was taken for an order that was cancelled meanwhile; keep the record for a refund.
+		logger.Warn().
+			Uint64("tenant_id", payment.TenantID).
+			Uint64("order_id", payment.IDOrder).
+			Uint64("payment_id", payment.ID).
+			Str("order_status", string(state.Status)).
+			Msg("Payment succeeded for an order that is no longer payable")
+	}
+
+	if err := s.PaymentRepo.MarkPaymentSucceededTx(ctx, tx, payment.TenantID, payment.ID, s.Now()); err != nil {
+		return err
+	}
+	reference := payment.ProviderPaymentID
+	paymentID := payment.ID
+	if _, err := s.OrderRepo.CreateOrderPaymentTx(ctx, tx, oModel.OrderPaymentRequest{
+		TenantID:  payment.TenantID,
+		IDOrder:   payment.IDOrder,
+		Amount:    payment.Amount,
+		Method:    oModel.PaymentMethodOnline,
+		Reference: &reference,
+		IDPayment: &paymentID,
+	}); err != nil {
+		return err
+	}
+
+	paid := oModel.IsFullyPaid(state.TotalPrice, state.AmountPaid+payment.Amount)
+	if paid == state.Paid {
+		return nil
+	}
+	if err := s.OrderRepo.UpdateOrderPaidStatusTx(ctx, tx, payment.TenantID, payment.IDOrder, paid); err != nil {
+		return err
+	}
+
+	var idUser *uint64
+	if order.IdUser != 0 {
+		idUser = &order.IdUser
+	}
+	history := oModel.OrderHistory{
+		TenantID:          payment.TenantID,
+		IDOrder:           payment.IDOrder,
+		IdUser:            idUser,
+		Status:            state.Status,
+		Price:             state.TotalPrice,
+		Note:              order.Note,
+		DeliveryDirection: order.DeliveryDirection,
+		DeliveryDate: sql.NullTime{
+			Time:  order.DeliveryDate,
+			Valid: !order.DeliveryDate.IsZero(),
+		},
+		Paid:               paid,
+		CancellationReason: order.CancellationReason,
+		ModifiedBy:         systemModifiedByID,
+		Action:             oModel.ActionUpdate,
+		DiscountAmount:     order.DiscountAmount,
+		PromotionCode:      order.PromotionCode,
+	}
+	if err := s.OrderRepo.CreateOrderHistoryTx(ctx, tx, history); err != nil {
+		return fmt.Errorf("error creating order history: %w", err)
+	}
+	return nil
+}
+
+func commit(tx *sql.Tx) error {
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("error committing transaction: %w", err)
+	}
+	return nil
+}
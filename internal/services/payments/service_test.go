@@ -0,0 +1,368 @@
+package payments
+
+import (
+	"context"
+	"database/sql"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	appErrors "github.com/radamesvaz/bakery-app/internal/errors"
+	"github.com/radamesvaz/bakery-app/internal/services/tokens"
+	"github.com/radamesvaz/bakery-app/model/money"
+	oModel "github.com/radamesvaz/bakery-app/model/orders"
+	pModel "github.com/radamesvaz/bakery-app/model/payments"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+type MockOrderPaymentRepository struct {
+	mock.Mock
+	DB *sql.DB
+}
+
+func (m *MockOrderPaymentRepository) BeginTx(ctx context.Context) (*sql.Tx, error) {
+	return m.DB.BeginTx(ctx, nil)
+}
+
+func (m *MockOrderPaymentRepository) GetOrderByID(ctx context.Context, tenantID, id uint64) (oModel.OrderResponse, error) {
+	args := m.Called(ctx, tenantID, id)
+	return args.Get(0).(oModel.OrderResponse), args.Error(1)
+}
+
+func (m *MockOrderPaymentRepository) GetOrderIDByTrackingTokenHash(ctx context.Context, tenantID uint64, tokenHash string) (uint64, error) {
+	args := m.Called(ctx, tenantID, tokenHash)
+	return args.Get(0).(uint64), args.Error(1)
+}
+
+func (m *MockOrderPaymentRepository) LockOrderPaymentStateTx(ctx context.Context, tx *sql.Tx, tenantID, orderID uint64) (oModel.OrderPaymentState, error) {
+	args := m.Called(ctx, tx, tenantID, orderID)
+	return args.Get(0).(oModel.OrderPaymentState), args.Error(1)
+}
+
+func (m *MockOrderPaymentRepository) CreateOrderPaymentTx(ctx context.Context, tx *sql.Tx, p oModel.OrderPaymentRequest) (oModel.OrderPayment, error) {
+	args := m.Called(ctx, tx, p)
+	return args.Get(0).(oModel.OrderPayment), args.Error(1)
+}
+
+func (m *MockOrderPaymentRepository) UpdateOrderPaidStatusTx(ctx context.Context, tx *sql.Tx, tenantID, orderID uint64, paid bool) error {
+	args := m.Called(ctx, tx, tenantID, orderID, paid)
+	return args.Error(0)
+}
+
+func (m *MockOrderPaymentRepository) CreateOrderHistoryTx(ctx context.Context, tx *sql.Tx, order oModel.OrderHistory) error {
+	args := m.Called(ctx, tx, order)
+	return args.Error(0)
+}
+
+type MockPaymentRepository struct {
+	mock.Mock
+}
+
+func (m *MockPaymentRepository) CreatePayment(ctx context.Context, req pModel.CreatePaymentRequest) (pModel.Payment, error) {
+	args := m.Called(ctx, req)
+	return args.Get(0).(pModel.Payment), args.Error(1)
+}
+
+func (m *MockPaymentRepository) GetInFlightPayment(ctx context.Context, tenantID, orderID uint64, now time.Time) (*pModel.Payment, error) {
+	args := m.Called(ctx, tenantID, orderID, now)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*pModel.Payment), args.Error(1)
+}
+
+func (m *MockPaymentRepository) LockPaymentByProviderIDTx(ctx context.Context, tx *sql.Tx, provider, providerPaymentID string) (pModel.Payment, error) {
+	args := m.Called(ctx, tx, provider, providerPaymentID)
+	return args.Get(0).(pModel.Payment), args.Error(1)
+}
+
+func (m *MockPaymentRepository) MarkPaymentSucceededTx(ctx context.Context, tx *sql.Tx, tenantID, id uint64, paidOn time.Time) error {
+	args := m.Called(ctx, tx, tenantID, id, paidOn)
+	return args.Error(0)
+}
+
+func (m *MockPaymentRepository) UpdatePaymentStatusTx(ctx context.Context, tx *sql.Tx, tenantID, id uint64, status pModel.PaymentStatus) error {
+	args := m.Called(ctx, tx, tenantID, id, status)
+	return args.Error(0)
+}
+
+const (
+	testTenantID      = uint64(1)
+	testOrderID       = uint64(42)
+	testWebhookSecret = "test-secret"
+	testTrackingToken = "ABCDEF0123"
+)
+
+var testNow = time.Date(2026, 4, 2, 10, 0, 0, 0, time.UTC)
+
+func newTestService(t *testing.T) (*Service, *MockOrderPaymentRepository, *MockPaymentRepository, sqlmock.Sqlmock) {
+	db, sqlMock, err := sqlmock.New()
+	require.NoError(t, err)
+	t.Cleanup(func() { db.Close() })
+
+	orderRepo := &MockOrderPaymentRepository{DB: db}
+	paymentRepo := new(MockPaymentRepository)
+	tokenManager := tokens.NewSHA256OneTimeTokenManager(32)
+	svc := NewService(orderRepo, paymentRepo, NewFakeProvider("https://pay.test", testWebhookSecret), tokenManager)
+	svc.Now = func() time.Time { return testNow }
+	orderRepo.On("GetOrderIDByTrackingTokenHash", mock.Anything, testTenantID, tokenManager.Hash(testTrackingToken)).Return(testOrderID, nil).Maybe()
+	return svc, orderRepo, paymentRepo, sqlMock
+}
+
+func pendingOrder() oModel.OrderResponse {
+	return oModel.OrderResponse{
+		ID:         testOrderID,
+		TenantID:   testTenantID,
+		IdUser:     7,
+		Status:     oModel.StatusPending,
+		Price:      3550,
+		BalanceDue: 3550,
+		ExpiresAt:  testNow.Add(10 * time.Minute),
+	}
+}
+
+func signedHeader(body []byte) http.Header {
+	h := http.Header{}
+	h.Set(HeaderSignature, Sign(testWebhookSecret, body))
+	return h
+}
+
+func TestService_StartCheckout_CreatesPayment(t *testing.T) {
+	svc, orderRepo, paymentRepo, _ := newTestService(t)
+
+	orderRepo.On("GetOrderByID", mock.Anything, testTenantID, testOrderID).Return(pendingOrder(), nil)
+	paymentRepo.On("GetInFlightPayment", mock.Anything, testTenantID, testOrderID, testNow).Return(nil, nil)
+	paymentRepo.On("CreatePayment", mock.Anything, mock.MatchedBy(func(req pModel.CreatePaymentRequest) bool {
+		return req.TenantID == testTenantID &&
+			req.IDOrder == testOrderID &&
+			req.Provider == "fake" &&
+			req.Amount == 3550 &&
+			req.ProviderPaymentID != "" &&
+			req.ExpiresAt.Equal(testNow.Add(DefaultCheckoutTTL))
+	})).Return(pModel.Payment{ID: 3, IDOrder: testOrderID, Provider: "fake", Amount: 3550, CheckoutURL: "https://pay.test/x", ExpiresAt: testNow.Add(DefaultCheckoutTTL)}, nil)
+
+	checkout, err := svc.StartCheckout(context.Background(), testTenantID, testTrackingToken)
+
+	require.NoError(t, err)
+	assert.Equal(t, uint64(3), checkout.PaymentID)
+	assert.Equal(t, "https://pay.test/x", checkout.CheckoutURL)
+	paymentRepo.AssertExpectations(t)
+}
+
+type recordingProvider struct {
+	*FakeProvider
+	got IntentRequest
+}
+
+func (p *recordingProvider) CreateIntent(ctx context.Context, req IntentRequest) (Intent, error) {
+	p.got = req
+	return p.FakeProvider.CreateIntent(ctx, req)
+}
+
+func TestService_StartCheckout_SendsOrderCurrency(t *testing.T) {
+	svc, orderRepo, paymentRepo, _ := newTestService(t)
+	provider := &recordingProvider{FakeProvider: NewFakeProvider("https://pay.test", testWebhookSecret)}
+	svc.Provider = provider
+
+	order := pendingOrder()
+	order.Currency = "EUR"
+	orderRepo.On("GetOrderByID", mock.Anything, testTenantID, testOrderID).Return(order, nil)
+	paymentRepo.On("GetInFlightPayment", mock.Anything, testTenantID, testOrderID, testNow).Return(nil, nil)
+	paymentRepo.On("CreatePayment", mock.Anything, mock.Anything).Return(pModel.Payment{ID: 3, IDOrder: testOrderID}, nil)
+
+	_, err := svc.StartCheckout(context.Background(), testTenantID, testTrackingToken)
+
+	require.NoError(t, err)
+	assert.Equal(t, money.Currency("EUR"), provider.got.Currency)
+	assert.Equal(t, money.Amount(3550), provider.got.Amount)
+}
+
+func TestService_StartCheckout_ReusesInFlightPayment(t *testing.T) {
+	svc, orderRepo, paymentRepo, _ := newTestService(t)
+
+	inFlight := &pModel.Payment{ID: 9, IDOrder: testOrderID, Provider: "fake", Amount: 3550, CheckoutURL: "https://pay.test/existing"}
+	orderRepo.On("GetOrderByID", mock.Anything, testTenantID, testOrderID).Return(pendingOrder(), nil)
+	paymentRepo.On("GetInFlightPayment", mock.Anything, testTenantID, testOrderID, testNow).Return(inFlight, nil)
+
+	checkout, err := svc.StartCheckout(context.Background(), testTenantID, testTrackingToken)
+
+	require.NoError(t, err)
+	assert.Equal(t, uint64(9), checkout.PaymentID)
+	paymentRepo.AssertNotCalled(t, "CreatePayment", mock.Anything, mock.Anything)
+}
+
+func TestService_StartCheckout_ChargesBalanceAfterDeposit(t *testing.T) {
+	svc, orderRepo, paymentRepo, _ := newTestService(t)
+
+	order := pendingOrder()
+	order.AmountPaid = 1000
+	order.BalanceDue = 2550
+	orderRepo.On("GetOrderByID", mock.Anything, testTenantID, testOrderID).Return(order, nil)
+	// A session opened before the deposit was recorded is for the wrong amount and is not reused.
+	stale := &pModel.Payment{ID: 9, IDOrder: testOrderID, Provider: "fake", Amount: 3550}
+	paymentRepo.On("GetInFlightPayment", mock.Anything, testTenantID, testOrderID, testNow).Return(stale, nil)
+	paymentRepo.On("CreatePayment", mock.Anything, mock.MatchedBy(func(req pModel.CreatePaymentRequest) bool {
+		return req.Amount == 2550
+	})).Return(pModel.Payment{ID: 10, IDOrder: testOrderID, Provider: "fake", Amount: 2550}, nil)
+
+	checkout, err := svc.StartCheckout(context.Background(), testTenantID, testTrackingToken)
+
+	require.NoError(t, err)
+	assert.Equal(t, uint64(10), checkout.PaymentID)
+	assert.Equal(t, money.Amount(2550), checkout.Amount)
+	paymentRepo.AssertExpectations(t)
+}
+
+func TestService_StartCheckout_RejectsPaidOrExpiredOrders(t *testing.T) {
+	t.Run("already_paid", func(t *testing.T) {
+		svc, orderRepo, _, _ := newTestService(t)
+		order := pendingOrder()
+		order.Paid = true
+		orderRepo.On("GetOrderByID", mock.Anything, testTenantID, testOrderID).Return(order, nil)
+
+		_, err := svc.StartCheckout(context.Background(), testTenantID, testTrackingToken)
+		assert.ErrorIs(t, err, appErrors.ErrOrderAlreadyPaid)
+	})
+
+	t.Run("balance_covered_by_deposits", func(t *testing.T) {
+		svc, orderRepo, _, _ := newTestService(t)
+		order := pendingOrder()
+		order.AmountPaid = 3550
+		order.BalanceDue = 0
+		orderRepo.On("GetOrderByID", mock.Anything, testTenantID, testOrderID).Return(order, nil)
+
+		_, err := svc.StartCheckout(context.Background(), testTenantID, testTrackingToken)
+		assert.ErrorIs(t, err, appErrors.ErrOrderAlreadyPaid)
+	})
+
+	t.Run("cancelled", func(t *testing.T) {
+		svc, orderRepo, _, _ := newTestService(t)
+		order := pendingOrder()
+		order.Status = oModel.StatusCancelled
+		orderRepo.On("GetOrderByID", mock.Anything, testTenantID, testOrderID).Return(order, nil)
+
+		_, err := svc.StartCheckout(context.Background(), testTenantID, testTrackingToken)
+		assert.ErrorIs(t, err, appErrors.ErrOrderNotPayable)
+	})
+
+	t.Run("pending_past_expiry_without_payment", func(t *testing.T) {
+		svc, orderRepo, paymentRepo, _ := newTestService(t)
+		order := pendingOrder()
+		order.ExpiresAt = testNow.Add(-time.Minute)
+		orderRepo.On("GetOrderByID", mock.Anything, testTenantID, testOrderID).Return(order, nil)
+		paymentRepo.On("GetInFlightPayment", mock.Anything, testTenantID, testOrderID, testNow).Return(nil, nil)
+
+		_, err := svc.StartCheckout(context.Background(), testTenantID, testTrackingToken)
+		assert.ErrorIs(t, err, appErrors.ErrOrderNotPayable)
+	})
+}
+
+func TestService_StartCheckout_NoProvider(t *testing.T) {
+	svc := NewService(nil, nil, nil, nil)
+
+	_, err := svc.StartCheckout(context.Background(), testTenantID, testTrackingToken)
+
+	assert.ErrorIs(t, err, appErrors.ErrPaymentProviderNotEnabled)
+}
+
+func TestService_StartCheckout_UnknownTrackingToken(t *testing.T) {
+	svc, orderRepo, paymentRepo, _ := newTestService(t)
+	orderRepo.On("GetOrderIDByTrackingTokenHash", mock.Anything, testTenantID, svc.Tokens.Hash("WRONG")).
+		Return(uint64(0), appErrors.NewNotFound(appErrors.ErrOrderNotFound))
+
+	_, err := svc.StartCheckout(context.Background(), testTenantID, "WRONG")
+	assertNotFound(t, err)
+
+	_, err = svc.StartCheckout(context.Background(), testTenantID, "  ")
+	assertNotFound(t, err)
+
+	orderRepo.AssertNotCalled(t, "GetOrderByID", mock.Anything, mock.Anything, mock.Anything)
+	paymentRepo.AssertNotCalled(t, "CreatePayment", mock.Anything, mock.Anything)
+}
+
+func assertNotFound(t *testing.T, err error) {
+	t.Helper()
+	var httpErr *appErrors.HTTPError
+	require.ErrorAs(t, err, &httpErr)
+	assert.Equal(t, http.StatusNotFound, httpErr.StatusCode)
+}
+
+func TestService_HandleWebhook_SucceededMarksOrderPaid(t *testing.T) {
+	svc, orderRepo, paymentRepo, sqlMock := newTestService(t)
+	sqlMock.ExpectBegin()
+	sqlMock.ExpectCommit()
+
+	body := []byte(`{"payment_id":"fake_abc","status":"succeeded"}`)
+	payment := pModel.Payment{ID: 5, TenantID: testTenantID, IDOrder: testOrderID, Provider: "fake", ProviderPaymentID: "fake_abc", Status: pModel.StatusPending, Amount: 2550}
+
+	paymentRepo.On("LockPaymentByProviderIDTx", mock.Anything, mock.Anything, "fake", "fake_abc").Return(payment, nil)
+	orderRepo.On("GetOrderByID", mock.Anything, testTenantID, testOrderID).Return(pendingOrder(), nil)
+	orderRepo.On("LockOrderPaymentStateTx", mock.Anything, mock.Anything, testTenantID, testOrderID).
+		Return(oModel.OrderPaymentState{Status: oModel.StatusPending, TotalPrice: 3550, AmountPaid: 1000}, nil)
+	paymentRepo.On("MarkPaymentSucceededTx", mock.Anything, mock.Anything, testTenantID, uint64(5), testNow).Return(nil)
+	orderRepo.On("CreateOrderPaymentTx", mock.Anything, mock.Anything, mock.MatchedBy(func(p oModel.OrderPaymentRequest) bool {
+		return p.IDOrder == testOrderID && p.Amount == 2550 && p.Method == oModel.PaymentMethodOnline &&
+			p.IDPayment != nil && *p.IDPayment == 5 && p.Reference != nil && *p.Reference == "fake_abc" && !p.IsRefund
+	})).Return(oModel.OrderPayment{ID: 1}, nil)
+	orderRepo.On("UpdateOrderPaidStatusTx", mock.Anything, mock.Anything, testTenantID, testOrderID, true).Return(nil)
+	orderRepo.On("CreateOrderHistoryTx", mock.Anything, mock.Anything, mock.MatchedBy(func(h oModel.OrderHistory) bool {
+		return h.IDOrder == testOrderID && h.Paid && h.Status == oModel.StatusPending && h.ModifiedBy == systemModifiedByID && h.Action == oModel.ActionUpdate
+	})).Return(nil)
+
+	err := svc.HandleWebhook(context.Background(), signedHeader(body), body)
+
+	require.NoError(t, err)
+	orderRepo.AssertExpectations(t)
+	paymentRepo.AssertExpectations(t)
+	require.NoError(t, sqlMock.ExpectationsWereMet())
+}
+
+func TestService_HandleWebhook_ReplayIsNoop(t *testing.T) {
+	svc, orderRepo, paymentRepo, sqlMock := newTestService(t)
+	sqlMock.ExpectBegin()
+	sqlMock.ExpectRollback()
+
+	body := []byte(`{"payment_id":"fake_abc","status":"failed"}`)
+	payment := pModel.Payment{ID: 5, TenantID: testTenantID, IDOrder: testOrderID, Provider: "fake", ProviderPaymentID: "fake_abc", Status: pModel.StatusSucceeded}
+	paymentRepo.On("LockPaymentByProviderIDTx", mock.Anything, mock.Anything, "fake", "fake_abc").Return(payment, nil)
+
+	err := svc.HandleWebhook(context.Background(), signedHeader(body), body)
+
+	require.NoError(t, err)
+	paymentRepo.AssertNotCalled(t, "UpdatePaymentStatusTx", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	orderRepo.AssertNotCalled(t, "UpdateOrderPaidStatusTx", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	require.NoError(t, sqlMock.ExpectationsWereMet())
+}
+
+func TestService_HandleWebhook_FailedUpdatesPaymentOnly(t *testing.T) {
+	svc, orderRepo, paymentRepo, sqlMock := newTestService(t)
+	sqlMock.ExpectBegin()
+	sqlMock.ExpectCommit()
+
+	body := []byte(`{"payment_id":"fake_abc","status":"failed"}`)
+	payment := pModel.Payment{ID: 5, TenantID: testTenantID, IDOrder: testOrderID, Provider: "fake", ProviderPaymentID: "fake_abc", Status: pModel.StatusPending}
+	paymentRepo.On("LockPaymentByProviderIDTx", mock.Anything, mock.Anything, "fake", "fake_abc").Return(payment, nil)
+	paymentRepo.On("UpdatePaymentStatusTx", mock.Anything, mock.Anything, testTenantID, uint64(5), pModel.StatusFailed).Return(nil)
+
+	err := svc.HandleWebhook(context.Background(), signedHeader(body), body)
+
+	require.NoError(t, err)
+	orderRepo.AssertNotCalled(t, "UpdateOrderPaidStatusTx", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	require.NoError(t, sqlMock.ExpectationsWereMet())
+}
+
+func TestService_HandleWebhook_InvalidSignature(t *testing.T) {
+	svc, _, _, sqlMock := newTestService(t)
+
+	body := []byte(`{"payment_id":"fake_abc","status":"succeeded"}`)
+	header := http.Header{}
+	header.Set(HeaderSignature, Sign("wrong-secret", body))
+
+	err := svc.HandleWebhook(context.Background(), header, body)
+
+	assert.ErrorIs(t, err, appErrors.ErrInvalidPaymentSignature)
+	require.NoError(t, sqlMock.ExpectationsWereMet())
+}
@@ -0,0 +1,107 @@
+// Package retryqueue holds what the outbox workers (order emails, webhook deliveries) share: the
+// claim lease, exponential backoff between attempts, the stored error message and the polling loop.
+package retryqueue
+
+import (
+	"context"
+	"time"
+	"unicode/utf8"
+
+	"github.com/radamesvaz/bakery-app/internal/logger"
+)
+
+const (
+	// MaxStoredErrorLength caps the last_error kept on a failed job.
+	MaxStoredErrorLength = 500
+
+	// leaseMargin is added to the time a batch can take so bookkeeping queries and clock skew
+	// between workers do not eat into the last job's lease.
+	leaseMargin = time.Minute
+
+	defaultIntervalSeconds = 30
+)
+
+// Policy decides when a failed job is tried again.
+type Policy struct {
+	MaxAttempts int
+	BaseBackoff time.Duration
+	MaxBackoff  time.Duration
+}
+
+// Backoff returns BaseBackoff * 2^(attempt-1), capped at MaxBackoff.
+func (p Policy) Backoff(attempt int) time.Duration {
+	delay := p.BaseBackoff
+	for i := 1; i < attempt; i++ {
+		delay *= 2
+		if delay >= p.MaxBackoff {
+			return p.MaxBackoff
+		}
+	}
+	return delay
+}
+
+// NextAttempt returns when to retry a job whose attempt (1-based) failed at now, or nil once
+// MaxAttempts is reached and the job gives up.
+func (p Policy) NextAttempt(now time.Time, attempt int) *time.Time {
+	if attempt >= p.MaxAttempts {
+		return nil
+	}
+	next := now.Add(p.Backoff(attempt))
+	return &next
+}
+
+// Lease is how long a worker holds the jobs it claimed; other workers skip them until it ends.
+type Lease struct {
+	Until time.Time
+}
+
+// NewLease covers sending a batch of batchSize jobs one after another, each taking up to perJob,
+// so claimed jobs do not become due again while this run still holds them.
+func NewLease(now time.Time, batchSize int, perJob time.Duration) Lease {
+	return Lease{Until: now.Add(time.Duration(max(batchSize, 1))*perJob + leaseMargin)}
+}
+
+// Expired reports whether the remaining jobs of the batch may already be claimed by another
+// worker and must be left to the next run.
+func (l Lease) Expired(now time.Time) bool {
+	return !now.Before(l.Until)
+}
+
+// Truncate cuts s to at most maxBytes without splitting a UTF-8 sequence.
+func Truncate(s string, maxBytes int) string {
+	if len(s) <= maxBytes {
+		return s
+	}
+	cut := maxBytes
+	for cut > 0 && !utf8.RuneStart(s[cut]) {
+		cut--
+	}
+	return s[:cut]
+}
+
+// Run calls runOnce every intervalSeconds until ctx is cancelled. name prefixes the log lines;
+// runOnce logs its own results and returns an error only when the run itself failed.
+func Run(ctx context.Context, name string, intervalSeconds, maxAttempts int, runOnce func(context.Context) error) {
+	if intervalSeconds <= 0 {
+		intervalSeconds = defaultIntervalSeconds
+	}
+	ticker := time.NewTicker(time.Duration(intervalSeconds) * time.Second)
+	defer ticker.Stop()
+
+	logger.Info().
+		Int("interval_seconds", intervalSeconds).
+		Int("max_attempts", maxAttempts).
+		Msg(name + ": started")
+
+	for {
+		select {
+		case <-ctx.Done():
+			logger.Info().Msg(name + ": stopping")
+			return
+		case <-ticker.C:
+			if err := runOnce(ctx); err != nil {
+				logger.Err(err).Msg(name + ": run failed")
+			}
+		}
+	}
+}
@@ -0,0 +1,46 @@
+package retryqueue
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPolicy_Backoff_CapsAtMax(t *testing.T) {
+	p := Policy{BaseBackoff: time.Minute, MaxBackoff: 10 * time.Minute}
+
+	assert.Equal(t, time.Minute, p.Backoff(1))
+	assert.Equal(t, 2*time.Minute, p.Backoff(2))
+	assert.Equal(t, 8*time.Minute, p.Backoff(4))
+	assert.Equal(t, 10*time.Minute, p.Backoff(5))
+	assert.Equal(t, 10*time.Minute, p.Backoff(20))
+}
+
+func TestPolicy_NextAttempt_GivesUpAtMaxAttempts(t *testing.T) {
+	p := Policy{MaxAttempts: 3, BaseBackoff: time.Minute, MaxBackoff: time.Hour}
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+
+	next := p.NextAttempt(now, 2)
+	require.NotNil(t, next)
+	assert.Equal(t, now.Add(2*time.Minute), *next)
+	assert.Nil(t, p.NextAttempt(now, 3))
+}
+
+func TestNewLease_CoversASerialBatch(t *testing.T) {
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	lease := NewLease(now, 50, 10*time.Second)
+
+	assert.Equal(t, now.Add(50*10*time.Second+leaseMargin), lease.Until)
+	assert.False(t, lease.Expired(lease.Until.Add(-time.Second)))
+	assert.True(t, lease.Expired(lease.Until))
+}
+
+func TestTruncate_KeepsRunesWhole(t *testing.T) {
+	assert.Equal(t, "short", Truncate("short", 10))
+	assert.Equal(t, "abc", Truncate("abcdef", 3))
+	// "ñ" is two bytes; cutting after its first byte must drop it entirely.
+	assert.Equal(t, "pa", Truncate("paño", 3))
+	assert.Equal(t, "pañ", Truncate("paño", 4))
+}
@@ -0,0 +1,227 @@
+package standingorders
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	appErrors "github.com/radamesvaz/bakery-app/internal/errors"
+	"github.com/radamesvaz/bakery-app/internal/logger"
+	orderService "github.com/radamesvaz/bakery-app/internal/services/orders"
+	oModel "github.com/radamesvaz/bakery-app/model/orders"
+	soModel "github.com/radamesvaz/bakery-app/model/standingorders"
+)
+
+const (
+	DefaultDaysAhead   = 7
+	DefaultMaxAttempts = 3
+)
+
+// Repository is the storage used by the Generator. It is implemented by the standing orders repository.
+type Repository interface {
+	ListDueStandingOrders(ctx context.Context, tenantID uint64, from, to time.Time) ([]soModel.StandingOrder, error)
+	ListOccurrencesBetween(ctx context.Context, tenantID uint64, from, to time.Time) ([]soModel.Occurrence, error)
+	RecordOccurrence(ctx context.Context, tenantID, id uint64, deliveryDate time.Time, status soModel.OccurrenceStatus, orderID *uint64, lastError *string) error
+}
+
+// TenantLister returns the tenants to process. It is implemented by the tenant repository.
+type TenantLister interface {
+	ListActiveTenantIDs(ctx context.Context) ([]uint64, error)
+}
+
+// OrderCreator creates the orders. It is implemented by orders.Creator, so standing orders go
+// through the same stock reservation, snapshots, history, webhooks and emails as storefront orders.
+type OrderCreator interface {
+	CreateOrderWithOptions(ctx context.Context, tenantID uint64, payload oModel.CreateOrderPayload, deliveryDate time.Time, opts orderService.CreateOrderOptions) (oModel.CreateOrderResult, error)
+}
+
+// Generator turns standing orders into real orders for the next DaysAhead days.
+type Generator struct {
+	Repo    Repository
+	Tenants TenantLister
+	Orders  OrderCreator
+	// DaysAhead is how many days after today are generated (tomorrow included); <= 0 means DefaultDaysAhead.
+	DaysAhead int
+	// MaxAttempts is how many times a failed delivery date is retried; <= 0 means DefaultMaxAttempts.
+	MaxAttempts int
+	// Now defaults to time.Now; tests override it.
+	Now func() time.Time
+}
+
+// RunResult counts the delivery dates handled by one Generate run.
+type RunResult struct {
+	Created int
+	Skipped int
+	Failed  int
+}
+
+// IdempotencyKey is the order Idempotency-Key of one delivery date of a standing order. If the
+// worker stops between creating the order and recording the occurrence, the retry gets the same
+// order back instead of creating a second one.
+func IdempotencyKey(standingOrderID uint64, deliveryDate time.Time) string {
+	return fmt.Sprintf("standing-order-%d-%s", standingOrderID, deliveryDate.Format(soModel.DateLayout))
+}
+
+func (g *Generator) now() time.Time {
+	if g.Now != nil {
+		return g.Now()
+	}
+	return time.Now()
+}
+
+// Generate creates the orders of every active tenant's standing orders for the delivery dates from
+// tomorrow to today + DaysAhead (UTC). Dates already created or skipped are left alone; failed dates
+// are retried until MaxAttempts. Blackout dates are recorded as skipped. A failure on one date does
+// not stop the others.
+func (g *Generator) Generate(ctx context.Context) (RunResult, error) {
+	daysAhead := g.DaysAhead
+	if daysAhead <= 0 {
+		daysAhead = DefaultDaysAhead
+	}
+	now := g.now().UTC()
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+	from := today.AddDate(0, 0, 1)
+	to := today.AddDate(0, 0, daysAhead)
+
+	tenantIDs, err := g.Tenants.ListActiveTenantIDs(ctx)
+	if err != nil {
+		return RunResult{}, fmt.Errorf("list active tenants: %w", err)
+	}
+
+	var result RunResult
+	for _, tenantID := range tenantIDs {
+		if err := g.generateTenant(ctx, tenantID, from, to, &result); err != nil {
+			return result, fmt.Errorf("tenant %d: %w", tenantID, err)
+		}
+	}
+	return result, nil
+}
+
+func (g *Generator) generateTenant(ctx context.Context, tenantID uint64, from, to time.Time, result *RunResult) error {
+	standingOrders, err := g.Repo.ListDueStandingOrders(ctx, tenantID, from, to)
+	if err != nil {
+		return err
+	}
+	if len(standingOrders) == 0 {
+		return nil
+	}
+	occurrences, err := g.Repo.ListOccurrencesBetween(ctx, tenantID, from, to)
+	if err != nil {
+		return err
+	}
+	type occurrenceKey struct {
+		id   uint64
+		date string
+	}
+	done := make(map[occurrenceKey]soModel.Occurrence, len(occurrences))
+	for _, o := range occurrences {
+		done[occurrenceKey{o.IDStandingOrder, o.DeliveryDate}] = o
+	}
+
+	maxAttempts := g.MaxAttempts
+	if maxAttempts <= 0 {
+		maxAttempts = DefaultMaxAttempts
+	}
+
+	for _, so := range standingOrders {
+		for date := from; !date.After(to); date = date.AddDate(0, 0, 1) {
+			if !so.OccursOn(date) {
+				continue
+			}
+			if o, ok := done[occurrenceKey{so.ID, date.Format(soModel.DateLayout)}]; ok {
+				if o.Status != soModel.OccurrenceFailed || o.Attempts >= maxAttempts {
+					continue
+				}
+			}
+			status, orderID, lastError := g.createOccurrence(ctx, tenantID, so, date)
+			if err := g.Repo.RecordOccurrence(ctx, tenantID, so.ID, date, status, orderID, lastError); err != nil {
+				return err
+			}
+			switch status {
+			case soModel.OccurrenceCreated:
+				result.Created++
+			case soModel.OccurrenceSkipped:
+				result.Skipped++
+			default:
+				result.Failed++
+			}
+		}
+	}
+	return nil
+}
+
+func (g *Generator) createOccurrence(ctx context.Context, tenantID uint64, so soModel.StandingOrder, date time.Time) (soModel.OccurrenceStatus, *uint64, *string) {
+	items := make([]oModel.CreateOrderItemInput, len(so.Items))
+	for i, item := range so.Items {
+		items[i] = oModel.CreateOrderItemInput{IdProduct: item.IdProduct, Quantity: item.Quantity}
+	}
+	payload := oModel.CreateOrderPayload{
+		Name:              so.Name,
+		Email:             so.Email,
+		Phone:             so.Phone,
+		DeliveryDate:      date.Format(soModel.DateLayout),
+		DeliveryDirection: so.DeliveryDirection,
+		Note:              so.Note,
+		Items:             items,
+	}
+	res, err := g.Orders.CreateOrderWithOptions(ctx, tenantID, payload, date, orderService.CreateOrderOptions{
+		IdempotencyKey: IdempotencyKey(so.ID, date),
+		NoExpiry:       true,
+	})
+	if err != nil {
+		msg := err.Error()
+		status := soModel.OccurrenceFailed
+		if errors.Is(err, appErrors.ErrDeliveryDateClosed) {
+			status = soModel.OccurrenceSkipped
+		}
+		logger.Warn().
+			Err(err).
+			Uint64("tenant_id", tenantID).
+			Uint64("id_standing_order", so.ID).
+			Str("delivery_date", payload.DeliveryDate).
+			Str("status", string(status)).
+			Msg("Standing order job: order not created")
+		return status, nil, &msg
+	}
+	orderID := res.Order.ID
+	return soModel.OccurrenceCreated, &orderID, nil
+}
+
+// RunWorker runs Generate once at start and then every intervalMinutes until ctx is cancelled.
+func RunWorker(ctx context.Context, g *Generator, intervalMinutes int) {
+	if intervalMinutes <= 0 {
+		intervalMinutes = 60
+	}
+	ticker := time.NewTicker(time.Duration(intervalMinutes) * time.Minute)
+	defer ticker.Stop()
+
+	logger.Info().
+		Int("interval_minutes", intervalMinutes).
+		Msg("Standing order worker: started")
+
+	run := func() {
+		logger.Info().Msg("Standing order job: starting run")
+		result, err := g.Generate(ctx)
+		if err != nil {
+			logger.Err(err).Msg("Standing order job: run failed")
+			return
+		}
+		logger.Info().
+			Int("created", result.Created).
+			Int("skipped", result.Skipped).
+			Int("failed", result.Failed).
+			Msg("Standing order job: run finished")
+	}
+
+	run()
+	for {
+		select {
+		case <-ctx.Done():
+			logger.Info().Msg("Standing order worker: stopping")
+			return
+		case <-ticker.C:
+			run()
+		}
+	}
+}
@@ -0,0 +1,193 @@
+package standingorders
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	appErrors "github.com/radamesvaz/bakery-app/internal/errors"
+	orderService "github.com/radamesvaz/bakery-app/internal/services/orders"
+	oModel "github.com/radamesvaz/bakery-app/model/orders"
+	soModel "github.com/radamesvaz/bakery-app/model/standingorders"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+type MockRepository struct {
+	mock.Mock
+}
+
+func (m *MockRepository) ListDueStandingOrders(ctx context.Context, tenantID uint64, from, to time.Time) ([]soModel.StandingOrder, error) {
+	args := m.Called(ctx, tenantID, from, to)
+	rows, _ := args.Get(0).([]soModel.StandingOrder)
+	return rows, args.Error(1)
+}
+
+func (m *MockRepository) ListOccurrencesBetween(ctx context.Context, tenantID uint64, from, to time.Time) ([]soModel.Occurrence, error) {
+	args := m.Called(ctx, tenantID, from, to)
+	rows, _ := args.Get(0).([]soModel.Occurrence)
+	return rows, args.Error(1)
+}
+
+func (m *MockRepository) RecordOccurrence(ctx context.Context, tenantID, id uint64, deliveryDate time.Time, status soModel.OccurrenceStatus, orderID *uint64, lastError *string) error {
+	args := m.Called(ctx, tenantID, id, deliveryDate, status, orderID, lastError)
+	return args.Error(0)
+}
+
+type MockTenantLister struct {
+	mock.Mock
+}
+
+func (m *MockTenantLister) ListActiveTenantIDs(ctx context.Context) ([]uint64, error) {
+	args := m.Called(ctx)
+	ids, _ := args.Get(0).([]uint64)
+	return ids, args.Error(1)
+}
+
+type MockOrderCreator struct {
+	mock.Mock
+}
+
+func (m *MockOrderCreator) CreateOrderWithOptions(ctx context.Context, tenantID uint64, payload oModel.CreateOrderPayload, deliveryDate time.Time, opts orderService.CreateOrderOptions) (oModel.CreateOrderResult, error) {
+	args := m.Called(ctx, tenantID, payload, deliveryDate, opts)
+	return args.Get(0).(oModel.CreateOrderResult), args.Error(1)
+}
+
+func day(y int, m time.Month, d int) time.Time {
+	return time.Date(y, m, d, 0, 0, 0, 0, time.UTC)
+}
+
+func strPtr(s string) *string { return &s }
+
+func uint64Ptr(v uint64) *uint64 { return &v }
+
+// mondayWednesdayFriday delivers two croissants on Mon/Wed/Fri from 2026-03-01.
+func mondayWednesdayFriday() soModel.StandingOrder {
+	return soModel.StandingOrder{
+		ID:                7,
+		TenantID:          1,
+		Name:              "Cafe Central",
+		Email:             "cafe@example.com",
+		Phone:             "555",
+		DeliveryDirection: "Main St 1",
+		Weekdays:          []int{1, 3, 5},
+		StartDate:         "2026-03-01",
+		Active:            true,
+		Items:             []soModel.StandingOrderItem{{IdProduct: 3, Quantity: 2}},
+	}
+}
+
+func expectedPayload(date string) oModel.CreateOrderPayload {
+	return oModel.CreateOrderPayload{
+		Name:              "Cafe Central",
+		Email:             "cafe@example.com",
+		Phone:             "555",
+		DeliveryDate:      date,
+		DeliveryDirection: "Main St 1",
+		Items:             []oModel.CreateOrderItemInput{{IdProduct: 3, Quantity: 2}},
+	}
+}
+
+func newGenerator(repo *MockRepository, tenants *MockTenantLister, orders *MockOrderCreator) *Generator {
+	return &Generator{
+		Repo:    repo,
+		Tenants: tenants,
+		Orders:  orders,
+		// Sunday 2026-03-01: the default window is Mon 03-02 .. Sun 03-08.
+		Now: func() time.Time { return time.Date(2026, 3, 1, 10, 0, 0, 0, time.UTC) },
+	}
+}
+
+func TestGenerator_Generate_CreatesSkipsAndRetries(t *testing.T) {
+	repo := new(MockRepository)
+	tenants := new(MockTenantLister)
+	orders := new(MockOrderCreator)
+	g := newGenerator(repo, tenants, orders)
+	ctx := context.Background()
+	from, to := day(2026, 3, 2), day(2026, 3, 8)
+
+	tenants.On("ListActiveTenantIDs", ctx).Return([]uint64{1}, nil)
+	repo.On("ListDueStandingOrders", ctx, uint64(1), from, to).Return([]soModel.StandingOrder{mondayWednesdayFriday()}, nil)
+	repo.On("ListOccurrencesBetween", ctx, uint64(1), from, to).Return([]soModel.Occurrence{
+		{IDStandingOrder: 7, DeliveryDate: "2026-03-02", Status: soModel.OccurrenceCreated, Attempts: 1},
+		{IDStandingOrder: 7, DeliveryDate: "2026-03-04", Status: soModel.OccurrenceFailed, Attempts: 1},
+	}, nil)
+
+	closedErr := appErrors.ErrDeliveryDateClosed
+	orders.On("CreateOrderWithOptions", ctx, uint64(1), expectedPayload("2026-03-04"), day(2026, 3, 4), orderService.CreateOrderOptions{
+		IdempotencyKey: "standing-order-7-2026-03-04",
+		NoExpiry:       true,
+	}).Return(oModel.CreateOrderResult{}, closedErr)
+	orders.On("CreateOrderWithOptions", ctx, uint64(1), expectedPayload("2026-03-06"), day(2026, 3, 6), orderService.CreateOrderOptions{
+		IdempotencyKey: "standing-order-7-2026-03-06",
+		NoExpiry:       true,
+	}).Return(oModel.CreateOrderResult{Order: oModel.OrderResponse{ID: 42}}, nil)
+
+	repo.On("RecordOccurrence", ctx, uint64(1), uint64(7), day(2026, 3, 4), soModel.OccurrenceSkipped, (*uint64)(nil), strPtr(closedErr.Error())).Return(nil)
+	repo.On("RecordOccurrence", ctx, uint64(1), uint64(7), day(2026, 3, 6), soModel.OccurrenceCreated, uint64Ptr(42), (*string)(nil)).Return(nil)
+
+	result, err := g.Generate(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, RunResult{Created: 1, Skipped: 1}, result)
+	repo.AssertExpectations(t)
+	orders.AssertExpectations(t)
+	orders.AssertNumberOfCalls(t, "CreateOrderWithOptions", 2)
+}
+
+func TestGenerator_Generate_RecordsFailuresAndStopsRetrying(t *testing.T) {
+	repo := new(MockRepository)
+	tenants := new(MockTenantLister)
+	orders := new(MockOrderCreator)
+	g := newGenerator(repo, tenants, orders)
+	g.MaxAttempts = 2
+	ctx := context.Background()
+	from, to := day(2026, 3, 2), day(2026, 3, 8)
+
+	tenants.On("ListActiveTenantIDs", ctx).Return([]uint64{1}, nil)
+	repo.On("ListDueStandingOrders", ctx, uint64(1), from, to).Return([]soModel.StandingOrder{mondayWednesdayFriday()}, nil)
+	repo.On("ListOccurrencesBetween", ctx, uint64(1), from, to).Return([]soModel.Occurrence{
+		{IDStandingOrder: 7, DeliveryDate: "2026-03-02", Status: soModel.OccurrenceFailed, Attempts: 2},
+		{IDStandingOrder: 7, DeliveryDate: "2026-03-04", Status: soModel.OccurrenceSkipped, Attempts: 1},
+	}, nil)
+
+	stockErr := appErrors.NewConflict(appErrors.ErrNotEnoughProductStock)
+	orders.On("CreateOrderWithOptions", ctx, uint64(1), expectedPayload("2026-03-06"), day(2026, 3, 6), mock.Anything).
+		Return(oModel.CreateOrderResult{}, stockErr)
+	repo.On("RecordOccurrence", ctx, uint64(1), uint64(7), day(2026, 3, 6), soModel.OccurrenceFailed, (*uint64)(nil), strPtr(stockErr.Error())).Return(nil)
+
+	result, err := g.Generate(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, RunResult{Failed: 1}, result)
+	orders.AssertNumberOfCalls(t, "CreateOrderWithOptions", 1)
+	repo.AssertExpectations(t)
+}
+
+func TestGenerator_Generate_ReturnsRepositoryErrors(t *testing.T) {
+	repo := new(MockRepository)
+	tenants := new(MockTenantLister)
+	orders := new(MockOrderCreator)
+	g := newGenerator(repo, tenants, orders)
+	ctx := context.Background()
+
+	tenants.On("ListActiveTenantIDs", ctx).Return([]uint64{1}, nil)
+	repo.On("ListDueStandingOrders", ctx, uint64(1), day(2026, 3, 2), day(2026, 3, 8)).Return(nil, errors.New("db down"))
+
+	_, err := g.Generate(ctx)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "db down")
+	orders.AssertNotCalled(t, "CreateOrderWithOptions", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestStandingOrder_OccursOn(t *testing.T) {
+	so := mondayWednesdayFriday()
+	end := "2026-03-09"
+	so.EndDate = &end
+
+	assert.True(t, so.OccursOn(day(2026, 3, 2)))
+	assert.False(t, so.OccursOn(day(2026, 3, 3)))
+	assert.True(t, so.OccursOn(day(2026, 3, 9)))
+	assert.False(t, so.OccursOn(day(2026, 3, 11)), "after end date")
+	assert.False(t, so.OccursOn(day(2026, 2, 27)), "before start date")
+}
@@ -17,6 +17,7 @@ import (
 )
 
 type recordingSignupEmailSender struct {
+	email.NoopSender
 	last  email.TenantSignupCodePayload
 	calls int
 	err   error
@@ -0,0 +1,37 @@
+package webhooks
+
+import (
+	"fmt"
+	"net"
+	"net/http"
+	"syscall"
+
+	whModel "github.com/radamesvaz/bakery-app/model/webhooks"
+)
+
+// NewHTTPClient returns the client deliveries are sent with. Unless allowInternal is set (local
+// development), every connection is checked after DNS resolution, so a subscription host that
+// resolves (or is later re-pointed) to a loopback, private or link-local address is refused.
+// Proxies from the environment are not used: the check must see the real destination.
+func NewHTTPClient(allowInternal bool) *http.Client {
+	dialer := &net.Dialer{Timeout: DefaultRequestTimeout}
+	if !allowInternal {
+		dialer.Control = refuseInternalAddress
+	}
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.Proxy = nil
+	transport.DialContext = dialer.DialContext
+	return &http.Client{Timeout: DefaultRequestTimeout, Transport: transport}
+}
+
+// refuseInternalAddress runs right before connect with the resolved address.
+func refuseInternalAddress(network, address string, _ syscall.RawConn) error {
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		return fmt.Errorf("webhook destination %q: %w", address, err)
+	}
+	if !whModel.IsDeliverableIP(net.ParseIP(host)) {
+		return fmt.Errorf("webhook destination %s is not a public address", host)
+	}
+	return nil
+}
@@ -0,0 +1,208 @@
+package webhooks
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/radamesvaz/bakery-app/internal/logger"
+	"github.com/radamesvaz/bakery-app/internal/services/retryqueue"
+	whModel "github.com/radamesvaz/bakery-app/model/webhooks"
+)
+
+const (
+	DefaultMaxAttempts    = 8
+	DefaultBatchSize      = 50
+	DefaultBaseBackoff    = 30 * time.Second
+	DefaultMaxBackoff     = 6 * time.Hour
+	DefaultRequestTimeout = 10 * time.Second
+
+	// Headers sent with every delivery. The signature is
+	// hex(HMAC-SHA256(secret, timestamp + "." + body)), prefixed with "sha256=".
+	HeaderSignature = "X-Webhook-Signature"
+	HeaderTimestamp = "X-Webhook-Timestamp"
+	HeaderEvent     = "X-Webhook-Event"
+	HeaderDelivery  = "X-Webhook-Delivery"
+)
+
+// Repository defines the persistence needed by the dispatcher.
+type Repository interface {
+	FanOutEvents(ctx context.Context, limit int) (int64, error)
+	ClaimDueDeliveries(ctx context.Context, now, leaseUntil time.Time, limit int) ([]whModel.PendingDelivery, error)
+	MarkDeliverySucceeded(ctx context.Context, id uint64, statusCode int) error
+	MarkDeliveryAttemptFailed(ctx context.Context, id uint64, statusCode *int, lastError string, nextAttemptAt *time.Time) error
+}
+
+// Dispatcher moves outbox events to subscriber endpoints with retries and exponential backoff.
+type Dispatcher struct {
+	retryqueue.Policy
+	Repo      Repository
+	Client    *http.Client
+	BatchSize int
+	Now       func() time.Time
+}
+
+func NewDispatcher(repo Repository, maxAttempts int) *Dispatcher {
+	if maxAttempts <= 0 {
+		maxAttempts = DefaultMaxAttempts
+	}
+	return &Dispatcher{
+		Policy: retryqueue.Policy{
+			MaxAttempts: maxAttempts,
+			BaseBackoff: DefaultBaseBackoff,
+			MaxBackoff:  DefaultMaxBackoff,
+		},
+		Repo:      repo,
+		Client:    NewHTTPClient(false),
+		BatchSize: DefaultBatchSize,
+		Now:       time.Now,
+	}
+}
+
+// DispatchResult summarizes one dispatcher run.
+type DispatchResult struct {
+	FannedOut int64
+	Succeeded int
+	Retrying  int
+	Failed    int
+}
+
+// DispatchPending fans new outbox events out to subscriptions, then sends every due delivery once.
+func (d *Dispatcher) DispatchPending(ctx context.Context) (DispatchResult, error) {
+	var result DispatchResult
+
+	fannedOut, err := d.Repo.FanOutEvents(ctx, d.BatchSize)
+	if err != nil {
+		return result, err
+	}
+	result.FannedOut = fannedOut
+
+	now := d.Now()
+	lease := retryqueue.NewLease(now, d.BatchSize, d.requestTimeout())
+	deliveries, err := d.Repo.ClaimDueDeliveries(ctx, now, lease.Until, d.BatchSize)
+	if err != nil {
+		return result, err
+	}
+
+	for _, delivery := range deliveries {
+		if lease.Expired(d.Now()) {
+			break
+		}
+		statusCode, sendErr := d.send(ctx, delivery)
+		if sendErr == nil {
+			if err := d.Repo.MarkDeliverySucceeded(ctx, delivery.ID, statusCode); err != nil {
+				return result, err
+			}
+			result.Succeeded++
+			continue
+		}
+
+		attempt := delivery.Attempts + 1
+		var codePtr *int
+		if statusCode != 0 {
+			codePtr = &statusCode
+		}
+		nextAttemptAt := d.NextAttempt(d.Now(), attempt)
+		if nextAttemptAt != nil {
+			result.Retrying++
+		} else {
+			result.Failed++
+		}
+
+		logger.Warn().Err(sendErr).
+			Uint64("tenant_id", delivery.TenantID).
+			Uint64("delivery_id", delivery.ID).
+			Str("event_type", string(delivery.EventType)).
+			Int("attempt", attempt).
+			Bool("gave_up", nextAttemptAt == nil).
+			Msg("Webhook delivery failed")
+
+		if err := d.Repo.MarkDeliveryAttemptFailed(ctx, delivery.ID, codePtr, retryqueue.Truncate(sendErr.Error(), retryqueue.MaxStoredErrorLength), nextAttemptAt); err != nil {
+			return result, err
+		}
+	}
+
+	return result, nil
+}
+
+// requestTimeout is the longest one delivery can take; the batch lease allows it for every row.
+func (d *Dispatcher) requestTimeout() time.Duration {
+	if d.Client != nil && d.Client.Timeout > 0 {
+		return d.Client.Timeout
+	}
+	return DefaultRequestTimeout
+}
+
+// send POSTs the signed envelope. A non-2xx response is returned as an error with its status code.
+func (d *Dispatcher) send(ctx context.Context, delivery whModel.PendingDelivery) (int, error) {
+	body, err := json.Marshal(whModel.Envelope{
+		ID:        delivery.EventID,
+		Type:      delivery.EventType,
+		TenantID:  delivery.TenantID,
+		CreatedOn: delivery.EventCreatedOn.UTC(),
+		Data:      delivery.Payload,
+	})
+	if err != nil {
+		return 0, fmt.Errorf("marshal envelope: %w", err)
+	}
+
+	timestamp := strconv.FormatInt(d.Now().Unix(), 10)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, delivery.URL, bytes.NewReader(body))
+	if err != nil {
+		return 0, fmt.Errorf("build request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(HeaderEvent, string(delivery.EventType))
+	req.Header.Set(HeaderDelivery, strconv.FormatUint(delivery.ID, 10))
+	req.Header.Set(HeaderTimestamp, timestamp)
+	req.Header.Set(HeaderSignature, "sha256="+Sign(delivery.Secret, timestamp, body))
+
+	resp, err := d.Client.Do(req)
+	if err != nil {
+		return 0, fmt.Errorf("post: %w", err)
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return resp.StatusCode, fmt.Errorf("unexpected status %d", resp.StatusCode)
+	}
+	return resp.StatusCode, nil
+}
+
+// Sign returns hex(HMAC-SHA256(secret, timestamp + "." + body)). Receivers recompute it to verify
+// the payload and reject stale timestamps to prevent replays.
+func Sign(secret, timestamp string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// RunDispatcherWorker runs DispatchPending every intervalSeconds until ctx is cancelled.
+func RunDispatcherWorker(ctx context.Context, d *Dispatcher, intervalSeconds int) {
+	retryqueue.Run(ctx, "Webhook dispatcher", intervalSeconds, d.MaxAttempts, func(ctx context.Context) error {
+		result, err := d.DispatchPending(ctx)
+		if err != nil {
+			return err
+		}
+		if result.FannedOut > 0 || result.Succeeded > 0 || result.Retrying > 0 || result.Failed > 0 {
+			logger.Info().
+				Int64("fanned_out", result.FannedOut).
+				Int("succeeded", result.Succeeded).
+				Int("retrying", result.Retrying).
+				Int("failed", result.Failed).
+				Msg("Webhook dispatcher: run finished")
+		}
+		return nil
+	})
+}
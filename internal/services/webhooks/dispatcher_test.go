@@ -0,0 +1,217 @@
+package webhooks
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	whModel "github.com/radamesvaz/bakery-app/model/webhooks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+type MockRepository struct {
+	mock.Mock
+}
+
+func (m *MockRepository) FanOutEvents(ctx context.Context, limit int) (int64, error) {
+	args := m.Called(ctx, limit)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *MockRepository) ClaimDueDeliveries(ctx context.Context, now, leaseUntil time.Time, limit int) ([]whModel.PendingDelivery, error) {
+	args := m.Called(ctx, now, leaseUntil, limit)
+	return args.Get(0).([]whModel.PendingDelivery), args.Error(1)
+}
+
+func (m *MockRepository) MarkDeliverySucceeded(ctx context.Context, id uint64, statusCode int) error {
+	args := m.Called(ctx, id, statusCode)
+	return args.Error(0)
+}
+
+func (m *MockRepository) MarkDeliveryAttemptFailed(ctx context.Context, id uint64, statusCode *int, lastError string, nextAttemptAt *time.Time) error {
+	args := m.Called(ctx, id, statusCode, lastError, nextAttemptAt)
+	return args.Error(0)
+}
+
+var fixedNow = time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+
+func newTestDispatcher(repo Repository) *Dispatcher {
+	d := NewDispatcher(repo, 3)
+	d.Now = func() time.Time { return fixedNow }
+	// httptest servers listen on loopback.
+	d.Client = NewHTTPClient(true)
+	return d
+}
+
+func pendingDelivery(url string, attempts int) whModel.PendingDelivery {
+	return whModel.PendingDelivery{
+		ID:             11,
+		TenantID:       1,
+		SubscriptionID: 2,
+		URL:            url,
+		Secret:         "whsec_test",
+		EventID:        5,
+		EventType:      whModel.EventOrderCreated,
+		Payload:        json.RawMessage(`{"id_order":9}`),
+		EventCreatedOn: fixedNow.Add(-time.Minute),
+		Attempts:       attempts,
+	}
+}
+
+func TestDispatcher_DispatchPending_SignsAndMarksSucceeded(t *testing.T) {
+	var gotBody []byte
+	var gotHeaders http.Header
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotHeaders = r.Header.Clone()
+		gotBody, _ = io.ReadAll(r.Body)
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	repo := new(MockRepository)
+	repo.On("FanOutEvents", mock.Anything, DefaultBatchSize).Return(int64(1), nil)
+	repo.On("ClaimDueDeliveries", mock.Anything, fixedNow, mock.Anything, DefaultBatchSize).
+		Return([]whModel.PendingDelivery{pendingDelivery(server.URL, 0)}, nil)
+	repo.On("MarkDeliverySucceeded", mock.Anything, uint64(11), http.StatusNoContent).Return(nil)
+
+	result, err := newTestDispatcher(repo).DispatchPending(context.Background())
+
+	require.NoError(t, err)
+	assert.Equal(t, DispatchResult{FannedOut: 1, Succeeded: 1}, result)
+	repo.AssertExpectations(t)
+
+	timestamp := gotHeaders.Get(HeaderTimestamp)
+	assert.Equal(t, "1772366400", timestamp)
+	assert.Equal(t, "sha256="+Sign("whsec_test", timestamp, gotBody), gotHeaders.Get(HeaderSignature))
+	assert.Equal(t, string(whModel.EventOrderCreated), gotHeaders.Get(HeaderEvent))
+	assert.Equal(t, "11", gotHeaders.Get(HeaderDelivery))
+
+	var envelope whModel.Envelope
+	require.NoError(t, json.Unmarshal(gotBody, &envelope))
+	assert.Equal(t, uint64(5), envelope.ID)
+	assert.Equal(t, whModel.EventOrderCreated, envelope.Type)
+	assert.JSONEq(t, `{"id_order":9}`, string(envelope.Data))
+}
+
+func TestDispatcher_DispatchPending_SchedulesRetryWithBackoff(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer server.Close()
+
+	repo := new(MockRepository)
+	repo.On("FanOutEvents", mock.Anything, DefaultBatchSize).Return(int64(0), nil)
+	repo.On("ClaimDueDeliveries", mock.Anything, fixedNow, mock.Anything, DefaultBatchSize).
+		Return([]whModel.PendingDelivery{pendingDelivery(server.URL, 1)}, nil)
+	expectedNext := fixedNow.Add(2 * DefaultBaseBackoff)
+	repo.On("MarkDeliveryAttemptFailed", mock.Anything, uint64(11),
+		mock.MatchedBy(func(code *int) bool { return code != nil && *code == http.StatusInternalServerError }),
+		"unexpected status 500",
+		mock.MatchedBy(func(next *time.Time) bool { return next != nil && next.Equal(expectedNext) }),
+	).Return(nil)
+
+	result, err := newTestDispatcher(repo).DispatchPending(context.Background())
+
+	require.NoError(t, err)
+	assert.Equal(t, DispatchResult{Retrying: 1}, result)
+	repo.AssertExpectations(t)
+}
+
+func TestDispatcher_DispatchPending_GivesUpAfterMaxAttempts(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadGateway)
+	}))
+	defer server.Close()
+
+	repo := new(MockRepository)
+	repo.On("FanOutEvents", mock.Anything, DefaultBatchSize).Return(int64(0), nil)
+	repo.On("ClaimDueDeliveries", mock.Anything, fixedNow, mock.Anything, DefaultBatchSize).
+		Return([]whModel.PendingDelivery{pendingDelivery(server.URL, 2)}, nil)
+	repo.On("MarkDeliveryAttemptFailed", mock.Anything, uint64(11), mock.Anything, "unexpected status 502", (*time.Time)(nil)).Return(nil)
+
+	result, err := newTestDispatcher(repo).DispatchPending(context.Background())
+
+	require.NoError(t, err)
+	assert.Equal(t, DispatchResult{Failed: 1}, result)
+	repo.AssertExpectations(t)
+}
+
+func TestDispatcher_DispatchPending_LeasesForAFullSerialBatch(t *testing.T) {
+	repo := new(MockRepository)
+	repo.On("FanOutEvents", mock.Anything, DefaultBatchSize).Return(int64(0), nil)
+	expectedLease := fixedNow.Add(DefaultBatchSize*DefaultRequestTimeout + time.Minute)
+	repo.On("ClaimDueDeliveries", mock.Anything, fixedNow, expectedLease, DefaultBatchSize).
+		Return([]whModel.PendingDelivery{}, nil)
+
+	_, err := newTestDispatcher(repo).DispatchPending(context.Background())
+
+	require.NoError(t, err)
+	repo.AssertExpectations(t)
+}
+
+func TestDispatcher_DispatchPending_StopsWhenLeaseRunsOut(t *testing.T) {
+	var requests int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	first := pendingDelivery(server.URL, 0)
+	second := pendingDelivery(server.URL, 0)
+	second.ID = 12
+
+	repo := new(MockRepository)
+	repo.On("FanOutEvents", mock.Anything, DefaultBatchSize).Return(int64(0), nil)
+	repo.On("ClaimDueDeliveries", mock.Anything, fixedNow, mock.Anything, DefaultBatchSize).
+		Return([]whModel.PendingDelivery{first, second}, nil)
+
+	d := newTestDispatcher(repo)
+	clock := fixedNow
+	d.Now = func() time.Time { return clock }
+	// The first send takes the whole lease.
+	repo.On("MarkDeliverySucceeded", mock.Anything, uint64(11), http.StatusNoContent).Run(func(mock.Arguments) {
+		clock = clock.Add(time.Hour)
+	}).Return(nil)
+
+	result, err := d.DispatchPending(context.Background())
+
+	require.NoError(t, err)
+	assert.Equal(t, DispatchResult{Succeeded: 1}, result)
+	assert.Equal(t, 1, requests)
+	repo.AssertNotCalled(t, "MarkDeliverySucceeded", mock.Anything, uint64(12), mock.Anything)
+}
+
+func TestSign_MatchesKnownDigest(t *testing.T) {
+	// echo -n '1700000000.{"a":1}' | openssl dgst -sha256 -hmac secret
+	assert.Equal(t,
+		"49f24e537407743fa4a0242bb63b94b9a47ee99cbbe071ccd8a22550ae411686",
+		Sign("secret", "1700000000", []byte(`{"a":1}`)),
+	)
+}
+
+func TestNewHTTPClient_RefusesInternalAddresses(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	// A hostname resolving to loopback is refused at connect time, not only literal IPs.
+	localhostURL := strings.Replace(server.URL, "127.0.0.1", "localhost", 1)
+	for _, target := range []string{server.URL, localhostURL} {
+		_, err := NewHTTPClient(false).Get(target)
+		assert.ErrorContains(t, err, "is not a public address", target)
+	}
+
+	resp, err := NewHTTPClient(true).Get(server.URL)
+	require.NoError(t, err)
+	resp.Body.Close()
+	assert.Equal(t, http.StatusNoContent, resp.StatusCode)
+}
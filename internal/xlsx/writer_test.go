@@ -0,0 +1,62 @@
+package xlsx
+
+import (
+	"archive/zip"
+	"bytes"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func readPart(t *testing.T, data []byte, name string) string {
+	t.Helper()
+	zr, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	require.NoError(t, err)
+	for _, f := range zr.File {
+		if f.Name == name {
+			rc, err := f.Open()
+			require.NoError(t, err)
+			defer rc.Close()
+			b, err := io.ReadAll(rc)
+			require.NoError(t, err)
+			return string(b)
+		}
+	}
+	t.Fatalf("part %s not found", name)
+	return ""
+}
+
+func TestWriter_WritesCellsByType(t *testing.T) {
+	var buf bytes.Buffer
+	w, err := NewWriter(&buf, "Orders & items")
+	require.NoError(t, err)
+
+	require.NoError(t, w.WriteRow("Order ID", "Customer"))
+	require.NoError(t, w.WriteRow(uint64(7), "Ana <Pérez>", 12.5, true, nil, time.Date(2026, 3, 1, 10, 0, 0, 0, time.UTC)))
+	require.NoError(t, w.Close())
+
+	sheet := readPart(t, buf.Bytes(), "xl/worksheets/sheet1.xml")
+	assert.Contains(t, sheet, `<row r="1"><c r="A1" t="inlineStr"><is><t xml:space="preserve">Order ID</t></is></c>`)
+	assert.Contains(t, sheet, `<c r="A2"><v>7</v></c>`)
+	assert.Contains(t, sheet, `Ana &lt;Pérez&gt;`)
+	assert.Contains(t, sheet, `<c r="C2"><v>12.5</v></c>`)
+	assert.Contains(t, sheet, `<c r="D2" t="b"><v>1</v></c>`)
+	assert.NotContains(t, sheet, `r="E2"`)
+	assert.Contains(t, sheet, `2026-03-01T10:00:00Z`)
+	assert.Contains(t, sheet, `</sheetData></worksheet>`)
+
+	workbook := readPart(t, buf.Bytes(), "xl/workbook.xml")
+	assert.Contains(t, workbook, `name="Orders &amp; items"`)
+	readPart(t, buf.Bytes(), "[Content_Types].xml")
+}
+
+func TestColumnName(t *testing.T) {
+	assert.Equal(t, "A", ColumnName(0))
+	assert.Equal(t, "Z", ColumnName(25))
+	assert.Equal(t, "AA", ColumnName(26))
+	assert.Equal(t, "AZ", ColumnName(51))
+	assert.Equal(t, "BA", ColumnName(52))
+}
@@ -0,0 +1,9 @@
+DROP INDEX IF EXISTS idx_webhook_deliveries_subscription;
+DROP INDEX IF EXISTS idx_webhook_deliveries_due;
+DROP TABLE IF EXISTS webhook_deliveries;
+
+DROP INDEX IF EXISTS idx_webhook_outbox_undispatched;
+DROP TABLE IF EXISTS webhook_outbox;
+
+DROP INDEX IF EXISTS idx_webhook_subscriptions_tenant_active;
+DROP TABLE IF EXISTS webhook_subscriptions;
@@ -0,0 +1,65 @@
+CREATE TABLE webhook_subscriptions (
+    id BIGSERIAL PRIMARY KEY,
+    tenant_id BIGINT NOT NULL,
+    url TEXT NOT NULL,
+    secret VARCHAR(128) NOT NULL,
+    event_types TEXT[] NOT NULL,
+    active BOOLEAN NOT NULL DEFAULT TRUE,
+    created_by_user_id BIGINT NULL,
+    created_on TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_on TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    CONSTRAINT fk_webhook_subscriptions_tenant
+        FOREIGN KEY (tenant_id) REFERENCES tenants(id) ON DELETE CASCADE,
+    CONSTRAINT fk_webhook_subscriptions_created_by_user
+        FOREIGN KEY (created_by_user_id) REFERENCES users(id_user) ON DELETE SET NULL
+);
+
+CREATE INDEX idx_webhook_subscriptions_tenant_active
+    ON webhook_subscriptions (tenant_id, active);
+
+-- Transactional outbox: rows are inserted in the same transaction as the order/product change
+-- and fanned out to webhook_deliveries by the dispatcher worker.
+CREATE TABLE webhook_outbox (
+    id BIGSERIAL PRIMARY KEY,
+    tenant_id BIGINT NOT NULL,
+    event_type VARCHAR(64) NOT NULL,
+    payload JSONB NOT NULL,
+    created_on TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    dispatched_on TIMESTAMPTZ NULL,
+    CONSTRAINT fk_webhook_outbox_tenant
+        FOREIGN KEY (tenant_id) REFERENCES tenants(id) ON DELETE CASCADE
+);
+
+CREATE INDEX idx_webhook_outbox_undispatched
+    ON webhook_outbox (id) WHERE dispatched_on IS NULL;
+
+CREATE TABLE webhook_deliveries (
+    id BIGSERIAL PRIMARY KEY,
+    tenant_id BIGINT NOT NULL,
+    webhook_subscription_id BIGINT NOT NULL,
+    webhook_event_id BIGINT NOT NULL,
+    status VARCHAR(16) NOT NULL DEFAULT 'pending',
+    attempts INT NOT NULL DEFAULT 0,
+    next_attempt_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    last_status_code INT NULL,
+    last_error TEXT NULL,
+    delivered_on TIMESTAMPTZ NULL,
+    created_on TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_on TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    CONSTRAINT chk_webhook_deliveries_status
+        CHECK (status IN ('pending', 'succeeded', 'failed')),
+    CONSTRAINT ux_webhook_deliveries_subscription_event
+        UNIQUE (webhook_subscription_id, webhook_event_id),
+    CONSTRAINT fk_webhook_deliveries_tenant
+        FOREIGN KEY (tenant_id) REFERENCES tenants(id) ON DELETE CASCADE,
+    CONSTRAINT fk_webhook_deliveries_subscription
+        FOREIGN KEY (webhook_subscription_id) REFERENCES webhook_subscriptions(id) ON DELETE CASCADE,
+    CONSTRAINT fk_webhook_deliveries_event
+        FOREIGN KEY (webhook_event_id) REFERENCES webhook_outbox(id) ON DELETE CASCADE
+);
+
+CREATE INDEX idx_webhook_deliveries_due
+    ON webhook_deliveries (next_attempt_at) WHERE status = 'pending';
+
+CREATE INDEX idx_webhook_deliveries_subscription
+    ON webhook_deliveries (tenant_id, webhook_subscription_id, id DESC);
@@ -0,0 +1,29 @@
+CREATE TABLE payments (
+    id BIGSERIAL PRIMARY KEY,
+    tenant_id BIGINT NOT NULL,
+    id_order BIGINT NOT NULL,
+    provider VARCHAR(32) NOT NULL,
+    provider_payment_id VARCHAR(255) NOT NULL,
+    status VARCHAR(16) NOT NULL DEFAULT 'pending',
+    amount NUMERIC(10, 2) NOT NULL,
+    checkout_url TEXT NOT NULL,
+    expires_at TIMESTAMPTZ NOT NULL,
+    paid_on TIMESTAMPTZ NULL,
+    created_on TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_on TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    CONSTRAINT chk_payments_status
+        CHECK (status IN ('pending', 'succeeded', 'failed', 'cancelled')),
+    CONSTRAINT ux_payments_provider_payment_id
+        UNIQUE (provider, provider_payment_id),
+    CONSTRAINT fk_payments_tenant
+        FOREIGN KEY (tenant_id) REFERENCES tenants(id) ON DELETE CASCADE,
+    CONSTRAINT fk_payments_order
+        FOREIGN KEY (id_order) REFERENCES orders(id_order) ON DELETE CASCADE
+);
+
+CREATE INDEX idx_payments_tenant_order
+    ON payments (tenant_id, id_order);
+
+-- Speeds up the ghost-order cron check for in-flight payments.
+CREATE INDEX idx_payments_in_flight
+    ON payments (id_order, expires_at) WHERE status = 'pending';
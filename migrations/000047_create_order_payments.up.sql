@@ -0,0 +1,29 @@
+-- Ledger of money received for (or refunded from) an order. orders.paid is kept in sync:
+-- it becomes true once the net amount covers total_price.
+CREATE TABLE order_payments (
+    id BIGSERIAL PRIMARY KEY,
+    tenant_id BIGINT NOT NULL,
+    id_order BIGINT NOT NULL,
+    amount NUMERIC(10, 2) NOT NULL,
+    method VARCHAR(16) NOT NULL,
+    reference VARCHAR(255) NULL,
+    is_refund BOOLEAN NOT NULL DEFAULT FALSE,
+    recorded_by_user_id BIGINT NULL,
+    id_payment BIGINT NULL,
+    created_on TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    CONSTRAINT chk_order_payments_amount_positive
+        CHECK (amount > 0),
+    CONSTRAINT chk_order_payments_method
+        CHECK (method IN ('cash', 'transfer', 'card', 'online', 'other')),
+    CONSTRAINT fk_order_payments_tenant
+        FOREIGN KEY (tenant_id) REFERENCES tenants(id) ON DELETE CASCADE,
+    CONSTRAINT fk_order_payments_order
+        FOREIGN KEY (id_order) REFERENCES orders(id_order) ON DELETE CASCADE,
+    CONSTRAINT fk_order_payments_recorded_by_user
+        FOREIGN KEY (recorded_by_user_id) REFERENCES users(id_user) ON DELETE SET NULL,
+    CONSTRAINT fk_order_payments_payment
+        FOREIGN KEY (id_payment) REFERENCES payments(id) ON DELETE SET NULL
+);
+
+CREATE INDEX idx_order_payments_tenant_order
+    ON order_payments (tenant_id, id_order, id);
@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS ux_orders_tenant_tracking_token_hash;
+ALTER TABLE orders DROP COLUMN IF EXISTS tracking_token_hash;
@@ -0,0 +1,6 @@
+-- SHA-256 of the public tracking token returned once on order creation; the plain token is never stored.
+ALTER TABLE orders ADD COLUMN tracking_token_hash VARCHAR(64) NULL;
+
+CREATE UNIQUE INDEX ux_orders_tenant_tracking_token_hash
+    ON orders (tenant_id, tracking_token_hash)
+    WHERE tracking_token_hash IS NOT NULL;
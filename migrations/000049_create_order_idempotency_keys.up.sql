@@ -0,0 +1,16 @@
+-- Idempotency-Key values sent with public order creation. A retried request with the same key
+-- returns the order created the first time instead of creating (and reserving stock for) another one.
+CREATE TABLE order_idempotency_keys (
+    id BIGSERIAL PRIMARY KEY,
+    tenant_id BIGINT NOT NULL,
+    idempotency_key VARCHAR(255) NOT NULL,
+    request_hash CHAR(64) NOT NULL,
+    id_order BIGINT NULL,
+    created_on TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    CONSTRAINT ux_order_idempotency_keys_tenant_key
+        UNIQUE (tenant_id, idempotency_key),
+    CONSTRAINT fk_order_idempotency_keys_tenant
+        FOREIGN KEY (tenant_id) REFERENCES tenants(id) ON DELETE CASCADE,
+    CONSTRAINT fk_order_idempotency_keys_order
+        FOREIGN KEY (id_order) REFERENCES orders(id_order) ON DELETE CASCADE
+);
@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS tenant_notification_settings;
+DROP TABLE IF EXISTS email_outbox;
@@ -0,0 +1,41 @@
+-- Queue of order emails. Rows are written in the same transaction as the order change and
+-- sent by a background worker, so requests never wait on the email provider.
+CREATE TABLE email_outbox (
+    id BIGSERIAL PRIMARY KEY,
+    tenant_id BIGINT NOT NULL,
+    id_order BIGINT NOT NULL,
+    kind VARCHAR(32) NOT NULL,
+    to_email VARCHAR(255) NOT NULL,
+    status VARCHAR(16) NOT NULL DEFAULT 'pending',
+    attempts INT NOT NULL DEFAULT 0,
+    next_attempt_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    last_error TEXT NULL,
+    sent_on TIMESTAMPTZ NULL,
+    created_on TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_on TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    CONSTRAINT chk_email_outbox_status
+        CHECK (status IN ('pending', 'sent', 'failed')),
+    CONSTRAINT chk_email_outbox_kind
+        CHECK (kind IN ('order_confirmation', 'order_ready', 'order_delivered', 'order_cancelled', 'order_expired', 'admin_new_order')),
+    CONSTRAINT fk_email_outbox_tenant
+        FOREIGN KEY (tenant_id) REFERENCES tenants(id) ON DELETE CASCADE,
+    CONSTRAINT fk_email_outbox_order
+        FOREIGN KEY (id_order) REFERENCES orders(id_order) ON DELETE CASCADE
+);
+
+CREATE INDEX idx_email_outbox_pending_due
+    ON email_outbox (next_attempt_at, id)
+    WHERE status = 'pending';
+
+-- Per-tenant switches; a missing row means the notification is enabled.
+CREATE TABLE tenant_notification_settings (
+    tenant_id BIGINT NOT NULL,
+    kind VARCHAR(32) NOT NULL,
+    enabled BOOLEAN NOT NULL,
+    updated_on TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (tenant_id, kind),
+    CONSTRAINT chk_tenant_notification_settings_kind
+        CHECK (kind IN ('order_confirmation', 'order_ready', 'order_delivered', 'order_cancelled', 'order_expired', 'admin_new_order')),
+    CONSTRAINT fk_tenant_notification_settings_tenant
+        FOREIGN KEY (tenant_id) REFERENCES tenants(id) ON DELETE CASCADE
+);
@@ -0,0 +1,4 @@
+DROP INDEX IF EXISTS idx_orders_tenant_delivery_date;
+DROP TABLE IF EXISTS tenant_delivery_day_locks;
+DROP TABLE IF EXISTS tenant_delivery_days;
+DROP TABLE IF EXISTS tenant_delivery_capacity;
@@ -0,0 +1,45 @@
+-- Default delivery limits per tenant. NULL limits mean unlimited.
+CREATE TABLE tenant_delivery_capacity (
+    tenant_id BIGINT PRIMARY KEY,
+    max_orders_per_day INT NULL,
+    max_units_per_day INT NULL,
+    min_lead_time_hours INT NOT NULL DEFAULT 0,
+    updated_on TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    CONSTRAINT chk_tenant_delivery_capacity_limits
+        CHECK ((max_orders_per_day IS NULL OR max_orders_per_day >= 0)
+            AND (max_units_per_day IS NULL OR max_units_per_day >= 0)
+            AND min_lead_time_hours >= 0),
+    CONSTRAINT fk_tenant_delivery_capacity_tenant
+        FOREIGN KEY (tenant_id) REFERENCES tenants(id) ON DELETE CASCADE
+);
+
+-- Per-date overrides of the default limits; closed = blackout date.
+CREATE TABLE tenant_delivery_days (
+    tenant_id BIGINT NOT NULL,
+    delivery_date DATE NOT NULL,
+    closed BOOLEAN NOT NULL DEFAULT FALSE,
+    max_orders INT NULL,
+    max_units INT NULL,
+    note VARCHAR(255) NULL,
+    created_on TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_on TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (tenant_id, delivery_date),
+    CONSTRAINT chk_tenant_delivery_days_limits
+        CHECK ((max_orders IS NULL OR max_orders >= 0) AND (max_units IS NULL OR max_units >= 0)),
+    CONSTRAINT fk_tenant_delivery_days_tenant
+        FOREIGN KEY (tenant_id) REFERENCES tenants(id) ON DELETE CASCADE
+);
+
+-- One row per booked delivery day, locked by order creation so concurrent orders for the
+-- same day are counted one after another and cannot overbook it.
+CREATE TABLE tenant_delivery_day_locks (
+    tenant_id BIGINT NOT NULL,
+    delivery_date DATE NOT NULL,
+    last_booked_on TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (tenant_id, delivery_date),
+    CONSTRAINT fk_tenant_delivery_day_locks_tenant
+        FOREIGN KEY (tenant_id) REFERENCES tenants(id) ON DELETE CASCADE
+);
+
+CREATE INDEX idx_orders_tenant_delivery_date
+    ON orders (tenant_id, delivery_date);
@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS idx_orders_history_tenant_order_closed;
+DROP INDEX IF EXISTS idx_orders_tenant_created_on;
@@ -0,0 +1,10 @@
+-- Analytics: revenue and top products scan the orders of a tenant by created_on range.
+CREATE INDEX idx_orders_tenant_created_on
+    ON orders (tenant_id, created_on)
+    INCLUDE (status, paid, total_price);
+
+-- Analytics: who closed an order (admin, customer or the expiry worker) is read from its
+-- latest cancelled/expired history row.
+CREATE INDEX idx_orders_history_tenant_order_closed
+    ON orders_history (tenant_id, id_order, modified_on DESC)
+    WHERE status IN ('cancelled', 'expired');
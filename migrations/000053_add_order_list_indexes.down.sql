@@ -0,0 +1,9 @@
+CREATE INDEX IF NOT EXISTS idx_orders_tenant_delivery_date
+    ON orders (tenant_id, delivery_date);
+
+CREATE INDEX IF NOT EXISTS idx_orders_tenant_created_on
+    ON orders (tenant_id, created_on)
+    INCLUDE (status, paid, total_price);
+
+DROP INDEX IF EXISTS idx_orders_tenant_delivery_date_id;
+DROP INDEX IF EXISTS idx_orders_tenant_created_on_id;
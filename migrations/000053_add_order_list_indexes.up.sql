@@ -0,0 +1,13 @@
+-- Order list keysets: created_on sort pages on (created_on, id_order), delivery_date sort on
+-- (delivery_date, id_order). The paid and total filters are read from the included columns.
+-- They replace the two-column indexes of 000051 and 000052, which are prefixes of these.
+CREATE INDEX idx_orders_tenant_created_on_id
+    ON orders (tenant_id, created_on, id_order)
+    INCLUDE (status, paid, total_price);
+
+CREATE INDEX idx_orders_tenant_delivery_date_id
+    ON orders (tenant_id, delivery_date, id_order)
+    INCLUDE (status, paid, total_price);
+
+DROP INDEX IF EXISTS idx_orders_tenant_created_on;
+DROP INDEX IF EXISTS idx_orders_tenant_delivery_date;
@@ -0,0 +1,61 @@
+-- Recurring order templates. weekdays uses ISO numbering (1 = Monday ... 7 = Sunday).
+CREATE TABLE standing_orders (
+    id_standing_order BIGSERIAL PRIMARY KEY,
+    tenant_id BIGINT NOT NULL,
+    name VARCHAR(255) NOT NULL,
+    email VARCHAR(255) NOT NULL,
+    phone VARCHAR(50) NOT NULL DEFAULT '',
+    delivery_direction TEXT NOT NULL,
+    note TEXT NOT NULL DEFAULT '',
+    weekdays SMALLINT[] NOT NULL,
+    start_date DATE NOT NULL,
+    end_date DATE NULL,
+    active BOOLEAN NOT NULL DEFAULT TRUE,
+    created_on TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_on TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    CONSTRAINT chk_standing_orders_weekdays
+        CHECK (cardinality(weekdays) > 0 AND weekdays <@ ARRAY[1, 2, 3, 4, 5, 6, 7]::SMALLINT[]),
+    CONSTRAINT chk_standing_orders_dates
+        CHECK (end_date IS NULL OR end_date >= start_date),
+    CONSTRAINT fk_standing_orders_tenant
+        FOREIGN KEY (tenant_id) REFERENCES tenants(id) ON DELETE CASCADE
+);
+
+CREATE INDEX idx_standing_orders_tenant_active
+    ON standing_orders (tenant_id, start_date)
+    WHERE active;
+
+CREATE TABLE standing_order_items (
+    id_standing_order BIGINT NOT NULL,
+    tenant_id BIGINT NOT NULL,
+    id_product BIGINT NOT NULL,
+    quantity INT NOT NULL,
+    PRIMARY KEY (id_standing_order, id_product),
+    CONSTRAINT chk_standing_order_items_quantity
+        CHECK (quantity > 0),
+    CONSTRAINT fk_standing_order_items_standing_order
+        FOREIGN KEY (id_standing_order) REFERENCES standing_orders(id_standing_order) ON DELETE CASCADE,
+    CONSTRAINT fk_standing_order_items_product
+        FOREIGN KEY (id_product) REFERENCES products(id_product) ON DELETE CASCADE
+);
+
+-- One row per delivery date the worker has handled: the order it created, or why it was skipped
+-- or failed. Failed dates are retried on later runs up to the worker's attempt limit.
+CREATE TABLE standing_order_occurrences (
+    id_standing_order BIGINT NOT NULL,
+    delivery_date DATE NOT NULL,
+    tenant_id BIGINT NOT NULL,
+    status VARCHAR(16) NOT NULL,
+    id_order BIGINT NULL,
+    attempts INT NOT NULL DEFAULT 1,
+    last_error TEXT NULL,
+    created_on TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_on TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (id_standing_order, delivery_date),
+    CONSTRAINT chk_standing_order_occurrences_status
+        CHECK (status IN ('created', 'skipped', 'failed')),
+    CONSTRAINT fk_standing_order_occurrences_standing_order
+        FOREIGN KEY (id_standing_order) REFERENCES standing_orders(id_standing_order) ON DELETE CASCADE,
+    CONSTRAINT fk_standing_order_occurrences_order
+        FOREIGN KEY (id_order) REFERENCES orders(id_order) ON DELETE SET NULL
+);
@@ -0,0 +1,13 @@
+ALTER TABLE orders_history
+    DROP COLUMN IF EXISTS promotion_code,
+    DROP COLUMN IF EXISTS discount_amount;
+
+ALTER TABLE orders
+    DROP CONSTRAINT IF EXISTS fk_orders_promotion,
+    DROP COLUMN IF EXISTS id_promotion,
+    DROP COLUMN IF EXISTS promotion_code,
+    DROP COLUMN IF EXISTS discount_amount;
+
+DROP TABLE IF EXISTS promotion_redemptions;
+DROP TABLE IF EXISTS promotion_products;
+DROP TABLE IF EXISTS promotions;
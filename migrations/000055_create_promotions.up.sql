@@ -0,0 +1,79 @@
+-- Discount codes. Codes are matched case-insensitively within a tenant. NULL limits mean unlimited;
+-- NULL starts_at/ends_at mean no bound on that side.
+CREATE TABLE promotions (
+    id_promotion BIGSERIAL PRIMARY KEY,
+    tenant_id BIGINT NOT NULL,
+    code VARCHAR(64) NOT NULL,
+    description TEXT NOT NULL DEFAULT '',
+    discount_type VARCHAR(16) NOT NULL,
+    discount_value DECIMAL(10,2) NOT NULL,
+    scope VARCHAR(16) NOT NULL DEFAULT 'order',
+    min_order_total DECIMAL(10,2) NULL,
+    starts_at TIMESTAMPTZ NULL,
+    ends_at TIMESTAMPTZ NULL,
+    max_uses INT NULL,
+    max_uses_per_customer INT NULL,
+    active BOOLEAN NOT NULL DEFAULT TRUE,
+    created_on TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_on TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    CONSTRAINT chk_promotions_discount
+        CHECK (discount_type IN ('percentage', 'fixed')
+            AND discount_value > 0
+            AND (discount_type <> 'percentage' OR discount_value <= 100)),
+    CONSTRAINT chk_promotions_scope
+        CHECK (scope IN ('order', 'products')),
+    CONSTRAINT chk_promotions_limits
+        CHECK ((min_order_total IS NULL OR min_order_total >= 0)
+            AND (max_uses IS NULL OR max_uses > 0)
+            AND (max_uses_per_customer IS NULL OR max_uses_per_customer > 0)),
+    CONSTRAINT chk_promotions_window
+        CHECK (starts_at IS NULL OR ends_at IS NULL OR ends_at > starts_at),
+    CONSTRAINT fk_promotions_tenant
+        FOREIGN KEY (tenant_id) REFERENCES tenants(id) ON DELETE CASCADE
+);
+
+CREATE UNIQUE INDEX ux_promotions_tenant_code ON promotions (tenant_id, UPPER(code));
+
+-- Products discounted by a promotion with scope = 'products'.
+CREATE TABLE promotion_products (
+    id_promotion BIGINT NOT NULL,
+    tenant_id BIGINT NOT NULL,
+    id_product BIGINT NOT NULL,
+    PRIMARY KEY (id_promotion, id_product),
+    CONSTRAINT fk_promotion_products_promotion
+        FOREIGN KEY (id_promotion) REFERENCES promotions(id_promotion) ON DELETE CASCADE,
+    CONSTRAINT fk_promotion_products_product
+        FOREIGN KEY (id_product) REFERENCES products(id_product) ON DELETE CASCADE
+);
+
+-- One row per order that used a code. Usage limits count the redemptions whose order is still live
+-- (not cancelled, expired or deleted).
+CREATE TABLE promotion_redemptions (
+    id_promotion_redemption BIGSERIAL PRIMARY KEY,
+    tenant_id BIGINT NOT NULL,
+    id_promotion BIGINT NOT NULL,
+    id_order BIGINT NOT NULL,
+    email VARCHAR(255) NOT NULL,
+    discount_amount DECIMAL(10,2) NOT NULL,
+    created_on TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    CONSTRAINT ux_promotion_redemptions_order UNIQUE (id_order),
+    CONSTRAINT fk_promotion_redemptions_promotion
+        FOREIGN KEY (id_promotion) REFERENCES promotions(id_promotion) ON DELETE CASCADE,
+    CONSTRAINT fk_promotion_redemptions_order
+        FOREIGN KEY (id_order) REFERENCES orders(id_order) ON DELETE CASCADE
+);
+
+CREATE INDEX idx_promotion_redemptions_promotion_email
+    ON promotion_redemptions (id_promotion, LOWER(email));
+
+-- total_price is what the customer pays: the sum of the lines minus discount_amount.
+ALTER TABLE orders
+    ADD COLUMN discount_amount DECIMAL(10,2) NOT NULL DEFAULT 0,
+    ADD COLUMN promotion_code VARCHAR(64) NULL,
+    ADD COLUMN id_promotion BIGINT NULL,
+    ADD CONSTRAINT fk_orders_promotion
+        FOREIGN KEY (id_promotion) REFERENCES promotions(id_promotion) ON DELETE SET NULL;
+
+ALTER TABLE orders_history
+    ADD COLUMN discount_amount DECIMAL(10,2) NOT NULL DEFAULT 0,
+    ADD COLUMN promotion_code VARCHAR(64) NULL;
@@ -0,0 +1,11 @@
+ALTER TABLE orders
+    DROP CONSTRAINT IF EXISTS fk_orders_delivery_zone,
+    DROP COLUMN IF EXISTS id_delivery_zone,
+    DROP COLUMN IF EXISTS delivery_fee,
+    DROP COLUMN IF EXISTS tax_amount,
+    DROP COLUMN IF EXISTS tax_inclusive,
+    DROP COLUMN IF EXISTS tax_rate,
+    DROP COLUMN IF EXISTS subtotal;
+
+DROP TABLE IF EXISTS delivery_zones;
+DROP TABLE IF EXISTS tenant_pricing;
@@ -0,0 +1,51 @@
+-- Tax and delivery fee configuration per tenant. A tenant without a row charges no tax and no
+-- delivery fee. tax_inclusive means catalog prices already include the tax.
+CREATE TABLE tenant_pricing (
+    tenant_id BIGINT PRIMARY KEY,
+    tax_rate DECIMAL(5,2) NOT NULL DEFAULT 0,
+    tax_inclusive BOOLEAN NOT NULL DEFAULT FALSE,
+    delivery_fee DECIMAL(10,2) NOT NULL DEFAULT 0,
+    free_delivery_above DECIMAL(10,2) NULL,
+    updated_on TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    CONSTRAINT chk_tenant_pricing_values
+        CHECK (tax_rate >= 0 AND tax_rate <= 100
+            AND delivery_fee >= 0
+            AND (free_delivery_above IS NULL OR free_delivery_above >= 0)),
+    CONSTRAINT fk_tenant_pricing_tenant
+        FOREIGN KEY (tenant_id) REFERENCES tenants(id) ON DELETE CASCADE
+);
+
+-- Delivery zones replace the flat delivery fee (and, when set, its free delivery threshold) for
+-- the orders that choose them.
+CREATE TABLE delivery_zones (
+    id_delivery_zone BIGSERIAL PRIMARY KEY,
+    tenant_id BIGINT NOT NULL,
+    name VARCHAR(100) NOT NULL,
+    fee DECIMAL(10,2) NOT NULL,
+    free_delivery_above DECIMAL(10,2) NULL,
+    active BOOLEAN NOT NULL DEFAULT TRUE,
+    created_on TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_on TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    CONSTRAINT chk_delivery_zones_fee
+        CHECK (fee >= 0 AND (free_delivery_above IS NULL OR free_delivery_above >= 0)),
+    CONSTRAINT fk_delivery_zones_tenant
+        FOREIGN KEY (tenant_id) REFERENCES tenants(id) ON DELETE CASCADE
+);
+
+CREATE UNIQUE INDEX ux_delivery_zones_tenant_name ON delivery_zones (tenant_id, LOWER(name));
+
+-- Totals breakdown of each order: total_price = subtotal - discount_amount + delivery_fee, plus
+-- tax_amount when the tax is not included in the prices. tax_rate and tax_inclusive are the
+-- configuration the order was priced with.
+ALTER TABLE orders
+    ADD COLUMN subtotal DECIMAL(10,2) NOT NULL DEFAULT 0,
+    ADD COLUMN tax_rate DECIMAL(5,2) NOT NULL DEFAULT 0,
+    ADD COLUMN tax_inclusive BOOLEAN NOT NULL DEFAULT FALSE,
+    ADD COLUMN tax_amount DECIMAL(10,2) NOT NULL DEFAULT 0,
+    ADD COLUMN delivery_fee DECIMAL(10,2) NOT NULL DEFAULT 0,
+    ADD COLUMN id_delivery_zone BIGINT NULL,
+    ADD CONSTRAINT fk_orders_delivery_zone
+        FOREIGN KEY (id_delivery_zone) REFERENCES delivery_zones(id_delivery_zone) ON DELETE SET NULL;
+
+-- Orders placed before the breakdown only had lines and a discount.
+UPDATE orders SET subtotal = total_price + discount_amount;
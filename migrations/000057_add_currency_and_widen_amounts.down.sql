@@ -0,0 +1,31 @@
+-- Fails if an amount no longer fits NUMERIC(10,2).
+ALTER TABLE delivery_zones
+    ALTER COLUMN fee TYPE DECIMAL(10,2),
+    ALTER COLUMN free_delivery_above TYPE DECIMAL(10,2);
+ALTER TABLE tenant_pricing
+    ALTER COLUMN delivery_fee TYPE DECIMAL(10,2),
+    ALTER COLUMN free_delivery_above TYPE DECIMAL(10,2);
+ALTER TABLE promotion_redemptions ALTER COLUMN discount_amount TYPE DECIMAL(10,2);
+ALTER TABLE promotions
+    ALTER COLUMN discount_value TYPE DECIMAL(10,2),
+    ALTER COLUMN min_order_total TYPE DECIMAL(10,2);
+ALTER TABLE payments ALTER COLUMN amount TYPE NUMERIC(10,2);
+ALTER TABLE order_payments ALTER COLUMN amount TYPE NUMERIC(10,2);
+ALTER TABLE order_items ALTER COLUMN unit_price_snapshot TYPE DECIMAL(10,2);
+ALTER TABLE orders_history
+    ALTER COLUMN total_price TYPE DECIMAL(10,2),
+    ALTER COLUMN discount_amount TYPE DECIMAL(10,2);
+ALTER TABLE orders
+    ALTER COLUMN total_price TYPE DECIMAL(10,2),
+    ALTER COLUMN discount_amount TYPE DECIMAL(10,2),
+    ALTER COLUMN subtotal TYPE DECIMAL(10,2),
+    ALTER COLUMN tax_amount TYPE DECIMAL(10,2),
+    ALTER COLUMN delivery_fee TYPE DECIMAL(10,2);
+ALTER TABLE products_history ALTER COLUMN price TYPE DECIMAL(10,2);
+ALTER TABLE products ALTER COLUMN price TYPE DECIMAL(10,2);
+
+ALTER TABLE orders DROP COLUMN IF EXISTS currency;
+
+ALTER TABLE tenants
+    DROP CONSTRAINT IF EXISTS chk_tenants_currency,
+    DROP COLUMN IF EXISTS currency;
@@ -0,0 +1,35 @@
+-- Currency of each tenant (ISO 4217) and of each order, which keeps the currency it was placed in.
+ALTER TABLE tenants
+    ADD COLUMN currency CHAR(3) NOT NULL DEFAULT 'USD',
+    ADD CONSTRAINT chk_tenants_currency CHECK (currency ~ '^[A-Z]{3}$');
+
+ALTER TABLE orders
+    ADD COLUMN currency CHAR(3) NOT NULL DEFAULT 'USD';
+
+-- Amounts stay exact decimals with two places, read and written by the application as integer
+-- minor units. Widen them so currencies with large nominal amounts fit; raising the precision of
+-- a NUMERIC column keeps the stored values and does not rewrite the table.
+ALTER TABLE products ALTER COLUMN price TYPE NUMERIC(12,2);
+ALTER TABLE products_history ALTER COLUMN price TYPE NUMERIC(12,2);
+ALTER TABLE orders
+    ALTER COLUMN total_price TYPE NUMERIC(12,2),
+    ALTER COLUMN discount_amount TYPE NUMERIC(12,2),
+    ALTER COLUMN subtotal TYPE NUMERIC(12,2),
+    ALTER COLUMN tax_amount TYPE NUMERIC(12,2),
+    ALTER COLUMN delivery_fee TYPE NUMERIC(12,2);
+ALTER TABLE orders_history
+    ALTER COLUMN total_price TYPE NUMERIC(12,2),
+    ALTER COLUMN discount_amount TYPE NUMERIC(12,2);
+ALTER TABLE order_items ALTER COLUMN unit_price_snapshot TYPE NUMERIC(12,2);
+ALTER TABLE order_payments ALTER COLUMN amount TYPE NUMERIC(12,2);
+ALTER TABLE payments ALTER COLUMN amount TYPE NUMERIC(12,2);
+ALTER TABLE promotions
+    ALTER COLUMN discount_value TYPE NUMERIC(12,2),
+    ALTER COLUMN min_order_total TYPE NUMERIC(12,2);
+ALTER TABLE promotion_redemptions ALTER COLUMN discount_amount TYPE NUMERIC(12,2);
+ALTER TABLE tenant_pricing
+    ALTER COLUMN delivery_fee TYPE NUMERIC(12,2),
+    ALTER COLUMN free_delivery_above TYPE NUMERIC(12,2);
+ALTER TABLE delivery_zones
+    ALTER COLUMN fee TYPE NUMERIC(12,2),
+    ALTER COLUMN free_delivery_above TYPE NUMERIC(12,2);
@@ -0,0 +1,7 @@
+ALTER TABLE orders
+    DROP CONSTRAINT IF EXISTS fk_orders_pickup_location,
+    DROP CONSTRAINT IF EXISTS chk_orders_fulfillment_type,
+    DROP COLUMN IF EXISTS id_pickup_location,
+    DROP COLUMN IF EXISTS fulfillment_type;
+
+DROP TABLE IF EXISTS pickup_locations;
@@ -0,0 +1,26 @@
+-- Shop counters and other places where customers collect pickup orders. opening_hours is a list
+-- of {"weekday": 1-7 (ISO, 1 = Monday), "opens": "HH:MM", "closes": "HH:MM"}; a location takes
+-- pickups on the weekdays it lists.
+CREATE TABLE pickup_locations (
+    id_pickup_location BIGSERIAL PRIMARY KEY,
+    tenant_id BIGINT NOT NULL,
+    name VARCHAR(100) NOT NULL,
+    address TEXT NOT NULL,
+    opening_hours JSONB NOT NULL DEFAULT '[]',
+    active BOOLEAN NOT NULL DEFAULT TRUE,
+    created_on TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_on TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    CONSTRAINT fk_pickup_locations_tenant
+        FOREIGN KEY (tenant_id) REFERENCES tenants(id) ON DELETE CASCADE
+);
+
+CREATE UNIQUE INDEX ux_pickup_locations_tenant_name ON pickup_locations (tenant_id, LOWER(name));
+
+-- Every existing order was a delivery. Pickup orders have no delivery address (stored as '')
+-- and name the location they are collected at.
+ALTER TABLE orders
+    ADD COLUMN fulfillment_type VARCHAR(10) NOT NULL DEFAULT 'delivery',
+    ADD COLUMN id_pickup_location BIGINT NULL,
+    ADD CONSTRAINT chk_orders_fulfillment_type CHECK (fulfillment_type IN ('delivery', 'pickup')),
+    ADD CONSTRAINT fk_orders_pickup_location
+        FOREIGN KEY (id_pickup_location) REFERENCES pickup_locations(id_pickup_location) ON DELETE SET NULL;
@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS idx_orders_history_tenant_order_history;
+DROP INDEX IF EXISTS idx_orders_history_tenant_id_history;
@@ -0,0 +1,7 @@
+-- Order stream: GET /auth/orders/stream tails the history of a tenant by id_order_history and
+-- compares each row with the previous one of the same order.
+CREATE INDEX idx_orders_history_tenant_id_history
+    ON orders_history (tenant_id, id_order_history);
+
+CREATE INDEX idx_orders_history_tenant_order_history
+    ON orders_history (tenant_id, id_order, id_order_history);
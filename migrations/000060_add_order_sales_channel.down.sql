@@ -0,0 +1,3 @@
+ALTER TABLE orders
+    DROP CONSTRAINT IF EXISTS chk_orders_sales_channel,
+    DROP COLUMN IF EXISTS sales_channel;
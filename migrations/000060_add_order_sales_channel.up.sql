@@ -0,0 +1,4 @@
+-- Where an order was taken. Every existing order came from the storefront.
+ALTER TABLE orders
+    ADD COLUMN sales_channel VARCHAR(16) NOT NULL DEFAULT 'web',
+    ADD CONSTRAINT chk_orders_sales_channel CHECK (sales_channel IN ('web', 'phone', 'whatsapp', 'counter'));
@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS idx_orders_history_tenant_user;
+DROP INDEX IF EXISTS idx_users_tenant_role_id;
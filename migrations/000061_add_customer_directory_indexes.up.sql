@@ -0,0 +1,8 @@
+-- Customer directory: GET /auth/customers pages the live client accounts of a tenant by id, and a
+-- merge moves the order history of the duplicate accounts.
+CREATE INDEX idx_users_tenant_role_id
+    ON users (tenant_id, id_role, id_user)
+    WHERE deleted_at IS NULL;
+
+CREATE INDEX idx_orders_history_tenant_user
+    ON orders_history (tenant_id, id_user);
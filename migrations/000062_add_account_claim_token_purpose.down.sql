@@ -0,0 +1,23 @@
+DELETE FROM auth_action_tokens_history WHERE purpose = 'account_claim';
+DELETE FROM auth_action_tokens WHERE purpose = 'account_claim';
+
+ALTER TABLE auth_action_tokens_history
+    DROP CONSTRAINT chk_auth_action_tokens_history_action,
+    ADD CONSTRAINT chk_auth_action_tokens_history_action
+        CHECK (
+            action IN (
+                'invite_created',
+                'invite_revoked',
+                'invite_accepted',
+                'password_reset_issued',
+                'password_reset_completed'
+            )
+        ),
+    DROP CONSTRAINT chk_auth_action_tokens_history_purpose,
+    ADD CONSTRAINT chk_auth_action_tokens_history_purpose
+        CHECK (purpose IN ('invite', 'password_reset'));
+
+ALTER TABLE auth_action_tokens
+    DROP CONSTRAINT chk_auth_action_tokens_purpose,
+    ADD CONSTRAINT chk_auth_action_tokens_purpose
+        CHECK (purpose IN ('invite', 'password_reset'));
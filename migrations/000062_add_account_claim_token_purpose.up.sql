@@ -0,0 +1,24 @@
+-- Account claim: customers created by their first order have no password and claim the account
+-- through an emailed one-time token (purpose 'account_claim').
+ALTER TABLE auth_action_tokens
+    DROP CONSTRAINT chk_auth_action_tokens_purpose,
+    ADD CONSTRAINT chk_auth_action_tokens_purpose
+        CHECK (purpose IN ('invite', 'password_reset', 'account_claim'));
+
+ALTER TABLE auth_action_tokens_history
+    DROP CONSTRAINT chk_auth_action_tokens_history_purpose,
+    ADD CONSTRAINT chk_auth_action_tokens_history_purpose
+        CHECK (purpose IN ('invite', 'password_reset', 'account_claim')),
+    DROP CONSTRAINT chk_auth_action_tokens_history_action,
+    ADD CONSTRAINT chk_auth_action_tokens_history_action
+        CHECK (
+            action IN (
+                'invite_created',
+                'invite_revoked',
+                'invite_accepted',
+                'password_reset_issued',
+                'password_reset_completed',
+                'account_claim_issued',
+                'account_claim_completed'
+            )
+        );
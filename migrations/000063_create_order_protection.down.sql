@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS idx_orders_tenant_user_pending_unpaid;
+DROP TABLE IF EXISTS order_blocklist;
+DROP TABLE IF EXISTS tenant_order_protection;
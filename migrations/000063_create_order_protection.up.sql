@@ -0,0 +1,33 @@
+-- Abuse protection of storefront orders per tenant. A tenant without a row gets the column
+-- defaults; 0 disables a limit.
+CREATE TABLE tenant_order_protection (
+    tenant_id BIGINT PRIMARY KEY,
+    max_pending_unpaid_orders INT NOT NULL DEFAULT 3,
+    max_quantity_per_line INT NOT NULL DEFAULT 100,
+    updated_on TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    CONSTRAINT chk_tenant_order_protection_values
+        CHECK (max_pending_unpaid_orders >= 0 AND max_quantity_per_line >= 0),
+    CONSTRAINT fk_tenant_order_protection_tenant
+        FOREIGN KEY (tenant_id) REFERENCES tenants(id) ON DELETE CASCADE
+);
+
+-- Emails and phones the storefront rejects orders from. Values are stored normalized: emails
+-- trimmed and lower-cased, phones reduced to their digits.
+CREATE TABLE order_blocklist (
+    id_blocklist_entry BIGSERIAL PRIMARY KEY,
+    tenant_id BIGINT NOT NULL,
+    kind VARCHAR(10) NOT NULL,
+    value VARCHAR(255) NOT NULL,
+    reason VARCHAR(255) NULL,
+    created_on TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    CONSTRAINT chk_order_blocklist_kind CHECK (kind IN ('email', 'phone')),
+    CONSTRAINT fk_order_blocklist_tenant
+        FOREIGN KEY (tenant_id) REFERENCES tenants(id) ON DELETE CASCADE
+);
+
+CREATE UNIQUE INDEX ux_order_blocklist_tenant_kind_value ON order_blocklist (tenant_id, kind, value);
+
+-- Order creation counts the pending unpaid orders of the customer.
+CREATE INDEX idx_orders_tenant_user_pending_unpaid
+    ON orders (tenant_id, id_user)
+    WHERE status = 'pending' AND paid IS NOT TRUE;
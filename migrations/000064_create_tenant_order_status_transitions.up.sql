@@ -0,0 +1,11 @@
+-- Order status changes a tenant allows through the API. A tenant without rows uses the default
+-- lifecycle (pending -> preparing -> ready -> delivered, cancel and delete along the way).
+CREATE TABLE tenant_order_status_transitions (
+    tenant_id BIGINT NOT NULL,
+    from_status order_status NOT NULL,
+    to_status order_status NOT NULL,
+    PRIMARY KEY (tenant_id, from_status, to_status),
+    CONSTRAINT chk_tenant_order_status_transitions_edge CHECK (from_status <> to_status),
+    CONSTRAINT fk_tenant_order_status_transitions_tenant
+        FOREIGN KEY (tenant_id) REFERENCES tenants(id) ON DELETE CASCADE
+);
@@ -0,0 +1,4 @@
+DROP INDEX IF EXISTS idx_orders_tenant_user_pending_unpaid;
+CREATE INDEX idx_orders_tenant_user_pending_unpaid
+    ON orders (tenant_id, id_user)
+    WHERE status = 'pending' AND paid IS NOT TRUE;
@@ -0,0 +1,6 @@
+-- Only storefront orders waiting to expire count against max_pending_unpaid_orders; staff and
+-- standing orders have no expires_at.
+DROP INDEX IF EXISTS idx_orders_tenant_user_pending_unpaid;
+CREATE INDEX idx_orders_tenant_user_pending_unpaid
+    ON orders (tenant_id, id_user)
+    WHERE status = 'pending' AND paid IS NOT TRUE AND expires_at IS NOT NULL;
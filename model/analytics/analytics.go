@@ -0,0 +1,103 @@
+package model
+
+import (
+	"time"
+
+	"github.com/radamesvaz/bakery-app/model/money"
+)
+
+// Interval is the bucket size of a revenue series.
+type Interval string
+
+const (
+	IntervalDay   Interval = "day"
+	IntervalWeek  Interval = "week"
+	IntervalMonth Interval = "month"
+)
+
+// IsValidInterval reports whether i is day, week or month.
+func IsValidInterval(i Interval) bool {
+	return i == IntervalDay || i == IntervalWeek || i == IntervalMonth
+}
+
+// RevenueTotals sums the orders of a period. Cancelled, expired and deleted orders are excluded.
+type RevenueTotals struct {
+	Orders            int          `json:"orders"`
+	Revenue           money.Amount `json:"revenue"`
+	PaidOrders        int          `json:"paid_orders"`
+	PaidRevenue       money.Amount `json:"paid_revenue"`
+	UnpaidOrders      int          `json:"unpaid_orders"`
+	UnpaidRevenue     money.Amount `json:"unpaid_revenue"`
+	AverageOrderValue money.Amount `json:"average_order_value"`
+}
+
+// RevenueBucket is one day, week (starting Monday) or month of the series.
+type RevenueBucket struct {
+	PeriodStart string `json:"period_start"`
+	RevenueTotals
+}
+
+// RevenueReport is the response of GET /auth/analytics/revenue.
+type RevenueReport struct {
+	From     string          `json:"from"`
+	To       string          `json:"to"`
+	Interval Interval        `json:"interval"`
+	Summary  RevenueTotals   `json:"summary"`
+	Buckets  []RevenueBucket `json:"buckets"`
+}
+
+// RevenueRow is what the repository returns per bucket.
+type RevenueRow struct {
+	PeriodStart   time.Time
+	PaidOrders    int
+	PaidRevenue   money.Amount
+	UnpaidOrders  int
+	UnpaidRevenue money.Amount
+}
+
+// TopProductsSort orders the top products list.
+type TopProductsSort string
+
+const (
+	SortByUnits   TopProductsSort = "units"
+	SortByRevenue TopProductsSort = "revenue"
+)
+
+// TopProduct aggregates the order lines of one product, priced with the unit price snapshots.
+type TopProduct struct {
+	IDProduct uint64       `json:"id_product"`
+	Name      string       `json:"name"`
+	Units     uint64       `json:"units"`
+	Revenue   money.Amount `json:"revenue"`
+	Orders    int          `json:"orders"`
+}
+
+// TopProductsReport is the response of GET /auth/analytics/top-products.
+type TopProductsReport struct {
+	From  string          `json:"from"`
+	To    string          `json:"to"`
+	Sort  TopProductsSort `json:"sort"`
+	Items []TopProduct    `json:"items"`
+}
+
+// CancellationCounts is how the orders of a range ended up closed without delivery.
+type CancellationCounts struct {
+	TotalOrders         int
+	CancelledByAdmin    int
+	CancelledByCustomer int
+	Expired             int
+}
+
+// CancellationReport is the response of GET /auth/analytics/cancellations. Rates are fractions
+// (0..1) of total_orders.
+type CancellationReport struct {
+	From                    string  `json:"from"`
+	To                      string  `json:"to"`
+	TotalOrders             int     `json:"total_orders"`
+	CancelledByAdmin        int     `json:"cancelled_by_admin"`
+	CancelledByCustomer     int     `json:"cancelled_by_customer"`
+	Expired                 int     `json:"expired"`
+	CancelledByAdminRate    float64 `json:"cancelled_by_admin_rate"`
+	CancelledByCustomerRate float64 `json:"cancelled_by_customer_rate"`
+	ExpiredRate             float64 `json:"expired_rate"`
+}
@@ -0,0 +1,18 @@
+package model
+
+type AccountClaimRequest struct {
+	Email string `json:"email"`
+}
+
+type AccountClaimResponse struct {
+	Message string `json:"message"`
+}
+
+type CompleteAccountClaimRequest struct {
+	Token       string `json:"token"`
+	NewPassword string `json:"new_password"`
+}
+
+type CompleteAccountClaimResponse struct {
+	Message string `json:"message"`
+}
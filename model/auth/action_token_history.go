@@ -9,4 +9,6 @@ const (
 	ActionTokenInviteAccepted          ActionTokenHistoryAction = "invite_accepted"
 	ActionTokenPasswordResetIssued    ActionTokenHistoryAction = "password_reset_issued"
 	ActionTokenPasswordResetCompleted ActionTokenHistoryAction = "password_reset_completed"
+	ActionTokenAccountClaimIssued     ActionTokenHistoryAction = "account_claim_issued"
+	ActionTokenAccountClaimCompleted  ActionTokenHistoryAction = "account_claim_completed"
 )
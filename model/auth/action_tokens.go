@@ -10,6 +10,8 @@ type ActionTokenPurpose string
 const (
 	ActionTokenPurposeInvite        ActionTokenPurpose = "invite"
 	ActionTokenPurposePasswordReset ActionTokenPurpose = "password_reset"
+	// ActionTokenPurposeAccountClaim lets a customer created by an order set its first password.
+	ActionTokenPurposeAccountClaim ActionTokenPurpose = "account_claim"
 )
 
 type CreateActionTokenRequest struct {
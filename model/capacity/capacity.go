@@ -0,0 +1,126 @@
+package model
+
+import "time"
+
+// DateLayout is the format of delivery dates in requests and responses.
+const DateLayout = "2006-01-02"
+
+// Settings are the tenant's default delivery limits. A nil limit means unlimited.
+type Settings struct {
+	MaxOrdersPerDay  *int `json:"max_orders_per_day"`
+	MaxUnitsPerDay   *int `json:"max_units_per_day"`
+	MinLeadTimeHours int  `json:"min_lead_time_hours"`
+}
+
+// DayOverride replaces the default limits on one delivery date; Closed makes it a blackout date.
+// A nil limit falls back to the tenant default.
+type DayOverride struct {
+	Date      string  `json:"date"`
+	Closed    bool    `json:"closed"`
+	MaxOrders *int    `json:"max_orders"`
+	MaxUnits  *int    `json:"max_units"`
+	Note      *string `json:"note,omitempty"`
+}
+
+// UpsertDayOverrideRequest is the body of PUT /auth/delivery-capacity/days/{date}.
+type UpsertDayOverrideRequest struct {
+	Closed    bool    `json:"closed"`
+	MaxOrders *int    `json:"max_orders"`
+	MaxUnits  *int    `json:"max_units"`
+	Note      *string `json:"note"`
+}
+
+// DayOverridesResponse is returned by GET /auth/delivery-capacity/days.
+type DayOverridesResponse struct {
+	Items []DayOverride `json:"items"`
+}
+
+// DayRules are the effective limits of one delivery date.
+type DayRules struct {
+	Closed           bool
+	MaxOrders        *int
+	MaxUnits         *int
+	MinLeadTimeHours int
+}
+
+// EffectiveRules merges the tenant defaults with the override of the date, if any.
+func EffectiveRules(settings Settings, override *DayOverride) DayRules {
+	rules := DayRules{
+		MaxOrders:        settings.MaxOrdersPerDay,
+		MaxUnits:         settings.MaxUnitsPerDay,
+		MinLeadTimeHours: settings.MinLeadTimeHours,
+	}
+	if override != nil {
+		rules.Closed = override.Closed
+		if override.MaxOrders != nil {
+			rules.MaxOrders = override.MaxOrders
+		}
+		if override.MaxUnits != nil {
+			rules.MaxUnits = override.MaxUnits
+		}
+	}
+	return rules
+}
+
+// HasLimits reports whether booking the date needs the usage of the day.
+func (r DayRules) HasLimits() bool {
+	return r.MaxOrders != nil || r.MaxUnits != nil
+}
+
+// DayUsage is what active (not cancelled, expired or deleted) orders already booked on a date.
+type DayUsage struct {
+	Orders int
+	Units  int
+}
+
+// Unavailability reasons returned by the availability endpoint.
+const (
+	ReasonClosed   = "closed"
+	ReasonFull     = "full"
+	ReasonLeadTime = "lead_time"
+)
+
+// Check returns the reason an order of units on date cannot be accepted at now, or "" when it fits.
+// Dates are calendar days in UTC; the lead time counts up to the start of the delivery day.
+func (r DayRules) Check(date, now time.Time, usage DayUsage, units int) string {
+	if r.Closed {
+		return ReasonClosed
+	}
+	if date.Before(now.Add(time.Duration(r.MinLeadTimeHours) * time.Hour)) {
+		return ReasonLeadTime
+	}
+	if r.MaxOrders != nil && usage.Orders+1 > *r.MaxOrders {
+		return ReasonFull
+	}
+	if r.MaxUnits != nil && usage.Units+units > *r.MaxUnits {
+		return ReasonFull
+	}
+	return ""
+}
+
+// CheckAddedUnits returns ReasonFull when units more on top of usage exceed MaxUnits, or "" when
+// they fit. It is used when an order already booked on the date grows, so closing, lead time and
+// the order limit are not checked again.
+func (r DayRules) CheckAddedUnits(usage DayUsage, units int) string {
+	if r.MaxUnits != nil && usage.Units+units > *r.MaxUnits {
+		return ReasonFull
+	}
+	return ""
+}
+
+// DayAvailability is one day of GET /t/{tenant_slug}/availability. Remaining counts are only
+// set when the day has that limit.
+type DayAvailability struct {
+	Date            string `json:"date"`
+	Available       bool   `json:"available"`
+	Reason          string `json:"reason,omitempty"`
+	RemainingOrders *int   `json:"remaining_orders,omitempty"`
+	RemainingUnits  *int   `json:"remaining_units,omitempty"`
+}
+
+// AvailabilityResponse is returned by GET /t/{tenant_slug}/availability.
+type AvailabilityResponse struct {
+	From string            `json:"from"`
+	To   string            `json:"to"`
+	Days []DayAvailability `json:"days"`
+}
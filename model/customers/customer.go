@@ -0,0 +1,42 @@
+package model
+
+import (
+	"time"
+
+	"github.com/radamesvaz/bakery-app/model/money"
+)
+
+// Customer is a client account of the tenant with its order stats. OrderCount and LastOrderAt
+// count every order except deleted ones; LifetimeSpend adds up the paid orders that were not
+// cancelled, expired or deleted.
+type Customer struct {
+	ID            uint64       `json:"id_user"`
+	Name          string       `json:"name"`
+	Email         string       `json:"email"`
+	Phone         string       `json:"phone"`
+	CreatedOn     time.Time    `json:"created_on"`
+	OrderCount    int          `json:"order_count"`
+	LifetimeSpend money.Amount `json:"lifetime_spend"`
+	LastOrderAt   *time.Time   `json:"last_order_date"`
+}
+
+// CustomerDetail is the customer view of GET /auth/customers/{id}. PossibleDuplicates are the
+// other client accounts with the same phone, candidates for a merge.
+type CustomerDetail struct {
+	Customer
+	FirstOrderAt       *time.Time `json:"first_order_date"`
+	PossibleDuplicates []Customer `json:"possible_duplicates"`
+}
+
+// MergeRequest is the body of POST /auth/customers/{id}/merge: the accounts folded into {id}.
+type MergeRequest struct {
+	DuplicateIDs []uint64 `json:"duplicate_ids"`
+}
+
+// MergeResult reports a merge. The duplicate accounts are deleted once their orders moved.
+type MergeResult struct {
+	Customer     Customer `json:"customer"`
+	MergedIDs    []uint64 `json:"merged_ids"`
+	OrdersMoved  int64    `json:"orders_moved"`
+	HistoryMoved int64    `json:"history_moved"`
+}
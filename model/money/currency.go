@@ -0,0 +1,30 @@
+package money
+
+import "strings"
+
+// Currency is an ISO 4217 currency code such as "USD".
+type Currency string
+
+// DefaultCurrency is the currency of tenants that never chose one.
+const DefaultCurrency Currency = "USD"
+
+// currencies are the ISO 4217 codes tenants can pick: the ones with two minor unit digits, so
+// their amounts fit Amount and the two-decimal NUMERIC columns. Zero- and three-decimal
+// currencies (JPY, CLP, KWD...) are not supported yet.
+var currencies = map[Currency]struct{}{
+	"ARS": {}, "AUD": {}, "BOB": {}, "BRL": {}, "CAD": {}, "CHF": {}, "CNY": {}, "COP": {},
+	"CRC": {}, "CZK": {}, "DKK": {}, "DOP": {}, "EUR": {}, "GBP": {}, "GTQ": {}, "HNL": {},
+	"MXN": {}, "NIO": {}, "NOK": {}, "NZD": {}, "PAB": {}, "PEN": {}, "PLN": {}, "SEK": {},
+	"TTD": {}, "USD": {}, "UYU": {}, "VES": {}, "ZAR": {},
+}
+
+// ParseCurrency normalizes raw (" eur " becomes "EUR") and reports whether it is supported.
+func ParseCurrency(raw string) (Currency, bool) {
+	c := Currency(strings.ToUpper(strings.TrimSpace(raw)))
+	_, ok := currencies[c]
+	return c, ok
+}
+
+func (c Currency) String() string {
+	return string(c)
+}
@@ -0,0 +1,149 @@
+package money
+
+import (
+	"database/sql/driver"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// Amount is a sum of money in minor units of its currency (cents for USD), so adding and
+// multiplying amounts never drifts. Every supported currency has two decimals, which is also the
+// scale of the NUMERIC columns amounts are stored in.
+//
+// In JSON and in SQL an Amount is a decimal in major units ("12.50"), so API clients and queries
+// keep working with the values they always saw.
+type Amount int64
+
+// Decimals is the number of minor unit digits of every supported currency.
+const Decimals = 2
+
+const minorPerMajor = 100
+
+// FromMajor converts a value in major units (12.5 dollars) to an Amount, rounding to the nearest
+// minor unit. Use it only at the edges where a float comes in (config, tests).
+func FromMajor(v float64) Amount {
+	return Amount(math.Round(v * minorPerMajor))
+}
+
+// Parse reads a decimal in major units ("12.5", "-3", "0.05") exactly. More than two decimals
+// is an error instead of a silent rounding.
+func Parse(s string) (Amount, error) {
+	raw := strings.TrimSpace(s)
+	digits := raw
+	negative := strings.HasPrefix(digits, "-")
+	if negative || strings.HasPrefix(digits, "+") {
+		digits = digits[1:]
+	}
+	whole, frac, hasFrac := strings.Cut(digits, ".")
+	if whole == "" || !isDigits(whole) || (hasFrac && !isDigits(frac)) {
+		return 0, fmt.Errorf("invalid amount %q", raw)
+	}
+	frac = strings.TrimRight(frac, "0")
+	if len(frac) > Decimals {
+		return 0, fmt.Errorf("invalid amount %q: at most %d decimals", raw, Decimals)
+	}
+	frac += strings.Repeat("0", Decimals-len(frac))
+
+	units, err := strconv.ParseInt(whole+frac, 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid amount %q: %w", raw, err)
+	}
+	if negative {
+		units = -units
+	}
+	return Amount(units), nil
+}
+
+func isDigits(s string) bool {
+	for _, r := range s {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return s != ""
+}
+
+// Times returns the amount of quantity units priced at a.
+func (a Amount) Times(quantity uint64) Amount {
+	return a * Amount(quantity)
+}
+
+// Percent returns rate percent of a, rounded half away from zero to the minor unit.
+func (a Amount) Percent(rate float64) Amount {
+	return Amount(math.Round(float64(a) * rate / 100))
+}
+
+// Major returns a in major units. The result is for display and spreadsheets only; do not
+// calculate with it.
+func (a Amount) Major() float64 {
+	return float64(a) / minorPerMajor
+}
+
+// String formats a in major units with two decimals ("12.50").
+func (a Amount) String() string {
+	sign := ""
+	units := int64(a)
+	if units < 0 {
+		sign = "-"
+		units = -units
+	}
+	return fmt.Sprintf("%s%d.%02d", sign, units/minorPerMajor, units%minorPerMajor)
+}
+
+// MarshalJSON writes a as a JSON number in major units.
+func (a Amount) MarshalJSON() ([]byte, error) {
+	return []byte(a.String()), nil
+}
+
+// UnmarshalJSON reads a JSON number in major units with at most two decimals.
+func (a *Amount) UnmarshalJSON(data []byte) error {
+	s := string(data)
+	if s == "null" {
+		return nil
+	}
+	parsed, err := Parse(s)
+	if err != nil {
+		// Exponent notation (1e2) is valid JSON; accept it when it is a whole number of minor units.
+		f, ferr := strconv.ParseFloat(s, 64)
+		if ferr != nil || math.Abs(f*minorPerMajor-math.Round(f*minorPerMajor)) > 1e-6 {
+			return err
+		}
+		parsed = FromMajor(f)
+	}
+	*a = parsed
+	return nil
+}
+
+// Scan implements sql.Scanner for NUMERIC columns, which the driver hands over as text.
+func (a *Amount) Scan(value interface{}) error {
+	switch v := value.(type) {
+	case []byte:
+		parsed, err := Parse(string(v))
+		if err != nil {
+			return err
+		}
+		*a = parsed
+	case string:
+		parsed, err := Parse(v)
+		if err != nil {
+			return err
+		}
+		*a = parsed
+	case int64:
+		*a = Amount(v * minorPerMajor)
+	case float64:
+		*a = FromMajor(v)
+	case nil:
+		return fmt.Errorf("cannot scan NULL into money.Amount")
+	default:
+		return fmt.Errorf("unsupported amount type %T", value)
+	}
+	return nil
+}
+
+// Value implements driver.Valuer; the decimal text is stored exactly by NUMERIC columns.
+func (a Amount) Value() (driver.Value, error) {
+	return a.String(), nil
+}
@@ -0,0 +1,97 @@
+package money
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		in       string
+		expected Amount
+	}{
+		{in: "12.5", expected: 1250},
+		{in: "12.50", expected: 1250},
+		{in: "12.500", expected: 1250},
+		{in: "0.05", expected: 5},
+		{in: "7", expected: 700},
+		{in: "-3.1", expected: -310},
+		{in: " 1.99 ", expected: 199},
+	}
+	for _, tt := range tests {
+		t.Run(tt.in, func(t *testing.T) {
+			got, err := Parse(tt.in)
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, got)
+		})
+	}
+
+	for _, in := range []string{"", "abc", "1.005", ".5", "1.2.3", "1e2", "NaN"} {
+		t.Run("invalid "+in, func(t *testing.T) {
+			_, err := Parse(in)
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestAmount_String(t *testing.T) {
+	assert.Equal(t, "0.00", Amount(0).String())
+	assert.Equal(t, "0.05", Amount(5).String())
+	assert.Equal(t, "12.50", Amount(1250).String())
+	assert.Equal(t, "-3.10", Amount(-310).String())
+}
+
+func TestAmount_Arithmetic(t *testing.T) {
+	// 0.1 + 0.2 is exact in minor units.
+	assert.Equal(t, Amount(30), FromMajor(0.1)+FromMajor(0.2))
+	assert.Equal(t, Amount(597), Amount(199).Times(3))
+	assert.Equal(t, Amount(16), Amount(99).Percent(16))
+	assert.Equal(t, 12.5, Amount(1250).Major())
+}
+
+func TestAmount_JSON(t *testing.T) {
+	var body struct {
+		Price *Amount `json:"price"`
+		Fee   Amount  `json:"fee"`
+	}
+	require.NoError(t, json.Unmarshal([]byte(`{"price":19.9,"fee":1e1}`), &body))
+	require.NotNil(t, body.Price)
+	assert.Equal(t, Amount(1990), *body.Price)
+	assert.Equal(t, Amount(1000), body.Fee)
+
+	out, err := json.Marshal(body)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"price":19.90,"fee":10.00}`, string(out))
+
+	assert.Error(t, json.Unmarshal([]byte(`{"fee":1.999}`), &body))
+	assert.Error(t, json.Unmarshal([]byte(`{"fee":"1.99"}`), &body))
+}
+
+func TestAmount_Scan(t *testing.T) {
+	var a Amount
+	require.NoError(t, a.Scan([]byte("45.10")))
+	assert.Equal(t, Amount(4510), a)
+	require.NoError(t, a.Scan(int64(3)))
+	assert.Equal(t, Amount(300), a)
+	require.NoError(t, a.Scan(2.25))
+	assert.Equal(t, Amount(225), a)
+	assert.Error(t, a.Scan(nil))
+
+	v, err := Amount(4510).Value()
+	require.NoError(t, err)
+	assert.Equal(t, "45.10", v)
+}
+
+func TestParseCurrency(t *testing.T) {
+	c, ok := ParseCurrency(" eur ")
+	assert.True(t, ok)
+	assert.Equal(t, Currency("EUR"), c)
+
+	_, ok = ParseCurrency("JPY")
+	assert.False(t, ok)
+	_, ok = ParseCurrency("")
+	assert.False(t, ok)
+}
@@ -0,0 +1,89 @@
+package model
+
+import (
+	"time"
+
+	"github.com/radamesvaz/bakery-app/model/money"
+)
+
+type Kind string
+
+const (
+	KindOrderConfirmation Kind = "order_confirmation"
+	KindOrderReady        Kind = "order_ready"
+	KindOrderDelivered    Kind = "order_delivered"
+	KindOrderCancelled    Kind = "order_cancelled"
+	KindOrderExpired      Kind = "order_expired"
+	KindAdminNewOrder     Kind = "admin_new_order"
+)
+
+// Kinds lists every notification a tenant can toggle.
+var Kinds = []Kind{
+	KindOrderConfirmation,
+	KindOrderReady,
+	KindOrderDelivered,
+	KindOrderCancelled,
+	KindOrderExpired,
+	KindAdminNewOrder,
+}
+
+// IsValidKind reports whether k is one of Kinds.
+func IsValidKind(k Kind) bool {
+	for _, known := range Kinds {
+		if k == known {
+			return true
+		}
+	}
+	return false
+}
+
+// IsAdminKind reports whether the notification goes to the tenant admins instead of the customer.
+func IsAdminKind(k Kind) bool {
+	return k == KindAdminNewOrder
+}
+
+// Setting is the state of one notification for a tenant.
+type Setting struct {
+	Kind    Kind `json:"kind"`
+	Enabled bool `json:"enabled"`
+}
+
+// SettingsResponse is returned by GET/PUT /auth/notifications/settings.
+type SettingsResponse struct {
+	Items []Setting `json:"items"`
+}
+
+// UpdateSettingsRequest is the body of PUT /auth/notifications/settings; omitted kinds keep their value.
+type UpdateSettingsRequest struct {
+	Items []Setting `json:"items"`
+}
+
+// PendingEmail is a queued email claimed by the worker.
+type PendingEmail struct {
+	ID       uint64
+	TenantID uint64
+	IDOrder  uint64
+	Kind     Kind
+	ToEmail  string
+	Attempts int
+}
+
+// OrderEmailItem is a line of the order as rendered in emails.
+type OrderEmailItem struct {
+	Name      string
+	Quantity  uint64
+	UnitPrice money.Amount
+}
+
+// OrderEmailData is the current state of an order used to render its emails.
+type OrderEmailData struct {
+	TenantName         string
+	CustomerName       string
+	IDOrder            uint64
+	Status             string
+	TotalPrice         money.Amount
+	Currency           money.Currency
+	DeliveryDate       time.Time
+	CancellationReason *string
+	Items              []OrderEmailItem
+}
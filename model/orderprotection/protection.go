@@ -0,0 +1,82 @@
+package model
+
+import (
+	"strings"
+	"time"
+)
+
+// Defaults of a tenant that never saved its order protection settings.
+const (
+	DefaultMaxPendingUnpaidOrders = 3
+	DefaultMaxQuantityPerLine     = 100
+)
+
+// Settings limit what one storefront customer can hold. MaxPendingUnpaidOrders caps the pending
+// orders a customer (by email) has not paid yet; MaxQuantityPerLine caps the quantity of each
+// product in an order. 0 disables a limit.
+type Settings struct {
+	MaxPendingUnpaidOrders int `json:"max_pending_unpaid_orders"`
+	MaxQuantityPerLine     int `json:"max_quantity_per_line"`
+}
+
+// DefaultSettings are the settings of a tenant without a row.
+func DefaultSettings() Settings {
+	return Settings{
+		MaxPendingUnpaidOrders: DefaultMaxPendingUnpaidOrders,
+		MaxQuantityPerLine:     DefaultMaxQuantityPerLine,
+	}
+}
+
+// BlocklistKind is what a blocklist entry matches on.
+type BlocklistKind string
+
+const (
+	BlocklistEmail BlocklistKind = "email"
+	BlocklistPhone BlocklistKind = "phone"
+)
+
+// BlocklistEntry is an email or phone the storefront rejects orders from. Value is normalized
+// (see NormalizeValue).
+type BlocklistEntry struct {
+	ID        uint64        `json:"id_blocklist_entry"`
+	TenantID  uint64        `json:"tenant_id"`
+	Kind      BlocklistKind `json:"kind"`
+	Value     string        `json:"value"`
+	Reason    *string       `json:"reason"`
+	CreatedOn time.Time     `json:"created_on"`
+}
+
+// BlocklistEntryRequest is the body of POST /auth/order-blocklist.
+type BlocklistEntryRequest struct {
+	Kind   BlocklistKind `json:"kind"`
+	Value  string        `json:"value"`
+	Reason *string       `json:"reason"`
+}
+
+// BlocklistResponse is returned by GET /auth/order-blocklist.
+type BlocklistResponse struct {
+	Items []BlocklistEntry `json:"items"`
+}
+
+// NormalizeEmail trims and lower-cases an email, so blocklist matches ignore case.
+func NormalizeEmail(email string) string {
+	return strings.ToLower(strings.TrimSpace(email))
+}
+
+// NormalizePhone keeps only the digits of a phone, so "+58 412-555" and "58412555" match.
+func NormalizePhone(phone string) string {
+	return strings.Map(func(r rune) rune {
+		if r >= '0' && r <= '9' {
+			return r
+		}
+		return -1
+	}, phone)
+}
+
+// NormalizeValue normalizes value as the kind of entry it is.
+func NormalizeValue(kind BlocklistKind, value string) string {
+	if kind == BlocklistPhone {
+		return NormalizePhone(value)
+	}
+	return NormalizeEmail(value)
+}
@@ -0,0 +1,26 @@
+package model
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNormalizeValue(t *testing.T) {
+	tests := []struct {
+		name  string
+		kind  BlocklistKind
+		value string
+		want  string
+	}{
+		{name: "email is trimmed and lower-cased", kind: BlocklistEmail, value: "  Ana@Example.COM ", want: "ana@example.com"},
+		{name: "phone keeps its digits", kind: BlocklistPhone, value: "+58 (412) 555-01", want: "5841255501"},
+		{name: "phone without digits", kind: BlocklistPhone, value: "n/a", want: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, NormalizeValue(tt.kind, tt.value))
+		})
+	}
+}
@@ -1,6 +1,10 @@
 package model
 
-import "time"
+import (
+	"time"
+
+	"github.com/radamesvaz/bakery-app/model/money"
+)
 
 type OrderStatus string
 
@@ -14,37 +18,95 @@ const (
 	StatusDeleted   OrderStatus = "deleted"
 )
 
+// FulfillmentType is how the customer receives the order: delivered to delivery_direction or
+// collected at a pickup location.
+type FulfillmentType string
+
+const (
+	FulfillmentDelivery FulfillmentType = "delivery"
+	FulfillmentPickup   FulfillmentType = "pickup"
+)
+
+// NormalizeFulfillmentType maps an empty value to delivery, the mode orders had before pickup existed.
+func NormalizeFulfillmentType(t FulfillmentType) FulfillmentType {
+	if t == "" {
+		return FulfillmentDelivery
+	}
+	return t
+}
+
+// SalesChannel is where the order was taken: the storefront (web) or by staff over the phone,
+// WhatsApp or at the counter.
+type SalesChannel string
+
+const (
+	SalesChannelWeb      SalesChannel = "web"
+	SalesChannelPhone    SalesChannel = "phone"
+	SalesChannelWhatsApp SalesChannel = "whatsapp"
+	SalesChannelCounter  SalesChannel = "counter"
+)
+
+// NormalizeSalesChannel maps an empty value to web, the channel of every storefront order.
+func NormalizeSalesChannel(c SalesChannel) SalesChannel {
+	if c == "" {
+		return SalesChannelWeb
+	}
+	return c
+}
+
 type Order struct {
-	ID                 uint64      `json:"id_order" gorm:"primaryKey"`
-	TenantID           uint64      `json:"tenant_id"`
-	IdUser             uint64      `json:"id_user" gorm:"not null;unique"`
-	Status             OrderStatus `json:"status"`
-	Price              float64     `json:"total_price" gorm:"not null;check:price >= 0"`
-	Note               string      `json:"note"`
-	DeliveryDirection  string      `json:"delivery_direction"`
-	CreatedOn          time.Time   `json:"created_on"`
-	DeliveryDate       time.Time   `json:"delivery_date"`
-	Paid               bool        `json:"paid" gorm:"default:false"`
-	ExpiresAt          time.Time   `json:"expires_at,omitempty"`
-	CancellationReason *string     `json:"cancellation_reason,omitempty"`
+	ID                 uint64       `json:"id_order" gorm:"primaryKey"`
+	TenantID           uint64       `json:"tenant_id"`
+	IdUser             uint64       `json:"id_user" gorm:"not null;unique"`
+	Status             OrderStatus  `json:"status"`
+	Price              money.Amount `json:"total_price" gorm:"not null;check:price >= 0"`
+	Note               string       `json:"note"`
+	DeliveryDirection  string       `json:"delivery_direction"`
+	CreatedOn          time.Time    `json:"created_on"`
+	DeliveryDate       time.Time    `json:"delivery_date"`
+	Paid               bool         `json:"paid" gorm:"default:false"`
+	ExpiresAt          time.Time    `json:"expires_at,omitempty"`
+	CancellationReason *string      `json:"cancellation_reason,omitempty"`
+	DiscountAmount     money.Amount `json:"discount_amount"`
+	PromotionCode      *string      `json:"promotion_code,omitempty"`
 }
 
 type OrderResponse struct {
-	ID                 uint64      `json:"id_order" gorm:"primaryKey"`
-	TenantID           uint64      `json:"tenant_id"`
-	IdUser             uint64      `json:"id_user" gorm:"not null"`
-	User               string      `json:"user_name" gorm:"not null;unique"`
-	Phone              string      `json:"phone"`
-	Status             OrderStatus `json:"status"`
-	Price              float64     `json:"total_price" gorm:"not null;check:price >= 0"`
-	Note               string      `json:"note"`
-	DeliveryDirection  string      `json:"delivery_direction"`
+	ID                 uint64         `json:"id_order" gorm:"primaryKey"`
+	TenantID           uint64         `json:"tenant_id"`
+	IdUser             uint64         `json:"id_user" gorm:"not null"`
+	User               string         `json:"user_name" gorm:"not null;unique"`
+	Phone              string         `json:"phone"`
+	Status             OrderStatus    `json:"status"`
+	Price              money.Amount   `json:"total_price" gorm:"not null;check:price >= 0"`
+	Currency           money.Currency `json:"currency"`
+	Note               string         `json:"note"`
+	DeliveryDirection  string         `json:"delivery_direction"`
 	OrderItems         []OrderItems
 	CreatedOn          time.Time `json:"created_on"`
 	DeliveryDate       time.Time `json:"delivery_date"`
 	Paid               bool      `json:"paid"`
 	ExpiresAt          time.Time `json:"expires_at,omitempty"`
 	CancellationReason *string   `json:"cancellation_reason,omitempty"`
+	// DiscountAmount is already taken off Price (total_price); PromotionCode is the code that granted it.
+	DiscountAmount money.Amount `json:"discount_amount"`
+	PromotionCode  *string      `json:"promotion_code,omitempty"`
+	IDPromotion    *uint64      `json:"id_promotion,omitempty"`
+	// Breakdown of Price: Subtotal - DiscountAmount + DeliveryFee, plus TaxAmount unless TaxInclusive.
+	Subtotal       money.Amount `json:"subtotal"`
+	TaxRate        float64      `json:"tax_rate"`
+	TaxInclusive   bool         `json:"tax_inclusive"`
+	TaxAmount      money.Amount `json:"tax_amount"`
+	DeliveryFee    money.Amount `json:"delivery_fee"`
+	IDDeliveryZone *uint64      `json:"id_delivery_zone,omitempty"`
+	// FulfillmentType is delivery or pickup; pickup orders have IDPickupLocation and no delivery_direction.
+	FulfillmentType  FulfillmentType `json:"fulfillment_type"`
+	IDPickupLocation *uint64         `json:"id_pickup_location,omitempty"`
+	SalesChannel     SalesChannel    `json:"sales_channel"`
+	// Derived from the order_payments ledger.
+	AmountPaid money.Amount `json:"amount_paid"`
+	BalanceDue money.Amount `json:"balance_due"`
+	RefundDue  money.Amount `json:"refund_due"`
 }
 
 type CreateOrderPayload struct {
@@ -55,27 +117,97 @@ type CreateOrderPayload struct {
 	DeliveryDirection string                 `json:"delivery_direction"`
 	Note              string                 `json:"note"`
 	Items             []CreateOrderItemInput `json:"items"`
+	// PromotionCode is an optional discount code, matched case-insensitively.
+	PromotionCode string `json:"promotion_code,omitempty"`
+	// IDDeliveryZone picks the delivery zone whose fee applies; nil uses the tenant's flat fee.
+	IDDeliveryZone *uint64 `json:"id_delivery_zone,omitempty"`
+	// FulfillmentType defaults to delivery. Pickup orders need IDPickupLocation instead of
+	// delivery_direction and id_delivery_zone.
+	FulfillmentType  FulfillmentType `json:"fulfillment_type,omitempty"`
+	IDPickupLocation *uint64         `json:"id_pickup_location,omitempty"`
+}
+
+// StaffCreateOrderPayload is the body of POST /auth/orders, the order entry of staff taking
+// orders by phone, WhatsApp or at the counter. The customer fields are optional: without an
+// email the order has no customer account.
+type StaffCreateOrderPayload struct {
+	CreateOrderPayload
+	SalesChannel SalesChannel `json:"sales_channel"`
+	// Status is the status the order starts in (default pending); delivered records a sale
+	// handed over on the spot.
+	Status OrderStatus `json:"status,omitempty"`
+	// Paid records a ledger payment of the full total with PaymentMethod (default cash).
+	Paid             bool          `json:"paid"`
+	PaymentMethod    PaymentMethod `json:"payment_method,omitempty"`
+	PaymentReference *string       `json:"payment_reference,omitempty"`
 }
 
 type CreateOrderRequest struct {
-	TenantID           uint64      `json:"tenant_id"`
-	IdUser             uint64      `json:"id_user" gorm:"not null;unique"`
-	DeliveryDate       time.Time   `json:"delivery_date" validate:"required,datetime=2006-01-02"`
-	DeliveryDirection  string      `json:"delivery_direction"`
-	Note               string      `json:"note"`
-	Price              float64     `json:"total_price" gorm:"not null;check:price >= 0"`
-	Status             OrderStatus `json:"status"`
-	Paid               bool        `json:"paid" gorm:"default:false"`
-	ExpiresAt          time.Time   `json:"expires_at"` // TODO multi-tenant: in the future derive from per-tenant timeout, not global env
+	TenantID           uint64       `json:"tenant_id"`
+	IdUser             uint64       `json:"id_user" gorm:"not null;unique"`
+	DeliveryDate       time.Time    `json:"delivery_date" validate:"required,datetime=2006-01-02"`
+	DeliveryDirection  string       `json:"delivery_direction"`
+	Note               string       `json:"note"`
+	Price              money.Amount `json:"total_price" gorm:"not null;check:price >= 0"`
+	Status             OrderStatus  `json:"status"`
+	Paid               bool         `json:"paid" gorm:"default:false"`
+	ExpiresAt          time.Time    `json:"expires_at"` // TODO multi-tenant: in the future derive from per-tenant timeout, not global env
+	TrackingTokenHash  string       `json:"-"`
+	DiscountAmount     money.Amount `json:"discount_amount"`
+	PromotionCode      *string      `json:"promotion_code,omitempty"`
+	IDPromotion        *uint64      `json:"id_promotion,omitempty"`
+	Subtotal           money.Amount `json:"subtotal"`
+	TaxRate            float64      `json:"tax_rate"`
+	TaxInclusive       bool         `json:"tax_inclusive"`
+	TaxAmount          money.Amount `json:"tax_amount"`
+	DeliveryFee        money.Amount `json:"delivery_fee"`
+	IDDeliveryZone     *uint64      `json:"id_delivery_zone,omitempty"`
+	FulfillmentType    FulfillmentType `json:"fulfillment_type"`
+	IDPickupLocation   *uint64         `json:"id_pickup_location,omitempty"`
+	SalesChannel       SalesChannel    `json:"sales_channel"`
 }
 
 type CreateFullOrder struct {
-	IdUser             uint64      `json:"id_user" gorm:"not null;unique"`
-	DeliveryDate       time.Time   `json:"delivery_date" validate:"required,datetime=2006-01-02"`
-	DeliveryDirection  string      `json:"delivery_direction"`
-	Note               string      `json:"note"`
-	Price              float64     `json:"total_price" gorm:"not null;check:price >= 0"`
-	Status             OrderStatus `json:"status"`
-	Paid               bool        `json:"paid" gorm:"default:false"`
+	IdUser             uint64       `json:"id_user" gorm:"not null;unique"`
+	DeliveryDate       time.Time    `json:"delivery_date" validate:"required,datetime=2006-01-02"`
+	DeliveryDirection  string       `json:"delivery_direction"`
+	Note               string       `json:"note"`
+	Price              money.Amount `json:"total_price" gorm:"not null;check:price >= 0"`
+	Status             OrderStatus  `json:"status"`
+	Paid               bool         `json:"paid" gorm:"default:false"`
 	OrderItems         []OrderItemRequest
 }
+
+// OrderTransitions lists the statuses an order can move to next (GET /auth/orders/{id}/transitions).
+type OrderTransitions struct {
+	IDOrder            uint64        `json:"id_order"`
+	Status             OrderStatus   `json:"status"`
+	AllowedTransitions []OrderStatus `json:"allowed_transitions"`
+}
+
+// CreateOrderResult is what the order creation flow hands back to the handler.
+type CreateOrderResult struct {
+	Order OrderResponse
+	// TrackingToken is only returned here; the database keeps its hash. Empty when Replayed.
+	TrackingToken string
+	// Replayed is true when the order was created by an earlier request with the same Idempotency-Key.
+	Replayed bool
+}
+
+// PaymentInstructions tells the storefront how much to collect and where.
+type PaymentInstructions struct {
+	AmountDue money.Amount `json:"amount_due"`
+	// ExpiresAt is when an unpaid pending order is cancelled automatically.
+	ExpiresAt *time.Time `json:"expires_at,omitempty"`
+	// CheckoutURL is the path to start an online checkout; empty when online payments are disabled.
+	CheckoutURL string `json:"checkout_url,omitempty"`
+}
+
+// CreateOrderResponse is the body returned by POST /t/{tenant_slug}/orders (and legacy POST /orders).
+type CreateOrderResponse struct {
+	Message       string              `json:"message"`
+	IDOrder       uint64              `json:"id_order"`
+	TrackingToken string              `json:"tracking_token,omitempty"`
+	Order         OrderResponse       `json:"order"`
+	Payment       PaymentInstructions `json:"payment"`
+}
@@ -2,6 +2,9 @@ package model
 
 import (
 	"database/sql"
+	"time"
+
+	"github.com/radamesvaz/bakery-app/model/money"
 )
 
 type OrderAction string
@@ -18,13 +21,48 @@ type OrderHistory struct {
 	IDOrder            uint64       `json:"id_order" gorm:"not null"`
 	IdUser             *uint64      `json:"id_user"` // nil when order's user was deleted (ON DELETE SET NULL)
 	Status             OrderStatus  `json:"status"`
-	Price              float64      `json:"total_price" gorm:"not null;check:price >= 0"`
+	Price              money.Amount `json:"total_price" gorm:"not null;check:price >= 0"`
 	Note               string       `json:"note"`
 	DeliveryDirection  string       `json:"delivery_direction"`
 	DeliveryDate       sql.NullTime `json:"delivery_date"`
 	Paid               bool         `json:"paid"`
 	CancellationReason *string      `json:"cancellation_reason,omitempty"`
+	DiscountAmount     money.Amount `json:"discount_amount"`
+	PromotionCode      *string      `json:"promotion_code,omitempty"`
 	ModifiedOn         sql.NullTime `json:"modified_on"`
 	ModifiedBy         uint64       `json:"modified_by"`
 	Action             OrderAction  `json:"action"`
 }
+
+// OrderHistoryChange is a single field that differs between two consecutive history entries.
+type OrderHistoryChange struct {
+	Field string      `json:"field"`
+	From  interface{} `json:"from"`
+	To    interface{} `json:"to"`
+}
+
+// OrderHistoryEntry is one row of an order timeline with the acting user resolved
+// and the changes versus the previous entry (empty for the first one).
+type OrderHistoryEntry struct {
+	ID                 uint64               `json:"id_order_history"`
+	Action             OrderAction          `json:"action"`
+	Status             OrderStatus          `json:"status"`
+	Price              money.Amount         `json:"total_price"`
+	Note               string               `json:"note"`
+	DeliveryDirection  string               `json:"delivery_direction"`
+	DeliveryDate       *time.Time           `json:"delivery_date"`
+	Paid               bool                 `json:"paid"`
+	CancellationReason *string              `json:"cancellation_reason,omitempty"`
+	DiscountAmount     money.Amount         `json:"discount_amount"`
+	PromotionCode      *string              `json:"promotion_code,omitempty"`
+	ModifiedOn         *time.Time           `json:"modified_on"`
+	ModifiedBy         uint64               `json:"modified_by"`
+	ModifiedByName     string               `json:"modified_by_name"`
+	Changes            []OrderHistoryChange `json:"changes"`
+}
+
+// OrderHistoryTimeline is the response of GET /auth/orders/{id}/history (oldest entry first).
+type OrderHistoryTimeline struct {
+	IDOrder uint64              `json:"id_order"`
+	Items   []OrderHistoryEntry `json:"items"`
+}
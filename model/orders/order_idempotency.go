@@ -0,0 +1,12 @@
+package model
+
+import "time"
+
+// OrderIdempotencyKey is a stored Idempotency-Key of a public order creation.
+type OrderIdempotencyKey struct {
+	Key         string
+	RequestHash string
+	// IDOrder is nil while the creating transaction has not committed.
+	IDOrder   *uint64
+	CreatedOn time.Time
+}
@@ -1,20 +1,22 @@
 package model
 
+import "github.com/radamesvaz/bakery-app/model/money"
+
 type OrderItems struct {
-	ID        uint64  `json:"id_order_item" gorm:"primaryKey"`
-	IdOrder   uint64  `json:"id_order" gorm:"not null;unique"`
-	IdProduct uint64  `json:"id_product" gorm:"not null;unique"`
-	Name      string  `json:"name"`        // snapshot del nombre del producto
-	UnitPrice float64 `json:"unit_price"`  // snapshot del precio unitario
-	Quantity  uint64  `json:"quantity"`
+	ID        uint64       `json:"id_order_item" gorm:"primaryKey"`
+	IdOrder   uint64       `json:"id_order" gorm:"not null;unique"`
+	IdProduct uint64       `json:"id_product" gorm:"not null;unique"`
+	Name      string       `json:"name"`        // snapshot del nombre del producto
+	UnitPrice money.Amount `json:"unit_price"`  // snapshot del precio unitario
+	Quantity  uint64       `json:"quantity"`
 }
 
 type OrderItemRequest struct {
-	IdProduct           uint64  `json:"id_product" validate:"required"`
-	IdOrder             uint64  `json:"id_order_item" gorm:"primaryKey"`
-	ProductNameSnapshot string  `json:"-"` // se usa solo internamente para persistir snapshots
-	UnitPriceSnapshot   float64 `json:"-"`
-	Quantity            uint64  `json:"quantity" validate:"required,gt=0"`
+	IdProduct           uint64       `json:"id_product" validate:"required"`
+	IdOrder             uint64       `json:"id_order_item" gorm:"primaryKey"`
+	ProductNameSnapshot string       `json:"-"` // se usa solo internamente para persistir snapshots
+	UnitPriceSnapshot   money.Amount `json:"-"`
+	Quantity            uint64       `json:"quantity" validate:"required,gt=0"`
 }
 
 type CreateOrderItemInput struct {
@@ -22,3 +24,8 @@ type CreateOrderItemInput struct {
 	Quantity  uint64 `json:"quantity"`
 }
 
+
+// UpdateOrderItemsPayload is the body of PUT /auth/orders/{id}/items: the full new list of lines.
+type UpdateOrderItemsPayload struct {
+	Items []CreateOrderItemInput `json:"items"`
+}
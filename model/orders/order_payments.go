@@ -0,0 +1,98 @@
+package model
+
+import (
+	"time"
+
+	"github.com/radamesvaz/bakery-app/model/money"
+)
+
+type PaymentMethod string
+
+const (
+	PaymentMethodCash     PaymentMethod = "cash"
+	PaymentMethodTransfer PaymentMethod = "transfer"
+	PaymentMethodCard     PaymentMethod = "card"
+	PaymentMethodOnline   PaymentMethod = "online"
+	PaymentMethodOther    PaymentMethod = "other"
+)
+
+// PaymentMethods lists the methods staff can record; online entries come from the payment provider.
+var PaymentMethods = []PaymentMethod{
+	PaymentMethodCash,
+	PaymentMethodTransfer,
+	PaymentMethodCard,
+	PaymentMethodOnline,
+	PaymentMethodOther,
+}
+
+// OrderPayment is one ledger line: money received for (or refunded from) an order.
+type OrderPayment struct {
+	ID         uint64        `json:"id"`
+	TenantID   uint64        `json:"tenant_id"`
+	IDOrder    uint64        `json:"id_order"`
+	Amount     money.Amount  `json:"amount"`
+	Method     PaymentMethod `json:"method"`
+	Reference  *string       `json:"reference,omitempty"`
+	IsRefund   bool          `json:"is_refund"`
+	RecordedBy *uint64       `json:"recorded_by,omitempty"`
+	IDPayment  *uint64       `json:"id_payment,omitempty"`
+	CreatedOn  time.Time     `json:"created_on"`
+}
+
+// RecordOrderPaymentPayload is the body of POST /auth/orders/{id}/payments and /refunds.
+type RecordOrderPaymentPayload struct {
+	Amount    money.Amount  `json:"amount"`
+	Method    PaymentMethod `json:"method"`
+	Reference *string       `json:"reference,omitempty"`
+}
+
+// OrderPaymentRequest is a ledger line to insert.
+type OrderPaymentRequest struct {
+	TenantID   uint64
+	IDOrder    uint64
+	Amount     money.Amount
+	Method     PaymentMethod
+	Reference  *string
+	IsRefund   bool
+	RecordedBy *uint64
+	IDPayment  *uint64
+}
+
+// OrderPaymentState is the locked view of an order used to apply a ledger line.
+type OrderPaymentState struct {
+	Status     OrderStatus
+	TotalPrice money.Amount
+	Paid       bool
+	AmountPaid money.Amount
+}
+
+// OrderPaymentsResponse is returned by GET /auth/orders/{id}/payments.
+type OrderPaymentsResponse struct {
+	Items      []OrderPayment `json:"items"`
+	AmountPaid money.Amount   `json:"amount_paid"`
+	BalanceDue money.Amount   `json:"balance_due"`
+	RefundDue  money.Amount   `json:"refund_due"`
+}
+
+// IsClosedStatus reports whether the order will not be fulfilled, so money taken for it is owed back.
+func IsClosedStatus(status OrderStatus) bool {
+	return status == StatusCancelled || status == StatusExpired || status == StatusDeleted
+}
+
+// ComputeBalance derives what the customer still owes and what the bakery owes back.
+// Closed orders owe nothing and everything paid is refundable; open orders owe the unpaid part
+// of the total and any overpayment (e.g. after items were removed) is refundable.
+func ComputeBalance(status OrderStatus, totalPrice, amountPaid money.Amount) (balanceDue, refundDue money.Amount) {
+	if IsClosedStatus(status) {
+		return 0, max(amountPaid, 0)
+	}
+	if amountPaid >= totalPrice {
+		return 0, amountPaid - totalPrice
+	}
+	return totalPrice - amountPaid, 0
+}
+
+// IsFullyPaid reports whether amountPaid covers totalPrice.
+func IsFullyPaid(totalPrice, amountPaid money.Amount) bool {
+	return amountPaid >= totalPrice
+}
@@ -0,0 +1,50 @@
+package model
+
+import (
+	"time"
+
+	"github.com/radamesvaz/bakery-app/model/money"
+)
+
+// OrderStreamEventType is the SSE event name of a change pushed by GET /auth/orders/stream.
+type OrderStreamEventType string
+
+const (
+	StreamOrderCreated       OrderStreamEventType = "order.created"
+	StreamOrderStatusChanged OrderStreamEventType = "order.status_changed"
+	StreamOrderPaid          OrderStreamEventType = "order.paid"
+	StreamOrderExpired       OrderStreamEventType = "order.expired"
+	// StreamOrderUpdated covers the other edits (items, note, delivery date, paid reverted).
+	StreamOrderUpdated OrderStreamEventType = "order.updated"
+)
+
+// OrderStreamEvent is one orders_history row as pushed to the kitchen dashboard. ID is the
+// id_order_history and doubles as the SSE event id, so a client resumes with Last-Event-ID.
+type OrderStreamEvent struct {
+	ID             uint64               `json:"id"`
+	Type           OrderStreamEventType `json:"type"`
+	IDOrder        uint64               `json:"id_order"`
+	Status         OrderStatus          `json:"status"`
+	PreviousStatus *OrderStatus         `json:"previous_status,omitempty"`
+	Paid           bool                 `json:"paid"`
+	TotalPrice     money.Amount         `json:"total_price"`
+	DeliveryDate   *time.Time           `json:"delivery_date,omitempty"`
+	At             time.Time            `json:"at"`
+}
+
+// ClassifyOrderStreamEvent names the change a history row records, given the previous row of the
+// same order (nil for its first row). A status change wins over a payment recorded with it.
+func ClassifyOrderStreamEvent(action OrderAction, status OrderStatus, paid bool, prevStatus *OrderStatus, prevPaid *bool) OrderStreamEventType {
+	switch {
+	case action == ActionCreate || prevStatus == nil:
+		return StreamOrderCreated
+	case status != *prevStatus && status == StatusExpired:
+		return StreamOrderExpired
+	case status != *prevStatus:
+		return StreamOrderStatusChanged
+	case paid && prevPaid != nil && !*prevPaid:
+		return StreamOrderPaid
+	default:
+		return StreamOrderUpdated
+	}
+}
@@ -0,0 +1,45 @@
+package model
+
+import (
+	"time"
+
+	"github.com/radamesvaz/bakery-app/model/money"
+)
+
+// OrderTrackingItem is a line of an order as shown to the customer.
+type OrderTrackingItem struct {
+	Name      string       `json:"name"`
+	Quantity  uint64       `json:"quantity"`
+	UnitPrice money.Amount `json:"unit_price"`
+}
+
+// OrderTrackingEvent is a status change in the public timeline of an order.
+type OrderTrackingEvent struct {
+	Status OrderStatus `json:"status"`
+	At     *time.Time  `json:"at"`
+}
+
+// OrderTrackingResponse is returned by GET /t/{tenant_slug}/orders/track/{token}. It omits
+// staff-only data (customer contact, notes, who changed what).
+type OrderTrackingResponse struct {
+	IDOrder            uint64               `json:"id_order"`
+	Status             OrderStatus          `json:"status"`
+	TotalPrice         money.Amount         `json:"total_price"`
+	Currency           money.Currency       `json:"currency"`
+	Paid               bool                 `json:"paid"`
+	AmountPaid         money.Amount         `json:"amount_paid"`
+	BalanceDue         money.Amount         `json:"balance_due"`
+	DeliveryDate       time.Time            `json:"delivery_date"`
+	FulfillmentType    FulfillmentType      `json:"fulfillment_type"`
+	IDPickupLocation   *uint64              `json:"id_pickup_location,omitempty"`
+	CreatedOn          time.Time            `json:"created_on"`
+	CancellationReason *string              `json:"cancellation_reason,omitempty"`
+	CanCancel          bool                 `json:"can_cancel"`
+	Items              []OrderTrackingItem  `json:"items"`
+	Timeline           []OrderTrackingEvent `json:"timeline"`
+}
+
+// CancelTrackedOrderPayload is the optional body of POST /t/{tenant_slug}/orders/track/{token}/cancel.
+type CancelTrackedOrderPayload struct {
+	Reason *string `json:"reason,omitempty"`
+}
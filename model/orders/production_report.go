@@ -0,0 +1,44 @@
+package model
+
+// ProductionReportRow is one order line booked for the report date.
+type ProductionReportRow struct {
+	IDOrder      uint64
+	IDProduct    uint64
+	ProductName  string
+	Quantity     uint64
+	Status       OrderStatus
+	CustomerName string
+	Note         string
+	// FulfillmentType and PickupLocation (the location name, pickup only) tell the kitchen where
+	// the order goes once baked.
+	FulfillmentType FulfillmentType
+	PickupLocation  string
+}
+
+// ProductionReportOrder is the share of one order in a product's total.
+type ProductionReportOrder struct {
+	IDOrder      uint64      `json:"id_order"`
+	CustomerName string      `json:"customer_name"`
+	Status       OrderStatus `json:"status"`
+	Quantity     uint64      `json:"quantity"`
+	Note         string      `json:"note,omitempty"`
+	// FulfillmentType is delivery or pickup; PickupLocation names the location of pickup orders.
+	FulfillmentType FulfillmentType `json:"fulfillment_type"`
+	PickupLocation  string          `json:"pickup_location,omitempty"`
+}
+
+// ProductionReportProduct is how many units of a product to bake, broken down by order.
+type ProductionReportProduct struct {
+	IDProduct     uint64                  `json:"id_product"`
+	Name          string                  `json:"name"`
+	TotalQuantity uint64                  `json:"total_quantity"`
+	Orders        []ProductionReportOrder `json:"orders"`
+}
+
+// ProductionReport is the response of GET /auth/reports/production.
+type ProductionReport struct {
+	Date        string                    `json:"date"`
+	TotalOrders int                       `json:"total_orders"`
+	TotalUnits  uint64                    `json:"total_units"`
+	Products    []ProductionReportProduct `json:"products"`
+}
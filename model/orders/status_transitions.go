@@ -0,0 +1,16 @@
+package model
+
+// StatusTransitions maps a status to the statuses an order in it can move to.
+type StatusTransitions map[OrderStatus][]OrderStatus
+
+// StatusTransitionsRequest is the body of PUT /auth/order-status-transitions.
+type StatusTransitionsRequest struct {
+	Transitions StatusTransitions `json:"transitions"`
+}
+
+// StatusTransitionsResponse is the tenant's order lifecycle (GET /auth/order-status-transitions).
+type StatusTransitionsResponse struct {
+	Transitions StatusTransitions `json:"transitions"`
+	// Customized is false while the tenant uses the default lifecycle.
+	Customized bool `json:"customized"`
+}
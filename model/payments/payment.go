@@ -0,0 +1,52 @@
+package model
+
+import (
+	"time"
+
+	"github.com/radamesvaz/bakery-app/model/money"
+)
+
+type PaymentStatus string
+
+const (
+	StatusPending   PaymentStatus = "pending"
+	StatusSucceeded PaymentStatus = "succeeded"
+	StatusFailed    PaymentStatus = "failed"
+	StatusCancelled PaymentStatus = "cancelled"
+)
+
+// Payment is one checkout attempt for an order at a payment provider.
+type Payment struct {
+	ID                uint64        `json:"id"`
+	TenantID          uint64        `json:"tenant_id"`
+	IDOrder           uint64        `json:"id_order"`
+	Provider          string        `json:"provider"`
+	ProviderPaymentID string        `json:"provider_payment_id"`
+	Status            PaymentStatus `json:"status"`
+	Amount            money.Amount  `json:"amount"`
+	CheckoutURL       string        `json:"checkout_url"`
+	ExpiresAt         time.Time     `json:"expires_at"`
+	PaidOn            *time.Time    `json:"paid_on,omitempty"`
+	CreatedOn         time.Time     `json:"created_on"`
+}
+
+// CreatePaymentRequest is the row inserted once the provider accepted a payment intent.
+type CreatePaymentRequest struct {
+	TenantID          uint64
+	IDOrder           uint64
+	Provider          string
+	ProviderPaymentID string
+	Amount            money.Amount
+	CheckoutURL       string
+	ExpiresAt         time.Time
+}
+
+// CheckoutResponse is returned by POST /t/{tenant_slug}/orders/{id}/checkout.
+type CheckoutResponse struct {
+	PaymentID   uint64       `json:"payment_id"`
+	IDOrder     uint64       `json:"id_order"`
+	Provider    string       `json:"provider"`
+	Amount      money.Amount `json:"amount"`
+	CheckoutURL string       `json:"checkout_url"`
+	ExpiresAt   time.Time    `json:"expires_at"`
+}
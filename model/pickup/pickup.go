@@ -0,0 +1,53 @@
+package model
+
+import (
+	"time"
+
+	soModel "github.com/radamesvaz/bakery-app/model/standingorders"
+)
+
+// OpeningHours are the hours a pickup location is open on one weekday (ISO numbering:
+// 1 = Monday ... 7 = Sunday). Opens and Closes are "HH:MM" in the tenant's local time.
+type OpeningHours struct {
+	Weekday int    `json:"weekday"`
+	Opens   string `json:"opens"`
+	Closes  string `json:"closes"`
+}
+
+// Location is a place where customers collect pickup orders. It takes pickups on the weekdays
+// listed in OpeningHours.
+type Location struct {
+	ID           uint64         `json:"id_pickup_location"`
+	TenantID     uint64         `json:"tenant_id"`
+	Name         string         `json:"name"`
+	Address      string         `json:"address"`
+	OpeningHours []OpeningHours `json:"opening_hours"`
+	Active       bool           `json:"active"`
+	CreatedOn    time.Time      `json:"created_on"`
+	UpdatedOn    time.Time      `json:"updated_on"`
+}
+
+// OpenOn reports whether the location is open on date (a UTC calendar day).
+func (l Location) OpenOn(date time.Time) bool {
+	wd := soModel.ISOWeekday(date)
+	for _, h := range l.OpeningHours {
+		if h.Weekday == wd {
+			return true
+		}
+	}
+	return false
+}
+
+// LocationRequest is the body of POST /auth/pickup-locations and PUT /auth/pickup-locations/{id}.
+// Active defaults to true.
+type LocationRequest struct {
+	Name         string         `json:"name"`
+	Address      string         `json:"address"`
+	OpeningHours []OpeningHours `json:"opening_hours"`
+	Active       *bool          `json:"active"`
+}
+
+// LocationsResponse is returned by the pickup location list endpoints.
+type LocationsResponse struct {
+	Items []Location `json:"items"`
+}
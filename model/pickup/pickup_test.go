@@ -0,0 +1,20 @@
+package model
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLocation_OpenOn(t *testing.T) {
+	location := Location{OpeningHours: []OpeningHours{
+		{Weekday: 6, Opens: "09:00", Closes: "13:00"},
+		{Weekday: 7, Opens: "10:00", Closes: "12:00"},
+	}}
+
+	assert.True(t, location.OpenOn(time.Date(2026, 3, 7, 0, 0, 0, 0, time.UTC)))  // Saturday
+	assert.True(t, location.OpenOn(time.Date(2026, 3, 8, 0, 0, 0, 0, time.UTC)))  // Sunday
+	assert.False(t, location.OpenOn(time.Date(2026, 3, 9, 0, 0, 0, 0, time.UTC))) // Monday
+	assert.False(t, Location{}.OpenOn(time.Date(2026, 3, 7, 0, 0, 0, 0, time.UTC)))
+}
@@ -0,0 +1,95 @@
+package model
+
+import (
+	"math"
+	"time"
+
+	"github.com/radamesvaz/bakery-app/model/money"
+)
+
+// Settings are the tenant's tax and default delivery fee. TaxRate is a percentage; TaxInclusive
+// means catalog prices already include the tax. A nil FreeDeliveryAbove never waives the fee.
+type Settings struct {
+	TaxRate           float64       `json:"tax_rate"`
+	TaxInclusive      bool          `json:"tax_inclusive"`
+	DeliveryFee       money.Amount  `json:"delivery_fee"`
+	FreeDeliveryAbove *money.Amount `json:"free_delivery_above"`
+}
+
+// DeliveryZone is an area with its own delivery fee. A nil FreeDeliveryAbove falls back to the
+// tenant's threshold.
+type DeliveryZone struct {
+	ID                uint64        `json:"id_delivery_zone"`
+	TenantID          uint64        `json:"tenant_id"`
+	Name              string        `json:"name"`
+	Fee               money.Amount  `json:"fee"`
+	FreeDeliveryAbove *money.Amount `json:"free_delivery_above"`
+	Active            bool          `json:"active"`
+	CreatedOn         time.Time     `json:"created_on"`
+	UpdatedOn         time.Time     `json:"updated_on"`
+}
+
+// DeliveryZoneRequest is the body of POST /auth/delivery-zones and PUT /auth/delivery-zones/{id}.
+// Active defaults to true.
+type DeliveryZoneRequest struct {
+	Name              string        `json:"name"`
+	Fee               money.Amount  `json:"fee"`
+	FreeDeliveryAbove *money.Amount `json:"free_delivery_above"`
+	Active            *bool         `json:"active"`
+}
+
+// DeliveryZonesResponse is returned by the delivery zone list endpoints.
+type DeliveryZonesResponse struct {
+	Items []DeliveryZone `json:"items"`
+}
+
+// Totals is the price breakdown of an order. Total is what the customer pays:
+// Subtotal - DiscountAmount + DeliveryFee, plus TaxAmount unless TaxInclusive.
+type Totals struct {
+	Subtotal       money.Amount
+	DiscountAmount money.Amount
+	TaxRate        float64
+	TaxInclusive   bool
+	TaxAmount      money.Amount
+	DeliveryFee    money.Amount
+	Total          money.Amount
+}
+
+// Calculate prices an order whose lines add up to subtotal and that got discount off them.
+// The tax applies to the discounted goods, not to the delivery fee. The delivery fee is the
+// zone's when zone is set, otherwise the tenant's, and is waived when the discounted goods reach
+// the free delivery threshold. The tax is rounded to the minor unit.
+func Calculate(subtotal, discount money.Amount, settings Settings, zone *DeliveryZone) Totals {
+	goods := subtotal - discount
+
+	fee := settings.DeliveryFee
+	freeAbove := settings.FreeDeliveryAbove
+	if zone != nil {
+		fee = zone.Fee
+		if zone.FreeDeliveryAbove != nil {
+			freeAbove = zone.FreeDeliveryAbove
+		}
+	}
+	if freeAbove != nil && goods >= *freeAbove {
+		fee = 0
+	}
+
+	var tax, total money.Amount
+	if settings.TaxInclusive {
+		tax = goods - money.Amount(math.Round(float64(goods)*100/(100+settings.TaxRate)))
+		total = goods + fee
+	} else {
+		tax = goods.Percent(settings.TaxRate)
+		total = goods + tax + fee
+	}
+
+	return Totals{
+		Subtotal:       subtotal,
+		DiscountAmount: discount,
+		TaxRate:        settings.TaxRate,
+		TaxInclusive:   settings.TaxInclusive,
+		TaxAmount:      tax,
+		DeliveryFee:    fee,
+		Total:          total,
+	}
+}
@@ -0,0 +1,63 @@
+package model
+
+import (
+	"testing"
+
+	"github.com/radamesvaz/bakery-app/model/money"
+	"github.com/stretchr/testify/assert"
+)
+
+func amountPtr(v money.Amount) *money.Amount { return &v }
+
+func TestCalculate_ExclusiveTaxAddsOnTopOfDiscountedGoods(t *testing.T) {
+	totals := Calculate(4000, 1000, Settings{TaxRate: 16, DeliveryFee: 300}, nil)
+
+	assert.Equal(t, Totals{
+		Subtotal:       4000,
+		DiscountAmount: 1000,
+		TaxRate:        16,
+		TaxAmount:      480,
+		DeliveryFee:    300,
+		Total:          3780,
+	}, totals)
+}
+
+func TestCalculate_InclusiveTaxIsExtractedFromGoods(t *testing.T) {
+	totals := Calculate(1160, 0, Settings{TaxRate: 16, TaxInclusive: true, DeliveryFee: 200}, nil)
+
+	assert.Equal(t, money.Amount(160), totals.TaxAmount)
+	assert.Equal(t, money.Amount(1360), totals.Total)
+	assert.True(t, totals.TaxInclusive)
+}
+
+func TestCalculate_TaxRoundsToTheCent(t *testing.T) {
+	// 16% of 0.99 is 0.1584; inclusive, 0.99 holds 0.1366 of tax.
+	assert.Equal(t, money.Amount(16), Calculate(99, 0, Settings{TaxRate: 16}, nil).TaxAmount)
+	assert.Equal(t, money.Amount(14), Calculate(99, 0, Settings{TaxRate: 16, TaxInclusive: true}, nil).TaxAmount)
+}
+
+func TestCalculate_ZoneFeeOverridesTenantFee(t *testing.T) {
+	settings := Settings{DeliveryFee: 300, FreeDeliveryAbove: amountPtr(10000)}
+	zone := &DeliveryZone{Fee: 500}
+
+	totals := Calculate(2000, 0, settings, zone)
+
+	assert.Equal(t, money.Amount(500), totals.DeliveryFee)
+	assert.Equal(t, money.Amount(2500), totals.Total)
+}
+
+func TestCalculate_FreeDeliveryThreshold(t *testing.T) {
+	settings := Settings{DeliveryFee: 300, FreeDeliveryAbove: amountPtr(5000)}
+
+	// The threshold is checked against the discounted goods.
+	assert.Equal(t, money.Amount(300), Calculate(5500, 1000, settings, nil).DeliveryFee)
+	assert.Equal(t, money.Amount(0), Calculate(5000, 0, settings, nil).DeliveryFee)
+
+	// A zone threshold wins over the tenant's.
+	zone := &DeliveryZone{Fee: 600, FreeDeliveryAbove: amountPtr(8000)}
+	assert.Equal(t, money.Amount(600), Calculate(6000, 0, settings, zone).DeliveryFee)
+	assert.Equal(t, money.Amount(0), Calculate(8000, 0, settings, zone).DeliveryFee)
+
+	// Without its own threshold the zone uses the tenant's.
+	assert.Equal(t, money.Amount(0), Calculate(6000, 0, settings, &DeliveryZone{Fee: 600}).DeliveryFee)
+}
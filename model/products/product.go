@@ -2,6 +2,8 @@ package model
 
 import (
 	"database/sql"
+
+	"github.com/radamesvaz/bakery-app/model/money"
 )
 
 type ProductStatus string
@@ -17,7 +19,7 @@ type Product struct {
 	TenantID       uint64        `json:"tenant_id"`
 	Name           string        `json:"name" gorm:"not null;unique"`
 	Description    string        `json:"description"`
-	Price          float64       `json:"price" gorm:"not null;check:price >= 0"`
+	Price          money.Amount  `json:"price" gorm:"not null;check:price >= 0"`
 	TrackInventory bool          `json:"track_inventory"`
 	Stock          uint64        `json:"stock"`
 	Status         ProductStatus `json:"status"`
@@ -29,7 +31,7 @@ type Product struct {
 type CreateProductRequest struct {
 	Name           string        `form:"name" json:"name" gorm:"not null;unique"`
 	Description    string        `form:"description" json:"description"`
-	Price          float64       `form:"price" json:"price" gorm:"not null;check:price >= 0"`
+	Price          money.Amount  `form:"price" json:"price" gorm:"not null;check:price >= 0"`
 	TrackInventory *bool         `form:"track_inventory" json:"track_inventory"`
 	Stock          uint64        `form:"stock" json:"stock"`
 	Status         ProductStatus `form:"status" json:"status"`
@@ -38,7 +40,7 @@ type CreateProductRequest struct {
 type UpdateProductRequest struct {
 	Name           string        `form:"name" json:"name"`
 	Description    string        `form:"description" json:"description"`
-	Price          float64       `form:"price" json:"price"`
+	Price          money.Amount  `form:"price" json:"price"`
 	TrackInventory *bool         `form:"track_inventory" json:"track_inventory"`
 	Stock          uint64        `form:"stock" json:"stock"`
 	Status         ProductStatus `form:"status" json:"status"`
@@ -2,6 +2,9 @@ package model
 
 import (
 	"database/sql"
+	"time"
+
+	"github.com/radamesvaz/bakery-app/model/money"
 )
 
 type ProductAction string
@@ -18,7 +21,7 @@ type ProductHistory struct {
 	IDProduct      uint64        `json:"id_product" gorm:"primaryKey"`
 	Name           string        `json:"name" gorm:"not null;unique"`
 	Description    string        `json:"description"`
-	Price          float64       `json:"price" gorm:"not null;check:price >= 0"`
+	Price          money.Amount  `json:"price" gorm:"not null;check:price >= 0"`
 	TrackInventory bool          `json:"track_inventory"`
 	Stock          uint64        `json:"stock"`
 	Status         ProductStatus `json:"status"`
@@ -28,3 +31,29 @@ type ProductHistory struct {
 	ModifiedBy     uint64        `json:"modified_by"`
 	Action         ProductAction `json:"action"`
 }
+
+// ProductHistoryChange is one field that differs from the previous snapshot of the product.
+type ProductHistoryChange struct {
+	Field string      `json:"field"`
+	From  interface{} `json:"from"`
+	To    interface{} `json:"to"`
+}
+
+// ProductHistoryEntry is a products_history snapshot as returned by GET /auth/products/{id}/history.
+// Changes is empty for the oldest snapshot of the product.
+type ProductHistoryEntry struct {
+	ID             uint64                 `json:"id_product_history"`
+	IDProduct      uint64                 `json:"id_product"`
+	Name           string                 `json:"name"`
+	Description    string                 `json:"description"`
+	Price          money.Amount           `json:"price"`
+	TrackInventory bool                   `json:"track_inventory"`
+	Stock          uint64                 `json:"stock"`
+	Status         ProductStatus          `json:"status"`
+	ImageURLs      []string               `json:"image_urls"`
+	ThumbnailURL   string                 `json:"thumbnail_url"`
+	ModifiedOn     *time.Time             `json:"modified_on"`
+	ModifiedBy     uint64                 `json:"modified_by"`
+	Action         ProductAction          `json:"action"`
+	Changes        []ProductHistoryChange `json:"changes"`
+}
@@ -0,0 +1,138 @@
+package model
+
+import (
+	"time"
+
+	"github.com/radamesvaz/bakery-app/model/money"
+)
+
+type DiscountType string
+
+const (
+	// DiscountPercentage takes Value percent off the eligible amount.
+	DiscountPercentage DiscountType = "percentage"
+	// DiscountFixed takes Value off the eligible amount, never more than the amount itself.
+	DiscountFixed DiscountType = "fixed"
+)
+
+type Scope string
+
+const (
+	// ScopeOrder discounts the whole order.
+	ScopeOrder Scope = "order"
+	// ScopeProducts discounts only the lines of ProductIDs.
+	ScopeProducts Scope = "products"
+)
+
+// Promotion is a discount code of a tenant. Nil limits mean unlimited and nil StartsAt/EndsAt
+// leave that side of the validity window open.
+type Promotion struct {
+	ID                 uint64        `json:"id_promotion"`
+	TenantID           uint64        `json:"tenant_id"`
+	Code               string        `json:"code"`
+	Description        string        `json:"description"`
+	DiscountType       DiscountType  `json:"discount_type"`
+	Value              float64       `json:"value"`
+	Scope              Scope         `json:"scope"`
+	ProductIDs         []uint64      `json:"product_ids"`
+	MinOrderTotal      *money.Amount `json:"min_order_total"`
+	StartsAt           *time.Time    `json:"starts_at"`
+	EndsAt             *time.Time    `json:"ends_at"`
+	MaxUses            *int          `json:"max_uses"`
+	MaxUsesPerCustomer *int          `json:"max_uses_per_customer"`
+	Active             bool          `json:"active"`
+	// Uses counts the redemptions of orders that are not cancelled, expired or deleted.
+	Uses      int       `json:"uses"`
+	CreatedOn time.Time `json:"created_on"`
+	UpdatedOn time.Time `json:"updated_on"`
+}
+
+// PromotionRequest is the body of POST /auth/promotions and PUT /auth/promotions/{id}.
+// Scope defaults to order and Active to true.
+type PromotionRequest struct {
+	Code               string        `json:"code"`
+	Description        string        `json:"description"`
+	DiscountType       DiscountType  `json:"discount_type"`
+	Value              float64       `json:"value"`
+	Scope              Scope         `json:"scope"`
+	ProductIDs         []uint64      `json:"product_ids"`
+	MinOrderTotal      *money.Amount `json:"min_order_total"`
+	StartsAt           *time.Time    `json:"starts_at"`
+	EndsAt             *time.Time    `json:"ends_at"`
+	MaxUses            *int          `json:"max_uses"`
+	MaxUsesPerCustomer *int          `json:"max_uses_per_customer"`
+	Active             *bool         `json:"active"`
+}
+
+// Redemption records that an order used a promotion.
+type Redemption struct {
+	TenantID       uint64
+	IDPromotion    uint64
+	IDOrder        uint64
+	Email          string
+	DiscountAmount money.Amount
+}
+
+// Line is one order line as seen by a promotion: its product and Price * Quantity.
+type Line struct {
+	IdProduct uint64
+	Amount    money.Amount
+}
+
+// IsRedeemableAt reports whether the promotion is active and now is inside its validity window.
+func (p Promotion) IsRedeemableAt(now time.Time) bool {
+	if !p.Active {
+		return false
+	}
+	if p.StartsAt != nil && now.Before(*p.StartsAt) {
+		return false
+	}
+	if p.EndsAt != nil && !now.Before(*p.EndsAt) {
+		return false
+	}
+	return true
+}
+
+// Subtotal sums the lines.
+func Subtotal(lines []Line) money.Amount {
+	var total money.Amount
+	for _, l := range lines {
+		total += l.Amount
+	}
+	return total
+}
+
+// MeetsMinimum reports whether the order subtotal reaches MinOrderTotal.
+func (p Promotion) MeetsMinimum(lines []Line) bool {
+	return p.MinOrderTotal == nil || Subtotal(lines) >= *p.MinOrderTotal
+}
+
+// Discount returns the amount taken off the lines, rounded to the minor unit: the whole subtotal
+// for ScopeOrder or only the lines of ProductIDs for ScopeProducts. It ignores the validity
+// window, minimum and usage limits; 0 means no line is eligible.
+func (p Promotion) Discount(lines []Line) money.Amount {
+	var eligible money.Amount
+	for _, l := range lines {
+		if p.Scope == ScopeProducts && !p.appliesTo(l.IdProduct) {
+			continue
+		}
+		eligible += l.Amount
+	}
+	var discount money.Amount
+	switch p.DiscountType {
+	case DiscountPercentage:
+		discount = eligible.Percent(p.Value)
+	case DiscountFixed:
+		discount = money.FromMajor(p.Value)
+	}
+	return min(discount, eligible)
+}
+
+func (p Promotion) appliesTo(idProduct uint64) bool {
+	for _, id := range p.ProductIDs {
+		if id == idProduct {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,104 @@
+package model
+
+import "time"
+
+// DateLayout is the format of start, end and delivery dates in requests and responses.
+const DateLayout = "2006-01-02"
+
+// StandingOrder is a recurring order template. The worker turns each matching delivery date into
+// a real order. Weekdays use ISO numbering: 1 = Monday ... 7 = Sunday.
+type StandingOrder struct {
+	ID                uint64              `json:"id_standing_order"`
+	TenantID          uint64              `json:"tenant_id"`
+	Name              string              `json:"name"`
+	Email             string              `json:"email"`
+	Phone             string              `json:"phone"`
+	DeliveryDirection string              `json:"delivery_direction"`
+	Note              string              `json:"note"`
+	Weekdays          []int               `json:"weekdays"`
+	StartDate         string              `json:"start_date"`
+	EndDate           *string             `json:"end_date"`
+	Active            bool                `json:"active"`
+	Items             []StandingOrderItem `json:"items"`
+	CreatedOn         time.Time           `json:"created_on"`
+	UpdatedOn         time.Time           `json:"updated_on"`
+}
+
+// StandingOrderItem is one product line of a standing order.
+type StandingOrderItem struct {
+	IdProduct uint64 `json:"id_product"`
+	Quantity  uint64 `json:"quantity"`
+}
+
+// ISOWeekday returns the ISO weekday of date (1 = Monday ... 7 = Sunday).
+func ISOWeekday(date time.Time) int {
+	wd := int(date.Weekday())
+	if wd == 0 {
+		return 7
+	}
+	return wd
+}
+
+// OccursOn reports whether the standing order delivers on date (a UTC calendar day).
+func (s StandingOrder) OccursOn(date time.Time) bool {
+	day := date.Format(DateLayout)
+	if day < s.StartDate || (s.EndDate != nil && day > *s.EndDate) {
+		return false
+	}
+	wd := ISOWeekday(date)
+	for _, d := range s.Weekdays {
+		if d == wd {
+			return true
+		}
+	}
+	return false
+}
+
+// StandingOrderRequest is the body of POST /auth/standing-orders and PUT /auth/standing-orders/{id}.
+// Active defaults to true.
+type StandingOrderRequest struct {
+	Name              string              `json:"name"`
+	Email             string              `json:"email"`
+	Phone             string              `json:"phone"`
+	DeliveryDirection string              `json:"delivery_direction"`
+	Note              string              `json:"note"`
+	Weekdays          []int               `json:"weekdays"`
+	StartDate         string              `json:"start_date"`
+	EndDate           *string             `json:"end_date"`
+	Active            *bool               `json:"active"`
+	Items             []StandingOrderItem `json:"items"`
+}
+
+// StandingOrderInput is a validated StandingOrderRequest, ready to store.
+type StandingOrderInput struct {
+	Name              string
+	Email             string
+	Phone             string
+	DeliveryDirection string
+	Note              string
+	Weekdays          []int
+	StartDate         time.Time
+	EndDate           *time.Time
+	Active            bool
+	Items             []StandingOrderItem
+}
+
+// OccurrenceStatus is the outcome of one delivery date of a standing order.
+type OccurrenceStatus string
+
+const (
+	OccurrenceCreated OccurrenceStatus = "created"
+	OccurrenceSkipped OccurrenceStatus = "skipped"
+	OccurrenceFailed  OccurrenceStatus = "failed"
+)
+
+// Occurrence records what the worker did for one delivery date of a standing order.
+type Occurrence struct {
+	IDStandingOrder uint64           `json:"id_standing_order"`
+	DeliveryDate    string           `json:"delivery_date"`
+	Status          OccurrenceStatus `json:"status"`
+	IDOrder         *uint64          `json:"id_order"`
+	Attempts        int              `json:"attempts"`
+	LastError       *string          `json:"last_error"`
+	UpdatedOn       time.Time        `json:"updated_on"`
+}
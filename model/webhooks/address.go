@@ -0,0 +1,26 @@
+package model
+
+import (
+	"net"
+	"strings"
+)
+
+// IsDeliverableIP reports whether webhooks may be sent to ip: public unicast addresses only, never
+// loopback, private (RFC 1918 / unique local), link-local (169.254.0.0/16, e.g. cloud metadata),
+// multicast or unspecified ones.
+func IsDeliverableIP(ip net.IP) bool {
+	return ip != nil &&
+		!ip.IsLoopback() &&
+		!ip.IsPrivate() &&
+		!ip.IsLinkLocalUnicast() &&
+		!ip.IsLinkLocalMulticast() &&
+		!ip.IsInterfaceLocalMulticast() &&
+		!ip.IsMulticast() &&
+		!ip.IsUnspecified()
+}
+
+// IsLocalHostname reports whether host names this machine ("localhost" and its subdomains).
+func IsLocalHostname(host string) bool {
+	host = strings.TrimSuffix(strings.ToLower(host), ".")
+	return host == "localhost" || strings.HasSuffix(host, ".localhost")
+}
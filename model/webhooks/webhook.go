@@ -0,0 +1,118 @@
+package model
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/radamesvaz/bakery-app/model/money"
+)
+
+type EventType string
+
+const (
+	EventOrderCreated       EventType = "order.created"
+	EventOrderStatusChanged EventType = "order.status_changed"
+	EventOrderExpired       EventType = "order.expired"
+	EventProductOutOfStock  EventType = "product.out_of_stock"
+)
+
+// EventTypes lists every event a subscription can listen to.
+var EventTypes = []EventType{
+	EventOrderCreated,
+	EventOrderStatusChanged,
+	EventOrderExpired,
+	EventProductOutOfStock,
+}
+
+// IsValidEventType reports whether t is one of EventTypes.
+func IsValidEventType(t EventType) bool {
+	for _, known := range EventTypes {
+		if t == known {
+			return true
+		}
+	}
+	return false
+}
+
+type DeliveryStatus string
+
+const (
+	DeliveryStatusPending   DeliveryStatus = "pending"
+	DeliveryStatusSucceeded DeliveryStatus = "succeeded"
+	DeliveryStatusFailed    DeliveryStatus = "failed"
+)
+
+// Subscription is a tenant endpoint that receives signed event payloads.
+// Secret is only returned when the subscription is created.
+type Subscription struct {
+	ID         uint64      `json:"id"`
+	TenantID   uint64      `json:"tenant_id"`
+	URL        string      `json:"url"`
+	Secret     string      `json:"secret,omitempty"`
+	EventTypes []EventType `json:"event_types"`
+	Active     bool        `json:"active"`
+	CreatedOn  time.Time   `json:"created_on"`
+	UpdatedOn  time.Time   `json:"updated_on"`
+}
+
+type CreateSubscriptionRequest struct {
+	URL        string      `json:"url"`
+	EventTypes []EventType `json:"event_types"`
+	Active     *bool       `json:"active,omitempty"`
+}
+
+type UpdateSubscriptionRequest struct {
+	URL        *string     `json:"url,omitempty"`
+	EventTypes []EventType `json:"event_types,omitempty"`
+	Active     *bool       `json:"active,omitempty"`
+}
+
+// Delivery is one attempt log line of an event sent to a subscription.
+type Delivery struct {
+	ID             uint64          `json:"id"`
+	SubscriptionID uint64          `json:"webhook_subscription_id"`
+	EventID        uint64          `json:"webhook_event_id"`
+	EventType      EventType       `json:"event_type"`
+	Payload        json.RawMessage `json:"payload"`
+	Status         DeliveryStatus  `json:"status"`
+	Attempts       int             `json:"attempts"`
+	NextAttemptAt  *time.Time      `json:"next_attempt_at,omitempty"`
+	LastStatusCode *int            `json:"last_status_code,omitempty"`
+	LastError      *string         `json:"last_error,omitempty"`
+	DeliveredOn    *time.Time      `json:"delivered_on,omitempty"`
+	CreatedOn      time.Time       `json:"created_on"`
+}
+
+// PendingDelivery is a delivery claimed by the dispatcher with everything needed to send it.
+type PendingDelivery struct {
+	ID             uint64
+	TenantID       uint64
+	SubscriptionID uint64
+	URL            string
+	Secret         string
+	EventID        uint64
+	EventType      EventType
+	Payload        json.RawMessage
+	EventCreatedOn time.Time
+	Attempts       int
+}
+
+// Envelope is the JSON body POSTed to subscribers.
+type Envelope struct {
+	ID        uint64          `json:"id"`
+	Type      EventType       `json:"type"`
+	TenantID  uint64          `json:"tenant_id"`
+	CreatedOn time.Time       `json:"created_on"`
+	Data      json.RawMessage `json:"data"`
+}
+
+// OrderEventData is the data of order.* events.
+type OrderEventData struct {
+	IDOrder            uint64       `json:"id_order"`
+	Status             string       `json:"status"`
+	PreviousStatus     string       `json:"previous_status,omitempty"`
+	Price              money.Amount `json:"price"`
+	Paid               bool         `json:"paid"`
+	DeliveryDate       string       `json:"delivery_date,omitempty"`
+	CancellationReason *string      `json:"cancellation_reason,omitempty"`
+}
@@ -75,6 +75,9 @@ func TestGetAllOrders(t *testing.T) {
       "status": "delivered",
       "tenant_id": 1,
       "total_price": 57,
+      "currency": "USD",
+      "fulfillment_type": "delivery",
+      "sales_channel": "web",
       "note": "make it bright",
       "delivery_direction": "https://maps.app.goo.gl/JewH99BXywGvtHQW6",
       "OrderItems": [
@@ -98,7 +101,16 @@ func TestGetAllOrders(t *testing.T) {
       "created_on": "2025-04-01T10:00:00Z",
       "delivery_date": "2025-04-05T00:00:00Z",
       "expires_at": "0001-01-01T00:00:00Z",
-      "paid": false
+      "paid": false,
+      "discount_amount": 0,
+      "subtotal": 57,
+      "tax_rate": 0,
+      "tax_inclusive": false,
+      "tax_amount": 0,
+      "delivery_fee": 0,
+      "amount_paid": 0,
+      "balance_due": 57,
+      "refund_due": 0
     },
     {
       "id_order": 2,
@@ -108,6 +120,9 @@ func TestGetAllOrders(t *testing.T) {
       "status": "pending",
       "tenant_id": 1,
       "total_price": 10,
+      "currency": "USD",
+      "fulfillment_type": "delivery",
+      "sales_channel": "web",
       "note": "deliver at the door",
       "delivery_direction": "https://maps.app.goo.gl/JewH99BXywGvtHQW6",
       "OrderItems": [
@@ -123,7 +138,16 @@ func TestGetAllOrders(t *testing.T) {
       "created_on": "2025-04-14T10:00:00Z",
       "delivery_date": "2025-04-20T00:00:00Z",
       "expires_at": "2025-04-14T10:30:00Z",
-      "paid": false
+      "paid": false,
+      "discount_amount": 0,
+      "subtotal": 10,
+      "tax_rate": 0,
+      "tax_inclusive": false,
+      "tax_amount": 0,
+      "delivery_fee": 0,
+      "amount_paid": 0,
+      "balance_due": 10,
+      "refund_due": 0
     },
     {
       "id_order": 3,
@@ -133,6 +157,9 @@ func TestGetAllOrders(t *testing.T) {
       "status": "preparing",
       "tenant_id": 1,
       "total_price": 12,
+      "currency": "USD",
+      "fulfillment_type": "delivery",
+      "sales_channel": "web",
       "note": "not so sweet",
       "delivery_direction": "https://maps.app.goo.gl/JewH99BXywGvtHQW6",
       "OrderItems": [
@@ -156,7 +183,16 @@ func TestGetAllOrders(t *testing.T) {
       "created_on": "2025-04-20T10:00:00Z",
       "delivery_date": "2025-04-25T00:00:00Z",
       "expires_at": "0001-01-01T00:00:00Z",
-      "paid": false
+      "paid": false,
+      "discount_amount": 0,
+      "subtotal": 12,
+      "tax_rate": 0,
+      "tax_inclusive": false,
+      "tax_amount": 0,
+      "delivery_fee": 0,
+      "amount_paid": 0,
+      "balance_due": 12,
+      "refund_due": 0
     }
   ],
   "next_cursor": null
@@ -269,6 +305,9 @@ func TestGetOrderByID(t *testing.T) {
     "status": "delivered",
     "tenant_id": 1,
     "total_price": 57,
+    "currency": "USD",
+    "fulfillment_type": "delivery",
+    "sales_channel": "web",
     "note": "make it bright",
     "delivery_direction": "https://maps.app.goo.gl/JewH99BXywGvtHQW6",
     "OrderItems": [
@@ -292,7 +331,16 @@ func TestGetOrderByID(t *testing.T) {
     "created_on": "2025-04-01T10:00:00Z",
     "delivery_date": "2025-04-05T00:00:00Z",
     "expires_at": "0001-01-01T00:00:00Z",
-    "paid": false
+    "paid": false,
+    "discount_amount": 0,
+    "subtotal": 57,
+    "tax_rate": 0,
+    "tax_inclusive": false,
+    "tax_amount": 0,
+    "delivery_fee": 0,
+    "amount_paid": 0,
+    "balance_due": 57,
+    "refund_due": 0
 }`
 
 	assert.Equal(t, http.StatusOK, rr.Code)
@@ -346,12 +394,11 @@ func TestCreateOrder(t *testing.T) {
 	rr := httptest.NewRecorder()
 	router.ServeHTTP(rr, req)
 
-	expected := `{
-			"message": "Order created successfully"
-		}`
-
 	assert.Equal(t, http.StatusOK, rr.Code)
-	assert.JSONEq(t, expected, rr.Body.String())
+	var created map[string]any
+	require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &created))
+	assert.Equal(t, "Order created successfully", created["message"])
+	assert.NotZero(t, created["id_order"])
 }
 
 func TestLegacyCreateOrder_WithoutTenantMiddlewareReturns400(t *testing.T) {
@@ -423,7 +470,10 @@ func TestLegacyCreateOrder_WithXTenantSlugHeader(t *testing.T) {
 	router.ServeHTTP(rr, req)
 
 	assert.Equal(t, http.StatusOK, rr.Code)
-	assert.JSONEq(t, `{"message": "Order created successfully"}`, rr.Body.String())
+	var created map[string]any
+	require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &created))
+	assert.Equal(t, "Order created successfully", created["message"])
+	assert.NotZero(t, created["id_order"])
 }
 
 func TestCreateOrder_MissingDeliveryDirection(t *testing.T) {
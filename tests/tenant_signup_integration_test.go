@@ -32,6 +32,7 @@ import (
 )
 
 type recordingTenantSignupEmailSender struct {
+	email.NoopSender
 	mu    sync.Mutex
 	last  email.TenantSignupCodePayload
 	calls int